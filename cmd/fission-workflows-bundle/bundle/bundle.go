@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"time"
 
@@ -14,24 +15,49 @@ import (
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/audit"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/apiserver/debug"
+	"github.com/fission/fission-workflows/pkg/apiserver/openapi"
+	"github.com/fission/fission-workflows/pkg/apiserver/ratelimit"
+	"github.com/fission/fission-workflows/pkg/blobstore"
+	blobfile "github.com/fission/fission-workflows/pkg/blobstore/file"
+	blobmem "github.com/fission/fission-workflows/pkg/blobstore/mem"
+	blobs3 "github.com/fission/fission-workflows/pkg/blobstore/s3"
 	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
 	"github.com/fission/fission-workflows/pkg/controller/executor"
 	"github.com/fission/fission-workflows/pkg/controller/expr"
 	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/buffer"
 	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
 	"github.com/fission/fission-workflows/pkg/fes/backend/nats"
 	"github.com/fission/fission-workflows/pkg/fes/cache"
 	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/container"
 	"github.com/fission/fission-workflows/pkg/fnenv/fission"
+	fnenvgrpc "github.com/fission/fission-workflows/pkg/fnenv/grpc"
+	fnenvhttp "github.com/fission/fission-workflows/pkg/fnenv/http"
+	"github.com/fission/fission-workflows/pkg/fnenv/job"
+	"github.com/fission/fission-workflows/pkg/fnenv/keyref"
 	"github.com/fission/fission-workflows/pkg/fnenv/native"
 	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/fnenv/workflows"
+	"github.com/fission/fission-workflows/pkg/quota"
 	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/schema"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/schema/avro"
 	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/fission/fission-workflows/pkg/util/labels"
+	"github.com/fission/fission-workflows/pkg/util/otlp"
 	"github.com/fission/fission-workflows/pkg/util/pubsub"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/fission/fission-workflows/pkg/version"
+	"github.com/fission/fission-workflows/pkg/worker"
+	"github.com/fission/fission/crd"
 	"github.com/gorilla/handlers"
 	"github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -46,6 +72,9 @@ import (
 	jaegerlog "github.com/uber/jaeger-client-go/log"
 	jaegerprom "github.com/uber/jaeger-lib/metrics/prometheus"
 	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 const (
@@ -56,28 +85,45 @@ const (
 	InvocationsCacheSize         = 100000
 	executorMaxParallelism       = 1000
 	executorMaxTaskQueueSize     = 100000
+	DefaultMaxGroupParallelism   = executorMaxParallelism / 10
+	DefaultDrainTimeout          = 20 * time.Second
+	DefaultInvokeTimeout         = 5 * time.Minute
 	workflowStorePollInterval    = time.Minute
 	invocationStorePollInterval  = time.Second
 	workflowSubscriptionBuffer   = 50
 	invocationSubscriptionBuffer = 1000
+
+	// TraceIDHeader is the header of the HTTP responses of the gateway that contains the id of the trace of the
+	// request. For requests that create an invocation, this is the trace of the invocation.
+	TraceIDHeader = "X-Trace-Id"
 )
 
 type App struct {
 	*Options
-	closers map[string]io.Closer
+	closers []namedCloser
+}
+
+type namedCloser struct {
+	name string
+	io.Closer
 }
 
 func (app *App) RegisterCloser(name string, closer io.Closer) {
-	if _, ok := app.closers[name]; ok {
-		panic(fmt.Sprintf("duplicate registry for key %s", name))
+	for _, c := range app.closers {
+		if c.name == name {
+			panic(fmt.Sprintf("duplicate registry for key %s", name))
+		}
 	}
 
-	app.closers[name] = closer
+	app.closers = append(app.closers, namedCloser{name: name, Closer: closer})
 }
 
+// Close closes the registered closers in the reverse order of their registration, so that components are closed
+// before the components that they were set up with, such as the caches before the event store.
 func (app *App) Close() error {
 	var errorOccured bool
-	for name, closer := range app.closers {
+	for i := len(app.closers) - 1; i >= 0; i-- {
+		name, closer := app.closers[i].name, app.closers[i].Closer
 		err := closer.Close()
 		if err != nil {
 			log.Errorf("Error while closing %s: %v", name, err)
@@ -94,9 +140,16 @@ func (app *App) Close() error {
 
 type Options struct {
 	NATS                 *nats.Config
+	EventBuffer          *buffer.Config
 	Scheduler            scheduler.Policy
 	Fission              *FissionOptions
+	HTTP                 *fnenvhttp.Config
+	Job                  *JobOptions
+	GRPC                 *fnenvgrpc.Config
+	Container            *container.Config
+	Secrets              *SecretsOptions
 	FissionProxy         *FissionProxyConfig
+	Operator             *OperatorConfig
 	InternalRuntime      bool
 	InvocationController bool
 	WorkflowController   bool
@@ -106,12 +159,163 @@ type Options struct {
 	InvocationAPI        bool
 	Metrics              bool
 	Debug                bool
+	ExpressionLimits     expr.Limits
+	ExpressionHelpers    map[string]expr.HelperFunc
+	ExpressionPlugins    []string
+	BlobStore            *BlobStoreOptions
+	AvroSchemaRegistry   string
+
+	// APIKeys enables the API key authentication of the gRPC and HTTP APIs. If neither APIKeys nor JWT is set, the
+	// APIs are open.
+	APIKeys []auth.Key
+
+	// JWT enables the authentication of the gRPC and HTTP APIs with JWTs, such as OpenID Connect ID tokens.
+	JWT *auth.JWTConfig
+
+	// RBACPolicy authorizes the authenticated callers to create, invoke, cancel or administer specific workflows or
+	// namespaces. It requires APIKeys or JWT to be set. If nil, the scopes of the callers are the only restriction.
+	RBACPolicy *auth.Policy
+
+	// Audit enables the audit log of the state-changing API calls. If nil, the calls are not audited.
+	Audit *AuditOptions
+
+	// Quotas limits the running invocations, the task rate and the payload size of each namespace. If nil, the
+	// namespaces are not limited.
+	Quotas *quota.Config
+
+	// RateLimit limits the rate of the API calls of each client. If nil, the clients are not limited.
+	RateLimit *ratelimit.Config
+
+	// RedactFields and RedactHeaders are the fields of values and the HTTP headers that are redacted from logs and
+	// traces, in addition to the defaults of the redact package.
+	RedactFields  []string
+	RedactHeaders []string
+
+	// MaxBodyMemorySize is the size (in bytes) above which HTTP bodies of any content type are streamed to the blob
+	// store. Without a blob store, larger bodies are rejected. If 0, the size of bodies is not limited.
+	MaxBodyMemorySize int64
+
+	// MaxPayloadSize is the maximum size (in bytes) of the inputs of invocations and the outputs of tasks, after
+	// offloading to the blob store. If 0, the size is not limited.
+	MaxPayloadSize int64
+
+	// MaxOutputSize is the maximum size (in bytes) of the outputs of tasks, before offloading to the blob store.
+	// Outputs that exceed it are handled according to the OutputSizePolicy. If 0, the size is not limited.
+	MaxOutputSize    int64
+	OutputSizePolicy fnenv.OutputPolicy
+
+	// HeartbeatTimeout is the maximum time between the heartbeats of the functions of which the runtime reports
+	// heartbeats, such as asynchronous Fission functions and Kubernetes Jobs. If 0, heartbeats are not checked.
+	HeartbeatTimeout time.Duration
+
+	// QuarantineThreshold is the number of consecutive failed evaluations of a controller, such as the controller of
+	// an invocation, after which it is quarantined. If 0, controllers are never quarantined.
+	QuarantineThreshold int
+
+	// OTLP exports the traces to an OpenTelemetry collector with OTLP/HTTP instead of the Jaeger agent. The spans are
+	// the same for both. If nil, the Jaeger config is read from the JAEGER_* env vars.
+	OTLP *otlp.Config
+
+	// DebugAddress is the address of a dedicated HTTP server for the runtime debug endpoints (pprof, goroutine dump and
+	// expvar), such as localhost:6060. If empty, the dedicated server is not started.
+	DebugAddress string
+
+	// DebugAPI serves the debug endpoints on the HTTP API gateway as well, where they require the admin scope if the
+	// APIs are authenticated.
+	DebugAPI bool
+
+	// ExecutorAlarms are the percentages of the capacity of the task queues of the controllers at which a warning is
+	// logged. If nil, executor.DefaultSaturationAlarms are used.
+	ExecutorAlarms []int
+
+	// MaxGroupParallelism is the maximum number of tasks of a single invocation that the invocation controller executes
+	// at the same time, so that invocations with a large fan-out cannot starve the other invocations. If 0, the
+	// invocations are not limited.
+	MaxGroupParallelism int
+
+	// Worker serves the worker API on the gRPC server, with which remote invocation controllers execute tasks with the
+	// function runtimes of this process. If nil, the worker API is not served.
+	Worker *worker.Config
+
+	// DrainTimeout is the maximum duration that the controllers wait for their executing tasks to finish on shutdown.
+	// The tasks that were abandoned are executed again after a restart.
+	DrainTimeout time.Duration
+
+	// InvokeTimeout is the deadline of the invocations of the invoke endpoints of the workflows on the HTTP gateway. If
+	// 0, DefaultInvokeTimeout is used.
+	InvokeTimeout time.Duration
+
+	// RemoteWorkers delegates the invocations of the functions of the invocation controller to remote workers. If nil,
+	// the functions are invoked by this process.
+	RemoteWorkers *RemoteWorkerOptions
+}
+
+// RemoteWorkerOptions configures the pool of workers to which the invocation controller delegates the invocations of
+// functions. The functions of the internal and workflows runtimes are always invoked by the controller itself, as they
+// depend on the state of the workflow engine.
+type RemoteWorkerOptions struct {
+	// Addresses are the gRPC addresses of the workers, such as worker-0.workflows-worker:5555.
+	Addresses []string
+
+	// APIKey authenticates the controller with the workers, if the APIs of the workers require API keys. The worker
+	// API requires the admin scope.
+	APIKey string
+}
+
+// BlobStoreOptions configures the blob store, to which values that exceed the threshold are offloaded.
+type BlobStoreOptions struct {
+	URL       string // e.g. mem://, file:///var/lib/workflows or s3://bucket?endpoint=minio:9000&insecure=true
+	AccessKey string
+	SecretKey string
+	Threshold int
+
+	// StreamThreshold is the size (in bytes) above which binary HTTP bodies are streamed to the blob store.
+	StreamThreshold int64
+}
+
+// JobOptions configures the function environment that runs tasks as Kubernetes Jobs.
+type JobOptions struct {
+	// Kubeconfig is the path to the kubeconfig. If empty, the in-cluster configuration is used.
+	Kubeconfig string
+	Config     job.Config
+}
+
+// SecretsOptions configures the resolution of references to Kubernetes secrets and configmaps in task inputs.
+type SecretsOptions struct {
+	// Kubeconfig is the path to the kubeconfig. If empty, the in-cluster configuration is used.
+	Kubeconfig string
+
+	// Namespace is the namespace of the secrets and configmaps of functions that are not scoped to a namespace.
+	Namespace string
+
+	// Vault reads the secrets from Vault instead of from the Kubernetes secrets. Configmaps are still read from
+	// Kubernetes.
+	Vault *keyref.VaultConfig
+}
+
+// AuditOptions configures the sinks of the audit log. The most recent audit events are always kept in memory, so that
+// they can be queried with the admin API.
+type AuditOptions struct {
+	// File is the path of the file to which the audit events are appended as JSON lines.
+	File string
+
+	// WebhookURL is the URL to which each audit event is posted as JSON.
+	WebhookURL string
+
+	// EventStore persists the audit events in the event store, which is then used to query the audit log.
+	EventStore bool
 }
 
 type FissionOptions struct {
 	ExecutorAddress string
 	ControllerAddr  string
 	RouterAddr      string
+	Config          fission.Config
+
+	// WatchFunctions watches the Fission functions with the Kubernetes API to invalidate the cached resolutions of
+	// changed functions, and to resolve the workflows of recreated functions again.
+	WatchFunctions bool
+	Kubeconfig     string
 }
 
 // Run serves enabled components in a blocking way
@@ -122,10 +326,48 @@ func Run(ctx context.Context, opts *Options) error {
 	}).Info("Starting bundle...")
 	app := &App{
 		Options: opts,
-		closers: map[string]io.Closer{},
 	}
 	ps := Processes{}
 
+	// The Starlark dialect is enabled through process-wide flags of the Starlark resolver, which affects any other use
+	// of Starlark in this process as well.
+	expr.DefaultResolver = expr.NewResolver(opts.ExpressionLimits, expr.WithStarlarkDialect())
+	redact.DefaultRedactor = redact.NewRedactor(opts.RedactFields, opts.RedactHeaders)
+
+	// The debug server is started first, so that hangs during the setup can be diagnosed as well.
+	if len(opts.DebugAddress) > 0 {
+		debugSrv := &http.Server{Addr: opts.DebugAddress, Handler: debug.Handler()}
+		go func() {
+			err := debugSrv.ListenAndServe()
+			log.WithField("err", err).Info("Debug server stopped")
+		}()
+		defer func() {
+			shutdownCtx, cancel := shutdownContext(opts)
+			defer cancel()
+			err := debugSrv.Shutdown(shutdownCtx)
+			log.Infof("Stopped debug server: %v", err)
+		}()
+		log.Infof("Serving debug endpoints at: %s%s", opts.DebugAddress, debug.PathPrefix)
+	}
+	for name, fn := range opts.ExpressionHelpers {
+		if err := expr.RegisterHelper(name, fn); err != nil {
+			log.Fatalf("Failed to register expression helper '%s': %v", name, err)
+		}
+	}
+	for _, path := range opts.ExpressionPlugins {
+		if err := expr.LoadHelperPlugin(path); err != nil {
+			log.Fatalf("Failed to load expression helpers: %v", err)
+		}
+	}
+	if helpers := expr.Helpers(); len(helpers) > 0 {
+		log.Infof("Expression helpers: %v", helpers)
+	}
+
+	if len(opts.AvroSchemaRegistry) > 0 {
+		log.Infof("Using Avro schema registry: %s", opts.AvroSchemaRegistry)
+		schema.DefaultValidator.Register(schema.KindAvro, avro.NewRegistryClient(opts.AvroSchemaRegistry))
+	}
+
 	// See https://github.com/jaegertracing/jaeger-client-go for the env vars to set; defaults to local Jaeger
 	// instance with default ports.
 	cfg, err := jaegercfg.FromEnv()
@@ -143,11 +385,25 @@ func Run(ctx context.Context, opts *Options) error {
 	}
 
 	// Initialize tracer with a logger and a metrics factory
-	closer, err := cfg.InitGlobalTracer(
-		jaegerTracerServiceName,
+	tracerOpts := []jaegercfg.Option{
 		jaegercfg.Logger(jaegerlog.StdLogger),
 		jaegercfg.Metrics(jaegerprom.New()),
-	)
+	}
+	if opts.OTLP != nil {
+		transport, err := otlp.NewTransport(*opts.OTLP)
+		if err != nil {
+			log.Fatalf("Failed to create OTLP exporter: %v", err)
+		}
+		// Like the OpenTelemetry SDKs, sample all traces unless a sampler is configured explicitly.
+		if len(cfg.Sampler.Type) == 0 {
+			cfg.Sampler.Type = jaeger.SamplerTypeConst
+			cfg.Sampler.Param = 1
+		}
+		tracerOpts = append(tracerOpts, jaegercfg.Reporter(jaeger.NewRemoteReporter(transport,
+			jaeger.ReporterOptions.Logger(jaegerlog.StdLogger))))
+		log.Infof("Exporting traces to OTLP endpoint %s", opts.OTLP.Endpoint)
+	}
+	closer, err := cfg.InitGlobalTracer(jaegerTracerServiceName, tracerOpts...)
 	if err != nil {
 		log.Fatalf("Could not initialize jaeger tracer: %s", err.Error())
 	}
@@ -169,21 +425,104 @@ func Run(ctx context.Context, opts *Options) error {
 		otOpts = append(otOpts, grpc_opentracing.LogPayloads())
 	}
 
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		grpc_prometheus.StreamServerInterceptor,
+		grpc_opentracing.OpenTracingStreamServerInterceptor(tracer, otOpts...),
+	}
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		grpc_prometheus.UnaryServerInterceptor,
+		grpc_opentracing.OpenTracingServerInterceptor(tracer, otOpts...),
+	}
+
+	//
+	// Authentication
+	//
+	var authenticator *auth.Authenticator
+	if len(opts.APIKeys) > 0 || opts.JWT != nil {
+		// The internal clients of the APIs authenticate with a key that is generated for this process only.
+		internalKey, err := auth.GenerateKey()
+		if err != nil {
+			log.Fatalf("Failed to generate internal API key: %v", err)
+		}
+		verifiers := []auth.Verifier{auth.Keys(append(opts.APIKeys, auth.Key{
+			Name:  auth.InternalKeyName,
+			Key:   internalKey,
+			Scope: auth.ScopeAdmin,
+		}))}
+		if opts.JWT != nil {
+			verifiers = append(verifiers, auth.NewJWTVerifier(*opts.JWT))
+			log.Infof("Enabled JWT authentication for issuer '%s'", opts.JWT.Issuer)
+		}
+		authenticator = auth.NewAuthenticator(verifiers...)
+		streamInterceptors = append(streamInterceptors, authenticator.StreamServerInterceptor())
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryServerInterceptor())
+		if opts.FissionProxy != nil {
+			opts.FissionProxy.APIKey = internalKey
+		}
+		if opts.Operator != nil {
+			opts.Operator.APIKey = internalKey
+		}
+		if len(opts.APIKeys) > 0 {
+			log.Infof("Enabled API key authentication with %d key(s)", len(opts.APIKeys))
+		}
+	}
+	var authorizer auth.Authorizer
+	if opts.RBACPolicy != nil {
+		if authenticator == nil {
+			log.Fatal("The RBAC policy requires API key or JWT authentication")
+		}
+		authorizer = opts.RBACPolicy
+		log.Infof("Enabled RBAC authorization with %d rule(s)", len(opts.RBACPolicy.Rules))
+	}
+	var rateLimiter *ratelimit.Limiter
+	if opts.RateLimit != nil {
+		// Chained after the authentication, to limit authenticated clients by their identity.
+		rateLimiter = ratelimit.NewLimiter(*opts.RateLimit, authenticator)
+		streamInterceptors = append(streamInterceptors, rateLimiter.StreamServerInterceptor())
+		unaryInterceptors = append(unaryInterceptors, rateLimiter.UnaryServerInterceptor())
+		log.Infof("Enabled rate limiting of %v calls/s and %v invocations/s per client", opts.RateLimit.Rate,
+			opts.RateLimit.InvokeRate)
+	}
+	var quotas *quota.Manager
+	if opts.Quotas != nil {
+		quotas = quota.NewManager(*opts.Quotas)
+		log.Infof("Enabled quotas for %d namespace(s) and the default namespace", len(opts.Quotas.Namespaces))
+	}
+
+	//
+	// Audit log
+	//
+	var auditLogger *audit.Logger
+	if opts.Audit != nil {
+		auditLogger = audit.NewLogger()
+		if len(opts.Audit.File) > 0 {
+			fileSink, err := audit.NewFileSink(opts.Audit.File)
+			if err != nil {
+				log.Fatalf("Failed to setup the audit log: %v", err)
+			}
+			auditLogger.AddSink(fileSink)
+			app.RegisterCloser("audit-file", fileSink)
+		}
+		if len(opts.Audit.WebhookURL) > 0 {
+			webhookSink := audit.NewWebhookSink(opts.Audit.WebhookURL)
+			auditLogger.AddSink(webhookSink)
+			app.RegisterCloser("audit-webhook", webhookSink)
+		}
+		// Chained after the authentication, to record the identity of the callers.
+		unaryInterceptors = append(unaryInterceptors, auditLogger.UnaryServerInterceptor())
+		log.Info("Enabled the audit log")
+	}
+
 	grpcServer := grpc.NewServer(
-		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			grpc_prometheus.StreamServerInterceptor,
-			grpc_opentracing.OpenTracingStreamServerInterceptor(tracer, otOpts...),
-		)),
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			grpc_prometheus.UnaryServerInterceptor,
-			grpc_opentracing.OpenTracingServerInterceptor(tracer, otOpts...),
-		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(streamInterceptors...)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
 	)
 
 	//
 	// Event Store
 	//
 	var eventStore fes.Backend
+	var eventPublisher builtin.EventPublisher
 	if opts.NATS != nil {
 		log.WithFields(log.Fields{
 			"url":           "<redacted>", // Typically includes the password
@@ -192,9 +531,22 @@ func Run(ctx context.Context, opts *Options) error {
 			"autoReconnect": opts.NATS.AutoReconnect,
 		}).Infof("Using event store: NATS")
 		natsBackend := setupNatsEventStoreClient(*opts.NATS)
+		app.RegisterCloser("event-store", natsBackend)
 		es = natsBackend
 		esPub = natsBackend
 		eventStore = natsBackend
+		if opts.EventBuffer != nil {
+			// Closed before the event store, to replay the buffered events a final time.
+			bufferedBackend, err := buffer.New(natsBackend, *opts.EventBuffer)
+			if err != nil {
+				log.Fatalf("Failed to setup the event buffer: %v", err)
+			}
+			app.RegisterCloser("event-buffer", bufferedBackend)
+			es = bufferedBackend
+			eventStore = bufferedBackend
+			log.Infof("Buffering up to %d events while the event store is unavailable", opts.EventBuffer.MaxSize)
+		}
+		eventPublisher = natsBackend.NatsConn()
 	} else {
 		log.Info("Using the in-memory event store")
 		memBackend := mem.NewBackend()
@@ -203,6 +555,40 @@ func Run(ctx context.Context, opts *Options) error {
 		eventStore = memBackend
 	}
 
+	if opts.Audit != nil && opts.Audit.EventStore {
+		auditLogger.AddSink(audit.NewEventStoreSink(eventStore))
+	}
+
+	//
+	// Blob Store
+	//
+	var offloader api.ValueOffloader
+	httpconv.DefaultHTTPMapper.MaxMemorySize = opts.MaxBodyMemorySize
+	fnenv.MaxPayloadSize = opts.MaxPayloadSize
+	fnenv.MaxOutputSize = opts.MaxOutputSize
+	fnenv.HeartbeatTimeout = opts.HeartbeatTimeout
+	if err := fnenv.ValidateHeartbeatTimeout(opts.HeartbeatTimeout, heartbeatPollIntervals(opts)); err != nil {
+		log.Fatalf("Invalid heartbeat timeout: %v", err)
+	}
+	if len(opts.OutputSizePolicy) > 0 {
+		fnenv.OutputSizePolicy = opts.OutputSizePolicy
+	}
+	if opts.BlobStore != nil {
+		blobStore, err := setupBlobStore(opts.BlobStore)
+		if err != nil {
+			log.Fatalf("Failed to setup blob store: %v", err)
+		}
+		blobOffloader := blobstore.NewOffloader(blobStore, opts.BlobStore.Threshold)
+		typedvalues.RegisterDereferencer(blobOffloader)
+		offloader = blobOffloader
+
+		if streamStore, ok := blobStore.(blobstore.StreamStore); ok {
+			log.Infof("Streaming binary values larger than %d bytes to the blob store", opts.BlobStore.StreamThreshold)
+			typedvalues.RegisterStreamStore(blobstore.NewStreamer(streamStore))
+			httpconv.DefaultHTTPMapper.StreamThreshold = opts.BlobStore.StreamThreshold
+		}
+	}
+
 	// Caches
 	invocationStore := getInvocationStore(app, esPub, eventStore)
 	workflowStore := getWorkflowStore(app, esPub, eventStore)
@@ -210,11 +596,13 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	// Function Runtimes
 	//
-	invocationAPI := api.NewInvocationAPI(es)
+	invocationAPI := api.NewInvocationAPI(es, offloader)
 	resolvers := map[string]fnenv.RuntimeResolver{}
 	runtimes := map[string]fnenv.Runtime{}
+	logProviders := map[string]fnenv.LogProvider{}
 	reflectiveRuntime := workflows.NewRuntime(invocationAPI, invocationStore, workflowStore)
-	if opts.InternalRuntime || opts.Fission != nil {
+	if opts.InternalRuntime || opts.Fission != nil || opts.HTTP != nil || opts.Job != nil ||
+		opts.GRPC != nil || opts.Container != nil {
 		log.Infof("Using function runtime: Workflow")
 		runtimes[workflows.Name] = reflectiveRuntime
 	} else {
@@ -223,6 +611,14 @@ func Run(ctx context.Context, opts *Options) error {
 	if opts.InternalRuntime {
 		log.Infof("Using function runtime: Internal")
 		internalRuntime := setupInternalFunctionRuntime()
+		internalRuntime.RegisterFn(builtin.Retry, builtin.NewFunctionRetry(
+			api.NewWorkflowAPI(es, fnenv.NewMetaResolver(resolvers)), reflectiveRuntime))
+		internalRuntime.RegisterFn(builtin.Timeout, builtin.NewFunctionTimeout(
+			api.NewWorkflowAPI(es, fnenv.NewMetaResolver(resolvers)), reflectiveRuntime))
+		kvStore := builtin.NewEventKVStore(es, invocationAPI, builtin.DefaultKVCacheSize)
+		internalRuntime.RegisterFn(builtin.KVSet, builtin.NewFunctionKVSet(kvStore, invocationStore))
+		internalRuntime.RegisterFn(builtin.KVGet, builtin.NewFunctionKVGet(kvStore, invocationStore))
+		internalRuntime.RegisterFn(builtin.Emit, builtin.NewFunctionEmit(eventPublisher))
 		runtimes["internal"] = internalRuntime
 		resolvers["internal"] = internalRuntime
 		log.Infof("Internal runtime functions: %v", internalRuntime.Installed())
@@ -233,9 +629,115 @@ func Run(ctx context.Context, opts *Options) error {
 			"router":     opts.Fission.RouterAddr,
 			"executor":   opts.Fission.ExecutorAddress,
 		}).Infof("Using function runtime: Fission")
-		fissionFnenv := setupFissionFunctionRuntime(opts.Fission)
+		fissionFnenv, err := setupFissionFunctionRuntime(opts.Fission)
+		if err != nil {
+			log.Fatalf("Failed to setup Fission function runtime: %v", err)
+		}
 		runtimes["fission"] = fissionFnenv
 		resolvers["fission"] = fissionFnenv
+		logProviders["fission"] = fissionFnenv
+
+		if opts.Fission.WatchFunctions {
+			functions, err := setupFissionFunctionWatcher(opts.Fission)
+			if err != nil {
+				log.Fatalf("Failed to setup watch of Fission functions: %v", err)
+			}
+			wfAPI := api.NewWorkflowAPI(es, fnenv.NewMetaResolver(resolvers))
+			go fissionFnenv.WatchFunctions(ctx, functions, func(fn types.FnRef) {
+				parsed := controller.ReparseWorkflows(wfAPI, workflowStore, func(ref types.FnRef) bool {
+					return fissionFnenv.RefersTo(ref, fn)
+				})
+				log.Infof("Resolved %d workflow(s) of recreated Fission function %s again", parsed, fn.Format())
+			})
+		}
+	}
+	if opts.HTTP != nil {
+		log.WithFields(log.Fields{
+			"method": opts.HTTP.DefaultMethod,
+			"hosts":  len(opts.HTTP.Auth),
+		}).Infof("Using function runtime: HTTP")
+		httpFnenv, err := fnenvhttp.NewFromConfig(*opts.HTTP)
+		if err != nil {
+			log.Fatalf("Failed to setup HTTP function runtime: %v", err)
+		}
+		for _, scheme := range []string{"http", "https"} {
+			runtimes[scheme] = httpFnenv
+			resolvers[scheme] = httpFnenv
+		}
+	}
+	if opts.Job != nil {
+		log.WithFields(log.Fields{
+			"namespace": opts.Job.Config.Namespace,
+		}).Infof("Using function runtime: Kubernetes Job")
+		jobFnenv, err := setupJobFunctionRuntime(opts.Job)
+		if err != nil {
+			log.Fatalf("Failed to setup Kubernetes Job function runtime: %v", err)
+		}
+		runtimes[job.Name] = jobFnenv
+		resolvers[job.Name] = jobFnenv
+	}
+	if opts.GRPC != nil {
+		log.WithFields(log.Fields{
+			"tls": opts.GRPC.TLS || len(opts.GRPC.CAFile) > 0,
+		}).Infof("Using function runtime: gRPC")
+		grpcFnenv, err := fnenvgrpc.NewFromConfig(*opts.GRPC)
+		if err != nil {
+			log.Fatalf("Failed to setup gRPC function runtime: %v", err)
+		}
+		app.RegisterCloser("fnenv-grpc", grpcFnenv)
+		runtimes[fnenvgrpc.Name] = grpcFnenv
+		resolvers[fnenvgrpc.Name] = grpcFnenv
+	}
+	if opts.Container != nil {
+		log.WithFields(log.Fields{
+			"cli": opts.Container.Command,
+		}).Infof("Using function runtime: Container")
+		containerFnenv := container.New(*opts.Container)
+		runtimes[container.Name] = containerFnenv
+		resolvers[container.Name] = containerFnenv
+	}
+	if opts.Secrets != nil {
+		log.WithFields(log.Fields{
+			"namespace": opts.Secrets.Namespace,
+		}).Info("Resolving secrets and configmaps in task inputs")
+		keyRefResolver, err := setupKeyRefResolver(opts.Secrets)
+		if err != nil {
+			log.Fatalf("Failed to setup the resolver of secrets and configmaps: %v", err)
+		}
+		for name, runtime := range runtimes {
+			// The workflows and internal runtimes pass their inputs back into the workflow engine.
+			if name == workflows.Name || name == "internal" {
+				continue
+			}
+			runtimes[name] = keyref.Wrap(runtime, keyRefResolver)
+		}
+	}
+
+	//
+	// Workers
+	//
+	if opts.Worker != nil {
+		workerRuntimes := map[string]fnenv.Runtime{}
+		for name, runtime := range runtimes {
+			if isRemoteRuntime(name) {
+				workerRuntimes[name] = runtime
+			}
+		}
+		worker.RegisterWorkerServer(grpcServer, worker.NewServer(workerRuntimes, *opts.Worker))
+		log.Infof("Serving worker API for %d function runtime(s)", len(workerRuntimes))
+	}
+	if opts.RemoteWorkers != nil {
+		pool, err := setupWorkerPool(opts.RemoteWorkers)
+		if err != nil {
+			log.Fatalf("Failed to setup the pool of remote workers: %v", err)
+		}
+		app.RegisterCloser("worker-pool", pool)
+		for name := range runtimes {
+			if isRemoteRuntime(name) {
+				runtimes[name] = pool
+			}
+		}
+		log.Infof("Delegating the invocations of functions to %d remote worker(s)", len(opts.RemoteWorkers.Addresses))
 	}
 
 	//
@@ -246,12 +748,27 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	// Controllers
 	//
+	// On shutdown, the APIs are stopped first, then the controllers are drained (sensors, evaluation queue and
+	// executor, in that order), and finally the stores, runtimes and event store are closed.
+	defer func() {
+		util.LogIfError(app.Close())
+	}()
+	// The executors of the controllers can be resized at runtime through the admin API.
+	executors := map[string]*executor.LocalExecutor{}
+	// The quarantined controllers of the control systems can be listed and released through the admin API.
+	ctrl.QuarantineThreshold = opts.QuarantineThreshold
+	systems := map[string]*ctrl.System{}
 	if opts.WorkflowController {
 		log.Info("Running workflow controller")
-		workflowCtrl := setupWorkflowController(workflowStore, es, resolvers)
-		go workflowCtrl.Run()
+		exec := setupExecutor("workflow", 10, 1000, opts.ExecutorAlarms)
+		executors[exec.Name()] = exec
+		workflowCtrl := setupWorkflowController(workflowStore, es, resolvers, exec)
+		systems[workflowCtrl.System().Name()] = workflowCtrl.System()
+		if err := workflowCtrl.Run(); err != nil {
+			return fmt.Errorf("failed to run workflow controller: %v", err)
+		}
 		defer func() {
-			if err := workflowCtrl.Close(); err != nil {
+			if err := workflowCtrl.Drain(opts.DrainTimeout); err != nil {
 				log.Errorf("Failed to stop workflow controller: %v", err)
 			} else {
 				log.Info("Stopped workflow controller")
@@ -260,10 +777,17 @@ func Run(ctx context.Context, opts *Options) error {
 	}
 	if opts.InvocationController {
 		log.Info("Running invocation controller")
-		invocationCtrl := setupInvocationController(invocationStore, es, runtimes, resolvers, sched)
-		go invocationCtrl.Run()
+		exec := setupExecutor("invocation", executorMaxParallelism, executorMaxTaskQueueSize, opts.ExecutorAlarms)
+		exec.SetMaxGroupParallelism(opts.MaxGroupParallelism)
+		executors[exec.Name()] = exec
+		invocationCtrl := setupInvocationController(invocationStore, es, runtimes, resolvers, sched, offloader,
+			quotas, exec)
+		systems[invocationCtrl.System().Name()] = invocationCtrl.System()
+		if err := invocationCtrl.Run(); err != nil {
+			return fmt.Errorf("failed to run invocation controller: %v", err)
+		}
 		defer func() {
-			if err := invocationCtrl.Close(); err != nil {
+			if err := invocationCtrl.Drain(opts.DrainTimeout); err != nil {
 				log.Errorf("Failed to stop invocation controller: %v", err)
 			} else {
 				log.Info("Stopped invocation controller")
@@ -276,22 +800,43 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	ps.Register(opts.FissionProxy)
 
+	//
+	// Kubernetes integration
+	//
+	if opts.Operator != nil {
+		ps.Register(opts.Operator)
+	}
+
 	//
 	// gRPC API
 	//
 	if opts.AdminAPI {
-		serveAdminAPI(grpcServer)
+		var auditQuerier apiserver.AuditQuerier
+		if auditLogger != nil {
+			auditQuerier = auditLogger
+		}
+		caches := map[string]fes.CacheRebuilder{
+			types.TypeWorkflow:   workflowStore.CacheReader.(fes.CacheRebuilder),
+			types.TypeInvocation: invocationStore.CacheReader.(fes.CacheRebuilder),
+		}
+		serveAdminAPI(grpcServer, auditQuerier, es, executors, systems, caches)
 	}
 
 	if opts.WorkflowAPI {
-		serveWorkflowAPI(grpcServer, es, resolvers, workflowStore)
+		serveWorkflowAPI(grpcServer, es, resolvers, workflowStore, invocationStore, offloader, authorizer)
 	}
 
 	if opts.InvocationAPI {
-		serveInvocationAPI(grpcServer, es, invocationStore, workflowStore)
+		// The schedules of the invocations are only known to the scheduler of the invocation controller.
+		var explainer *scheduler.InvocationScheduler
+		if opts.InvocationController {
+			explainer = sched
+		}
+		serveInvocationAPI(grpcServer, es, invocationStore, workflowStore, offloader, authorizer, quotas,
+			logProviders, explainer)
 	}
 
-	if opts.AdminAPI || opts.WorkflowAPI || opts.InvocationAPI {
+	if opts.AdminAPI || opts.WorkflowAPI || opts.InvocationAPI || opts.Worker != nil {
 		if opts.Metrics {
 			log.Debug("Instrumenting gRPC server with Prometheus metrics")
 			grpc_prometheus.Register(grpcServer)
@@ -313,8 +858,9 @@ func Run(ctx context.Context, opts *Options) error {
 	//
 	// HTTP API
 	//
-	if opts.HTTPGateway || opts.Metrics {
-		grpcMux := grpcruntime.NewServeMux()
+	if opts.HTTPGateway || opts.Metrics || opts.DebugAPI {
+		grpcMux := grpcruntime.NewServeMux(grpcruntime.WithMarshalerOption(apiserver.MIMEEventStream,
+			&apiserver.EventStreamMarshaler{JSONPb: grpcruntime.JSONPb{OrigName: true}}))
 		httpMux := http.NewServeMux()
 
 		if opts.HTTPGateway {
@@ -337,15 +883,43 @@ func Run(ctx context.Context, opts *Options) error {
 			log.Infof("Set up prometheus collector: %v/metrics", apiGatewayAddress)
 		}
 
+		if opts.DebugAPI {
+			debugHandler := debug.Handler()
+			if authenticator != nil {
+				debugHandler = authenticator.HTTPScopeHandler(auth.ScopeAdmin, debugHandler)
+			} else {
+				log.Warn("Serving debug endpoints on the HTTP API without authentication")
+			}
+			httpMux.Handle(debug.PathPrefix, debugHandler)
+			log.Infof("Serving debug endpoints at: %v%s", apiGatewayAddress, debug.PathPrefix)
+		}
+
 		httpApiSrv := &http.Server{Addr: apiGatewayAddress}
-		httpMux.Handle("/", handlers.LoggingHandler(os.Stdout, tracingWrapper(grpcMux)))
+		var gatewayHandler http.Handler = grpcMux
+		if opts.HTTPGateway && opts.WorkflowAPI && opts.InvocationAPI {
+			gatewayHandler, err = serveWorkflowEndpoints(app, opts, grpcMux)
+			if err != nil {
+				return err
+			}
+			log.Infof("Serving the OpenAPI document of the workflows at: %v%s", apiGatewayAddress,
+				openapi.DocumentPath)
+		}
+		if authenticator != nil {
+			gatewayHandler = authenticator.HTTPHandler(gatewayHandler)
+		}
+		if rateLimiter != nil {
+			gatewayHandler = rateLimiter.HTTPHandler(gatewayHandler)
+		}
+		httpMux.Handle("/", handlers.LoggingHandler(os.Stdout, tracingWrapper(gatewayHandler)))
 		httpApiSrv.Handler = httpMux
 		go func() {
 			err := httpApiSrv.ListenAndServe()
 			log.WithField("err", err).Info("HTTP Gateway stopped")
 		}()
 		defer func() {
-			err := httpApiSrv.Shutdown(ctx)
+			shutdownCtx, cancel := shutdownContext(opts)
+			defer cancel()
+			err := httpApiSrv.Shutdown(shutdownCtx)
 			log.Infof("Stopped HTTP API server: %v", err)
 		}()
 
@@ -357,11 +931,35 @@ func Run(ctx context.Context, opts *Options) error {
 	<-ctx.Done()
 	log.WithField("reason", ctx.Err()).Info("Shutting down...")
 	logIfErr(ps.Close())
-	util.LogIfError(app.Close())
-	time.Sleep(5 * time.Second) // Hack: wait a bit to ensure all goroutines are shutdown.
 	return nil
 }
 
+// shutdownContext returns the context for stopping a server gracefully, which is bounded by the drain timeout. As the
+// servers are stopped once the context of the bundle is done, it cannot be derived from that context.
+func shutdownContext(opts *Options) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), opts.DrainTimeout)
+}
+
+// heartbeatPollIntervals returns the poll intervals of the enabled runtimes that report a heartbeat on each poll.
+func heartbeatPollIntervals(opts *Options) map[string]time.Duration {
+	intervals := map[string]time.Duration{}
+	if opts.Fission != nil && !opts.Fission.Config.Async.Disabled {
+		interval := opts.Fission.Config.Async.PollInterval
+		if interval <= 0 {
+			interval = fission.DefaultConfig.Async.PollInterval
+		}
+		intervals[fission.Name] = interval
+	}
+	if opts.Job != nil {
+		interval := opts.Job.Config.PollInterval
+		if interval <= 0 {
+			interval = job.DefaultPollInterval
+		}
+		intervals[job.Name] = interval
+	}
+	return intervals
+}
+
 func getWorkflowStore(app *App, eventPub pubsub.Publisher, backend fes.Backend) *store.Workflows {
 	c := setupWorkflowCache(app, eventPub, backend)
 	return store.NewWorkflowsStore(c)
@@ -376,8 +974,53 @@ func setupInternalFunctionRuntime() *native.FunctionEnv {
 	return native.NewFunctionEnv(builtin.DefaultBuiltinFunctions)
 }
 
-func setupFissionFunctionRuntime(fissionOpts *FissionOptions) *fission.FunctionEnv {
-	return fission.New(fissionOpts.ExecutorAddress, fissionOpts.ControllerAddr, fissionOpts.RouterAddr)
+func setupFissionFunctionRuntime(fissionOpts *FissionOptions) (*fission.FunctionEnv, error) {
+	return fission.NewWithConfig(fissionOpts.ExecutorAddress, fissionOpts.ControllerAddr, fissionOpts.RouterAddr,
+		fissionOpts.Config)
+}
+
+func setupFissionFunctionWatcher(fissionOpts *FissionOptions) (fission.FunctionWatcher, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", fissionOpts.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := crd.GetCrdClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return crd.MakeFunctionInterface(client, metav1.NamespaceAll), nil
+}
+
+func setupJobFunctionRuntime(opts *JobOptions) (*job.FunctionEnv, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return job.New(client, opts.Config), nil
+}
+
+func setupKeyRefResolver(opts *SecretsOptions) (*keyref.Resolver, error) {
+	config, err := clientcmd.BuildConfigFromFlags("", opts.Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Vault == nil {
+		return keyref.NewResolver(client, opts.Namespace), nil
+	}
+	secrets, err := keyref.NewVaultSecrets(*opts.Vault)
+	if err != nil {
+		return nil, err
+	}
+	log.Infof("Reading secrets from Vault at %s", opts.Vault.Addr)
+	return keyref.NewResolverWithSecrets(client, secrets, opts.Namespace), nil
 }
 
 func setupNatsEventStoreClient(config nats.Config) *nats.EventStore {
@@ -440,24 +1083,31 @@ func setupWorkflowCache(app *App, workflowEventPub pubsub.Publisher, backend fes
 	return c
 }
 
-func serveAdminAPI(s *grpc.Server) {
-	adminServer := &apiserver.Admin{}
+func serveAdminAPI(s *grpc.Server, audit apiserver.AuditQuerier, es fes.Backend,
+	executors map[string]*executor.LocalExecutor, systems map[string]*ctrl.System,
+	caches map[string]fes.CacheRebuilder) {
+	adminServer := apiserver.NewAdmin(audit, es, executors, systems, caches)
 	apiserver.RegisterAdminAPIServer(s, adminServer)
 	log.Infof("Serving admin gRPC API at %s.", gRPCAddress)
 }
 
 func serveWorkflowAPI(s *grpc.Server, es fes.Backend, resolvers map[string]fnenv.RuntimeResolver,
-	store *store.Workflows) {
+	store *store.Workflows, invocations *store.Invocations, offloader api.ValueOffloader,
+	authorizer auth.Authorizer) {
 	workflowParser := fnenv.NewMetaResolver(resolvers)
 	workflowAPI := api.NewWorkflowAPI(es, workflowParser)
-	workflowServer := apiserver.NewWorkflow(workflowAPI, store, es)
+	invocationAPI := api.NewInvocationAPI(es, offloader)
+	workflowServer := apiserver.NewWorkflow(workflowAPI, store, invocationAPI, invocations, es, authorizer)
 	apiserver.RegisterWorkflowAPIServer(s, workflowServer)
 	log.Infof("Serving workflow gRPC API at %s.", gRPCAddress)
 }
 
-func serveInvocationAPI(s *grpc.Server, es fes.Backend, invocations *store.Invocations, workflows *store.Workflows) {
-	invocationAPI := api.NewInvocationAPI(es)
-	invocationServer := apiserver.NewInvocation(invocationAPI, invocations, workflows, es)
+func serveInvocationAPI(s *grpc.Server, es fes.Backend, invocations *store.Invocations, workflows *store.Workflows,
+	offloader api.ValueOffloader, authorizer auth.Authorizer, quotas *quota.Manager,
+	logProviders map[string]fnenv.LogProvider, sched *scheduler.InvocationScheduler) {
+	invocationAPI := api.NewInvocationAPI(es, offloader)
+	invocationServer := apiserver.NewInvocation(invocationAPI, invocations, workflows, es, authorizer, quotas,
+		logProviders, sched)
 	apiserver.RegisterWorkflowInvocationAPIServer(s, invocationServer)
 	log.Infof("Serving workflow invocation gRPC API at %s.", gRPCAddress)
 }
@@ -496,26 +1146,108 @@ func serveHTTPGateway(ctx context.Context, mux *grpcruntime.ServeMux, adminAPIAd
 	}
 }
 
+// serveWorkflowEndpoints serves the invoke endpoints of the workflows and their OpenAPI documents on the HTTP gateway,
+// which invoke the workflows through the gRPC APIs with the credentials of the caller. The connection to the gRPC APIs
+// is closed along with the app.
+func serveWorkflowEndpoints(app *App, opts *Options, gateway http.Handler) (http.Handler, error) {
+	conn, err := grpc.Dial(gRPCAddress, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the gRPC APIs: %v", err)
+	}
+	app.RegisterCloser("workflow-endpoints", conn)
+	timeout := opts.InvokeTimeout
+	if timeout <= 0 {
+		timeout = DefaultInvokeTimeout
+	}
+	return openapi.NewHandler(apiserver.NewClient(conn), timeout, gateway), nil
+}
+
 func setupInvocationController(invocations *store.Invocations, es fes.Backend,
 	fnRuntimes map[string]fnenv.Runtime, fnResolvers map[string]fnenv.RuntimeResolver,
-	s *scheduler.InvocationScheduler) *controller.InvocationMetaController {
+	s *scheduler.InvocationScheduler, offloader api.ValueOffloader,
+	quotas *quota.Manager, localExec *executor.LocalExecutor) *controller.InvocationMetaController {
 
 	workflowAPI := api.NewWorkflowAPI(es, fnenv.NewMetaResolver(fnResolvers))
-	invocationAPI := api.NewInvocationAPI(es)
+	invocationAPI := api.NewInvocationAPI(es, offloader)
 	dynamicAPI := api.NewDynamicApi(workflowAPI, invocationAPI)
-	taskAPI := api.NewTaskAPI(fnRuntimes, es, dynamicAPI)
-	stateStore := expr.NewStore()
-	localExec := executor.NewLocalExecutor(executorMaxParallelism, executorMaxTaskQueueSize)
-	return controller.NewInvocationMetaController(localExec, invocations, invocationAPI, taskAPI, s, stateStore, invocationStorePollInterval)
+	taskAPI := api.NewTaskAPI(fnRuntimes, es, dynamicAPI, offloader)
+	stateStore := expr.NewStoreWithInvocations(invocations)
+	return controller.NewInvocationMetaController(localExec, invocations, invocationAPI, taskAPI, s, stateStore,
+		invocationStorePollInterval, quotas)
+}
+
+// setupBlobStore creates the blob store identified by the URL of the options.
+func setupBlobStore(opts *BlobStoreOptions) (blobstore.Store, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid blob store url: %v", err)
+	}
+	var store blobstore.Store
+	switch u.Scheme {
+	case "mem":
+		log.Info("Using blob store: in-memory")
+		store = blobmem.NewStore()
+	case "file":
+		log.WithField("dir", u.Path).Info("Using blob store: file system")
+		store, err = blobfile.NewStore(u.Path)
+		if err != nil {
+			return nil, err
+		}
+	case "s3", "gcs":
+		endpoint := u.Query().Get("endpoint")
+		if len(endpoint) == 0 && u.Scheme == "gcs" {
+			endpoint = blobs3.GCSEndpoint
+		}
+		log.WithFields(log.Fields{
+			"bucket":   u.Host,
+			"endpoint": endpoint,
+		}).Info("Using blob store: S3")
+		store, err = blobs3.NewStore(blobs3.Config{
+			Endpoint:  endpoint,
+			Bucket:    u.Host,
+			Region:    u.Query().Get("region"),
+			AccessKey: opts.AccessKey,
+			SecretKey: opts.SecretKey,
+			Insecure:  u.Query().Get("insecure") == "true",
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported blob store: %s", opts.URL)
+	}
+	return store, nil
 }
 
 func setupWorkflowController(store *store.Workflows, es fes.Backend,
-	fnResolvers map[string]fnenv.RuntimeResolver) *controller.WorkflowMetaController {
+	fnResolvers map[string]fnenv.RuntimeResolver, exec *executor.LocalExecutor) *controller.WorkflowMetaController {
 	wfAPI := api.NewWorkflowAPI(es, fnenv.NewMetaResolver(fnResolvers))
-	exec := executor.NewLocalExecutor(10, 1000)
 	return controller.NewWorkflowMetaController(wfAPI, store, exec, workflowStorePollInterval)
 }
 
+// isRemoteRuntime returns whether the functions of the runtime can be invoked by remote workers. The workflows and
+// internal runtimes depend on the state of the workflow engine, such as the invocations and the key-value store.
+func isRemoteRuntime(name string) bool {
+	return name != workflows.Name && name != "internal"
+}
+
+func setupWorkerPool(opts *RemoteWorkerOptions) (*worker.Pool, error) {
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if len(opts.APIKey) > 0 {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.Credentials(opts.APIKey)))
+	}
+	return worker.NewPool(opts.Addresses, dialOpts...)
+}
+
+// setupExecutor creates the task executor of a controller. If saturationAlarms is nil, the default alarms are used.
+func setupExecutor(name string, maxParallelism, maxQueueSize int, saturationAlarms []int) *executor.LocalExecutor {
+	exec := executor.NewNamedLocalExecutor(name, maxParallelism, maxQueueSize)
+	if saturationAlarms != nil {
+		exec.SetSaturationAlarms(saturationAlarms)
+	}
+	return exec
+}
+
 func setupMetricsEndpoint(apiMux *http.ServeMux) {
 	apiMux.Handle("/metrics", promhttp.Handler())
 }
@@ -539,6 +1271,9 @@ func tracingWrapper(h http.Handler) http.Handler {
 			)
 			r = r.WithContext(opentracing.ContextWithSpan(r.Context(), serverSpan))
 			defer serverSpan.Finish()
+			if traceID := fes.TraceID(serverSpan.Context()); len(traceID) > 0 {
+				w.Header().Set(TraceIDHeader, traceID)
+			}
 		} else {
 			log.Errorf("Failed to extract tracer from HTTP request: %v", err)
 		}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
 	"github.com/fission/fission-workflows/pkg/apiserver/fission"
 	"github.com/gorilla/handlers"
 	log "github.com/sirupsen/logrus"
@@ -20,6 +22,10 @@ type FissionProxyConfig struct {
 	WorkflowsAddr  string
 	ExposeMetrics  bool
 
+	// APIKey authenticates the proxy with the workflow APIs, if the API key authentication is enabled.
+	APIKey string
+
+	mu     sync.Mutex
 	server *http.Server
 }
 
@@ -40,7 +46,11 @@ func (c *FissionProxyConfig) Run() error {
 		return nil
 	}
 
-	conn, err := grpc.Dial(c.ProxyAddr, grpc.WithInsecure())
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if len(c.APIKey) > 0 {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.Credentials(c.APIKey)))
+	}
+	conn, err := grpc.Dial(c.ProxyAddr, dialOpts...)
 	if err != nil {
 		panic(err)
 	}
@@ -52,10 +62,16 @@ func (c *FissionProxyConfig) Run() error {
 		Addr:    c.ProxyAddr,
 		Handler: handlers.LoggingHandler(os.Stdout, proxyMux),
 	}
+	c.mu.Lock()
+	c.server = fissionProxySrv
+	c.mu.Unlock()
 
 	log.Infof("Serving HTTP Fission Proxy at: %s", fissionProxySrv.Addr)
 	err = fissionProxySrv.ListenAndServe()
 	log.Infof("Fission Proxy server stopped: %v", err)
+	if err == http.ErrServerClosed {
+		return nil
+	}
 	return err
 }
 
@@ -63,6 +79,11 @@ func (c *FissionProxyConfig) Close() error {
 	if c == nil {
 		return nil
 	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.server == nil {
+		return nil
+	}
 
 	err := c.server.Shutdown(context.Background())
 	if err != nil {
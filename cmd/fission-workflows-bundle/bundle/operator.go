@@ -0,0 +1,88 @@
+package bundle
+
+import (
+	"context"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/operator"
+	log "github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+	"google.golang.org/grpc"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// OperatorConfig configures the operator that syncs Workflow custom resources into the workflow engine.
+type OperatorConfig struct {
+	// Kubeconfig is the path to the kubeconfig. If empty, the in-cluster configuration is used.
+	Kubeconfig string
+
+	// Namespace is the namespace to watch the Workflow resources in. If empty, all namespaces are watched.
+	Namespace string
+
+	// WorkflowsAddr is the address of the workflow API to sync the workflows to.
+	WorkflowsAddr string
+
+	// APIKey authenticates the operator with the workflow API, if the API key authentication is enabled.
+	APIKey string
+
+	cancel context.CancelFunc
+	mu     sync.Mutex
+}
+
+func ParseOperatorConfig(ctx *cli.Context) *OperatorConfig {
+	if !ctx.Bool("operator") {
+		return nil
+	}
+	return &OperatorConfig{
+		Kubeconfig:    ctx.String("kubeconfig"),
+		Namespace:     ctx.String("operator-namespace"),
+		WorkflowsAddr: gRPCAddress,
+	}
+}
+
+func (c *OperatorConfig) Run() error {
+	if c == nil {
+		return nil
+	}
+
+	config, err := clientcmd.BuildConfigFromFlags("", c.Kubeconfig)
+	if err != nil {
+		return err
+	}
+	crds, err := operator.NewRESTClient(config)
+	if err != nil {
+		return err
+	}
+	dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+	if len(c.APIKey) > 0 {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.Credentials(c.APIKey)))
+	}
+	conn, err := grpc.Dial(c.WorkflowsAddr, dialOpts...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.cancel = cancel
+	c.mu.Unlock()
+	log.Info("Running Workflow resource operator")
+	operator.New(apiserver.NewWorkflowAPIClient(conn), crds, c.Namespace).Run(ctx)
+	return conn.Close()
+}
+
+func (c *OperatorConfig) Close() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+	log.Info("Stopped Workflow resource operator")
+	return nil
+}
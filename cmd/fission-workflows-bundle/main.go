@@ -3,14 +3,34 @@ package main
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fission/fission-workflows/cmd/fission-workflows-bundle/bundle"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/apiserver/ratelimit"
+	"github.com/fission/fission-workflows/pkg/blobstore"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fes/backend/buffer"
 	"github.com/fission/fission-workflows/pkg/fes/backend/nats"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/container"
+	"github.com/fission/fission-workflows/pkg/fnenv/fission"
+	fnenvgrpc "github.com/fission/fission-workflows/pkg/fnenv/grpc"
+	fnenvhttp "github.com/fission/fission-workflows/pkg/fnenv/http"
+	"github.com/fission/fission-workflows/pkg/fnenv/job"
+	"github.com/fission/fission-workflows/pkg/fnenv/keyref"
+	"github.com/fission/fission-workflows/pkg/quota"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/fission/fission-workflows/pkg/util/otlp"
+	"github.com/fission/fission-workflows/pkg/worker"
 	natsio "github.com/nats-io/go-nats"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
@@ -20,17 +40,15 @@ func main() {
 	ctx, cancelFn := context.WithCancel(context.Background())
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, os.Kill, syscall.SIGTERM)
+	// The first signal shuts the bundle down gracefully, which is bounded by the drain timeout. A second signal forces
+	// the shutdown.
 	go func() {
-		for sig := range c {
-			fmt.Println("Received signal: ", sig)
-			go func() {
-				time.Sleep(30 * time.Second)
-				fmt.Println("Deadline exceeded; forcing shutdown.")
-				os.Exit(0)
-			}()
-			cancelFn()
-			break
-		}
+		sig := <-c
+		fmt.Println("Received signal: ", sig)
+		cancelFn()
+		sig = <-c
+		fmt.Printf("Received signal %v; forcing shutdown.\n", sig)
+		os.Exit(1)
 	}()
 
 	cliApp := createCli()
@@ -46,9 +64,58 @@ func main() {
 			logrus.Fatal("Error while parsing Fission Proxy: ", err)
 		}
 
+		httpOptions, err := parseHTTPOptions(c)
+		if err != nil {
+			logrus.Fatal("Error while parsing HTTP function environment options: ", err)
+		}
+
+		var apiKeys []auth.Key
+		if path := c.String("api-keys"); len(path) > 0 {
+			apiKeys, err = auth.LoadKeys(path)
+			if err != nil {
+				logrus.Fatal("Error while loading API keys: ", err)
+			}
+		}
+
+		jwtConfig, err := parseJWTConfig(c)
+		if err != nil {
+			logrus.Fatal("Error while parsing JWT authentication options: ", err)
+		}
+
+		var rbacPolicy *auth.Policy
+		if path := c.String("rbac-policy"); len(path) > 0 {
+			rbacPolicy, err = auth.LoadPolicy(path)
+			if err != nil {
+				logrus.Fatal("Error while loading RBAC policy: ", err)
+			}
+		}
+
+		var quotas *quota.Config
+		if path := c.String("quotas"); len(path) > 0 {
+			quotas, err = quota.LoadConfig(path)
+			if err != nil {
+				logrus.Fatal("Error while loading quotas: ", err)
+			}
+		}
+
+		otlpConfig, err := parseOTLPConfig(c)
+		if err != nil {
+			logrus.Fatal("Error while parsing OTLP options: ", err)
+		}
+
+		outputSizePolicy, err := fnenv.ParseOutputPolicy(c.String("output-size-policy"))
+		if err != nil {
+			logrus.Fatal("Error while parsing the output size policy: ", err)
+		}
+
 		return bundle.Run(ctx, &bundle.Options{
 			NATS:                 parseNatsOptions(c),
+			EventBuffer:          parseEventBufferOptions(c),
 			Fission:              parseFissionOptions(c),
+			HTTP:                 httpOptions,
+			Job:                  parseJobOptions(c),
+			GRPC:                 parseGRPCOptions(c),
+			Container:            parseContainerOptions(c),
 			Scheduler:            policy,
 			InternalRuntime:      c.Bool("internal"),
 			InvocationController: c.Bool("controller") || c.Bool("invocation-controller"),
@@ -60,6 +127,35 @@ func main() {
 			Metrics:              c.Bool("metrics"),
 			Debug:                c.Bool("debug"),
 			FissionProxy:         proxyConfig,
+			Operator:             bundle.ParseOperatorConfig(c),
+			Secrets:              parseSecretsOptions(c),
+			ExpressionLimits:     parseExpressionLimits(c),
+			ExpressionPlugins:    c.StringSlice("expr-plugin"),
+			BlobStore:            parseBlobStoreOptions(c),
+			MaxBodyMemorySize:    c.Int64("max-body-memory-size"),
+			MaxPayloadSize:       c.Int64("max-payload-size"),
+			MaxOutputSize:        c.Int64("max-output-size"),
+			OutputSizePolicy:     outputSizePolicy,
+			HeartbeatTimeout:     c.Duration("heartbeat-timeout"),
+			QuarantineThreshold:  c.Int("quarantine-threshold"),
+			AvroSchemaRegistry:   c.String("avro-schema-registry"),
+			APIKeys:              apiKeys,
+			JWT:                  jwtConfig,
+			RBACPolicy:           rbacPolicy,
+			Audit:                parseAuditOptions(c),
+			Quotas:               quotas,
+			RateLimit:            parseRateLimitConfig(c),
+			RedactFields:         c.StringSlice("redact-field"),
+			RedactHeaders:        c.StringSlice("redact-header"),
+			OTLP:                 otlpConfig,
+			DebugAddress:         c.String("debug-addr"),
+			DebugAPI:             c.Bool("debug-api"),
+			ExecutorAlarms:       c.IntSlice("executor-saturation-alarm"),
+			MaxGroupParallelism:  c.Int("executor-max-group-parallelism"),
+			DrainTimeout:         c.Duration("drain-timeout"),
+			InvokeTimeout:        c.Duration("invoke-timeout"),
+			Worker:               parseWorkerOptions(c),
+			RemoteWorkers:        parseRemoteWorkerOptions(c),
 		})
 	}
 	cliApp.Run(os.Args)
@@ -78,10 +174,269 @@ func parseFissionOptions(c *cli.Context) *bundle.FissionOptions {
 		return nil
 	}
 
+	// The Fission components share the TLS configuration, as they are usually behind the same proxy.
+	tlsConfig := fnenvhttp.TLSConfig{
+		CAFile:             c.String("fission-tls-ca"),
+		CertFile:           c.String("fission-tls-cert"),
+		KeyFile:            c.String("fission-tls-key"),
+		InsecureSkipVerify: c.Bool("fission-tls-insecure"),
+	}
 	return &bundle.FissionOptions{
 		ExecutorAddress: c.String("fission-executor"),
 		ControllerAddr:  c.String("fission-controller"),
 		RouterAddr:      c.String("fission-router"),
+		WatchFunctions:  c.Bool("fission-watch"),
+		Kubeconfig:      c.String("kubeconfig"),
+		Config: fission.Config{
+			Namespace: c.String("fission-namespace"),
+			Client: fission.ClientConfig{
+				Timeout:             c.Duration("fission-timeout"),
+				MaxIdleConnsPerHost: c.Int("fission-max-idle-conns-per-host"),
+				IdleConnTimeout:     c.Duration("fission-idle-conn-timeout"),
+				KeepAlive:           c.Duration("fission-keep-alive"),
+				TLS:                 tlsConfig,
+			},
+			Retry: fission.RetryConfig{
+				MaxAttempts: c.Int("fission-max-attempts"),
+			},
+			Breaker: fission.BreakerConfig{
+				FailureThreshold: c.Int("fission-breaker-threshold"),
+				OpenDuration:     c.Duration("fission-breaker-open-duration"),
+			},
+			Async: fission.AsyncConfig{
+				Disabled:     c.Bool("fission-no-async"),
+				PollInterval: c.Duration("fission-async-poll-interval"),
+			},
+			Direct: fission.DirectConfig{
+				Enabled:             c.Bool("fission-direct"),
+				AddressTTL:          c.Duration("fission-direct-address-ttl"),
+				HealthCheckInterval: c.Duration("fission-direct-health-check-interval"),
+			},
+			Resolve: fission.ResolveConfig{
+				TTL: c.Duration("fission-resolve-ttl"),
+			},
+			Auth: fission.AuthConfig{
+				Token:         c.String("fission-token"),
+				TokenFile:     c.String("fission-token-file"),
+				ExecutorTLS:   tlsConfig,
+				ControllerTLS: tlsConfig,
+			},
+		},
+	}
+}
+
+func parseHTTPOptions(c *cli.Context) (*fnenvhttp.Config, error) {
+	if !c.Bool("http") {
+		return nil, nil
+	}
+
+	auth := map[string]fnenvhttp.Auth{}
+	for _, s := range c.StringSlice("http-auth") {
+		host, creds, err := fnenvhttp.ParseAuth(s)
+		if err != nil {
+			return nil, err
+		}
+		auth[host] = creds
+	}
+	return &fnenvhttp.Config{
+		DefaultMethod: c.String("http-method"),
+		TLS: fnenvhttp.TLSConfig{
+			CAFile:             c.String("http-tls-ca"),
+			CertFile:           c.String("http-tls-cert"),
+			KeyFile:            c.String("http-tls-key"),
+			InsecureSkipVerify: c.Bool("http-tls-insecure"),
+		},
+		Auth: auth,
+	}, nil
+}
+
+func parseJWTConfig(c *cli.Context) (*auth.JWTConfig, error) {
+	issuer := c.String("oidc-issuer")
+	if len(issuer) == 0 {
+		return nil, nil
+	}
+
+	var defaultScope auth.Scope
+	if name := c.String("oidc-default-scope"); len(name) > 0 {
+		scope, err := auth.ParseScope(name)
+		if err != nil {
+			return nil, err
+		}
+		defaultScope = scope
+	}
+	return &auth.JWTConfig{
+		Issuer:       issuer,
+		Audience:     c.String("oidc-audience"),
+		JWKSURL:      c.String("oidc-jwks-url"),
+		SubjectClaim: c.String("oidc-subject-claim"),
+		GroupsClaim:  c.String("oidc-groups-claim"),
+		ScopeClaim:   c.String("oidc-scope-claim"),
+		DefaultScope: defaultScope,
+	}, nil
+}
+
+func parseSecretsOptions(c *cli.Context) *bundle.SecretsOptions {
+	if !c.Bool("secrets") {
+		return nil
+	}
+
+	opts := &bundle.SecretsOptions{
+		Kubeconfig: c.String("kubeconfig"),
+		Namespace:  c.String("secrets-namespace"),
+	}
+	if addr := c.String("secrets-vault-addr"); len(addr) > 0 {
+		token := c.String("secrets-vault-token")
+		if path := c.String("secrets-vault-token-file"); len(path) > 0 {
+			bs, err := ioutil.ReadFile(path)
+			if err != nil {
+				logrus.Fatalf("Failed to read the Vault token: %v", err)
+			}
+			token = strings.TrimSpace(string(bs))
+		}
+		opts.Vault = &keyref.VaultConfig{
+			Addr:      addr,
+			Token:     token,
+			Mount:     c.String("secrets-vault-mount"),
+			KVVersion: c.Int("secrets-vault-kv-version"),
+		}
+	}
+	return opts
+}
+
+func parseAuditOptions(c *cli.Context) *bundle.AuditOptions {
+	opts := &bundle.AuditOptions{
+		File:       c.String("audit-file"),
+		WebhookURL: c.String("audit-webhook"),
+		EventStore: c.Bool("audit-event-store"),
+	}
+	if !c.Bool("audit") && len(opts.File) == 0 && len(opts.WebhookURL) == 0 && !opts.EventStore {
+		return nil
+	}
+	return opts
+}
+
+func parseRateLimitConfig(c *cli.Context) *ratelimit.Config {
+	config := &ratelimit.Config{
+		Rate:        c.Float64("ratelimit-rate"),
+		Burst:       c.Int("ratelimit-burst"),
+		InvokeRate:  c.Float64("ratelimit-invoke-rate"),
+		InvokeBurst: c.Int("ratelimit-invoke-burst"),
+	}
+	if config.Rate <= 0 && config.InvokeRate <= 0 {
+		return nil
+	}
+	return config
+}
+
+func parseJobOptions(c *cli.Context) *bundle.JobOptions {
+	if !c.Bool("job") {
+		return nil
+	}
+
+	return &bundle.JobOptions{
+		Kubeconfig: c.String("kubeconfig"),
+		Config: job.Config{
+			Namespace:      c.String("job-namespace"),
+			ServiceAccount: c.String("job-service-account"),
+			KeepJobs:       c.Bool("job-keep"),
+		},
+	}
+}
+
+func parseGRPCOptions(c *cli.Context) *fnenvgrpc.Config {
+	if !c.Bool("grpc") {
+		return nil
+	}
+
+	return &fnenvgrpc.Config{
+		TLS:    c.Bool("grpc-tls"),
+		CAFile: c.String("grpc-tls-ca"),
+	}
+}
+
+func parseWorkerOptions(c *cli.Context) *worker.Config {
+	if !c.Bool("worker") {
+		return nil
+	}
+
+	return &worker.Config{
+		MaxParallelism: c.Int("worker-parallelism"),
+	}
+}
+
+func parseRemoteWorkerOptions(c *cli.Context) *bundle.RemoteWorkerOptions {
+	if len(c.StringSlice("remote-worker")) == 0 {
+		return nil
+	}
+
+	return &bundle.RemoteWorkerOptions{
+		Addresses: c.StringSlice("remote-worker"),
+		APIKey:    c.String("remote-worker-api-key"),
+	}
+}
+
+func parseContainerOptions(c *cli.Context) *container.Config {
+	if !c.Bool("container") {
+		return nil
+	}
+
+	return &container.Config{
+		Command: c.String("container-cli"),
+		RunArgs: c.StringSlice("container-run-arg"),
+	}
+}
+
+func parseExpressionLimits(c *cli.Context) expr.Limits {
+	return expr.Limits{
+		Timeout:       c.Duration("expr-timeout"),
+		MaxOutputSize: c.Int("expr-max-output-size"),
+		MaxSteps:      c.Uint64("expr-max-steps"),
+	}
+}
+
+func parseBlobStoreOptions(c *cli.Context) *bundle.BlobStoreOptions {
+	if len(c.String("blobstore")) == 0 {
+		return nil
+	}
+
+	return &bundle.BlobStoreOptions{
+		URL:             c.String("blobstore"),
+		AccessKey:       c.String("blobstore-access-key"),
+		SecretKey:       c.String("blobstore-secret-key"),
+		Threshold:       c.Int("blobstore-threshold"),
+		StreamThreshold: c.Int64("stream-threshold"),
+	}
+}
+
+func parseOTLPConfig(c *cli.Context) (*otlp.Config, error) {
+	endpoint := c.String("otlp-endpoint")
+	if len(endpoint) == 0 {
+		return nil, nil
+	}
+
+	headers := map[string]string{}
+	for _, s := range c.StringSlice("otlp-header") {
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 || len(strings.TrimSpace(parts[0])) == 0 {
+			return nil, fmt.Errorf("invalid OTLP header %q: expected key=value", s)
+		}
+		headers[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return &otlp.Config{
+		Endpoint: endpoint,
+		Headers:  headers,
+		Timeout:  c.Duration("otlp-timeout"),
+	}, nil
+}
+
+func parseEventBufferOptions(c *cli.Context) *buffer.Config {
+	if !c.Bool("nats") || c.Int("event-buffer-size") <= 0 {
+		return nil
+	}
+
+	return &buffer.Config{
+		MaxSize: c.Int("event-buffer-size"),
+		Path:    c.String("event-buffer-path"),
 	}
 }
 
@@ -107,12 +462,98 @@ func createCli() *cli.App {
 
 	cliApp := cli.NewApp()
 
-	cliApp.Flags = append([]cli.Flag{
+	cliApp.Flags = []cli.Flag{
 		// Generic
 		cli.BoolFlag{
 			Name:   "d, debug",
 			EnvVar: "WORKFLOW_DEBUG",
 		},
+		cli.StringFlag{
+			Name:   "debug-addr",
+			Usage:  "Address (e.g. localhost:6060) of a dedicated server for the pprof, goroutine dump and expvar endpoints",
+			EnvVar: "WORKFLOWS_DEBUG_ADDR",
+		},
+		cli.BoolFlag{
+			Name:   "debug-api",
+			Usage:  "Serve the pprof, goroutine dump and expvar endpoints on the HTTP API, requiring the admin scope",
+			EnvVar: "WORKFLOWS_DEBUG_API",
+		},
+		cli.IntSliceFlag{
+			Name: "executor-saturation-alarm",
+			Usage: "Percentage of the capacity of the task queues of the controllers at which a warning is logged " +
+				"(default: 80 and 100; 0 disables the warnings)",
+			EnvVar: "WORKFLOWS_EXECUTOR_SATURATION_ALARMS",
+		},
+		cli.IntFlag{
+			Name:   "executor-max-group-parallelism",
+			Usage:  "Maximum number of tasks of a single invocation that are executed at the same time (0 = unlimited)",
+			EnvVar: "WORKFLOWS_EXECUTOR_MAX_GROUP_PARALLELISM",
+			Value:  bundle.DefaultMaxGroupParallelism,
+		},
+		cli.DurationFlag{
+			Name:   "drain-timeout",
+			Usage:  "Maximum duration to wait for executing tasks to finish on shutdown",
+			EnvVar: "WORKFLOWS_DRAIN_TIMEOUT",
+			Value:  bundle.DefaultDrainTimeout,
+		},
+		cli.DurationFlag{
+			Name:   "invoke-timeout",
+			Usage:  "Deadline of the invocations of the invoke endpoints of the workflows on the HTTP gateway",
+			EnvVar: "WORKFLOWS_INVOKE_TIMEOUT",
+			Value:  bundle.DefaultInvokeTimeout,
+		},
+		cli.BoolFlag{
+			Name:   "worker",
+			Usage:  "Serve the worker API, to execute the tasks of remote invocation controllers with the function runtimes",
+			EnvVar: "WORKFLOWS_WORKER",
+		},
+		cli.IntFlag{
+			Name:   "worker-parallelism",
+			Usage:  "Maximum number of tasks that the worker executes at the same time",
+			EnvVar: "WORKFLOWS_WORKER_PARALLELISM",
+			Value:  worker.DefaultMaxParallelism,
+		},
+		cli.StringSliceFlag{
+			Name:   "remote-worker",
+			Usage:  "Address of a worker to which the invocation controller delegates the invocations of functions",
+			EnvVar: "WORKFLOWS_REMOTE_WORKERS",
+		},
+		cli.StringFlag{
+			Name:   "remote-worker-api-key",
+			Usage:  "API key (with the admin scope) to authenticate with the remote workers",
+			EnvVar: "WORKFLOWS_REMOTE_WORKER_API_KEY",
+		},
+		cli.StringSliceFlag{
+			Name: "redact-field",
+			Usage: "Field to redact from the inputs and outputs in logs and traces, as a path such as inputs.body.apiKey " +
+				"or output.*.token (in addition to inputs.password and inputs.body.password)",
+			EnvVar: "WORKFLOW_REDACT_FIELDS",
+		},
+		cli.StringSliceFlag{
+			Name: "redact-header",
+			Usage: "HTTP header to redact from logs and traces (in addition to Authorization, Proxy-Authorization, " +
+				"Cookie and Set-Cookie)",
+			EnvVar: "WORKFLOW_REDACT_HEADERS",
+		},
+
+		// Tracing
+		cli.StringFlag{
+			Name: "otlp-endpoint",
+			Usage: "URL of an OpenTelemetry collector (such as http://otel-collector:4318) to export traces to with " +
+				"OTLP/HTTP, instead of the Jaeger agent",
+			EnvVar: "OTEL_EXPORTER_OTLP_ENDPOINT",
+		},
+		cli.StringSliceFlag{
+			Name:   "otlp-header",
+			Usage:  "HTTP header to add to the OTLP export requests, as key=value; can be repeated",
+			EnvVar: "OTEL_EXPORTER_OTLP_HEADERS",
+		},
+		cli.DurationFlag{
+			Name:   "otlp-timeout",
+			Usage:  "Timeout of the OTLP export requests",
+			Value:  otlp.DefaultTimeout,
+			EnvVar: "WORKFLOWS_OTLP_TIMEOUT",
+		},
 
 		// NATS
 		cli.StringFlag{
@@ -136,6 +577,17 @@ func createCli() *cli.App {
 			Name:  "nats",
 			Usage: "Use NATS as the event store",
 		},
+		cli.IntFlag{
+			Name:   "event-buffer-size",
+			Usage:  "The maximum number of events that are buffered while the NATS event store is unavailable (0 disables the buffer)",
+			Value:  buffer.DefaultMaxSize,
+			EnvVar: "WORKFLOWS_EVENT_BUFFER_SIZE",
+		},
+		cli.StringFlag{
+			Name:   "event-buffer-path",
+			Usage:  "The file to which the buffered events are written, to replay them after a restart as well",
+			EnvVar: "WORKFLOWS_EVENT_BUFFER_PATH",
+		},
 
 		// Fission Environment Proxy
 		cli.BoolFlag{
@@ -176,6 +628,264 @@ func createCli() *cli.App {
 			Value:  "http://router.fission",
 			EnvVar: "FNENV_FISSION_ROUTER",
 		},
+		cli.StringFlag{
+			Name:   "fission-namespace",
+			Usage:  "Namespace of the Fission functions that are referenced without a namespace",
+			Value:  fission.DefaultConfig.Namespace,
+			EnvVar: "FNENV_FISSION_NAMESPACE",
+		},
+		cli.DurationFlag{
+			Name:   "fission-timeout",
+			Usage:  "Maximum duration of an invocation of a Fission function, including retries (0: bounded by the task deadline)",
+			EnvVar: "FNENV_FISSION_TIMEOUT",
+		},
+		cli.IntFlag{
+			Name:   "fission-max-idle-conns-per-host",
+			Usage:  "Maximum number of idle connections to keep open to the Fission router",
+			Value:  fission.DefaultConfig.Client.MaxIdleConnsPerHost,
+			EnvVar: "FNENV_FISSION_MAX_IDLE_CONNS_PER_HOST",
+		},
+		cli.DurationFlag{
+			Name:   "fission-idle-conn-timeout",
+			Usage:  "Duration after which idle connections to the Fission router are closed",
+			Value:  fission.DefaultConfig.Client.IdleConnTimeout,
+			EnvVar: "FNENV_FISSION_IDLE_CONN_TIMEOUT",
+		},
+		cli.DurationFlag{
+			Name:   "fission-keep-alive",
+			Usage:  "Interval of the TCP keep-alive probes of the connections to the Fission router (negative disables them)",
+			Value:  fission.DefaultConfig.Client.KeepAlive,
+			EnvVar: "FNENV_FISSION_KEEP_ALIVE",
+		},
+		cli.StringFlag{
+			Name:   "fission-tls-ca",
+			Usage:  "Path to PEM-encoded CA certificates to verify the Fission router, executor, and controller with",
+			EnvVar: "FNENV_FISSION_TLS_CA",
+		},
+		cli.StringFlag{
+			Name:   "fission-tls-cert",
+			Usage:  "Path to the PEM-encoded client certificate for mutual TLS with the Fission router, executor, and controller",
+			EnvVar: "FNENV_FISSION_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "fission-tls-key",
+			Usage:  "Path to the PEM-encoded key of the client certificate",
+			EnvVar: "FNENV_FISSION_TLS_KEY",
+		},
+		cli.BoolFlag{
+			Name:   "fission-tls-insecure",
+			Usage:  "Skip the verification of the certificates of the Fission router, executor, and controller",
+			EnvVar: "FNENV_FISSION_TLS_INSECURE",
+		},
+		cli.StringFlag{
+			Name:   "fission-token",
+			Usage:  "Bearer token to authenticate the requests to the Fission router, executor, and controller with",
+			EnvVar: "FNENV_FISSION_TOKEN",
+		},
+		cli.StringFlag{
+			Name:   "fission-token-file",
+			Usage:  "Path to a file with the bearer token for the Fission components, which is read again when it changes",
+			EnvVar: "FNENV_FISSION_TOKEN_FILE",
+		},
+		cli.IntFlag{
+			Name:   "fission-max-attempts",
+			Usage:  "Maximum number of attempts of a request to a Fission function that failed to reach the function",
+			Value:  fission.DefaultConfig.Retry.MaxAttempts,
+			EnvVar: "FNENV_FISSION_MAX_ATTEMPTS",
+		},
+		cli.IntFlag{
+			Name:   "fission-breaker-threshold",
+			Usage:  "Number of consecutive failures of a Fission function after which its circuit breaker opens (0 disables circuit breaking)",
+			EnvVar: "FNENV_FISSION_BREAKER_THRESHOLD",
+		},
+		cli.DurationFlag{
+			Name:   "fission-breaker-open-duration",
+			Usage:  "Duration that the circuit breaker of a Fission function stays open before allowing a trial invocation",
+			Value:  fission.DefaultBreakerOpenDuration,
+			EnvVar: "FNENV_FISSION_BREAKER_OPEN_DURATION",
+		},
+		cli.BoolFlag{
+			Name:   "fission-no-async",
+			Usage:  "Treat 202 Accepted responses of Fission functions as results, instead of polling their status URL",
+			EnvVar: "FNENV_FISSION_NO_ASYNC",
+		},
+		cli.DurationFlag{
+			Name:   "fission-async-poll-interval",
+			Usage:  "Interval at which the status URL of asynchronous Fission function invocations is polled",
+			Value:  fission.DefaultConfig.Async.PollInterval,
+			EnvVar: "FNENV_FISSION_ASYNC_POLL_INTERVAL",
+		},
+		cli.BoolFlag{
+			Name:   "fission-direct",
+			Usage:  "Invoke Fission functions directly at the address of their pods, falling back to the router",
+			EnvVar: "FNENV_FISSION_DIRECT",
+		},
+		cli.DurationFlag{
+			Name:   "fission-direct-address-ttl",
+			Usage:  "Duration for which the pod address of a Fission function is cached",
+			Value:  fission.DefaultConfig.Direct.AddressTTL,
+			EnvVar: "FNENV_FISSION_DIRECT_ADDRESS_TTL",
+		},
+		cli.DurationFlag{
+			Name:   "fission-direct-health-check-interval",
+			Usage:  "Interval after which a cached pod address of a Fission function is checked before it is used",
+			Value:  fission.DefaultConfig.Direct.HealthCheckInterval,
+			EnvVar: "FNENV_FISSION_DIRECT_HEALTH_CHECK_INTERVAL",
+		},
+		cli.DurationFlag{
+			Name:   "fission-resolve-ttl",
+			Usage:  "Duration for which resolved Fission functions are cached (negative to disable)",
+			Value:  fission.DefaultConfig.Resolve.TTL,
+			EnvVar: "FNENV_FISSION_RESOLVE_TTL",
+		},
+		cli.BoolFlag{
+			Name:   "fission-watch",
+			Usage:  "Watch the Fission functions to invalidate cached resolutions and re-resolve workflows of recreated functions",
+			EnvVar: "FNENV_FISSION_WATCH",
+		},
+
+		// HTTP Function Runtime
+		cli.BoolFlag{
+			Name:  "http",
+			Usage: "Use arbitrary HTTP(S) endpoints (http:// and https:// function references) as a function environment",
+		},
+		cli.StringFlag{
+			Name:   "http-method",
+			Usage:  "Default HTTP method of requests to HTTP functions",
+			Value:  http.MethodGet,
+			EnvVar: "FNENV_HTTP_METHOD",
+		},
+		cli.StringFlag{
+			Name:   "http-tls-ca",
+			Usage:  "Path to PEM-encoded CA certificates to verify HTTPS functions with",
+			EnvVar: "FNENV_HTTP_TLS_CA",
+		},
+		cli.StringFlag{
+			Name:   "http-tls-cert",
+			Usage:  "Path to the PEM-encoded client certificate for mutual TLS with HTTPS functions",
+			EnvVar: "FNENV_HTTP_TLS_CERT",
+		},
+		cli.StringFlag{
+			Name:   "http-tls-key",
+			Usage:  "Path to the PEM-encoded key of the client certificate",
+			EnvVar: "FNENV_HTTP_TLS_KEY",
+		},
+		cli.BoolFlag{
+			Name:   "http-tls-insecure",
+			Usage:  "Skip the verification of the certificates of HTTPS functions",
+			EnvVar: "FNENV_HTTP_TLS_INSECURE",
+		},
+		cli.StringSliceFlag{
+			Name:   "http-auth",
+			Usage:  "Credentials for a host of HTTP functions: <host>=basic:<username>:<password> or <host>=bearer:<token>",
+			EnvVar: "FNENV_HTTP_AUTH",
+		},
+
+		// Kubernetes Job Function Runtime
+		cli.BoolFlag{
+			Name:  "job",
+			Usage: "Run tasks with job:// function references as Kubernetes Jobs",
+		},
+		cli.StringFlag{
+			Name:   "kubeconfig",
+			Usage:  "Path to the kubeconfig to connect to Kubernetes with (default: in-cluster configuration)",
+			EnvVar: "KUBECONFIG",
+		},
+		cli.StringFlag{
+			Name:   "job-namespace",
+			Usage:  "Namespace to create jobs in, if the function reference does not specify a namespace",
+			Value:  job.DefaultNamespace,
+			EnvVar: "FNENV_JOB_NAMESPACE",
+		},
+		cli.StringFlag{
+			Name:   "job-service-account",
+			Usage:  "Service account to run the pods of the jobs as",
+			EnvVar: "FNENV_JOB_SERVICE_ACCOUNT",
+		},
+		cli.BoolFlag{
+			Name:  "job-keep",
+			Usage: "Keep the jobs after they have finished, instead of deleting them",
+		},
+
+		// gRPC Function Runtime
+		cli.BoolFlag{
+			Name:  "grpc",
+			Usage: "Invoke functions with grpc://host:port/function references over gRPC",
+		},
+		cli.BoolFlag{
+			Name:   "grpc-tls",
+			Usage:  "Use TLS for the connections to gRPC function servers",
+			EnvVar: "FNENV_GRPC_TLS",
+		},
+		cli.StringFlag{
+			Name:   "grpc-tls-ca",
+			Usage:  "Path to PEM-encoded CA certificates to verify gRPC function servers with (implies --grpc-tls)",
+			EnvVar: "FNENV_GRPC_TLS_CA",
+		},
+
+		// Container Function Runtime
+		cli.BoolFlag{
+			Name:  "container",
+			Usage: "Run tasks with container:// function references as containers",
+		},
+		cli.StringFlag{
+			Name:   "container-cli",
+			Usage:  "Docker-compatible CLI to run the containers with, such as docker or podman",
+			Value:  container.DefaultCommand,
+			EnvVar: "FNENV_CONTAINER_CLI",
+		},
+		cli.StringSliceFlag{
+			Name:   "container-run-arg",
+			Usage:  "Additional argument of the run command of the containers, such as --network=host",
+			EnvVar: "FNENV_CONTAINER_RUN_ARGS",
+		},
+
+		// Secrets
+		cli.BoolFlag{
+			Name:  "secrets",
+			Usage: "Resolve references to Kubernetes secrets and configmaps in task inputs before invoking functions",
+		},
+		cli.StringFlag{
+			Name:   "secrets-namespace",
+			Usage:  "Namespace of the secrets and configmaps of functions that are not scoped to a namespace",
+			Value:  keyref.DefaultNamespace,
+			EnvVar: "SECRETS_NAMESPACE",
+		},
+		cli.StringFlag{
+			Name:   "secrets-vault-addr",
+			Usage:  "Address of the Vault server to read secrets from, instead of the Kubernetes secrets",
+			EnvVar: "VAULT_ADDR",
+		},
+		cli.StringFlag{
+			Name:   "secrets-vault-token",
+			Usage:  "Token used to read secrets from Vault",
+			EnvVar: "VAULT_TOKEN",
+		},
+		cli.StringFlag{
+			Name:  "secrets-vault-token-file",
+			Usage: "File containing the token used to read secrets from Vault (overrides --secrets-vault-token)",
+		},
+		cli.StringFlag{
+			Name:  "secrets-vault-mount",
+			Usage: "Path at which the key/value secrets engine of Vault is mounted",
+			Value: "secret",
+		},
+		cli.IntFlag{
+			Name:  "secrets-vault-kv-version",
+			Usage: "Version (1 or 2) of the key/value secrets engine of Vault",
+			Value: 2,
+		},
+
+		// Kubernetes Operator
+		cli.BoolFlag{
+			Name:  "operator",
+			Usage: "Sync Workflow custom resources into the workflow engine (requires the workflow API)",
+		},
+		cli.StringFlag{
+			Name:   "operator-namespace",
+			Usage:  "Namespace to watch Workflow resources in (default: all namespaces)",
+			EnvVar: "OPERATOR_NAMESPACE",
+		},
 
 		// Components
 		cli.BoolFlag{
@@ -218,6 +928,97 @@ func createCli() *cli.App {
 			Name:  "api",
 			Usage: "Shortcut for serving all APIs over both gRPC and HTTP",
 		},
+		cli.StringFlag{
+			Name:   "api-keys",
+			Usage:  "Path to a YAML file with the API keys (and their read, invoke or admin scope) required to call the APIs",
+			EnvVar: "WORKFLOWS_API_KEYS",
+		},
+		cli.StringFlag{
+			Name:   "rbac-policy",
+			Usage:  "Path to a YAML file with the RBAC rules that grant callers permissions on workflows or namespaces",
+			EnvVar: "WORKFLOWS_RBAC_POLICY",
+		},
+		cli.StringFlag{
+			Name:   "oidc-issuer",
+			Usage:  "Issuer of the JWTs (e.g. OpenID Connect ID tokens) that clients can authenticate with",
+			EnvVar: "WORKFLOWS_OIDC_ISSUER",
+		},
+		cli.StringFlag{
+			Name:   "oidc-audience",
+			Usage:  "Required audience of the JWTs",
+			EnvVar: "WORKFLOWS_OIDC_AUDIENCE",
+		},
+		cli.StringFlag{
+			Name:   "oidc-jwks-url",
+			Usage:  "URL of the keys that sign the JWTs (default: discovered from the OpenID configuration of the issuer)",
+			EnvVar: "WORKFLOWS_OIDC_JWKS_URL",
+		},
+		cli.StringFlag{
+			Name:  "oidc-subject-claim",
+			Value: "sub",
+			Usage: "Claim of the JWTs that identifies the caller",
+		},
+		cli.StringFlag{
+			Name:  "oidc-groups-claim",
+			Value: "groups",
+			Usage: "Claim of the JWTs that lists the groups of the caller",
+		},
+		cli.StringFlag{
+			Name:  "oidc-scope-claim",
+			Usage: "Claim of the JWTs that lists the scopes (read, invoke or admin) of the caller",
+		},
+		cli.StringFlag{
+			Name:  "oidc-default-scope",
+			Usage: "Scope (read, invoke or admin) of callers whose JWT does not specify a scope (default: none)",
+		},
+
+		// Audit log
+		cli.BoolFlag{
+			Name:   "audit",
+			Usage:  "Record the state-changing API calls in the audit log, which is queryable with the admin API",
+			EnvVar: "WORKFLOWS_AUDIT",
+		},
+		cli.StringFlag{
+			Name:   "audit-file",
+			Usage:  "Append the audit events as JSON lines to this file (implies --audit)",
+			EnvVar: "WORKFLOWS_AUDIT_FILE",
+		},
+		cli.StringFlag{
+			Name:   "audit-webhook",
+			Usage:  "Post each audit event as JSON to this URL (implies --audit)",
+			EnvVar: "WORKFLOWS_AUDIT_WEBHOOK",
+		},
+		cli.BoolFlag{
+			Name:  "audit-event-store",
+			Usage: "Persist the audit events in the event store, and query the audit log from it (implies --audit)",
+		},
+
+		// Rate limiting
+		cli.Float64Flag{
+			Name:   "ratelimit-rate",
+			Usage:  "Maximum number of API calls per second of each client (default: unlimited)",
+			EnvVar: "WORKFLOWS_RATELIMIT_RATE",
+		},
+		cli.IntFlag{
+			Name:  "ratelimit-burst",
+			Usage: "Number of API calls that a client can make at once above the rate (default: a second's worth)",
+		},
+		cli.Float64Flag{
+			Name:   "ratelimit-invoke-rate",
+			Usage:  "Maximum number of workflow invocations per second of each client (default: unlimited)",
+			EnvVar: "WORKFLOWS_RATELIMIT_INVOKE_RATE",
+		},
+		cli.IntFlag{
+			Name:  "ratelimit-invoke-burst",
+			Usage: "Number of invocations that a client can make at once above the invoke rate (default: a second's worth)",
+		},
+
+		// Quotas
+		cli.StringFlag{
+			Name:   "quotas",
+			Usage:  "Path to a YAML file with the quotas of the namespaces (default: no quotas)",
+			EnvVar: "WORKFLOWS_QUOTAS",
+		},
 
 		// Scheduler
 		cli.StringFlag{
@@ -230,7 +1031,96 @@ func createCli() *cli.App {
 			Usage: "The static cold start duration to assume when using prewarm schedulers",
 			Value: 1 * time.Second,
 		},
-	})
+
+		// Expressions
+		cli.DurationFlag{
+			Name:  "expr-timeout",
+			Usage: "The maximum duration of the resolution of a single expression",
+			Value: expr.DefaultLimits.Timeout,
+		},
+		cli.IntFlag{
+			Name:  "expr-max-output-size",
+			Usage: "The maximum size (in bytes) of the output of a single expression",
+			Value: expr.DefaultLimits.MaxOutputSize,
+		},
+		cli.Uint64Flag{
+			Name:  "expr-max-steps",
+			Usage: "The maximum number of execution steps of a single expression (only enforced for Starlark)",
+			Value: expr.DefaultLimits.MaxSteps,
+		},
+		cli.StringSliceFlag{
+			Name:  "expr-plugin",
+			Usage: "Path to a Go plugin that exports helper functions for expressions (can be repeated)",
+		},
+
+		// Blob store
+		cli.StringFlag{
+			Name:   "blobstore",
+			Usage:  "URL of the blob store to offload large values to (e.g. mem://, file:///var/lib/workflows or s3://bucket?endpoint=minio:9000&insecure=true)",
+			EnvVar: "BLOBSTORE_URL",
+		},
+		cli.StringFlag{
+			Name:   "blobstore-access-key",
+			Usage:  "Access key of the (S3-compatible) blob store",
+			EnvVar: "BLOBSTORE_ACCESS_KEY",
+		},
+		cli.StringFlag{
+			Name:   "blobstore-secret-key",
+			Usage:  "Secret key of the (S3-compatible) blob store",
+			EnvVar: "BLOBSTORE_SECRET_KEY",
+		},
+		cli.IntFlag{
+			Name:  "blobstore-threshold",
+			Usage: "The size (in bytes) above which values are offloaded to the blob store",
+			Value: blobstore.DefaultThreshold,
+		},
+		cli.Int64Flag{
+			Name:  "stream-threshold",
+			Usage: "The size (in bytes) above which binary bodies are streamed to the blob store instead of kept in memory (0 disables streaming)",
+			Value: httpconv.DefaultStreamThreshold,
+		},
+		cli.Int64Flag{
+			Name:  "max-body-memory-size",
+			Usage: "The size (in bytes) above which bodies of any content type are streamed to the blob store, or rejected without a blob store (0 disables the limit)",
+			Value: httpconv.DefaultMaxMemorySize,
+		},
+		cli.Int64Flag{
+			Name:   "max-payload-size",
+			Usage:  "The maximum size (in bytes) of the inputs of an invocation and of the output of a task, after offloading to the blob store (0 disables the limit)",
+			Value:  fnenv.DefaultMaxPayloadSize,
+			EnvVar: "WORKFLOWS_MAX_PAYLOAD_SIZE",
+		},
+		cli.Int64Flag{
+			Name:   "max-output-size",
+			Usage:  "The maximum size (in bytes) of the output of a task, before offloading to the blob store (0 disables the limit)",
+			EnvVar: "WORKFLOWS_MAX_OUTPUT_SIZE",
+		},
+		cli.StringFlag{
+			Name:   "output-size-policy",
+			Usage:  "How outputs that exceed the max-output-size are handled: reject, truncate or offload (to the blob store)",
+			Value:  string(fnenv.OutputPolicyReject),
+			EnvVar: "WORKFLOWS_OUTPUT_SIZE_POLICY",
+		},
+		cli.DurationFlag{
+			Name:   "heartbeat-timeout",
+			Usage:  "The maximum time between the heartbeats of long-running functions, after which their tasks fail (0 disables the check)",
+			Value:  fnenv.DefaultHeartbeatTimeout,
+			EnvVar: "WORKFLOWS_HEARTBEAT_TIMEOUT",
+		},
+		cli.IntFlag{
+			Name:   "quarantine-threshold",
+			Usage:  "The number of consecutive failed evaluations of an invocation or workflow, after which it is quarantined (0 disables the quarantine)",
+			Value:  ctrl.DefaultQuarantineThreshold,
+			EnvVar: "WORKFLOWS_QUARANTINE_THRESHOLD",
+		},
+
+		// Schemas
+		cli.StringFlag{
+			Name:   "avro-schema-registry",
+			Usage:  "URL of the (Confluent-compatible) schema registry to resolve Avro schemas from",
+			EnvVar: "AVRO_SCHEMA_REGISTRY_URL",
+		},
+	}
 
 	return cliApp
 }
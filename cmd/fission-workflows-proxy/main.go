@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
 	"github.com/fission/fission-workflows/pkg/apiserver/fission"
 	"github.com/fission/fission-workflows/pkg/version"
 	"github.com/golang/protobuf/ptypes/empty"
@@ -42,6 +43,11 @@ func main() {
 			Value: "fission-workflows",
 			Usage: "Address of Fission Workflows to proxy traffic to (do not add a scheme)",
 		},
+		cli.StringFlag{
+			Name:   "api-key",
+			EnvVar: "WORKFLOWS_API_KEY",
+			Usage:  "API key to authenticate with, if Fission Workflows requires API keys (needs the invoke scope)",
+		},
 		cli.IntFlag{
 			Name:  "port, p",
 			Value: 80,
@@ -56,6 +62,10 @@ func main() {
 			Usage: "The default timeout assigned to workflow invocations coming from the Fission proxy",
 			Value: 5 * time.Minute,
 		},
+		cli.StringFlag{
+			Name:  "triggers",
+			Usage: "Path to a YAML file with the input templates of Fission message-queue triggers",
+		},
 	}
 	app.Action = commandContext(func(cliCtx Context) error {
 		// Print version if asked
@@ -83,14 +93,26 @@ func main() {
 
 		// Establish connection with Fission Workflows apiserver
 		target := cliCtx.String("target")
-		conn, err := grpc.DialContext(ctx, target, grpc.WithInsecure())
+		dialOpts := []grpc.DialOption{grpc.WithInsecure()}
+		if key := cliCtx.String("api-key"); len(key) > 0 {
+			dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(auth.Credentials(key)))
+		}
+		conn, err := grpc.DialContext(ctx, target, dialOpts...)
 		if err != nil {
 			logrus.Fatalf("Failed to establish connection to '%s': %v", target, err)
 		}
 		logrus.Infof("Established gRPC connection to '%s'", target)
 
 		// Setup proxy
-		proxy := fission.NewEnvironmentProxyServer(apiserver.NewClient(conn), cliCtx.Duration("timeout"))
+		var triggers []fission.MQTrigger
+		if path := cliCtx.String("triggers"); len(path) > 0 {
+			triggers, err = fission.LoadMQTriggers(path)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			logrus.Infof("Loaded %d message-queue triggers from '%s'", len(triggers), path)
+		}
+		proxy := fission.NewEnvironmentProxyServer(apiserver.NewClient(conn), cliCtx.Duration("timeout"), triggers...)
 
 		// Test proxy
 		if cliCtx.Bool("test") {
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// The backup archive is a gzipped tar containing a JSON document per workflow and per invocation history.
+const (
+	backupWorkflowsDir   = "workflows"
+	backupInvocationsDir = "invocations"
+)
+
+var cmdBackup = cli.Command{
+	Name:  "backup",
+	Usage: "Export and import the workflows and invocations of a workflow engine",
+	Subcommands: []cli.Command{
+		{
+			Name:  "export",
+			Usage: "export <archive.tar.gz>",
+			Description: "Export the specs of all workflows to an archive. With --invocations, the histories (events) " +
+				"of the finished invocations are exported as well.",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "invocations",
+					Usage: "Also export the histories of the finished invocations.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows backup export <archive.tar.gz>")
+				}
+				client := getClient(ctx)
+				fd, err := os.Create(ctx.Args().First())
+				if err != nil {
+					logrus.Fatalf("Failed to create archive: %v", err)
+				}
+				defer fd.Close()
+				gz := gzip.NewWriter(fd)
+				archive := tar.NewWriter(gz)
+
+				wfs, err := client.Workflow.List(ctx, &apiserver.WorkflowListQuery{})
+				if err != nil {
+					logrus.Fatalf("Failed to list workflows: %v", err)
+				}
+				for _, id := range wfs.GetWorkflows() {
+					wf, err := client.Workflow.Get(ctx, id)
+					if err != nil {
+						logrus.Fatalf("Failed to get workflow %s: %v", id, err)
+					}
+					if err := writeBackupEntry(archive, path.Join(backupWorkflowsDir, id+".json"), wf); err != nil {
+						logrus.Fatalf("Failed to write workflow %s: %v", id, err)
+					}
+				}
+				logrus.Infof("Exported %d workflows.", len(wfs.GetWorkflows()))
+
+				if ctx.Bool("invocations") {
+					wis, err := client.Invocation.List(ctx, &apiserver.InvocationListQuery{})
+					if err != nil {
+						logrus.Fatalf("Failed to list invocations: %v", err)
+					}
+					var exported int
+					for _, id := range wis.GetInvocations() {
+						wi, err := client.Invocation.Get(ctx, id)
+						if err != nil {
+							logrus.Fatalf("Failed to get invocation %s: %v", id, err)
+						}
+						// Restoring an active invocation would run its tasks again in the other workflow engine.
+						if !wi.GetStatus().Finished() {
+							logrus.Warnf("Skipping invocation %s: it has not finished yet.", id)
+							continue
+						}
+						events, err := client.Invocation.Events(ctx, id)
+						if err != nil {
+							logrus.Fatalf("Failed to get the events of invocation %s: %v", id, err)
+						}
+						err = writeBackupEntry(archive, path.Join(backupInvocationsDir, id+".json"), events)
+						if err != nil {
+							logrus.Fatalf("Failed to write invocation %s: %v", id, err)
+						}
+						exported++
+					}
+					logrus.Infof("Exported %d invocations.", exported)
+				}
+
+				if err := archive.Close(); err != nil {
+					logrus.Fatalf("Failed to write archive: %v", err)
+				}
+				if err := gz.Close(); err != nil {
+					logrus.Fatalf("Failed to write archive: %v", err)
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "import",
+			Usage: "import <archive.tar.gz>",
+			Description: "Import the workflows and invocation histories of an archive created by 'backup export'. " +
+				"The workflows are created with their original ids, and parsed again by the workflow engine. " +
+				"Workflows and invocations that already exist are skipped.",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows backup import <archive.tar.gz>")
+				}
+				client := getClient(ctx)
+				fd, err := os.Open(ctx.Args().First())
+				if err != nil {
+					logrus.Fatalf("Failed to open archive: %v", err)
+				}
+				defer fd.Close()
+				gz, err := gzip.NewReader(fd)
+				if err != nil {
+					logrus.Fatalf("Failed to read archive: %v", err)
+				}
+				archive := tar.NewReader(gz)
+
+				// The workflows precede the invocations in the archive.
+				var workflows, invocations, failed int
+				for {
+					header, err := archive.Next()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						logrus.Fatalf("Failed to read archive: %v", err)
+					}
+					switch path.Dir(header.Name) {
+					case backupWorkflowsDir:
+						wf := &types.Workflow{}
+						if err := readBackupEntry(archive, wf); err != nil {
+							logrus.Fatalf("Failed to read %s: %v", header.Name, err)
+						}
+						if _, err := client.Workflow.Get(ctx, wf.ID()); err == nil {
+							logrus.Infof("Skipping workflow %s: it already exists.", wf.ID())
+							continue
+						}
+						spec := wf.GetSpec()
+						spec.ForceId = wf.ID()
+						if _, err := client.Workflow.Create(ctx, spec); err != nil {
+							logrus.Errorf("Failed to import workflow %s: %v", wf.ID(), err)
+							failed++
+							continue
+						}
+						workflows++
+					case backupInvocationsDir:
+						events := &apiserver.ObjectEvents{}
+						if err := readBackupEntry(archive, events); err != nil {
+							logrus.Fatalf("Failed to read %s: %v", header.Name, err)
+						}
+						id := events.GetMetadata().GetId()
+						if _, err := client.Invocation.Get(ctx, id); err == nil {
+							logrus.Infof("Skipping invocation %s: it already exists.", id)
+							continue
+						}
+						if err := client.Admin.Restore(ctx, events); err != nil {
+							logrus.Errorf("Failed to import invocation %s: %v", id, err)
+							failed++
+							continue
+						}
+						invocations++
+					default:
+						logrus.Warnf("Skipping unknown entry %s.", header.Name)
+					}
+				}
+				logrus.Infof("Imported %d workflows and %d invocations.", workflows, invocations)
+				if failed > 0 {
+					logrus.Fatalf("Failed to import %d workflows or invocations.", failed)
+				}
+				return nil
+			}),
+		},
+	},
+}
+
+func writeBackupEntry(archive *tar.Writer, name string, msg proto.Message) error {
+	buf := &bytes.Buffer{}
+	if err := (&jsonpb.Marshaler{Indent: "  "}).Marshal(buf, msg); err != nil {
+		return err
+	}
+	err := archive.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(buf.Len()),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = archive.Write(buf.Bytes())
+	return err
+}
+
+func readBackupEntry(archive *tar.Reader, msg proto.Message) error {
+	data, err := ioutil.ReadAll(archive)
+	if err != nil {
+		return err
+	}
+	if err := jsonpb.Unmarshal(bytes.NewReader(data), msg); err != nil {
+		return fmt.Errorf("invalid %s: %v", strings.TrimPrefix(proto.MessageName(msg), "fission.workflows."), err)
+	}
+	return nil
+}
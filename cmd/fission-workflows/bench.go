@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// maxBenchErrors is the maximum number of distinct errors listed in the results of a benchmark.
+const maxBenchErrors = 5
+
+var cmdBench = cli.Command{
+	Name:  "bench",
+	Usage: "bench <workflow-id>",
+	Description: "Invoke a workflow repeatedly for a duration, and report the latency percentiles, throughput and " +
+		"error rate of the invocations. The invocations are synchronous; --concurrency limits the number of " +
+		"invocations in flight, and --rate limits the number of invocations started per second.",
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "duration, d",
+			Usage: "Duration of the benchmark.",
+			Value: 30 * time.Second,
+		},
+		cli.IntFlag{
+			Name:  "concurrency, c",
+			Usage: "Maximum number of invocations in flight.",
+			Value: 10,
+		},
+		cli.Float64Flag{
+			Name:  "rate, r",
+			Usage: "Number of invocations started per second. If 0, invocations are started as fast as the concurrency allows.",
+		},
+		cli.StringFlag{
+			Name:  "inputs",
+			Usage: "Sets the inputs of the invocations to provided value. Expects a JSON object.",
+		},
+		cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label of the invocations, as key=value; can be repeated",
+		},
+		cli.Int64Flag{
+			Name:  "version",
+			Usage: "Version of the workflow to invoke. By default the latest version is invoked.",
+		},
+		cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Timeout of each invocation.",
+			Value: time.Minute,
+		},
+	},
+	Action: commandContext(func(ctx Context) error {
+		if !ctx.Args().Present() {
+			logrus.Fatal("Usage: fission-workflows bench <workflow-id>")
+		}
+		concurrency := ctx.Int("concurrency")
+		if concurrency <= 0 {
+			logrus.Fatal("Concurrency should be larger than 0")
+		}
+		rate := ctx.Float64("rate")
+		if rate < 0 {
+			logrus.Fatal("Rate should not be negative")
+		}
+		client := getClient(ctx)
+		workflowID := ctx.Args().First()
+		inputs := parseInputs(ctx.String("inputs"))
+		labels := parseKeyValues("label", ctx.StringSlice("label"), nil)
+		timeout := ctx.Duration("timeout")
+
+		benchCtx, cancel := context.WithTimeout(context.Background(), ctx.Duration("duration"))
+		defer cancel()
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt)
+		go func() {
+			<-signals
+			logrus.Info("Interrupted; stopping the benchmark")
+			cancel()
+		}()
+
+		// The starts channel paces the invocations: each value is the start of one invocation.
+		starts := make(chan struct{})
+		go func() {
+			defer close(starts)
+			var ticker <-chan time.Time
+			if rate > 0 {
+				t := time.NewTicker(time.Duration(float64(time.Second) / rate))
+				defer t.Stop()
+				ticker = t.C
+			}
+			for {
+				if ticker != nil {
+					select {
+					case <-benchCtx.Done():
+						return
+					case <-ticker:
+					}
+				}
+				select {
+				case <-benchCtx.Done():
+					return
+				case starts <- struct{}{}:
+				}
+			}
+		}()
+
+		fmt.Printf("Benchmarking workflow %s for %v (concurrency: %d, rate: %s)\n", workflowID,
+			ctx.Duration("duration"), concurrency, formatRate(rate))
+		results := &benchResults{}
+		start := time.Now()
+		wg := &sync.WaitGroup{}
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range starts {
+					spec := types.NewWorkflowInvocationSpec(workflowID, time.Now().Add(timeout))
+					spec.WorkflowVersion = ctx.Int64("version")
+					spec.Inputs = inputs
+					spec.Labels = labels
+					// The invocations in flight are allowed to finish after the benchmark duration has passed.
+					invokeCtx, cancelInvoke := context.WithTimeout(context.Background(), timeout)
+					invokeStart := time.Now()
+					wi, err := client.Invocation.InvokeSync(invokeCtx, spec)
+					latency := time.Since(invokeStart)
+					cancelInvoke()
+					results.add(latency, wi, err)
+				}
+			}()
+		}
+		wg.Wait()
+		results.print(os.Stdout, time.Since(start))
+		return nil
+	}),
+}
+
+// benchResults collects the outcomes of the invocations of a benchmark.
+type benchResults struct {
+	lock      sync.Mutex
+	latencies []time.Duration
+	failed    int
+
+	// errors counts the invocations that could not be completed, such as rejected requests, by their error.
+	errors map[string]int
+}
+
+// add records the outcome of an invocation, which either completed as wi or could not be completed due to err.
+func (r *benchResults) add(latency time.Duration, wi *types.WorkflowInvocation, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.latencies = append(r.latencies, latency)
+	if err != nil {
+		if r.errors == nil {
+			r.errors = map[string]int{}
+		}
+		r.errors[err.Error()]++
+		logrus.Debugf("Failed to invoke workflow: %v", err)
+	} else if !wi.GetStatus().Successful() {
+		r.failed++
+		logrus.Debugf("Invocation %s failed: %s", wi.ID(), wi.GetStatus().GetError().GetMessage())
+	}
+}
+
+func (r *benchResults) print(w io.Writer, elapsed time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	total := len(r.latencies)
+	fmt.Fprintln(w)
+	if total == 0 {
+		fmt.Fprintln(w, "No invocations were completed.")
+		return
+	}
+	var errored int
+	var errs []string
+	for msg, count := range r.errors {
+		errored += count
+		errs = append(errs, msg)
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		return r.errors[errs[i]] > r.errors[errs[j]]
+	})
+	succeeded := total - r.failed - errored
+	latencies := make([]time.Duration, total)
+	copy(latencies, r.latencies)
+	sort.Slice(latencies, func(i, j int) bool {
+		return latencies[i] < latencies[j]
+	})
+	var sum time.Duration
+	for _, latency := range latencies {
+		sum += latency
+	}
+
+	table(w, nil, [][]string{
+		{"Invocations", fmt.Sprintf("%d (%d succeeded, %d failed, %d errors)", total, succeeded, r.failed, errored)},
+		{"Error rate", fmt.Sprintf("%.2f%%", 100*float64(total-succeeded)/float64(total))},
+		{"Duration", elapsed.Round(time.Millisecond).String()},
+		{"Throughput", fmt.Sprintf("%.2f invocations/s", float64(total)/elapsed.Seconds())},
+	})
+	fmt.Fprintln(w)
+	table(w, []string{"MIN", "MEAN", "P50", "P90", "P95", "P99", "MAX"}, [][]string{{
+		formatLatency(latencies[0]),
+		formatLatency(sum / time.Duration(total)),
+		formatLatency(percentile(latencies, 50)),
+		formatLatency(percentile(latencies, 90)),
+		formatLatency(percentile(latencies, 95)),
+		formatLatency(percentile(latencies, 99)),
+		formatLatency(latencies[total-1]),
+	}})
+
+	if len(errs) > 0 {
+		fmt.Fprintln(w)
+		var rows [][]string
+		for i, msg := range errs {
+			if i == maxBenchErrors {
+				break
+			}
+			rows = append(rows, []string{strconv.Itoa(r.errors[msg]), msg})
+		}
+		table(w, []string{"COUNT", "ERROR"}, rows)
+	}
+}
+
+// percentile returns the p-th percentile of the sorted latencies, using the nearest-rank method.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(100 * time.Microsecond).String()
+}
+
+func formatRate(rate float64) string {
+	if rate <= 0 {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%g/s", rate)
+}
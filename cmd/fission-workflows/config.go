@@ -2,14 +2,44 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
+	"gopkg.in/yaml.v2"
 )
 
+// defaultConfigPath is the location of the CLI config file, relative to the home directory of the user.
+var defaultConfigPath = filepath.Join(".fission-workflows", "config.yaml")
+
+// cliConfig is the CLI config file, which contains the profiles (contexts) of the workflow engines that the user
+// operates, similar to the contexts of a kubeconfig.
+type cliConfig struct {
+	CurrentContext string                 `yaml:"current-context,omitempty"`
+	Contexts       map[string]*cliContext `yaml:"contexts,omitempty"`
+}
+
+// cliContext contains the settings to connect to a workflow engine. Empty settings fall back to the defaults of the
+// corresponding global flags.
+type cliContext struct {
+	URL        string `yaml:"url,omitempty"`
+	PathPrefix string `yaml:"path-prefix,omitempty"`
+	APIKey     string `yaml:"api-key,omitempty"`
+	Namespace  string `yaml:"namespace,omitempty"`
+	KubeConfig string `yaml:"kubeconfig,omitempty"`
+}
+
 var cmdConfig = cli.Command{
-	Name:   "config",
-	Hidden: true,
-	Usage:  "Print fission-workflows config",
+	Name:  "config",
+	Usage: "Print the fission-workflows config, or manage the contexts of the config file",
+	Description: "The config file (~/.fission-workflows/config.yaml, or --config) contains contexts: named settings " +
+		"to connect to a workflow engine, such as the URL and API key. The current context is used unless another " +
+		"context is selected with --context; global flags and environment variables override the settings of the " +
+		"context.",
 	Action: commandContext(func(ctx Context) error {
 		fmt.Println("cli:")
 		for _, flag := range ctx.GlobalFlagNames() {
@@ -20,4 +50,252 @@ var cmdConfig = cli.Command{
 		}
 		return nil
 	}),
+	Subcommands: []cli.Command{
+		{
+			Name:  "get-contexts",
+			Usage: "get-contexts",
+			Action: commandContext(func(ctx Context) error {
+				cfg := mustLoadConfig(ctx)
+				var rows [][]string
+				for _, name := range contextNames(cfg) {
+					current := ""
+					if name == cfg.CurrentContext {
+						current = "*"
+					}
+					c := cfg.Contexts[name]
+					rows = append(rows, []string{current, name, c.URL, c.PathPrefix, c.Namespace})
+				}
+				table(os.Stdout, []string{"CURRENT", "NAME", "URL", "PATH-PREFIX", "NAMESPACE"}, rows)
+				return nil
+			}),
+		},
+		{
+			Name:  "current-context",
+			Usage: "current-context",
+			Action: commandContext(func(ctx Context) error {
+				cfg := mustLoadConfig(ctx)
+				if len(cfg.CurrentContext) == 0 {
+					logrus.Fatal("The current context is not set.")
+				}
+				fmt.Println(cfg.CurrentContext)
+				return nil
+			}),
+		},
+		{
+			Name:  "use-context",
+			Usage: "use-context <name>",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows config use-context <name>")
+				}
+				name := ctx.Args().First()
+				cfg := mustLoadConfig(ctx)
+				if _, ok := cfg.Contexts[name]; !ok {
+					logrus.Fatalf("Context %s does not exist.", name)
+				}
+				cfg.CurrentContext = name
+				mustSaveConfig(ctx, cfg)
+				fmt.Printf("Switched to context %s.\n", name)
+				return nil
+			}),
+		},
+		{
+			Name:  "set-context",
+			Usage: "set-context <name>",
+			Description: "Create or update a context. Only the provided settings of an existing context are updated. " +
+				"The API key is stored in the config file, which is only readable by the user.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "url",
+					Usage: "URL to the Fission apiserver",
+				},
+				cli.StringFlag{
+					Name:  "path-prefix",
+					Usage: "The path to prepend each of the commands",
+				},
+				cli.StringFlag{
+					Name:  "api-key",
+					Usage: "API key to authenticate with, if the workflow engine requires API keys",
+				},
+				cli.StringFlag{
+					Name:  "namespace",
+					Usage: "Namespace of Fission, to port-forward to if the URL is not set",
+				},
+				cli.StringFlag{
+					Name:  "kubeconfig",
+					Usage: "Path to the kubeconfig to port-forward with if the URL is not set",
+				},
+				cli.BoolFlag{
+					Name:  "use",
+					Usage: "Also make the context the current context.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows config set-context <name>")
+				}
+				name := ctx.Args().First()
+				cfg := mustLoadConfig(ctx)
+				if cfg.Contexts == nil {
+					cfg.Contexts = map[string]*cliContext{}
+				}
+				c, ok := cfg.Contexts[name]
+				if !ok {
+					c = &cliContext{}
+					cfg.Contexts[name] = c
+				}
+				for flag, field := range map[string]*string{
+					"url":         &c.URL,
+					"path-prefix": &c.PathPrefix,
+					"api-key":     &c.APIKey,
+					"namespace":   &c.Namespace,
+					"kubeconfig":  &c.KubeConfig,
+				} {
+					if ctx.IsSet(flag) {
+						*field = ctx.String(flag)
+					}
+				}
+				if ctx.Bool("use") || len(cfg.CurrentContext) == 0 {
+					cfg.CurrentContext = name
+				}
+				mustSaveConfig(ctx, cfg)
+				if ok {
+					fmt.Printf("Updated context %s.\n", name)
+				} else {
+					fmt.Printf("Created context %s.\n", name)
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "delete-context",
+			Usage: "delete-context <name>",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows config delete-context <name>")
+				}
+				name := ctx.Args().First()
+				cfg := mustLoadConfig(ctx)
+				if _, ok := cfg.Contexts[name]; !ok {
+					logrus.Fatalf("Context %s does not exist.", name)
+				}
+				delete(cfg.Contexts, name)
+				if cfg.CurrentContext == name {
+					cfg.CurrentContext = ""
+				}
+				mustSaveConfig(ctx, cfg)
+				fmt.Printf("Deleted context %s.\n", name)
+				return nil
+			}),
+		},
+		{
+			Name:  "view",
+			Usage: "view",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "raw",
+					Usage: "Show the API keys instead of redacting them.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				cfg := mustLoadConfig(ctx)
+				if !ctx.Bool("raw") {
+					for _, c := range cfg.Contexts {
+						if len(c.APIKey) > 0 {
+							c.APIKey = "REDACTED"
+						}
+					}
+				}
+				bs, err := yaml.Marshal(cfg)
+				if err != nil {
+					panic(err)
+				}
+				fmt.Print(string(bs))
+				return nil
+			}),
+		},
+	},
+}
+
+// getConfigPath returns the path of the config file, which is set with the global --config flag.
+func getConfigPath(ctx Context) string {
+	if path := ctx.GlobalString("config"); len(path) > 0 {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		logrus.Fatalf("Failed to find the config file: %v", err)
+	}
+	return filepath.Join(home, defaultConfigPath)
+}
+
+// loadConfig reads the config file at path. A missing config file is treated as an empty config.
+func loadConfig(path string) (*cliConfig, error) {
+	cfg := &cliConfig{}
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	if err := yaml.Unmarshal(bs, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// saveConfig writes the config file. As it may contain API keys, it is only accessible by the user.
+func saveConfig(path string, cfg *cliConfig) error {
+	bs, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, bs, 0600)
+}
+
+func mustLoadConfig(ctx Context) *cliConfig {
+	cfg, err := loadConfig(getConfigPath(ctx))
+	if err != nil {
+		logrus.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+func mustSaveConfig(ctx Context, cfg *cliConfig) {
+	if err := saveConfig(getConfigPath(ctx), cfg); err != nil {
+		logrus.Fatalf("Failed to save config: %v", err)
+	}
+}
+
+// getContext returns the selected context of the config file: the context of the --context flag, or the current
+// context. It returns an empty context if no context is selected.
+func getContext(ctx Context) *cliContext {
+	cfg := mustLoadConfig(ctx)
+	name := ctx.GlobalString("context")
+	if len(name) == 0 {
+		name = cfg.CurrentContext
+		if len(name) == 0 {
+			return &cliContext{}
+		}
+	}
+	c, ok := cfg.Contexts[name]
+	if !ok {
+		logrus.Fatalf("Context %s does not exist; available contexts: %s", name,
+			strings.Join(contextNames(cfg), ", "))
+	}
+	logrus.Debugf("Using context %s.", name)
+	return c
+}
+
+func contextNames(cfg *cliConfig) []string {
+	var names []string
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
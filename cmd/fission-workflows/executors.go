@@ -0,0 +1,75 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var cmdExecutors = cli.Command{
+	Name:  "executors",
+	Usage: "View and resize the task executors of the workflow engine",
+	Action: commandContext(func(ctx Context) error {
+		client := getClient(ctx)
+		resp, err := client.Admin.ListExecutors(ctx)
+		if err != nil {
+			logrus.Fatalf("Failed to list executors: %v", err)
+		}
+		printExecutors(resp.GetExecutors()...)
+		return nil
+	}),
+	Subcommands: []cli.Command{
+		{
+			Name:  "set",
+			Usage: "set <executor> [--parallelism N] [--queue-size N]",
+			Description: "Change the parallelism and the queue size of an executor, without restarting the workflow " +
+				"engine. When the parallelism is decreased, the surplus workers stop after finishing their current task.",
+			Flags: []cli.Flag{
+				cli.IntFlag{
+					Name:  "parallelism",
+					Usage: "The maximum number of tasks that the executor executes at the same time.",
+				},
+				cli.IntFlag{
+					Name:  "queue-size",
+					Usage: "The maximum number of tasks that are queued for execution.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows executors set <executor> [--parallelism N] [--queue-size N]")
+				}
+				if !ctx.IsSet("parallelism") && !ctx.IsSet("queue-size") {
+					logrus.Fatal("Either --parallelism or --queue-size should be provided.")
+				}
+				client := getClient(ctx)
+				resp, err := client.Admin.UpdateExecutor(ctx, &apiserver.ExecutorConfig{
+					Name:        ctx.Args().First(),
+					Parallelism: int32(ctx.Int("parallelism")),
+					QueueSize:   int32(ctx.Int("queue-size")),
+				})
+				if err != nil {
+					logrus.Fatalf("Failed to update executor: %v", err)
+				}
+				printExecutors(resp)
+				return nil
+			}),
+		},
+	},
+}
+
+func printExecutors(executors ...*apiserver.ExecutorConfig) {
+	var rows [][]string
+	for _, ex := range executors {
+		rows = append(rows, []string{
+			ex.GetName(),
+			strconv.Itoa(int(ex.GetParallelism())),
+			strconv.Itoa(int(ex.GetWorkers())),
+			strconv.Itoa(int(ex.GetQueueSize())),
+			strconv.Itoa(int(ex.GetQueued())),
+		})
+	}
+	table(os.Stdout, []string{"NAME", "PARALLELISM", "WORKERS", "QUEUE_SIZE", "QUEUED"}, rows)
+}
@@ -1,16 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/apiserver/httpclient"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
+	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/ptypes"
@@ -32,13 +36,17 @@ var cmdInvocation = cli.Command{
 					Usage: "Amount history (non-active invocations) to show.",
 					Value: time.Duration(1) * time.Hour,
 				},
+				cli.StringFlag{
+					Name:  "selector, l",
+					Usage: "Label selector to filter the listed invocations, such as 'team=payments,env=prod'",
+				},
 			},
 			Action: commandContext(func(ctx Context) error {
 				client := getClient(ctx)
 				switch ctx.NArg() {
 				case 0:
 					since := ctx.Duration("history")
-					invocationsList(os.Stdout, client.Invocation, time.Now().Add(-since))
+					invocationsList(os.Stdout, client.Invocation, time.Now().Add(-since), ctx.String("selector"))
 				case 1:
 					// Get Workflow Invocation
 					wfiID := ctx.Args().Get(0)
@@ -76,14 +84,52 @@ var cmdInvocation = cli.Command{
 			}),
 		},
 		{
-			Name:  "cancel",
-			Usage: "cancel <invocation-id>",
+			Name:    "cancel",
+			Aliases: []string{"abort"},
+			Usage:   "cancel <invocation-id>",
+			Description: "Cancel an invocation. With --cascade, the unfinished child invocations of the invocation, " +
+				"such as nested workflows and dynamic tasks, are canceled as well; the number of affected child " +
+				"invocations is shown for confirmation first.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "reason",
+					Usage: "Human-readable reason of the cancellation, recorded in the status of the invocation.",
+				},
+				cli.BoolFlag{
+					Name:  "cascade",
+					Usage: "Also cancel the child invocations, such as nested workflows and dynamic tasks.",
+				},
+				cli.BoolFlag{
+					Name:  "yes, y",
+					Usage: "Do not ask for confirmation before canceling the child invocations.",
+				},
+			},
 			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation cancel <invocation-id>")
+				}
 				client := getClient(ctx)
 				wfiID := ctx.Args().Get(0)
-				err := client.Invocation.Cancel(ctx, wfiID)
+				if ctx.Bool("cascade") && !ctx.Bool("yes") {
+					children, err := activeChildInvocations(ctx, client.Invocation, wfiID)
+					if err != nil {
+						logrus.Fatalf("Failed to find the child invocations of %s: %v", wfiID, err)
+					}
+					fmt.Printf("Canceling invocation %s also cancels %d child invocations.\n", wfiID, len(children))
+					for _, child := range children {
+						fmt.Printf("  %s (workflow %s)\n", child.ID(), child.GetSpec().GetWorkflowId())
+					}
+					if !confirm(os.Stdin, "Continue?") {
+						return nil
+					}
+				}
+				err := client.Invocation.Cancel(ctx, &apiserver.CancelRequest{
+					Id:      wfiID,
+					Reason:  ctx.String("reason"),
+					Cascade: ctx.Bool("cascade"),
+				})
 				if err != nil {
-					panic(err)
+					logrus.Fatalf("Failed to cancel invocation %s: %v", wfiID, err)
 				}
 				return nil
 			}),
@@ -116,6 +162,112 @@ var cmdInvocation = cli.Command{
 				return nil
 			}),
 		},
+		{
+			Name:  "logs",
+			Usage: "logs <invocation-id> <task-id>",
+			Description: "Print the log output of the function of a task, for the function environments that provide " +
+				"logs, such as Fission. As instances of a function can be shared, the output can contain lines of " +
+				"other invocations of the function during the task.",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "timestamps",
+					Usage: "Prefix each line with its timestamp.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if ctx.NArg() < 2 {
+					logrus.Fatal("Usage: fission-workflows invocation logs <invocation-id> <task-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().Get(0)
+				taskID := ctx.Args().Get(1)
+
+				logs, err := client.Invocation.GetTaskLogs(ctx, wfiID, taskID)
+				if err != nil {
+					logrus.Fatalf("Failed to retrieve logs of task %s of %s: %v", taskID, wfiID, err)
+				}
+				for _, entry := range logs.GetEntries() {
+					if ctx.Bool("timestamps") {
+						ts, _ := ptypes.Timestamp(entry.GetTimestamp())
+						fmt.Printf("%s %s\n", ts.Format(time.RFC3339Nano), entry.GetMessage())
+					} else {
+						fmt.Println(entry.GetMessage())
+					}
+				}
+				return nil
+			}),
+		},
+		{
+			Name:  "explain",
+			Usage: "explain <invocation-id>",
+			Description: "Explain the most recent decision of the scheduler for an invocation: which tasks were " +
+				"runnable, which tasks were blocked and why, and when the prewarmed tasks are expected to start.",
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation explain <invocation-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().First()
+
+				schedule, err := client.Invocation.Explain(ctx, wfiID)
+				if err != nil {
+					logrus.Fatalf("Failed to explain invocation %s: %v", wfiID, err)
+				}
+				summary := [][]string{
+					{"id", wfiID},
+					{"EVALUATED", ptypes.TimestampString(schedule.GetCreatedAt())},
+				}
+				if abort := schedule.GetAbort(); abort != nil {
+					summary = append(summary, []string{"ABORTED", abort.GetReason()})
+				}
+				table(os.Stdout, nil, summary)
+				fmt.Println()
+
+				table(os.Stdout, []string{"TASK", "STATE", "REASON", "EXPECTED_START"}, explainRows(schedule))
+				return nil
+			}),
+		},
+		{
+			Name:  "tail",
+			Usage: "tail <invocation-id>",
+			Description: "Follow the progress of an invocation, printing a line for each change in the status of the " +
+				"invocation or one of its tasks until the invocation has finished. The updates are streamed by the " +
+				"watch API; for servers that do not support it the invocation is polled instead.",
+			Flags: []cli.Flag{
+				cli.BoolFlag{
+					Name:  "poll",
+					Usage: "Poll the invocation instead of watching it.",
+				},
+				cli.DurationFlag{
+					Name:  "interval",
+					Usage: "Interval at which the invocation is polled.",
+					Value: time.Second,
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows invocation tail <invocation-id>")
+				}
+				client := getClient(ctx)
+				wfiID := ctx.Args().First()
+
+				tail := newInvocationTail(os.Stdout)
+				var wi *types.WorkflowInvocation
+				var err error
+				if ctx.Bool("poll") {
+					wi, err = tail.poll(ctx, client, wfiID, ctx.Duration("interval"))
+				} else {
+					wi, err = tail.watch(ctx, client, wfiID)
+				}
+				if err != nil {
+					logrus.Fatalf("Failed to follow invocation %s: %v", wfiID, err)
+				}
+				if !wi.GetStatus().Successful() {
+					os.Exit(1)
+				}
+				return nil
+			}),
+		},
 		{
 			Name:  "status",
 			Usage: "status <Workflow-Invocation-id> ",
@@ -144,6 +296,7 @@ var cmdInvocation = cli.Command{
 					{"CREATED", wfiCreated},
 					{"UPDATED", wfiUpdated},
 					{"STATUS", wfi.Status.Status.String()},
+					{"TRACE_ID", wfi.Status.TraceId},
 				})
 				fmt.Println()
 
@@ -162,10 +315,12 @@ var cmdInvocation = cli.Command{
 	},
 }
 
-func invocationsList(out io.Writer, wfiAPI *httpclient.InvocationAPI, since time.Time) {
+func invocationsList(out io.Writer, wfiAPI *httpclient.InvocationAPI, since time.Time, selector string) {
 	// List workflows invocations
 	ctx := context.TODO()
-	wis, err := wfiAPI.List(ctx)
+	wis, err := wfiAPI.List(ctx, &apiserver.InvocationListQuery{
+		LabelSelector: selector,
+	})
 	if err != nil {
 		panic(err)
 	}
@@ -211,6 +366,81 @@ func invocationsList(out io.Writer, wfiAPI *httpclient.InvocationAPI, since time
 
 }
 
+// explainRows lists the runnable tasks of the schedule, followed by the blocked tasks along with the reason and, if they
+// are prewarmed, the time at which they are expected to start.
+func explainRows(schedule *scheduler.Schedule) [][]string {
+	var rows [][]string
+	for _, action := range schedule.GetRunTasks() {
+		rows = append(rows, []string{action.GetTaskID(), "RUNNABLE", "", ""})
+	}
+	expectedAt := map[string]string{}
+	for _, action := range schedule.GetPrepareTasks() {
+		expectedAt[action.GetTaskID()] = ptypes.TimestampString(action.GetExpectedAt())
+	}
+	for _, blocked := range schedule.GetBlockedTasks() {
+		state := "BLOCKED"
+		if _, ok := expectedAt[blocked.GetTaskID()]; ok {
+			state = "PREWARMED"
+		}
+		rows = append(rows, []string{blocked.GetTaskID(), state, blocked.GetReason(), expectedAt[blocked.GetTaskID()]})
+	}
+	return rows
+}
+
+// activeChildInvocations returns the unfinished invocations that descend from the invocation, which are the
+// invocations that a cascading cancellation of the invocation cancels.
+func activeChildInvocations(ctx context.Context, wfiAPI *httpclient.InvocationAPI,
+	invocationID string) ([]*types.WorkflowInvocation, error) {
+	wis, err := wfiAPI.List(ctx, &apiserver.InvocationListQuery{})
+	if err != nil {
+		return nil, err
+	}
+	children := map[string][]*types.WorkflowInvocation{}
+	for _, wfiID := range wis.GetInvocations() {
+		wi, err := wfiAPI.Get(ctx, wfiID)
+		if err != nil {
+			return nil, err
+		}
+		spec := wi.GetSpec()
+		if len(spec.GetParentId()) > 0 {
+			children[spec.GetParentId()] = append(children[spec.GetParentId()], wi)
+		}
+		if len(spec.GetCallerId()) > 0 && spec.GetCallerId() != spec.GetParentId() {
+			children[spec.GetCallerId()] = append(children[spec.GetCallerId()], wi)
+		}
+	}
+
+	var active []*types.WorkflowInvocation
+	visited := map[string]bool{invocationID: true}
+	queue := []string{invocationID}
+	for len(queue) > 0 {
+		for _, child := range children[queue[0]] {
+			if visited[child.ID()] {
+				continue
+			}
+			visited[child.ID()] = true
+			queue = append(queue, child.ID())
+			if !child.GetStatus().Finished() {
+				active = append(active, child)
+			}
+		}
+		queue = queue[1:]
+	}
+	return active, nil
+}
+
+// confirm asks the user a yes/no question, returning true only if the answer is yes.
+func confirm(in io.Reader, question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
 func collectStatus(tasks map[string]*types.TaskSpec, taskStatus map[string]*types.TaskInvocation,
 	rows [][]string) [][]string {
 	var ids []string
@@ -15,6 +15,7 @@ import (
 	"github.com/blang/semver"
 	"github.com/fatih/color"
 	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
@@ -56,6 +57,18 @@ var cmdInvoke = cli.Command{
 			Name:  "inputs",
 			Usage: "Sets the inputs to provided value. Expects a JSON object.",
 		},
+		cli.StringSliceFlag{
+			Name:  "label",
+			Usage: "Label of the invocation, as key=value; can be repeated",
+		},
+		cli.StringSliceFlag{
+			Name:  "annotation",
+			Usage: "Annotation of the invocation, as key=value; can be repeated",
+		},
+		cli.Int64Flag{
+			Name:  "version",
+			Usage: "Version of the workflow to invoke. By default the latest version is invoked.",
+		},
 		cli.DurationFlag{
 			Name:  "poll",
 			Value: 10 * time.Millisecond,
@@ -81,20 +94,13 @@ var cmdInvoke = cli.Command{
 		workflowID := ctx.Args().First()
 		logrus.Infof("Invoking workflow: %v", workflowID)
 
-		inputs := map[string]*typedvalues.TypedValue{}
-		if jsonInputs := ctx.String("inputs"); len(jsonInputs) > 0 {
-			inputMap := map[string]interface{}{}
-			err := json.Unmarshal([]byte(jsonInputs), &inputMap)
-			if err != nil {
-				logrus.Fatalf("Failed to parse provided inputs to JSON object: %v", err)
-			}
-			inputs = typedvalues.MustWrapMapTypedValue(inputMap)
-		}
-
 		client := getClient(ctx)
 		spec := &types.WorkflowInvocationSpec{
-			WorkflowId: workflowID,
-			Inputs:     inputs,
+			WorkflowId:      workflowID,
+			WorkflowVersion: ctx.Int64("version"),
+			Inputs:          parseInputs(ctx.String("inputs")),
+			Labels:          parseKeyValues("label", ctx.StringSlice("label"), nil),
+			Annotations:     parseKeyValues("annotation", ctx.StringSlice("annotation"), nil),
 		}
 		types.NewWorkflowInvocationSpec(workflowID, time.Now().Add(timeout))
 		md, err := client.Invocation.Invoke(ctx, spec)
@@ -143,7 +149,12 @@ var cmdInvoke = cli.Command{
 		go func() {
 			for sig := range c {
 				logrus.Infof("Received signal: %v - cancelling invocation %s", sig, md.Id)
-				if err := client.Invocation.Cancel(ctx, md.Id); err != nil {
+				err := client.Invocation.Cancel(ctx, &apiserver.CancelRequest{
+					Id:      md.Id,
+					Reason:  fmt.Sprintf("received signal: %v", sig),
+					Cascade: true,
+				})
+				if err != nil {
 					panic(err)
 				}
 			}
@@ -158,13 +169,31 @@ var cmdInvoke = cli.Command{
 		if wi.GetStatus().Successful() {
 			fmt.Println(typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
 		} else {
-			logrus.Error(wi.GetStatus().GetError().GetMessage())
+			if traceID := wi.GetStatus().GetTraceId(); len(traceID) > 0 {
+				logrus.WithField("trace", traceID).Error(wi.GetStatus().GetError().GetMessage())
+			} else {
+				logrus.Error(wi.GetStatus().GetError().GetMessage())
+			}
 			os.Exit(1)
 		}
 		return nil
 	}),
 }
 
+// parseInputs parses the JSON object of the --inputs flag into the inputs of an invocation.
+func parseInputs(jsonInputs string) map[string]*typedvalues.TypedValue {
+	inputs := map[string]*typedvalues.TypedValue{}
+	if len(jsonInputs) > 0 {
+		inputMap := map[string]interface{}{}
+		err := json.Unmarshal([]byte(jsonInputs), &inputMap)
+		if err != nil {
+			logrus.Fatalf("Failed to parse provided inputs to JSON object: %v", err)
+		}
+		inputs = typedvalues.MustWrapMapTypedValue(inputMap)
+	}
+	return inputs
+}
+
 func fetchAndPrintEvents(ctx context.Context, client client, invocationID string, offset int,
 	w io.Writer) (finished bool,
 	err error) {
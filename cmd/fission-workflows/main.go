@@ -27,6 +27,16 @@ func main() {
 	app.Description = app.Usage
 	app.HideVersion = true
 	app.Flags = []cli.Flag{
+		cli.StringFlag{
+			Name:   "config",
+			EnvVar: "WORKFLOWS_CONFIG",
+			Usage:  "Path to the CLI config file (default: ~/.fission-workflows/config.yaml)",
+		},
+		cli.StringFlag{
+			Name:   "context",
+			EnvVar: "WORKFLOWS_CONTEXT",
+			Usage:  "Context of the config file to use instead of the current context",
+		},
 		cli.StringFlag{
 			Name:   "url, u",
 			EnvVar: "FISSION_URL",
@@ -38,6 +48,11 @@ func main() {
 			Value:  "/proxy/workflows-apiserver",
 			Usage:  "The path to prepend each of the commands",
 		},
+		cli.StringFlag{
+			Name:   "api-key",
+			EnvVar: "WORKFLOWS_API_KEY",
+			Usage:  "API key to authenticate with, if the workflow engine requires API keys",
+		},
 		cli.IntFlag{
 			Name:   "verbosity",
 			Value:  1,
@@ -58,6 +73,13 @@ func main() {
 		cmdWorkflow,
 		cmdInvocation,
 		cmdValidate,
+		cmdVisualize,
+		cmdBackup,
+		cmdTop,
+		cmdBench,
+		cmdExecutors,
+		cmdQuarantine,
+		cmdProjections,
 		cmdVersion,
 	}
 	app.Action = func(ctx *cli.Context) error {
@@ -94,6 +116,26 @@ func table(writer io.Writer, headings []string, rows [][]string) {
 	}
 }
 
+// parseKeyValues parses the key=value pairs of a flag, such as --label, into a map. It adds the pairs to the existing
+// map, if it is not nil.
+func parseKeyValues(flag string, kvs []string, existing map[string]string) map[string]string {
+	if len(kvs) == 0 {
+		return existing
+	}
+	result := map[string]string{}
+	for k, v := range existing {
+		result[k] = v
+	}
+	for _, kv := range kvs {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			fail(fmt.Sprintf("Invalid --%s '%s': expected key=value", flag, kv))
+		}
+		result[parts[0]] = parts[1]
+	}
+	return result
+}
+
 func fail(msg ...interface{}) {
 	for _, line := range msg {
 		fmt.Fprintln(os.Stderr, line)
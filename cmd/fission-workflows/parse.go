@@ -19,7 +19,7 @@ var cmdParse = cli.Command{
 		cli.StringFlag{
 			Name:  "type, t",
 			Value: "yaml",
-			Usage: "Indicate which parser plugin to use for the parsing (yaml|pb).",
+			Usage: "Indicate which parser plugin to use for the parsing (yaml|pb|cwl|bpmn).",
 		},
 	},
 	Description: "Read YAML definitions to the executable JSON format (deprecated)",
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
 	"github.com/fission/fission-workflows/pkg/apiserver/httpclient"
 	"github.com/sirupsen/logrus"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,15 +29,25 @@ type client struct {
 }
 
 func getClient(ctx Context) client {
-
+	// The global flags and their environment variables override the settings of the selected context.
+	profile := getContext(ctx)
 	url := ctx.GlobalString("url")
+	if !ctx.GlobalIsSet("url") && len(profile.URL) > 0 {
+		url = profile.URL
+	}
 
 	// fetch the FISSION_URL env variable. If not set, port-forward to controller.
 	if len(url) == 0 {
 		fissionURL := os.Getenv("FISSION_URL")
 		if len(fissionURL) == 0 {
 			fissionNamespace := getFissionNamespace()
-			kubeConfig := getKubeConfigPath()
+			if len(fissionNamespace) == 0 {
+				fissionNamespace = profile.Namespace
+			}
+			kubeConfig := profile.KubeConfig
+			if len(os.Getenv("KUBECONFIG")) > 0 || len(kubeConfig) == 0 {
+				kubeConfig = getKubeConfigPath()
+			}
 			localPort := setupPortForward(kubeConfig, fissionNamespace, "application=fission-api")
 			url = "http://127.0.0.1:" + localPort
 			logrus.Debugf("Forwarded Fission API to %s.", url)
@@ -45,11 +56,21 @@ func getClient(ctx Context) client {
 		}
 	}
 	path := ctx.GlobalString("path-prefix")
-	if path[0] != '/' {
+	if !ctx.GlobalIsSet("path-prefix") && len(profile.PathPrefix) > 0 {
+		path = profile.PathPrefix
+	}
+	if len(path) == 0 || path[0] != '/' {
 		path = "/" + path
 	}
 	url = url + strings.TrimSuffix(path, "/")
 	httpClient := http.Client{}
+	key := ctx.GlobalString("api-key")
+	if !ctx.GlobalIsSet("api-key") {
+		key = profile.APIKey
+	}
+	if len(key) > 0 {
+		httpClient.Transport = &auth.Transport{Key: key}
+	}
 	return client{
 		Admin:      httpclient.NewAdminAPI(url, httpClient),
 		Workflow:   httpclient.NewWorkflowAPI(url, httpClient),
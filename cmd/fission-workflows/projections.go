@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var cmdProjections = cli.Command{
+	Name:  "projections",
+	Usage: "Repair the projections of invocations and workflows by rebuilding them from their events",
+	Subcommands: []cli.Command{
+		{
+			Name:  "rebuild",
+			Usage: "rebuild <id> [--type invocation]",
+			Description: "Discard the cached projection of an invocation or workflow, and rebuild it from its events " +
+				"in the event store.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "type",
+					Usage: "The type of the projection: invocation or workflow.",
+					Value: types.TypeInvocation,
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows projections rebuild <id> [--type invocation]")
+				}
+				client := getClient(ctx)
+				err := client.Admin.RebuildProjection(ctx, &apiserver.ProjectionRef{
+					Type: ctx.String("type"),
+					Id:   ctx.Args().First(),
+				})
+				if err != nil {
+					logrus.Fatalf("Failed to rebuild projection: %v", err)
+				}
+				fmt.Printf("Rebuilt %s %s\n", ctx.String("type"), ctx.Args().First())
+				return nil
+			}),
+		},
+		{
+			Name:  "resync",
+			Usage: "resync [--type invocation]",
+			Description: "Rebuild the projections of all invocations and workflows in the event store, and discard " +
+				"the cached projections that no longer have events. Without a type, all projections are resynced.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "type",
+					Usage: "The type of the projections: invocation or workflow.",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				client := getClient(ctx)
+				resp, err := client.Admin.ResyncProjections(ctx, &apiserver.ResyncProjectionsRequest{
+					Type: ctx.String("type"),
+				})
+				if err != nil {
+					logrus.Fatalf("Failed to resync projections: %v", err)
+				}
+				fmt.Printf("Rebuilt %d and removed %d projection(s)\n", resp.GetRebuilt(), resp.GetRemoved())
+				if len(resp.GetFailed()) > 0 {
+					var rows [][]string
+					for _, failed := range resp.GetFailed() {
+						rows = append(rows, []string{failed.GetRef().GetType(), failed.GetRef().GetId(),
+							failed.GetError()})
+					}
+					table(os.Stdout, []string{"TYPE", "ID", "ERROR"}, rows)
+				}
+				return nil
+			}),
+		},
+	},
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var cmdQuarantine = cli.Command{
+	Name:  "quarantine",
+	Usage: "View and release the invocations and workflows that are quarantined, because their evaluations failed",
+	Action: commandContext(func(ctx Context) error {
+		client := getClient(ctx)
+		resp, err := client.Admin.ListQuarantined(ctx)
+		if err != nil {
+			logrus.Fatalf("Failed to list quarantined controllers: %v", err)
+		}
+		printQuarantined(resp.GetControllers()...)
+		return nil
+	}),
+	Subcommands: []cli.Command{
+		{
+			Name:  "release",
+			Usage: "release <key> [--system invocation]",
+			Description: "Release a quarantined invocation or workflow, after which the workflow engine evaluates it " +
+				"again. If it keeps failing, it is quarantined again.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "system",
+					Usage: "The control system of the controller: invocation or workflow.",
+					Value: "invocation",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows quarantine release <key> [--system invocation]")
+				}
+				client := getClient(ctx)
+				resp, err := client.Admin.ReleaseQuarantined(ctx, &apiserver.QuarantinedControllerRef{
+					System: ctx.String("system"),
+					Key:    ctx.Args().First(),
+				})
+				if err != nil {
+					logrus.Fatalf("Failed to release controller: %v", err)
+				}
+				printQuarantined(resp)
+				return nil
+			}),
+		},
+	},
+}
+
+func printQuarantined(controllers ...*apiserver.QuarantinedController) {
+	var rows [][]string
+	for _, q := range controllers {
+		rows = append(rows, []string{
+			q.GetSystem(),
+			q.GetKey(),
+			ptypes.TimestampString(q.GetSince()),
+			strconv.Itoa(int(q.GetFailures())),
+			q.GetLastError(),
+		})
+	}
+	table(os.Stdout, []string{"SYSTEM", "KEY", "SINCE", "FAILURES", "LAST_ERROR"}, rows)
+}
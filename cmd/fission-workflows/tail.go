@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/sirupsen/logrus"
+)
+
+const tailTimeFormat = "15:04:05.000"
+
+// invocationTail prints the changes in the status of an invocation and its tasks, one line per change.
+type invocationTail struct {
+	out    io.Writer
+	status types.WorkflowInvocationStatus_Status
+	tasks  map[string]types.TaskInvocationStatus_Status
+}
+
+func newInvocationTail(out io.Writer) *invocationTail {
+	return &invocationTail{
+		out:   out,
+		tasks: map[string]types.TaskInvocationStatus_Status{},
+	}
+}
+
+// update prints the changes between the previous state of the invocation and wi.
+func (t *invocationTail) update(wi *types.WorkflowInvocation) {
+	var ids []string
+	for id := range wi.GetStatus().GetTasks() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	tasks := wi.Tasks()
+	for _, id := range ids {
+		ti := wi.GetStatus().GetTasks()[id]
+		status := ti.GetStatus().GetStatus()
+		if prev, ok := t.tasks[id]; ok && prev == status {
+			continue
+		}
+		t.tasks[id] = status
+		line := fmt.Sprintf("%s  task        %-24s %s %s", tailTimestamp(ti.GetStatus().GetUpdatedAt()), id,
+			colorStatus(fmt.Sprintf("%-11s", status.String())), tasks[id].GetSpec().GetFunctionRef())
+		if msg := ti.GetStatus().GetError().GetMessage(); len(msg) > 0 {
+			line += ": " + msg
+		}
+		fmt.Fprintln(t.out, line)
+	}
+
+	status := wi.GetStatus().GetStatus()
+	if status != t.status {
+		t.status = status
+		line := fmt.Sprintf("%s  invocation  %-24s %s", tailTimestamp(wi.GetStatus().GetUpdatedAt()), wi.ID(),
+			colorStatus(status.String()))
+		if msg := wi.GetStatus().GetError().GetMessage(); len(msg) > 0 {
+			line += ": " + msg
+		}
+		fmt.Fprintln(t.out, line)
+	}
+}
+
+// watch prints the changes of the invocation as they are streamed by the server, until the invocation has
+// finished. It returns the last state of the invocation.
+func (t *invocationTail) watch(ctx context.Context, client client, id string) (*types.WorkflowInvocation, error) {
+	var last *types.WorkflowInvocation
+	err := client.Invocation.Watch(ctx, &apiserver.InvocationWatchQuery{Ids: []string{id}},
+		func(update *apiserver.InvocationUpdate) error {
+			last = update.GetInvocation()
+			t.update(last)
+			return nil
+		})
+	if err == nil && !last.GetStatus().Finished() {
+		err = fmt.Errorf("watch of invocation %s ended before it finished", id)
+	}
+	if err != nil && ctx.Err() == nil {
+		// Older servers do not support watches, and the connection of the watch may be cut by proxies.
+		logrus.Warnf("Falling back to polling the invocation: %v", err)
+		return t.poll(ctx, client, id, time.Second)
+	}
+	return last, err
+}
+
+// poll prints the changes of the invocation by fetching it at the interval, until the invocation has finished.
+func (t *invocationTail) poll(ctx context.Context, client client, id string,
+	interval time.Duration) (*types.WorkflowInvocation, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		wi, err := client.Invocation.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		t.update(wi)
+		if wi.GetStatus().Finished() {
+			return wi, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func tailTimestamp(ts *timestamp.Timestamp) string {
+	if t, err := ptypes.Timestamp(ts); err == nil {
+		return t.Local().Format(tailTimeFormat)
+	}
+	return time.Now().Format(tailTimeFormat)
+}
+
+// colorStatus colors the name of a task or invocation status, which may be padded.
+func colorStatus(status string) string {
+	switch strings.TrimSpace(status) {
+	case types.TaskInvocationStatus_SUCCEEDED.String():
+		return color.HiGreenString(status)
+	case types.TaskInvocationStatus_FAILED.String():
+		return color.HiRedString(status)
+	case types.TaskInvocationStatus_IN_PROGRESS.String():
+		return color.HiYellowString(status)
+	case types.TaskInvocationStatus_SCHEDULED.String():
+		return color.HiCyanString(status)
+	default:
+		return color.HiBlackString(status)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+// clearScreen moves the cursor to the top-left corner and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+var cmdTop = cli.Command{
+	Name:  "top",
+	Usage: "top",
+	Description: "Show a dashboard of the active invocations and their tasks, with the number of invocations and " +
+		"tasks per status, the depth of the task queues and the number of rejected requests. The dashboard is " +
+		"refreshed until it is interrupted.",
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "interval",
+			Usage: "Interval at which the dashboard is refreshed.",
+			Value: 2 * time.Second,
+		},
+		cli.IntFlag{
+			Name:  "max",
+			Usage: "Maximum number of active invocations to show.",
+			Value: 20,
+		},
+		cli.BoolFlag{
+			Name:  "once",
+			Usage: "Print the dashboard once, without clearing the terminal.",
+		},
+	},
+	Action: commandContext(func(ctx Context) error {
+		d := &dashboard{
+			client:   getClient(ctx),
+			max:      ctx.Int("max"),
+			finished: map[string]*types.WorkflowInvocation{},
+		}
+		interval := ctx.Duration("interval")
+		for {
+			snapshot, err := d.refresh(ctx)
+			if err != nil {
+				logrus.Fatalf("Failed to refresh the dashboard: %v", err)
+			}
+			buf := &bytes.Buffer{}
+			if !ctx.Bool("once") {
+				buf.WriteString(clearScreen)
+			}
+			snapshot.render(buf, interval)
+			if _, err := io.Copy(os.Stdout, buf); err != nil {
+				panic(err)
+			}
+			if ctx.Bool("once") {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(interval):
+			}
+		}
+	}),
+}
+
+// dashboard collects the state of the workflow engine for the top command.
+type dashboard struct {
+	client client
+	max    int
+
+	// finished caches the invocations that have finished, which do not change anymore.
+	finished map[string]*types.WorkflowInvocation
+}
+
+type dashboardSnapshot struct {
+	time        time.Time
+	active      []*types.WorkflowInvocation
+	invocations map[types.WorkflowInvocationStatus_Status]int
+	tasks       map[types.TaskInvocationStatus_Status]int
+
+	// metrics are the values of the metrics of the workflow engine, or nil if they are not available.
+	metrics map[string]float64
+}
+
+func (d *dashboard) refresh(ctx context.Context) (*dashboardSnapshot, error) {
+	list, err := d.client.Invocation.List(ctx, &apiserver.InvocationListQuery{})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &dashboardSnapshot{
+		time:        time.Now(),
+		invocations: map[types.WorkflowInvocationStatus_Status]int{},
+		tasks:       map[types.TaskInvocationStatus_Status]int{},
+	}
+	for _, id := range list.GetInvocations() {
+		wi, ok := d.finished[id]
+		if !ok {
+			wi, err = d.client.Invocation.Get(ctx, id)
+			if err != nil {
+				logrus.Debugf("Failed to get invocation %s: %v", id, err)
+				continue
+			}
+			if wi.GetStatus().Finished() {
+				d.finished[id] = wi
+			}
+		}
+		snapshot.invocations[wi.GetStatus().GetStatus()]++
+		for _, ti := range wi.GetStatus().GetTasks() {
+			snapshot.tasks[ti.GetStatus().GetStatus()]++
+		}
+		// Nested invocations are shown as tasks of their parent.
+		if !wi.GetStatus().Finished() && len(wi.GetSpec().GetParentId()) == 0 {
+			snapshot.active = append(snapshot.active, wi)
+		}
+	}
+	sort.Slice(snapshot.active, func(i, j int) bool {
+		return util.CmpProtoTimestamps(snapshot.active[i].GetMetadata().GetCreatedAt(),
+			snapshot.active[j].GetMetadata().GetCreatedAt())
+	})
+	if d.max > 0 && len(snapshot.active) > d.max {
+		snapshot.active = snapshot.active[:d.max]
+	}
+
+	// The metrics endpoint is optional; the dashboard works without it.
+	if data, err := d.client.Admin.Metrics(ctx); err == nil {
+		snapshot.metrics = parseMetrics(data)
+	} else {
+		logrus.Debugf("Failed to fetch metrics: %v", err)
+	}
+	return snapshot, nil
+}
+
+func (s *dashboardSnapshot) render(w io.Writer, interval time.Duration) {
+	fmt.Fprintf(w, "fission-workflows top - %s, refreshed every %v\n\n", s.time.Format("15:04:05"), interval)
+	fmt.Fprintf(w, "Invocations: %d active, %d succeeded, %d failed, %d aborted\n",
+		s.invocations[types.WorkflowInvocationStatus_SCHEDULED]+s.invocations[types.WorkflowInvocationStatus_IN_PROGRESS],
+		s.invocations[types.WorkflowInvocationStatus_SUCCEEDED], s.invocations[types.WorkflowInvocationStatus_FAILED],
+		s.invocations[types.WorkflowInvocationStatus_ABORTED])
+	fmt.Fprintf(w, "Tasks:       %d scheduled, %d in progress, %d succeeded, %d failed, %d skipped\n",
+		s.tasks[types.TaskInvocationStatus_SCHEDULED], s.tasks[types.TaskInvocationStatus_IN_PROGRESS],
+		s.tasks[types.TaskInvocationStatus_SUCCEEDED], s.tasks[types.TaskInvocationStatus_FAILED],
+		s.tasks[types.TaskInvocationStatus_SKIPPED])
+	if s.metrics != nil {
+		fmt.Fprintf(w, "Queues:      %.0f queued tasks, %.0f active functions\n",
+			s.metrics["workflows_executor_queued_tasks"], s.metrics["workflows_fnenv_functions_active"])
+		fmt.Fprintf(w, "Rejected:    %.0f by quotas, %.0f by rate limits, %.0f payloads too large\n",
+			s.metrics["workflows_quota_exceeded_total"], s.metrics["workflows_apiserver_ratelimit_rejected_total"],
+			s.metrics["workflows_fnenv_payload_rejected_total"])
+	} else {
+		fmt.Fprintln(w, "Queues:      metrics are not available")
+	}
+	fmt.Fprintln(w)
+
+	var rows [][]string
+	for _, wi := range s.active {
+		rows = append(rows, []string{wi.ID(), wi.GetSpec().GetWorkflowId(),
+			colorStatus(wi.GetStatus().GetStatus().String()), age(s.time, wi.GetMetadata().GetCreatedAt())})
+		tasks := wi.Tasks()
+		var ids []string
+		for id, ti := range wi.GetStatus().GetTasks() {
+			if !ti.GetStatus().Finished() {
+				ids = append(ids, id)
+			}
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			ti := wi.GetStatus().GetTasks()[id]
+			rows = append(rows, []string{"  " + id, tasks[id].GetSpec().GetFunctionRef(),
+				colorStatus(ti.GetStatus().GetStatus().String()), age(s.time, ti.GetMetadata().GetCreatedAt())})
+		}
+	}
+	table(w, []string{"INVOCATION/TASK", "WORKFLOW/FUNCTION", "STATUS", "AGE"}, rows)
+}
+
+func age(now time.Time, ts *timestamp.Timestamp) string {
+	t, err := ptypes.Timestamp(ts)
+	if err != nil {
+		return ""
+	}
+	return now.Sub(t).Round(time.Second).String()
+}
+
+// parseMetrics parses metrics in the Prometheus text format into the sum of the values of each metric.
+func parseMetrics(data []byte) map[string]float64 {
+	metrics := map[string]float64{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// A sample is the name, the optional labels, the value and an optional timestamp.
+		name, rest := line, ""
+		if i := strings.IndexAny(line, "{ "); i >= 0 {
+			name, rest = line[:i], line[i:]
+		}
+		if i := strings.LastIndex(rest, "}"); i >= 0 {
+			rest = rest[i+1:]
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			continue
+		}
+		metrics[name] += value
+	}
+	return metrics
+}
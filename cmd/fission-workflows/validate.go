@@ -1,27 +1,49 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/parse/bpmn"
+	"github.com/fission/fission-workflows/pkg/parse/cwl"
 	"github.com/fission/fission-workflows/pkg/parse/protobuf"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
 	"github.com/fission/fission-workflows/pkg/types"
-	"github.com/golang/protobuf/jsonpb"
-
 	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/urfave/cli"
 )
 
 var cmdValidate = cli.Command{
-	Name:        "validate",
-	Usage:       "Validate [file ...]",
-	Description: "Validate a workflow definition",
+	Name:  "validate",
+	Usage: "Validate [file ...]",
+	Description: "Validate a workflow definition offline. Besides the structure of the workflow, such as unknown " +
+		"fields and circular dependencies, the syntax of the expressions in the tasks is checked. With --resolve, " +
+		"the workflow engine also resolves the functions of the tasks of valid definitions. With --remote, the " +
+		"workflow engine validates the workflow instead.",
 	Flags: []cli.Flag{
 		cli.StringFlag{
 			Name:  "type, t",
 			Value: "yaml",
-			Usage: "encoding of the file(s) [yaml|proto|json]",
+			Usage: "encoding of the file(s) [yaml|proto|json|cwl|bpmn]",
+		},
+		cli.BoolFlag{
+			Name:  "remote",
+			Usage: "Validate the workflow definition(s) with the workflow engine",
+		},
+		cli.BoolFlag{
+			Name:  "resolve",
+			Usage: "Resolve the functions of valid workflow definition(s) with the workflow engine",
+		},
+		cli.StringFlag{
+			Name:  "output, o",
+			Value: "text",
+			Usage: "format of the diagnostics [text|json]",
 		},
 	},
 	Action: commandContext(func(ctx Context) error {
@@ -30,13 +52,72 @@ var cmdValidate = cli.Command{
 			fail("No file provided.")
 		}
 
+		var validator func(spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult, error)
+		workflowAPI := api.NewWorkflowAPI(nil, nil)
+		localValidator := func(spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult, error) {
+			return apiserver.NewWorkflowValidationResult(workflowAPI.Validate(spec)), nil
+		}
+		switch {
+		case ctx.Bool("remote"):
+			client := getClient(ctx)
+			validator = func(spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult, error) {
+				return client.Workflow.Validate(ctx, spec)
+			}
+		case ctx.Bool("resolve"):
+			client := getClient(ctx)
+			validator = func(spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult, error) {
+				result, err := localValidator(spec)
+				if err != nil || !result.GetValid() {
+					return result, err
+				}
+				// Only the resolution of the functions of a valid workflow needs the workflow engine.
+				return client.Workflow.Validate(ctx, spec)
+			}
+		default:
+			validator = localValidator
+		}
+
 		var failed bool
+		results := map[string]json.RawMessage{}
 		for _, path := range ctx.Args() {
-			if err := validateWorkflowDefinition(path, ctx.String("type")); err != nil {
+			result, err := validateWorkflowDefinition(path, ctx.String("type"), validator)
+			if err != nil {
 				if _, err := fmt.Fprintf(os.Stderr, "%s: %s\n", path, err.Error()); err != nil {
 					panic(err)
 				}
 				failed = true
+				continue
+			}
+			if !result.GetValid() {
+				failed = true
+			}
+
+			switch ctx.String("output") {
+			case "json":
+				buf := &bytes.Buffer{}
+				if err := (&jsonpb.Marshaler{}).Marshal(buf, result); err != nil {
+					panic(err)
+				}
+				results[path] = buf.Bytes()
+			default:
+				for _, d := range result.GetDiagnostics() {
+					location := path
+					if len(d.GetField()) > 0 {
+						location += ": " + d.GetField()
+					}
+					if _, err := fmt.Fprintf(os.Stderr, "%s: %s\n", location, d.GetMessage()); err != nil {
+						panic(err)
+					}
+				}
+			}
+		}
+
+		if ctx.String("output") == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetEscapeHTML(false)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(results); err != nil {
+				panic(err)
 			}
 		}
 
@@ -48,45 +129,62 @@ var cmdValidate = cli.Command{
 	}),
 }
 
-func validateWorkflowDefinition(path string, fType string) error {
+func validateWorkflowDefinition(path string, fType string,
+	validator func(spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult, error)) (
+	*apiserver.WorkflowValidationResult, error) {
 	// Get file
-	file, err := os.Open(path)
-	defer file.Close()
+	data, err := ioutil.ReadFile(path)
 	if err != nil {
-		return fmt.Errorf("failed to read file: %v", err)
+		return nil, fmt.Errorf("failed to read file: %v", err)
 	}
 
-	// Read file into workflowSpec (assume yaml for now)
+	// Read file into workflowSpec
 	var spec *types.WorkflowSpec
+	var fieldErr error
 	switch fType {
 	case "yaml":
-		spec, err = yaml.Parse(file)
+		fieldErr = yaml.CheckFields(data)
+		if _, ok := fieldErr.(validate.Error); fieldErr != nil && !ok {
+			return nil, fmt.Errorf("failed to parse yaml definition: %v", fieldErr)
+		}
+		spec, err = yaml.Parse(bytes.NewReader(data))
 		if err != nil {
-			return fmt.Errorf("failed to parse yaml definition: %v", err)
+			return nil, fmt.Errorf("failed to parse yaml definition: %v", err)
 		}
 	case "proto":
-		spec, err = protobuf.Parse(file)
+		spec, err = protobuf.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse protobuf definition: %v", err)
+		}
+	case "cwl":
+		spec, err = cwl.Parse(bytes.NewReader(data))
 		if err != nil {
-			return fmt.Errorf("failed to parse protobuf definition: %v", err)
+			return nil, fmt.Errorf("failed to parse CWL definition: %v", err)
+		}
+	case "bpmn":
+		spec, err = bpmn.Parse(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse BPMN definition: %v", err)
 		}
 	case "json":
-		err := jsonpb.Unmarshal(file, spec)
+		spec = &types.WorkflowSpec{}
+		err := jsonpb.Unmarshal(bytes.NewReader(data), spec)
 		if err != nil {
-			return fmt.Errorf("failed to parse json definition: %v", err)
+			return nil, fmt.Errorf("failed to parse json definition: %v", err)
 		}
 	default:
-		return fmt.Errorf("unsupported workflow definition format: %v", fType)
+		return nil, fmt.Errorf("unsupported workflow definition format: %v", fType)
 	}
 
 	// Validate workflowSpec
-	err = validate.WorkflowSpec(spec)
+	result, err := validator(spec)
 	if err != nil {
-		invalid, ok := err.(validate.Error)
-		if ok {
-			return fmt.Errorf(validate.Format(invalid))
-		} else {
-			return fmt.Errorf("unknown error: %v", err)
-		}
+		return nil, fmt.Errorf("failed to validate workflow definition: %v", err)
+	}
+	if fieldErr != nil {
+		fieldResult := apiserver.NewWorkflowValidationResult(fieldErr)
+		result.Valid = false
+		result.Diagnostics = append(fieldResult.Diagnostics, result.Diagnostics...)
 	}
-	return nil
+	return result, nil
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/parse"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli"
+)
+
+var cmdVisualize = cli.Command{
+	Name:  "visualize",
+	Usage: "visualize <workflow-id>",
+	Description: "Render the task graph of a workflow in Graphviz DOT or Mermaid format. With --invocation, the graph " +
+		"of the invocation is rendered, including its dynamic tasks and the status of its tasks. With --src, a " +
+		"local workflow definition is rendered without contacting the workflow engine.\n\n" +
+		"   For example: fission-workflows visualize <workflow-id> | dot -Tpng > workflow.png",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "format, f",
+			Value: graph.FormatDOT,
+			Usage: "format of the graph [dot|mermaid]",
+		},
+		cli.StringFlag{
+			Name:  "invocation, i",
+			Usage: "id of an invocation of the workflow to render",
+		},
+		cli.StringFlag{
+			Name:  "src",
+			Usage: "path to a local YAML, CWL, BPMN or Protobuf workflow definition file to render",
+		},
+	},
+	Action: commandContext(func(ctx Context) error {
+		format := ctx.String("format")
+		if srcPath := ctx.String("src"); len(srcPath) > 0 {
+			fd, err := os.Open(srcPath)
+			if err != nil {
+				logrus.Fatalf("Failed to open workflow definition file: %v", err)
+			}
+			spec, err := parse.Parse(fd)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			wf := &types.Workflow{
+				Metadata: &types.ObjectMetadata{Id: srcPath},
+				Spec:     spec,
+			}
+			if err := graph.NewWorkflowVisualization(wf).Render(os.Stdout, format); err != nil {
+				logrus.Fatal(err)
+			}
+			return nil
+		}
+
+		if !ctx.Args().Present() && len(ctx.String("invocation")) == 0 {
+			logrus.Fatal("Usage: fission-workflows visualize <workflow-id>")
+		}
+		client := getClient(ctx)
+		workflowID := ctx.Args().First()
+		invocationID := ctx.String("invocation")
+		if len(workflowID) == 0 {
+			wi, err := client.Invocation.Get(ctx, invocationID)
+			if err != nil {
+				logrus.Fatalf("Failed to get invocation %s: %v", invocationID, err)
+			}
+			workflowID = wi.GetSpec().GetWorkflowId()
+		}
+		v, err := client.Workflow.Visualize(ctx, &apiserver.VisualizeRequest{
+			WorkflowId:   workflowID,
+			InvocationId: invocationID,
+			Format:       format,
+		})
+		if err != nil {
+			logrus.Fatalf("Failed to visualize workflow %s: %v", workflowID, err)
+		}
+		fmt.Print(v.GetGraph())
+		return nil
+	}),
+}
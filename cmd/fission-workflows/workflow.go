@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
 	"github.com/blang/semver"
+	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/parse"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
 	"github.com/golang/protobuf/jsonpb"
@@ -25,12 +27,20 @@ var cmdWorkflow = cli.Command{
 			Flags: []cli.Flag{
 				cli.StringFlag{
 					Name:  "src",
-					Usage: "Path to the YAML or Protobuf workflow definition file",
+					Usage: "Path to the YAML, CWL, BPMN or Protobuf workflow definition file",
 				},
 				cli.StringFlag{
 					Name:  "name",
 					Usage: "Name of the workflow",
 				},
+				cli.StringSliceFlag{
+					Name:  "label",
+					Usage: "Label of the workflow, as key=value; can be repeated",
+				},
+				cli.StringSliceFlag{
+					Name:  "annotation",
+					Usage: "Annotation of the workflow, as key=value; can be repeated",
+				},
 			},
 			Action: commandContext(func(ctx Context) error {
 				client := getClient(ctx)
@@ -49,6 +59,8 @@ var cmdWorkflow = cli.Command{
 					logrus.Fatal(err)
 				}
 				spec.Name = ctx.String("name")
+				spec.Labels = parseKeyValues("label", ctx.StringSlice("label"), spec.Labels)
+				spec.Annotations = parseKeyValues("annotation", ctx.StringSlice("annotation"), spec.Annotations)
 
 				// Create workflow
 				md, err := client.Workflow.CreateSync(ctx, spec)
@@ -60,6 +72,88 @@ var cmdWorkflow = cli.Command{
 				return nil
 			}),
 		},
+		{
+			Name:  "update",
+			Usage: "update <workflow-id>",
+			Description: "Update a workflow, which creates a new version of the workflow. By default, invocations that " +
+				"are running continue with the version that they were started with.",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "src",
+					Usage: "Path to the YAML, CWL, BPMN or Protobuf workflow definition file",
+				},
+				cli.StringFlag{
+					Name:  "name",
+					Usage: "Name of the workflow",
+				},
+				cli.StringSliceFlag{
+					Name:  "label",
+					Usage: "Label of the new version of the workflow, as key=value; can be repeated",
+				},
+				cli.StringSliceFlag{
+					Name:  "annotation",
+					Usage: "Annotation of the new version of the workflow, as key=value; can be repeated",
+				},
+				cli.StringFlag{
+					Name:  "patch",
+					Usage: "Path to a JSON workflow patch to apply instead of a workflow definition file",
+				},
+				cli.StringFlag{
+					Name:  "policy",
+					Usage: "What to do with the running invocations: continue, fail, or migrate",
+					Value: "continue",
+				},
+			},
+			Action: commandContext(func(ctx Context) error {
+				if !ctx.Args().Present() {
+					logrus.Fatal("Usage: fission-workflows workflow update <workflow-id> (--src <file> | --patch <file>)")
+				}
+				client := getClient(ctx)
+				req := &apiserver.WorkflowUpdateRequest{
+					Id: ctx.Args().First(),
+				}
+				policy, ok := apiserver.RunningInvocationPolicy_value[strings.ToUpper(ctx.String("policy"))]
+				if !ok {
+					logrus.Fatalf("Unknown policy for running invocations: %s", ctx.String("policy"))
+				}
+				req.Policy = apiserver.RunningInvocationPolicy(policy)
+
+				if patchPath := ctx.String("patch"); len(patchPath) > 0 {
+					fd, err := os.Open(patchPath)
+					if err != nil {
+						logrus.Fatalf("Failed to open workflow patch file: %v", err)
+					}
+					req.Patch = &apiserver.WorkflowPatch{}
+					if err := jsonpb.Unmarshal(fd, req.Patch); err != nil {
+						logrus.Fatalf("Failed to parse workflow patch: %v", err)
+					}
+				} else {
+					// Fetch and parse the workflow
+					srcPath := ctx.String("src")
+					if len(srcPath) == 0 {
+						logrus.Fatalf("Requires workflow definition file or patch. Use `--src <file>` or `--patch <file>`.")
+					}
+					fd, err := os.Open(srcPath)
+					if err != nil {
+						logrus.Fatalf("Failed to open workflow definition file: %v", err)
+					}
+					spec, err := parse.Parse(fd)
+					if err != nil {
+						logrus.Fatal(err)
+					}
+					spec.Name = ctx.String("name")
+					spec.Labels = parseKeyValues("label", ctx.StringSlice("label"), spec.Labels)
+					spec.Annotations = parseKeyValues("annotation", ctx.StringSlice("annotation"), spec.Annotations)
+					req.Spec = spec
+				}
+
+				if err := client.Workflow.Update(ctx, req); err != nil {
+					logrus.Fatalf("Failed to update workflow: %v", err)
+				}
+				fmt.Println(req.GetId())
+				return nil
+			}),
+		},
 		{
 			Name:  "delete",
 			Usage: "Delete workflow within the workflow engine.",
@@ -108,13 +202,21 @@ var cmdWorkflow = cli.Command{
 		{
 			Name:  "get",
 			Usage: "get <Workflow-id> <task-id>",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "selector, l",
+					Usage: "Label selector to filter the listed workflows, such as 'team=payments,env in (prod)'",
+				},
+			},
 			Action: commandContext(func(ctx Context) error {
 				client := getClient(ctx)
 
 				switch ctx.NArg() {
 				case 0:
 					// List workflows
-					resp, err := client.Workflow.List(ctx)
+					resp, err := client.Workflow.List(ctx, &apiserver.WorkflowListQuery{
+						LabelSelector: ctx.String("selector"),
+					})
 					if err != nil {
 						panic(err)
 					}
@@ -129,10 +231,10 @@ var cmdWorkflow = cli.Command{
 						updated, _ := ptypes.Timestamp(wf.Status.UpdatedAt)
 						created, _ := ptypes.Timestamp(wf.Metadata.CreatedAt)
 
-						rows = append(rows, []string{wfID, wf.Spec.Name, wf.Status.Status.String(),
-							created.String(), updated.String()})
+						rows = append(rows, []string{wfID, wf.Spec.Name, fmt.Sprintf("%d", wf.Status.Version),
+							wf.Status.Status.String(), created.String(), updated.String()})
 					}
-					table(os.Stdout, []string{"ID", "NAME", "STATUS", "CREATED", "UPDATED"}, rows)
+					table(os.Stdout, []string{"ID", "NAME", "VERSION", "STATUS", "CREATED", "UPDATED"}, rows)
 				case 1:
 					// Get Workflow
 					wfID := ctx.Args().Get(0)
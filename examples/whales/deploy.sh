@@ -21,3 +21,12 @@ fission fn create --name switchwhale --env workflow --src ./switchwhale.wf.yaml
 fission fn create --name whilewhale --env workflow --src ./whilewhale.wf.yaml
 fission fn create --name foreachwhale --env workflow --src ./foreachwhale.wf.yaml
 fission fn create --name respheaderswhale --env workflow --src ./respheaderswhale.wf.yaml
+fission fn create --name mapreducewhale --env workflow --src ./mapreducewhale.wf.yaml
+fission fn create --name retrywhale --env workflow --src ./retrywhale.wf.yaml
+fission fn create --name transformwhale --env workflow --src ./transformwhale.wf.yaml
+fission fn create --name regexwhale --env workflow --src ./regexwhale.wf.yaml
+fission fn create --name encodingwhale --env workflow --src ./encodingwhale.wf.yaml
+fission fn create --name kvwhale --env workflow --src ./kvwhale.wf.yaml
+fission fn create --name randomwhale --env workflow --src ./randomwhale.wf.yaml
+fission fn create --name timewhale --env workflow --src ./timewhale.wf.yaml
+fission fn create --name emitwhale --env workflow --src ./emitwhale.wf.yaml
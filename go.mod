@@ -12,7 +12,7 @@ require (
 	github.com/cenkalti/backoff v2.1.1+incompatible // indirect
 	github.com/codahale/hdrhistogram v0.0.0-20161010025455-3a0bb77429bd // indirect
 	github.com/containerd/continuity v0.0.0-20190426062206-aaeac12a7ffc // indirect
-	github.com/dgrijalva/jwt-go v0.0.0-20160705203006-01aeca54ebda // indirect
+	github.com/dgrijalva/jwt-go v0.0.0-20160705203006-01aeca54ebda
 	github.com/docker/go-connections v0.4.0 // indirect
 	github.com/docker/go-units v0.4.0 // indirect
 	github.com/docker/spdystream v0.0.0-20160310174837-449fdfce4d96 // indirect
@@ -23,14 +23,13 @@ require (
 	github.com/fatih/structs v1.0.0
 	github.com/fission/fission v0.0.0-20181101225549-9bd18bdacd26
 	github.com/ghodss/yaml v0.0.0-20150909031657-73d445a93680 // indirect
+	github.com/go-ini/ini v1.67.0 // indirect
 	github.com/go-sql-driver/mysql v1.4.1 // indirect
 	github.com/gogo/protobuf v0.0.0-20170330071051-c0656edd0d9e // indirect
 	github.com/golang/glog v0.0.0-20141105023935-44145f04b68c // indirect
 	github.com/golang/groupcache v0.0.0-20190702054246-869f871628b6 // indirect
 	github.com/golang/protobuf v1.3.1
-	github.com/golang/snappy v0.0.0-20180518054509-2e65f85255db // indirect
 	github.com/gomodule/redigo v0.0.0-20180627144507-2cd21d9966bf // indirect
-	github.com/google/go-cmp v0.3.0 // indirect
 	github.com/google/gofuzz v0.0.0-20161122191042-44d81051d367 // indirect
 	github.com/googleapis/gnostic v0.0.0-20170729233727-0c5108395e2d // indirect
 	github.com/gophercloud/gophercloud v0.0.0-20180210024343-6da026c32e2d // indirect
@@ -48,13 +47,16 @@ require (
 	github.com/hashicorp/raft v1.1.0 // indirect
 	github.com/howeyc/gopass v0.0.0-20170109162249-bf9dde6d0d2c // indirect
 	github.com/imdario/mergo v0.3.6
+	github.com/itchyny/gojq v0.12.4
 	github.com/json-iterator/go v0.0.0-20180612202835-f2b4162afba3 // indirect
 	github.com/konsorten/go-windows-terminal-sequences v1.0.1 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/lib/pq v1.1.1 // indirect
+	github.com/linkedin/goavro/v2 v2.9.7
 	github.com/mattn/go-colorable v0.1.0 // indirect
-	github.com/mattn/go-isatty v0.0.0-20180830101745-3fb116b82035 // indirect
 	github.com/mholt/archiver v0.0.0-20180417220235-e4ef56d48eb0 // indirect
+	github.com/minio/minio-go v6.0.14+incompatible
+	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
 	github.com/modern-go/reflect2 v0.0.0-20180320133207-05fbef0ca5da // indirect
 	github.com/nats-io/gnatsd v1.4.1 // indirect
@@ -72,6 +74,7 @@ require (
 	github.com/pierrec/xxHash v0.1.5 // indirect
 	github.com/pkg/errors v0.8.1
 	github.com/prometheus/client_golang v0.9.2
+	github.com/prometheus/client_model v0.0.0-20180712105110-5c3871d89910
 	github.com/robertkrimen/otto v0.0.0-20180305042045-6c383dd335ef
 	github.com/robfig/cron v1.2.0 // indirect
 	github.com/satori/go.uuid v1.2.0
@@ -84,12 +87,13 @@ require (
 	github.com/ulikunitz/xz v0.0.0-20180703112113-636d36a76670 // indirect
 	github.com/urfave/cli v1.19.1
 	go.etcd.io/bbolt v1.3.3 // indirect
+	go.starlark.net v0.0.0-20201006213952-227f4aabceb5
 	go.uber.org/atomic v1.3.2
 	golang.org/x/exp v0.0.0-20190627132806-fd42eb6b336f // indirect
 	golang.org/x/net v0.0.0-20190311183353-d8887717615a
 	golang.org/x/oauth2 v0.0.0-20170412232759-a6bd8cefa181 // indirect
 	golang.org/x/sync v0.0.0-20181108010431-42b317875d0f
-	golang.org/x/time v0.0.0-20161028155119-f51c12702a4d // indirect
+	golang.org/x/time v0.0.0-20161028155119-f51c12702a4d
 	gonum.org/v1/gonum v0.0.0-20180205154402-996b88e8f894
 	google.golang.org/appengine v0.0.0-20171031194329-9d8544a6b2c7 // indirect
 	google.golang.org/genproto v0.0.0-20180316064809-f8c870359523
@@ -100,7 +104,7 @@ require (
 	gopkg.in/sourcemap.v1 v1.0.5 // indirect
 	gopkg.in/yaml.v2 v2.0.0-20170721113624-670d4cfef054
 	gotest.tools v2.2.0+incompatible // indirect
-	k8s.io/api v0.0.0-20190116205037-c89978d5f86d // indirect
+	k8s.io/api v0.0.0-20190116205037-c89978d5f86d
 	k8s.io/apiextensions-apiserver v0.0.0-20190116211702-f0729a5940c5 // indirect
 	k8s.io/apimachinery v0.0.0-20190116203031-d49e237a2683
 	k8s.io/client-go v7.0.0+incompatible
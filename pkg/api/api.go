@@ -6,12 +6,14 @@ import (
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 )
 
 type CallConfig struct {
 	ctx             context.Context
 	postTransformer func(i interface{}) error
 	awaitWorkflow   time.Duration
+	rateLimit       float64
 }
 
 type CallOption func(op *CallConfig)
@@ -51,3 +53,45 @@ func AwaitWorklow(timeout time.Duration) CallOption {
 		config.awaitWorkflow = timeout
 	}
 }
+
+// RateLimit limits the number of calls per second of batch operations, such as InvokeMany. If 0, the calls are not
+// limited.
+func RateLimit(perSecond float64) CallOption {
+	return func(config *CallConfig) {
+		config.rateLimit = perSecond
+	}
+}
+
+// ValueOffloader moves large values out of the events, by replacing them with references to an external store.
+type ValueOffloader interface {
+	// Offload replaces the value with a reference if it exceeds the threshold of the offloader.
+	Offload(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error)
+
+	// Put replaces the value with a reference regardless of its size.
+	Put(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error)
+}
+
+// offload offloads the value using the offloader, if one is configured.
+func offload(offloader ValueOffloader, tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	if offloader == nil || tv == nil {
+		return tv, nil
+	}
+	return offloader.Offload(tv)
+}
+
+// offloadAll offloads each of the values using the offloader, if one is configured.
+func offloadAll(offloader ValueOffloader, tvs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue,
+	error) {
+	if offloader == nil || tvs == nil {
+		return tvs, nil
+	}
+	offloaded := make(map[string]*typedvalues.TypedValue, len(tvs))
+	for k, tv := range tvs {
+		o, err := offloader.Offload(tv)
+		if err != nil {
+			return nil, err
+		}
+		offloaded[k] = o
+	}
+	return offloaded, nil
+}
@@ -11,25 +11,33 @@ type Event interface {
 }
 
 const (
-	EventWorkflowCreated       EventType = "WorkflowCreated"
-	EventWorkflowDeleted       EventType = "WorkflowDeleted"
-	EventWorkflowParsed        EventType = "WorkflowParsed"
-	EventWorkflowParsingFailed EventType = "WorkflowParsingFailed"
-	EventInvocationCreated     EventType = "InvocationCreated"
-	EventInvocationCompleted   EventType = "InvocationCompleted"
-	EventInvocationCanceled    EventType = "InvocationCanceled"
-	EventInvocationTaskAdded   EventType = "InvocationTaskAdded"
-	EventInvocationFailed      EventType = "InvocationFailed"
-	EventTaskStarted           EventType = "TaskStarted"
-	EventTaskSucceeded         EventType = "TaskSucceeded"
-	EventTaskSkipped           EventType = "TaskSkipped"
-	EventTaskFailed            EventType = "TaskFailed"
+	EventWorkflowCreated           EventType = "WorkflowCreated"
+	EventWorkflowUpdated           EventType = "WorkflowUpdated"
+	EventWorkflowDeleted           EventType = "WorkflowDeleted"
+	EventWorkflowParsed            EventType = "WorkflowParsed"
+	EventWorkflowParsingFailed     EventType = "WorkflowParsingFailed"
+	EventInvocationCreated         EventType = "InvocationCreated"
+	EventInvocationCompleted       EventType = "InvocationCompleted"
+	EventInvocationCanceled        EventType = "InvocationCanceled"
+	EventInvocationTaskAdded       EventType = "InvocationTaskAdded"
+	EventInvocationWorkflowUpdated EventType = "InvocationWorkflowUpdated"
+	EventInvocationFailed          EventType = "InvocationFailed"
+	EventInvocationIntentRecorded  EventType = "InvocationIntentRecorded"
+	EventInvocationValueStored     EventType = "InvocationValueStored"
+	EventTaskStarted               EventType = "TaskStarted"
+	EventTaskSucceeded             EventType = "TaskSucceeded"
+	EventTaskSkipped               EventType = "TaskSkipped"
+	EventTaskFailed                EventType = "TaskFailed"
 )
 
 func (m *WorkflowCreated) Type() EventType {
 	return EventWorkflowCreated
 }
 
+func (m *WorkflowUpdated) Type() EventType {
+	return EventWorkflowUpdated
+}
+
 func (m *WorkflowDeleted) Type() EventType {
 	return EventWorkflowDeleted
 }
@@ -58,10 +66,22 @@ func (m *InvocationTaskAdded) Type() EventType {
 	return EventInvocationTaskAdded
 }
 
+func (m *InvocationWorkflowUpdated) Type() EventType {
+	return EventInvocationWorkflowUpdated
+}
+
 func (m *InvocationFailed) Type() EventType {
 	return EventInvocationFailed
 }
 
+func (m *InvocationIntentRecorded) Type() EventType {
+	return EventInvocationIntentRecorded
+}
+
+func (m *InvocationValueStored) Type() EventType {
+	return EventInvocationValueStored
+}
+
 func (m *TaskStarted) Type() EventType {
 	return EventTaskStarted
 }
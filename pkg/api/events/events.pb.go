@@ -9,6 +9,7 @@ It is generated from these files:
 
 It has these top-level messages:
 	WorkflowCreated
+	WorkflowUpdated
 	WorkflowDeleted
 	WorkflowParsed
 	WorkflowParsingFailed
@@ -16,7 +17,10 @@ It has these top-level messages:
 	InvocationCompleted
 	InvocationCanceled
 	InvocationTaskAdded
+	InvocationWorkflowUpdated
 	InvocationFailed
+	InvocationIntentRecorded
+	InvocationValueStored
 	TaskStarted
 	TaskSucceeded
 	TaskSkipped
@@ -57,22 +61,49 @@ func (m *WorkflowCreated) GetSpec() *fission_workflows_types1.WorkflowSpec {
 	return nil
 }
 
+type WorkflowUpdated struct {
+	Spec *fission_workflows_types1.WorkflowSpec `protobuf:"bytes,1,opt,name=spec" json:"spec,omitempty"`
+	// version is the version of the workflow that the update creates.
+	Version int64 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *WorkflowUpdated) Reset()                    { *m = WorkflowUpdated{} }
+func (m *WorkflowUpdated) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowUpdated) ProtoMessage()               {}
+func (*WorkflowUpdated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *WorkflowUpdated) GetSpec() *fission_workflows_types1.WorkflowSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *WorkflowUpdated) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type WorkflowDeleted struct {
 }
 
 func (m *WorkflowDeleted) Reset()                    { *m = WorkflowDeleted{} }
 func (m *WorkflowDeleted) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowDeleted) ProtoMessage()               {}
-func (*WorkflowDeleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*WorkflowDeleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 type WorkflowParsed struct {
 	Tasks map[string]*fission_workflows_types1.TaskStatus `protobuf:"bytes,1,rep,name=tasks" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// version is the version of the workflow that was parsed. Parse results of outdated versions are ignored.
+	Version int64 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *WorkflowParsed) Reset()                    { *m = WorkflowParsed{} }
 func (m *WorkflowParsed) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowParsed) ProtoMessage()               {}
-func (*WorkflowParsed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*WorkflowParsed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *WorkflowParsed) GetTasks() map[string]*fission_workflows_types1.TaskStatus {
 	if m != nil {
@@ -81,6 +112,13 @@ func (m *WorkflowParsed) GetTasks() map[string]*fission_workflows_types1.TaskSta
 	return nil
 }
 
+func (m *WorkflowParsed) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 type WorkflowParsingFailed struct {
 	Error *fission_workflows_types1.Error `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
 }
@@ -88,7 +126,7 @@ type WorkflowParsingFailed struct {
 func (m *WorkflowParsingFailed) Reset()                    { *m = WorkflowParsingFailed{} }
 func (m *WorkflowParsingFailed) String() string            { return proto.CompactTextString(m) }
 func (*WorkflowParsingFailed) ProtoMessage()               {}
-func (*WorkflowParsingFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*WorkflowParsingFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *WorkflowParsingFailed) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -104,7 +142,7 @@ type InvocationCreated struct {
 func (m *InvocationCreated) Reset()                    { *m = InvocationCreated{} }
 func (m *InvocationCreated) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCreated) ProtoMessage()               {}
-func (*InvocationCreated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*InvocationCreated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *InvocationCreated) GetSpec() *fission_workflows_types1.WorkflowInvocationSpec {
 	if m != nil {
@@ -121,7 +159,7 @@ type InvocationCompleted struct {
 func (m *InvocationCompleted) Reset()                    { *m = InvocationCompleted{} }
 func (m *InvocationCompleted) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCompleted) ProtoMessage()               {}
-func (*InvocationCompleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*InvocationCompleted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *InvocationCompleted) GetOutput() *fission_workflows_types.TypedValue {
 	if m != nil {
@@ -144,7 +182,7 @@ type InvocationCanceled struct {
 func (m *InvocationCanceled) Reset()                    { *m = InvocationCanceled{} }
 func (m *InvocationCanceled) String() string            { return proto.CompactTextString(m) }
 func (*InvocationCanceled) ProtoMessage()               {}
-func (*InvocationCanceled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*InvocationCanceled) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *InvocationCanceled) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -160,7 +198,7 @@ type InvocationTaskAdded struct {
 func (m *InvocationTaskAdded) Reset()                    { *m = InvocationTaskAdded{} }
 func (m *InvocationTaskAdded) String() string            { return proto.CompactTextString(m) }
 func (*InvocationTaskAdded) ProtoMessage()               {}
-func (*InvocationTaskAdded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*InvocationTaskAdded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *InvocationTaskAdded) GetTask() *fission_workflows_types1.Task {
 	if m != nil {
@@ -169,6 +207,23 @@ func (m *InvocationTaskAdded) GetTask() *fission_workflows_types1.Task {
 	return nil
 }
 
+type InvocationWorkflowUpdated struct {
+	// workflow is the snapshot of the version of the workflow that the invocation was migrated to.
+	Workflow *fission_workflows_types1.Workflow `protobuf:"bytes,1,opt,name=workflow" json:"workflow,omitempty"`
+}
+
+func (m *InvocationWorkflowUpdated) Reset()                    { *m = InvocationWorkflowUpdated{} }
+func (m *InvocationWorkflowUpdated) String() string            { return proto.CompactTextString(m) }
+func (*InvocationWorkflowUpdated) ProtoMessage()               {}
+func (*InvocationWorkflowUpdated) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *InvocationWorkflowUpdated) GetWorkflow() *fission_workflows_types1.Workflow {
+	if m != nil {
+		return m.Workflow
+	}
+	return nil
+}
+
 type InvocationFailed struct {
 	Error *fission_workflows_types1.Error `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
 }
@@ -176,7 +231,7 @@ type InvocationFailed struct {
 func (m *InvocationFailed) Reset()                    { *m = InvocationFailed{} }
 func (m *InvocationFailed) String() string            { return proto.CompactTextString(m) }
 func (*InvocationFailed) ProtoMessage()               {}
-func (*InvocationFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*InvocationFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *InvocationFailed) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -185,6 +240,47 @@ func (m *InvocationFailed) GetError() *fission_workflows_types1.Error {
 	return nil
 }
 
+type InvocationIntentRecorded struct {
+	Intent *fission_workflows_types1.Intent `protobuf:"bytes,1,opt,name=intent" json:"intent,omitempty"`
+}
+
+func (m *InvocationIntentRecorded) Reset()                    { *m = InvocationIntentRecorded{} }
+func (m *InvocationIntentRecorded) String() string            { return proto.CompactTextString(m) }
+func (*InvocationIntentRecorded) ProtoMessage()               {}
+func (*InvocationIntentRecorded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *InvocationIntentRecorded) GetIntent() *fission_workflows_types1.Intent {
+	if m != nil {
+		return m.Intent
+	}
+	return nil
+}
+
+// InvocationValueStored records a value of the key-value store of the invocation (see the kv functions).
+type InvocationValueStored struct {
+	Key   string                              `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value *fission_workflows_types.TypedValue `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *InvocationValueStored) Reset()                    { *m = InvocationValueStored{} }
+func (m *InvocationValueStored) String() string            { return proto.CompactTextString(m) }
+func (*InvocationValueStored) ProtoMessage()               {}
+func (*InvocationValueStored) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *InvocationValueStored) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *InvocationValueStored) GetValue() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
 //
 // Task
 //
@@ -196,7 +292,7 @@ type TaskStarted struct {
 func (m *TaskStarted) Reset()                    { *m = TaskStarted{} }
 func (m *TaskStarted) String() string            { return proto.CompactTextString(m) }
 func (*TaskStarted) ProtoMessage()               {}
-func (*TaskStarted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*TaskStarted) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *TaskStarted) GetSpec() *fission_workflows_types1.TaskInvocationSpec {
 	if m != nil {
@@ -212,7 +308,7 @@ type TaskSucceeded struct {
 func (m *TaskSucceeded) Reset()                    { *m = TaskSucceeded{} }
 func (m *TaskSucceeded) String() string            { return proto.CompactTextString(m) }
 func (*TaskSucceeded) ProtoMessage()               {}
-func (*TaskSucceeded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*TaskSucceeded) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *TaskSucceeded) GetResult() *fission_workflows_types1.TaskInvocationStatus {
 	if m != nil {
@@ -222,12 +318,21 @@ func (m *TaskSucceeded) GetResult() *fission_workflows_types1.TaskInvocationStat
 }
 
 type TaskSkipped struct {
+	// Spec is the spec of the task invocation that was skipped.
+	Spec *fission_workflows_types1.TaskInvocationSpec `protobuf:"bytes,1,opt,name=spec" json:"spec,omitempty"`
 }
 
 func (m *TaskSkipped) Reset()                    { *m = TaskSkipped{} }
 func (m *TaskSkipped) String() string            { return proto.CompactTextString(m) }
 func (*TaskSkipped) ProtoMessage()               {}
-func (*TaskSkipped) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*TaskSkipped) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *TaskSkipped) GetSpec() *fission_workflows_types1.TaskInvocationSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
 
 type TaskFailed struct {
 	Error *fission_workflows_types1.Error `protobuf:"bytes,1,opt,name=error" json:"error,omitempty"`
@@ -236,7 +341,7 @@ type TaskFailed struct {
 func (m *TaskFailed) Reset()                    { *m = TaskFailed{} }
 func (m *TaskFailed) String() string            { return proto.CompactTextString(m) }
 func (*TaskFailed) ProtoMessage()               {}
-func (*TaskFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*TaskFailed) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *TaskFailed) GetError() *fission_workflows_types1.Error {
 	if m != nil {
@@ -247,6 +352,7 @@ func (m *TaskFailed) GetError() *fission_workflows_types1.Error {
 
 func init() {
 	proto.RegisterType((*WorkflowCreated)(nil), "fission.workflows.events.WorkflowCreated")
+	proto.RegisterType((*WorkflowUpdated)(nil), "fission.workflows.events.WorkflowUpdated")
 	proto.RegisterType((*WorkflowDeleted)(nil), "fission.workflows.events.WorkflowDeleted")
 	proto.RegisterType((*WorkflowParsed)(nil), "fission.workflows.events.WorkflowParsed")
 	proto.RegisterType((*WorkflowParsingFailed)(nil), "fission.workflows.events.WorkflowParsingFailed")
@@ -254,7 +360,10 @@ func init() {
 	proto.RegisterType((*InvocationCompleted)(nil), "fission.workflows.events.InvocationCompleted")
 	proto.RegisterType((*InvocationCanceled)(nil), "fission.workflows.events.InvocationCanceled")
 	proto.RegisterType((*InvocationTaskAdded)(nil), "fission.workflows.events.InvocationTaskAdded")
+	proto.RegisterType((*InvocationWorkflowUpdated)(nil), "fission.workflows.events.InvocationWorkflowUpdated")
 	proto.RegisterType((*InvocationFailed)(nil), "fission.workflows.events.InvocationFailed")
+	proto.RegisterType((*InvocationIntentRecorded)(nil), "fission.workflows.events.InvocationIntentRecorded")
+	proto.RegisterType((*InvocationValueStored)(nil), "fission.workflows.events.InvocationValueStored")
 	proto.RegisterType((*TaskStarted)(nil), "fission.workflows.events.TaskStarted")
 	proto.RegisterType((*TaskSucceeded)(nil), "fission.workflows.events.TaskSucceeded")
 	proto.RegisterType((*TaskSkipped)(nil), "fission.workflows.events.TaskSkipped")
@@ -264,36 +373,42 @@ func init() {
 func init() { proto.RegisterFile("pkg/api/events/events.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 488 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x94, 0xdf, 0x6a, 0xd4, 0x40,
-	0x14, 0xc6, 0x49, 0xdb, 0x5d, 0xf4, 0x2c, 0xab, 0xed, 0x88, 0xb0, 0xac, 0x28, 0x25, 0x22, 0x14,
-	0xa4, 0x09, 0xb6, 0x5e, 0xd8, 0x7a, 0x21, 0xb6, 0xae, 0xec, 0x4a, 0xfd, 0x43, 0x2a, 0x55, 0x04,
-	0x2f, 0xa6, 0x99, 0xd3, 0x35, 0x24, 0xcd, 0x0c, 0x33, 0x93, 0x2d, 0xfb, 0x30, 0xbe, 0x88, 0x4f,
-	0x27, 0x93, 0x99, 0x98, 0x04, 0xdd, 0xad, 0x74, 0x6f, 0x36, 0x43, 0xf6, 0x7c, 0x3f, 0xce, 0xf7,
-	0x9d, 0x33, 0x81, 0x07, 0x22, 0x9d, 0x86, 0x54, 0x24, 0x21, 0xce, 0x30, 0xd7, 0xca, 0x3d, 0x02,
-	0x21, 0xb9, 0xe6, 0x64, 0x70, 0x91, 0x28, 0x95, 0xf0, 0x3c, 0xb8, 0xe2, 0x32, 0xbd, 0xc8, 0xf8,
-	0x95, 0x0a, 0xec, 0xff, 0xc3, 0xc3, 0x69, 0xa2, 0x7f, 0x14, 0xe7, 0x41, 0xcc, 0x2f, 0x43, 0x57,
-	0x54, 0x3d, 0x77, 0xff, 0x14, 0x87, 0x86, 0xad, 0xe7, 0x02, 0x95, 0xfd, 0xb5, 0xd4, 0xe1, 0xc9,
-	0x0d, 0xb4, 0x6c, 0x46, 0xb3, 0xa2, 0x7d, 0xb6, 0x34, 0xff, 0x04, 0xee, 0x7e, 0x71, 0xa2, 0x63,
-	0x89, 0x54, 0x23, 0x23, 0x07, 0xb0, 0xa1, 0x04, 0xc6, 0x03, 0x6f, 0xdb, 0xdb, 0xe9, 0xed, 0x3d,
-	0x09, 0xfe, 0x76, 0x61, 0xdb, 0xa9, 0x74, 0xa7, 0x02, 0xe3, 0xa8, 0x94, 0xf8, 0x5b, 0x35, 0xed,
-	0x0d, 0x66, 0xa8, 0x91, 0xf9, 0xbf, 0x3c, 0xb8, 0x53, 0xbd, 0xfb, 0x44, 0xa5, 0x42, 0x46, 0x26,
-	0xd0, 0xd1, 0x54, 0xa5, 0x6a, 0xe0, 0x6d, 0xaf, 0xef, 0xf4, 0xf6, 0xf6, 0x83, 0x45, 0x39, 0x05,
-	0x6d, 0x61, 0xf0, 0xd9, 0xa8, 0x46, 0xb9, 0x96, 0xf3, 0xc8, 0x12, 0x86, 0xdf, 0x01, 0xea, 0x97,
-	0x64, 0x13, 0xd6, 0x53, 0x9c, 0x97, 0x8d, 0xdf, 0x8e, 0xcc, 0x91, 0x1c, 0x40, 0xa7, 0xb4, 0x3b,
-	0x58, 0x2b, 0xcd, 0x3c, 0x5e, 0x68, 0xc6, 0x50, 0x4e, 0x35, 0xd5, 0x85, 0x8a, 0xac, 0xe2, 0x70,
-	0xed, 0x85, 0xe7, 0xbf, 0x87, 0xfb, 0xcd, 0x16, 0x92, 0x7c, 0xfa, 0x96, 0x26, 0x19, 0x32, 0xf2,
-	0x1c, 0x3a, 0x28, 0x25, 0x97, 0x2e, 0xa4, 0x47, 0x0b, 0xb9, 0x23, 0x53, 0x15, 0xd9, 0x62, 0xff,
-	0x2b, 0x6c, 0x4d, 0xf2, 0x19, 0x8f, 0xa9, 0x4e, 0x78, 0x5e, 0xc5, 0x7d, 0xdc, 0x8a, 0x3b, 0xbc,
-	0x36, 0xee, 0x9a, 0xd0, 0x08, 0xfe, 0xa7, 0x07, 0xf7, 0x1a, 0x68, 0x7e, 0x29, 0xca, 0xf4, 0xc9,
-	0x4b, 0xe8, 0xf2, 0x42, 0x8b, 0x42, 0x3b, 0xfc, 0x92, 0x00, 0xcc, 0x6a, 0x9c, 0x19, 0xe7, 0x91,
-	0x93, 0x90, 0x09, 0xf4, 0x3f, 0x96, 0xa7, 0x31, 0x52, 0x86, 0x52, 0x5d, 0x1f, 0x62, 0xcd, 0x68,
-	0x2b, 0xfd, 0x77, 0x40, 0x1a, 0xed, 0xd1, 0x3c, 0xc6, 0x9b, 0xa7, 0x38, 0x6e, 0x5a, 0x35, 0x73,
-	0x7b, 0xcd, 0x18, 0x32, 0xf2, 0x0c, 0x36, 0xcc, 0x4e, 0x38, 0xd6, 0xc3, 0xa5, 0x93, 0x8e, 0xca,
-	0x52, 0x7f, 0x0c, 0x9b, 0x35, 0x69, 0xa5, 0xc9, 0x7e, 0x80, 0x9e, 0xdb, 0x20, 0x69, 0x62, 0x7f,
-	0xd5, 0x9a, 0xe9, 0xd3, 0xa5, 0xbd, 0xfc, 0x73, 0x9e, 0x67, 0xd0, 0x2f, 0x79, 0x45, 0x1c, 0x23,
-	0x1a, 0x77, 0x23, 0xe8, 0x4a, 0x54, 0x45, 0x56, 0x0d, 0x72, 0xf7, 0x7f, 0x99, 0x76, 0xa7, 0x9d,
-	0xd8, 0xef, 0xbb, 0x3e, 0xd3, 0x44, 0x08, 0x64, 0xfe, 0x91, 0xbd, 0x3e, 0xab, 0x58, 0x3f, 0xba,
-	0xf5, 0xad, 0x6b, 0x6f, 0xeb, 0x79, 0xb7, 0xfc, 0xa4, 0xec, 0xff, 0x0e, 0x00, 0x00, 0xff, 0xff,
-	0x86, 0x61, 0xe6, 0x8d, 0x15, 0x05, 0x00, 0x00,
+	// 577 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x95, 0x6d, 0x6b, 0xd3, 0x50,
+	0x14, 0xc7, 0xc9, 0xb6, 0xd6, 0x79, 0xca, 0x74, 0x8b, 0x0c, 0x62, 0x45, 0xad, 0x11, 0xa1, 0x20,
+	0x4b, 0xb1, 0x13, 0x74, 0x13, 0x11, 0x37, 0x2b, 0xad, 0xcc, 0x07, 0x52, 0x9d, 0x32, 0xf0, 0x45,
+	0x96, 0x7b, 0x5a, 0x43, 0xbb, 0xdc, 0xcb, 0xbd, 0x37, 0x1d, 0xfd, 0x30, 0x7e, 0x2b, 0x3f, 0x90,
+	0xdc, 0x87, 0x34, 0xad, 0xda, 0x75, 0xac, 0xbe, 0x69, 0x6e, 0xd3, 0xf3, 0xff, 0xf5, 0x9c, 0xf3,
+	0x3f, 0x27, 0x81, 0x3b, 0x6c, 0xd0, 0x6f, 0x44, 0x2c, 0x69, 0xe0, 0x08, 0x53, 0x29, 0xec, 0x25,
+	0x60, 0x9c, 0x4a, 0xea, 0x7a, 0xbd, 0x44, 0x88, 0x84, 0xa6, 0xc1, 0x39, 0xe5, 0x83, 0xde, 0x90,
+	0x9e, 0x8b, 0xc0, 0xfc, 0x5e, 0xdd, 0xef, 0x27, 0xf2, 0x47, 0x76, 0x1a, 0xc4, 0xf4, 0xac, 0x61,
+	0x83, 0xf2, 0xeb, 0xce, 0x24, 0xb8, 0xa1, 0xd8, 0x72, 0xcc, 0x50, 0x98, 0x4f, 0x43, 0xad, 0x1e,
+	0x5d, 0x41, 0x4b, 0x46, 0xd1, 0x30, 0x9b, 0x3d, 0x1b, 0x9a, 0x7f, 0x04, 0x37, 0xbf, 0x5a, 0xd1,
+	0x21, 0xc7, 0x48, 0x22, 0x71, 0xf7, 0x60, 0x4d, 0x30, 0x8c, 0x3d, 0xa7, 0xe6, 0xd4, 0x2b, 0xcd,
+	0x47, 0xc1, 0xdf, 0x55, 0x98, 0x74, 0x72, 0x5d, 0x97, 0x61, 0x1c, 0x6a, 0x89, 0xdf, 0x2b, 0x68,
+	0x5f, 0x18, 0x59, 0x92, 0xe6, 0x7a, 0x70, 0x6d, 0x84, 0x5c, 0x45, 0x7b, 0x2b, 0x35, 0xa7, 0xbe,
+	0x1a, 0xe6, 0x5f, 0xfd, 0xad, 0xe2, 0x7f, 0xde, 0xe0, 0x10, 0x25, 0x12, 0xff, 0x97, 0x03, 0x37,
+	0xf2, 0x7b, 0x9f, 0x22, 0x2e, 0x90, 0xb8, 0x1d, 0x28, 0xc9, 0x48, 0x0c, 0x84, 0xe7, 0xd4, 0x56,
+	0xeb, 0x95, 0xe6, 0x6e, 0x30, 0xcf, 0x8f, 0x60, 0x56, 0x18, 0x7c, 0x56, 0xaa, 0x56, 0x2a, 0xf9,
+	0x38, 0x34, 0x84, 0xf9, 0xa9, 0x54, 0xbf, 0x03, 0x14, 0xe1, 0xee, 0x26, 0xac, 0x0e, 0x70, 0xac,
+	0x8b, 0xbd, 0x1e, 0xaa, 0xa3, 0xbb, 0x07, 0x25, 0xdd, 0x70, 0xad, 0xab, 0x34, 0x1f, 0xce, 0x6d,
+	0x80, 0xa2, 0x74, 0x65, 0x24, 0x33, 0x11, 0x1a, 0xc5, 0xfe, 0xca, 0x73, 0xc7, 0x7f, 0x0f, 0xdb,
+	0xd3, 0xc9, 0x25, 0x69, 0xff, 0x6d, 0x94, 0x0c, 0x91, 0xb8, 0x4f, 0xa1, 0x84, 0x9c, 0x53, 0x6e,
+	0x1b, 0x7b, 0x6f, 0x2e, 0xb7, 0xa5, 0xa2, 0x42, 0x13, 0xec, 0x7f, 0x83, 0xad, 0x4e, 0x3a, 0xa2,
+	0x71, 0x24, 0x13, 0x9a, 0xe6, 0x86, 0x1f, 0xce, 0x58, 0xd4, 0x58, 0x68, 0x51, 0x41, 0x98, 0xb2,
+	0xfe, 0xa7, 0x03, 0xb7, 0xa6, 0xd0, 0xf4, 0x8c, 0x69, 0x5f, 0xdc, 0x17, 0x50, 0xa6, 0x99, 0x64,
+	0x99, 0xf4, 0x9c, 0x45, 0x0d, 0x50, 0xc3, 0x79, 0xac, 0x2a, 0x0f, 0xad, 0xc4, 0xed, 0xc0, 0xc6,
+	0x47, 0x7d, 0x6a, 0x63, 0x44, 0x90, 0x0b, 0x6f, 0xe5, 0xf2, 0x8c, 0x59, 0xa5, 0xff, 0x0e, 0xdc,
+	0xa9, 0xf4, 0xa2, 0x34, 0xc6, 0xab, 0x77, 0xb1, 0x3d, 0x5d, 0xaa, 0xf2, 0xed, 0x35, 0x21, 0x48,
+	0xdc, 0x27, 0xb0, 0xa6, 0xa6, 0xc5, 0xb2, 0xee, 0x5e, 0xe8, 0x74, 0xa8, 0x43, 0xfd, 0x13, 0xb8,
+	0x5d, 0x90, 0xfe, 0x5c, 0x9d, 0x97, 0xb0, 0x9e, 0x4b, 0x2d, 0xf3, 0xc1, 0x42, 0x6f, 0xc2, 0x89,
+	0xc4, 0x6f, 0xc3, 0x66, 0xc1, 0x5e, 0x6a, 0x6a, 0xba, 0xe0, 0x15, 0xa4, 0x4e, 0x2a, 0x31, 0x95,
+	0x21, 0xc6, 0x94, 0xab, 0xa2, 0x9f, 0x41, 0x39, 0xd1, 0x77, 0x2c, 0xf2, 0xfe, 0x5c, 0xa4, 0x15,
+	0xda, 0x70, 0x9f, 0xc0, 0x76, 0x01, 0xd5, 0x96, 0x75, 0x25, 0xe5, 0x48, 0x96, 0xda, 0xa1, 0xc2,
+	0x7e, 0xa3, 0xf0, 0x3f, 0x40, 0xc5, 0x2e, 0x16, 0x57, 0x2d, 0x7d, 0x35, 0x33, 0xea, 0x8f, 0x2f,
+	0xb4, 0xe8, 0x9f, 0x63, 0x7e, 0x0c, 0x1b, 0x9a, 0x97, 0xc5, 0x31, 0xa2, 0xaa, 0xbf, 0x05, 0x65,
+	0x8e, 0x22, 0x1b, 0xe6, 0xf5, 0xef, 0x5c, 0x96, 0x69, 0x56, 0xdd, 0x8a, 0x27, 0x79, 0x0e, 0x12,
+	0xc6, 0xfe, 0x47, 0x9e, 0x07, 0xe6, 0xb1, 0xb4, 0x8c, 0xed, 0x07, 0xeb, 0x27, 0x65, 0xf3, 0x7c,
+	0x3c, 0x2d, 0xeb, 0x97, 0xc5, 0xee, 0xef, 0x01, 0x00, 0xbd, 0x17, 0x71, 0xff, 0xef, 0x06, 0x00,
+	0x00,
 }
@@ -3,29 +3,40 @@ package api
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/proto"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
 )
 
-const ErrInvocationCanceled = "workflow invocation was canceled"
+const (
+	ErrInvocationCanceled         = "workflow invocation was canceled"
+	ErrInvocationDeadlineExceeded = "deadline exceeded"
+)
 
 // Invocation contains the API functionality for controlling (workflow) invocations.
 // This includes starting, stopping, and completing invocations.
 type Invocation struct {
-	es fes.Backend
+	es        fes.Backend
+	offloader ValueOffloader
 }
 
-// NewInvocationAPI creates the Invocation API.
-func NewInvocationAPI(esClient fes.Backend) *Invocation {
-	return &Invocation{esClient}
+// NewInvocationAPI creates the Invocation API. The offloader is optional; if it is provided, large invocation inputs
+// and outputs are offloaded before they are appended to the event store.
+func NewInvocationAPI(esClient fes.Backend, offloader ValueOffloader) *Invocation {
+	return &Invocation{
+		es:        esClient,
+		offloader: offloader,
+	}
 }
 
 // Invoke triggers the start of the invocation using the provided specification.
@@ -37,6 +48,25 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 	if err != nil {
 		return "", err
 	}
+	// The spec is completed below (such as with the default inputs), which should not affect the spec of the caller.
+	spec = proto.Clone(spec).(*types.WorkflowInvocationSpec)
+
+	// Pin the invocation to the requested version of the workflow.
+	if spec.Workflow != nil {
+		wf, ok := spec.Workflow.AtVersion(spec.GetWorkflowVersion())
+		if !ok {
+			return "", validate.NewError("workflowVersion",
+				fmt.Errorf("workflow %s has no version %d", spec.Workflow.ID(), spec.GetWorkflowVersion()))
+		}
+		spec.Workflow = wf
+		spec.WorkflowVersion = wf.GetStatus().GetVersion()
+	}
+
+	// Apply the default inputs of the workflow, and validate the inputs against its input schema.
+	spec.Inputs = spec.GetWorkflow().GetSpec().WithDefaultInputs(spec.GetInputs())
+	if err := validate.WorkflowInputs(spec.GetWorkflow().GetSpec(), spec.GetInputs()); err != nil {
+		return "", err
+	}
 
 	// Ensure that te body input is also accessible on the default parameter
 	// TODO remove once default input field is removed
@@ -48,6 +78,19 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 
 	invocationID := fmt.Sprintf("wi-%s", util.UID())
 
+	inputs, err := offloadAll(ia.offloader, spec.Inputs)
+	if err != nil {
+		return "", err
+	}
+	if err := fnenv.CheckPayloadSizes("inputs", inputs); err != nil {
+		return "", validate.NewError("inputs", err)
+	}
+	if len(inputs) > 0 {
+		offloaded := *spec
+		offloaded.Inputs = inputs
+		spec = &offloaded
+	}
+
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationCreated{
 			Spec: spec,
@@ -56,7 +99,8 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 		return "", err
 	}
 
-	// If part of a span, add trace metadata to the event.
+	// If part of a span, add trace metadata to the event, which becomes the tracing context of the invocation unless
+	// the spec provides one.
 	span := opentracing.SpanFromContext(cfg.ctx)
 	if span != nil {
 		err = opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap,
@@ -74,18 +118,59 @@ func (ia *Invocation) Invoke(spec *types.WorkflowInvocationSpec, opts ...CallOpt
 	return invocationID, nil
 }
 
+// InvokeMany starts an invocation for each of the specifications, in order. It returns the invocationID of each
+// invocation, or the error that prevented the invocation from being started, by the index of the specification. An
+// error of one invocation does not affect the other invocations, unless the context is canceled.
+//
+// With the RateLimit option, the invocations are spread over time, blocking until all invocations have been started.
+func (ia *Invocation) InvokeMany(specs []*types.WorkflowInvocationSpec, opts ...CallOption) ([]string, []error) {
+	cfg := parseCallOptions(opts)
+	ids := make([]string, len(specs))
+	errs := make([]error, len(specs))
+
+	var throttle <-chan time.Time
+	if cfg.rateLimit > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.rateLimit))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+	for i, spec := range specs {
+		if i > 0 && throttle != nil {
+			select {
+			case <-cfg.ctx.Done():
+			case <-throttle:
+			}
+		}
+		if err := cfg.ctx.Err(); err != nil {
+			errs[i] = err
+			continue
+		}
+		ids[i], errs[i] = ia.Invoke(spec, opts...)
+	}
+	return ids, errs
+}
+
 // Cancel halts an invocation. This does not guarantee that tasks currently running are halted,
 // but beyond the invocation will not progress any further than those tasks. The state of the invocation will
 // become ABORTED. If the API fails to append the event to the event store, it will return an error.
 func (ia *Invocation) Cancel(invocationID string) error {
+	return ia.CancelWithReason(invocationID, "")
+}
+
+// CancelWithReason cancels the invocation, recording the human-readable reason in the error of the invocation status.
+func (ia *Invocation) CancelWithReason(invocationID string, reason string) error {
 	if len(invocationID) == 0 {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
 
+	msg := ErrInvocationCanceled
+	if len(reason) > 0 {
+		msg = fmt.Sprintf("%s: %s", ErrInvocationCanceled, reason)
+	}
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationCanceled{
 			Error: &types.Error{
-				Message: ErrInvocationCanceled,
+				Message: msg,
 			},
 		})
 	if err != nil {
@@ -99,6 +184,49 @@ func (ia *Invocation) Cancel(invocationID string) error {
 	return nil
 }
 
+// ChildInvocationLister lists the child invocations of an invocation. It is implemented by store.Invocations.
+type ChildInvocationLister interface {
+	GetChildInvocations(invocationID string) ([]*types.WorkflowInvocation, error)
+}
+
+// CancelCascade cancels the invocation along with all of its descendants - the invocations started by it, such as
+// nested workflows and dynamic tasks - that have not finished yet. The reason is recorded in the status of each of the
+// canceled invocations.
+func (ia *Invocation) CancelCascade(invocationID string, reason string, invocations ChildInvocationLister) error {
+	// Cancel the invocation first, to prevent it from starting new child invocations.
+	if err := ia.CancelWithReason(invocationID, reason); err != nil {
+		return err
+	}
+
+	childReason := fmt.Sprintf("invocation %s was canceled", invocationID)
+	if len(reason) > 0 {
+		childReason = fmt.Sprintf("%s: %s", childReason, reason)
+	}
+	visited := map[string]bool{invocationID: true}
+	queue := []string{invocationID}
+	for len(queue) > 0 {
+		children, err := invocations.GetChildInvocations(queue[0])
+		if err != nil {
+			return err
+		}
+		queue = queue[1:]
+		for _, child := range children {
+			if visited[child.ID()] {
+				continue
+			}
+			visited[child.ID()] = true
+			queue = append(queue, child.ID())
+			if child.GetStatus() != nil && child.GetStatus().Finished() {
+				continue
+			}
+			if err := ia.CancelWithReason(child.ID(), childReason); err != nil {
+				return fmt.Errorf("failed to cancel child invocation %s: %v", child.ID(), err)
+			}
+		}
+	}
+	return nil
+}
+
 // Complete forces the completion of an invocation. This function - used by the controller - is the only way
 // to ensure that a workflow invocation turns into the COMPLETED state.
 // If the API fails to append the event to the event store, it will return an error.
@@ -107,6 +235,10 @@ func (ia *Invocation) Complete(invocationID string, output *typedvalues.TypedVal
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
 
+	output, err := offload(ia.offloader, output)
+	if err != nil {
+		return err
+	}
 	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID),
 		&events.InvocationCompleted{
 			Output:        output,
@@ -144,6 +276,69 @@ func (ia *Invocation) Fail(invocationID string, errMsg error) error {
 	return ia.es.Append(event)
 }
 
+// RecordIntent records the intent of the controller to run a task of the invocation, or to complete or fail the
+// invocation, before the action is executed. The intent remains pending until the outcome of the action is recorded.
+// The error can be a validate.Err, proto marshall error, or a fes error.
+func (ia *Invocation) RecordIntent(invocationID string, intent *types.Intent) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationIntentRecorded{
+		Intent: intent,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
+// StoreValue records the value of the key in the key-value store of the invocation. Large values are offloaded, like
+// the outputs of tasks.
+// The error can be a validate.Err, proto marshall error, or a fes error.
+func (ia *Invocation) StoreValue(invocationID string, key string, value *typedvalues.TypedValue) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if len(key) == 0 {
+		return validate.NewError("key", errors.New("key should not be empty"))
+	}
+	value, err := offload(ia.offloader, value)
+	if err != nil {
+		return err
+	}
+
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationValueStored{
+		Key:   key,
+		Value: value,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
+// MigrateWorkflow moves the invocation to another version of its workflow. The tasks of the invocation that have
+// already been started keep their results; the remaining tasks are executed according to the new version.
+// The error can be a validate.Err, proto marshall error, or a fes error.
+func (ia *Invocation) MigrateWorkflow(invocationID string, workflow *types.Workflow) error {
+	if len(invocationID) == 0 {
+		return validate.NewError("invocationID", errors.New("id should not be empty"))
+	}
+	if !workflow.GetStatus().Ready() {
+		return validate.NewError("workflow", fmt.Errorf("cannot migrate to non-ready workflow %s", workflow.ID()))
+	}
+
+	snapshot, _ := workflow.AtVersion(types.WorkflowVersionLatest)
+	event, err := fes.NewEvent(projectors.NewInvocationAggregate(invocationID), &events.InvocationWorkflowUpdated{
+		Workflow: snapshot,
+	})
+	if err != nil {
+		return err
+	}
+	return ia.es.Append(event)
+}
+
 // AddTask provides functionality to add a task to a specific invocation (instead of a workflow).
 // This allows users to modify specific invocations (see dynamic API).
 // The error can be a validate.Err, proto marshall error, or a fes error.
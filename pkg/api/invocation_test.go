@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
+)
+
+func TestInvocation_InvokeTracingContext(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("workflows-test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer closer.Close()
+	prevTracer := opentracing.GlobalTracer()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(prevTracer)
+
+	es := mem.NewBackend()
+	invocationAPI := NewInvocationAPI(es, nil)
+	spec := types.NewWorkflowInvocationSpec("wf-1", time.Now().Add(time.Minute))
+	spec.Inputs = map[string]*typedvalues.TypedValue{
+		types.InputBody: typedvalues.MustWrap("foo"),
+	}
+	span := tracer.StartSpan("test")
+	defer span.Finish()
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+
+	invocationID, err := invocationAPI.Invoke(spec, WithContext(ctx))
+	require.NoError(t, err)
+
+	// The spec of the caller is not modified.
+	assert.Empty(t, spec.GetTracingContext())
+	assert.Len(t, spec.GetInputs(), 1)
+
+	// The tracing context of the request is kept in the status of the invocation.
+	key := projectors.NewInvocationAggregate(invocationID)
+	evts, err := es.Get(key)
+	require.NoError(t, err)
+	projector := projectors.NewWorkflowInvocation()
+	base, err := projector.NewProjection(key)
+	require.NoError(t, err)
+	entity, err := projector.Project(base, evts...)
+	require.NoError(t, err)
+	wi := entity.(*types.WorkflowInvocation)
+	assert.NotEmpty(t, wi.GetStatus().GetTracingContext())
+	assert.Equal(t, fes.TraceID(span.Context()), wi.GetStatus().GetTraceId())
+}
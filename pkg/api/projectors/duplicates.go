@@ -0,0 +1,136 @@
+package projectors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+// DuplicateWindow is the replay window of events: the period after the creation of an event in which it can be
+// delivered again, because its publication is retried or because the backend redelivers it. The projectors remember
+// the events that they applied during this period to skip these duplicates. Duplicates within the events of a single
+// projection, such as when an entity is rebuilt from all of its events, are always skipped.
+const DuplicateWindow = 10 * time.Minute
+
+var duplicateEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "projector",
+	Name:      "duplicate_events_total",
+	Help:      "Number of events that were skipped, because they had already been applied to the object",
+}, []string{"type"})
+
+func init() {
+	prometheus.MustRegister(duplicateEvents)
+}
+
+// appliedEvents keeps track of the uids of the events that a projector applied to the entities that it returned. The
+// uids of an aggregate are only valid for the entity that was returned last, which is the entity that the cache passes
+// as the base of the next projection. The uids of events that were created before the duplicate window are pruned
+// periodically, so the number of tracked uids (and retained entities) is bounded by the number of events within about
+// twice the window.
+type appliedEvents struct {
+	window  time.Duration
+	mu      sync.Mutex
+	entries map[fes.Aggregate]*appliedEntry
+	pruned  time.Time
+}
+
+// appliedEntry contains the uids of the events that were applied to the entity, along with their creation time.
+type appliedEntry struct {
+	entity fes.Entity
+	uids   map[string]time.Time
+}
+
+func newAppliedEvents(window time.Duration) *appliedEvents {
+	return &appliedEvents{
+		window:  window,
+		entries: map[fes.Aggregate]*appliedEntry{},
+		pruned:  time.Now(),
+	}
+}
+
+// take returns the events that were applied to the base entity. The entry is removed until it is put back, so that
+// concurrent projections onto the same base do not share it. If the base is not the entity that was returned last for
+// its aggregate, such as a new projection, no events are assumed to have been applied to it.
+func (a *appliedEvents) take(base fes.Entity) *appliedEntry {
+	if base != nil {
+		key := fes.GetAggregate(base)
+		a.mu.Lock()
+		entry, ok := a.entries[key]
+		if ok && entry.entity == base {
+			delete(a.entries, key)
+			a.mu.Unlock()
+			return entry
+		}
+		a.mu.Unlock()
+	}
+	return &appliedEntry{
+		uids: map[string]time.Time{},
+	}
+}
+
+// put records the events that were applied to the entity that the projection returned.
+func (a *appliedEvents) put(entity fes.Entity, entry *appliedEntry) {
+	if len(entity.ID()) == 0 {
+		return
+	}
+	entry.entity = entity
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(entry.uids) > 0 {
+		a.entries[fes.GetAggregate(entity)] = entry
+	}
+
+	// Pruning visits all tracked uids, so it is done once per window rather than on every projection.
+	now := time.Now()
+	if now.Sub(a.pruned) > a.window {
+		for key, other := range a.entries {
+			if other.prune(now.Add(-a.window)); len(other.uids) == 0 {
+				delete(a.entries, key)
+			}
+		}
+		a.pruned = now
+	}
+}
+
+// isDuplicate returns whether the event has already been applied. Events without a uid, such as events that were
+// stored before uids were assigned, are never considered duplicates.
+func (e *appliedEntry) isDuplicate(event *fes.Event) bool {
+	uid := event.GetUid()
+	if len(uid) == 0 {
+		return false
+	}
+	if _, ok := e.uids[uid]; !ok {
+		return false
+	}
+	duplicateEvents.WithLabelValues(event.GetAggregate().GetType()).Inc()
+	logrus.Debugf("Skipping duplicate event %s (%s) of %s", uid, event.GetType(), event.GetAggregate().Format())
+	return true
+}
+
+// record records that the event has been applied.
+func (e *appliedEntry) record(event *fes.Event) {
+	uid := event.GetUid()
+	if len(uid) == 0 {
+		return
+	}
+	createdAt, err := ptypes.Timestamp(event.GetTimestamp())
+	if err != nil {
+		createdAt = time.Now()
+	}
+	e.uids[uid] = createdAt
+}
+
+// prune forgets the events that were created before the time.
+func (e *appliedEntry) prune(before time.Time) {
+	for uid, createdAt := range e.uids {
+		if createdAt.Before(before) {
+			delete(e.uids, uid)
+		}
+	}
+}
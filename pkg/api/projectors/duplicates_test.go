@@ -0,0 +1,47 @@
+package projectors
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_ProjectDuplicates(t *testing.T) {
+	key := NewWorkflowAggregate("wf-1")
+	created, err := fes.NewEvent(key, &events.WorkflowCreated{
+		Spec: types.NewWorkflowSpec().SetOutput("a").AddTask("a", types.NewTaskSpec("noop")),
+	})
+	require.NoError(t, err)
+	updated, err := fes.NewEvent(key, &events.WorkflowUpdated{
+		Spec: types.NewWorkflowSpec().SetOutput("b").AddTask("b", types.NewTaskSpec("noop")),
+	})
+	require.NoError(t, err)
+
+	// Duplicates within the events of a projection are skipped.
+	projector := NewWorkflow()
+	base, err := projector.NewProjection(key)
+	require.NoError(t, err)
+	wf, err := projector.Project(base, created, created)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, wf.(*types.Workflow).GetMetadata().GetGeneration())
+
+	// Duplicates of events that were applied to the base in a previous projection are skipped.
+	wf, err = projector.Project(wf, created, updated)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, wf.(*types.Workflow).GetMetadata().GetGeneration())
+	wf, err = projector.Project(wf, updated)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, wf.(*types.Workflow).GetMetadata().GetGeneration())
+
+	// Rebuilding the entity from its events does not depend on the events that were applied previously.
+	base, err = projector.NewProjection(key)
+	require.NoError(t, err)
+	wf, err = projector.Project(base, created, updated, updated)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, wf.(*types.Workflow).GetMetadata().GetGeneration())
+	assert.Equal(t, "b", wf.(*types.Workflow).GetSpec().GetOutputTask())
+}
@@ -11,11 +11,13 @@ import (
 
 type WorkflowInvocation struct {
 	taskRunProjector *TaskRun
+	applied          *appliedEvents
 }
 
 func NewWorkflowInvocation() *WorkflowInvocation {
 	return &WorkflowInvocation{
 		taskRunProjector: NewTaskRun(),
+		applied:          newAppliedEvents(DuplicateWindow),
 	}
 }
 
@@ -32,12 +34,18 @@ func (i *WorkflowInvocation) Project(base fes.Entity, events ...*fes.Event) (upd
 		invocation = invocation.Copy()
 	}
 
+	applied := i.applied.take(base)
 	for _, event := range events {
+		if applied.isDuplicate(event) {
+			continue
+		}
 		err := i.project(invocation, event)
 		if err != nil {
 			return nil, err
 		}
+		applied.record(event)
 	}
+	i.applied.put(invocation, applied)
 	return invocation, nil
 }
 
@@ -58,8 +66,10 @@ func (i *WorkflowInvocation) project(wi *types.WorkflowInvocation, event *fes.Ev
 	switch m := eventData.(type) {
 	case *events.InvocationCreated:
 		wi.Metadata = &types.ObjectMetadata{
-			Id:        event.Aggregate.Id,
-			CreatedAt: event.Timestamp,
+			Id:          event.Aggregate.Id,
+			CreatedAt:   event.Timestamp,
+			Labels:      m.GetSpec().GetLabels(),
+			Annotations: m.GetSpec().GetAnnotations(),
 		}
 		wi.Spec = m.GetSpec()
 		wi.Status = &types.WorkflowInvocationStatus{
@@ -67,22 +77,48 @@ func (i *WorkflowInvocation) project(wi *types.WorkflowInvocation, event *fes.Ev
 			Tasks:        map[string]*types.TaskInvocation{},
 			DynamicTasks: map[string]*types.Task{},
 		}
+		tracingContext := m.GetSpec().GetTracingContext()
+		if len(tracingContext) == 0 {
+			tracingContext = event.GetMetadata()
+		}
+		if spanCtx, err := fes.ExtractTracingFromEventMetadata(tracingContext); err == nil && spanCtx != nil {
+			wi.Status.TraceId = fes.TraceID(spanCtx)
+			wi.Status.TracingContext = make(map[string]string, len(tracingContext))
+			for k, v := range tracingContext {
+				wi.Status.TracingContext[k] = v
+			}
+		}
 	case *events.InvocationCanceled:
 		wi.Status.Status = types.WorkflowInvocationStatus_ABORTED
 		wi.Status.Error = m.GetError()
+		wi.Status.Intents = nil
 	case *events.InvocationCompleted:
 		wi.Status.Status = types.WorkflowInvocationStatus_SUCCEEDED
 		wi.Status.Output = m.GetOutput()
 		wi.Status.OutputHeaders = m.GetOutputHeaders()
+		wi.Status.Intents = nil
 	case *events.InvocationTaskAdded:
 		task := m.GetTask()
 		if wi.Status.DynamicTasks == nil {
 			wi.Status.DynamicTasks = map[string]*types.Task{}
 		}
 		wi.Status.DynamicTasks[task.ID()] = task
+	case *events.InvocationWorkflowUpdated:
+		wi.Spec.Workflow = m.GetWorkflow()
+		wi.Spec.WorkflowVersion = m.GetWorkflow().GetStatus().GetVersion()
 	case *events.InvocationFailed:
 		wi.Status.Error = m.GetError()
 		wi.Status.Status = types.WorkflowInvocationStatus_FAILED
+		wi.Status.Intents = nil
+	case *events.InvocationIntentRecorded:
+		intent := m.GetIntent()
+		if wi.Status.Intents == nil {
+			wi.Status.Intents = map[string]*types.Intent{}
+		}
+		wi.Status.Intents[intent.ID()] = intent
+	case *events.InvocationValueStored:
+		// The values of the key-value store are not part of the invocation; they are read from the events by the kv
+		// functions.
 	default:
 		//key := wi.Aggregate()
 		return fes.ErrUnsupportedEntityEvent.WithEvent(event)
@@ -140,6 +176,11 @@ func (i *WorkflowInvocation) applyTaskEvent(invocation *types.WorkflowInvocation
 		invocation.Status.Tasks = map[string]*types.TaskInvocation{}
 	}
 	invocation.Status.Tasks[taskID] = task
+
+	// The intent to run the task is resolved once the task has finished.
+	if task.GetStatus().Finished() {
+		delete(invocation.Status.Intents, types.NewRunIntent(taskID).ID())
+	}
 	return nil
 }
 
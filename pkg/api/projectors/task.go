@@ -55,8 +55,11 @@ func (t *TaskRun) project(taskRun *types.TaskInvocation, event *fes.Event) error
 			Generation: 1,
 		}
 		taskRun.Spec = m.GetSpec()
+		// The error of a previous attempt is kept when the task is retried.
 		taskRun.Status = &types.TaskInvocationStatus{
-			Status: types.TaskInvocationStatus_IN_PROGRESS,
+			Status:    types.TaskInvocationStatus_IN_PROGRESS,
+			Attempts:  m.GetSpec().GetAttempt() + 1,
+			LastError: taskRun.GetStatus().GetLastError(),
 		}
 	case *events.TaskSucceeded:
 		taskRun.Status.Output = m.GetResult().Output
@@ -64,10 +67,22 @@ func (t *TaskRun) project(taskRun *types.TaskInvocation, event *fes.Event) error
 		taskRun.Status.Status = types.TaskInvocationStatus_SUCCEEDED
 	case *events.TaskFailed:
 		taskRun.Status.Error = m.GetError()
+		taskRun.Status.LastError = m.GetError()
 		taskRun.Status.Status = types.TaskInvocationStatus_FAILED
 	case *events.TaskSkipped:
-		// TODO ensure that object (spec/status) is present
-		taskRun.Status.Status = types.TaskInvocationStatus_SKIPPED
+		// A skipped task has not been started, so the task run might not have been initialized yet.
+		if taskRun.Metadata == nil {
+			taskRun.Metadata = &types.ObjectMetadata{
+				Id:        event.Aggregate.Id,
+				CreatedAt: event.Timestamp,
+			}
+		}
+		if m.GetSpec() != nil {
+			taskRun.Spec = m.GetSpec()
+		}
+		taskRun.Status = &types.TaskInvocationStatus{
+			Status: types.TaskInvocationStatus_SKIPPED,
+		}
 	default:
 		key := fes.GetAggregate(taskRun)
 		return fes.ErrUnsupportedEntityEvent.WithAggregate(&key).WithEvent(event)
@@ -10,10 +10,13 @@ import (
 )
 
 type Workflow struct {
+	applied *appliedEvents
 }
 
 func NewWorkflow() *Workflow {
-	return &Workflow{}
+	return &Workflow{
+		applied: newAppliedEvents(DuplicateWindow),
+	}
 }
 
 func (w *Workflow) Project(base fes.Entity, events ...*fes.Event) (updated fes.Entity, err error) {
@@ -29,12 +32,18 @@ func (w *Workflow) Project(base fes.Entity, events ...*fes.Event) (updated fes.E
 		wf = wf.Copy()
 	}
 
+	applied := w.applied.take(base)
 	for _, event := range events {
+		if applied.isDuplicate(event) {
+			continue
+		}
 		err := w.project(wf, event)
 		if err != nil {
 			return wf, err
 		}
+		applied.record(event)
 	}
+	w.applied.put(wf, applied)
 	return wf, nil
 }
 
@@ -52,18 +61,44 @@ func (w *Workflow) project(wf *types.Workflow, event *fes.Event) error {
 	case *events.WorkflowCreated:
 		spec := m.GetSpec()
 		wf.Metadata = &types.ObjectMetadata{
-			Id:        wf.GetMetadata().GetId(),
-			Name:      spec.GetName(),
-			CreatedAt: event.GetTimestamp(),
+			Id:          wf.GetMetadata().GetId(),
+			Name:        spec.GetName(),
+			CreatedAt:   event.GetTimestamp(),
+			Labels:      spec.GetLabels(),
+			Annotations: spec.GetAnnotations(),
 		}
 		wf.Spec = spec
 		wf.Status = &types.WorkflowStatus{
-			Status: types.WorkflowStatus_QUEUED,
+			Status:  types.WorkflowStatus_QUEUED,
+			Version: 1,
+		}
+	case *events.WorkflowUpdated:
+		// Keep the previous version, so that it can still be invoked.
+		previous, _ := wf.AtVersion(types.WorkflowVersionLatest)
+		wf.History = append(wf.History, previous)
+
+		spec := m.GetSpec()
+		wf.Metadata.Name = spec.GetName()
+		wf.Metadata.Labels = spec.GetLabels()
+		wf.Metadata.Annotations = spec.GetAnnotations()
+		wf.Spec = spec
+		version := m.GetVersion()
+		if version == 0 {
+			// Updates that were recorded before the version was part of the event.
+			version = previous.GetStatus().GetVersion() + 1
+		}
+		wf.Status = &types.WorkflowStatus{
+			Status:  types.WorkflowStatus_QUEUED,
+			Version: version,
 		}
 	case *events.WorkflowParsingFailed:
 		wf.Status.Error = m.GetError()
 		wf.Status.Status = types.WorkflowStatus_FAILED
 	case *events.WorkflowParsed:
+		if m.GetVersion() != 0 && m.GetVersion() != wf.GetStatus().GetVersion() {
+			// The workflow has been updated since it was parsed.
+			break
+		}
 		wf.Status.Status = types.WorkflowStatus_READY
 		//wf.Status.Tasks = m.GetTasks()
 		for taskID, status := range m.GetTasks() {
@@ -98,6 +98,26 @@ func (s *Invocations) GetInvocation(invocationID string) (*types.WorkflowInvocat
 	return wfi, nil
 }
 
+// GetChildInvocations returns the invocations that were started by the invocation, such as nested workflows, dynamic
+// tasks and retry attempts. Only the direct children of the invocation are returned.
+func (s *Invocations) GetChildInvocations(invocationID string) ([]*types.WorkflowInvocation, error) {
+	var children []*types.WorkflowInvocation
+	for _, aggregate := range s.List() {
+		if aggregate.Type != types.TypeInvocation || aggregate.Id == invocationID {
+			continue
+		}
+		wfi, err := s.GetInvocation(aggregate.Id)
+		if err != nil {
+			return nil, err
+		}
+		spec := wfi.GetSpec()
+		if spec.GetParentId() == invocationID || spec.GetCallerId() == invocationID {
+			children = append(children, wfi)
+		}
+	}
+	return children, nil
+}
+
 // GetInvocationSubscription returns a subscription to the updates of the invocation cache.
 // Returns nil if the cache does not support pubsub.
 //
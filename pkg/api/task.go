@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
@@ -10,26 +11,60 @@ import (
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/schema"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
+var (
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "task",
+		Name:      "duration_seconds",
+		Help:      "Duration of the execution of the function of a task",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 16),
+	}, []string{"workflow", "task", "fnenv"})
+
+	taskFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "task",
+		Name:      "failures_total",
+		Help:      "Number of task invocations that failed",
+	}, []string{"workflow", "task", "fnenv"})
+
+	heartbeatTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "task",
+		Name:      "heartbeat_timeouts_total",
+		Help:      "Number of task invocations that failed, because the heartbeats of their function stopped",
+	}, []string{"workflow", "task", "fnenv"})
+)
+
+func init() {
+	prometheus.MustRegister(taskDuration, taskFailures, heartbeatTimeouts)
+}
+
 // Task contains the API functionality for controlling the lifecycle of individual tasks.
 // This includes starting, stopping and completing tasks.
 type Task struct {
 	runtime    map[string]fnenv.Runtime
 	es         fes.Backend
 	dynamicAPI *Dynamic
+	offloader  ValueOffloader
 }
 
-// NewTaskAPI creates the Task API.
-func NewTaskAPI(runtime map[string]fnenv.Runtime, esClient fes.Backend, api *Dynamic) *Task {
+// NewTaskAPI creates the Task API. The offloader is optional; if it is provided, large task outputs are offloaded
+// before they are appended to the event store.
+func NewTaskAPI(runtime map[string]fnenv.Runtime, esClient fes.Backend, api *Dynamic, offloader ValueOffloader) *Task {
 	return &Task{
 		runtime:    runtime,
 		es:         esClient,
 		dynamicAPI: api,
+		offloader:  offloader,
 	}
 }
 
@@ -62,16 +97,40 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 	}
 
 	aggregate := projectors.NewInvocationAggregate(spec.InvocationId)
+	metricLabels := []string{spec.GetWorkflowId(), taskID, spec.GetFnRef().GetRuntime()}
+	var succeeded bool
+	defer func() {
+		if !succeeded {
+			taskFailures.WithLabelValues(metricLabels...).Inc()
+		}
+	}()
+
+	// Record that the task has started before its function is invoked, so that a task that is interrupted by a restart
+	// of the workflow engine can be recovered. The attempt is recorded before the inputs are validated, so that invalid
+	// inputs also count towards the retries of the task.
 	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskStarted{
 		Spec: spec,
 	})
-	event.Parent = &aggregate
 	if err != nil {
 		return nil, err
 	}
+	event.Parent = &aggregate
+	if err := ap.es.Append(event); err != nil {
+		return nil, err
+	}
+
+	// Ensure that the inputs conform to their schemas before invoking the function.
+	if err := validateInputSchemas(spec); err != nil {
+		log.Infof("Task inputs are invalid: %v", err)
+		if esErr := ap.Fail(spec.InvocationId, taskID, err.Error()); esErr != nil {
+			return nil, esErr
+		}
+		return nil, err
+	}
 
-	fnResult, err := ap.runtime[spec.FnRef.Runtime].Invoke(spec, fnenv.WithContext(cfg.ctx),
-		fnenv.AwaitWorkflow(cfg.awaitWorkflow))
+	start := time.Now()
+	fnResult, err := ap.invokeRuntime(spec, cfg, metricLabels)
+	taskDuration.WithLabelValues(metricLabels...).Observe(time.Since(start).Seconds())
 	if fnResult == nil && err == nil {
 		err = errors.New("function crashed")
 	}
@@ -107,8 +166,44 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 	}
 
 	if fnResult.Status == types.TaskInvocationStatus_SUCCEEDED {
+		if err := validateOutputSchema(spec, fnResult); err != nil {
+			log.Infof("Task output is invalid: %v", err)
+			if esErr := ap.Fail(spec.InvocationId, taskID, err.Error()); esErr != nil {
+				return nil, esErr
+			}
+			return nil, err
+		}
+		result := *fnResult
+		// Control flow outputs are not limited or offloaded, as they need to remain recognizable as control flow for
+		// the resolution of the output of the task to the output of its dynamic task.
+		if !controlflow.IsControlFlow(fnResult.Output) {
+			var put func(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error)
+			if ap.offloader != nil {
+				put = ap.offloader.Put
+			}
+			output, err := fnenv.LimitOutput(fnResult.Output, put)
+			if err != nil {
+				log.Infof("Task output is too large: %v", err)
+				if esErr := ap.Fail(spec.InvocationId, taskID, err.Error()); esErr != nil {
+					return nil, esErr
+				}
+				return nil, err
+			}
+			fnResult.Output = output
+			result.Output, err = offload(ap.offloader, fnResult.Output)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if err := fnenv.CheckPayloadSize("output", result.Output, result.OutputHeaders); err != nil {
+			log.Infof("Task output is too large: %v", err)
+			if esErr := ap.Fail(spec.InvocationId, taskID, err.Error()); esErr != nil {
+				return nil, esErr
+			}
+			return nil, err
+		}
 		event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskSucceeded{
-			Result: fnResult,
+			Result: &result,
 		})
 		if err != nil {
 			return nil, err
@@ -116,17 +211,69 @@ func (ap *Task) Invoke(spec *types.TaskInvocationSpec, opts ...CallOption) (*typ
 		event.Parent = &aggregate
 		err = ap.es.Append(event)
 	} else {
-		err = ap.Fail(spec.InvocationId, taskID, fnResult.Error.GetMessage())
+		// The error is recorded as is, so that its status code can be matched against the retryable status codes.
+		err = ap.fail(spec.InvocationId, taskID, &types.Error{
+			Message:    fnResult.GetError().GetMessage(),
+			StatusCode: fnResult.GetError().GetStatusCode(),
+		})
 	}
 	if err != nil {
 		return nil, err
 	}
+	succeeded = fnResult.Status == types.TaskInvocationStatus_SUCCEEDED
 	return task, nil
 }
 
+// invokeRuntime invokes the function of the task with its runtime. If the runtime reports heartbeats for the
+// invocation, and these stop for longer than the fnenv.HeartbeatTimeout, the invocation is canceled and an error
+// wrapping fnenv.ErrHeartbeatTimeout is returned without waiting for the runtime to return.
+func (ap *Task) invokeRuntime(spec *types.TaskInvocationSpec, cfg *CallConfig,
+	metricLabels []string) (*types.TaskInvocationStatus, error) {
+	runtime, ok := ap.runtime[spec.FnRef.Runtime]
+	if !ok {
+		return nil, fmt.Errorf("unknown runtime: %s", spec.FnRef.Runtime)
+	}
+	if fnenv.HeartbeatTimeout <= 0 {
+		return runtime.Invoke(spec, fnenv.WithContext(cfg.ctx), fnenv.AwaitWorkflow(cfg.awaitWorkflow))
+	}
+
+	ctx, cancel := context.WithCancel(cfg.ctx)
+	defer cancel()
+	heartbeat := fnenv.NewHeartbeat(fnenv.HeartbeatTimeout)
+	dead := heartbeat.Watch(ctx)
+
+	type result struct {
+		status *types.TaskInvocationStatus
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: fmt.Errorf("function runtime panicked: %v", r)}
+			}
+		}()
+		status, err := runtime.Invoke(spec, fnenv.WithContext(ctx), fnenv.AwaitWorkflow(cfg.awaitWorkflow),
+			fnenv.WithHeartbeat(heartbeat.Beat))
+		done <- result{status, err}
+	}()
+	select {
+	case r := <-done:
+		return r.status, r.err
+	case <-dead:
+		heartbeatTimeouts.WithLabelValues(metricLabels...).Inc()
+		return nil, fmt.Errorf("%v: no heartbeat of the function since %v", fnenv.ErrHeartbeatTimeout,
+			heartbeat.Last().Format(time.RFC3339))
+	}
+}
+
 // Fail forces the failure of a task. This turns the state of a task into FAILED.
 // If the API fails to append the event to the event store, it will return an error.
 func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
+	return ap.fail(invocationID, taskID, &types.Error{Message: errMsg})
+}
+
+func (ap *Task) fail(invocationID string, taskID string, taskErr *types.Error) error {
 	if len(invocationID) == 0 {
 		return validate.NewError("invocationID", errors.New("id should not be empty"))
 	}
@@ -135,7 +282,7 @@ func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
 	}
 
 	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(taskID), &events.TaskFailed{
-		Error: &types.Error{Message: errMsg},
+		Error: taskErr,
 	})
 	if err != nil {
 		return err
@@ -145,6 +292,23 @@ func (ap *Task) Fail(invocationID string, taskID string, errMsg string) error {
 	return ap.es.Append(event)
 }
 
+// Skip skips the task invocation instead of running it, such as when the condition of its task does not hold. This
+// turns the state of the task into SKIPPED, which, unlike a failure, does not fail the workflow invocation.
+func (ap *Task) Skip(spec *types.TaskInvocationSpec) error {
+	if err := validate.TaskInvocationSpec(spec); err != nil {
+		return err
+	}
+	event, err := fes.NewEvent(projectors.NewTaskRunAggregate(spec.GetTaskId()), &events.TaskSkipped{
+		Spec: spec,
+	})
+	if err != nil {
+		return err
+	}
+	aggregate := projectors.NewInvocationAggregate(spec.GetInvocationId())
+	event.Parent = &aggregate
+	return ap.es.Append(event)
+}
+
 func (ap *Task) Prepare(spec *types.TaskInvocationSpec, expectedAt time.Time, opts ...CallOption) error {
 	runtime, ok := ap.runtime[spec.GetFnRef().GetRuntime()]
 	if !ok {
@@ -159,3 +323,46 @@ func (ap *Task) Prepare(spec *types.TaskInvocationSpec, expectedAt time.Time, op
 
 	return preparer.Prepare(*spec.FnRef, expectedAt)
 }
+
+// validateInputSchemas validates the inputs of the task invocation against the schemas that the task declares, or
+// otherwise against the schemas that the inputs are tagged with.
+func validateInputSchemas(spec *types.TaskInvocationSpec) error {
+	declared := spec.GetTask().GetSpec().GetInputSchemas()
+	for key, ref := range declared {
+		if _, ok := spec.GetInputs()[key]; !ok {
+			return fmt.Errorf("input '%s' is missing (expected schema %s)", key, ref)
+		}
+	}
+	for key, input := range spec.GetInputs() {
+		ref, ok := declared[key]
+		if !ok {
+			ref = schema.RefOf(input)
+		}
+		if len(ref) == 0 {
+			continue
+		}
+		if err := schema.DefaultValidator.Validate(ref, input); err != nil {
+			return fmt.Errorf("invalid input '%s': %v", key, err)
+		}
+	}
+	return nil
+}
+
+// validateOutputSchema validates the output of the task against the schema that the task declares, or otherwise
+// against the schema that the output is tagged with. If the task declares a schema, the output is tagged with it.
+func validateOutputSchema(spec *types.TaskInvocationSpec, status *types.TaskInvocationStatus) error {
+	ref := spec.GetTask().GetSpec().GetOutputSchema()
+	if len(ref) == 0 {
+		ref = schema.RefOf(status.GetOutput())
+	}
+	if len(ref) == 0 {
+		return nil
+	}
+	if err := schema.DefaultValidator.Validate(ref, status.GetOutput()); err != nil {
+		return fmt.Errorf("invalid output: %v", err)
+	}
+	if status.Output != nil {
+		schema.Tag(status.Output, ref)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/blobstore"
+	memstore "github.com/fission/fission-workflows/pkg/blobstore/mem"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockRuntime struct {
+	output *typedvalues.TypedValue
+}
+
+func (m *mockRuntime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	return &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: m.output,
+	}, nil
+}
+
+func TestTask_InvokeOffload(t *testing.T) {
+	threshold := 1024
+	large := strings.Repeat("a", 2*threshold)
+	for name, output := range map[string]*typedvalues.TypedValue{
+		"value": typedvalues.MustWrap(large),
+		"controlflow": typedvalues.MustWrap(&types.TaskSpec{
+			FunctionRef: "noop",
+			Inputs: map[string]*typedvalues.TypedValue{
+				types.InputMain: typedvalues.MustWrap(large),
+			},
+		}),
+	} {
+		es := mem.NewBackend()
+		var offloader ValueOffloader = blobstore.NewOffloader(memstore.NewStore(), threshold)
+		invocationAPI := NewInvocationAPI(es, offloader)
+		dynamicAPI := NewDynamicApi(NewWorkflowAPI(es, nil), invocationAPI)
+		taskAPI := NewTaskAPI(map[string]fnenv.Runtime{
+			"mock": &mockRuntime{output: output},
+		}, es, dynamicAPI, offloader)
+
+		spec := &types.TaskInvocationSpec{
+			InvocationId: "wi-1",
+			TaskId:       "task",
+			FnRef:        &types.FnRef{Runtime: "mock", ID: "fn"},
+			Task:         types.NewTask("task", "mock://fn"),
+		}
+		_, err := taskAPI.Invoke(spec)
+		require.NoError(t, err, name)
+
+		result := taskResult(t, es, "wi-1")
+		require.NotNil(t, result, name)
+		if name == "controlflow" {
+			// Control flow outputs are kept as is, so that the task is still recognized as dynamic.
+			assert.True(t, controlflow.IsControlFlow(result.Output), name)
+		} else {
+			assert.True(t, typedvalues.IsReference(result.Output), name)
+		}
+	}
+}
+
+// taskResult returns the result of the last TaskSucceeded event of the invocation.
+func taskResult(t *testing.T, es fes.Backend, invocationID string) *types.TaskInvocationStatus {
+	evts, err := es.Get(projectors.NewInvocationAggregate(invocationID))
+	require.NoError(t, err)
+	var result *types.TaskInvocationStatus
+	for _, event := range evts {
+		if event.Type != string(events.EventTaskSucceeded) {
+			continue
+		}
+		msg, err := fes.ParseEventData(event)
+		require.NoError(t, err)
+		result = msg.(*events.TaskSucceeded).GetResult()
+	}
+	return result
+}
@@ -3,12 +3,17 @@ package api
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"sync"
 
 	"github.com/fission/fission-workflows/pkg/api/events"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/expr"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/golang/protobuf/ptypes"
@@ -16,6 +21,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// updateLock serializes the updates of workflows, so that each update is assigned the next version of the workflow.
+var updateLock sync.Mutex
+
 // Workflow contains the API functionality for controlling workflow definitions.
 // This includes creating and parsing workflows.
 type Workflow struct {
@@ -70,6 +78,82 @@ func (wa *Workflow) Create(workflow *types.WorkflowSpec, opts ...CallOption) (st
 	return id, nil
 }
 
+// Update replaces the spec of the workflow, creating a new version of the workflow. The previous versions of the
+// workflow remain available; running invocations continue with the version that they were started with.
+// The function either returns the version that the update created or an error.
+// The error can be a validate.Err, proto marshall error, or a fes error.
+func (wa *Workflow) Update(workflowID string, workflow *types.WorkflowSpec, opts ...CallOption) (int64, error) {
+	cfg := parseCallOptions(opts)
+	if len(workflowID) == 0 {
+		return 0, validate.NewError("workflowID", errors.New("id should not be empty"))
+	}
+	if err := validate.WorkflowSpec(workflow); err != nil {
+		return 0, err
+	}
+
+	// The version is determined from the events of the workflow rather than its projection, which might not include
+	// the latest updates yet.
+	updateLock.Lock()
+	defer updateLock.Unlock()
+	version, err := wa.latestVersion(workflowID)
+	if err != nil {
+		return 0, err
+	}
+	version++
+
+	event, err := fes.NewEvent(projectors.NewWorkflowAggregate(workflowID), &events.WorkflowUpdated{
+		Spec:    workflow,
+		Version: version,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// If part of a span, add trace metadata to the event.
+	span := opentracing.SpanFromContext(cfg.ctx)
+	if span != nil {
+		err = opentracing.GlobalTracer().Inject(span.Context(), opentracing.TextMap,
+			opentracing.TextMapCarrier(event.Metadata))
+		if err != nil {
+			logrus.Warnf("Failed to inject tracer context into event: %v", err)
+		}
+	}
+	if err := wa.es.Append(event); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// latestVersion returns the latest version of the workflow according to its events.
+func (wa *Workflow) latestVersion(workflowID string) (int64, error) {
+	key := projectors.NewWorkflowAggregate(workflowID)
+	evts, err := wa.es.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	var version int64
+	for _, event := range evts {
+		switch event.GetType() {
+		case events.EventWorkflowCreated:
+			version = 1
+		case events.EventWorkflowUpdated:
+			data, err := fes.ParseEventData(event)
+			if err != nil {
+				return 0, err
+			}
+			if v := data.(*events.WorkflowUpdated).GetVersion(); v > 0 {
+				version = v
+			} else {
+				version++
+			}
+		}
+	}
+	if version == 0 {
+		return 0, fes.ErrEntityNotFound.WithAggregate(&key)
+	}
+	return version, nil
+}
+
 // Delete marks a workflow as deleted, making it unavailable to any future interactions.
 // This also means that subsequent invocations for this workflow will fail.
 // If the API fails to append the event to the event store, it will return an error.
@@ -86,6 +170,117 @@ func (wa *Workflow) Delete(workflowID string) error {
 	return wa.es.Append(event)
 }
 
+// Validate runs the complete validation of the workflow spec, without creating the workflow.
+//
+// Besides the checks of validate.WorkflowSpec, it checks that the expressions in the tasks and outputs parse, that the
+// expressions of the tasks only refer to the output of their data dependencies, and it resolves the functions of the
+// tasks in a dry-run, unless the API has no resolver. The function either returns nil, or
+// a validate.Error of which each issue is a validate.Diagnostic.
+func (wa *Workflow) Validate(spec *types.WorkflowSpec) error {
+	err := validate.WorkflowSpec(spec)
+	if spec == nil {
+		return err
+	}
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	resolver := wa.resolver
+	if nr, ok := resolver.(fnenv.NamespacedResolver); ok && len(spec.Namespace) > 0 {
+		resolver = nr.InNamespace(spec.Namespace)
+	}
+	var taskIDs []string
+	for taskID := range spec.Tasks {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+	resolveErrs := map[string]error{}
+	for _, taskID := range taskIDs {
+		task := spec.Tasks[taskID]
+		if task == nil {
+			continue
+		}
+
+		// Resolve the function of the task, without recording the result.
+		if resolver != nil && len(task.FunctionRef) > 0 {
+			resolveErr, ok := resolveErrs[task.FunctionRef]
+			if !ok {
+				_, resolveErr = resolver.Resolve(task.FunctionRef)
+				resolveErrs[task.FunctionRef] = resolveErr
+			}
+			if resolveErr != nil {
+				errs = append(errs, validate.Diagnostic{
+					Reason: validate.ErrUnresolvedFunction,
+					Detail: fmt.Sprintf("'%s' (%v)", task.FunctionRef, resolveErr),
+					TaskID: taskID,
+					Field:  fmt.Sprintf("tasks.%s.functionRef", taskID),
+				})
+			}
+		}
+
+		// Check the syntax of the expressions of the task.
+		expressions := map[string]*typedvalues.TypedValue{
+			"output":        task.Output,
+			"outputHeaders": task.OutputHeaders,
+			"when":          task.When,
+		}
+		fields := []string{"output", "outputHeaders", "when"}
+		for key, input := range task.Inputs {
+			expressions["inputs."+key] = input
+			fields = append(fields, "inputs."+key)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			if expressions[field] == nil {
+				continue
+			}
+			if err := expr.Parse(expressions[field]); err != nil {
+				errs = append(errs, validate.Diagnostic{
+					Reason: validate.ErrInvalidExpression,
+					Detail: err.Error(),
+					TaskID: taskID,
+					Field:  fmt.Sprintf("tasks.%s.%s", taskID, field),
+				})
+			}
+		}
+
+		// Check that the expressions only refer to the output of the data dependencies of the task.
+		for _, ref := range graph.DanglingReferences(taskID, task, spec.Tasks) {
+			detail := fmt.Sprintf("'%s'", ref.Target)
+			if len(ref.Artifact) > 0 {
+				detail = fmt.Sprintf("'%s.%s'", ref.Target, ref.Artifact)
+			}
+			errs = append(errs, validate.Diagnostic{
+				Reason: validate.ErrDanglingReference,
+				Detail: detail,
+				TaskID: taskID,
+				Field:  fmt.Sprintf("tasks.%s.%s", taskID, ref.Field),
+			})
+		}
+	}
+
+	// Check the syntax of the expressions of the named outputs.
+	var outputKeys []string
+	for key := range spec.Outputs {
+		outputKeys = append(outputKeys, key)
+	}
+	sort.Strings(outputKeys)
+	for _, key := range outputKeys {
+		if err := expr.Parse(spec.Outputs[key]); err != nil {
+			errs = append(errs, validate.Diagnostic{
+				Reason: validate.ErrInvalidExpression,
+				Detail: err.Error(),
+				Field:  "outputs." + key,
+			})
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return validate.NewError("WorkflowSpec", errs...)
+}
+
 // Parse processes the workflow to resolve any ambiguity.
 // Currently, this means that all the function references are resolved to function identifiers. For convenience
 // this function returns the new WorkflowStatus. If the API fails to append the event to the event store,
@@ -95,22 +290,38 @@ func (wa *Workflow) Parse(workflow *types.Workflow) (map[string]*types.TaskStatu
 		return nil, err
 	}
 
-	resolvedFns, err := fnenv.ResolveTasks(wa.resolver, workflow.Spec.Tasks)
+	resolver := wa.resolver
+	if nr, ok := resolver.(fnenv.NamespacedResolver); ok && len(workflow.Spec.Namespace) > 0 {
+		resolver = nr.InNamespace(workflow.Spec.Namespace)
+	}
+	resolvedFns, err := fnenv.ResolveTasks(resolver, workflow.Spec.Tasks)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve tasks in workflow: %v", err)
 	}
 
+	hintResolver, _ := resolver.(fnenv.HintResolver)
 	taskStatuses := map[string]*types.TaskStatus{}
 	for id, t := range workflow.Spec.Tasks {
+		fnRef := resolvedFns[t.FunctionRef]
+		var hints *types.FnHints
+		if hintResolver != nil && fnRef != nil {
+			hints, err = hintResolver.ResolveHints(*fnRef)
+			if err != nil {
+				// Hints are optional; the task falls back to the defaults of the workflow engine.
+				logrus.Warnf("Failed to resolve hints of function %s: %v", fnRef.Format(), err)
+			}
+		}
 		taskStatuses[id] = &types.TaskStatus{
 			UpdatedAt: ptypes.TimestampNow(),
-			FnRef:     resolvedFns[t.FunctionRef],
+			FnRef:     fnRef,
 			Status:    types.TaskStatus_READY,
+			FnHints:   hints,
 		}
 	}
 
 	event, err := fes.NewEvent(projectors.NewWorkflowAggregate(workflow.ID()), &events.WorkflowParsed{
-		Tasks: taskStatuses,
+		Tasks:   taskStatuses,
+		Version: workflow.GetStatus().GetVersion(),
 	})
 	if err != nil {
 		return nil, err
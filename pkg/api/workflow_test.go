@@ -0,0 +1,44 @@
+package api
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_UpdateVersion(t *testing.T) {
+	wfAPI := NewWorkflowAPI(mem.NewBackend(), nil)
+	spec := types.NewWorkflowSpec().
+		SetOutput("a").
+		AddTask("a", types.NewTaskSpec("noop"))
+	_, err := wfAPI.Update("unknown", spec)
+	assert.True(t, fes.ErrEntityNotFound.Is(err), "%v", err)
+
+	wfID, err := wfAPI.Create(spec)
+	require.NoError(t, err)
+
+	// Concurrent updates are each assigned a distinct version.
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var versions []int
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			version, err := wfAPI.Update(wfID, spec)
+			assert.NoError(t, err)
+			mu.Lock()
+			versions = append(versions, int(version))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	sort.Ints(versions)
+	assert.Equal(t, []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, versions)
+}
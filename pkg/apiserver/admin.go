@@ -1,15 +1,49 @@
 package apiserver
 
 import (
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/version"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const StatusOK = "OK!"
 
+// AuditQuerier queries the audit log of the state-changing API calls.
+type AuditQuerier interface {
+	Query(query *AuditLogQuery) ([]*AuditEvent, error)
+}
+
 // Admin is responsible for all administrative functions related to managing the workflow engine.
 type Admin struct {
+	audit     AuditQuerier
+	backend   fes.Backend
+	executors map[string]*executor.LocalExecutor
+	systems   map[string]*ctrl.System
+	caches    map[string]fes.CacheRebuilder
+}
+
+// NewAdmin creates the admin API. If audit is nil, the audit log is not available. If backend is nil, invocations
+// cannot be restored. The executors, indexed by name, can be resized through the API. The quarantined controllers of
+// the control systems, indexed by name, can be listed and released through the API. The projections in the caches,
+// indexed by the aggregate type, can be rebuilt through the API.
+func NewAdmin(audit AuditQuerier, backend fes.Backend, executors map[string]*executor.LocalExecutor,
+	systems map[string]*ctrl.System, caches map[string]fes.CacheRebuilder) *Admin {
+	return &Admin{
+		audit:     audit,
+		backend:   backend,
+		executors: executors,
+		systems:   systems,
+		caches:    caches,
+	}
 }
 
 func (as *Admin) Status(ctx context.Context, _ *empty.Empty) (*Health, error) {
@@ -18,7 +52,208 @@ func (as *Admin) Status(ctx context.Context, _ *empty.Empty) (*Health, error) {
 	}, nil
 }
 
+func (as *Admin) Ready(ctx context.Context, _ *empty.Empty) (*Health, error) {
+	if err := checkAvailable(as.backend); err != nil {
+		return nil, err
+	}
+	return &Health{
+		Status: StatusOK,
+	}, nil
+}
+
 func (as *Admin) Version(ctx context.Context, _ *empty.Empty) (*version.Info, error) {
 	v := version.VersionInfo()
 	return &v, nil
 }
+
+func (as *Admin) AuditLog(ctx context.Context, query *AuditLogQuery) (*AuditLog, error) {
+	if as.audit == nil {
+		return nil, status.Error(codes.Unimplemented, "audit logging is not enabled")
+	}
+	events, err := as.audit.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{
+		Events: events,
+	}, nil
+}
+
+func (as *Admin) Restore(ctx context.Context, req *ObjectEvents) (*empty.Empty, error) {
+	if as.backend == nil {
+		return nil, status.Error(codes.Unimplemented, "restoring invocations is not supported")
+	}
+	if len(req.GetMetadata().GetId()) == 0 || len(req.GetEvents()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "the id and the events of the invocation are required")
+	}
+	aggregate := projectors.NewInvocationAggregate(req.GetMetadata().GetId())
+	for _, event := range req.GetEvents() {
+		// The events of the tasks are stored with the events of their invocation.
+		key := event.GetAggregate()
+		if event.GetParent() != nil {
+			key = event.GetParent()
+		}
+		if key == nil || *key != aggregate {
+			return nil, status.Errorf(codes.InvalidArgument, "event %s does not belong to invocation %s",
+				event.GetId(), aggregate.Id)
+		}
+	}
+
+	existing, err := as.backend.Get(aggregate)
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	if len(existing) > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "invocation %s already exists", aggregate.Id)
+	}
+	for _, event := range req.GetEvents() {
+		if err := as.backend.Append(event); err != nil {
+			return nil, toErrorStatus(err)
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+func (as *Admin) ListExecutors(ctx context.Context, _ *empty.Empty) (*Executors, error) {
+	result := &Executors{}
+	for _, ex := range as.executors {
+		result.Executors = append(result.Executors, executorConfig(ex))
+	}
+	sort.Slice(result.Executors, func(i, j int) bool {
+		return result.Executors[i].Name < result.Executors[j].Name
+	})
+	return result, nil
+}
+
+func (as *Admin) UpdateExecutor(ctx context.Context, req *ExecutorConfig) (*ExecutorConfig, error) {
+	ex, ok := as.executors[req.GetName()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "executor %s not found", req.GetName())
+	}
+	if req.GetParallelism() < 0 || req.GetQueueSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "the parallelism and queue size should not be negative")
+	}
+	if req.GetParallelism() > 0 {
+		if err := ex.SetParallelism(int(req.GetParallelism())); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if req.GetQueueSize() > 0 {
+		if err := ex.SetQueueSize(int(req.GetQueueSize())); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	return executorConfig(ex), nil
+}
+
+func executorConfig(ex *executor.LocalExecutor) *ExecutorConfig {
+	return &ExecutorConfig{
+		Name:        ex.Name(),
+		Parallelism: int32(ex.Parallelism()),
+		QueueSize:   int32(ex.QueueSize()),
+		Workers:     int32(ex.Workers()),
+		Queued:      int32(ex.QueueLen()),
+	}
+}
+
+func (as *Admin) ListQuarantined(ctx context.Context, _ *empty.Empty) (*QuarantinedControllers, error) {
+	result := &QuarantinedControllers{}
+	for name, system := range as.systems {
+		for _, q := range system.Quarantined() {
+			result.Controllers = append(result.Controllers, quarantinedController(name, q))
+		}
+	}
+	sort.Slice(result.Controllers, func(i, j int) bool {
+		a, b := result.Controllers[i], result.Controllers[j]
+		if a.System != b.System {
+			return a.System < b.System
+		}
+		return a.Key < b.Key
+	})
+	return result, nil
+}
+
+func (as *Admin) ReleaseQuarantined(ctx context.Context, req *QuarantinedControllerRef) (*QuarantinedController,
+	error) {
+	system, ok := as.systems[req.GetSystem()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "control system %s not found", req.GetSystem())
+	}
+	q, ok := system.Release(req.GetKey())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "controller %s is not quarantined in %s", req.GetKey(),
+			req.GetSystem())
+	}
+	return quarantinedController(req.GetSystem(), q), nil
+}
+
+func quarantinedController(system string, q ctrl.Quarantined) *QuarantinedController {
+	since, _ := ptypes.TimestampProto(q.Since)
+	return &QuarantinedController{
+		System:    system,
+		Key:       q.Key,
+		Since:     since,
+		Failures:  int32(q.Failures),
+		LastError: q.LastError,
+	}
+}
+
+func (as *Admin) RebuildProjection(ctx context.Context, req *ProjectionRef) (*empty.Empty, error) {
+	c, ok := as.caches[req.GetType()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "projections of type %s cannot be rebuilt", req.GetType())
+	}
+	if _, err := c.Rebuild(fes.Aggregate{Type: req.GetType(), Id: req.GetId()}); err != nil {
+		if fes.ErrEntityNotFound.Is(err) {
+			return nil, status.Errorf(codes.NotFound, "%s %s has no events", req.GetType(), req.GetId())
+		}
+		return nil, toErrorStatus(err)
+	}
+	return &empty.Empty{}, nil
+}
+
+func (as *Admin) ResyncProjections(ctx context.Context, req *ResyncProjectionsRequest) (*ResyncProjectionsResult,
+	error) {
+	aggregateTypes := []string{req.GetType()}
+	if len(req.GetType()) == 0 {
+		aggregateTypes = nil
+		for aggregateType := range as.caches {
+			aggregateTypes = append(aggregateTypes, aggregateType)
+		}
+		sort.Strings(aggregateTypes)
+	}
+
+	result := &ResyncProjectionsResult{}
+	for _, aggregateType := range aggregateTypes {
+		c, ok := as.caches[aggregateType]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "projections of type %s cannot be resynced",
+				aggregateType)
+		}
+		resynced, err := c.Resync(func(key fes.Aggregate) bool {
+			return key.Type == aggregateType
+		})
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		result.Rebuilt += int32(resynced.Rebuilt)
+		result.Removed += int32(resynced.Removed)
+		for key, err := range resynced.Failed {
+			result.Failed = append(result.Failed, &ProjectionError{
+				Ref: &ProjectionRef{
+					Type: key.Type,
+					Id:   key.Id,
+				},
+				Error: err.Error(),
+			})
+		}
+	}
+	sort.Slice(result.Failed, func(i, j int) bool {
+		a, b := result.Failed[i].Ref, result.Failed[j].Ref
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Id < b.Id
+	})
+	return result, nil
+}
@@ -14,13 +14,24 @@ packages that are responsible for the business logic, such as `api`.
 package apiserver
 
 import (
+	"net"
+
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// ForwardedForHeader is the metadata key in which the HTTP gateway passes the address of the client of a request.
+const ForwardedForHeader = "x-forwarded-for"
+
 type Empty = empty.Empty
 
 func toErrorStatus(err error) error {
@@ -33,3 +44,65 @@ func toErrorStatus(err error) error {
 		return err
 	}
 }
+
+// checkAvailable returns an Unavailable error if the backend reports that it is temporarily unavailable, such as while
+// the events are buffered until the event store can be reached again.
+func checkAvailable(backend fes.Backend) error {
+	if checker, ok := backend.(fes.AvailabilityChecker); ok && !checker.Available() {
+		return status.Error(codes.Unavailable, "the event store is unavailable; new invocations are paused until it "+
+			"has recovered")
+	}
+	return nil
+}
+
+// authorize checks whether the caller of the request has the permission for the workflow with the spec. Without an
+// authorizer, all callers are allowed.
+func authorize(ctx context.Context, authorizer auth.Authorizer, permission auth.Permission, workflowID string,
+	spec *types.WorkflowSpec) error {
+	if authorizer == nil {
+		return nil
+	}
+	err := authorizer.Authorize(ctx, permission, auth.Resource{
+		Namespace:    spec.GetNamespace(),
+		WorkflowID:   workflowID,
+		WorkflowName: spec.GetName(),
+	})
+	switch err {
+	case nil:
+		return nil
+	case auth.ErrUnauthenticated:
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+}
+
+// ForwardedFor returns the client address that the HTTP gateway forwarded with the call. The forwarded address is only
+// trusted if the call comes from the loopback interface, on which the gateway calls the gRPC server; any other client
+// could set the header to forge its address.
+func ForwardedFor(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil || !IsLoopback(HostIP(p.Addr.String())) {
+		return "", false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md[ForwardedForHeader]) == 0 {
+		return "", false
+	}
+	return md[ForwardedForHeader][0], true
+}
+
+// HostIP returns the host of the address, or the address itself if it does not have a port.
+func HostIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// IsLoopback returns whether the ip is a loopback address.
+func IsLoopback(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsLoopback()
+}
@@ -8,12 +8,43 @@ It is generated from these files:
 	pkg/apiserver/apiserver.proto
 
 It has these top-level messages:
+	VisualizeRequest
+	Visualization
+	WorkflowListQuery
 	WorkflowList
+	WorkflowUpdateRequest
+	WorkflowPatch
+	WorkflowValidationResult
+	ValidationDiagnostic
+	WorkflowWatchQuery
+	WorkflowUpdate
+	InvokeManyRequest
+	InvocationInputs
+	InvokeManyResponse
+	InvokeManyResult
+	CancelRequest
 	AddTaskRequest
+	TaskLogsRequest
+	TaskLogs
+	LogEntry
 	InvocationListQuery
 	WorkflowInvocationList
+	InvocationWatchQuery
+	InvocationUpdate
 	ObjectEvents
 	Health
+	ExecutorConfig
+	Executors
+	QuarantinedController
+	QuarantinedControllers
+	QuarantinedControllerRef
+	ProjectionRef
+	ResyncProjectionsRequest
+	ResyncProjectionsResult
+	ProjectionError
+	AuditEvent
+	AuditLogQuery
+	AuditLog
 */
 package apiserver
 
@@ -21,9 +52,12 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import fission_workflows_types1 "github.com/fission/fission-workflows/pkg/types"
+import fission_workflows_types "github.com/fission/fission-workflows/pkg/types/typedvalues"
 import fission_workflows_version "github.com/fission/fission-workflows/pkg/version"
 import fission_workflows_eventstore "github.com/fission/fission-workflows/pkg/fes"
+import fission_workflows_scheduler "github.com/fission/fission-workflows/pkg/scheduler"
 import google_protobuf3 "github.com/golang/protobuf/ptypes/empty"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
 import _ "google.golang.org/genproto/googleapis/api/annotations"
 
 import (
@@ -42,125 +76,1164 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// RunningInvocationPolicy determines what happens to the running invocations of a workflow when it is updated.
+type RunningInvocationPolicy int32
+
+const (
+	// CONTINUE lets the running invocations continue with the version that they were started with.
+	RunningInvocationPolicy_CONTINUE RunningInvocationPolicy = 0
+	// FAIL fails the running invocations.
+	RunningInvocationPolicy_FAIL RunningInvocationPolicy = 1
+	// MIGRATE moves the running invocations to the new version, once it is ready. Tasks that have already been started
+	// are not executed again; tasks that were added are executed once their dependencies have completed.
+	RunningInvocationPolicy_MIGRATE RunningInvocationPolicy = 2
+)
+
+var RunningInvocationPolicy_name = map[int32]string{
+	0: "CONTINUE",
+	1: "FAIL",
+	2: "MIGRATE",
+}
+var RunningInvocationPolicy_value = map[string]int32{
+	"CONTINUE": 0,
+	"FAIL":     1,
+	"MIGRATE":  2,
+}
+
+func (x RunningInvocationPolicy) String() string {
+	return proto.EnumName(RunningInvocationPolicy_name, int32(x))
+}
+func (RunningInvocationPolicy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+type VisualizeRequest struct {
+	// workflowId is the id of the workflow to visualize.
+	WorkflowId string `protobuf:"bytes,1,opt,name=workflowId" json:"workflowId,omitempty"`
+	// invocationId is the id of an invocation of the workflow. If set, the graph of the invocation is rendered.
+	InvocationId string `protobuf:"bytes,2,opt,name=invocationId" json:"invocationId,omitempty"`
+	// format is the format of the graph: "dot" (default) or "mermaid".
+	Format string `protobuf:"bytes,3,opt,name=format" json:"format,omitempty"`
+}
+
+func (m *VisualizeRequest) Reset()                    { *m = VisualizeRequest{} }
+func (m *VisualizeRequest) String() string            { return proto.CompactTextString(m) }
+func (*VisualizeRequest) ProtoMessage()               {}
+func (*VisualizeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *VisualizeRequest) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *VisualizeRequest) GetInvocationId() string {
+	if m != nil {
+		return m.InvocationId
+	}
+	return ""
+}
+
+func (m *VisualizeRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+type Visualization struct {
+	// format is the format of the graph.
+	Format string `protobuf:"bytes,1,opt,name=format" json:"format,omitempty"`
+	// graph is the rendered graph, which can be rendered further with Graphviz or Mermaid.
+	Graph string `protobuf:"bytes,2,opt,name=graph" json:"graph,omitempty"`
+}
+
+func (m *Visualization) Reset()                    { *m = Visualization{} }
+func (m *Visualization) String() string            { return proto.CompactTextString(m) }
+func (*Visualization) ProtoMessage()               {}
+func (*Visualization) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Visualization) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *Visualization) GetGraph() string {
+	if m != nil {
+		return m.Graph
+	}
+	return ""
+}
+
+type WorkflowListQuery struct {
+	// labelSelector selects the workflows by their labels, such as "team=payments,env in (prod, staging)". If empty,
+	// all workflows are listed.
+	LabelSelector string `protobuf:"bytes,1,opt,name=labelSelector" json:"labelSelector,omitempty"`
+}
+
+func (m *WorkflowListQuery) Reset()                    { *m = WorkflowListQuery{} }
+func (m *WorkflowListQuery) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowListQuery) ProtoMessage()               {}
+func (*WorkflowListQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *WorkflowListQuery) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
 type WorkflowList struct {
 	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
 }
 
-func (m *WorkflowList) Reset()                    { *m = WorkflowList{} }
-func (m *WorkflowList) String() string            { return proto.CompactTextString(m) }
-func (*WorkflowList) ProtoMessage()               {}
-func (*WorkflowList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (m *WorkflowList) Reset()                    { *m = WorkflowList{} }
+func (m *WorkflowList) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowList) ProtoMessage()               {}
+func (*WorkflowList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *WorkflowList) GetWorkflows() []string {
+	if m != nil {
+		return m.Workflows
+	}
+	return nil
+}
+
+type WorkflowUpdateRequest struct {
+	// id is the id of the workflow to update.
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// spec is the spec of the new version of the workflow. Either the spec or the patch should be set.
+	Spec *fission_workflows_types1.WorkflowSpec `protobuf:"bytes,2,opt,name=spec" json:"spec,omitempty"`
+	// patch is applied to the spec of the latest version of the workflow to create the new version.
+	Patch *WorkflowPatch `protobuf:"bytes,3,opt,name=patch" json:"patch,omitempty"`
+	// policy determines what happens to the invocations of the workflow that are running.
+	Policy RunningInvocationPolicy `protobuf:"varint,4,opt,name=policy,enum=fission.workflows.apiserver.RunningInvocationPolicy" json:"policy,omitempty"`
+}
+
+func (m *WorkflowUpdateRequest) Reset()                    { *m = WorkflowUpdateRequest{} }
+func (m *WorkflowUpdateRequest) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowUpdateRequest) ProtoMessage()               {}
+func (*WorkflowUpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *WorkflowUpdateRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WorkflowUpdateRequest) GetSpec() *fission_workflows_types1.WorkflowSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *WorkflowUpdateRequest) GetPatch() *WorkflowPatch {
+	if m != nil {
+		return m.Patch
+	}
+	return nil
+}
+
+func (m *WorkflowUpdateRequest) GetPolicy() RunningInvocationPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return RunningInvocationPolicy_CONTINUE
+}
+
+// WorkflowPatch is a strategic patch of the spec of a workflow.
+//
+// The tasks to remove are removed before the tasks of the patch are merged, so a task can be replaced entirely by
+// both removing and patching it.
+type WorkflowPatch struct {
+	// tasks are merged into the tasks of the workflow. Tasks that do not exist yet are added. For existing tasks, the
+	// fields that are set in the patch replace those of the task, except for the inputs, requires and inputSchemas,
+	// which are merged by key.
+	Tasks map[string]*fission_workflows_types1.TaskSpec `protobuf:"bytes,1,rep,name=tasks" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// removeTasks contains the ids of the tasks to remove from the workflow.
+	RemoveTasks []string `protobuf:"bytes,2,rep,name=removeTasks" json:"removeTasks,omitempty"`
+	// outputTask replaces the output task of the workflow, if set.
+	OutputTask string `protobuf:"bytes,3,opt,name=outputTask" json:"outputTask,omitempty"`
+	// description replaces the description of the workflow, if set.
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *WorkflowPatch) Reset()                    { *m = WorkflowPatch{} }
+func (m *WorkflowPatch) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowPatch) ProtoMessage()               {}
+func (*WorkflowPatch) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *WorkflowPatch) GetTasks() map[string]*fission_workflows_types1.TaskSpec {
+	if m != nil {
+		return m.Tasks
+	}
+	return nil
+}
+
+func (m *WorkflowPatch) GetRemoveTasks() []string {
+	if m != nil {
+		return m.RemoveTasks
+	}
+	return nil
+}
+
+func (m *WorkflowPatch) GetOutputTask() string {
+	if m != nil {
+		return m.OutputTask
+	}
+	return ""
+}
+
+func (m *WorkflowPatch) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type WorkflowValidationResult struct {
+	// valid is true if no issues were found in the workflow spec.
+	Valid bool `protobuf:"varint,1,opt,name=valid" json:"valid,omitempty"`
+	// diagnostics contains an entry for each issue that was found in the workflow spec.
+	Diagnostics []*ValidationDiagnostic `protobuf:"bytes,2,rep,name=diagnostics" json:"diagnostics,omitempty"`
+}
+
+func (m *WorkflowValidationResult) Reset()                    { *m = WorkflowValidationResult{} }
+func (m *WorkflowValidationResult) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowValidationResult) ProtoMessage()               {}
+func (*WorkflowValidationResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *WorkflowValidationResult) GetValid() bool {
+	if m != nil {
+		return m.Valid
+	}
+	return false
+}
+
+func (m *WorkflowValidationResult) GetDiagnostics() []*ValidationDiagnostic {
+	if m != nil {
+		return m.Diagnostics
+	}
+	return nil
+}
+
+// ValidationDiagnostic describes an issue in a workflow spec, and where it is located.
+type ValidationDiagnostic struct {
+	// reason is the type of the issue, such as "task contains undefined dependency".
+	Reason string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
+	// message is the full description of the issue.
+	Message string `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	// taskId is the id of the task that contains the issue, or empty if the issue is not located in a task.
+	TaskId string `protobuf:"bytes,3,opt,name=taskId" json:"taskId,omitempty"`
+	// field is the dot-separated path to the field that contains the issue, such as "tasks.foo.requires.bar".
+	Field string `protobuf:"bytes,4,opt,name=field" json:"field,omitempty"`
+}
+
+func (m *ValidationDiagnostic) Reset()                    { *m = ValidationDiagnostic{} }
+func (m *ValidationDiagnostic) String() string            { return proto.CompactTextString(m) }
+func (*ValidationDiagnostic) ProtoMessage()               {}
+func (*ValidationDiagnostic) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ValidationDiagnostic) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ValidationDiagnostic) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ValidationDiagnostic) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *ValidationDiagnostic) GetField() string {
+	if m != nil {
+		return m.Field
+	}
+	return ""
+}
+
+type WorkflowWatchQuery struct {
+	// ids are the ids of the workflows to watch. If empty, all workflows are watched.
+	Ids []string `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+}
+
+func (m *WorkflowWatchQuery) Reset()                    { *m = WorkflowWatchQuery{} }
+func (m *WorkflowWatchQuery) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowWatchQuery) ProtoMessage()               {}
+func (*WorkflowWatchQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *WorkflowWatchQuery) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type WorkflowUpdate struct {
+	// event is the type of the event that caused the update.
+	Event    string                             `protobuf:"bytes,1,opt,name=event" json:"event,omitempty"`
+	Workflow *fission_workflows_types1.Workflow `protobuf:"bytes,2,opt,name=workflow" json:"workflow,omitempty"`
+}
+
+func (m *WorkflowUpdate) Reset()                    { *m = WorkflowUpdate{} }
+func (m *WorkflowUpdate) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowUpdate) ProtoMessage()               {}
+func (*WorkflowUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *WorkflowUpdate) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *WorkflowUpdate) GetWorkflow() *fission_workflows_types1.Workflow {
+	if m != nil {
+		return m.Workflow
+	}
+	return nil
+}
+
+type InvokeManyRequest struct {
+	// spec is the template of the invocations. The inputs of each input set are added to the inputs of the spec.
+	Spec *fission_workflows_types1.WorkflowInvocationSpec `protobuf:"bytes,1,opt,name=spec" json:"spec,omitempty"`
+	// inputs contains the input sets, one for each invocation.
+	Inputs []*InvocationInputs `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty"`
+	// maxRate is the maximum number of invocations that are created per second. If 0, the invocations are created
+	// without delay.
+	MaxRate float64 `protobuf:"fixed64,3,opt,name=maxRate" json:"maxRate,omitempty"`
+}
+
+func (m *InvokeManyRequest) Reset()                    { *m = InvokeManyRequest{} }
+func (m *InvokeManyRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvokeManyRequest) ProtoMessage()               {}
+func (*InvokeManyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *InvokeManyRequest) GetSpec() *fission_workflows_types1.WorkflowInvocationSpec {
+	if m != nil {
+		return m.Spec
+	}
+	return nil
+}
+
+func (m *InvokeManyRequest) GetInputs() []*InvocationInputs {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *InvokeManyRequest) GetMaxRate() float64 {
+	if m != nil {
+		return m.MaxRate
+	}
+	return 0
+}
+
+type InvocationInputs struct {
+	Inputs map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,1,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *InvocationInputs) Reset()                    { *m = InvocationInputs{} }
+func (m *InvocationInputs) String() string            { return proto.CompactTextString(m) }
+func (*InvocationInputs) ProtoMessage()               {}
+func (*InvocationInputs) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *InvocationInputs) GetInputs() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+type InvokeManyResponse struct {
+	Results []*InvokeManyResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *InvokeManyResponse) Reset()                    { *m = InvokeManyResponse{} }
+func (m *InvokeManyResponse) String() string            { return proto.CompactTextString(m) }
+func (*InvokeManyResponse) ProtoMessage()               {}
+func (*InvokeManyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *InvokeManyResponse) GetResults() []*InvokeManyResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type InvokeManyResult struct {
+	// id is the id of the created invocation, if the invocation was created.
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// error is the reason that the invocation was not created.
+	Error *fission_workflows_types1.Error `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *InvokeManyResult) Reset()                    { *m = InvokeManyResult{} }
+func (m *InvokeManyResult) String() string            { return proto.CompactTextString(m) }
+func (*InvokeManyResult) ProtoMessage()               {}
+func (*InvokeManyResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *InvokeManyResult) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *InvokeManyResult) GetError() *fission_workflows_types1.Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type CancelRequest struct {
+	// id is the id of the invocation to cancel.
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	// reason is a human-readable explanation of why the invocation was canceled.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	// cascade indicates whether the child invocations of the invocation should be canceled as well.
+	Cascade bool `protobuf:"varint,3,opt,name=cascade" json:"cascade,omitempty"`
+}
+
+func (m *CancelRequest) Reset()                    { *m = CancelRequest{} }
+func (m *CancelRequest) String() string            { return proto.CompactTextString(m) }
+func (*CancelRequest) ProtoMessage()               {}
+func (*CancelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *CancelRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CancelRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *CancelRequest) GetCascade() bool {
+	if m != nil {
+		return m.Cascade
+	}
+	return false
+}
+
+type AddTaskRequest struct {
+	InvocationID string                         `protobuf:"bytes,1,opt,name=invocationID" json:"invocationID,omitempty"`
+	Task         *fission_workflows_types1.Task `protobuf:"bytes,2,opt,name=task" json:"task,omitempty"`
+}
+
+func (m *AddTaskRequest) Reset()                    { *m = AddTaskRequest{} }
+func (m *AddTaskRequest) String() string            { return proto.CompactTextString(m) }
+func (*AddTaskRequest) ProtoMessage()               {}
+func (*AddTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *AddTaskRequest) GetInvocationID() string {
+	if m != nil {
+		return m.InvocationID
+	}
+	return ""
+}
+
+func (m *AddTaskRequest) GetTask() *fission_workflows_types1.Task {
+	if m != nil {
+		return m.Task
+	}
+	return nil
+}
+
+type TaskLogsRequest struct {
+	InvocationID string `protobuf:"bytes,1,opt,name=invocationID" json:"invocationID,omitempty"`
+	TaskID       string `protobuf:"bytes,2,opt,name=taskID" json:"taskID,omitempty"`
+}
+
+func (m *TaskLogsRequest) Reset()                    { *m = TaskLogsRequest{} }
+func (m *TaskLogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*TaskLogsRequest) ProtoMessage()               {}
+func (*TaskLogsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *TaskLogsRequest) GetInvocationID() string {
+	if m != nil {
+		return m.InvocationID
+	}
+	return ""
+}
+
+func (m *TaskLogsRequest) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+type TaskLogs struct {
+	Entries []*LogEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *TaskLogs) Reset()                    { *m = TaskLogs{} }
+func (m *TaskLogs) String() string            { return proto.CompactTextString(m) }
+func (*TaskLogs) ProtoMessage()               {}
+func (*TaskLogs) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *TaskLogs) GetEntries() []*LogEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type LogEntry struct {
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	Message   string                     `protobuf:"bytes,2,opt,name=message" json:"message,omitempty"`
+	// stream is the output stream of the log line, such as stdout or stderr.
+	Stream string `protobuf:"bytes,3,opt,name=stream" json:"stream,omitempty"`
+	// source identifies the instance of the function that wrote the log line, such as the name of the pod.
+	Source string `protobuf:"bytes,4,opt,name=source" json:"source,omitempty"`
+}
+
+func (m *LogEntry) Reset()                    { *m = LogEntry{} }
+func (m *LogEntry) String() string            { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()               {}
+func (*LogEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *LogEntry) GetTimestamp() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *LogEntry) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LogEntry) GetStream() string {
+	if m != nil {
+		return m.Stream
+	}
+	return ""
+}
+
+func (m *LogEntry) GetSource() string {
+	if m != nil {
+		return m.Source
+	}
+	return ""
+}
+
+type InvocationListQuery struct {
+	// workflows are the ids of the workflows of which the invocations are listed. If empty, the invocations of all
+	// workflows are listed.
+	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
+	// labelSelector selects the invocations by their labels, such as "team=payments,env=prod". If empty, the
+	// invocations are not filtered by their labels.
+	LabelSelector string `protobuf:"bytes,2,opt,name=labelSelector" json:"labelSelector,omitempty"`
+}
+
+func (m *InvocationListQuery) Reset()                    { *m = InvocationListQuery{} }
+func (m *InvocationListQuery) String() string            { return proto.CompactTextString(m) }
+func (*InvocationListQuery) ProtoMessage()               {}
+func (*InvocationListQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *InvocationListQuery) GetWorkflows() []string {
+	if m != nil {
+		return m.Workflows
+	}
+	return nil
+}
+
+func (m *InvocationListQuery) GetLabelSelector() string {
+	if m != nil {
+		return m.LabelSelector
+	}
+	return ""
+}
+
+type WorkflowInvocationList struct {
+	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+}
+
+func (m *WorkflowInvocationList) Reset()                    { *m = WorkflowInvocationList{} }
+func (m *WorkflowInvocationList) String() string            { return proto.CompactTextString(m) }
+func (*WorkflowInvocationList) ProtoMessage()               {}
+func (*WorkflowInvocationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *WorkflowInvocationList) GetInvocations() []string {
+	if m != nil {
+		return m.Invocations
+	}
+	return nil
+}
+
+type InvocationWatchQuery struct {
+	// ids are the ids of the invocations to watch.
+	Ids []string `protobuf:"bytes,1,rep,name=ids" json:"ids,omitempty"`
+	// workflows limits the watch to the invocations of these workflows.
+	Workflows []string `protobuf:"bytes,2,rep,name=workflows" json:"workflows,omitempty"`
+}
+
+func (m *InvocationWatchQuery) Reset()                    { *m = InvocationWatchQuery{} }
+func (m *InvocationWatchQuery) String() string            { return proto.CompactTextString(m) }
+func (*InvocationWatchQuery) ProtoMessage()               {}
+func (*InvocationWatchQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *InvocationWatchQuery) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *InvocationWatchQuery) GetWorkflows() []string {
+	if m != nil {
+		return m.Workflows
+	}
+	return nil
+}
+
+type InvocationUpdate struct {
+	// event is the type of the event that caused the update.
+	Event      string                                       `protobuf:"bytes,1,opt,name=event" json:"event,omitempty"`
+	Invocation *fission_workflows_types1.WorkflowInvocation `protobuf:"bytes,2,opt,name=invocation" json:"invocation,omitempty"`
+}
+
+func (m *InvocationUpdate) Reset()                    { *m = InvocationUpdate{} }
+func (m *InvocationUpdate) String() string            { return proto.CompactTextString(m) }
+func (*InvocationUpdate) ProtoMessage()               {}
+func (*InvocationUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *InvocationUpdate) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
+}
+
+func (m *InvocationUpdate) GetInvocation() *fission_workflows_types1.WorkflowInvocation {
+	if m != nil {
+		return m.Invocation
+	}
+	return nil
+}
+
+type ObjectEvents struct {
+	Metadata *fission_workflows_types1.ObjectMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
+	Events   []*fission_workflows_eventstore.Event    `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *ObjectEvents) Reset()                    { *m = ObjectEvents{} }
+func (m *ObjectEvents) String() string            { return proto.CompactTextString(m) }
+func (*ObjectEvents) ProtoMessage()               {}
+func (*ObjectEvents) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *ObjectEvents) GetMetadata() *fission_workflows_types1.ObjectMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ObjectEvents) GetEvents() []*fission_workflows_eventstore.Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type Health struct {
+	Status string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+}
+
+func (m *Health) Reset()                    { *m = Health{} }
+func (m *Health) String() string            { return proto.CompactTextString(m) }
+func (*Health) ProtoMessage()               {}
+func (*Health) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *Health) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+// ExecutorConfig is the configuration and utilization of a task executor.
+type ExecutorConfig struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// parallelism is the maximum number of tasks that the executor executes at the same time.
+	Parallelism int32 `protobuf:"varint,2,opt,name=parallelism" json:"parallelism,omitempty"`
+	// queueSize is the maximum number of tasks that are queued for execution.
+	QueueSize int32 `protobuf:"varint,3,opt,name=queueSize" json:"queueSize,omitempty"`
+	// workers is the number of running workers (read-only).
+	Workers int32 `protobuf:"varint,4,opt,name=workers" json:"workers,omitempty"`
+	// queued is the number of tasks that are waiting to be executed (read-only).
+	Queued int32 `protobuf:"varint,5,opt,name=queued" json:"queued,omitempty"`
+}
+
+func (m *ExecutorConfig) Reset()                    { *m = ExecutorConfig{} }
+func (m *ExecutorConfig) String() string            { return proto.CompactTextString(m) }
+func (*ExecutorConfig) ProtoMessage()               {}
+func (*ExecutorConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *ExecutorConfig) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ExecutorConfig) GetParallelism() int32 {
+	if m != nil {
+		return m.Parallelism
+	}
+	return 0
+}
+
+func (m *ExecutorConfig) GetQueueSize() int32 {
+	if m != nil {
+		return m.QueueSize
+	}
+	return 0
+}
+
+func (m *ExecutorConfig) GetWorkers() int32 {
+	if m != nil {
+		return m.Workers
+	}
+	return 0
+}
+
+func (m *ExecutorConfig) GetQueued() int32 {
+	if m != nil {
+		return m.Queued
+	}
+	return 0
+}
+
+type Executors struct {
+	Executors []*ExecutorConfig `protobuf:"bytes,1,rep,name=executors" json:"executors,omitempty"`
+}
+
+func (m *Executors) Reset()                    { *m = Executors{} }
+func (m *Executors) String() string            { return proto.CompactTextString(m) }
+func (*Executors) ProtoMessage()               {}
+func (*Executors) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *Executors) GetExecutors() []*ExecutorConfig {
+	if m != nil {
+		return m.Executors
+	}
+	return nil
+}
+
+// QuarantinedController is a controller that is quarantined, because its evaluations failed repeatedly.
+type QuarantinedController struct {
+	// system is the name of the control system of the controller, such as invocation or workflow.
+	System string `protobuf:"bytes,1,opt,name=system" json:"system,omitempty"`
+	// key identifies the controller, such as the id of the invocation.
+	Key   string                     `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+	Since *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=since" json:"since,omitempty"`
+	// failures is the number of consecutive failed evaluations that caused the quarantine.
+	Failures  int32  `protobuf:"varint,4,opt,name=failures" json:"failures,omitempty"`
+	LastError string `protobuf:"bytes,5,opt,name=lastError" json:"lastError,omitempty"`
+}
+
+func (m *QuarantinedController) Reset()                    { *m = QuarantinedController{} }
+func (m *QuarantinedController) String() string            { return proto.CompactTextString(m) }
+func (*QuarantinedController) ProtoMessage()               {}
+func (*QuarantinedController) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *QuarantinedController) GetSystem() string {
+	if m != nil {
+		return m.System
+	}
+	return ""
+}
+
+func (m *QuarantinedController) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *QuarantinedController) GetSince() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *QuarantinedController) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *QuarantinedController) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+type QuarantinedControllers struct {
+	Controllers []*QuarantinedController `protobuf:"bytes,1,rep,name=controllers" json:"controllers,omitempty"`
+}
+
+func (m *QuarantinedControllers) Reset()                    { *m = QuarantinedControllers{} }
+func (m *QuarantinedControllers) String() string            { return proto.CompactTextString(m) }
+func (*QuarantinedControllers) ProtoMessage()               {}
+func (*QuarantinedControllers) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *QuarantinedControllers) GetControllers() []*QuarantinedController {
+	if m != nil {
+		return m.Controllers
+	}
+	return nil
+}
+
+type QuarantinedControllerRef struct {
+	System string `protobuf:"bytes,1,opt,name=system" json:"system,omitempty"`
+	Key    string `protobuf:"bytes,2,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *QuarantinedControllerRef) Reset()                    { *m = QuarantinedControllerRef{} }
+func (m *QuarantinedControllerRef) String() string            { return proto.CompactTextString(m) }
+func (*QuarantinedControllerRef) ProtoMessage()               {}
+func (*QuarantinedControllerRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *QuarantinedControllerRef) GetSystem() string {
+	if m != nil {
+		return m.System
+	}
+	return ""
+}
+
+func (m *QuarantinedControllerRef) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+// ProjectionRef identifies the projection of a workflow or invocation.
+type ProjectionRef struct {
+	// type is the type of the projection, either workflow or invocation.
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Id   string `protobuf:"bytes,2,opt,name=id" json:"id,omitempty"`
+}
 
-func (m *WorkflowList) GetWorkflows() []string {
+func (m *ProjectionRef) Reset()                    { *m = ProjectionRef{} }
+func (m *ProjectionRef) String() string            { return proto.CompactTextString(m) }
+func (*ProjectionRef) ProtoMessage()               {}
+func (*ProjectionRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *ProjectionRef) GetType() string {
 	if m != nil {
-		return m.Workflows
+		return m.Type
 	}
-	return nil
+	return ""
 }
 
-type AddTaskRequest struct {
-	InvocationID string                         `protobuf:"bytes,1,opt,name=invocationID" json:"invocationID,omitempty"`
-	Task         *fission_workflows_types1.Task `protobuf:"bytes,2,opt,name=task" json:"task,omitempty"`
+func (m *ProjectionRef) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-func (m *AddTaskRequest) Reset()                    { *m = AddTaskRequest{} }
-func (m *AddTaskRequest) String() string            { return proto.CompactTextString(m) }
-func (*AddTaskRequest) ProtoMessage()               {}
-func (*AddTaskRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+type ResyncProjectionsRequest struct {
+	// type is the type of the projections to resync, either workflow or invocation. If empty, all are resynced.
+	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+}
 
-func (m *AddTaskRequest) GetInvocationID() string {
+func (m *ResyncProjectionsRequest) Reset()                    { *m = ResyncProjectionsRequest{} }
+func (m *ResyncProjectionsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResyncProjectionsRequest) ProtoMessage()               {}
+func (*ResyncProjectionsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *ResyncProjectionsRequest) GetType() string {
 	if m != nil {
-		return m.InvocationID
+		return m.Type
 	}
 	return ""
 }
 
-func (m *AddTaskRequest) GetTask() *fission_workflows_types1.Task {
+type ResyncProjectionsResult struct {
+	// rebuilt is the number of projections that were rebuilt from their events.
+	Rebuilt int32 `protobuf:"varint,1,opt,name=rebuilt" json:"rebuilt,omitempty"`
+	// removed is the number of cached projections that were discarded, because they had no events.
+	Removed int32 `protobuf:"varint,2,opt,name=removed" json:"removed,omitempty"`
+	// failed contains the projections that could not be rebuilt.
+	Failed []*ProjectionError `protobuf:"bytes,3,rep,name=failed" json:"failed,omitempty"`
+}
+
+func (m *ResyncProjectionsResult) Reset()                    { *m = ResyncProjectionsResult{} }
+func (m *ResyncProjectionsResult) String() string            { return proto.CompactTextString(m) }
+func (*ResyncProjectionsResult) ProtoMessage()               {}
+func (*ResyncProjectionsResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *ResyncProjectionsResult) GetRebuilt() int32 {
 	if m != nil {
-		return m.Task
+		return m.Rebuilt
+	}
+	return 0
+}
+
+func (m *ResyncProjectionsResult) GetRemoved() int32 {
+	if m != nil {
+		return m.Removed
+	}
+	return 0
+}
+
+func (m *ResyncProjectionsResult) GetFailed() []*ProjectionError {
+	if m != nil {
+		return m.Failed
 	}
 	return nil
 }
 
-type InvocationListQuery struct {
-	Workflows []string `protobuf:"bytes,1,rep,name=workflows" json:"workflows,omitempty"`
+type ProjectionError struct {
+	Ref   *ProjectionRef `protobuf:"bytes,1,opt,name=ref" json:"ref,omitempty"`
+	Error string         `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
 }
 
-func (m *InvocationListQuery) Reset()                    { *m = InvocationListQuery{} }
-func (m *InvocationListQuery) String() string            { return proto.CompactTextString(m) }
-func (*InvocationListQuery) ProtoMessage()               {}
-func (*InvocationListQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (m *ProjectionError) Reset()                    { *m = ProjectionError{} }
+func (m *ProjectionError) String() string            { return proto.CompactTextString(m) }
+func (*ProjectionError) ProtoMessage()               {}
+func (*ProjectionError) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
 
-func (m *InvocationListQuery) GetWorkflows() []string {
+func (m *ProjectionError) GetRef() *ProjectionRef {
 	if m != nil {
-		return m.Workflows
+		return m.Ref
 	}
 	return nil
 }
 
-type WorkflowInvocationList struct {
-	Invocations []string `protobuf:"bytes,1,rep,name=invocations" json:"invocations,omitempty"`
+func (m *ProjectionError) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
 }
 
-func (m *WorkflowInvocationList) Reset()                    { *m = WorkflowInvocationList{} }
-func (m *WorkflowInvocationList) String() string            { return proto.CompactTextString(m) }
-func (*WorkflowInvocationList) ProtoMessage()               {}
-func (*WorkflowInvocationList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+// AuditEvent records a state-changing API call.
+type AuditEvent struct {
+	Id        string                     `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Timestamp *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	// subject identifies the caller, such as the name of the API key or the subject of the JWT. It is empty if the API
+	// is not authenticated.
+	Subject string `protobuf:"bytes,3,opt,name=subject" json:"subject,omitempty"`
+	// peer is the address of the caller.
+	Peer string `protobuf:"bytes,4,opt,name=peer" json:"peer,omitempty"`
+	// method is the full name of the gRPC method, such as /fission.workflows.apiserver.WorkflowAPI/Delete.
+	Method string `protobuf:"bytes,5,opt,name=method" json:"method,omitempty"`
+	// resource is the id of the workflow or invocation that the call acted on, if known.
+	Resource string `protobuf:"bytes,6,opt,name=resource" json:"resource,omitempty"`
+	// requestDigest is the hex-encoded SHA-256 digest of the serialized request.
+	RequestDigest string `protobuf:"bytes,7,opt,name=requestDigest" json:"requestDigest,omitempty"`
+	// code is the gRPC status code of the call, such as OK or PermissionDenied.
+	Code  string `protobuf:"bytes,8,opt,name=code" json:"code,omitempty"`
+	Error string `protobuf:"bytes,9,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *AuditEvent) Reset()                    { *m = AuditEvent{} }
+func (m *AuditEvent) String() string            { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()               {}
+func (*AuditEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *AuditEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
 
-func (m *WorkflowInvocationList) GetInvocations() []string {
+func (m *AuditEvent) GetTimestamp() *google_protobuf.Timestamp {
 	if m != nil {
-		return m.Invocations
+		return m.Timestamp
 	}
 	return nil
 }
 
-type ObjectEvents struct {
-	Metadata *fission_workflows_types1.ObjectMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
-	Events   []*fission_workflows_eventstore.Event    `protobuf:"bytes,2,rep,name=events" json:"events,omitempty"`
+func (m *AuditEvent) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
 }
 
-func (m *ObjectEvents) Reset()                    { *m = ObjectEvents{} }
-func (m *ObjectEvents) String() string            { return proto.CompactTextString(m) }
-func (*ObjectEvents) ProtoMessage()               {}
-func (*ObjectEvents) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (m *AuditEvent) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
 
-func (m *ObjectEvents) GetMetadata() *fission_workflows_types1.ObjectMetadata {
+func (m *AuditEvent) GetMethod() string {
 	if m != nil {
-		return m.Metadata
+		return m.Method
 	}
-	return nil
+	return ""
 }
 
-func (m *ObjectEvents) GetEvents() []*fission_workflows_eventstore.Event {
+func (m *AuditEvent) GetResource() string {
 	if m != nil {
-		return m.Events
+		return m.Resource
 	}
-	return nil
+	return ""
 }
 
-type Health struct {
-	Status string `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
+func (m *AuditEvent) GetRequestDigest() string {
+	if m != nil {
+		return m.RequestDigest
+	}
+	return ""
 }
 
-func (m *Health) Reset()                    { *m = Health{} }
-func (m *Health) String() string            { return proto.CompactTextString(m) }
-func (*Health) ProtoMessage()               {}
-func (*Health) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *AuditEvent) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
 
-func (m *Health) GetStatus() string {
+func (m *AuditEvent) GetError() string {
 	if m != nil {
-		return m.Status
+		return m.Error
+	}
+	return ""
+}
+
+type AuditLogQuery struct {
+	// subject, method and resource filter the events by exact match. If empty, the events are not filtered.
+	Subject  string `protobuf:"bytes,1,opt,name=subject" json:"subject,omitempty"`
+	Method   string `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	Resource string `protobuf:"bytes,3,opt,name=resource" json:"resource,omitempty"`
+	// since excludes the events before this time.
+	Since *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=since" json:"since,omitempty"`
+	// limit is the maximum number of events to return. Defaults to 100.
+	Limit int32 `protobuf:"varint,5,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *AuditLogQuery) Reset()                    { *m = AuditLogQuery{} }
+func (m *AuditLogQuery) String() string            { return proto.CompactTextString(m) }
+func (*AuditLogQuery) ProtoMessage()               {}
+func (*AuditLogQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *AuditLogQuery) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *AuditLogQuery) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditLogQuery) GetResource() string {
+	if m != nil {
+		return m.Resource
 	}
 	return ""
 }
 
+func (m *AuditLogQuery) GetSince() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Since
+	}
+	return nil
+}
+
+func (m *AuditLogQuery) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type AuditLog struct {
+	Events []*AuditEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *AuditLog) Reset()                    { *m = AuditLog{} }
+func (m *AuditLog) String() string            { return proto.CompactTextString(m) }
+func (*AuditLog) ProtoMessage()               {}
+func (*AuditLog) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *AuditLog) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
 func init() {
+	proto.RegisterType((*VisualizeRequest)(nil), "fission.workflows.apiserver.VisualizeRequest")
+	proto.RegisterType((*Visualization)(nil), "fission.workflows.apiserver.Visualization")
+	proto.RegisterType((*WorkflowListQuery)(nil), "fission.workflows.apiserver.WorkflowListQuery")
 	proto.RegisterType((*WorkflowList)(nil), "fission.workflows.apiserver.WorkflowList")
+	proto.RegisterType((*WorkflowUpdateRequest)(nil), "fission.workflows.apiserver.WorkflowUpdateRequest")
+	proto.RegisterType((*WorkflowPatch)(nil), "fission.workflows.apiserver.WorkflowPatch")
+	proto.RegisterType((*WorkflowValidationResult)(nil), "fission.workflows.apiserver.WorkflowValidationResult")
+	proto.RegisterType((*ValidationDiagnostic)(nil), "fission.workflows.apiserver.ValidationDiagnostic")
+	proto.RegisterType((*WorkflowWatchQuery)(nil), "fission.workflows.apiserver.WorkflowWatchQuery")
+	proto.RegisterType((*WorkflowUpdate)(nil), "fission.workflows.apiserver.WorkflowUpdate")
+	proto.RegisterType((*InvokeManyRequest)(nil), "fission.workflows.apiserver.InvokeManyRequest")
+	proto.RegisterType((*InvocationInputs)(nil), "fission.workflows.apiserver.InvocationInputs")
+	proto.RegisterType((*InvokeManyResponse)(nil), "fission.workflows.apiserver.InvokeManyResponse")
+	proto.RegisterType((*InvokeManyResult)(nil), "fission.workflows.apiserver.InvokeManyResult")
+	proto.RegisterType((*CancelRequest)(nil), "fission.workflows.apiserver.CancelRequest")
 	proto.RegisterType((*AddTaskRequest)(nil), "fission.workflows.apiserver.AddTaskRequest")
+	proto.RegisterType((*TaskLogsRequest)(nil), "fission.workflows.apiserver.TaskLogsRequest")
+	proto.RegisterType((*TaskLogs)(nil), "fission.workflows.apiserver.TaskLogs")
+	proto.RegisterType((*LogEntry)(nil), "fission.workflows.apiserver.LogEntry")
 	proto.RegisterType((*InvocationListQuery)(nil), "fission.workflows.apiserver.InvocationListQuery")
 	proto.RegisterType((*WorkflowInvocationList)(nil), "fission.workflows.apiserver.WorkflowInvocationList")
+	proto.RegisterType((*InvocationWatchQuery)(nil), "fission.workflows.apiserver.InvocationWatchQuery")
+	proto.RegisterType((*InvocationUpdate)(nil), "fission.workflows.apiserver.InvocationUpdate")
 	proto.RegisterType((*ObjectEvents)(nil), "fission.workflows.apiserver.ObjectEvents")
 	proto.RegisterType((*Health)(nil), "fission.workflows.apiserver.Health")
+	proto.RegisterType((*ExecutorConfig)(nil), "fission.workflows.apiserver.ExecutorConfig")
+	proto.RegisterType((*Executors)(nil), "fission.workflows.apiserver.Executors")
+	proto.RegisterType((*QuarantinedController)(nil), "fission.workflows.apiserver.QuarantinedController")
+	proto.RegisterType((*QuarantinedControllers)(nil), "fission.workflows.apiserver.QuarantinedControllers")
+	proto.RegisterType((*QuarantinedControllerRef)(nil), "fission.workflows.apiserver.QuarantinedControllerRef")
+	proto.RegisterType((*ProjectionRef)(nil), "fission.workflows.apiserver.ProjectionRef")
+	proto.RegisterType((*ResyncProjectionsRequest)(nil), "fission.workflows.apiserver.ResyncProjectionsRequest")
+	proto.RegisterType((*ResyncProjectionsResult)(nil), "fission.workflows.apiserver.ResyncProjectionsResult")
+	proto.RegisterType((*ProjectionError)(nil), "fission.workflows.apiserver.ProjectionError")
+	proto.RegisterType((*AuditEvent)(nil), "fission.workflows.apiserver.AuditEvent")
+	proto.RegisterType((*AuditLogQuery)(nil), "fission.workflows.apiserver.AuditLogQuery")
+	proto.RegisterType((*AuditLog)(nil), "fission.workflows.apiserver.AuditLog")
+	proto.RegisterEnum("fission.workflows.apiserver.RunningInvocationPolicy", RunningInvocationPolicy_name, RunningInvocationPolicy_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -176,11 +1249,31 @@ const _ = grpc.SupportPackageIsVersion4
 type WorkflowAPIClient interface {
 	Create(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
 	CreateSync(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
-	List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error)
+	// Update replaces or patches the spec of a workflow, which creates a new version of the workflow.
+	//
+	// The previous versions of the workflow remain available, so new invocations can target a previous version with
+	// their workflowVersion. The policy of the request determines what happens to the invocations of the workflow that
+	// are running: by default they continue with the version that they were started with.
+	Update(ctx context.Context, in *WorkflowUpdateRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// List returns the ids of the workflows that match the query.
+	List(ctx context.Context, in *WorkflowListQuery, opts ...grpc.CallOption) (*WorkflowList, error)
+	// Watch streams the updates of the workflows that match the query.
+	//
+	// On the HTTP gateway, the updates are streamed as newline-delimited JSON, or as server-sent events if the request
+	// accepts text/event-stream.
+	Watch(ctx context.Context, in *WorkflowWatchQuery, opts ...grpc.CallOption) (WorkflowAPI_WatchClient, error)
 	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error)
 	Delete(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
-	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// Validate runs the complete validation of a workflow spec, without creating the workflow.
+	//
+	// Besides validating the structure of the spec, it checks that the expressions in the tasks parse and it resolves
+	// the functions of the tasks in a dry-run. Instead of failing on the first issue, it returns a diagnostic for each
+	// issue that it found.
+	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*WorkflowValidationResult, error)
 	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
+	// Visualize renders the task graph of a workflow in Graphviz DOT or Mermaid format. With an invocationId, the
+	// graph of the invocation is rendered instead, which includes its dynamic tasks and the status of its tasks.
+	Visualize(ctx context.Context, in *VisualizeRequest, opts ...grpc.CallOption) (*Visualization, error)
 }
 
 type workflowAPIClient struct {
@@ -209,7 +1302,16 @@ func (c *workflowAPIClient) CreateSync(ctx context.Context, in *fission_workflow
 	return out, nil
 }
 
-func (c *workflowAPIClient) List(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*WorkflowList, error) {
+func (c *workflowAPIClient) Update(ctx context.Context, in *WorkflowUpdateRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Update", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowAPIClient) List(ctx context.Context, in *WorkflowListQuery, opts ...grpc.CallOption) (*WorkflowList, error) {
 	out := new(WorkflowList)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/List", in, out, c.cc, opts...)
 	if err != nil {
@@ -218,6 +1320,38 @@ func (c *workflowAPIClient) List(ctx context.Context, in *google_protobuf3.Empty
 	return out, nil
 }
 
+func (c *workflowAPIClient) Watch(ctx context.Context, in *WorkflowWatchQuery, opts ...grpc.CallOption) (WorkflowAPI_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WorkflowAPI_serviceDesc.Streams[0], c.cc, "/fission.workflows.apiserver.WorkflowAPI/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowAPIWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkflowAPI_WatchClient interface {
+	Recv() (*WorkflowUpdate, error)
+	grpc.ClientStream
+}
+
+type workflowAPIWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowAPIWatchClient) Recv() (*WorkflowUpdate, error) {
+	m := new(WorkflowUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *workflowAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.Workflow, error) {
 	out := new(fission_workflows_types1.Workflow)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Get", in, out, c.cc, opts...)
@@ -236,8 +1370,8 @@ func (c *workflowAPIClient) Delete(ctx context.Context, in *fission_workflows_ty
 	return out, nil
 }
 
-func (c *workflowAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
-	out := new(google_protobuf3.Empty)
+func (c *workflowAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowSpec, opts ...grpc.CallOption) (*WorkflowValidationResult, error) {
+	out := new(WorkflowValidationResult)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Validate", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
@@ -254,16 +1388,45 @@ func (c *workflowAPIClient) Events(ctx context.Context, in *fission_workflows_ty
 	return out, nil
 }
 
+func (c *workflowAPIClient) Visualize(ctx context.Context, in *VisualizeRequest, opts ...grpc.CallOption) (*Visualization, error) {
+	out := new(Visualization)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowAPI/Visualize", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for WorkflowAPI service
 
 type WorkflowAPIServer interface {
 	Create(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.ObjectMetadata, error)
 	CreateSync(context.Context, *fission_workflows_types1.WorkflowSpec) (*fission_workflows_types1.Workflow, error)
-	List(context.Context, *google_protobuf3.Empty) (*WorkflowList, error)
+	// Update replaces or patches the spec of a workflow, which creates a new version of the workflow.
+	//
+	// The previous versions of the workflow remain available, so new invocations can target a previous version with
+	// their workflowVersion. The policy of the request determines what happens to the invocations of the workflow that
+	// are running: by default they continue with the version that they were started with.
+	Update(context.Context, *WorkflowUpdateRequest) (*google_protobuf3.Empty, error)
+	// List returns the ids of the workflows that match the query.
+	List(context.Context, *WorkflowListQuery) (*WorkflowList, error)
+	// Watch streams the updates of the workflows that match the query.
+	//
+	// On the HTTP gateway, the updates are streamed as newline-delimited JSON, or as server-sent events if the request
+	// accepts text/event-stream.
+	Watch(*WorkflowWatchQuery, WorkflowAPI_WatchServer) error
 	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.Workflow, error)
 	Delete(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
-	Validate(context.Context, *fission_workflows_types1.WorkflowSpec) (*google_protobuf3.Empty, error)
+	// Validate runs the complete validation of a workflow spec, without creating the workflow.
+	//
+	// Besides validating the structure of the spec, it checks that the expressions in the tasks parse and it resolves
+	// the functions of the tasks in a dry-run. Instead of failing on the first issue, it returns a diagnostic for each
+	// issue that it found.
+	Validate(context.Context, *fission_workflows_types1.WorkflowSpec) (*WorkflowValidationResult, error)
 	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
+	// Visualize renders the task graph of a workflow in Graphviz DOT or Mermaid format. With an invocationId, the
+	// graph of the invocation is rendered instead, which includes its dynamic tasks and the status of its tasks.
+	Visualize(context.Context, *VisualizeRequest) (*Visualization, error)
 }
 
 func RegisterWorkflowAPIServer(s *grpc.Server, srv WorkflowAPIServer) {
@@ -306,8 +1469,26 @@ func _WorkflowAPI_CreateSync_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowAPI_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WorkflowUpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Update(ctx, req.(*WorkflowUpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowAPI_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(google_protobuf3.Empty)
+	in := new(WorkflowListQuery)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -319,11 +1500,32 @@ func _WorkflowAPI_List_Handler(srv interface{}, ctx context.Context, dec func(in
 		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/List",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowAPIServer).List(ctx, req.(*google_protobuf3.Empty))
+		return srv.(WorkflowAPIServer).List(ctx, req.(*WorkflowListQuery))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowAPI_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WorkflowWatchQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowAPIServer).Watch(m, &workflowAPIWatchServer{stream})
+}
+
+type WorkflowAPI_WatchServer interface {
+	Send(*WorkflowUpdate) error
+	grpc.ServerStream
+}
+
+type workflowAPIWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowAPIWatchServer) Send(m *WorkflowUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WorkflowAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
@@ -396,6 +1598,24 @@ func _WorkflowAPI_Events_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowAPI_Visualize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VisualizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowAPIServer).Visualize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowAPI/Visualize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowAPIServer).Visualize(ctx, req.(*VisualizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _WorkflowAPI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "fission.workflows.apiserver.WorkflowAPI",
 	HandlerType: (*WorkflowAPIServer)(nil),
@@ -408,6 +1628,10 @@ var _WorkflowAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CreateSync",
 			Handler:    _WorkflowAPI_CreateSync_Handler,
 		},
+		{
+			MethodName: "Update",
+			Handler:    _WorkflowAPI_Update_Handler,
+		},
 		{
 			MethodName: "List",
 			Handler:    _WorkflowAPI_List_Handler,
@@ -428,8 +1652,18 @@ var _WorkflowAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Events",
 			Handler:    _WorkflowAPI_Events_Handler,
 		},
+		{
+			MethodName: "Visualize",
+			Handler:    _WorkflowAPI_Visualize_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _WorkflowAPI_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiserver/apiserver.proto",
 }
 
@@ -441,20 +1675,49 @@ type WorkflowInvocationAPIClient interface {
 	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
 	Invoke(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.ObjectMetadata, error)
 	InvokeSync(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
+	// Create an invocation for each of the input sets of the request
+	//
+	// The result of each invocation - its id, or the error that prevented it from being created - is returned in the
+	// order of the input sets. An invalid input set does not affect the other invocations of the batch.
+	InvokeMany(ctx context.Context, in *InvokeManyRequest, opts ...grpc.CallOption) (*InvokeManyResponse, error)
 	AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
 	// Cancel a workflow invocation
 	//
 	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
 	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
 	// In case that an invocation does not exist a HTTP 404 error status is returned.
-	Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	//
+	// The reason of the cancellation is recorded in the error of the invocation status. If cascade is set, the child
+	// invocations of the invocation - such as nested workflows and dynamic tasks - are canceled as well.
+	Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// List returns the ids of the invocations that match the query.
 	List(ctx context.Context, in *InvocationListQuery, opts ...grpc.CallOption) (*WorkflowInvocationList, error)
+	// Watch streams the updates of the invocations that match the query.
+	//
+	// The current state of the invocations with the ids of the query is sent first. If the query contains ids, the
+	// stream ends once all of these invocations have finished.
+	//
+	// On the HTTP gateway, the updates are streamed as newline-delimited JSON, or as server-sent events if the request
+	// accepts text/event-stream.
+	Watch(ctx context.Context, in *InvocationWatchQuery, opts ...grpc.CallOption) (WorkflowInvocationAPI_WatchClient, error)
 	// Get the specification and status of a workflow invocation
 	//
 	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
 	// To lighten the request load, consider using a more specific request.
 	Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error)
 	Events(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*ObjectEvents, error)
+	// GetTaskLogs returns the log output that the function of a task wrote during its run in an invocation.
+	//
+	// The logs are fetched from the function environment of the task, such as the log database of Fission. In case
+	// the function environment does not provide logs, a HTTP 501 error status is returned.
+	GetTaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (*TaskLogs, error)
+	// Explain returns the most recent schedule that the scheduler determined for the invocation: the tasks that were
+	// runnable, the tasks that were blocked along with the reason, and the tasks that were prewarmed along with the
+	// time at which they are expected to start.
+	//
+	// The schedules are kept in memory by the controller, so in case the controller does not run in the same process
+	// as the API, a HTTP 501 error status is returned.
+	Explain(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_scheduler.Schedule, error)
 	Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
 }
 
@@ -484,6 +1747,15 @@ func (c *workflowInvocationAPIClient) InvokeSync(ctx context.Context, in *fissio
 	return out, nil
 }
 
+func (c *workflowInvocationAPIClient) InvokeMany(ctx context.Context, in *InvokeManyRequest, opts ...grpc.CallOption) (*InvokeManyResponse, error) {
+	out := new(InvokeManyResponse)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowInvocationAPIClient) AddTask(ctx context.Context, in *AddTaskRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
 	out := new(google_protobuf3.Empty)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask", in, out, c.cc, opts...)
@@ -493,7 +1765,7 @@ func (c *workflowInvocationAPIClient) AddTask(ctx context.Context, in *AddTaskRe
 	return out, nil
 }
 
-func (c *workflowInvocationAPIClient) Cancel(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+func (c *workflowInvocationAPIClient) Cancel(ctx context.Context, in *CancelRequest, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
 	out := new(google_protobuf3.Empty)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel", in, out, c.cc, opts...)
 	if err != nil {
@@ -511,6 +1783,38 @@ func (c *workflowInvocationAPIClient) List(ctx context.Context, in *InvocationLi
 	return out, nil
 }
 
+func (c *workflowInvocationAPIClient) Watch(ctx context.Context, in *InvocationWatchQuery, opts ...grpc.CallOption) (WorkflowInvocationAPI_WatchClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_WorkflowInvocationAPI_serviceDesc.Streams[0], c.cc, "/fission.workflows.apiserver.WorkflowInvocationAPI/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &workflowInvocationAPIWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WorkflowInvocationAPI_WatchClient interface {
+	Recv() (*InvocationUpdate, error)
+	grpc.ClientStream
+}
+
+type workflowInvocationAPIWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *workflowInvocationAPIWatchClient) Recv() (*InvocationUpdate, error) {
+	m := new(InvocationUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *workflowInvocationAPIClient) Get(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_types1.WorkflowInvocation, error) {
 	out := new(fission_workflows_types1.WorkflowInvocation)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Get", in, out, c.cc, opts...)
@@ -529,6 +1833,24 @@ func (c *workflowInvocationAPIClient) Events(ctx context.Context, in *fission_wo
 	return out, nil
 }
 
+func (c *workflowInvocationAPIClient) GetTaskLogs(ctx context.Context, in *TaskLogsRequest, opts ...grpc.CallOption) (*TaskLogs, error) {
+	out := new(TaskLogs)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTaskLogs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workflowInvocationAPIClient) Explain(ctx context.Context, in *fission_workflows_types1.ObjectMetadata, opts ...grpc.CallOption) (*fission_workflows_scheduler.Schedule, error) {
+	out := new(fission_workflows_scheduler.Schedule)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Explain", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *workflowInvocationAPIClient) Validate(ctx context.Context, in *fission_workflows_types1.WorkflowInvocationSpec, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
 	out := new(google_protobuf3.Empty)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.WorkflowInvocationAPI/Validate", in, out, c.cc, opts...)
@@ -546,20 +1868,49 @@ type WorkflowInvocationAPIServer interface {
 	// In case the invocation specification is missing fields or contains invalid fields, a HTTP 400 is returned.
 	Invoke(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.ObjectMetadata, error)
 	InvokeSync(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*fission_workflows_types1.WorkflowInvocation, error)
+	// Create an invocation for each of the input sets of the request
+	//
+	// The result of each invocation - its id, or the error that prevented it from being created - is returned in the
+	// order of the input sets. An invalid input set does not affect the other invocations of the batch.
+	InvokeMany(context.Context, *InvokeManyRequest) (*InvokeManyResponse, error)
 	AddTask(context.Context, *AddTaskRequest) (*google_protobuf3.Empty, error)
 	// Cancel a workflow invocation
 	//
 	// This action is irreverisble. A canceled invocation cannot be resumed or restarted.
 	// In case that an invocation already is canceled, has failed or has completed, nothing happens.
 	// In case that an invocation does not exist a HTTP 404 error status is returned.
-	Cancel(context.Context, *fission_workflows_types1.ObjectMetadata) (*google_protobuf3.Empty, error)
+	//
+	// The reason of the cancellation is recorded in the error of the invocation status. If cascade is set, the child
+	// invocations of the invocation - such as nested workflows and dynamic tasks - are canceled as well.
+	Cancel(context.Context, *CancelRequest) (*google_protobuf3.Empty, error)
+	// List returns the ids of the invocations that match the query.
 	List(context.Context, *InvocationListQuery) (*WorkflowInvocationList, error)
+	// Watch streams the updates of the invocations that match the query.
+	//
+	// The current state of the invocations with the ids of the query is sent first. If the query contains ids, the
+	// stream ends once all of these invocations have finished.
+	//
+	// On the HTTP gateway, the updates are streamed as newline-delimited JSON, or as server-sent events if the request
+	// accepts text/event-stream.
+	Watch(*InvocationWatchQuery, WorkflowInvocationAPI_WatchServer) error
 	// Get the specification and status of a workflow invocation
 	//
 	// Get returns three different aspects of the workflow invocation, namely the spec (specification), status and logs.
 	// To lighten the request load, consider using a more specific request.
 	Get(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_types1.WorkflowInvocation, error)
 	Events(context.Context, *fission_workflows_types1.ObjectMetadata) (*ObjectEvents, error)
+	// GetTaskLogs returns the log output that the function of a task wrote during its run in an invocation.
+	//
+	// The logs are fetched from the function environment of the task, such as the log database of Fission. In case
+	// the function environment does not provide logs, a HTTP 501 error status is returned.
+	GetTaskLogs(context.Context, *TaskLogsRequest) (*TaskLogs, error)
+	// Explain returns the most recent schedule that the scheduler determined for the invocation: the tasks that were
+	// runnable, the tasks that were blocked along with the reason, and the tasks that were prewarmed along with the
+	// time at which they are expected to start.
+	//
+	// The schedules are kept in memory by the controller, so in case the controller does not run in the same process
+	// as the API, a HTTP 501 error status is returned.
+	Explain(context.Context, *fission_workflows_types1.ObjectMetadata) (*fission_workflows_scheduler.Schedule, error)
 	Validate(context.Context, *fission_workflows_types1.WorkflowInvocationSpec) (*google_protobuf3.Empty, error)
 }
 
@@ -603,6 +1954,24 @@ func _WorkflowInvocationAPI_InvokeSync_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowInvocationAPI_InvokeMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).InvokeMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).InvokeMany(ctx, req.(*InvokeManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowInvocationAPI_AddTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddTaskRequest)
 	if err := dec(in); err != nil {
@@ -622,7 +1991,7 @@ func _WorkflowInvocationAPI_AddTask_Handler(srv interface{}, ctx context.Context
 }
 
 func _WorkflowInvocationAPI_Cancel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(fission_workflows_types1.ObjectMetadata)
+	in := new(CancelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -634,7 +2003,7 @@ func _WorkflowInvocationAPI_Cancel_Handler(srv interface{}, ctx context.Context,
 		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+		return srv.(WorkflowInvocationAPIServer).Cancel(ctx, req.(*CancelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -657,6 +2026,27 @@ func _WorkflowInvocationAPI_List_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowInvocationAPI_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvocationWatchQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkflowInvocationAPIServer).Watch(m, &workflowInvocationAPIWatchServer{stream})
+}
+
+type WorkflowInvocationAPI_WatchServer interface {
+	Send(*InvocationUpdate) error
+	grpc.ServerStream
+}
+
+type workflowInvocationAPIWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *workflowInvocationAPIWatchServer) Send(m *InvocationUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _WorkflowInvocationAPI_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(fission_workflows_types1.ObjectMetadata)
 	if err := dec(in); err != nil {
@@ -693,6 +2083,42 @@ func _WorkflowInvocationAPI_Events_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WorkflowInvocationAPI_GetTaskLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).GetTaskLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/GetTaskLogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).GetTaskLogs(ctx, req.(*TaskLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorkflowInvocationAPI_Explain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.ObjectMetadata)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkflowInvocationAPIServer).Explain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.WorkflowInvocationAPI/Explain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkflowInvocationAPIServer).Explain(ctx, req.(*fission_workflows_types1.ObjectMetadata))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _WorkflowInvocationAPI_Validate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(fission_workflows_types1.WorkflowInvocationSpec)
 	if err := dec(in); err != nil {
@@ -723,6 +2149,10 @@ var _WorkflowInvocationAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "InvokeSync",
 			Handler:    _WorkflowInvocationAPI_InvokeSync_Handler,
 		},
+		{
+			MethodName: "InvokeMany",
+			Handler:    _WorkflowInvocationAPI_InvokeMany_Handler,
+		},
 		{
 			MethodName: "AddTask",
 			Handler:    _WorkflowInvocationAPI_AddTask_Handler,
@@ -743,12 +2173,26 @@ var _WorkflowInvocationAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Events",
 			Handler:    _WorkflowInvocationAPI_Events_Handler,
 		},
+		{
+			MethodName: "GetTaskLogs",
+			Handler:    _WorkflowInvocationAPI_GetTaskLogs_Handler,
+		},
+		{
+			MethodName: "Explain",
+			Handler:    _WorkflowInvocationAPI_Explain_Handler,
+		},
 		{
 			MethodName: "Validate",
 			Handler:    _WorkflowInvocationAPI_Validate_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _WorkflowInvocationAPI_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "pkg/apiserver/apiserver.proto",
 }
 
@@ -756,7 +2200,31 @@ var _WorkflowInvocationAPI_serviceDesc = grpc.ServiceDesc{
 
 type AdminAPIClient interface {
 	Status(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error)
+	// Ready returns whether the workflow engine is ready to accept new invocations. It fails with UNAVAILABLE (HTTP 503)
+	// while the event store is unavailable, so that it can be used as a readiness probe.
+	Ready(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error)
 	Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error)
+	// AuditLog returns the audit events of the state-changing API calls, most recent first.
+	AuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditLog, error)
+	// Restore appends the events of an invocation, such as the history of an invocation exported by a backup, to the
+	// event store. The events must belong to the invocation or its tasks, and the invocation must not exist yet.
+	Restore(ctx context.Context, in *ObjectEvents, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// ListExecutors returns the configuration and utilization of the task executors of the controllers.
+	ListExecutors(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Executors, error)
+	// UpdateExecutor changes the parallelism and the queue size of a task executor at runtime. Zero values are left
+	// unchanged. When the parallelism is decreased, the surplus workers stop after finishing their current task.
+	UpdateExecutor(ctx context.Context, in *ExecutorConfig, opts ...grpc.CallOption) (*ExecutorConfig, error)
+	// ListQuarantined returns the controllers that are quarantined, because their evaluations failed repeatedly. The
+	// events of quarantined controllers, such as those of a poison invocation, are not evaluated.
+	ListQuarantined(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*QuarantinedControllers, error)
+	// ReleaseQuarantined releases a controller from the quarantine, after which its events are evaluated again.
+	ReleaseQuarantined(ctx context.Context, in *QuarantinedControllerRef, opts ...grpc.CallOption) (*QuarantinedController, error)
+	// RebuildProjection discards the cached projection of a workflow or invocation, and rebuilds it from its events in
+	// the event store, such as to repair a projection that was corrupted by a bug in a projector.
+	RebuildProjection(ctx context.Context, in *ProjectionRef, opts ...grpc.CallOption) (*google_protobuf3.Empty, error)
+	// ResyncProjections rebuilds the projections of all workflows or invocations in the event store, and discards the
+	// cached projections that no longer have events in the event store.
+	ResyncProjections(ctx context.Context, in *ResyncProjectionsRequest, opts ...grpc.CallOption) (*ResyncProjectionsResult, error)
 }
 
 type adminAPIClient struct {
@@ -776,6 +2244,15 @@ func (c *adminAPIClient) Status(ctx context.Context, in *google_protobuf3.Empty,
 	return out, nil
 }
 
+func (c *adminAPIClient) Ready(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Health, error) {
+	out := new(Health)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Ready", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *adminAPIClient) Version(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*fission_workflows_version.Info, error) {
 	out := new(fission_workflows_version.Info)
 	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Version", in, out, c.cc, opts...)
@@ -785,11 +2262,107 @@ func (c *adminAPIClient) Version(ctx context.Context, in *google_protobuf3.Empty
 	return out, nil
 }
 
+func (c *adminAPIClient) AuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditLog, error) {
+	out := new(AuditLog)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/AuditLog", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) Restore(ctx context.Context, in *ObjectEvents, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/Restore", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ListExecutors(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*Executors, error) {
+	out := new(Executors)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ListExecutors", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) UpdateExecutor(ctx context.Context, in *ExecutorConfig, opts ...grpc.CallOption) (*ExecutorConfig, error) {
+	out := new(ExecutorConfig)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/UpdateExecutor", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ListQuarantined(ctx context.Context, in *google_protobuf3.Empty, opts ...grpc.CallOption) (*QuarantinedControllers, error) {
+	out := new(QuarantinedControllers)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ListQuarantined", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ReleaseQuarantined(ctx context.Context, in *QuarantinedControllerRef, opts ...grpc.CallOption) (*QuarantinedController, error) {
+	out := new(QuarantinedController)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ReleaseQuarantined", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) RebuildProjection(ctx context.Context, in *ProjectionRef, opts ...grpc.CallOption) (*google_protobuf3.Empty, error) {
+	out := new(google_protobuf3.Empty)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/RebuildProjection", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminAPIClient) ResyncProjections(ctx context.Context, in *ResyncProjectionsRequest, opts ...grpc.CallOption) (*ResyncProjectionsResult, error) {
+	out := new(ResyncProjectionsResult)
+	err := grpc.Invoke(ctx, "/fission.workflows.apiserver.AdminAPI/ResyncProjections", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for AdminAPI service
 
 type AdminAPIServer interface {
 	Status(context.Context, *google_protobuf3.Empty) (*Health, error)
+	// Ready returns whether the workflow engine is ready to accept new invocations. It fails with UNAVAILABLE (HTTP 503)
+	// while the event store is unavailable, so that it can be used as a readiness probe.
+	Ready(context.Context, *google_protobuf3.Empty) (*Health, error)
 	Version(context.Context, *google_protobuf3.Empty) (*fission_workflows_version.Info, error)
+	// AuditLog returns the audit events of the state-changing API calls, most recent first.
+	AuditLog(context.Context, *AuditLogQuery) (*AuditLog, error)
+	// Restore appends the events of an invocation, such as the history of an invocation exported by a backup, to the
+	// event store. The events must belong to the invocation or its tasks, and the invocation must not exist yet.
+	Restore(context.Context, *ObjectEvents) (*google_protobuf3.Empty, error)
+	// ListExecutors returns the configuration and utilization of the task executors of the controllers.
+	ListExecutors(context.Context, *google_protobuf3.Empty) (*Executors, error)
+	// UpdateExecutor changes the parallelism and the queue size of a task executor at runtime. Zero values are left
+	// unchanged. When the parallelism is decreased, the surplus workers stop after finishing their current task.
+	UpdateExecutor(context.Context, *ExecutorConfig) (*ExecutorConfig, error)
+	// ListQuarantined returns the controllers that are quarantined, because their evaluations failed repeatedly. The
+	// events of quarantined controllers, such as those of a poison invocation, are not evaluated.
+	ListQuarantined(context.Context, *google_protobuf3.Empty) (*QuarantinedControllers, error)
+	// ReleaseQuarantined releases a controller from the quarantine, after which its events are evaluated again.
+	ReleaseQuarantined(context.Context, *QuarantinedControllerRef) (*QuarantinedController, error)
+	// RebuildProjection discards the cached projection of a workflow or invocation, and rebuilds it from its events in
+	// the event store, such as to repair a projection that was corrupted by a bug in a projector.
+	RebuildProjection(context.Context, *ProjectionRef) (*google_protobuf3.Empty, error)
+	// ResyncProjections rebuilds the projections of all workflows or invocations in the event store, and discards the
+	// cached projections that no longer have events in the event store.
+	ResyncProjections(context.Context, *ResyncProjectionsRequest) (*ResyncProjectionsResult, error)
 }
 
 func RegisterAdminAPIServer(s *grpc.Server, srv AdminAPIServer) {
@@ -814,6 +2387,24 @@ func _AdminAPI_Status_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminAPI_Ready_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).Ready(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/Ready",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).Ready(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AdminAPI_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(google_protobuf3.Empty)
 	if err := dec(in); err != nil {
@@ -832,6 +2423,150 @@ func _AdminAPI_Version_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminAPI_AuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).AuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/AuditLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).AuditLog(ctx, req.(*AuditLogQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ObjectEvents)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/Restore",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).Restore(ctx, req.(*ObjectEvents))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ListExecutors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ListExecutors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ListExecutors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ListExecutors(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_UpdateExecutor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecutorConfig)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).UpdateExecutor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/UpdateExecutor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).UpdateExecutor(ctx, req.(*ExecutorConfig))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ListQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(google_protobuf3.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ListQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ListQuarantined",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ListQuarantined(ctx, req.(*google_protobuf3.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ReleaseQuarantined_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuarantinedControllerRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ReleaseQuarantined(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ReleaseQuarantined",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ReleaseQuarantined(ctx, req.(*QuarantinedControllerRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_RebuildProjection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProjectionRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).RebuildProjection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/RebuildProjection",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).RebuildProjection(ctx, req.(*ProjectionRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminAPI_ResyncProjections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResyncProjectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminAPIServer).ResyncProjections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.apiserver.AdminAPI/ResyncProjections",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminAPIServer).ResyncProjections(ctx, req.(*ResyncProjectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _AdminAPI_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "fission.workflows.apiserver.AdminAPI",
 	HandlerType: (*AdminAPIServer)(nil),
@@ -840,10 +2575,46 @@ var _AdminAPI_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Status",
 			Handler:    _AdminAPI_Status_Handler,
 		},
+		{
+			MethodName: "Ready",
+			Handler:    _AdminAPI_Ready_Handler,
+		},
 		{
 			MethodName: "Version",
 			Handler:    _AdminAPI_Version_Handler,
 		},
+		{
+			MethodName: "AuditLog",
+			Handler:    _AdminAPI_AuditLog_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _AdminAPI_Restore_Handler,
+		},
+		{
+			MethodName: "ListExecutors",
+			Handler:    _AdminAPI_ListExecutors_Handler,
+		},
+		{
+			MethodName: "UpdateExecutor",
+			Handler:    _AdminAPI_UpdateExecutor_Handler,
+		},
+		{
+			MethodName: "ListQuarantined",
+			Handler:    _AdminAPI_ListQuarantined_Handler,
+		},
+		{
+			MethodName: "ReleaseQuarantined",
+			Handler:    _AdminAPI_ReleaseQuarantined_Handler,
+		},
+		{
+			MethodName: "RebuildProjection",
+			Handler:    _AdminAPI_RebuildProjection_Handler,
+		},
+		{
+			MethodName: "ResyncProjections",
+			Handler:    _AdminAPI_ResyncProjections_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/apiserver/apiserver.proto",
@@ -852,58 +2623,166 @@ var _AdminAPI_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/apiserver/apiserver.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 835 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0xd1, 0x4e, 0xe3, 0x56,
-	0x10, 0x95, 0x01, 0x99, 0x64, 0x4c, 0x51, 0x3a, 0x40, 0x08, 0x01, 0x44, 0x7a, 0x51, 0xd5, 0x00,
-	0xad, 0xdd, 0x06, 0xa9, 0x0f, 0x54, 0xaa, 0x44, 0x01, 0xb5, 0x91, 0x5a, 0xd1, 0x0d, 0x08, 0x24,
-	0xb4, 0x2f, 0x8e, 0x73, 0x93, 0x78, 0x93, 0xd8, 0xc1, 0xbe, 0x09, 0x0a, 0x28, 0x2f, 0xec, 0x0f,
-	0xac, 0xb4, 0x8f, 0xfb, 0xb0, 0xcf, 0xfb, 0x09, 0xfb, 0x1d, 0xfb, 0x0b, 0xfb, 0x21, 0x2b, 0x5f,
-	0x5f, 0x27, 0x0e, 0xc1, 0x89, 0xa3, 0xd5, 0x3e, 0x40, 0x92, 0xf1, 0xcc, 0x9c, 0x39, 0x73, 0xe7,
-	0xcc, 0x35, 0x6c, 0xb7, 0x1b, 0x35, 0x4d, 0x6f, 0x9b, 0x2e, 0x75, 0xba, 0xd4, 0x19, 0x7e, 0x53,
-	0xdb, 0x8e, 0xcd, 0x6c, 0xdc, 0xac, 0x9a, 0xae, 0x6b, 0xda, 0x96, 0x7a, 0x67, 0x3b, 0x8d, 0x6a,
-	0xd3, 0xbe, 0x73, 0xd5, 0x81, 0x4b, 0xf6, 0xa8, 0x66, 0xb2, 0x7a, 0xa7, 0xac, 0x1a, 0x76, 0x4b,
-	0x13, 0x7e, 0xc1, 0xe7, 0x2f, 0x03, 0x7f, 0xcd, 0x03, 0x60, 0xbd, 0x36, 0x75, 0xfd, 0xff, 0x7e,
-	0xe2, 0xec, 0x9f, 0xb1, 0x63, 0xbb, 0xd4, 0xe1, 0x4f, 0xc5, 0xa7, 0x88, 0xff, 0x3d, 0x76, 0x7c,
-	0x95, 0xba, 0xde, 0x9f, 0x88, 0xdb, 0xac, 0xd9, 0x76, 0xad, 0x49, 0x35, 0xfe, 0xab, 0xdc, 0xa9,
-	0x6a, 0xb4, 0xd5, 0x66, 0x3d, 0xf1, 0x70, 0x4b, 0x3c, 0xd4, 0xdb, 0xa6, 0xa6, 0x5b, 0x96, 0xcd,
-	0x74, 0x66, 0xda, 0x96, 0x08, 0x25, 0x3f, 0xc3, 0xd2, 0xb5, 0xc8, 0xfc, 0xaf, 0xe9, 0x32, 0xdc,
-	0x82, 0xe4, 0x00, 0x29, 0x23, 0xe5, 0xe6, 0xf3, 0xc9, 0xd2, 0xd0, 0x40, 0x6a, 0xb0, 0x7c, 0x5c,
-	0xa9, 0x5c, 0xea, 0x6e, 0xa3, 0x44, 0x6f, 0x3b, 0xd4, 0x65, 0x48, 0x60, 0xc9, 0xb4, 0xba, 0xb6,
-	0xc1, 0x93, 0x16, 0x4f, 0x33, 0x52, 0x4e, 0xca, 0x27, 0x4b, 0x23, 0x36, 0xfc, 0x0d, 0x16, 0x98,
-	0xee, 0x36, 0x32, 0x73, 0x39, 0x29, 0xaf, 0x14, 0xb6, 0xd5, 0xf1, 0xf6, 0xfb, 0x4d, 0xe4, 0x79,
-	0xb9, 0x2b, 0x39, 0x84, 0x95, 0xe2, 0x20, 0x85, 0x57, 0xd8, 0x8b, 0x0e, 0x75, 0x7a, 0x53, 0xaa,
-	0x3b, 0x82, 0x74, 0xc0, 0x65, 0x34, 0x18, 0x73, 0xa0, 0x0c, 0x2b, 0x0a, 0x22, 0xc3, 0x26, 0xf2,
-	0x46, 0x82, 0xa5, 0xf3, 0xf2, 0x2b, 0x6a, 0xb0, 0xb3, 0x2e, 0xb5, 0x98, 0x8b, 0x27, 0x90, 0x68,
-	0x51, 0xa6, 0x57, 0x74, 0xa6, 0x73, 0x52, 0x4a, 0xe1, 0xa7, 0xc8, 0xc2, 0xfd, 0xc0, 0xff, 0x84,
-	0x7b, 0x69, 0x10, 0x88, 0x7f, 0x80, 0x4c, 0x79, 0xba, 0xcc, 0x5c, 0x6e, 0x3e, 0xaf, 0x14, 0x76,
-	0x9f, 0x49, 0xe1, 0x3b, 0x30, 0xdb, 0xa1, 0x2a, 0x87, 0x2e, 0x89, 0x10, 0x92, 0x03, 0xf9, 0x1f,
-	0xaa, 0x37, 0x59, 0x1d, 0xd3, 0x20, 0xbb, 0x4c, 0x67, 0x1d, 0x57, 0xb4, 0x57, 0xfc, 0x2a, 0xbc,
-	0x96, 0x41, 0x09, 0x18, 0x1f, 0xff, 0x5f, 0x44, 0x0b, 0xe4, 0x13, 0x87, 0xea, 0x8c, 0xe2, 0x8f,
-	0x91, 0xb5, 0x06, 0xfe, 0x17, 0x6d, 0x6a, 0x64, 0xe3, 0x52, 0x22, 0xab, 0x8f, 0x9f, 0x3e, 0xbf,
-	0x9d, 0x5b, 0x26, 0x49, 0x2d, 0x70, 0x3c, 0x92, 0xf6, 0xf1, 0x16, 0xc0, 0xc7, 0xbb, 0xe8, 0x59,
-	0x46, 0x5c, 0xcc, 0x1f, 0xa6, 0xba, 0x91, 0x0d, 0x8e, 0xb6, 0x42, 0x96, 0x07, 0x68, 0x9a, 0xdb,
-	0xb3, 0x0c, 0x0f, 0xf2, 0x25, 0x2c, 0xf0, 0x13, 0x4d, 0xab, 0xfe, 0x58, 0xab, 0xc1, 0xcc, 0xab,
-	0x67, 0xde, 0xcc, 0x67, 0xf7, 0xd4, 0x09, 0xe2, 0x56, 0xc3, 0xa3, 0x4e, 0xbe, 0xe7, 0x28, 0x0a,
-	0x0e, 0x39, 0xa1, 0x09, 0xf3, 0x7f, 0x53, 0x86, 0x71, 0xdb, 0x12, 0x87, 0x4b, 0x9a, 0xa3, 0xa4,
-	0x30, 0xc4, 0xe5, 0xc1, 0xac, 0xf4, 0x51, 0x07, 0xf9, 0x94, 0x36, 0x29, 0xa3, 0xf1, 0xd1, 0x22,
-	0x38, 0x07, 0x10, 0xfb, 0x4f, 0x21, 0xea, 0x90, 0xb8, 0xd2, 0x9b, 0x66, 0x65, 0x86, 0x81, 0x88,
-	0x82, 0xd8, 0xe6, 0x10, 0xeb, 0x04, 0x87, 0x10, 0x5d, 0x91, 0xda, 0x3b, 0x95, 0x07, 0x90, 0x85,
-	0x6c, 0x62, 0x93, 0x99, 0x7c, 0x50, 0x61, 0x29, 0x06, 0xe0, 0xb8, 0x36, 0xca, 0x4f, 0xf3, 0x75,
-	0x52, 0xf8, 0x90, 0x80, 0xb5, 0x71, 0xdd, 0x7b, 0x7a, 0xb8, 0x07, 0xd9, 0x33, 0x34, 0x28, 0x6a,
-	0x53, 0xe9, 0x0f, 0x23, 0x67, 0x53, 0x86, 0x68, 0x3e, 0x51, 0xb4, 0xe1, 0x3a, 0xf1, 0x5a, 0xf2,
-	0x4e, 0x02, 0xf0, 0xc1, 0xb9, 0x38, 0x66, 0x2e, 0xe0, 0x60, 0x86, 0x00, 0xa2, 0xf1, 0x22, 0xf6,
-	0x48, 0x2a, 0x54, 0x44, 0x20, 0x99, 0x1b, 0xc4, 0x31, 0x33, 0xbe, 0x97, 0x60, 0x51, 0x6c, 0x72,
-	0x3c, 0x98, 0x78, 0x12, 0xa3, 0xfb, 0x3e, 0x72, 0x40, 0xce, 0x79, 0x05, 0x45, 0x92, 0x0b, 0x43,
-	0x3d, 0x84, 0xaf, 0x81, 0xbe, 0xe6, 0x6d, 0x76, 0xd7, 0xab, 0x88, 0x64, 0xa7, 0xba, 0xa1, 0x01,
-	0xf2, 0x89, 0x6e, 0x19, 0xb4, 0xf9, 0xf5, 0xfa, 0xc8, 0xf0, 0xda, 0x70, 0x3f, 0x35, 0x0a, 0x5a,
-	0xe9, 0xe3, 0xa3, 0x24, 0xd6, 0xc9, 0xaf, 0x13, 0x7b, 0xf0, 0xcc, 0x55, 0x94, 0x3d, 0x8c, 0xb5,
-	0x68, 0x46, 0x23, 0xc9, 0x0a, 0xaf, 0xe4, 0x3b, 0x0c, 0x0f, 0x0b, 0x76, 0x66, 0x5c, 0x3a, 0x33,
-	0x4d, 0x86, 0xe0, 0x8e, 0xe3, 0xdc, 0xfb, 0xdf, 0x54, 0xb3, 0x3b, 0x1c, 0x77, 0x03, 0xd7, 0x9f,
-	0xe2, 0x0a, 0xd5, 0x22, 0x0b, 0x2d, 0xa7, 0x99, 0xc5, 0x11, 0x75, 0xd2, 0x02, 0x95, 0xac, 0x86,
-	0x51, 0x43, 0x8b, 0xaa, 0xf0, 0x51, 0x82, 0xc4, 0x71, 0xa5, 0x65, 0xf2, 0xf5, 0x70, 0x0d, 0xf2,
-	0x05, 0xbf, 0x48, 0x23, 0x6f, 0x93, 0xdd, 0x89, 0x84, 0xfd, 0xdb, 0x99, 0xa4, 0x38, 0x28, 0x60,
-	0x42, 0xab, 0x73, 0xc3, 0x3d, 0x5e, 0xc2, 0xe2, 0x95, 0xff, 0x62, 0x17, 0x99, 0x79, 0xe7, 0x99,
-	0xcc, 0xc1, 0xcb, 0x60, 0xd1, 0xaa, 0xda, 0xa1, 0xac, 0xc2, 0xfc, 0x97, 0x72, 0x93, 0x1c, 0x60,
-	0x97, 0x65, 0x9e, 0xef, 0xf0, 0x4b, 0x00, 0x00, 0x00, 0xff, 0xff, 0x8f, 0xab, 0xd0, 0x49, 0xeb,
-	0x0a, 0x00, 0x00,
+	// 2563 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x19, 0x5b, 0x6f, 0x1c, 0x57,
+	0x99, 0x59, 0x7b, 0xd7, 0xbb, 0xdf, 0xda, 0xce, 0xfa, 0xc4, 0x71, 0xb6, 0xd3, 0x5c, 0xcc, 0x69,
+	0xd3, 0xa6, 0x4e, 0xbb, 0x93, 0x38, 0x69, 0x69, 0x02, 0x6d, 0x71, 0x6d, 0x37, 0xac, 0xe2, 0x34,
+	0xc9, 0xd8, 0x49, 0xa0, 0x08, 0xa4, 0xf1, 0xce, 0xd9, 0xf5, 0xe0, 0xd9, 0x99, 0xc9, 0xcc, 0x19,
+	0x27, 0x1b, 0xcb, 0x12, 0x54, 0x02, 0x2a, 0x81, 0x10, 0x52, 0x11, 0x0f, 0x20, 0x04, 0x12, 0xe2,
+	0x91, 0x1f, 0xc0, 0x0b, 0x6f, 0xfc, 0x02, 0x9e, 0x79, 0xe3, 0x17, 0xf0, 0x0b, 0xd0, 0xb9, 0xcd,
+	0x65, 0xed, 0x5d, 0xcf, 0x16, 0xf1, 0x90, 0x78, 0xbe, 0x73, 0xbe, 0xdb, 0xf9, 0xae, 0xe7, 0x7c,
+	0x0b, 0x17, 0x83, 0xfd, 0x9e, 0x61, 0x05, 0x4e, 0x44, 0xc2, 0x03, 0x12, 0xa6, 0x5f, 0xad, 0x20,
+	0xf4, 0xa9, 0x8f, 0x5e, 0xed, 0x3a, 0x51, 0xe4, 0xf8, 0x5e, 0xeb, 0xb9, 0x1f, 0xee, 0x77, 0x5d,
+	0xff, 0x79, 0xd4, 0x4a, 0x50, 0xf4, 0x3b, 0x3d, 0x87, 0xee, 0xc5, 0xbb, 0xad, 0x8e, 0xdf, 0x37,
+	0x24, 0x9e, 0xfa, 0xfb, 0x4e, 0x82, 0x6f, 0x30, 0x01, 0x74, 0x10, 0x90, 0x48, 0xfc, 0x2f, 0x18,
+	0xeb, 0x5b, 0x5f, 0x81, 0xd6, 0x3e, 0xb0, 0xdc, 0x38, 0xff, 0x2d, 0xb9, 0x7d, 0x58, 0x98, 0xdb,
+	0x01, 0x09, 0xf9, 0xae, 0xfc, 0x2b, 0xe9, 0xdf, 0x2b, 0x4c, 0xdf, 0x25, 0x11, 0xfb, 0x27, 0xe9,
+	0x3e, 0x2e, 0x4c, 0x17, 0x75, 0xf6, 0x88, 0x1d, 0xbb, 0x24, 0x4c, 0xbf, 0x24, 0x8f, 0x57, 0x7b,
+	0xbe, 0xdf, 0x73, 0x89, 0xc1, 0xa1, 0xdd, 0xb8, 0x6b, 0x90, 0x7e, 0x40, 0x07, 0x72, 0xf3, 0xf2,
+	0xf0, 0x26, 0x75, 0xfa, 0x24, 0xa2, 0x56, 0x3f, 0x90, 0x08, 0x17, 0x24, 0x82, 0x15, 0x38, 0x86,
+	0xe5, 0x79, 0x3e, 0xb5, 0xa8, 0xe3, 0x7b, 0x52, 0x3f, 0xec, 0x41, 0xe3, 0x89, 0x13, 0xc5, 0x96,
+	0xeb, 0xbc, 0x24, 0x26, 0x79, 0x16, 0x93, 0x88, 0xa2, 0x4b, 0x00, 0x4a, 0xb5, 0xb6, 0xdd, 0xd4,
+	0x96, 0xb5, 0xab, 0x35, 0x33, 0xb3, 0x82, 0x30, 0xcc, 0x3a, 0xde, 0x81, 0xdf, 0xe1, 0x8c, 0xda,
+	0x76, 0xb3, 0xc4, 0x31, 0x72, 0x6b, 0x68, 0x09, 0x2a, 0x5d, 0x3f, 0xec, 0x5b, 0xb4, 0x39, 0xc5,
+	0x77, 0x25, 0x84, 0x3f, 0x80, 0x39, 0x25, 0x8f, 0xa3, 0x66, 0x10, 0xb5, 0x2c, 0x22, 0x5a, 0x84,
+	0x72, 0x2f, 0xb4, 0x82, 0x3d, 0xc9, 0x5d, 0x00, 0xf8, 0x36, 0x2c, 0x3c, 0x95, 0x8a, 0x6c, 0x39,
+	0x11, 0x7d, 0x14, 0x93, 0x70, 0x80, 0x5e, 0x87, 0x39, 0xd7, 0xda, 0x25, 0xee, 0x36, 0x71, 0x49,
+	0x87, 0xfa, 0xa1, 0xe4, 0x94, 0x5f, 0xc4, 0x6f, 0xc3, 0x6c, 0x96, 0x14, 0x5d, 0x80, 0x5a, 0xe2,
+	0x80, 0xa6, 0xb6, 0x3c, 0x75, 0xb5, 0x66, 0xa6, 0x0b, 0xf8, 0x3f, 0x1a, 0x9c, 0x53, 0xe8, 0x8f,
+	0x03, 0xdb, 0xa2, 0x89, 0x75, 0xe6, 0xa1, 0xe4, 0x28, 0xab, 0x94, 0x1c, 0x1b, 0xdd, 0x86, 0xe9,
+	0x28, 0x20, 0x1d, 0xae, 0x67, 0x7d, 0xf5, 0x4a, 0xeb, 0x78, 0x3e, 0x88, 0xa8, 0x56, 0xdc, 0xb6,
+	0x03, 0xd2, 0x31, 0x39, 0x09, 0xfa, 0x36, 0x94, 0x03, 0x8b, 0x76, 0xf6, 0xb8, 0x8d, 0xea, 0xab,
+	0x2b, 0xad, 0x31, 0xb9, 0x94, 0xd0, 0x3f, 0x64, 0x14, 0xa6, 0x20, 0x44, 0x5b, 0x50, 0x09, 0x7c,
+	0xd7, 0xe9, 0x0c, 0x9a, 0xd3, 0xcb, 0xda, 0xd5, 0xf9, 0xd5, 0x5b, 0x63, 0x59, 0x98, 0xb1, 0xe7,
+	0x39, 0x5e, 0xaf, 0x9d, 0x38, 0xea, 0x21, 0xa7, 0x35, 0x25, 0x0f, 0xfc, 0x87, 0x12, 0xcc, 0xe5,
+	0xc4, 0xa0, 0x7b, 0x50, 0xa6, 0x56, 0xb4, 0x2f, 0x0c, 0x54, 0x5f, 0x7d, 0xb7, 0xb8, 0x86, 0xad,
+	0x1d, 0x46, 0xb7, 0xe9, 0xd1, 0x70, 0x60, 0x0a, 0x1e, 0x68, 0x19, 0xea, 0x21, 0xe9, 0xfb, 0x07,
+	0x84, 0x6f, 0x35, 0x4b, 0xdc, 0xe6, 0xd9, 0x25, 0x16, 0x79, 0x7e, 0x4c, 0x83, 0x98, 0x32, 0x50,
+	0x46, 0x4e, 0x66, 0x85, 0x71, 0xb0, 0x49, 0xd4, 0x09, 0x9d, 0x80, 0x69, 0xcf, 0xcf, 0x5c, 0x33,
+	0xb3, 0x4b, 0xfa, 0xf7, 0x01, 0x52, 0xc1, 0xa8, 0x01, 0x53, 0xfb, 0x64, 0x20, 0x9d, 0xc5, 0x3e,
+	0xd1, 0x37, 0xa0, 0xcc, 0xeb, 0x82, 0x74, 0xd7, 0xd7, 0x47, 0xba, 0x8b, 0x71, 0xe1, 0xae, 0x12,
+	0xf8, 0x77, 0x4a, 0xef, 0x6b, 0xf8, 0xa7, 0x1a, 0x34, 0xd5, 0x21, 0x9f, 0x58, 0xae, 0x63, 0x73,
+	0x23, 0x9a, 0x24, 0x8a, 0x5d, 0x1e, 0xb0, 0x07, 0x6c, 0x8d, 0x4b, 0xab, 0x9a, 0x02, 0x40, 0xdb,
+	0x50, 0xb7, 0x1d, 0xab, 0xe7, 0xf9, 0x11, 0x75, 0x3a, 0xe2, 0xcc, 0xf5, 0xd5, 0x1b, 0x63, 0xcd,
+	0x98, 0x72, 0xde, 0x48, 0x28, 0xcd, 0x2c, 0x17, 0x7c, 0x00, 0x8b, 0x27, 0x21, 0xb1, 0x5c, 0x0a,
+	0x89, 0x15, 0xf9, 0x9e, 0xca, 0x25, 0x01, 0xa1, 0x26, 0xcc, 0xf4, 0x49, 0x14, 0x59, 0x3d, 0x22,
+	0xb3, 0x49, 0x81, 0x8c, 0x82, 0xf9, 0xa6, 0x6d, 0xab, 0x34, 0x15, 0x10, 0x3b, 0x4c, 0xd7, 0x21,
+	0xae, 0x2d, 0x4d, 0x2c, 0x00, 0xfc, 0x06, 0x20, 0x75, 0xfc, 0xa7, 0xcc, 0xc7, 0x22, 0xfd, 0x1a,
+	0x30, 0xe5, 0xd8, 0x2a, 0x85, 0xd8, 0x27, 0x26, 0x30, 0x9f, 0xcf, 0x1d, 0xc6, 0x8f, 0x1c, 0x10,
+	0x4f, 0x25, 0xb9, 0x00, 0xd0, 0x07, 0x50, 0x55, 0x06, 0x38, 0xd5, 0x1f, 0x8a, 0xa1, 0x99, 0x90,
+	0xe0, 0xbf, 0x69, 0xb0, 0xc0, 0x62, 0x79, 0x9f, 0xdc, 0xb7, 0xbc, 0x81, 0xca, 0xcf, 0x75, 0x99,
+	0x8f, 0x1a, 0x67, 0x68, 0x9c, 0xca, 0x30, 0xcd, 0x86, 0x4c, 0x66, 0x6e, 0x42, 0xc5, 0xf1, 0x82,
+	0x98, 0x2a, 0x8f, 0xbd, 0x33, 0xd6, 0x63, 0x29, 0x8b, 0x36, 0x27, 0x32, 0x25, 0x31, 0x37, 0xbc,
+	0xf5, 0xc2, 0xb4, 0x28, 0xe1, 0xf6, 0xd5, 0x4c, 0x05, 0xe2, 0x7f, 0x68, 0xd0, 0x18, 0x26, 0x43,
+	0x8f, 0x12, 0xa9, 0x22, 0xdd, 0x6e, 0x4f, 0x24, 0xb5, 0x25, 0xfe, 0x88, 0x94, 0x93, 0x8c, 0xf4,
+	0x1f, 0x42, 0x3d, 0xb3, 0x7c, 0x42, 0x42, 0xdc, 0xce, 0x27, 0xc4, 0x6b, 0xa3, 0x13, 0x82, 0xf5,
+	0xd4, 0x27, 0x0c, 0x35, 0x9b, 0x12, 0x3f, 0x00, 0x94, 0x75, 0x41, 0x14, 0xf8, 0x5e, 0x44, 0xd0,
+	0x5d, 0x98, 0x09, 0x79, 0x56, 0xa8, 0x93, 0x9c, 0x6e, 0xbf, 0x84, 0x43, 0xec, 0x52, 0x53, 0x51,
+	0xe3, 0xef, 0x42, 0x63, 0x78, 0xf3, 0x58, 0x01, 0xbe, 0x05, 0x65, 0x12, 0x86, 0x7e, 0x28, 0x4f,
+	0x70, 0x69, 0xe4, 0x09, 0x36, 0x19, 0x96, 0x29, 0x90, 0xf1, 0x23, 0x98, 0x5b, 0xb7, 0xbc, 0x0e,
+	0x71, 0x47, 0xd5, 0xf5, 0x34, 0x99, 0x4a, 0xc3, 0xc9, 0xd4, 0xb1, 0xa2, 0x8e, 0x65, 0x0b, 0x9f,
+	0x56, 0x4d, 0x05, 0xe2, 0x1e, 0xcc, 0xaf, 0xd9, 0x36, 0x2b, 0x1c, 0x8a, 0x67, 0xbe, 0x53, 0x6e,
+	0x48, 0xee, 0xb9, 0x35, 0x74, 0x03, 0xa6, 0x59, 0xd2, 0x49, 0xed, 0x2f, 0x8e, 0x2d, 0x48, 0x26,
+	0x47, 0xc5, 0xf7, 0xe1, 0x0c, 0x83, 0xb6, 0xfc, 0x5e, 0x34, 0x89, 0x24, 0x95, 0xec, 0x1b, 0xea,
+	0x44, 0x02, 0xc2, 0xf7, 0xa0, 0xaa, 0xd8, 0xa1, 0x8f, 0x60, 0x86, 0x78, 0x34, 0x74, 0x88, 0xf2,
+	0xdc, 0x95, 0xb1, 0x9e, 0xdb, 0xf2, 0x7b, 0x22, 0xde, 0x14, 0x15, 0xfe, 0x95, 0x06, 0x55, 0xb5,
+	0x8a, 0xde, 0x87, 0x5a, 0x72, 0x1d, 0x91, 0x09, 0xa9, 0xb7, 0xc4, 0x7d, 0xa4, 0xa5, 0x2e, 0x2c,
+	0xad, 0x1d, 0x85, 0x61, 0xa6, 0xc8, 0xe3, 0x4b, 0x56, 0x44, 0x43, 0x62, 0xf5, 0x55, 0xc9, 0x12,
+	0x10, 0x5f, 0xf7, 0xe3, 0xb0, 0x43, 0x64, 0xcd, 0x92, 0x10, 0xfe, 0x1e, 0x9c, 0x4d, 0x33, 0x25,
+	0xbd, 0x34, 0x8c, 0x6d, 0xff, 0xc7, 0xaf, 0x14, 0xa5, 0x93, 0xae, 0x14, 0x77, 0x60, 0xe9, 0x78,
+	0x15, 0xe1, 0x97, 0x8b, 0x65, 0xa8, 0xa7, 0xa6, 0x57, 0xfc, 0xb3, 0x4b, 0xf8, 0x13, 0x58, 0x4c,
+	0x69, 0xc6, 0x55, 0xd3, 0xbc, 0xa6, 0xa5, 0xe1, 0x8b, 0x4a, 0x9c, 0xad, 0x23, 0x63, 0xab, 0xed,
+	0x3d, 0x80, 0x54, 0x01, 0x19, 0x6e, 0xd7, 0x26, 0x28, 0x8f, 0x66, 0x86, 0x1c, 0xff, 0x5a, 0x83,
+	0xd9, 0x07, 0xbb, 0x3f, 0x22, 0x1d, 0xba, 0xc9, 0x98, 0x47, 0x68, 0x1d, 0xaa, 0x7d, 0x42, 0x2d,
+	0xdb, 0xa2, 0x96, 0xf4, 0xf4, 0x9b, 0x23, 0x79, 0x0b, 0xc2, 0xfb, 0x12, 0xdd, 0x4c, 0x08, 0xd1,
+	0x37, 0xa1, 0xc2, 0x75, 0x55, 0x65, 0xf7, 0xa4, 0x6a, 0x24, 0x10, 0xa8, 0x1f, 0x92, 0x16, 0x17,
+	0x6d, 0x4a, 0x12, 0xbc, 0x0c, 0x95, 0xef, 0x10, 0xcb, 0xa5, 0x7b, 0x22, 0x44, 0x2c, 0x1a, 0x47,
+	0xaa, 0x0f, 0x0a, 0x08, 0xff, 0x56, 0x83, 0xf9, 0xcd, 0x17, 0xa4, 0x13, 0x53, 0x3f, 0x5c, 0xf7,
+	0xbd, 0xae, 0xd3, 0x43, 0x08, 0xa6, 0x3d, 0xab, 0x4f, 0x24, 0x22, 0xff, 0x66, 0xce, 0x0b, 0xac,
+	0xd0, 0x72, 0x5d, 0xe2, 0x3a, 0x51, 0x9f, 0x5b, 0xaa, 0x6c, 0x66, 0x97, 0x98, 0x4b, 0x9e, 0xc5,
+	0x24, 0x26, 0xdb, 0xce, 0x4b, 0x51, 0x05, 0xca, 0x66, 0xba, 0xc0, 0x62, 0x97, 0xa9, 0x4b, 0xc2,
+	0x88, 0x87, 0x62, 0xd9, 0x54, 0x20, 0x53, 0x8c, 0xa3, 0xd9, 0xcd, 0x32, 0xdf, 0x90, 0x10, 0x7e,
+	0x02, 0x35, 0xa5, 0x57, 0x84, 0xda, 0x50, 0x23, 0x0a, 0x90, 0x49, 0x78, 0x6d, 0x6c, 0x12, 0xe6,
+	0x8f, 0x64, 0xa6, 0xd4, 0xf8, 0xaf, 0x1a, 0x9c, 0x7b, 0x14, 0x5b, 0xa1, 0xe5, 0x51, 0xc7, 0x23,
+	0xf6, 0xba, 0xef, 0xd1, 0xd0, 0x77, 0x5d, 0x12, 0x72, 0x13, 0x0d, 0x22, 0x4a, 0xfa, 0x89, 0x89,
+	0x38, 0xa4, 0x1a, 0x44, 0x29, 0x6d, 0x10, 0xd7, 0xa1, 0x1c, 0x39, 0x5e, 0x87, 0x34, 0xa7, 0x4e,
+	0xcd, 0x5f, 0x81, 0x88, 0x74, 0xa8, 0x76, 0x2d, 0xc7, 0x8d, 0x43, 0xa2, 0x0c, 0x90, 0xc0, 0xcc,
+	0x72, 0xae, 0x15, 0x51, 0x5e, 0x8a, 0xb9, 0x11, 0x6a, 0x66, 0xba, 0x80, 0x3d, 0x58, 0x3a, 0x51,
+	0xdd, 0x08, 0xed, 0x40, 0xbd, 0x93, 0x82, 0xd2, 0x2c, 0xab, 0x63, 0xcd, 0x72, 0x22, 0x27, 0x33,
+	0xcb, 0x06, 0x6f, 0x40, 0xf3, 0x64, 0x2c, 0xd2, 0x2d, 0x6e, 0x21, 0x7c, 0x13, 0xe6, 0x1e, 0x86,
+	0x3e, 0x0b, 0x69, 0x7e, 0x1b, 0xec, 0xb2, 0xa0, 0x62, 0x81, 0xae, 0x82, 0x8a, 0x7d, 0xcb, 0xf6,
+	0x52, 0x52, 0xed, 0x05, 0xb7, 0xa0, 0x69, 0x92, 0x68, 0xe0, 0x75, 0x52, 0xd2, 0xa4, 0x98, 0x9f,
+	0x40, 0x8f, 0xbf, 0xd4, 0xe0, 0xfc, 0x09, 0x04, 0xbc, 0x23, 0x36, 0x59, 0xbb, 0xdd, 0x8d, 0x1d,
+	0x57, 0x64, 0x7c, 0xd9, 0x54, 0xa0, 0xd8, 0x61, 0xf7, 0x6b, 0x5b, 0x86, 0xb1, 0x02, 0xd1, 0x06,
+	0x54, 0x98, 0x53, 0x08, 0xbb, 0xf9, 0x31, 0x5b, 0xbe, 0x3d, 0xd6, 0x96, 0xa9, 0x4c, 0xd1, 0x44,
+	0x25, 0x2d, 0x26, 0x70, 0x66, 0x68, 0x0b, 0x7d, 0x0b, 0xa6, 0x42, 0xd2, 0x6d, 0x6a, 0x05, 0x9e,
+	0x34, 0x39, 0xab, 0x99, 0x8c, 0x8c, 0x97, 0xae, 0xa4, 0x99, 0xd7, 0x54, 0xb3, 0xfe, 0xa2, 0x04,
+	0xb0, 0x16, 0xdb, 0x8e, 0x28, 0x36, 0xc7, 0x5a, 0x75, 0xae, 0xcd, 0x94, 0x26, 0x6c, 0x33, 0x51,
+	0xcc, 0xab, 0x91, 0xec, 0x26, 0x0a, 0x64, 0x3e, 0x08, 0x08, 0x09, 0x65, 0x33, 0xe1, 0xdf, 0x2c,
+	0x24, 0xfa, 0x84, 0xee, 0xf9, 0xb6, 0x8c, 0x5c, 0x09, 0xb1, 0x80, 0x0f, 0x89, 0x6c, 0x3e, 0x15,
+	0xbe, 0x93, 0xc0, 0xac, 0x93, 0x84, 0xc2, 0xad, 0x1b, 0x4e, 0x8f, 0x44, 0xb4, 0x39, 0x23, 0x3a,
+	0x49, 0x6e, 0x91, 0x49, 0xeb, 0xf8, 0x36, 0x69, 0x56, 0x85, 0x34, 0xf6, 0x9d, 0x9a, 0xa2, 0x96,
+	0x35, 0xc5, 0x5f, 0x34, 0x98, 0xe3, 0xa6, 0xd8, 0xf2, 0x7b, 0xa2, 0x63, 0x64, 0xce, 0xa0, 0xe5,
+	0xcf, 0x90, 0xea, 0x5b, 0x1a, 0xa9, 0xef, 0xd4, 0x90, 0xbe, 0x49, 0xba, 0x4f, 0x17, 0x4d, 0xf7,
+	0x45, 0x28, 0xbb, 0x4e, 0xdf, 0xa1, 0xb2, 0xa6, 0x09, 0x80, 0x5d, 0x2a, 0x94, 0x9a, 0xe8, 0xa3,
+	0xa4, 0xac, 0x8b, 0xbc, 0x7d, 0x73, 0x6c, 0x54, 0xa4, 0x8e, 0x56, 0xa5, 0x7d, 0xe5, 0x43, 0x38,
+	0x3f, 0xe2, 0xed, 0x8a, 0x66, 0xa1, 0xba, 0xfe, 0xe0, 0xd3, 0x9d, 0xf6, 0xa7, 0x8f, 0x37, 0x1b,
+	0x5f, 0x43, 0x55, 0x98, 0xfe, 0x64, 0xad, 0xbd, 0xd5, 0xd0, 0x50, 0x1d, 0x66, 0xee, 0xb7, 0xef,
+	0x9a, 0x6b, 0x3b, 0x9b, 0x8d, 0xd2, 0xea, 0xdf, 0x6b, 0x50, 0x57, 0x0d, 0x6d, 0xed, 0x61, 0x1b,
+	0x79, 0x50, 0x59, 0x0f, 0x09, 0x6b, 0x95, 0xc5, 0xde, 0xeb, 0x7a, 0xd1, 0x5e, 0x86, 0x17, 0x3f,
+	0xff, 0xe7, 0xbf, 0xbf, 0x2c, 0xcd, 0xe3, 0x9a, 0xa1, 0x10, 0xef, 0x68, 0x2b, 0xe8, 0x19, 0x80,
+	0x90, 0xb7, 0x3d, 0xf0, 0x3a, 0x45, 0x65, 0x9e, 0xfe, 0x16, 0xc2, 0xaf, 0x70, 0x69, 0x67, 0xf1,
+	0x7c, 0x22, 0xcd, 0x60, 0x15, 0x82, 0x89, 0xf4, 0xa1, 0x22, 0x6f, 0x03, 0xab, 0x85, 0x1e, 0xed,
+	0xb9, 0x21, 0x87, 0xbe, 0x74, 0xcc, 0xed, 0x9b, 0x6c, 0xe6, 0xa4, 0x04, 0xea, 0x19, 0x81, 0x87,
+	0x8e, 0x7d, 0xc4, 0x04, 0x52, 0x98, 0xe6, 0x57, 0x9f, 0x56, 0x21, 0x71, 0xc9, 0x45, 0x4c, 0x7f,
+	0xab, 0x30, 0x3e, 0x5e, 0xe0, 0xd2, 0xeb, 0x28, 0x35, 0x2e, 0xfa, 0xb1, 0x06, 0x65, 0x7e, 0x7b,
+	0x42, 0x46, 0x21, 0x3e, 0xe9, 0x4d, 0x4b, 0xbf, 0x36, 0x81, 0x5d, 0xf0, 0x79, 0x2e, 0x7a, 0x01,
+	0x9d, 0x49, 0x0f, 0xfe, 0x9c, 0xb1, 0xba, 0xae, 0x21, 0x07, 0xa6, 0xee, 0x12, 0x8a, 0x8a, 0x86,
+	0x48, 0x11, 0xbf, 0x2e, 0x71, 0x69, 0x0d, 0x34, 0x64, 0x66, 0x64, 0x41, 0x65, 0x83, 0xb8, 0x84,
+	0x92, 0xe2, 0xd2, 0x46, 0x79, 0x52, 0x8a, 0x58, 0x19, 0x16, 0xf1, 0x73, 0x0d, 0xaa, 0x72, 0xb8,
+	0x50, 0x38, 0x3b, 0x8a, 0x8d, 0x85, 0x86, 0x27, 0x26, 0xf8, 0x22, 0x57, 0xe1, 0x3c, 0x46, 0xa9,
+	0x0a, 0x07, 0x52, 0x32, 0x0b, 0xa8, 0x43, 0xa8, 0xc8, 0xbb, 0x65, 0xe1, 0xc3, 0x8e, 0x8f, 0xa5,
+	0xec, 0x7d, 0x55, 0x09, 0x47, 0xe7, 0xf2, 0xe7, 0x37, 0x44, 0xc5, 0x41, 0xbf, 0xd4, 0xa0, 0x96,
+	0x0c, 0x46, 0xd1, 0xf8, 0xe7, 0xeb, 0xf0, 0x00, 0x55, 0x5f, 0x29, 0x84, 0x2e, 0x2e, 0xd2, 0xaf,
+	0x73, 0x3d, 0x2e, 0xa1, 0x0b, 0x19, 0x3d, 0xd2, 0x59, 0xeb, 0x91, 0xc1, 0xe7, 0x9e, 0xab, 0xff,
+	0x9a, 0x4d, 0xc7, 0x91, 0x69, 0x09, 0x64, 0xa5, 0xec, 0x25, 0x54, 0xc4, 0x0b, 0x19, 0x4d, 0x3a,
+	0xea, 0x28, 0x5e, 0xd4, 0x64, 0xac, 0xe0, 0xba, 0x91, 0xbe, 0x00, 0x98, 0x87, 0x7e, 0xaf, 0x01,
+	0x08, 0xe1, 0xbc, 0xae, 0x4d, 0xac, 0xc0, 0x24, 0xaf, 0x0f, 0x6c, 0x70, 0x25, 0xde, 0xc2, 0x8d,
+	0x8c, 0x12, 0xaa, 0xda, 0x7d, 0x86, 0xd0, 0xb1, 0x65, 0xf4, 0x8b, 0x44, 0x3b, 0x36, 0x3c, 0x38,
+	0xa5, 0x2e, 0x1d, 0x9b, 0x23, 0xe9, 0x46, 0x61, 0x7c, 0x31, 0xf4, 0xc0, 0x17, 0xb8, 0x82, 0x4b,
+	0x78, 0x21, 0xab, 0xc9, 0x2e, 0x2b, 0x12, 0xcc, 0x56, 0x7f, 0xd4, 0x60, 0x46, 0x4e, 0x07, 0xd0,
+	0xf8, 0xca, 0x93, 0x9f, 0x21, 0x8c, 0x4c, 0xe0, 0x07, 0x5c, 0x5c, 0x1b, 0x2f, 0x67, 0xc5, 0x1d,
+	0x66, 0x1f, 0xfc, 0x47, 0x06, 0x9f, 0xbb, 0x32, 0xfb, 0x60, 0xfd, 0x54, 0x34, 0xd4, 0x85, 0x8a,
+	0x98, 0x88, 0xa0, 0xf1, 0xf1, 0x9b, 0x1b, 0x9b, 0x8c, 0x54, 0xaf, 0xc9, 0xd5, 0x43, 0x2b, 0x8d,
+	0xbc, 0x5c, 0xfb, 0x08, 0x7d, 0xae, 0xc9, 0x4e, 0x71, 0xbd, 0xe0, 0x78, 0x2b, 0xed, 0x15, 0x37,
+	0x0b, 0x15, 0x9a, 0x3c, 0x25, 0x3e, 0xcb, 0x35, 0x99, 0x43, 0xd9, 0xe8, 0x45, 0x3f, 0x4b, 0xfa,
+	0xc6, 0x8d, 0x82, 0x5a, 0x64, 0x3a, 0x47, 0xd1, 0x69, 0xa0, 0xec, 0x1d, 0xb2, 0x69, 0xa2, 0x5c,
+	0x60, 0xa8, 0xee, 0x11, 0x4f, 0xd8, 0x3d, 0x26, 0xca, 0x19, 0xe9, 0x04, 0x74, 0xdc, 0x09, 0x47,
+	0xff, 0xd7, 0xe2, 0x7a, 0x99, 0xcb, 0x7d, 0x05, 0x9d, 0x1f, 0x96, 0xab, 0xca, 0xeb, 0xef, 0x34,
+	0xa8, 0xdf, 0x25, 0x34, 0x19, 0x3b, 0x8d, 0x7f, 0x7d, 0x0c, 0x0d, 0xbb, 0xf4, 0x2b, 0x85, 0xb0,
+	0xf1, 0x7b, 0x5c, 0x8b, 0xeb, 0xa8, 0x75, 0x5a, 0xe8, 0x1b, 0x87, 0x62, 0x12, 0x76, 0x64, 0xb8,
+	0x4c, 0x99, 0x43, 0x98, 0xd9, 0x7c, 0x11, 0xb8, 0x96, 0xe3, 0x15, 0x37, 0xce, 0x49, 0x2a, 0xa5,
+	0xbf, 0xe3, 0x6d, 0xcb, 0x2f, 0xbc, 0xcc, 0x55, 0xd2, 0x51, 0xf3, 0xb8, 0x61, 0xa4, 0x44, 0x9a,
+	0x69, 0xbf, 0x13, 0x17, 0xd4, 0x51, 0xc9, 0x28, 0xfd, 0x81, 0x17, 0xb3, 0x62, 0x33, 0xbd, 0x76,
+	0xf5, 0x37, 0x00, 0xd5, 0x35, 0xbb, 0xef, 0xf0, 0x96, 0xf2, 0x14, 0x2a, 0xdb, 0x7c, 0x60, 0x82,
+	0x46, 0xf0, 0xd3, 0x5f, 0x1b, 0xeb, 0x00, 0x31, 0x85, 0xc1, 0x0d, 0x2e, 0x14, 0x50, 0xd5, 0xd8,
+	0xe3, 0x0b, 0x2f, 0xd1, 0x63, 0x28, 0x9b, 0xc4, 0xb2, 0x07, 0xff, 0x1b, 0xdf, 0x33, 0x9c, 0x6f,
+	0x0d, 0xcd, 0x18, 0x21, 0x63, 0xf6, 0x12, 0xed, 0xc0, 0xcc, 0x13, 0xf1, 0x63, 0xed, 0x48, 0xc6,
+	0x97, 0x4f, 0x60, 0xac, 0x7e, 0xe0, 0x6d, 0x7b, 0x5d, 0x3f, 0xa3, 0xac, 0x5c, 0x46, 0xfd, 0xcc,
+	0x03, 0x66, 0xe5, 0xf4, 0x07, 0x8b, 0x7a, 0x8e, 0xe9, 0x57, 0x0a, 0xe1, 0xe2, 0x79, 0x2e, 0xb0,
+	0x8a, 0x2a, 0x86, 0xc5, 0x96, 0x90, 0x05, 0x33, 0x26, 0xe1, 0x63, 0x2d, 0x54, 0x3c, 0xd1, 0x46,
+	0x3a, 0x5c, 0xd6, 0x3c, 0x5c, 0x35, 0x42, 0xc1, 0x94, 0xb5, 0xa0, 0x0e, 0xcc, 0xb1, 0x82, 0x98,
+	0x4e, 0x9a, 0x46, 0x59, 0xeb, 0x8d, 0x42, 0xe3, 0xa6, 0x08, 0x23, 0x2e, 0x65, 0x16, 0x81, 0x91,
+	0x8c, 0x9c, 0xd0, 0x17, 0x1a, 0xcc, 0x8b, 0xba, 0xa7, 0xf0, 0xd0, 0x24, 0xd3, 0x2b, 0x7d, 0x12,
+	0x64, 0xd5, 0x72, 0xf5, 0x85, 0x54, 0x01, 0xe3, 0x90, 0xcd, 0xf0, 0xf8, 0x8b, 0x24, 0x82, 0x33,
+	0xa2, 0x75, 0x24, 0x13, 0x9e, 0x91, 0x27, 0xbe, 0x39, 0xf9, 0x24, 0x29, 0xca, 0x34, 0x96, 0x67,
+	0x09, 0x02, 0xfa, 0xb3, 0x06, 0xc8, 0x24, 0x2e, 0xb1, 0x22, 0x92, 0x15, 0xfc, 0xee, 0xe4, 0x02,
+	0x4c, 0xd2, 0xd5, 0xbf, 0xc2, 0x84, 0x0b, 0x63, 0xae, 0xd6, 0x85, 0x15, 0x3d, 0xa3, 0x96, 0x71,
+	0x28, 0xa6, 0x57, 0x47, 0xc6, 0xe1, 0x3e, 0x19, 0x1c, 0xa1, 0x9f, 0x68, 0xb0, 0x60, 0xf2, 0x19,
+	0x91, 0x9d, 0x0e, 0x61, 0xd0, 0x04, 0xd3, 0x9a, 0x91, 0x91, 0x77, 0x95, 0x4b, 0xc7, 0x78, 0xd9,
+	0x08, 0x12, 0x7c, 0x56, 0x60, 0x07, 0x01, 0x39, 0x12, 0x95, 0x4e, 0xcc, 0xa6, 0x6c, 0xf4, 0x27,
+	0xae, 0xc3, 0xd0, 0x44, 0xeb, 0x14, 0x43, 0x8d, 0x1a, 0x99, 0xe9, 0xb7, 0x26, 0x25, 0xe3, 0x2f,
+	0x90, 0x4b, 0x5c, 0xd9, 0x26, 0x3e, 0x9b, 0x53, 0x36, 0x24, 0xf2, 0x5a, 0xf9, 0x71, 0xfd, 0xb3,
+	0x5a, 0xc2, 0x64, 0xb7, 0xc2, 0x4f, 0x7a, 0xf3, 0xbf, 0x03, 0x00, 0x11, 0xaf, 0x27, 0xba, 0x11,
+	0x23, 0x00, 0x00,
 }
@@ -0,0 +1,181 @@
+// Package audit records the state-changing calls to the APIs, to attribute actions such as cancellations and
+// deletions to their callers.
+package audit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultRecentEvents is the number of recent audit events that the logger keeps in memory.
+	DefaultRecentEvents = 1000
+
+	defaultQueryLimit = 100
+)
+
+var (
+	recordedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "apiserver",
+		Name:      "audit_events_total",
+		Help:      "Total number of audited API calls, by method and status code",
+	}, []string{"method", "code"})
+
+	failedWrites = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "apiserver",
+		Name:      "audit_write_errors_total",
+		Help:      "Total number of audit events that could not be written to a sink",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(recordedEvents, failedWrites)
+}
+
+// Sink stores or forwards audit events.
+type Sink interface {
+	Write(event *apiserver.AuditEvent) error
+}
+
+// Querier returns the audit events that match the query, most recent first.
+type Querier interface {
+	Query(query *apiserver.AuditLogQuery) ([]*apiserver.AuditEvent, error)
+}
+
+// Logger writes the audit events to its sinks.
+//
+// The logger keeps the most recent events in memory. Queries are answered by the first sink that implements Querier,
+// such as the EventStoreSink, or otherwise from the recent events in memory.
+type Logger struct {
+	mu     sync.RWMutex
+	sinks  []Sink
+	recent *Ring
+}
+
+func NewLogger(sinks ...Sink) *Logger {
+	return &Logger{
+		sinks:  sinks,
+		recent: NewRing(DefaultRecentEvents),
+	}
+}
+
+// AddSink adds a sink to the logger, for sinks that are only available after the APIs have been set up.
+func (l *Logger) AddSink(sink Sink) {
+	l.mu.Lock()
+	l.sinks = append(l.sinks, sink)
+	l.mu.Unlock()
+}
+
+// Record writes the audit event to the sinks. Failures of sinks are logged, rather than failing the API call that
+// has already been performed.
+func (l *Logger) Record(event *apiserver.AuditEvent) {
+	recordedEvents.WithLabelValues(event.Method, event.Code).Inc()
+	l.recent.Write(event)
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, sink := range l.sinks {
+		if err := sink.Write(event); err != nil {
+			failedWrites.Inc()
+			logrus.Errorf("Failed to write audit event %s to %T: %v", event.Id, sink, err)
+		}
+	}
+}
+
+func (l *Logger) Query(query *apiserver.AuditLogQuery) ([]*apiserver.AuditEvent, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	for _, sink := range l.sinks {
+		if querier, ok := sink.(Querier); ok {
+			return querier.Query(query)
+		}
+	}
+	return l.recent.Query(query)
+}
+
+// Ring keeps a fixed number of the most recent audit events in memory.
+type Ring struct {
+	mu     sync.Mutex
+	events []*apiserver.AuditEvent
+	next   int
+	full   bool
+}
+
+func NewRing(size int) *Ring {
+	return &Ring{
+		events: make([]*apiserver.AuditEvent, size),
+	}
+}
+
+func (r *Ring) Write(event *apiserver.AuditEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[r.next] = event
+	r.next = (r.next + 1) % len(r.events)
+	if r.next == 0 {
+		r.full = true
+	}
+	return nil
+}
+
+func (r *Ring) Query(query *apiserver.AuditLogQuery) ([]*apiserver.AuditEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := r.next
+	if r.full {
+		n = len(r.events)
+	}
+	var results []*apiserver.AuditEvent
+	for i := 1; i <= n && len(results) < Limit(query); i++ {
+		event := r.events[(r.next-i+len(r.events))%len(r.events)]
+		if Matches(query, event) {
+			results = append(results, event)
+		}
+	}
+	return results, nil
+}
+
+// Matches returns whether the event matches the filters of the query.
+func Matches(query *apiserver.AuditLogQuery, event *apiserver.AuditEvent) bool {
+	if len(query.GetSubject()) > 0 && query.GetSubject() != event.GetSubject() {
+		return false
+	}
+	if len(query.GetMethod()) > 0 && query.GetMethod() != event.GetMethod() {
+		return false
+	}
+	if len(query.GetResource()) > 0 && query.GetResource() != event.GetResource() {
+		return false
+	}
+	if query.GetSince() != nil {
+		since, err := ptypes.Timestamp(query.GetSince())
+		ts, _ := ptypes.Timestamp(event.GetTimestamp())
+		if err == nil && ts.Before(since) {
+			return false
+		}
+	}
+	return true
+}
+
+// Limit returns the maximum number of events to return for the query.
+func Limit(query *apiserver.AuditLogQuery) int {
+	if query.GetLimit() > 0 {
+		return int(query.GetLimit())
+	}
+	return defaultQueryLimit
+}
+
+// day returns the day of the event, which is used to partition the events in the event store.
+func day(event *apiserver.AuditEvent) string {
+	ts, err := ptypes.Timestamp(event.GetTimestamp())
+	if err != nil {
+		ts = time.Now()
+	}
+	return ts.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	methodCancel = "/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel"
+	methodCreate = "/fission.workflows.apiserver.WorkflowAPI/Create"
+	methodGet    = "/fission.workflows.apiserver.WorkflowAPI/Get"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	logger := NewLogger()
+	interceptor := logger.UnaryServerInterceptor()
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "ci", Scope: auth.ScopeAdmin})
+
+	// Successful create: the resource is the id in the response.
+	_, err := interceptor(ctx, &types.WorkflowSpec{}, &grpc.UnaryServerInfo{FullMethod: methodCreate},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &types.ObjectMetadata{Id: "wf-1"}, nil
+		})
+	require.NoError(t, err)
+
+	// Failed cancel: the resource is the id in the request.
+	_, err = interceptor(ctx, &apiserver.CancelRequest{Id: "wi-1"}, &grpc.UnaryServerInfo{FullMethod: methodCancel},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.PermissionDenied, "forbidden")
+		})
+	assert.Error(t, err)
+
+	// Read-only methods are not audited.
+	_, err = interceptor(ctx, &types.ObjectMetadata{Id: "wf-1"}, &grpc.UnaryServerInfo{FullMethod: methodGet},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &types.Workflow{}, nil
+		})
+	require.NoError(t, err)
+
+	events, err := logger.Query(&apiserver.AuditLogQuery{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+
+	cancel := events[0]
+	assert.Equal(t, methodCancel, cancel.Method)
+	assert.Equal(t, "ci", cancel.Subject)
+	assert.Equal(t, "wi-1", cancel.Resource)
+	assert.Equal(t, codes.PermissionDenied.String(), cancel.Code)
+	assert.Contains(t, cancel.Error, "forbidden")
+	assert.Len(t, cancel.RequestDigest, 64)
+
+	create := events[1]
+	assert.Equal(t, methodCreate, create.Method)
+	assert.Equal(t, "wf-1", create.Resource)
+	assert.Equal(t, codes.OK.String(), create.Code)
+	assert.Empty(t, create.Error)
+}
+
+func TestNewEventPeer(t *testing.T) {
+	forwarded := metadata.Pairs(apiserver.ForwardedForHeader, "10.0.0.1")
+	newPeerContext := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{
+			Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234},
+		})
+	}
+
+	// The address forwarded by the HTTP gateway is used for calls from the loopback interface.
+	gateway := metadata.NewIncomingContext(newPeerContext("127.0.0.1"), forwarded)
+	assert.Equal(t, "10.0.0.1", NewEvent(gateway, methodCreate, nil, nil, nil).Peer)
+
+	// Other clients cannot forge their address with the header.
+	forged := metadata.NewIncomingContext(newPeerContext("10.0.0.2"), forwarded)
+	assert.Equal(t, "10.0.0.2:1234", NewEvent(forged, methodCreate, nil, nil, nil).Peer)
+}
+
+func newTestEvent(subject, method, resource string, ts time.Time) *apiserver.AuditEvent {
+	timestamp, _ := ptypes.TimestampProto(ts)
+	return &apiserver.AuditEvent{
+		Id:        subject + method + resource,
+		Timestamp: timestamp,
+		Subject:   subject,
+		Method:    method,
+		Resource:  resource,
+		Code:      codes.OK.String(),
+	}
+}
+
+func testQueries(t *testing.T, querier Querier) {
+	events, err := querier.Query(&apiserver.AuditLogQuery{})
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	assert.Equal(t, "bob", events[0].Subject)
+
+	events, err = querier.Query(&apiserver.AuditLogQuery{Subject: "alice"})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "wi-1", events[0].Resource)
+
+	events, err = querier.Query(&apiserver.AuditLogQuery{Method: methodCreate, Resource: "wf-1"})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	events, err = querier.Query(&apiserver.AuditLogQuery{Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	since, _ := ptypes.TimestampProto(time.Now().Add(-time.Hour))
+	events, err = querier.Query(&apiserver.AuditLogQuery{Since: since})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+}
+
+func writeTestEvents(t *testing.T, sink Sink) {
+	now := time.Now()
+	for _, event := range []*apiserver.AuditEvent{
+		newTestEvent("alice", methodCreate, "wf-1", now.Add(-48*time.Hour)),
+		newTestEvent("alice", methodCancel, "wi-1", now.Add(-time.Minute)),
+		newTestEvent("bob", methodCancel, "wi-2", now),
+	} {
+		require.NoError(t, sink.Write(event))
+	}
+}
+
+func TestRing(t *testing.T) {
+	ring := NewRing(3)
+	require.NoError(t, ring.Write(newTestEvent("mallory", methodCreate, "wf-0", time.Now())))
+	writeTestEvents(t, ring)
+	// The oldest event should have been overwritten.
+	testQueries(t, ring)
+}
+
+func TestEventStoreSink(t *testing.T) {
+	sink := NewEventStoreSink(mem.NewBackend())
+	writeTestEvents(t, sink)
+	testQueries(t, sink)
+}
+
+func TestFileSink(t *testing.T) {
+	f, err := ioutil.TempFile("", "audit")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	defer os.Remove(f.Name())
+
+	sink, err := NewFileSink(f.Name())
+	require.NoError(t, err)
+	writeTestEvents(t, sink)
+	require.NoError(t, sink.Close())
+
+	bs, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(bs)), "\n")
+	require.Len(t, lines, 3)
+	event := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+	assert.Equal(t, "bob", event["subject"])
+}
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan map[string]interface{}, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		event := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(server.URL)
+	writeTestEvents(t, sink)
+	require.NoError(t, sink.Close())
+	require.Len(t, received, 3)
+	assert.Equal(t, "alice", (<-received)["subject"])
+}
+
+type failingSink struct{}
+
+func (failingSink) Write(event *apiserver.AuditEvent) error {
+	return errors.New("failed")
+}
+
+func TestLoggerFailingSink(t *testing.T) {
+	logger := NewLogger(failingSink{})
+	logger.Record(newTestEvent("alice", methodCancel, "wi-1", time.Now()))
+
+	// The events should still be queryable from memory.
+	events, err := logger.Query(&apiserver.AuditLogQuery{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
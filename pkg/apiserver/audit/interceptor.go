@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// auditedMethods are the gRPC methods that change the state of the workflow engine.
+var auditedMethods = map[string]bool{
+	"/fission.workflows.apiserver.WorkflowAPI/Create":     true,
+	"/fission.workflows.apiserver.WorkflowAPI/CreateSync": true,
+	"/fission.workflows.apiserver.WorkflowAPI/Update":     true,
+	"/fission.workflows.apiserver.WorkflowAPI/Delete":     true,
+
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke":     true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync": true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeMany": true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask":    true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel":     true,
+
+	"/fission.workflows.apiserver.AdminAPI/Restore": true,
+}
+
+// UnaryServerInterceptor records the calls to the state-changing methods, including the calls that failed.
+//
+// It should be chained after the authentication interceptor, so that the identity of the caller is known.
+func (l *Logger) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if !auditedMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		l.Record(NewEvent(ctx, info.FullMethod, req, resp, err))
+		return resp, err
+	}
+}
+
+// NewEvent creates the audit event of a call to the method.
+func NewEvent(ctx context.Context, method string, req, resp interface{}, err error) *apiserver.AuditEvent {
+	event := &apiserver.AuditEvent{
+		Id:        util.UID(),
+		Timestamp: ptypes.TimestampNow(),
+		Method:    method,
+		Resource:  resource(req, resp),
+		Code:      status.Code(err).String(),
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		event.Subject = identity.String()
+	}
+	if addr, ok := apiserver.ForwardedFor(ctx); ok {
+		// Calls through the HTTP gateway come from the gateway itself.
+		event.Peer = addr
+	} else if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.Peer = p.Addr.String()
+	}
+	if msg, ok := req.(proto.Message); ok {
+		if bs, err := proto.Marshal(msg); err == nil {
+			digest := sha256.Sum256(bs)
+			event.RequestDigest = hex.EncodeToString(digest[:])
+		}
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// resource returns the id of the workflow or invocation that the call acted on. The id of created objects is taken
+// from the response; if the call failed, the id in the request is used instead.
+func resource(req, resp interface{}) string {
+	switch r := resp.(type) {
+	case *types.ObjectMetadata:
+		if len(r.GetId()) > 0 {
+			return r.GetId()
+		}
+	case interface{ GetMetadata() *types.ObjectMetadata }:
+		if id := r.GetMetadata().GetId(); len(id) > 0 {
+			return id
+		}
+	}
+	switch r := req.(type) {
+	case interface{ GetId() string }:
+		// ObjectMetadata, WorkflowUpdateRequest and CancelRequest
+		return r.GetId()
+	case *apiserver.AddTaskRequest:
+		return r.GetInvocationID()
+	case *apiserver.InvokeManyRequest:
+		return r.GetSpec().GetWorkflowId()
+	case *types.WorkflowInvocationSpec:
+		return r.GetWorkflowId()
+	case *types.WorkflowSpec:
+		return r.GetForceId()
+	case *apiserver.ObjectEvents:
+		return r.GetMetadata().GetId()
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/sirupsen/logrus"
+)
+
+// AggregateType is the type of the aggregates of the audit events in the event store. The events are partitioned
+// into one aggregate per day, with the date (such as 2019-01-31) as the id.
+const AggregateType = "audit"
+
+const defaultWebhookBuffer = 1000
+
+var marshaler = &jsonpb.Marshaler{}
+
+// FileSink appends the audit events to a file as JSON lines.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Write(event *apiserver.AuditEvent) error {
+	line, err := marshaler.MarshalToString(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.WriteString(line + "\n")
+	return err
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// WebhookSink posts each audit event as JSON to a URL.
+//
+// The events are posted in the background, in the order that they were recorded, so that a slow webhook does not
+// slow down the APIs. If the buffer of pending events is full, new events are dropped.
+type WebhookSink struct {
+	url     string
+	client  *http.Client
+	pending chan *apiserver.AuditEvent
+	done    chan struct{}
+}
+
+func NewWebhookSink(url string) *WebhookSink {
+	s := &WebhookSink{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		pending: make(chan *apiserver.AuditEvent, defaultWebhookBuffer),
+		done:    make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *WebhookSink) Write(event *apiserver.AuditEvent) error {
+	select {
+	case s.pending <- event:
+		return nil
+	default:
+		return fmt.Errorf("webhook buffer is full, dropped event")
+	}
+}
+
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	for event := range s.pending {
+		if err := s.post(event); err != nil {
+			failedWrites.Inc()
+			logrus.Errorf("Failed to post audit event %s to webhook: %v", event.Id, err)
+		}
+	}
+}
+
+func (s *WebhookSink) post(event *apiserver.AuditEvent) error {
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, event); err != nil {
+		return err
+	}
+	resp, err := s.client.Post(s.url, "application/json", buf)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// Close posts the pending events and stops the sink.
+func (s *WebhookSink) Close() error {
+	close(s.pending)
+	<-s.done
+	return nil
+}
+
+// EventStoreSink appends the audit events to the event store, where they are persisted along with the events of the
+// workflows and invocations.
+type EventStoreSink struct {
+	backend fes.Backend
+}
+
+func NewEventStoreSink(backend fes.Backend) *EventStoreSink {
+	return &EventStoreSink{backend: backend}
+}
+
+func (s *EventStoreSink) Write(event *apiserver.AuditEvent) error {
+	e, err := fes.NewEvent(fes.Aggregate{Type: AggregateType, Id: day(event)}, event)
+	if err != nil {
+		return err
+	}
+	e.Timestamp = event.Timestamp
+	return s.backend.Append(e)
+}
+
+// Query reads the audit events from the event store, starting with the most recent day.
+func (s *EventStoreSink) Query(query *apiserver.AuditLogQuery) ([]*apiserver.AuditEvent, error) {
+	aggregates, err := s.backend.List(func(a fes.Aggregate) bool {
+		return a.Type == AggregateType
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(aggregates, func(i, j int) bool {
+		return aggregates[i].Id > aggregates[j].Id
+	})
+
+	var since string
+	if query.GetSince() != nil {
+		since = day(&apiserver.AuditEvent{Timestamp: query.GetSince()})
+	}
+	var results []*apiserver.AuditEvent
+	for _, aggregate := range aggregates {
+		if aggregate.Id < since {
+			break
+		}
+		events, err := s.backend.Get(aggregate)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(events) - 1; i >= 0; i-- {
+			msg, err := fes.ParseEventData(events[i])
+			if err != nil {
+				return nil, err
+			}
+			event, ok := msg.(*apiserver.AuditEvent)
+			if !ok || !Matches(query, event) {
+				continue
+			}
+			results = append(results, event)
+			if len(results) >= Limit(query) {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
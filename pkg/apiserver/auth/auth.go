@@ -0,0 +1,263 @@
+// Package auth implements the optional API key authentication of the workflow APIs.
+//
+// Each API key has a scope, which determines the methods of the APIs that the key is allowed to call. The scopes are
+// hierarchical: a key with the invoke scope is also allowed to call the methods of the read scope, and a key with
+// the admin scope is allowed to call all methods.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Scope is the set of methods of the APIs that an API key is allowed to call.
+type Scope int
+
+const (
+	// ScopeRead allows the key to get, list and watch workflows and invocations.
+	ScopeRead Scope = iota + 1
+
+	// ScopeInvoke allows the key to invoke and cancel workflows, in addition to the ScopeRead methods.
+	ScopeInvoke
+
+	// ScopeAdmin allows the key to call all methods, including the ones that create, update or delete workflows.
+	ScopeAdmin
+)
+
+var scopeNames = map[Scope]string{
+	ScopeRead:   "read",
+	ScopeInvoke: "invoke",
+	ScopeAdmin:  "admin",
+}
+
+var (
+	ErrNoKey        = errors.New("no API key provided")
+	ErrInvalidKey   = errors.New("invalid API key")
+	ErrInvalidScope = errors.New("invalid scope")
+)
+
+// ParseScope parses the name of a scope (read, invoke or admin).
+func ParseScope(name string) (Scope, error) {
+	for scope, scopeName := range scopeNames {
+		if strings.EqualFold(name, scopeName) {
+			return scope, nil
+		}
+	}
+	return 0, fmt.Errorf("%v: '%s' (expected one of read, invoke, admin)", ErrInvalidScope, name)
+}
+
+func (s Scope) String() string {
+	if name, ok := scopeNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("scope(%d)", s)
+}
+
+// Allows returns true if the scope includes the required scope.
+func (s Scope) Allows(required Scope) bool {
+	return s >= required
+}
+
+// UnmarshalYAML parses the scope from its name.
+func (s *Scope) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+	scope, err := ParseScope(name)
+	if err != nil {
+		return err
+	}
+	*s = scope
+	return nil
+}
+
+// Key is an API key that clients present to authenticate with the APIs.
+type Key struct {
+	// Name identifies the key - for example the client that uses it - without revealing the key itself.
+	Name string `yaml:"name"`
+
+	// Key is the secret that clients present as a bearer token.
+	Key string `yaml:"key"`
+
+	// Scope determines the methods that the key is allowed to call.
+	Scope Scope `yaml:"scope"`
+}
+
+// String describes the key without revealing the secret, so that keys can be logged safely.
+func (k Key) String() string {
+	return fmt.Sprintf("%s (%s)", k.Name, k.Scope)
+}
+
+// LoadKeys reads a YAML (or JSON) file containing a list of API keys, such as a mounted Kubernetes secret:
+//
+//   - name: ci
+//     key: 6f1d1cf8e0b2...
+//     scope: admin
+//   - name: dashboard
+//     key: 2b9e07a4c5d1...
+//     scope: read
+func LoadKeys(path string) ([]Key, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %v", err)
+	}
+	var keys []Key
+	if err := yaml.Unmarshal(bs, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse API keys: %v", err)
+	}
+	if err := validateKeys(keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func validateKeys(keys []Key) error {
+	names := map[string]bool{}
+	for i, key := range keys {
+		if len(key.Name) == 0 {
+			return fmt.Errorf("API key %d has no name", i)
+		}
+		if key.Name == InternalKeyName {
+			return fmt.Errorf("API key name '%s' is reserved", key.Name)
+		}
+		if names[key.Name] {
+			return fmt.Errorf("duplicate API key name '%s'", key.Name)
+		}
+		names[key.Name] = true
+		if len(key.Key) == 0 {
+			return fmt.Errorf("API key '%s' is empty", key.Name)
+		}
+		if _, ok := scopeNames[key.Scope]; !ok {
+			return fmt.Errorf("API key '%s' has no scope", key.Name)
+		}
+	}
+	return nil
+}
+
+// GenerateKey generates a random API key, for example for the internal clients of the APIs.
+func GenerateKey() (string, error) {
+	bs := make([]byte, 32)
+	if _, err := rand.Read(bs); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bs), nil
+}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Subject identifies the caller: the name of the API key, or the subject of the JWT.
+	Subject string
+
+	// Issuer is the issuer of the JWT that authenticated the caller, or empty for API keys.
+	Issuer string
+
+	// Scope determines the methods that the caller is allowed to call.
+	Scope Scope
+
+	// Groups are the groups that the caller is a member of, according to the JWT.
+	Groups []string
+}
+
+// Internal returns whether the caller is one of the internal clients of the APIs, such as the operator.
+func (i Identity) Internal() bool {
+	return len(i.Issuer) == 0 && i.Subject == InternalKeyName
+}
+
+func (i Identity) String() string {
+	if len(i.Issuer) == 0 {
+		return i.Subject
+	}
+	return i.Issuer + "#" + i.Subject
+}
+
+type identityContextKey struct{}
+
+// FromContext returns the identity of the caller that was authenticated for the request of the context.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(Identity)
+	return identity, ok
+}
+
+// WithIdentity returns a copy of the context that carries the identity of the caller.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// Verifier authenticates the bearer tokens of one kind of credentials.
+type Verifier interface {
+	// Verify returns the identity of the caller that the token belongs to. It returns ErrInvalidKey if the
+	// verifier does not recognize the token, so that the next verifier can try it.
+	Verify(token string) (Identity, error)
+}
+
+// Keys verifies API keys.
+type Keys []Key
+
+// Verify looks up the API key that matches the token.
+func (ks Keys) Verify(token string) (Identity, error) {
+	// Compare all keys in constant time to avoid leaking (parts of) the keys through the timing of the responses.
+	var match Key
+	var found bool
+	for _, key := range ks {
+		if subtle.ConstantTimeCompare([]byte(key.Key), []byte(token)) == 1 {
+			match = key
+			found = true
+		}
+	}
+	if !found {
+		return Identity{}, ErrInvalidKey
+	}
+	return Identity{
+		Subject: match.Name,
+		Scope:   match.Scope,
+	}, nil
+}
+
+// Authenticator authenticates requests with one or more verifiers, such as API keys and JWTs.
+type Authenticator struct {
+	verifiers []Verifier
+}
+
+func NewAuthenticator(verifiers ...Verifier) *Authenticator {
+	return &Authenticator{
+		verifiers: verifiers,
+	}
+}
+
+// Authenticate returns the identity of the first verifier that recognizes the token.
+func (a *Authenticator) Authenticate(token string) (Identity, error) {
+	if len(token) == 0 {
+		return Identity{}, ErrNoKey
+	}
+	for _, verifier := range a.verifiers {
+		identity, err := verifier.Verify(token)
+		if err != ErrInvalidKey {
+			return identity, err
+		}
+	}
+	return Identity{}, ErrInvalidKey
+}
+
+// parseAuthorization extracts the token from the value of an Authorization header.
+// AuthenticateRequest authenticates the caller of the HTTP request with the token in its Authorization header.
+func (a *Authenticator) AuthenticateRequest(r *http.Request) (Identity, error) {
+	return a.Authenticate(parseAuthorization(r.Header.Get(authorizationHeader)))
+}
+
+func parseAuthorization(header string) string {
+	const prefix = "bearer "
+	if len(header) >= len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+		return strings.TrimSpace(header[len(prefix):])
+	}
+	return strings.TrimSpace(header)
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testKeys = []Key{
+	{Name: "dashboard", Key: "read-key", Scope: ScopeRead},
+	{Name: "ci", Key: "invoke-key", Scope: ScopeInvoke},
+	{Name: "ops", Key: "admin-key", Scope: ScopeAdmin},
+}
+
+func TestLoadKeys(t *testing.T) {
+	f, err := ioutil.TempFile("", "api-keys")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`
+- name: dashboard
+  key: read-key
+  scope: read
+- name: ci
+  key: invoke-key
+  scope: Invoke
+`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	keys, err := LoadKeys(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, testKeys[:2], keys)
+}
+
+func TestLoadKeysInvalid(t *testing.T) {
+	for _, content := range []string{
+		"- name: ci\n  key: foo\n  scope: superuser",
+		"- name: ci\n  key: foo",
+		"- name: ci\n  scope: read",
+		"- key: foo\n  scope: read",
+		"- name: ci\n  key: foo\n  scope: read\n- name: ci\n  key: bar\n  scope: read",
+	} {
+		f, err := ioutil.TempFile("", "api-keys")
+		require.NoError(t, err)
+		_, err = f.WriteString(content)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		_, err = LoadKeys(f.Name())
+		assert.Error(t, err, content)
+		os.Remove(f.Name())
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	authenticator := NewAuthenticator(Keys(testKeys))
+	cases := []struct {
+		authorization string
+		method        string
+		code          codes.Code
+	}{
+		{"", "/fission.workflows.apiserver.WorkflowAPI/Get", codes.Unauthenticated},
+		{"Bearer unknown-key", "/fission.workflows.apiserver.WorkflowAPI/Get", codes.Unauthenticated},
+		{"Bearer read-key", "/fission.workflows.apiserver.WorkflowAPI/Get", codes.OK},
+		{"read-key", "/fission.workflows.apiserver.WorkflowAPI/Get", codes.OK},
+		{"Bearer read-key", "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke", codes.PermissionDenied},
+		{"Bearer invoke-key", "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke", codes.OK},
+		{"Bearer invoke-key", "/fission.workflows.apiserver.WorkflowAPI/Create", codes.PermissionDenied},
+		{"Bearer admin-key", "/fission.workflows.apiserver.WorkflowAPI/Create", codes.OK},
+		{"Bearer invoke-key", "/fission.workflows.apiserver.WorkflowAPI/Unknown", codes.PermissionDenied},
+	}
+	for _, c := range cases {
+		ctx := context.Background()
+		if len(c.authorization) > 0 {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", c.authorization))
+		}
+		authCtx, err := authenticator.Authorize(ctx, c.method)
+		assert.Equal(t, c.code, status.Code(err), "%s %s", c.authorization, c.method)
+		if err == nil {
+			_, ok := FromContext(authCtx)
+			assert.True(t, ok)
+		}
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	handler := NewAuthenticator(Keys(testKeys)).HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter,
+		r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	client := &http.Client{Transport: &Transport{Key: "read-key"}}
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHTTPScopeHandler(t *testing.T) {
+	handler := NewAuthenticator(Keys(testKeys)).HTTPScopeHandler(ScopeAdmin, http.HandlerFunc(func(
+		w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	client := &http.Client{Transport: &Transport{Key: "read-key"}}
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	client = &http.Client{Transport: &Transport{Key: "admin-key"}}
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// Credentials authenticates the gRPC calls of a client with an API key.
+type Credentials string
+
+// GetRequestMetadata adds the API key as a bearer token to the metadata of each call.
+func (c Credentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{
+		authorizationHeader: "Bearer " + string(c),
+	}, nil
+}
+
+// RequireTransportSecurity returns false, because the APIs are typically served without TLS within the cluster.
+func (c Credentials) RequireTransportSecurity() bool {
+	return false
+}
+
+// Transport authenticates the HTTP requests of a client with an API key.
+type Transport struct {
+	Key string
+
+	// Base is the transport that sends the authenticated requests. If nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request, so the header is set on a copy.
+	authReq := new(http.Request)
+	*authReq = *req
+	authReq.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		authReq.Header[k] = v
+	}
+	authReq.Header.Set(authorizationHeader, "Bearer "+t.Key)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(authReq)
+}
@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const authorizationHeader = "authorization"
+
+var rejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "apiserver",
+	Name:      "auth_rejected_total",
+	Help:      "Total number of requests rejected by the API key authentication, by method and reason",
+}, []string{"method", "reason"})
+
+func init() {
+	prometheus.MustRegister(rejectedRequests)
+}
+
+// methodScopes maps the gRPC methods of the APIs to the scope that they require. Methods that are not listed require
+// the admin scope, so that new methods are not exposed to less privileged keys by accident.
+var methodScopes = map[string]Scope{
+	"/fission.workflows.apiserver.AdminAPI/Status":  ScopeRead,
+	"/fission.workflows.apiserver.AdminAPI/Version": ScopeRead,
+	// The audit log reveals who did what, so it is restricted to administrators.
+	"/fission.workflows.apiserver.AdminAPI/AuditLog": ScopeAdmin,
+
+	"/fission.workflows.apiserver.WorkflowAPI/Get":       ScopeRead,
+	"/fission.workflows.apiserver.WorkflowAPI/List":      ScopeRead,
+	"/fission.workflows.apiserver.WorkflowAPI/Watch":     ScopeRead,
+	"/fission.workflows.apiserver.WorkflowAPI/Events":    ScopeRead,
+	"/fission.workflows.apiserver.WorkflowAPI/Validate":  ScopeRead,
+	"/fission.workflows.apiserver.WorkflowAPI/Visualize": ScopeRead,
+
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Get":        ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/List":       ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Watch":      ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Events":     ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Explain":    ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Validate":   ScopeRead,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke":     ScopeInvoke,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync": ScopeInvoke,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeMany": ScopeInvoke,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/AddTask":    ScopeInvoke,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Cancel":     ScopeInvoke,
+}
+
+// RequiredScope returns the scope that is required to call the gRPC method.
+func RequiredScope(fullMethod string) Scope {
+	if scope, ok := methodScopes[fullMethod]; ok {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// Authorize authenticates the caller with the token in the metadata of the context, and checks whether the caller is
+// allowed to call the method. It returns the context with the identity of the caller, or a gRPC status error.
+func (a *Authenticator) Authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	var token string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md[authorizationHeader]; len(values) > 0 {
+			token = parseAuthorization(values[0])
+		}
+	}
+	identity, err := a.Authenticate(token)
+	if err != nil {
+		rejectedRequests.WithLabelValues(fullMethod, rejectionReason(err)).Inc()
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if span := opentracing.SpanFromContext(ctx); span != nil {
+		span.SetTag("auth.subject", identity.String())
+	}
+	if required := RequiredScope(fullMethod); !identity.Scope.Allows(required) {
+		rejectedRequests.WithLabelValues(fullMethod, "insufficient_scope").Inc()
+		return nil, status.Errorf(codes.PermissionDenied, "'%s' has scope '%s', but %s requires scope '%s'",
+			identity, identity.Scope, fullMethod, required)
+	}
+	return WithIdentity(ctx, identity), nil
+}
+
+func rejectionReason(err error) string {
+	switch err {
+	case ErrNoKey:
+		return "missing_key"
+	case ErrInvalidKey:
+		return "invalid_key"
+	default:
+		return "invalid_token"
+	}
+}
+
+// UnaryServerInterceptor rejects the unary calls that are not authorized by an API key.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.Authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the streaming calls that are not authorized by an API key.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		ctx, err := a.Authorize(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authorizedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// HTTPHandler rejects the unauthenticated HTTP requests before they reach the handler.
+//
+// It only authenticates the requests; the HTTP gateway forwards the Authorization header to the gRPC APIs, whose
+// interceptors check the scope of the key for the specific method.
+func (a *Authenticator) HTTPHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := a.AuthenticateRequest(r)
+		if err != nil {
+			rejectedRequests.WithLabelValues("http", rejectionReason(err)).Inc()
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// HTTPScopeHandler rejects the HTTP requests that are not authenticated with the required scope before they reach the
+// handler. Unlike HTTPHandler, it is meant for handlers that are not backed by the gRPC APIs, such as the debug
+// endpoints.
+func (a *Authenticator) HTTPScopeHandler(required Scope, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, err := a.AuthenticateRequest(r)
+		if err != nil {
+			rejectedRequests.WithLabelValues("http", rejectionReason(err)).Inc()
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !identity.Scope.Allows(required) {
+			rejectedRequests.WithLabelValues("http", "insufficient_scope").Inc()
+			http.Error(w, fmt.Sprintf("'%s' has scope '%s', but %s requires scope '%s'", identity, identity.Scope,
+				r.URL.Path, required), http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
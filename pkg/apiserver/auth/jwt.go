@@ -0,0 +1,328 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSubjectClaim       = "sub"
+	defaultGroupsClaim        = "groups"
+	defaultJWKSRefresh        = time.Hour
+	minJWKSRefreshInterval    = time.Minute
+	openIDConfigurationSuffix = "/.well-known/openid-configuration"
+)
+
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// JWTConfig configures the validation of JWTs, such as the ID tokens of an OpenID Connect provider.
+type JWTConfig struct {
+	// Issuer is the required issuer (iss) of the tokens.
+	Issuer string
+
+	// Audience is the required audience (aud) of the tokens. If empty, the audience is not checked.
+	Audience string
+
+	// JWKSURL is the URL of the JSON Web Key Set with the public keys that sign the tokens. If empty, it is
+	// discovered from the OpenID configuration of the issuer.
+	JWKSURL string
+
+	// SubjectClaim is the claim that identifies the caller. Defaults to "sub".
+	SubjectClaim string
+
+	// GroupsClaim is the claim that lists the groups of the caller. Defaults to "groups".
+	GroupsClaim string
+
+	// ScopeClaim is the claim that lists the scopes of the caller, either as an array or as a space-separated string.
+	// The caller gets the highest of the scopes read, invoke and admin in the claim. If empty, or if the claim
+	// contains none of these scopes, the caller gets the DefaultScope.
+	ScopeClaim string
+
+	// DefaultScope is the scope of the callers whose token does not specify a scope. If 0, these callers are not
+	// allowed to call any method.
+	DefaultScope Scope
+
+	// RefreshInterval is the interval at which the signing keys are fetched again, to pick up rotated keys. Defaults
+	// to an hour. Tokens signed with an unknown key trigger a refresh as well.
+	RefreshInterval time.Duration
+}
+
+// JWTVerifier verifies JWTs that are signed with the RSA or ECDSA keys of a JSON Web Key Set.
+type JWTVerifier struct {
+	config JWTConfig
+	client *http.Client
+	parser *jwt.Parser
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+func NewJWTVerifier(config JWTConfig) *JWTVerifier {
+	if len(config.SubjectClaim) == 0 {
+		config.SubjectClaim = defaultSubjectClaim
+	}
+	if len(config.GroupsClaim) == 0 {
+		config.GroupsClaim = defaultGroupsClaim
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultJWKSRefresh
+	}
+	return &JWTVerifier{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+		parser: &jwt.Parser{
+			// Only asymmetric algorithms; the symmetric ones would allow anyone with the public key to sign tokens.
+			ValidMethods: []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"},
+		},
+	}
+}
+
+// Verify validates the signature and the claims of the JWT, and returns the identity of its subject.
+func (v *JWTVerifier) Verify(token string) (Identity, error) {
+	if strings.Count(token, ".") != 2 {
+		// Not a JWT, so leave it to the other verifiers.
+		return Identity{}, ErrInvalidKey
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, v.signingKey)
+	if err != nil {
+		if verr, ok := err.(*jwt.ValidationError); ok && verr.Inner != nil {
+			err = verr.Inner
+		}
+		return Identity{}, fmt.Errorf("invalid token: %v", err)
+	}
+	if !claims.VerifyIssuer(v.config.Issuer, true) {
+		return Identity{}, fmt.Errorf("invalid token: unexpected issuer '%v'", claims["iss"])
+	}
+	if len(v.config.Audience) > 0 && !containsString(claimValues(claims["aud"]), v.config.Audience) {
+		return Identity{}, fmt.Errorf("invalid token: audience does not include '%s'", v.config.Audience)
+	}
+	subject, ok := claims[v.config.SubjectClaim].(string)
+	if !ok || len(subject) == 0 {
+		return Identity{}, fmt.Errorf("invalid token: no subject claim '%s'", v.config.SubjectClaim)
+	}
+
+	return Identity{
+		Subject: subject,
+		Issuer:  v.config.Issuer,
+		Scope:   v.scope(claims),
+		Groups:  claimValues(claims[v.config.GroupsClaim]),
+	}, nil
+}
+
+func (v *JWTVerifier) scope(claims jwt.MapClaims) Scope {
+	var scope Scope
+	if len(v.config.ScopeClaim) > 0 {
+		for _, name := range claimValues(claims[v.config.ScopeClaim]) {
+			if s, err := ParseScope(name); err == nil && s > scope {
+				scope = s
+			}
+		}
+	}
+	if scope == 0 {
+		return v.config.DefaultScope
+	}
+	return scope
+}
+
+// signingKey looks up the public key that signed the token by its key ID (kid).
+func (v *JWTVerifier) signingKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	stale := time.Since(v.fetchedAt) > v.config.RefreshInterval
+	recentlyFetched := time.Since(v.fetchedAt) < minJWKSRefreshInterval
+	v.mu.RUnlock()
+	if ok && !stale {
+		return key, nil
+	}
+
+	// Refresh the keys, but limit the refreshes triggered by unknown keys to avoid hammering the issuer.
+	if !ok && recentlyFetched {
+		return nil, ErrUnknownSigningKey
+	}
+	if err := v.refresh(); err != nil {
+		if ok {
+			logrus.Warnf("Failed to refresh the JWT signing keys, using the previous keys: %v", err)
+			return key, nil
+		}
+		return nil, err
+	}
+
+	v.mu.RLock()
+	key, ok = v.keys[kid]
+	v.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+func (v *JWTVerifier) refresh() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// Another request could have refreshed the keys in the meantime.
+	if time.Since(v.fetchedAt) < minJWKSRefreshInterval {
+		return nil
+	}
+	// Back off on failures as well.
+	v.fetchedAt = time.Now()
+
+	jwksURL := v.config.JWKSURL
+	if len(jwksURL) == 0 {
+		discovery := struct {
+			JWKSURI string `json:"jwks_uri"`
+		}{}
+		url := strings.TrimSuffix(v.config.Issuer, "/") + openIDConfigurationSuffix
+		if err := v.getJSON(url, &discovery); err != nil {
+			return fmt.Errorf("failed to discover the OpenID configuration of '%s': %v", v.config.Issuer, err)
+		}
+		if len(discovery.JWKSURI) == 0 {
+			return fmt.Errorf("OpenID configuration of '%s' has no jwks_uri", v.config.Issuer)
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	if err := v.getJSON(jwksURL, &jwks); err != nil {
+		return fmt.Errorf("failed to fetch the JWT signing keys: %v", err)
+	}
+	keys := make(map[string]interface{}, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if len(jwk.Use) > 0 && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logrus.Warnf("Ignoring JWT signing key '%s': %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	v.keys = keys
+	logrus.Debugf("Fetched %d JWT signing key(s) from '%s'", len(keys), jwksURL)
+	return nil
+}
+
+func (v *JWTVerifier) getJSON(url string, dst interface{}) error {
+	resp, err := v.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set (RFC 7517).
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// ECDSA
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve '%s'", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type '%s'", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	bs, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, err
+	}
+	if len(bs) == 0 {
+		return nil, errors.New("missing key parameter")
+	}
+	return new(big.Int).SetBytes(bs), nil
+}
+
+// claimValues returns the values of a claim that is either a single string, an array of strings, or a space-separated
+// string (like the OAuth 2.0 scope claim).
+func claimValues(claim interface{}) []string {
+	switch c := claim.(type) {
+	case string:
+		return strings.Fields(c)
+	case []interface{}:
+		var values []string
+		for _, v := range c {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIssuer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	return server
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func TestJWTVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := setupIssuer(t, key)
+	defer issuer.Close()
+
+	verifier := NewJWTVerifier(JWTConfig{
+		Issuer:       issuer.URL,
+		Audience:     "workflows",
+		ScopeClaim:   "scope",
+		DefaultScope: ScopeRead,
+	})
+	exp := time.Now().Add(time.Hour).Unix()
+
+	identity, err := verifier.Verify(signToken(t, key, "test", jwt.MapClaims{
+		"iss":    issuer.URL,
+		"aud":    []string{"dashboard", "workflows"},
+		"sub":    "alice",
+		"exp":    exp,
+		"groups": []string{"payments"},
+		"scope":  "openid invoke",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, Identity{
+		Subject: "alice",
+		Issuer:  issuer.URL,
+		Scope:   ScopeInvoke,
+		Groups:  []string{"payments"},
+	}, identity)
+
+	// Without a scope claim, the default scope applies.
+	identity, err = verifier.Verify(signToken(t, key, "test", jwt.MapClaims{
+		"iss": issuer.URL,
+		"aud": "workflows",
+		"sub": "bob",
+		"exp": exp,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, ScopeRead, identity.Scope)
+
+	// Tokens that are not a JWT are left to the other verifiers.
+	_, err = verifier.Verify("not-a-jwt")
+	assert.Equal(t, ErrInvalidKey, err)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": issuer.URL,
+		"aud": "workflows",
+		"sub": "mallory",
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	for name, token := range map[string]string{
+		"expired": signToken(t, key, "test", jwt.MapClaims{
+			"iss": issuer.URL, "aud": "workflows", "sub": "alice", "exp": time.Now().Add(-time.Hour).Unix(),
+		}),
+		"issuer": signToken(t, key, "test", jwt.MapClaims{
+			"iss": "https://example.com", "aud": "workflows", "sub": "alice", "exp": exp,
+		}),
+		"audience": signToken(t, key, "test", jwt.MapClaims{
+			"iss": issuer.URL, "aud": "dashboard", "sub": "alice", "exp": exp,
+		}),
+		"subject": signToken(t, key, "test", jwt.MapClaims{
+			"iss": issuer.URL, "aud": "workflows", "exp": exp,
+		}),
+		"signature": signToken(t, otherKey, "test", jwt.MapClaims{
+			"iss": issuer.URL, "aud": "workflows", "sub": "alice", "exp": exp,
+		}),
+		"kid": signToken(t, otherKey, "other", jwt.MapClaims{
+			"iss": issuer.URL, "aud": "workflows", "sub": "alice", "exp": exp,
+		}),
+		"hmac": hmacToken,
+	} {
+		_, err := verifier.Verify(token)
+		assert.Error(t, err, name)
+		assert.NotEqual(t, ErrInvalidKey, err, name)
+	}
+}
+
+func TestAuthenticatorMultipleVerifiers(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	issuer := setupIssuer(t, key)
+	defer issuer.Close()
+
+	authenticator := NewAuthenticator(Keys(testKeys), NewJWTVerifier(JWTConfig{
+		Issuer:       issuer.URL,
+		DefaultScope: ScopeAdmin,
+	}))
+
+	identity, err := authenticator.Authenticate("read-key")
+	require.NoError(t, err)
+	assert.Equal(t, "dashboard", identity.Subject)
+
+	identity, err = authenticator.Authenticate(signToken(t, key, "test", jwt.MapClaims{
+		"iss": issuer.URL,
+		"sub": "alice",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", identity.Subject)
+	assert.Equal(t, ScopeAdmin, identity.Scope)
+
+	_, err = authenticator.Authenticate("unknown-key")
+	assert.Equal(t, ErrInvalidKey, err)
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+)
+
+// InternalKeyName is the reserved name of the API key of the internal clients of the APIs, such as the operator.
+const InternalKeyName = "internal"
+
+// Permission is an action on workflows that the RBAC policy grants to callers.
+type Permission string
+
+const (
+	// PermissionCreate allows the caller to create and update workflows.
+	PermissionCreate Permission = "create"
+
+	// PermissionInvoke allows the caller to invoke workflows, and to add tasks to their invocations.
+	PermissionInvoke Permission = "invoke"
+
+	// PermissionCancel allows the caller to cancel invocations of workflows.
+	PermissionCancel Permission = "cancel"
+
+	// PermissionAdmin allows the caller to perform all actions, including deleting workflows.
+	PermissionAdmin Permission = "admin"
+)
+
+var (
+	ErrUnauthenticated = errors.New("request is not authenticated")
+	ErrForbidden       = errors.New("forbidden")
+)
+
+var deniedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "apiserver",
+	Name:      "authorization_denied_total",
+	Help:      "Total number of requests denied by the RBAC policy, by permission",
+}, []string{"permission"})
+
+func init() {
+	prometheus.MustRegister(deniedRequests)
+}
+
+// Resource is the workflow that an action is performed on.
+type Resource struct {
+	Namespace    string
+	WorkflowID   string
+	WorkflowName string
+}
+
+func (r Resource) String() string {
+	name := r.WorkflowID
+	if len(name) == 0 {
+		name = r.WorkflowName
+	}
+	if len(r.Namespace) == 0 {
+		return fmt.Sprintf("workflow '%s'", name)
+	}
+	return fmt.Sprintf("workflow '%s' in namespace '%s'", name, r.Namespace)
+}
+
+// Authorizer decides whether callers are allowed to perform actions on workflows.
+type Authorizer interface {
+	// Authorize returns nil if the caller of the request in the context has the permission for the resource.
+	Authorize(ctx context.Context, permission Permission, resource Resource) error
+}
+
+// Rule grants permissions on a set of workflows to a set of callers.
+type Rule struct {
+	// Subjects are the callers that the rule applies to:
+	//
+	//	key:<name>       the API key with the name
+	//	user:<subject>   the subject of a JWT
+	//	group:<group>    the members of the group, according to their JWT
+	//	*                all authenticated callers
+	Subjects []string `yaml:"subjects"`
+
+	// Permissions are the permissions that the rule grants.
+	Permissions []Permission `yaml:"permissions"`
+
+	// Namespaces limits the rule to the workflows in these namespaces. If empty, or if it contains "*", the rule
+	// applies to all namespaces.
+	Namespaces []string `yaml:"namespaces"`
+
+	// Workflows limits the rule to the workflows with these IDs or names. If empty, or if it contains "*", the rule
+	// applies to all workflows.
+	Workflows []string `yaml:"workflows"`
+}
+
+func (r Rule) matchesSubject(identity Identity) bool {
+	for _, subject := range r.Subjects {
+		kind := strings.SplitN(subject, ":", 2)
+		switch {
+		case subject == "*":
+			return true
+		case len(kind) != 2:
+			continue
+		case kind[0] == "key" && len(identity.Issuer) == 0 && kind[1] == identity.Subject:
+			return true
+		case kind[0] == "user" && len(identity.Issuer) > 0 && kind[1] == identity.Subject:
+			return true
+		case kind[0] == "group" && containsString(identity.Groups, kind[1]):
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matchesResource(resource Resource) bool {
+	if len(r.Namespaces) > 0 && !containsString(r.Namespaces, "*") &&
+		!containsString(r.Namespaces, resource.Namespace) {
+		return false
+	}
+	if len(r.Workflows) > 0 && !containsString(r.Workflows, "*") &&
+		!(len(resource.WorkflowID) > 0 && containsString(r.Workflows, resource.WorkflowID)) &&
+		!(len(resource.WorkflowName) > 0 && containsString(r.Workflows, resource.WorkflowName)) {
+		return false
+	}
+	return true
+}
+
+func (r Rule) grants(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission || p == PermissionAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// Policy is an RBAC policy that grants callers the permissions of the rules that match them. Callers that no rule
+// grants the permission for a resource are denied.
+type Policy struct {
+	Rules []Rule
+}
+
+// LoadPolicy reads a YAML (or JSON) file containing a list of RBAC rules:
+//
+//   - subjects: ["group:payments", "key:ci"]
+//     permissions: [create, invoke, cancel]
+//     namespaces: [payments]
+//   - subjects: ["user:alice"]
+//     permissions: [invoke]
+//     workflows: [checkout]
+func LoadPolicy(path string) (*Policy, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RBAC policy: %v", err)
+	}
+	var rules []Rule
+	if err := yaml.Unmarshal(bs, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse RBAC policy: %v", err)
+	}
+	for i, rule := range rules {
+		if len(rule.Subjects) == 0 {
+			return nil, fmt.Errorf("RBAC rule %d has no subjects", i)
+		}
+		for _, subject := range rule.Subjects {
+			if subject == "key:"+InternalKeyName {
+				return nil, fmt.Errorf("RBAC rule %d refers to the reserved key '%s'", i, InternalKeyName)
+			}
+		}
+		for _, permission := range rule.Permissions {
+			switch permission {
+			case PermissionCreate, PermissionInvoke, PermissionCancel, PermissionAdmin:
+			default:
+				return nil, fmt.Errorf("RBAC rule %d has unknown permission '%s'", i, permission)
+			}
+		}
+	}
+	return &Policy{Rules: rules}, nil
+}
+
+// Authorize returns ErrForbidden if none of the rules grants the caller the permission for the resource. The internal
+// clients are always allowed.
+func (p *Policy) Authorize(ctx context.Context, permission Permission, resource Resource) error {
+	identity, ok := FromContext(ctx)
+	if !ok {
+		deniedRequests.WithLabelValues(string(permission)).Inc()
+		return ErrUnauthenticated
+	}
+	if identity.Internal() {
+		return nil
+	}
+	for _, rule := range p.Rules {
+		if rule.grants(permission) && rule.matchesSubject(identity) && rule.matchesResource(resource) {
+			return nil
+		}
+	}
+	deniedRequests.WithLabelValues(string(permission)).Inc()
+	return fmt.Errorf("%v: '%s' does not have the %s permission for %v", ErrForbidden, identity, permission,
+		resource)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	f, err := ioutil.TempFile("", "rbac-policy")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`
+- subjects: ["group:payments", "key:ci"]
+  permissions: [create, invoke, cancel]
+  namespaces: [payments]
+- subjects: ["user:alice"]
+  permissions: [invoke]
+  workflows: [checkout]
+`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	policy, err := LoadPolicy(f.Name())
+	require.NoError(t, err)
+	assert.Equal(t, []Rule{
+		{
+			Subjects:    []string{"group:payments", "key:ci"},
+			Permissions: []Permission{PermissionCreate, PermissionInvoke, PermissionCancel},
+			Namespaces:  []string{"payments"},
+		},
+		{
+			Subjects:    []string{"user:alice"},
+			Permissions: []Permission{PermissionInvoke},
+			Workflows:   []string{"checkout"},
+		},
+	}, policy.Rules)
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	for _, content := range []string{
+		"- permissions: [invoke]",
+		"- subjects: [\"*\"]\n  permissions: [delete]",
+		"- subjects: [\"key:internal\"]\n  permissions: [admin]",
+	} {
+		f, err := ioutil.TempFile("", "rbac-policy")
+		require.NoError(t, err)
+		_, err = f.WriteString(content)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		_, err = LoadPolicy(f.Name())
+		assert.Error(t, err, content)
+		os.Remove(f.Name())
+	}
+}
+
+func TestPolicyAuthorize(t *testing.T) {
+	policy := &Policy{Rules: []Rule{
+		{
+			Subjects:    []string{"group:payments", "key:ci"},
+			Permissions: []Permission{PermissionCreate, PermissionInvoke, PermissionCancel},
+			Namespaces:  []string{"payments"},
+		},
+		{
+			Subjects:    []string{"user:alice"},
+			Permissions: []Permission{PermissionInvoke},
+			Workflows:   []string{"checkout"},
+		},
+		{
+			Subjects:    []string{"user:root"},
+			Permissions: []Permission{PermissionAdmin},
+		},
+	}}
+	payments := Resource{Namespace: "payments", WorkflowID: "wf-1", WorkflowName: "refund"}
+	checkout := Resource{Namespace: "shop", WorkflowID: "wf-2", WorkflowName: "checkout"}
+
+	ci := Identity{Subject: "ci", Scope: ScopeAdmin}
+	bob := Identity{Subject: "bob", Issuer: "https://idp", Groups: []string{"payments"}}
+	alice := Identity{Subject: "alice", Issuer: "https://idp"}
+	fakeAlice := Identity{Subject: "alice"}
+	root := Identity{Subject: "root", Issuer: "https://idp"}
+	internal := Identity{Subject: InternalKeyName}
+
+	cases := []struct {
+		identity   Identity
+		permission Permission
+		resource   Resource
+		allowed    bool
+	}{
+		{ci, PermissionCreate, payments, true},
+		{ci, PermissionCreate, checkout, false},
+		{ci, PermissionAdmin, payments, false},
+		{bob, PermissionCancel, payments, true},
+		{alice, PermissionInvoke, checkout, true},
+		{alice, PermissionInvoke, payments, false},
+		{alice, PermissionCancel, checkout, false},
+		{fakeAlice, PermissionInvoke, checkout, false},
+		{root, PermissionAdmin, checkout, true},
+		{root, PermissionCancel, payments, true},
+		{internal, PermissionAdmin, checkout, true},
+	}
+	for _, c := range cases {
+		err := policy.Authorize(WithIdentity(context.Background(), c.identity), c.permission, c.resource)
+		assert.Equal(t, c.allowed, err == nil, "%v %v %v: %v", c.identity, c.permission, c.resource, err)
+	}
+
+	err := policy.Authorize(context.Background(), PermissionInvoke, checkout)
+	assert.Equal(t, ErrUnauthenticated, err)
+}
@@ -0,0 +1,53 @@
+// Package debug serves the runtime debug endpoints of the workflow engine, which allow hangs and leaks in production to
+// be diagnosed without rebuilding or restarting the engine with debug flags:
+//
+// - /debug/pprof/: the CPU, heap, goroutine, block and mutex profiles of net/http/pprof, for use with go tool pprof.
+// - /debug/goroutines: a plain-text dump of the stack traces of all goroutines.
+// - /debug/vars: the expvar variables, such as the memory statistics, in JSON.
+//
+// The endpoints expose internals of the engine, so they should only be served on a private address or behind
+// authentication.
+package debug
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+
+	"github.com/fission/fission-workflows/pkg/version"
+)
+
+// PathPrefix is the path under which the debug endpoints are served.
+const PathPrefix = "/debug/"
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("version", expvar.Func(func() interface{} {
+		return version.VersionInfo()
+	}))
+}
+
+// Handler returns the handler of the debug endpoints.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathPrefix+"pprof/", pprof.Index)
+	mux.HandleFunc(PathPrefix+"pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc(PathPrefix+"pprof/profile", pprof.Profile)
+	mux.HandleFunc(PathPrefix+"pprof/symbol", pprof.Symbol)
+	mux.HandleFunc(PathPrefix+"pprof/trace", pprof.Trace)
+	mux.HandleFunc(PathPrefix+"goroutines", dumpGoroutines)
+	mux.Handle(PathPrefix+"vars", expvar.Handler())
+	return mux
+}
+
+// dumpGoroutines writes the stack traces of all goroutines, in the same format as an unrecovered panic.
+func dumpGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := rpprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
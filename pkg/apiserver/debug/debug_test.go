@@ -0,0 +1,49 @@
+package debug
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestHandler(t *testing.T) {
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+
+	code, body := get(t, server.URL+"/debug/goroutines")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "goroutine ")
+	assert.Contains(t, body, "dumpGoroutines")
+
+	code, body = get(t, server.URL+"/debug/pprof/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "goroutine")
+
+	code, _ = get(t, server.URL+"/debug/pprof/heap")
+	assert.Equal(t, http.StatusOK, code)
+
+	code, body = get(t, server.URL+"/debug/vars")
+	assert.Equal(t, http.StatusOK, code)
+	vars := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(body), &vars))
+	assert.Contains(t, vars, "memstats")
+	assert.Contains(t, vars, "version")
+	assert.NotZero(t, vars["goroutines"])
+
+	code, _ = get(t, server.URL+"/debug/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/fission/fission"
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/openapi"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
@@ -26,7 +27,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-const fissionIDsCacheSize = 1E4
+const (
+	fissionIDsCacheSize = 1E4
+
+	// fissionFunctionRoute is the prefix of the route on which the Fission router exposes functions by default.
+	fissionFunctionRoute = "/fission-function/"
+)
 
 // Proxy between Fission and Workflows to ensure that invocations comply with Fission function interface. This
 // ensures that workflows can be executed exactly like Fission functions are executed.
@@ -34,10 +40,14 @@ type Proxy struct {
 	client         *apiserver.Client
 	fissionIds     *lru.Cache // map[string]bool
 	defaultTimeout time.Duration
+	triggers       map[string]*MQTrigger
 }
 
 // NewEnvironmentProxyServer creates a proxy server to adheres to the Fission Environment specification.
-func NewEnvironmentProxyServer(client *apiserver.Client, defaultTimeout time.Duration) *Proxy {
+//
+// The optional triggers map the messages of Fission message-queue triggers onto workflow invocations.
+func NewEnvironmentProxyServer(client *apiserver.Client, defaultTimeout time.Duration,
+	triggers ...MQTrigger) *Proxy {
 	cache, err := lru.New(fissionIDsCacheSize)
 	if err != nil {
 		panic(err)
@@ -45,10 +55,15 @@ func NewEnvironmentProxyServer(client *apiserver.Client, defaultTimeout time.Dur
 	if defaultTimeout <= 0 {
 		panic("default timeout for the Fission Proxy must be larger than 0")
 	}
+	triggersByTopic := map[string]*MQTrigger{}
+	for i := range triggers {
+		triggersByTopic[triggers[i].Topic] = &triggers[i]
+	}
 	return &Proxy{
 		client:         client,
 		fissionIds:     cache,
 		defaultTimeout: defaultTimeout,
+		triggers:       triggersByTopic,
 	}
 }
 
@@ -57,6 +72,37 @@ func (fp *Proxy) RegisterServer(mux *http.ServeMux) {
 	mux.HandleFunc("/", fp.handleRequest)
 	mux.HandleFunc("/v2/specialize", fp.handleSpecialize)
 	mux.HandleFunc("/healthz", fp.handleHealthCheck)
+	mux.HandleFunc(openapi.DocumentPath, fp.handleOpenAPI)
+}
+
+// handleOpenAPI serves an OpenAPI document that describes the workflows that the proxy has been specialized with, at
+// the default routes of their Fission functions. The requests to these routes are mapped onto the inputs of the
+// workflows like the requests to other Fission functions.
+func (fp *Proxy) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	doc := openapi.NewDocument("Fission Workflows")
+	doc.Info.Description = "The workflows that are deployed as Fission functions."
+	for _, key := range fp.fissionIds.Keys() {
+		fnID := key.(string)
+		wf, err := fp.client.Workflow.Get(r.Context(), &types.ObjectMetadata{Id: fnID})
+		if err != nil || len(wf.GetSpec().GetName()) == 0 {
+			continue
+		}
+		err = doc.AddWorkflow(fissionFunctionRoute+wf.GetSpec().GetName(), wf, openapi.MapHTTPRequest)
+		if err != nil {
+			logrus.Warnf("Omitting workflow %s from the OpenAPI document: %v", fnID, err)
+		}
+	}
+	bs, err := json.Marshal(doc)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs)
 }
 
 func (fp *Proxy) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
@@ -107,6 +153,16 @@ func (fp *Proxy) handleRequest(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Failed to parse inputs", 400)
 		return
 	}
+	async := len(r.Header.Get("X-Async")) > 0
+	if trigger, ok := fp.triggers[r.Header.Get(headerMQTopic)]; ok {
+		inputs, err = trigger.formatInputs(inputs)
+		if err != nil {
+			logrus.Errorf("Failed to map message to inputs: %v", err)
+			http.Error(w, err.Error(), 400)
+			return
+		}
+		async = async || trigger.Async
+	}
 	deadline := fp.determineDeadline(r)
 
 	wfSpec := types.NewWorkflowInvocationSpec(fnID, deadline)
@@ -118,7 +174,7 @@ func (fp *Proxy) handleRequest(w http.ResponseWriter, r *http.Request) {
 		util.LogIfError(err)
 		logrus.Debugf("Fission proxy request: %v - %v", wfSpec.WorkflowId, inputs)
 	}
-	if len(r.Header.Get("X-Async")) > 0 {
+	if async {
 		invocationID, invokeErr := fp.client.Invocation.Invoke(ctx, wfSpec)
 		if invokeErr != nil {
 			logrus.Errorf("Failed to invoke: %v", invokeErr)
@@ -2,12 +2,16 @@ package fission
 
 import (
 	"context"
+	"encoding/json"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/fission/fission"
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/openapi"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/golang/protobuf/jsonpb"
@@ -34,11 +38,16 @@ func (m *mockWorkflowClient) Create(ctx context.Context, in *types.WorkflowSpec,
 	return &types.ObjectMetadata{Id: args.String(0)}, args.Error(1)
 }
 
-func (m *mockWorkflowClient) List(ctx context.Context, _ *empty.Empty, opts ...grpc.CallOption) (*apiserver.WorkflowList, error) {
+func (m *mockWorkflowClient) List(ctx context.Context, _ *apiserver.WorkflowListQuery, opts ...grpc.CallOption) (*apiserver.WorkflowList, error) {
 	args := m.Called()
 	return args.Get(0).(*apiserver.WorkflowList), args.Error(1)
 }
 
+func (m *mockWorkflowClient) Update(ctx context.Context, in *apiserver.WorkflowUpdateRequest, opts ...grpc.CallOption) (*empty.Empty, error) {
+	args := m.Called(in)
+	return &empty.Empty{}, args.Error(1)
+}
+
 func (m *mockWorkflowClient) Get(ctx context.Context, in *types.ObjectMetadata, opts ...grpc.CallOption) (*types.Workflow, error) {
 	args := m.Called(in)
 	return args.Get(0).(*types.Workflow), args.Error(1)
@@ -49,15 +58,23 @@ func (m *mockWorkflowClient) Delete(ctx context.Context, in *types.ObjectMetadat
 	return &empty.Empty{}, args.Error(1)
 }
 
-func (m *mockWorkflowClient) Validate(ctx context.Context, in *types.WorkflowSpec, opts ...grpc.CallOption) (*empty.Empty, error) {
+func (m *mockWorkflowClient) Validate(ctx context.Context, in *types.WorkflowSpec, opts ...grpc.CallOption) (*apiserver.WorkflowValidationResult, error) {
 	args := m.Called(in)
-	return &empty.Empty{}, args.Error(1)
+	return &apiserver.WorkflowValidationResult{Valid: true}, args.Error(1)
 }
 
 func (m *mockWorkflowClient) Events(ctx context.Context, in *types.ObjectMetadata, opts ...grpc.CallOption) (*apiserver.ObjectEvents, error) {
 	panic("implement me")
 }
 
+func (m *mockWorkflowClient) Watch(ctx context.Context, in *apiserver.WorkflowWatchQuery, opts ...grpc.CallOption) (apiserver.WorkflowAPI_WatchClient, error) {
+	panic("implement me")
+}
+
+func (m *mockWorkflowClient) Visualize(ctx context.Context, in *apiserver.VisualizeRequest, opts ...grpc.CallOption) (*apiserver.Visualization, error) {
+	panic("implement me")
+}
+
 func TestProxy_Specialize(t *testing.T) {
 	workflowServer := &mockWorkflowClient{}
 	workflowServer.On("CreateSync", mock.Anything).Return(&types.Workflow{
@@ -111,3 +128,29 @@ func TestProxy_Specialize(t *testing.T) {
 	assert.Equal(t, 1, len(wfIds))
 	mock.AssertExpectationsForObjects(t, workflowServer)
 }
+
+func TestProxy_OpenAPI(t *testing.T) {
+	workflowServer := &mockWorkflowClient{}
+	workflowServer.On("Get", &types.ObjectMetadata{Id: "1"}).Return(&types.Workflow{
+		Metadata: types.NewObjectMetadata("1"),
+		Spec: &types.WorkflowSpec{
+			Name:        "testFn",
+			InputSchema: `{"properties": {"default": {"type": "string"}}, "required": ["default"]}`,
+		},
+	}, nil)
+	env := NewEnvironmentProxyServer(&apiserver.Client{
+		Workflow: workflowServer,
+	}, time.Second)
+	env.fissionIds.Add("1", true)
+
+	w := httptest.NewRecorder()
+	env.handleOpenAPI(w, httptest.NewRequest(http.MethodGet, openapi.DocumentPath, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	doc := &openapi.Document{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), doc))
+	assert.Len(t, doc.Paths, 1)
+	op := doc.Paths["/fission-function/testFn"].Post
+	assert.NotNil(t, op)
+	assert.True(t, op.RequestBody.Required)
+	assert.Equal(t, openapi.Schema{"type": "string"}, op.RequestBody.Content["text/plain"].Schema)
+}
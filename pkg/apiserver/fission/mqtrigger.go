@@ -0,0 +1,127 @@
+package fission
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"gopkg.in/yaml.v2"
+)
+
+// headerMQTopic is set by the Fission message-queue triggers on the requests that deliver messages.
+const headerMQTopic = "X-Fission-MQTrigger-Topic"
+
+var ErrMQTriggerNoTopic = errors.New("message-queue trigger has no topic")
+
+// MQTrigger maps the messages that a Fission message-queue trigger delivers to a workflow onto the inputs of the
+// workflow invocation.
+//
+// Fission delivers the messages of a topic to the workflow like any other request, so messages of topics without an
+// MQTrigger start a workflow invocation with the message as the body input.
+type MQTrigger struct {
+	// Topic is the topic of the message-queue trigger.
+	Topic string `yaml:"topic"`
+
+	// Inputs are the templates of the inputs of the workflow invocation. Expressions in the templates are evaluated
+	// against a MessageScope; for example, "{$.Body.id}" evaluates to the id field of a JSON message. The templated
+	// inputs override the inputs that were parsed from the message.
+	Inputs map[string]string `yaml:"inputs"`
+
+	// Async acknowledges a message as soon as the workflow invocation has been created, instead of once it has
+	// completed. The response topic of the trigger then receives the invocation ID rather than the output.
+	Async bool `yaml:"async"`
+}
+
+// MessageScope is the scope of the expressions in the input templates of a message-queue trigger.
+type MessageScope struct {
+	Topic   string
+	Body    interface{}
+	Headers interface{}
+}
+
+// LoadMQTriggers reads a YAML file containing a list of message-queue triggers.
+func LoadMQTriggers(path string) ([]MQTrigger, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message-queue triggers: %v", err)
+	}
+	var triggers []MQTrigger
+	if err := yaml.Unmarshal(bs, &triggers); err != nil {
+		return nil, fmt.Errorf("failed to parse message-queue triggers: %v", err)
+	}
+	for _, trigger := range triggers {
+		if len(trigger.Topic) == 0 {
+			return nil, ErrMQTriggerNoTopic
+		}
+	}
+	return triggers, nil
+}
+
+// formatInputs evaluates the input templates of the trigger for a message, which has been parsed into inputs.
+func (t *MQTrigger) formatInputs(inputs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue,
+	error) {
+	scope, err := newMessageScope(t.Topic, inputs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*typedvalues.TypedValue, len(inputs)+len(t.Inputs))
+	for k, v := range inputs {
+		result[k] = v
+	}
+	for k, template := range t.Inputs {
+		tv, err := typedvalues.Wrap(template)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := expr.Resolve(scope, "", tv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate input '%s' of trigger for topic '%s': %v", k, t.Topic, err)
+		}
+		result[k] = resolved
+	}
+	return result, nil
+}
+
+func newMessageScope(topic string, inputs map[string]*typedvalues.TypedValue) (*MessageScope, error) {
+	scope := &MessageScope{
+		Topic: topic,
+	}
+	if tv, ok := inputs[types.InputBody]; ok {
+		body, err := typedvalues.Unwrap(tv)
+		if err != nil {
+			return nil, err
+		}
+		scope.Body = decodeJSON(body)
+	}
+	if tv, ok := inputs[types.InputHeaders]; ok {
+		headers, err := typedvalues.Unwrap(tv)
+		if err != nil {
+			return nil, err
+		}
+		scope.Headers = headers
+	}
+	return scope, nil
+}
+
+// decodeJSON decodes the message if it is JSON, because the message queues do not provide a content type for the
+// messages.
+func decodeJSON(body interface{}) interface{} {
+	var bs []byte
+	switch t := body.(type) {
+	case []byte:
+		bs = t
+	case string:
+		bs = []byte(t)
+	default:
+		return body
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(bs, &decoded); err != nil {
+		return string(bs)
+	}
+	return decoded
+}
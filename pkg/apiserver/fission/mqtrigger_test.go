@@ -0,0 +1,55 @@
+package fission
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTrigger_FormatInputs(t *testing.T) {
+	trigger := &MQTrigger{
+		Topic: "orders",
+		Inputs: map[string]string{
+			"orderId": "{$.Body.id}",
+			"topic":   "{$.Topic}",
+			"source":  "queue",
+		},
+	}
+	inputs, err := trigger.formatInputs(map[string]*typedvalues.TypedValue{
+		types.InputBody: typedvalues.MustWrap([]byte(`{"id": 42}`)),
+	})
+	assert.NoError(t, err)
+	unwrapped, err := typedvalues.UnwrapMapTypedValue(inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		types.InputBody: []byte(`{"id": 42}`),
+		"orderId":       float64(42),
+		"topic":         "orders",
+		"source":        "queue",
+	}, unwrapped)
+}
+
+func TestLoadMQTriggers(t *testing.T) {
+	fd, err := ioutil.TempFile("", "test-fission-workflows-mqtriggers")
+	assert.NoError(t, err)
+	defer os.Remove(fd.Name())
+	fd.WriteString(`
+- topic: orders
+  async: true
+  inputs:
+    orderId: "{$.Body.id}"
+`)
+	fd.Close()
+
+	triggers, err := LoadMQTriggers(fd.Name())
+	assert.NoError(t, err)
+	assert.Equal(t, []MQTrigger{{
+		Topic:  "orders",
+		Async:  true,
+		Inputs: map[string]string{"orderId": "{$.Body.id}"},
+	}}, triggers)
+}
@@ -2,10 +2,17 @@ package httpclient
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/version"
+	"github.com/golang/protobuf/ptypes"
 )
 
 type AdminAPI struct {
@@ -23,12 +30,113 @@ func NewAdminAPI(endpoint string, client http.Client) *AdminAPI {
 
 func (api *AdminAPI) Status(ctx context.Context) (*apiserver.Health, error) {
 	result := &apiserver.Health{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/healthz"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/healthz"), nil, result)
+	return result, err
+}
+
+// Ready returns an error if the workflow engine is not ready to accept new invocations.
+func (api *AdminAPI) Ready(ctx context.Context) (*apiserver.Health, error) {
+	result := &apiserver.Health{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/readyz"), nil, result)
 	return result, err
 }
 
 func (api *AdminAPI) Version(ctx context.Context) (*version.Info, error) {
 	result := &version.Info{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/version"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/version"), nil, result)
+	return result, err
+}
+
+func (api *AdminAPI) AuditLog(ctx context.Context, query *apiserver.AuditLogQuery) (*apiserver.AuditLog, error) {
+	params := url.Values{}
+	for key, value := range map[string]string{
+		"subject":  query.GetSubject(),
+		"method":   query.GetMethod(),
+		"resource": query.GetResource(),
+	} {
+		if len(value) > 0 {
+			params.Set(key, value)
+		}
+	}
+	if query.GetSince() != nil {
+		since, err := ptypes.Timestamp(query.GetSince())
+		if err != nil {
+			return nil, err
+		}
+		params.Set("since", since.Format(time.RFC3339Nano))
+	}
+	if query.GetLimit() > 0 {
+		params.Set("limit", strconv.Itoa(int(query.GetLimit())))
+	}
+	path := "/audit"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	result := &apiserver.AuditLog{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL(path), nil, result)
+	return result, err
+}
+
+func (api *AdminAPI) Restore(ctx context.Context, events *apiserver.ObjectEvents) error {
+	return api.callWithJSON(ctx, http.MethodPost, api.formatURL("/restore"), events, nil)
+}
+
+func (api *AdminAPI) ListExecutors(ctx context.Context) (*apiserver.Executors, error) {
+	result := &apiserver.Executors{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/executors"), nil, result)
+	return result, err
+}
+
+func (api *AdminAPI) UpdateExecutor(ctx context.Context, config *apiserver.ExecutorConfig) (*apiserver.ExecutorConfig,
+	error) {
+	result := &apiserver.ExecutorConfig{}
+	err := api.callWithJSON(ctx, http.MethodPut, api.formatURL("/executors/"+config.GetName()), config, result)
+	return result, err
+}
+
+func (api *AdminAPI) ListQuarantined(ctx context.Context) (*apiserver.QuarantinedControllers, error) {
+	result := &apiserver.QuarantinedControllers{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/quarantine"), nil, result)
+	return result, err
+}
+
+func (api *AdminAPI) ReleaseQuarantined(ctx context.Context, ref *apiserver.QuarantinedControllerRef) (
+	*apiserver.QuarantinedController, error) {
+	result := &apiserver.QuarantinedController{}
+	err := api.callWithJSON(ctx, http.MethodDelete, api.formatURL("/quarantine/"+ref.GetSystem()+"/"+ref.GetKey()),
+		nil, result)
+	return result, err
+}
+
+func (api *AdminAPI) RebuildProjection(ctx context.Context, ref *apiserver.ProjectionRef) error {
+	return api.callWithJSON(ctx, http.MethodPost,
+		api.formatURL("/projections/"+ref.GetType()+"/"+ref.GetId()+"/rebuild"), nil, nil)
+}
+
+func (api *AdminAPI) ResyncProjections(ctx context.Context, req *apiserver.ResyncProjectionsRequest) (
+	*apiserver.ResyncProjectionsResult, error) {
+	result := &apiserver.ResyncProjectionsResult{}
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/projections/resync"), req, result)
 	return result, err
 }
+
+// Metrics returns the Prometheus metrics of the workflow engine, in the Prometheus text format.
+func (api *AdminAPI) Metrics(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, api.formatURL("/metrics"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrRequestCreate, err)
+	}
+	resp, err := api.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrRequestSend, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrDeserialize, err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("%v (%s): %s", ErrResponseError, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
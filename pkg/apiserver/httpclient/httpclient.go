@@ -4,6 +4,7 @@ package httpclient
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -25,7 +26,6 @@ var (
 	ErrDeserialize   = errors.New("failed to deserialize input")
 )
 
-var defaultHTTPClient = http.Client{}
 var defaultJSONPBMarshaller = jsonpb.Marshaler{}
 
 func toJSON(dst io.Writer, m proto.Message) error {
@@ -36,7 +36,8 @@ func fromJSON(src io.Reader, dst proto.Message) error {
 	return jsonpb.Unmarshal(src, dst)
 }
 
-func callWithJSON(ctx context.Context, method string, url string, in proto.Message, out proto.Message) error {
+func (api *baseAPI) callWithJSON(ctx context.Context, method string, url string, in proto.Message,
+	out proto.Message) error {
 	buf := bytes.NewBuffer(nil)
 	if in != nil {
 		err := toJSON(buf, in)
@@ -72,7 +73,7 @@ func callWithJSON(ctx context.Context, method string, url string, in proto.Messa
 		opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, carrier)
 	}
 
-	resp, err := defaultHTTPClient.Do(req.WithContext(ctx))
+	resp, err := api.client.Do(req.WithContext(ctx))
 	if err != nil {
 		return fmt.Errorf("%v: %v", ErrRequestSend, err)
 	}
@@ -104,6 +105,57 @@ func callWithJSON(ctx context.Context, method string, url string, in proto.Messa
 	return nil
 }
 
+// streamChunk is a message of a stream of the HTTP gateway, which contains either a result or an error.
+type streamChunk struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// streamJSON calls a streaming endpoint, calling fn with the result of each message of the stream until the stream
+// ends, fn returns an error, or the context is canceled.
+func (api *baseAPI) streamJSON(ctx context.Context, url string, fn func(result []byte) error) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%v: %v", ErrRequestCreate, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	logrus.Debugf("--> %s %s", http.MethodGet, url)
+
+	resp, err := api.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("%v: %v", ErrRequestSend, err)
+	}
+	defer resp.Body.Close()
+	logrus.Debugf("<-- %s - %s", resp.Status, url)
+	if resp.StatusCode >= 400 {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("%v (%s): %s", ErrResponseError, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		chunk := &streamChunk{}
+		err := decoder.Decode(chunk)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%v: %v", ErrDeserialize, err)
+		}
+		if chunk.Error != nil {
+			return fmt.Errorf("%v: %s", ErrResponseError, chunk.Error.Message)
+		}
+		if err := fn(chunk.Result); err != nil {
+			return err
+		}
+	}
+}
+
 type baseAPI struct {
 	endpoint string
 	client   http.Client
@@ -1,10 +1,15 @@
 package httpclient
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
 )
 
@@ -24,39 +29,107 @@ func NewInvocationAPI(endpoint string, client http.Client) *InvocationAPI {
 func (api *InvocationAPI) Invoke(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.ObjectMetadata,
 	error) {
 	result := &types.ObjectMetadata{}
-	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation"), spec, result)
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation"), spec, result)
 	return result, err
 }
 
 func (api *InvocationAPI) InvokeSync(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.
 	WorkflowInvocation, error) {
 	result := &types.WorkflowInvocation{}
-	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/sync"), spec, result)
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/sync"), spec, result)
 	return result, err
 }
 
-func (api *InvocationAPI) Cancel(ctx context.Context, id string) error {
-	return callWithJSON(ctx, http.MethodDelete, api.formatURL("/invocation/"+id), nil, nil)
+func (api *InvocationAPI) InvokeMany(ctx context.Context, req *apiserver.InvokeManyRequest) (*apiserver.
+	InvokeManyResponse, error) {
+	result := &apiserver.InvokeManyResponse{}
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/batch"), req, result)
+	return result, err
+}
+
+func (api *InvocationAPI) Cancel(ctx context.Context, req *apiserver.CancelRequest) error {
+	query := url.Values{}
+	if len(req.GetReason()) > 0 {
+		query.Set("reason", req.GetReason())
+	}
+	if req.GetCascade() {
+		query.Set("cascade", strconv.FormatBool(req.GetCascade()))
+	}
+	path := "/invocation/" + req.GetId()
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	return api.callWithJSON(ctx, http.MethodDelete, api.formatURL(path), nil, nil)
 }
 
-func (api *InvocationAPI) List(ctx context.Context) (*apiserver.WorkflowInvocationList, error) {
+func (api *InvocationAPI) List(ctx context.Context, query *apiserver.InvocationListQuery) (
+	*apiserver.WorkflowInvocationList, error) {
+	params := url.Values{}
+	for _, wfID := range query.GetWorkflows() {
+		params.Add("workflows", wfID)
+	}
+	if len(query.GetLabelSelector()) > 0 {
+		params.Set("labelSelector", query.GetLabelSelector())
+	}
+	path := "/invocation"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
 	result := &apiserver.WorkflowInvocationList{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL(path), nil, result)
 	return result, err
 }
 
 func (api *InvocationAPI) Get(ctx context.Context, id string) (*types.WorkflowInvocation, error) {
 	result := &types.WorkflowInvocation{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id), nil, result)
 	return result, err
 }
 
 func (api *InvocationAPI) Validate(ctx context.Context, spec *types.WorkflowInvocationSpec) error {
-	return callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/validate"), spec, nil)
+	return api.callWithJSON(ctx, http.MethodPost, api.formatURL("/invocation/validate"), spec, nil)
 }
 
 func (api *InvocationAPI) Events(ctx context.Context, id string) (*apiserver.ObjectEvents, error) {
 	result := &apiserver.ObjectEvents{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/events"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/events"), nil, result)
+	return result, err
+}
+
+func (api *InvocationAPI) GetTaskLogs(ctx context.Context, invocationID, taskID string) (*apiserver.TaskLogs, error) {
+	result := &apiserver.TaskLogs{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+invocationID+"/tasks/"+taskID+"/logs"),
+		nil, result)
+	return result, err
+}
+
+// Explain returns the most recent schedule that the scheduler determined for the invocation.
+func (api *InvocationAPI) Explain(ctx context.Context, id string) (*scheduler.Schedule, error) {
+	result := &scheduler.Schedule{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/invocation/"+id+"/explain"), nil, result)
 	return result, err
 }
+
+// Watch streams the updates of the invocations that match the query, calling fn for each update until the stream
+// ends, fn returns an error or the context is canceled.
+func (api *InvocationAPI) Watch(ctx context.Context, query *apiserver.InvocationWatchQuery,
+	fn func(update *apiserver.InvocationUpdate) error) error {
+	params := url.Values{}
+	for _, id := range query.GetIds() {
+		params.Add("ids", id)
+	}
+	for _, wfID := range query.GetWorkflows() {
+		params.Add("workflows", wfID)
+	}
+	path := "/invocation/watch"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	return api.streamJSON(ctx, api.formatURL(path), func(result []byte) error {
+		update := &apiserver.InvocationUpdate{}
+		if err := fromJSON(bytes.NewReader(result), update); err != nil {
+			return fmt.Errorf("%v: %v", ErrDeserialize, err)
+		}
+		return fn(update)
+	})
+}
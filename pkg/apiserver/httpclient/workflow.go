@@ -3,6 +3,7 @@ package httpclient
 import (
 	"context"
 	"net/http"
+	"net/url"
 
 	"github.com/fission/fission-workflows/pkg/apiserver"
 	"github.com/fission/fission-workflows/pkg/types"
@@ -23,35 +24,72 @@ func NewWorkflowAPI(endpoint string, client http.Client) *WorkflowAPI {
 
 func (api *WorkflowAPI) Create(ctx context.Context, spec *types.WorkflowSpec) (*types.ObjectMetadata, error) {
 	result := &types.ObjectMetadata{}
-	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow"), spec, result)
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow"), spec, result)
 	return result, err
 }
 
 func (api *WorkflowAPI) CreateSync(ctx context.Context, spec *types.WorkflowSpec) (*types.Workflow, error) {
 	wf := &types.Workflow{}
-	err := callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow/sync"), spec, wf)
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow/sync"), spec, wf)
 	return wf, err
 }
 
-func (api *WorkflowAPI) List(ctx context.Context) (*apiserver.WorkflowList, error) {
+func (api *WorkflowAPI) Update(ctx context.Context, req *apiserver.WorkflowUpdateRequest) error {
+	return api.callWithJSON(ctx, http.MethodPut, api.formatURL("/workflow/"+req.GetId()), req, nil)
+}
+
+func (api *WorkflowAPI) List(ctx context.Context, query *apiserver.WorkflowListQuery) (*apiserver.WorkflowList, error) {
+	params := url.Values{}
+	if len(query.GetLabelSelector()) > 0 {
+		params.Set("labelSelector", query.GetLabelSelector())
+	}
+	path := "/workflow"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
 	result := &apiserver.WorkflowList{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL(path), nil, result)
 	return result, err
 }
 
 func (api *WorkflowAPI) Get(ctx context.Context, id string) (*types.Workflow, error) {
 	result := &types.Workflow{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow/"+id), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow/"+id), nil, result)
 	return result, err
 }
 
 func (api *WorkflowAPI) Delete(ctx context.Context, id string) error {
-	err := callWithJSON(ctx, http.MethodDelete, api.formatURL("/workflow/"+id), nil, nil)
+	err := api.callWithJSON(ctx, http.MethodDelete, api.formatURL("/workflow/"+id), nil, nil)
 	return err
 }
 
+func (api *WorkflowAPI) Validate(ctx context.Context, spec *types.WorkflowSpec) (*apiserver.WorkflowValidationResult,
+	error) {
+	result := &apiserver.WorkflowValidationResult{}
+	err := api.callWithJSON(ctx, http.MethodPost, api.formatURL("/workflow/validate"), spec, result)
+	return result, err
+}
+
 func (api *WorkflowAPI) Events(ctx context.Context, id string) (*apiserver.ObjectEvents, error) {
 	result := &apiserver.ObjectEvents{}
-	err := callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow/"+id+"/events"), nil, result)
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL("/workflow/"+id+"/events"), nil, result)
+	return result, err
+}
+
+func (api *WorkflowAPI) Visualize(ctx context.Context, req *apiserver.VisualizeRequest) (*apiserver.Visualization,
+	error) {
+	params := url.Values{}
+	if len(req.GetInvocationId()) > 0 {
+		params.Set("invocationId", req.GetInvocationId())
+	}
+	if len(req.GetFormat()) > 0 {
+		params.Set("format", req.GetFormat())
+	}
+	path := "/workflow/" + req.GetWorkflowId() + "/graph"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+	result := &apiserver.Visualization{}
+	err := api.callWithJSON(ctx, http.MethodGet, api.formatURL(path), nil, result)
 	return result, err
 }
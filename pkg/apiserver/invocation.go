@@ -1,21 +1,41 @@
 package apiserver
 
 import (
-	"fmt"
 	"sort"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/fnenv"
 	workflowFnenv "github.com/fission/fission-workflows/pkg/fnenv/workflows"
+	"github.com/fission/fission-workflows/pkg/quota"
+	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/fission/fission-workflows/pkg/util/labels"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// WatchResyncInterval is the interval at which the watched invocations are checked for missed updates.
+	WatchResyncInterval = 5 * time.Second
+
+	// MaxBatchSize is the maximum number of invocations that can be created with a single InvokeMany request.
+	MaxBatchSize = 1000
+
+	// taskLogsMargin extends the period in which the logs of a task are looked up, to account for clock skew between
+	// the workflow engine and the function environment.
+	taskLogsMargin = time.Second
 )
 
 // Invocation is responsible for all functionality related to managing invocations.
@@ -25,16 +45,63 @@ type Invocation struct {
 	workflows   *store.Workflows
 	fnenv       *workflowFnenv.Runtime
 	backend     fes.Backend
+	authorizer  auth.Authorizer
+	quotas      *quota.Manager
+	logs        map[string]fnenv.LogProvider
+	scheduler   *scheduler.InvocationScheduler
 }
 
-func NewInvocation(api *api.Invocation, invocations *store.Invocations, workflows *store.Workflows, backend fes.Backend) WorkflowInvocationAPIServer {
+// NewInvocation creates the invocation API server. If the authorizer is nil, the callers are not authorized. If quotas
+// is nil, the namespaces are not limited. The logs of tasks are only available for the function environments in logs.
+// The schedules of invocations can only be explained if the scheduler of the invocation controller is provided.
+func NewInvocation(api *api.Invocation, invocations *store.Invocations, workflows *store.Workflows, backend fes.Backend,
+	authorizer auth.Authorizer, quotas *quota.Manager, logs map[string]fnenv.LogProvider,
+	scheduler *scheduler.InvocationScheduler) WorkflowInvocationAPIServer {
 	return &Invocation{
 		api:         api,
 		invocations: invocations,
 		workflows:   workflows,
 		fnenv:       workflowFnenv.NewRuntime(api, invocations, workflows),
 		backend:     backend,
+		authorizer:  authorizer,
+		quotas:      quotas,
+		logs:        logs,
+		scheduler:   scheduler,
+	}
+}
+
+// admit checks whether the invocations of the workflow fit in the quota of the namespace of the workflow.
+func (gi *Invocation) admit(wf *types.Workflow, specs ...*types.WorkflowInvocationSpec) error {
+	if gi.quotas == nil {
+		return nil
+	}
+	namespace := wf.GetSpec().GetNamespace()
+	if err := gi.quotas.AdmitInvocations(namespace, len(specs)); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	for _, spec := range specs {
+		if err := gi.quotas.CheckPayload(namespace, spec.GetInputs()); err != nil {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+	return nil
+}
+
+// authorizeInvocation checks whether the caller has the permission for the workflow of the invocation. The workflow is
+// always looked up in the store, because the workflow embedded in the spec is provided by the caller.
+func (gi *Invocation) authorizeInvocation(ctx context.Context, permission auth.Permission,
+	spec *types.WorkflowInvocationSpec) error {
+	if gi.authorizer == nil {
+		return nil
+	}
+	wf, err := gi.workflows.GetWorkflow(spec.GetWorkflowId())
+	if err != nil {
+		return toErrorStatus(err)
+	}
+	if wf == nil {
+		return status.Errorf(codes.NotFound, "workflow %s not found", spec.GetWorkflowId())
 	}
+	return authorize(ctx, gi.authorizer, permission, spec.GetWorkflowId(), wf.GetSpec())
 }
 
 func (gi *Invocation) Validate(ctx context.Context, spec *types.WorkflowInvocationSpec) (*empty.Empty, error) {
@@ -46,6 +113,9 @@ func (gi *Invocation) Validate(ctx context.Context, spec *types.WorkflowInvocati
 }
 
 func (gi *Invocation) Invoke(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.ObjectMetadata, error) {
+	if err := checkAvailable(gi.backend); err != nil {
+		return nil, err
+	}
 	// TODO go through same runtime as InvokeSync
 	// Check if the workflow required by the invocation exists
 	wf, err := gi.workflows.GetWorkflow(spec.GetWorkflowId())
@@ -53,16 +123,44 @@ func (gi *Invocation) Invoke(ctx context.Context, spec *types.WorkflowInvocation
 		return nil, err
 	}
 	spec.Workflow = wf
+	if err := gi.authorizeInvocation(ctx, auth.PermissionInvoke, spec); err != nil {
+		return nil, err
+	}
+	if err := gi.admit(wf, spec); err != nil {
+		return nil, err
+	}
 
 	eventID, err := gi.api.Invoke(spec, api.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
+	if gi.quotas != nil {
+		gi.quotas.Track(wf.GetSpec().GetNamespace(), eventID)
+	}
 
 	return &types.ObjectMetadata{Id: eventID}, nil
 }
 
 func (gi *Invocation) InvokeSync(ctx context.Context, spec *types.WorkflowInvocationSpec) (*types.WorkflowInvocation, error) {
+	if err := checkAvailable(gi.backend); err != nil {
+		return nil, err
+	}
+	// The workflow embedded in the spec is provided by the caller, so it is replaced by the stored workflow; otherwise
+	// a caller could execute a workflow of its own under the id, and thus the permissions, of another workflow. The
+	// runtime looks up the stored workflow (of the requested version) once it is ready.
+	spec.Workflow = nil
+	if err := gi.authorizeInvocation(ctx, auth.PermissionInvoke, spec); err != nil {
+		return nil, err
+	}
+	if gi.quotas != nil {
+		wf, err := gi.workflows.GetWorkflow(spec.GetWorkflowId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		if err := gi.admit(wf, spec); err != nil {
+			return nil, err
+		}
+	}
 	wfi, err := gi.fnenv.InvokeWorkflow(spec, fnenv.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
@@ -70,8 +168,80 @@ func (gi *Invocation) InvokeSync(ctx context.Context, spec *types.WorkflowInvoca
 	return wfi, nil
 }
 
-func (gi *Invocation) Cancel(ctx context.Context, objectMetadata *types.ObjectMetadata) (*empty.Empty, error) {
-	err := gi.api.Cancel(objectMetadata.GetId())
+func (gi *Invocation) InvokeMany(ctx context.Context, req *InvokeManyRequest) (*InvokeManyResponse, error) {
+	if req.GetSpec() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no invocation spec provided")
+	}
+	if len(req.GetInputs()) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d invocations exceeds the maximum of %d",
+			len(req.GetInputs()), MaxBatchSize)
+	}
+	if req.GetMaxRate() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max rate should not be negative")
+	}
+	if err := checkAvailable(gi.backend); err != nil {
+		return nil, err
+	}
+	wf, err := gi.workflows.GetWorkflow(req.GetSpec().GetWorkflowId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	if wf == nil {
+		return nil, status.Errorf(codes.NotFound, "workflow %s not found", req.GetSpec().GetWorkflowId())
+	}
+	if err := authorize(ctx, gi.authorizer, auth.PermissionInvoke, wf.ID(), wf.GetSpec()); err != nil {
+		return nil, err
+	}
+
+	specs := make([]*types.WorkflowInvocationSpec, len(req.GetInputs()))
+	for i, inputSet := range req.GetInputs() {
+		spec := *req.GetSpec()
+		spec.Workflow = wf
+		spec.Inputs = make(map[string]*typedvalues.TypedValue, len(spec.Inputs)+len(inputSet.GetInputs()))
+		for k, v := range req.GetSpec().GetInputs() {
+			spec.Inputs[k] = v
+		}
+		for k, v := range inputSet.GetInputs() {
+			spec.Inputs[k] = v
+		}
+		specs[i] = &spec
+	}
+	if err := gi.admit(wf, specs...); err != nil {
+		return nil, err
+	}
+
+	ids, errs := gi.api.InvokeMany(specs, api.WithContext(ctx), api.RateLimit(req.GetMaxRate()))
+	results := make([]*InvokeManyResult, len(specs))
+	for i := range specs {
+		if errs[i] != nil {
+			results[i] = &InvokeManyResult{Error: &types.Error{Message: validate.FormatConcise(errs[i])}}
+		} else {
+			results[i] = &InvokeManyResult{Id: ids[i]}
+			if gi.quotas != nil {
+				gi.quotas.Track(wf.GetSpec().GetNamespace(), ids[i])
+			}
+		}
+	}
+	return &InvokeManyResponse{Results: results}, nil
+}
+
+func (gi *Invocation) Cancel(ctx context.Context, req *CancelRequest) (*empty.Empty, error) {
+	if gi.authorizer != nil {
+		wfi, err := gi.invocations.GetInvocation(req.GetId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		if err := gi.authorizeInvocation(ctx, auth.PermissionCancel, wfi.GetSpec()); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if req.GetCascade() {
+		err = gi.api.CancelCascade(req.GetId(), req.GetReason(), gi.invocations)
+	} else {
+		err = gi.api.CancelWithReason(req.GetId(), req.GetReason())
+	}
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
@@ -88,6 +258,11 @@ func (gi *Invocation) Get(ctx context.Context, objectMetadata *types.ObjectMetad
 }
 
 func (gi *Invocation) List(ctx context.Context, query *InvocationListQuery) (*WorkflowInvocationList, error) {
+	selector, err := labels.ParseSelector(query.GetLabelSelector())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	var invocations []string
 	as := gi.invocations.List()
 	for _, aggregate := range as {
@@ -96,7 +271,7 @@ func (gi *Invocation) List(ctx context.Context, query *InvocationListQuery) (*Wo
 			continue
 		}
 
-		if len(query.Workflows) > 0 {
+		if len(query.Workflows) > 0 || len(query.GetLabelSelector()) > 0 {
 			// TODO make more efficient (by moving list queries to invocations)
 			entity, err := gi.invocations.GetAggregate(aggregate)
 			if err != nil {
@@ -104,7 +279,10 @@ func (gi *Invocation) List(ctx context.Context, query *InvocationListQuery) (*Wo
 				continue
 			}
 			wfi := entity.(*types.WorkflowInvocation)
-			if !contains(query.Workflows, wfi.GetSpec().GetWorkflowId()) {
+			if len(query.Workflows) > 0 && !contains(query.Workflows, wfi.GetSpec().GetWorkflowId()) {
+				continue
+			}
+			if !selector.Matches(labels.Set(wfi.GetMetadata().GetLabels())) {
 				continue
 			}
 		}
@@ -114,11 +292,105 @@ func (gi *Invocation) List(ctx context.Context, query *InvocationListQuery) (*Wo
 	return &WorkflowInvocationList{Invocations: invocations}, nil
 }
 
+func (gi *Invocation) Watch(query *InvocationWatchQuery, stream WorkflowInvocationAPI_WatchServer) error {
+	// Subscribe before sending the current state, to avoid missing updates in between.
+	sub := gi.invocations.GetInvocationUpdates()
+	if sub == nil {
+		return status.Error(codes.Unimplemented, "invocation store does not support watches")
+	}
+	defer sub.Close()
+
+	// If only specific invocations are watched, the watch ends once all of them have finished.
+	pending := map[string]bool{}
+	for _, id := range query.GetIds() {
+		wi, err := gi.invocations.GetInvocation(id)
+		if err != nil {
+			return toErrorStatus(err)
+		}
+		if wi == nil {
+			return status.Errorf(codes.NotFound, "invocation %s not found", id)
+		}
+		if !matchesInvocationQuery(query, wi) {
+			continue
+		}
+		if err := stream.Send(&InvocationUpdate{Invocation: wi}); err != nil {
+			return err
+		}
+		if !invocationFinished(wi) {
+			pending[id] = true
+		}
+	}
+	untilFinished := len(query.GetIds()) > 0
+	if untilFinished && len(pending) == 0 {
+		return nil
+	}
+
+	// Notifications are dropped if the subscription falls behind, so the pending invocations are checked
+	// periodically, to ensure that the watch ends.
+	resync := time.NewTicker(WatchResyncInterval)
+	defer resync.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-resync.C:
+			for id := range pending {
+				wi, err := gi.invocations.GetInvocation(id)
+				if err != nil || !invocationFinished(wi) {
+					continue
+				}
+				if err := stream.Send(&InvocationUpdate{Invocation: wi}); err != nil {
+					return err
+				}
+				delete(pending, id)
+			}
+			if len(pending) == 0 {
+				return nil
+			}
+		case msg, ok := <-sub.Ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch of invocations was closed")
+			}
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			wi, err := store.ParseNotificationToInvocation(notification)
+			if err != nil || !matchesInvocationQuery(query, wi) {
+				continue
+			}
+			err = stream.Send(&InvocationUpdate{
+				Event:      notification.Event.GetType(),
+				Invocation: wi,
+			})
+			if err != nil {
+				return err
+			}
+			if untilFinished && invocationFinished(wi) {
+				delete(pending, wi.ID())
+				if len(pending) == 0 {
+					return nil
+				}
+			}
+		}
+	}
+}
+
 func (gi *Invocation) AddTask(ctx context.Context, req *AddTaskRequest) (*empty.Empty, error) {
 	invocation, err := gi.invocations.GetInvocation(req.GetInvocationID())
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
+	if err := gi.authorizeInvocation(ctx, auth.PermissionInvoke, invocation.GetSpec()); err != nil {
+		return nil, err
+	}
+	if gi.quotas != nil {
+		namespace := invocation.Workflow().GetSpec().GetNamespace()
+		if err := gi.quotas.CheckPayload(namespace, req.GetTask().GetSpec().GetInputs()); err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
 	if err := gi.api.AddTask(invocation.ID(), req.Task); err != nil {
 		return nil, err
 	}
@@ -126,26 +398,16 @@ func (gi *Invocation) AddTask(ctx context.Context, req *AddTaskRequest) (*empty.
 }
 
 func (gi *Invocation) Events(ctx context.Context, md *types.ObjectMetadata) (*ObjectEvents, error) {
-	events, err := gi.backend.Get(projectors.NewWorkflowAggregate(md.Id))
-	if err != nil {
+	if _, err := gi.invocations.GetInvocation(md.Id); err != nil {
 		return nil, toErrorStatus(err)
 	}
 
-	// TODO this should not be this cumbersome
-	wi, err := gi.invocations.GetInvocation(md.Id)
+	// The events of the tasks are stored with the events of their invocation.
+	events, err := gi.backend.Get(projectors.NewInvocationAggregate(md.Id))
 	if err != nil {
 		return nil, toErrorStatus(err)
 	}
 
-	// Fold task events into invocation events
-	for _, task := range wi.GetStatus().GetTasks() {
-		taskEvents, err := gi.taskEvents(task.ID())
-		if err != nil {
-			return nil, toErrorStatus(fmt.Errorf("failed to fetch task events: %v", err))
-		}
-		events = append(events, taskEvents...)
-	}
-
 	sort.SliceStable(events, func(i, j int) bool {
 		return util.CmpProtoTimestamps(events[i].GetTimestamp(), events[j].GetTimestamp())
 	})
@@ -156,8 +418,90 @@ func (gi *Invocation) Events(ctx context.Context, md *types.ObjectMetadata) (*Ob
 	}, nil
 }
 
-func (gi *Invocation) taskEvents(taskRunID string) ([]*fes.Event, error) {
-	return gi.backend.Get(projectors.NewTaskRunAggregate(taskRunID))
+// GetTaskLogs returns the logs that the function of the task wrote during the invocation. As the start of a task run
+// is not recorded, the logs are looked up from the start of the invocation until the end of the task run.
+func (gi *Invocation) GetTaskLogs(ctx context.Context, req *TaskLogsRequest) (*TaskLogs, error) {
+	wi, err := gi.invocations.GetInvocation(req.GetInvocationID())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	task, ok := wi.Task(req.GetTaskID())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "task %s does not exist in invocation %s", req.GetTaskID(),
+			req.GetInvocationID())
+	}
+	fn := task.GetStatus().GetFnRef()
+	if fn == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "task %s has not been resolved to a function",
+			req.GetTaskID())
+	}
+	provider, ok := gi.logs[fn.Runtime]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "logs are not available for function environment '%s'",
+			fn.Runtime)
+	}
+
+	since, err := ptypes.Timestamp(wi.GetMetadata().GetCreatedAt())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	until := time.Now()
+	if run, ok := wi.TaskInvocation(req.GetTaskID()); ok && run.GetStatus().Finished() {
+		if finishedAt, err := ptypes.Timestamp(run.GetStatus().GetUpdatedAt()); err == nil {
+			until = finishedAt
+		}
+	}
+	entries, err := provider.Logs(ctx, *fn, since.Add(-taskLogsMargin), until.Add(taskLogsMargin))
+	if err != nil {
+		logrus.Errorf("Failed to fetch logs of function %s: %v", fn.Format(), err)
+		return nil, status.Errorf(codes.Unavailable, "failed to fetch logs of function %s: %v", fn.Format(), err)
+	}
+
+	logs := &TaskLogs{}
+	for _, entry := range entries {
+		ts, err := ptypes.TimestampProto(entry.Timestamp)
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		logs.Entries = append(logs.Entries, &LogEntry{
+			Timestamp: ts,
+			Message:   entry.Message,
+			Stream:    entry.Stream,
+			Source:    entry.Source,
+		})
+	}
+	return logs, nil
+}
+
+// Explain returns the most recent schedule of the invocation, which explains which tasks were runnable, blocked or
+// prewarmed in the last evaluation of the invocation by the scheduler.
+func (gi *Invocation) Explain(ctx context.Context, objectMetadata *types.ObjectMetadata) (*scheduler.Schedule, error) {
+	if gi.scheduler == nil {
+		return nil, status.Error(codes.Unimplemented, "the invocation controller does not run in this process")
+	}
+	wi, err := gi.invocations.GetInvocation(objectMetadata.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	schedule, ok := gi.scheduler.Explain(wi.ID())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "invocation %s has not been evaluated by the scheduler", wi.ID())
+	}
+	return schedule, nil
+}
+
+func matchesInvocationQuery(query *InvocationWatchQuery, wi *types.WorkflowInvocation) bool {
+	if len(query.GetIds()) > 0 && !contains(query.GetIds(), wi.ID()) {
+		return false
+	}
+	if len(query.GetWorkflows()) > 0 && !contains(query.GetWorkflows(), wi.GetSpec().GetWorkflowId()) {
+		return false
+	}
+	return true
+}
+
+func invocationFinished(wi *types.WorkflowInvocation) bool {
+	return wi.GetStatus() != nil && wi.GetStatus().Finished()
 }
 
 func contains(haystack []string, needle string) bool {
@@ -0,0 +1,58 @@
+package apiserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestInvocation_AuthorizeSpoofedWorkflow ensures that the permissions of an invocation are checked against the stored
+// workflow, rather than against a workflow that the caller embedded in the spec.
+func TestInvocation_AuthorizeSpoofedWorkflow(t *testing.T) {
+	backend := mem.NewBackend()
+	workflowsCache := testutil.NewCache()
+	require.NoError(t, workflowsCache.Put(&types.Workflow{
+		Metadata: &types.ObjectMetadata{Id: "wf-prod"},
+		Spec:     &types.WorkflowSpec{Namespace: "prod", Name: "payout"},
+		Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_READY},
+	}))
+	policy := &auth.Policy{Rules: []auth.Rule{
+		{
+			Subjects:    []string{"user:dev"},
+			Permissions: []auth.Permission{auth.PermissionInvoke, auth.PermissionCancel},
+			Namespaces:  []string{"dev"},
+		},
+	}}
+	server := NewInvocation(api.NewInvocationAPI(backend, nil), store.NewInvocationStore(testutil.NewCache()),
+		store.NewWorkflowsStore(workflowsCache), backend, policy, nil, nil, nil)
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "dev", Issuer: "https://idp"})
+
+	newSpoofedSpec := func() *types.WorkflowInvocationSpec {
+		spec := types.NewWorkflowInvocationSpec("wf-prod", time.Now().Add(time.Minute))
+		spec.Workflow = &types.Workflow{
+			Metadata: &types.ObjectMetadata{Id: "wf-prod"},
+			Spec:     &types.WorkflowSpec{Namespace: "dev", Name: "payout"},
+			Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_READY},
+		}
+		return spec
+	}
+
+	_, err := server.InvokeSync(ctx, newSpoofedSpec())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "InvokeSync: %v", err)
+
+	_, err = server.Invoke(ctx, newSpoofedSpec())
+	assert.Equal(t, codes.PermissionDenied, status.Code(err), "Invoke: %v", err)
+
+	assert.Equal(t, 0, backend.Len())
+}
@@ -0,0 +1,207 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
+	grpcruntime "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DocumentPath is the path of the document that describes the invoke endpoints of all workflows.
+	DocumentPath = "/openapi.json"
+
+	// workflowPathPrefix is the prefix of the paths of the endpoints of a single workflow, which are served under the
+	// path of the workflow in the workflow API, such as /workflow/<id>/invoke.
+	workflowPathPrefix = "/workflow/"
+	invokeSuffix       = "/invoke"
+	documentSuffix     = DocumentPath
+
+	authorizationHeader = "authorization"
+)
+
+// Handler serves the invoke endpoints of the workflows on the HTTP gateway, along with the OpenAPI documents that
+// describe them. The endpoints invoke the workflows through the gRPC APIs on behalf of the caller, so the requests are
+// authorized like the other requests to the HTTP gateway. Requests to other paths are passed on to the next handler.
+type Handler struct {
+	client  *apiserver.Client
+	timeout time.Duration
+	next    http.Handler
+}
+
+// NewHandler creates a handler that serves the workflow endpoints, using the client to access the workflows and
+// invocations. The timeout is the deadline of the invocations of the invoke endpoints.
+func NewHandler(client *apiserver.Client, timeout time.Duration, next http.Handler) *Handler {
+	if timeout <= 0 {
+		panic("timeout of the invoke endpoints must be larger than 0")
+	}
+	return &Handler{
+		client:  client,
+		timeout: timeout,
+		next:    next,
+	}
+}
+
+// InvokePath returns the path of the invoke endpoint of the workflow.
+func InvokePath(wfID string) string {
+	return workflowPathPrefix + wfID + invokeSuffix
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == DocumentPath && r.Method == http.MethodGet {
+		h.handleDocument(w, r)
+		return
+	}
+	if wfID, ok := matchWorkflowPath(r.URL.Path, invokeSuffix); ok && r.Method == http.MethodPost {
+		h.handleInvoke(w, r, wfID)
+		return
+	}
+	if wfID, ok := matchWorkflowPath(r.URL.Path, documentSuffix); ok && r.Method == http.MethodGet {
+		h.handleWorkflowDocument(w, r, wfID)
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// handleDocument serves the document that describes the invoke endpoints of all workflows that can be invoked.
+func (h *Handler) handleDocument(w http.ResponseWriter, r *http.Request) {
+	ctx := forwardAuthorization(r)
+	wfIDs, err := h.client.Workflow.List(ctx, &apiserver.WorkflowListQuery{})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	doc := NewDocument("Fission Workflows")
+	doc.Info.Description = "The invoke endpoints of the workflows."
+	for _, wfID := range wfIDs.GetWorkflows() {
+		wf, err := h.client.Workflow.Get(ctx, &types.ObjectMetadata{Id: wfID})
+		if err != nil {
+			// The workflow might have been deleted in the meantime.
+			logrus.Debugf("Failed to get workflow %s for the OpenAPI document: %v", wfID, err)
+			continue
+		}
+		if wf.GetSpec().GetInternal() || !wf.GetStatus().Ready() {
+			continue
+		}
+		if err := doc.AddWorkflow(InvokePath(wfID), wf, MapJSONBody); err != nil {
+			logrus.Warnf("Omitting workflow %s from the OpenAPI document: %v", wfID, err)
+		}
+	}
+	writeJSON(w, doc)
+}
+
+// handleWorkflowDocument serves the document that describes the invoke endpoint of a single workflow.
+func (h *Handler) handleWorkflowDocument(w http.ResponseWriter, r *http.Request, wfID string) {
+	wf, err := h.client.Workflow.Get(forwardAuthorization(r), &types.ObjectMetadata{Id: wfID})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	title := wf.GetSpec().GetName()
+	if len(title) == 0 {
+		title = wfID
+	}
+	doc := NewDocument(title)
+	doc.Info.Description = wf.GetSpec().GetDescription()
+	if err := doc.AddWorkflow(InvokePath(wfID), wf, MapJSONBody); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, doc)
+}
+
+// handleInvoke invokes the workflow with the properties of the JSON object in the body as inputs, and responds with
+// the output of the invocation.
+func (h *Handler) handleInvoke(w http.ResponseWriter, r *http.Request, wfID string) {
+	inputs, err := parseInputs(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	spec := types.NewWorkflowInvocationSpec(wfID, time.Now().Add(h.timeout))
+	spec.Inputs = inputs
+	wi, err := h.client.Invocation.InvokeSync(forwardAuthorization(r), spec)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if !wi.GetStatus().Successful() && wi.GetStatus().GetError() == nil {
+		wi.Status.Error = &types.Error{
+			Message: fmt.Sprintf("invocation did not succeed (status: %v)", wi.GetStatus().GetStatus()),
+		}
+	}
+	httpconv.FormatResponse(w, wi.GetStatus().GetOutput(), wi.GetStatus().GetOutputHeaders(),
+		wi.GetStatus().GetError())
+}
+
+// parseInputs parses the body of the request, which should be a JSON object, to the inputs of an invocation. An empty
+// body results in no inputs.
+func parseInputs(r *http.Request) (map[string]*typedvalues.TypedValue, error) {
+	defer r.Body.Close()
+	bs, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %v", err)
+	}
+	if len(strings.TrimSpace(string(bs))) == 0 {
+		return nil, nil
+	}
+	var inputs map[string]interface{}
+	if err := json.Unmarshal(bs, &inputs); err != nil {
+		return nil, fmt.Errorf("body should be a JSON object with the inputs: %v", err)
+	}
+	return typedvalues.WrapMapTypedValue(inputs)
+}
+
+// matchWorkflowPath returns the workflow id in the path, if the path is the path of a workflow with the suffix.
+func matchWorkflowPath(path string, suffix string) (string, bool) {
+	if !strings.HasPrefix(path, workflowPathPrefix) {
+		return "", false
+	}
+	rest := strings.TrimPrefix(path, workflowPathPrefix)
+	if !strings.HasSuffix(rest, suffix) {
+		return "", false
+	}
+	wfID := strings.TrimSuffix(rest, suffix)
+	if len(wfID) == 0 || strings.Contains(wfID, "/") {
+		return "", false
+	}
+	return wfID, true
+}
+
+// forwardAuthorization returns the context of the request with the Authorization header of the request as metadata,
+// so that the gRPC APIs authorize the caller, like the HTTP gateway does for the other requests.
+func forwardAuthorization(r *http.Request) context.Context {
+	ctx := r.Context()
+	if authorization := r.Header.Get(authorizationHeader); len(authorization) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, authorizationHeader, authorization)
+	}
+	return ctx
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.Write(bs)
+}
+
+// writeError responds with the HTTP status that corresponds to the gRPC status of the error.
+func writeError(w http.ResponseWriter, err error) {
+	s, _ := status.FromError(err)
+	http.Error(w, s.Message(), grpcruntime.HTTPStatusFromCode(s.Code()))
+}
@@ -0,0 +1,289 @@
+// Package openapi generates OpenAPI documents that describe the invoke endpoints of workflows, so that API consumers
+// and API gateways can discover the APIs that the deployed workflows offer.
+//
+// The request of an invoke endpoint is described by the input schema of the workflow, taking the default inputs of the
+// workflow into account. How the request maps onto the inputs depends on the server that serves the endpoint (see
+// Mapping).
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/version"
+)
+
+// Version is the version of the OpenAPI specification that the generated documents conform to.
+const Version = "3.0.3"
+
+const (
+	contentTypeJSON = "application/json"
+	contentTypeText = "text/plain"
+)
+
+// Mapping determines how an HTTP request to an invoke endpoint is mapped onto the inputs of the workflow invocation.
+type Mapping int
+
+const (
+	// MapJSONBody maps the properties of the JSON object in the body of the request onto the inputs, with the keys of
+	// the object being the input keys. The HTTP gateway uses this mapping.
+	MapJSONBody Mapping = iota
+
+	// MapHTTPRequest maps the body, query and headers of the request onto the default, query and headers inputs, like
+	// the HTTP-based function environments do (see httpconv). The Fission proxy uses this mapping.
+	MapHTTPRequest
+)
+
+// Document is an OpenAPI document. It only models the parts of the specification that are needed to describe the
+// invoke endpoints of workflows.
+type Document struct {
+	OpenAPI string               `json:"openapi"`
+	Info    Info                 `json:"info"`
+	Paths   map[string]*PathItem `json:"paths"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type PathItem struct {
+	Post *Operation `json:"post,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Parameters  []*Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required,omitempty"`
+	Schema   Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// Schema is a JSON Schema, which is kept as a generic map to preserve the input schemas of workflows as they are.
+type Schema map[string]interface{}
+
+// NewDocument creates an empty document with the given title.
+func NewDocument(title string) *Document {
+	return &Document{
+		OpenAPI: Version,
+		Info: Info{
+			Title:   title,
+			Version: version.Version,
+		},
+		Paths: map[string]*PathItem{},
+	}
+}
+
+// AddWorkflow adds the invoke endpoint of the workflow at the path to the document, with the request being mapped
+// onto the inputs of the workflow according to the mapping.
+func (d *Document) AddWorkflow(path string, wf *types.Workflow, mapping Mapping) error {
+	op, err := InvokeOperation(wf, mapping)
+	if err != nil {
+		return err
+	}
+	d.Paths[path] = &PathItem{Post: op}
+	return nil
+}
+
+// InvokeOperation describes the invocation of the workflow as an operation.
+func InvokeOperation(wf *types.Workflow, mapping Mapping) (*Operation, error) {
+	spec := wf.GetSpec()
+	inputSchema, err := parseInputSchema(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input schema of workflow %s: %v", wf.ID(), err)
+	}
+
+	name := spec.GetName()
+	if len(name) == 0 {
+		name = wf.ID()
+	}
+	op := &Operation{
+		OperationID: "invoke-" + wf.ID(),
+		Summary:     "Invoke workflow " + name,
+		Description: spec.GetDescription(),
+		Responses: map[string]*Response{
+			"200": {
+				Description: "The output of the workflow.",
+				Content:     map[string]*MediaType{contentTypeJSON: {Schema: outputSchema(spec)}},
+			},
+			"400": {Description: "The inputs do not conform to the input schema of the workflow."},
+			"500": {Description: "The invocation of the workflow failed."},
+		},
+	}
+	switch mapping {
+	case MapJSONBody:
+		op.RequestBody = &RequestBody{
+			Required: len(requiredProperties(inputSchema)) > 0,
+			Content:  map[string]*MediaType{contentTypeJSON: {Schema: inputSchema}},
+		}
+	case MapHTTPRequest:
+		properties := propertySchemas(inputSchema)
+		op.Parameters = append(parameters(properties[types.InputQuery], "query"),
+			parameters(properties[types.InputHeaders], "header")...)
+		body, required := properties[types.InputMain], requiredProperties(inputSchema)[types.InputMain]
+		if body == nil {
+			body, required = properties[types.InputBody], requiredProperties(inputSchema)[types.InputBody]
+		}
+		op.RequestBody = requestBody(body, required)
+	default:
+		return nil, fmt.Errorf("unknown mapping: %v", mapping)
+	}
+	return op, nil
+}
+
+// parseInputSchema returns the input schema of the workflow, with the properties that have a default input not being
+// required anymore. If the workflow has no input schema, the schema accepts any object.
+func parseInputSchema(spec *types.WorkflowSpec) (Schema, error) {
+	schema := Schema{"type": "object"}
+	if len(spec.GetInputSchema()) > 0 {
+		if err := json.Unmarshal([]byte(spec.GetInputSchema()), &schema); err != nil {
+			return nil, err
+		}
+	}
+	if len(spec.GetDefaultInputs()) == 0 {
+		return schema, nil
+	}
+
+	properties := propertySchemas(schema)
+	required := requiredProperties(schema)
+	for key, tv := range spec.GetDefaultInputs() {
+		delete(required, key)
+		property, ok := properties[key]
+		if !ok {
+			continue
+		}
+		if _, ok := property["default"]; ok {
+			continue
+		}
+		if v, err := typedvalues.Unwrap(tv); err == nil {
+			property["default"] = v
+		}
+	}
+	if len(required) > 0 {
+		schema["required"] = sortedKeys(required)
+	} else {
+		delete(schema, "required")
+	}
+	return schema, nil
+}
+
+// outputSchema describes the output of the workflow, which is either a map of the named outputs, or the output of the
+// output task, which can be anything.
+func outputSchema(spec *types.WorkflowSpec) Schema {
+	if len(spec.GetOutputs()) == 0 {
+		return Schema{}
+	}
+	properties := map[string]interface{}{}
+	required := map[string]bool{}
+	for key := range spec.GetOutputs() {
+		properties[key] = Schema{}
+		required[key] = true
+	}
+	return Schema{
+		"type":       "object",
+		"properties": properties,
+		"required":   sortedKeys(required),
+	}
+}
+
+// parameters describes the properties of the schema as parameters, such as the query parameters of the request.
+func parameters(schema Schema, in string) []*Parameter {
+	properties := propertySchemas(schema)
+	required := requiredProperties(schema)
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var params []*Parameter
+	for _, name := range names {
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       in,
+			Required: required[name],
+			Schema:   properties[name],
+		})
+	}
+	return params
+}
+
+// requestBody describes the body of the request with the schema. A body that is a string is sent as plain text, and
+// any other body as JSON.
+func requestBody(schema Schema, required bool) *RequestBody {
+	if schema == nil {
+		schema = Schema{}
+	}
+	contentType := contentTypeJSON
+	if schema["type"] == "string" {
+		contentType = contentTypeText
+	}
+	return &RequestBody{
+		Required: required,
+		Content:  map[string]*MediaType{contentType: {Schema: schema}},
+	}
+}
+
+// propertySchemas returns the schemas of the properties of an object schema by the property names.
+func propertySchemas(schema Schema) map[string]Schema {
+	properties := map[string]Schema{}
+	m, _ := schema["properties"].(map[string]interface{})
+	for name, property := range m {
+		if s, ok := property.(map[string]interface{}); ok {
+			properties[name] = s
+		}
+	}
+	return properties
+}
+
+// requiredProperties returns the set of the required properties of an object schema.
+func requiredProperties(schema Schema) map[string]bool {
+	required := map[string]bool{}
+	switch names := schema["required"].(type) {
+	case []interface{}:
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,153 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvokeOperation_JSONBody(t *testing.T) {
+	wf := &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf-123"),
+		Spec: &types.WorkflowSpec{
+			Name:        "greet",
+			Description: "Greets the user.",
+			InputSchema: `{
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"greeting": {"type": "string"}
+				},
+				"required": ["name", "greeting"]
+			}`,
+			DefaultInputs: map[string]*typedvalues.TypedValue{
+				"greeting": typedvalues.MustWrap("hello"),
+			},
+			Outputs: map[string]*typedvalues.TypedValue{
+				"message": typedvalues.MustWrap("{ output('greet') }"),
+			},
+		},
+	}
+	op, err := InvokeOperation(wf, MapJSONBody)
+	require.NoError(t, err)
+	assert.Equal(t, "invoke-wf-123", op.OperationID)
+	assert.Equal(t, "Invoke workflow greet", op.Summary)
+	assert.Equal(t, "Greets the user.", op.Description)
+	assert.Empty(t, op.Parameters)
+
+	// The input with a default input is not required anymore, and documents the default.
+	bs, err := json.Marshal(op.RequestBody)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"required": true,
+		"content": {
+			"application/json": {
+				"schema": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string"},
+						"greeting": {"type": "string", "default": "hello"}
+					},
+					"required": ["name"]
+				}
+			}
+		}
+	}`, string(bs))
+
+	bs, err = json.Marshal(op.Responses["200"].Content["application/json"].Schema)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "object", "properties": {"message": {}}, "required": ["message"]}`, string(bs))
+}
+
+func TestInvokeOperation_HTTPRequest(t *testing.T) {
+	wf := &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf-123"),
+		Spec: &types.WorkflowSpec{
+			InputSchema: `{
+				"properties": {
+					"default": {"type": "object", "properties": {"items": {"type": "array"}}},
+					"query": {"properties": {"limit": {"type": "integer"}, "dryRun": {"type": "boolean"}}},
+					"headers": {"properties": {"X-Tenant": {"type": "string"}}, "required": ["X-Tenant"]}
+				},
+				"required": ["default"]
+			}`,
+		},
+	}
+	op, err := InvokeOperation(wf, MapHTTPRequest)
+	require.NoError(t, err)
+	assert.Equal(t, "Invoke workflow wf-123", op.Summary)
+	assert.Equal(t, []*Parameter{
+		{Name: "dryRun", In: "query", Schema: Schema{"type": "boolean"}},
+		{Name: "limit", In: "query", Schema: Schema{"type": "integer"}},
+		{Name: "X-Tenant", In: "header", Required: true, Schema: Schema{"type": "string"}},
+	}, op.Parameters)
+	assert.True(t, op.RequestBody.Required)
+	assert.Equal(t, "object", op.RequestBody.Content["application/json"].Schema["type"])
+
+	// Without an input schema, the body can be anything.
+	wf.Spec.InputSchema = ""
+	op, err = InvokeOperation(wf, MapHTTPRequest)
+	require.NoError(t, err)
+	assert.Empty(t, op.Parameters)
+	assert.False(t, op.RequestBody.Required)
+	assert.Equal(t, Schema{}, op.RequestBody.Content["application/json"].Schema)
+}
+
+func TestInvokeOperation_InvalidSchema(t *testing.T) {
+	_, err := InvokeOperation(&types.Workflow{
+		Metadata: types.NewObjectMetadata("wf-123"),
+		Spec:     &types.WorkflowSpec{InputSchema: "{"},
+	}, MapJSONBody)
+	assert.Error(t, err)
+}
+
+func TestDocument(t *testing.T) {
+	doc := NewDocument("test")
+	err := doc.AddWorkflow(InvokePath("wf-123"), &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf-123"),
+		Spec:     &types.WorkflowSpec{},
+	}, MapJSONBody)
+	require.NoError(t, err)
+
+	bs, err := json.Marshal(doc)
+	require.NoError(t, err)
+	parsed := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(bs, &parsed))
+	assert.Equal(t, Version, parsed["openapi"])
+	assert.Contains(t, parsed["paths"], "/workflow/wf-123/invoke")
+}
+
+func TestMatchWorkflowPath(t *testing.T) {
+	wfID, ok := matchWorkflowPath("/workflow/wf-123/invoke", invokeSuffix)
+	assert.True(t, ok)
+	assert.Equal(t, "wf-123", wfID)
+	wfID, ok = matchWorkflowPath("/workflow/wf-123/openapi.json", documentSuffix)
+	assert.True(t, ok)
+	assert.Equal(t, "wf-123", wfID)
+
+	for _, path := range []string{"/workflow/invoke", "/workflow/wf-123", "/workflow/a/b/invoke", "/invoke"} {
+		_, ok := matchWorkflowPath(path, invokeSuffix)
+		assert.False(t, ok, path)
+	}
+}
+
+func TestParseInputs(t *testing.T) {
+	inputs, err := parseInputs(httptest.NewRequest("POST", "/", strings.NewReader(`{"name": "world", "n": 2}`)))
+	require.NoError(t, err)
+	values, err := typedvalues.UnwrapMapTypedValue(inputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "world", "n": float64(2)}, values)
+
+	inputs, err = parseInputs(httptest.NewRequest("POST", "/", strings.NewReader("")))
+	assert.NoError(t, err)
+	assert.Empty(t, inputs)
+
+	_, err = parseInputs(httptest.NewRequest("POST", "/", strings.NewReader(`"world"`)))
+	assert.Error(t, err)
+}
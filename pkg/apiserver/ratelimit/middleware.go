@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// invokeMethods are the gRPC methods that count towards the invoke rate.
+var invokeMethods = map[string]bool{
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke":     true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeSync": true,
+	"/fission.workflows.apiserver.WorkflowInvocationAPI/InvokeMany": true,
+}
+
+// invokePaths are the HTTP paths of the HTTP gateway that count towards the invoke rate.
+var invokePaths = map[string]bool{
+	"/invocation":       true,
+	"/invocation/sync":  true,
+	"/invocation/batch": true,
+}
+
+// UnaryServerInterceptor rejects the unary calls of clients that exceed their rate with RESOURCE_EXHAUSTED.
+//
+// It should be chained after the authentication interceptor, so that authenticated clients are identified by their
+// identity rather than their IP address.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allowCall(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor rejects the streaming calls of clients that exceed their rate with RESOURCE_EXHAUSTED.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler) error {
+		if err := l.allowCall(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+func (l *Limiter) allowCall(ctx context.Context, method string) error {
+	var key string
+	if identity, ok := auth.FromContext(ctx); ok {
+		if identity.Internal() {
+			return nil
+		}
+		key = identity.String()
+	} else {
+		p, ok := peer.FromContext(ctx)
+		if !ok || p.Addr == nil {
+			return nil
+		}
+		// The calls that the HTTP gateway forwards have been limited by the HTTPHandler.
+		if _, ok := apiserver.ForwardedFor(ctx); ok {
+			return nil
+		}
+		key = "ip:" + apiserver.HostIP(p.Addr.String())
+	}
+	if err := l.Allow(key, method, invokeMethods[method]); err != nil {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil
+}
+
+// HTTPHandler rejects the HTTP requests of clients that exceed their rate with 429 Too Many Requests.
+//
+// The HTTP gateway maps RESOURCE_EXHAUSTED to 503 Service Unavailable, so HTTP requests are limited before they reach
+// the gateway, rather than by the gRPC interceptors.
+func (l *Limiter) HTTPHandler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + apiserver.HostIP(r.RemoteAddr)
+		if l.authenticator != nil {
+			if identity, err := l.authenticator.AuthenticateRequest(r); err == nil {
+				if identity.Internal() {
+					handler.ServeHTTP(w, r)
+					return
+				}
+				key = identity.String()
+			}
+		}
+		// GET /invocation lists the invocations, whereas GET /invocation/sync invokes a workflow.
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		invoke := invokePaths[path] && (r.Method == http.MethodPost || path == "/invocation/sync")
+		if err := l.Allow(key, "http", invoke); err != nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,140 @@
+// Package ratelimit limits the rate at which each client can call the APIs, so that a single misbehaving client
+// cannot overload the workflow engine.
+//
+// Each client has a token bucket for all API calls, and a separate token bucket for the calls that invoke
+// workflows. Clients are identified by their authenticated identity if authentication is enabled, or by their IP
+// address otherwise.
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// idleTimeout is the duration after which the buckets of clients that have not made any calls are discarded.
+	idleTimeout = 10 * time.Minute
+)
+
+var (
+	limitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "apiserver",
+		Name:      "ratelimit_rejected_total",
+		Help:      "Total number of requests rejected by the rate limiter, by method",
+	}, []string{"method"})
+
+	allowedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "apiserver",
+		Name:      "ratelimit_allowed_total",
+		Help:      "Total number of requests allowed by the rate limiter, by method",
+	}, []string{"method"})
+)
+
+func init() {
+	prometheus.MustRegister(limitedRequests, allowedRequests)
+}
+
+// Config configures the rates per client. A rate of 0 is unlimited.
+type Config struct {
+	// Rate is the maximum number of API calls per second of a client.
+	Rate float64
+
+	// Burst is the number of calls that a client can make at once, above the rate. If 0, the burst is a second's
+	// worth of calls.
+	Burst int
+
+	// InvokeRate is the maximum number of calls per second of a client that invoke workflows. The invocations also
+	// count towards the Rate.
+	InvokeRate float64
+
+	// InvokeBurst is the number of invocations that a client can make at once, above the invoke rate. If 0, the burst
+	// is a second's worth of invocations.
+	InvokeBurst int
+}
+
+// Limiter keeps the token buckets of the clients.
+type Limiter struct {
+	config        Config
+	authenticator *auth.Authenticator
+	mu            sync.Mutex
+	clients       map[string]*client
+	lastSweep     time.Time
+}
+
+type client struct {
+	calls       *rate.Limiter
+	invocations *rate.Limiter
+	lastSeen    time.Time
+}
+
+// NewLimiter creates a rate limiter. If the authenticator is not nil, authenticated HTTP clients are identified by
+// their identity instead of their IP address, consistent with the gRPC interceptors.
+func NewLimiter(config Config, authenticator *auth.Authenticator) *Limiter {
+	return &Limiter{
+		config:        config,
+		authenticator: authenticator,
+		clients:       map[string]*client{},
+		lastSweep:     time.Now(),
+	}
+}
+
+// Allow returns nil if the client can make the call to the method now, or an error describing the exceeded rate
+// otherwise. If invoke is true, the call also counts towards the invoke rate of the client.
+func (l *Limiter) Allow(key string, method string, invoke bool) error {
+	c := l.client(key)
+	if c.calls != nil && !c.calls.Allow() {
+		limitedRequests.WithLabelValues(method).Inc()
+		return fmt.Errorf("rate limit of %v calls per second exceeded", l.config.Rate)
+	}
+	if invoke && c.invocations != nil && !c.invocations.Allow() {
+		limitedRequests.WithLabelValues(method).Inc()
+		return fmt.Errorf("rate limit of %v invocations per second exceeded", l.config.InvokeRate)
+	}
+	allowedRequests.WithLabelValues(method).Inc()
+	return nil
+}
+
+func (l *Limiter) client(key string) *client {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) > idleTimeout {
+		for k, c := range l.clients {
+			if now.Sub(c.lastSeen) > idleTimeout {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	c, ok := l.clients[key]
+	if !ok {
+		c = &client{
+			calls:       newBucket(l.config.Rate, l.config.Burst),
+			invocations: newBucket(l.config.InvokeRate, l.config.InvokeBurst),
+		}
+		l.clients[key] = c
+	}
+	c.lastSeen = now
+	return c
+}
+
+// newBucket returns a token bucket, or nil if the rate is unlimited.
+func newBucket(perSecond float64, burst int) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = int(perSecond)
+		if burst < 1 {
+			burst = 1
+		}
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), burst)
+}
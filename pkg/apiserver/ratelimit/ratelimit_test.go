@@ -0,0 +1,97 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	methodInvoke = "/fission.workflows.apiserver.WorkflowInvocationAPI/Invoke"
+	methodGet    = "/fission.workflows.apiserver.WorkflowInvocationAPI/Get"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	l := NewLimiter(Config{Rate: 3, InvokeRate: 1}, nil)
+
+	assert.NoError(t, l.Allow("alice", methodInvoke, true))
+	assert.Error(t, l.Allow("alice", methodInvoke, true))
+	// Other calls are only limited by the overall rate, which the invocations count towards.
+	assert.NoError(t, l.Allow("alice", methodGet, false))
+	assert.Error(t, l.Allow("alice", methodGet, false))
+
+	// Clients have their own buckets.
+	assert.NoError(t, l.Allow("bob", methodInvoke, true))
+}
+
+func TestLimiter_AllowUnlimited(t *testing.T) {
+	l := NewLimiter(Config{}, nil)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, l.Allow("alice", methodInvoke, true))
+	}
+}
+
+func callInterceptor(ctx context.Context, l *Limiter) error {
+	_, err := l.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: methodInvoke},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	return err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	l := NewLimiter(Config{InvokeRate: 1}, nil)
+	remote := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234},
+	})
+
+	assert.NoError(t, callInterceptor(remote, l))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callInterceptor(remote, l)))
+
+	// Authenticated clients are identified by their identity.
+	alice := auth.WithIdentity(remote, auth.Identity{Subject: "alice"})
+	assert.NoError(t, callInterceptor(alice, l))
+	assert.Error(t, callInterceptor(alice, l))
+
+	// Internal clients are not limited.
+	internal := auth.WithIdentity(remote, auth.Identity{Subject: auth.InternalKeyName})
+	assert.NoError(t, callInterceptor(internal, l))
+	assert.NoError(t, callInterceptor(internal, l))
+
+	// Calls forwarded by the HTTP gateway have already been limited.
+	gateway := metadata.NewIncomingContext(peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234},
+	}), metadata.Pairs(apiserver.ForwardedForHeader, "10.0.0.1"))
+	assert.NoError(t, callInterceptor(gateway, l))
+	assert.NoError(t, callInterceptor(gateway, l))
+}
+
+func TestHTTPHandler(t *testing.T) {
+	l := NewLimiter(Config{InvokeRate: 1}, nil)
+	handler := l.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/invocation"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(http.MethodPost, "/invocation"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(http.MethodGet, "/invocation/sync"))
+	// Listing the invocations is not an invocation.
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/invocation"))
+}
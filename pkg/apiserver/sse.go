@@ -0,0 +1,53 @@
+package apiserver
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+)
+
+// MIMEEventStream is the MIME type of server-sent events.
+const MIMEEventStream = "text/event-stream"
+
+// EventStreamMarshaler formats the responses of the HTTP gateway as server-sent events. Registered for the
+// MIMEEventStream type, it allows browsers to consume the streaming endpoints - such as the watches - with an
+// EventSource. Each message of the stream is sent as the JSON data of an event.
+type EventStreamMarshaler struct {
+	runtime.JSONPb
+}
+
+func (m *EventStreamMarshaler) ContentType() string {
+	return MIMEEventStream
+}
+
+func (m *EventStreamMarshaler) Marshal(v interface{}) ([]byte, error) {
+	data, err := m.JSONPb.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// Data with newlines has to be split over multiple data fields.
+	buf := bytes.NewBuffer(nil)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(line)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// Delimiter ends the event; the last data field is already terminated by a newline.
+func (m *EventStreamMarshaler) Delimiter() []byte {
+	return []byte("\n")
+}
+
+func (m *EventStreamMarshaler) NewEncoder(w io.Writer) runtime.Encoder {
+	return runtime.EncoderFunc(func(v interface{}) error {
+		data, err := m.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, m.Delimiter()...))
+		return err
+	})
+}
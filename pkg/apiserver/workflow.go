@@ -1,17 +1,27 @@
 package apiserver
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
 	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/apiserver/auth"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/fission/fission-workflows/pkg/util/labels"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -20,20 +30,31 @@ const (
 
 // Workflow is responsible for all functionality related to managing workflows.
 type Workflow struct {
-	api     *api.Workflow
-	store   *store.Workflows
-	backend fes.Backend
+	api           *api.Workflow
+	store         *store.Workflows
+	invocationAPI *api.Invocation
+	invocations   *store.Invocations
+	backend       fes.Backend
+	authorizer    auth.Authorizer
 }
 
-func NewWorkflow(api *api.Workflow, store *store.Workflows, backend fes.Backend) *Workflow {
+// NewWorkflow creates the workflow API server. If the authorizer is nil, the callers are not authorized.
+func NewWorkflow(api *api.Workflow, store *store.Workflows, invocationAPI *api.Invocation,
+	invocations *store.Invocations, backend fes.Backend, authorizer auth.Authorizer) *Workflow {
 	return &Workflow{
-		api:     api,
-		store:   store,
-		backend: backend,
+		api:           api,
+		store:         store,
+		invocationAPI: invocationAPI,
+		invocations:   invocations,
+		backend:       backend,
+		authorizer:    authorizer,
 	}
 }
 
 func (ga *Workflow) Create(ctx context.Context, spec *types.WorkflowSpec) (*types.ObjectMetadata, error) {
+	if err := authorize(ctx, ga.authorizer, auth.PermissionCreate, spec.GetForceId(), spec); err != nil {
+		return nil, err
+	}
 	id, err := ga.api.Create(spec, api.WithContext(ctx))
 	if err != nil {
 		return nil, toErrorStatus(err)
@@ -48,9 +69,14 @@ func (ga *Workflow) CreateSync(ctx context.Context, spec *types.WorkflowSpec) (*
 	if err != nil {
 		return nil, err
 	}
+	return ga.awaitReady(ctx, metadata.GetId(), types.WorkflowVersionLatest)
+}
 
-	// Poll for completed state of the workflow.
+// awaitReady polls the workflow until the version of the workflow is ready. If the version is
+// types.WorkflowVersionLatest, the latest version of the workflow is awaited.
+func (ga *Workflow) awaitReady(ctx context.Context, workflowID string, version int64) (*types.Workflow, error) {
 	ticker := time.NewTicker(CreateSyncPollInterval)
+	defer ticker.Stop()
 	var lastWorkflowErr error
 	for {
 		select {
@@ -61,10 +87,17 @@ func (ga *Workflow) CreateSync(ctx context.Context, spec *types.WorkflowSpec) (*
 			return nil, toErrorStatus(lastWorkflowErr)
 		case <-ticker.C:
 			// Fetch the current workflow from the workflows store.
-			wf, err := ga.store.GetWorkflow(metadata.GetId())
-			if err != nil {
+			wf, err := ga.store.GetWorkflow(workflowID)
+			if err != nil || wf == nil {
 				continue
 			}
+			if version != types.WorkflowVersionLatest {
+				var ok bool
+				if wf, ok = wf.AtVersion(version); !ok {
+					// The version has not been projected yet.
+					continue
+				}
+			}
 
 			// Decide if to wait further based on the state of the workflow.
 			switch wf.GetStatus().GetStatus() {
@@ -82,6 +115,183 @@ func (ga *Workflow) CreateSync(ctx context.Context, spec *types.WorkflowSpec) (*
 	}
 }
 
+func (ga *Workflow) Update(ctx context.Context, req *WorkflowUpdateRequest) (*empty.Empty, error) {
+	wf, err := ga.store.GetWorkflow(req.GetId())
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+	if wf == nil {
+		return nil, status.Errorf(codes.NotFound, "workflow %s not found", req.GetId())
+	}
+	if wf.GetStatus().GetStatus() == types.WorkflowStatus_DELETED {
+		return nil, status.Errorf(codes.FailedPrecondition, "workflow %s was deleted", req.GetId())
+	}
+
+	spec := req.GetSpec()
+	if req.GetPatch() != nil {
+		if spec != nil {
+			return nil, status.Error(codes.InvalidArgument, "either a spec or a patch should be provided, not both")
+		}
+		spec, err = applyWorkflowPatch(wf.GetSpec(), req.GetPatch())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if spec == nil {
+		return nil, status.Error(codes.InvalidArgument, "no spec or patch provided")
+	}
+	// Both the current and the updated workflow should be within the permissions of the caller, to prevent callers
+	// from moving workflows to other namespaces.
+	if err := authorize(ctx, ga.authorizer, auth.PermissionCreate, wf.ID(), wf.GetSpec()); err != nil {
+		return nil, err
+	}
+	if err := authorize(ctx, ga.authorizer, auth.PermissionCreate, wf.ID(), spec); err != nil {
+		return nil, err
+	}
+
+	// Collect the running invocations before the update, to avoid affecting invocations of the new version.
+	var running []*types.WorkflowInvocation
+	if req.GetPolicy() != RunningInvocationPolicy_CONTINUE {
+		running, err = ga.runningInvocations(req.GetId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+	}
+
+	version, err := ga.api.Update(req.GetId(), spec, api.WithContext(ctx))
+	if err != nil {
+		return nil, toErrorStatus(err)
+	}
+
+	switch req.GetPolicy() {
+	case RunningInvocationPolicy_FAIL:
+		for _, wfi := range running {
+			err := ga.invocationAPI.Fail(wfi.ID(), fmt.Errorf("workflow %s was updated to version %d",
+				req.GetId(), version))
+			if err != nil {
+				return nil, toErrorStatus(err)
+			}
+		}
+	case RunningInvocationPolicy_MIGRATE:
+		// The running invocations can only be migrated once the new version has been parsed.
+		updated, err := ga.awaitReady(ctx, req.GetId(), version)
+		if err != nil {
+			return nil, err
+		}
+		for _, wfi := range running {
+			err := ga.invocationAPI.MigrateWorkflow(wfi.ID(), updated)
+			if err != nil {
+				return nil, toErrorStatus(err)
+			}
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+// runningInvocations returns the invocations of the workflow that have not finished yet.
+func (ga *Workflow) runningInvocations(workflowID string) ([]*types.WorkflowInvocation, error) {
+	var running []*types.WorkflowInvocation
+	for _, aggregate := range ga.invocations.List() {
+		if aggregate.Type != types.TypeInvocation {
+			continue
+		}
+		wfi, err := ga.invocations.GetInvocation(aggregate.Id)
+		if err != nil {
+			return nil, err
+		}
+		if wfi.GetSpec().GetWorkflowId() == workflowID && !invocationFinished(wfi) {
+			running = append(running, wfi)
+		}
+	}
+	return running, nil
+}
+
+// applyWorkflowPatch returns a copy of the workflow spec with the patch applied.
+func applyWorkflowPatch(spec *types.WorkflowSpec, patch *WorkflowPatch) (*types.WorkflowSpec, error) {
+	patched := proto.Clone(spec).(*types.WorkflowSpec)
+	// The patch is applied to the spec of the workflow, so the id of the workflow should not be forced again.
+	patched.ForceId = ""
+	for _, taskID := range patch.GetRemoveTasks() {
+		if _, ok := patched.Tasks[taskID]; !ok {
+			return nil, fmt.Errorf("cannot remove unknown task %s", taskID)
+		}
+		delete(patched.Tasks, taskID)
+	}
+	for taskID, taskPatch := range patch.GetTasks() {
+		if patched.Tasks == nil {
+			patched.Tasks = map[string]*types.TaskSpec{}
+		}
+		task, ok := patched.Tasks[taskID]
+		if !ok {
+			patched.Tasks[taskID] = taskPatch
+			continue
+		}
+		mergeTaskSpec(task, taskPatch)
+	}
+	if len(patch.GetOutputTask()) > 0 {
+		patched.OutputTask = patch.GetOutputTask()
+	}
+	if len(patch.GetDescription()) > 0 {
+		patched.Description = patch.GetDescription()
+	}
+	return patched, nil
+}
+
+// mergeTaskSpec merges the fields that are set in the patch into the task.
+func mergeTaskSpec(task *types.TaskSpec, patch *types.TaskSpec) {
+	if len(patch.GetFunctionRef()) > 0 {
+		task.FunctionRef = patch.GetFunctionRef()
+	}
+	for k, v := range patch.GetInputs() {
+		if task.Inputs == nil {
+			task.Inputs = map[string]*typedvalues.TypedValue{}
+		}
+		task.Inputs[k] = v
+	}
+	for k, v := range patch.GetRequires() {
+		if task.Requires == nil {
+			task.Requires = map[string]*types.TaskDependencyParameters{}
+		}
+		task.Requires[k] = v
+	}
+	if patch.GetAwait() != 0 {
+		task.Await = patch.GetAwait()
+	}
+	if patch.GetOutput() != nil {
+		task.Output = patch.GetOutput()
+	}
+	if patch.GetOutputHeaders() != nil {
+		task.OutputHeaders = patch.GetOutputHeaders()
+	}
+	if patch.GetTimeout() != nil {
+		task.Timeout = patch.GetTimeout()
+	}
+	for k, v := range patch.GetInputSchemas() {
+		if task.InputSchemas == nil {
+			task.InputSchemas = map[string]string{}
+		}
+		task.InputSchemas[k] = v
+	}
+	if len(patch.GetOutputSchema()) > 0 {
+		task.OutputSchema = patch.GetOutputSchema()
+	}
+	if patch.GetIdempotent() {
+		task.Idempotent = true
+	}
+	if patch.GetMaxRetries() != 0 {
+		task.MaxRetries = patch.GetMaxRetries()
+	}
+	if patch.GetBackoff() != nil {
+		task.Backoff = patch.GetBackoff()
+	}
+	if len(patch.GetRetryableStatusCodes()) > 0 {
+		task.RetryableStatusCodes = patch.GetRetryableStatusCodes()
+	}
+	if patch.GetWhen() != nil {
+		task.When = patch.GetWhen()
+	}
+}
+
 func (ga *Workflow) Get(ctx context.Context, workflowID *types.ObjectMetadata) (*types.Workflow, error) {
 	wf, err := ga.store.GetWorkflow(workflowID.GetId())
 	if err != nil {
@@ -91,6 +301,16 @@ func (ga *Workflow) Get(ctx context.Context, workflowID *types.ObjectMetadata) (
 }
 
 func (ga *Workflow) Delete(ctx context.Context, workflowID *types.ObjectMetadata) (*empty.Empty, error) {
+	if ga.authorizer != nil {
+		wf, err := ga.store.GetWorkflow(workflowID.GetId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		if err := authorize(ctx, ga.authorizer, auth.PermissionAdmin, workflowID.GetId(), wf.GetSpec()); err != nil {
+			return nil, err
+		}
+	}
+
 	err := ga.api.Delete(workflowID.GetId())
 	if err != nil {
 		return nil, toErrorStatus(err)
@@ -98,21 +318,98 @@ func (ga *Workflow) Delete(ctx context.Context, workflowID *types.ObjectMetadata
 	return &empty.Empty{}, nil
 }
 
-func (ga *Workflow) List(ctx context.Context, req *empty.Empty) (*WorkflowList, error) {
+func (ga *Workflow) List(ctx context.Context, query *WorkflowListQuery) (*WorkflowList, error) {
+	selector, err := labels.ParseSelector(query.GetLabelSelector())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	var results []string
 	wfs := ga.store.List()
 	for _, result := range wfs {
+		if len(query.GetLabelSelector()) > 0 {
+			wf, err := ga.store.GetWorkflow(result.Id)
+			if err != nil {
+				logrus.Errorf("List: failed to fetch %v from workflows: %v", result, err)
+				continue
+			}
+			if !selector.Matches(labels.Set(wf.GetMetadata().GetLabels())) {
+				continue
+			}
+		}
 		results = append(results, result.Id)
 	}
 	return &WorkflowList{Workflows: results}, nil
 }
 
-func (ga *Workflow) Validate(ctx context.Context, spec *types.WorkflowSpec) (*empty.Empty, error) {
-	err := validate.WorkflowSpec(spec)
-	if err != nil {
-		return nil, toErrorStatus(err)
+func (ga *Workflow) Watch(query *WorkflowWatchQuery, stream WorkflowAPI_WatchServer) error {
+	// Subscribe before sending the current state, to avoid missing updates in between.
+	sub := ga.store.GetWorkflowUpdates()
+	if sub == nil {
+		return status.Error(codes.Unimplemented, "workflow store does not support watches")
 	}
-	return &empty.Empty{}, nil
+	defer sub.Close()
+
+	for _, id := range query.GetIds() {
+		wf, err := ga.store.GetWorkflow(id)
+		if err != nil {
+			return toErrorStatus(err)
+		}
+		if wf == nil {
+			return status.Errorf(codes.NotFound, "workflow %s not found", id)
+		}
+		if err := stream.Send(&WorkflowUpdate{Workflow: wf}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case msg, ok := <-sub.Ch:
+			if !ok {
+				return status.Error(codes.Unavailable, "watch of workflows was closed")
+			}
+			notification, err := sub.ToNotification(msg)
+			if err != nil {
+				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
+				continue
+			}
+			wf, err := store.ParseNotificationToWorkflow(notification)
+			if err != nil || (len(query.GetIds()) > 0 && !contains(query.GetIds(), wf.ID())) {
+				continue
+			}
+			err = stream.Send(&WorkflowUpdate{
+				Event:    notification.Event.GetType(),
+				Workflow: wf,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (ga *Workflow) Validate(ctx context.Context, spec *types.WorkflowSpec) (*WorkflowValidationResult, error) {
+	return NewWorkflowValidationResult(ga.api.Validate(spec)), nil
+}
+
+// NewWorkflowValidationResult converts the result of validating a workflow spec into a WorkflowValidationResult,
+// with a diagnostic for each of the issues in the validation error.
+func NewWorkflowValidationResult(err error) *WorkflowValidationResult {
+	result := &WorkflowValidationResult{
+		Valid: err == nil,
+	}
+	for _, d := range validate.Diagnostics(err) {
+		result.Diagnostics = append(result.Diagnostics, &ValidationDiagnostic{
+			Reason:  d.Reason.Error(),
+			Message: d.Error(),
+			TaskId:  d.TaskID,
+			Field:   d.Field,
+		})
+	}
+	return result
 }
 
 func (ga *Workflow) Events(ctx context.Context, md *types.ObjectMetadata) (*ObjectEvents, error) {
@@ -125,3 +422,37 @@ func (ga *Workflow) Events(ctx context.Context, md *types.ObjectMetadata) (*Obje
 		Events:   events,
 	}, nil
 }
+
+func (ga *Workflow) Visualize(ctx context.Context, req *VisualizeRequest) (*Visualization, error) {
+	var v *graph.Visualization
+	if len(req.GetInvocationId()) > 0 {
+		wi, err := ga.invocations.GetInvocation(req.GetInvocationId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		if len(req.GetWorkflowId()) > 0 && wi.GetSpec().GetWorkflowId() != req.GetWorkflowId() {
+			return nil, status.Errorf(codes.InvalidArgument, "invocation %s is not an invocation of workflow %s",
+				wi.ID(), req.GetWorkflowId())
+		}
+		v = graph.NewInvocationVisualization(wi)
+	} else {
+		wf, err := ga.store.GetWorkflow(req.GetWorkflowId())
+		if err != nil {
+			return nil, toErrorStatus(err)
+		}
+		v = graph.NewWorkflowVisualization(wf)
+	}
+
+	format := req.GetFormat()
+	if len(format) == 0 {
+		format = graph.FormatDOT
+	}
+	buf := &bytes.Buffer{}
+	if err := v.Render(buf, format); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &Visualization{
+		Format: format,
+		Graph:  buf.String(),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package apiserver
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMergeTaskSpec_AllFields ensures that each field of the TaskSpec is merged, so that fields that are added to the
+// TaskSpec are not silently dropped from patches.
+func TestMergeTaskSpec_AllFields(t *testing.T) {
+	specType := reflect.TypeOf(types.TaskSpec{})
+	for i := 0; i < specType.NumField(); i++ {
+		field := specType.Field(i)
+		if strings.HasPrefix(field.Name, "XXX_") {
+			continue
+		}
+		patch := &types.TaskSpec{}
+		value := reflect.ValueOf(patch).Elem().Field(i)
+		if !setSample(value) {
+			t.Fatalf("unsupported type %v of TaskSpec field %s; extend mergeTaskSpec and this test", field.Type,
+				field.Name)
+		}
+
+		task := &types.TaskSpec{}
+		mergeTaskSpec(task, patch)
+		assert.Equal(t, value.Interface(), reflect.ValueOf(task).Elem().Field(i).Interface(),
+			"TaskSpec field %s is not merged", field.Name)
+	}
+}
+
+// setSample sets the value to a non-zero sample value, returning false if the type of the value is not supported.
+func setSample(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString("patched")
+	case reflect.Int32, reflect.Int64:
+		value.SetInt(7)
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Ptr:
+		value.Set(reflect.New(value.Type().Elem()))
+	case reflect.Slice:
+		item := reflect.New(value.Type().Elem()).Elem()
+		if !setSample(item) {
+			return false
+		}
+		value.Set(reflect.Append(reflect.MakeSlice(value.Type(), 0, 1), item))
+	case reflect.Map:
+		key := reflect.New(value.Type().Key()).Elem()
+		item := reflect.New(value.Type().Elem()).Elem()
+		if !setSample(key) || !setSample(item) {
+			return false
+		}
+		value.Set(reflect.MakeMap(value.Type()))
+		value.SetMapIndex(key, item)
+	default:
+		return false
+	}
+	return true
+}
+
+func TestMergeTaskSpec_MergesMapsByKey(t *testing.T) {
+	task := types.NewTaskSpec("a").
+		Input("keep", typedvalues.MustWrap(1)).
+		Input("replace", typedvalues.MustWrap(2))
+	patch := &types.TaskSpec{}
+	patch.Input("replace", typedvalues.MustWrap(3)).
+		Input("add", typedvalues.MustWrap(4))
+
+	mergeTaskSpec(task, patch)
+	inputs, err := typedvalues.UnwrapMapTypedValue(task.Inputs)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", task.FunctionRef)
+	assert.Equal(t, map[string]interface{}{"keep": int32(1), "replace": int32(3), "add": int32(4)}, inputs)
+}
@@ -0,0 +1,142 @@
+// package blobstore provides storage for large values outside of the event store.
+//
+// Large values, such as the outputs of tasks that process files, bloat the event log and the caches of the workflow
+// engine. The Offloader moves these values to a blob store, replacing them with a (small) reference TypedValue. The
+// references are dereferenced transparently when they are unwrapped, by registering the Offloader as the
+// typedvalues.Dereferencer.
+package blobstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DefaultThreshold = 256 * 1024 // 256 KiB
+	keyPrefix        = "values/"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+
+	offloadedValues = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "blobstore",
+		Name:      "offloaded_values_total",
+		Help:      "Count of values that were offloaded to the blob store.",
+	})
+
+	offloadedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "blobstore",
+		Name:      "offloaded_bytes_total",
+		Help:      "Total size of the values that were offloaded to the blob store.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(offloadedValues, offloadedBytes)
+}
+
+// Store is a (remote) store for blobs.
+type Store interface {
+	// Put stores the data under the key, and returns the URI that can be used to retrieve the data.
+	Put(key string, data []byte) (uri string, err error)
+
+	// Get retrieves the data stored at the URI. If no data is stored at the URI, ErrNotFound is returned.
+	Get(uri string) ([]byte, error)
+}
+
+// StreamStore is a blob store that supports storing and retrieving data without buffering it in memory.
+type StreamStore interface {
+	Store
+
+	// PutStream stores the data of the reader under the key. The size is the number of bytes that will be read from
+	// the reader, or -1 if unknown.
+	PutStream(key string, r io.Reader, size int64) (uri string, err error)
+
+	// GetStream returns a reader of the data stored at the URI. If no data is stored at the URI, ErrNotFound is
+	// returned.
+	GetStream(uri string) (io.ReadCloser, error)
+}
+
+// Offloader stores values that exceed the threshold in the blob store, and dereferences the resulting references.
+type Offloader struct {
+	store     Store
+	threshold int
+}
+
+func NewOffloader(store Store, threshold int) *Offloader {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Offloader{
+		store:     store,
+		threshold: threshold,
+	}
+}
+
+// Offload stores the value in the blob store if its size exceeds the threshold, and returns a reference to it.
+// Otherwise, the value is returned as is. The metadata of the value is preserved in the reference.
+func (o *Offloader) Offload(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	if tv == nil || typedvalues.IsReference(tv) {
+		return tv, nil
+	}
+	if proto.Size(tv) <= o.threshold {
+		return tv, nil
+	}
+	return o.Put(tv)
+}
+
+// Put stores the value in the blob store regardless of its size, and returns a reference to it. The metadata of the
+// value is preserved in the reference.
+func (o *Offloader) Put(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	if tv == nil || typedvalues.IsReference(tv) {
+		return tv, nil
+	}
+	size := proto.Size(tv)
+
+	// The metadata is kept in the reference, so only the value itself needs to be stored.
+	data, err := proto.Marshal(&typedvalues.TypedValue{Value: tv.GetValue()})
+	if err != nil {
+		return nil, err
+	}
+	// Values are content-addressed, which deduplicates identical values (such as an output that is passed as-is).
+	hash := sha256.Sum256(data)
+	uri, err := o.store.Put(keyPrefix+hex.EncodeToString(hash[:]), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to offload value: %v", err)
+	}
+	ref, err := typedvalues.Wrap(&typedvalues.Reference{
+		Uri:       uri,
+		ValueType: tv.ValueType(),
+		Size:      int64(size),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tv.GetMetadata() {
+		ref.SetMetadata(k, v)
+	}
+	offloadedValues.Inc()
+	offloadedBytes.Add(float64(size))
+	return ref, nil
+}
+
+// Dereference retrieves the value that the reference points to from the blob store.
+func (o *Offloader) Dereference(ref *typedvalues.Reference) (*typedvalues.TypedValue, error) {
+	data, err := o.store.Get(ref.GetUri())
+	if err != nil {
+		return nil, err
+	}
+	tv := &typedvalues.TypedValue{}
+	if err := proto.Unmarshal(data, tv); err != nil {
+		return nil, fmt.Errorf("failed to parse referenced value: %v", err)
+	}
+	return tv, nil
+}
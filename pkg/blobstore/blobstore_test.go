@@ -0,0 +1,86 @@
+package blobstore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapStore map[string][]byte
+
+func (s mapStore) Put(key string, data []byte) (string, error) {
+	s[key] = data
+	return "test://" + key, nil
+}
+
+func (s mapStore) Get(uri string) ([]byte, error) {
+	data, ok := s[strings.TrimPrefix(uri, "test://")]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+func TestOffloaderBelowThreshold(t *testing.T) {
+	store := mapStore{}
+	offloader := NewOffloader(store, 1024)
+
+	tv := typedvalues.MustWrap("small value")
+	result, err := offloader.Offload(tv)
+	assert.NoError(t, err)
+	assert.Equal(t, tv, result)
+	assert.Empty(t, store)
+}
+
+func TestOffloaderRoundTrip(t *testing.T) {
+	store := mapStore{}
+	offloader := NewOffloader(store, 1024)
+
+	tv := typedvalues.MustWrap(strings.Repeat("a", 2048))
+	tv.SetMetadata("foo", "bar")
+	ref, err := offloader.Offload(tv)
+	assert.NoError(t, err)
+	assert.True(t, typedvalues.IsReference(ref))
+	assert.Len(t, store, 1)
+	assert.Equal(t, "bar", ref.GetMetadata()["foo"])
+
+	r, err := typedvalues.UnwrapReference(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, typedvalues.TypeString, r.GetValueType())
+
+	dereferenced, err := offloader.Dereference(r)
+	assert.NoError(t, err)
+	s, err := typedvalues.UnwrapString(dereferenced)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 2048), s)
+
+	// Offloading an identical value should not result in a new blob
+	_, err = offloader.Offload(typedvalues.MustWrap(strings.Repeat("a", 2048)))
+	assert.NoError(t, err)
+	assert.Len(t, store, 1)
+}
+
+func TestOffloaderTransparentUnwrap(t *testing.T) {
+	offloader := NewOffloader(mapStore{}, 1024)
+	typedvalues.RegisterDereferencer(offloader)
+	defer typedvalues.RegisterDereferencer(nil)
+
+	value := map[string]interface{}{
+		"data": strings.Repeat("a", 2048),
+	}
+	ref, err := offloader.Offload(typedvalues.MustWrap(value))
+	assert.NoError(t, err)
+	assert.True(t, typedvalues.IsReference(ref))
+
+	i, err := typedvalues.Unwrap(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, value, i)
+}
+
+func TestOffloaderMissingBlob(t *testing.T) {
+	offloader := NewOffloader(mapStore{}, 1024)
+	_, err := offloader.Dereference(&typedvalues.Reference{Uri: "test://missing"})
+	assert.Equal(t, ErrNotFound, err)
+}
@@ -0,0 +1,114 @@
+// package file contains an implementation of the blob store on the local file system.
+//
+// This implementation allows large values and streams to be spilled to disk instead of being kept in memory. As the
+// blobs are only available to processes that share the directory, it cannot be used when the workflow engine is
+// distributed over multiple nodes, unless the directory is on a shared volume.
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/blobstore"
+)
+
+const Scheme = "file://"
+
+type Store struct {
+	dir string
+}
+
+// NewStore creates a blob store that stores the blobs in the directory. The directory is created if it does not exist
+// yet.
+func NewStore(dir string) (*Store, error) {
+	if len(dir) == 0 {
+		return nil, fmt.Errorf("no directory specified")
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create directory '%s': %v", dir, err)
+	}
+	return &Store{
+		dir: dir,
+	}, nil
+}
+
+func (s *Store) Put(key string, data []byte) (string, error) {
+	return s.PutStream(key, bytes.NewReader(data), int64(len(data)))
+}
+
+func (s *Store) Get(uri string) ([]byte, error) {
+	rc, err := s.GetStream(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+// PutStream writes the data to a temporary file first, which is moved to the path of the key once all data has been
+// written. This ensures that readers never observe partially written blobs.
+func (s *Store) PutStream(key string, r io.Reader, size int64) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return Scheme + path, nil
+}
+
+func (s *Store) GetStream(uri string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(uri, Scheme) {
+		return nil, fmt.Errorf("unsupported uri '%s'", uri)
+	}
+	path := strings.TrimPrefix(uri, Scheme)
+	key, err := filepath.Rel(s.dir, path)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported uri '%s': %v", uri, err)
+	}
+	if path, err = s.path(key); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, blobstore.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// path returns the path of the blob of the key, ensuring that the path is located within the directory of the store.
+func (s *Store) path(key string) (string, error) {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid key '%s'", key)
+	}
+	return path, nil
+}
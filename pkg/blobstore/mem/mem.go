@@ -0,0 +1,65 @@
+// package mem contains an in-memory implementation of the blob store.
+//
+// This implementation is intended for development and test purposes. As the blobs are only available within the
+// process, it cannot be used when the workflow engine is distributed over multiple processes.
+package mem
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/blobstore"
+)
+
+const Scheme = "mem://"
+
+type Store struct {
+	blobs map[string][]byte
+	mu    sync.RWMutex
+}
+
+func NewStore() *Store {
+	return &Store{
+		blobs: map[string][]byte{},
+	}
+}
+
+func (s *Store) Put(key string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = data
+	return Scheme + key, nil
+}
+
+func (s *Store) Get(uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, Scheme) {
+		return nil, fmt.Errorf("unsupported uri '%s'", uri)
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, ok := s.blobs[strings.TrimPrefix(uri, Scheme)]
+	if !ok {
+		return nil, blobstore.ErrNotFound
+	}
+	return data, nil
+}
+
+func (s *Store) PutStream(key string, r io.Reader, size int64) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return s.Put(key, data)
+}
+
+func (s *Store) GetStream(uri string) (io.ReadCloser, error) {
+	data, err := s.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
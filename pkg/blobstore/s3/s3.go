@@ -0,0 +1,114 @@
+// package s3 contains an implementation of the blob store for S3-compatible object stores, such as AWS S3, Minio,
+// and Google Cloud Storage (using its S3-interoperable XML API).
+package s3
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/blobstore"
+	"github.com/minio/minio-go"
+)
+
+const (
+	Scheme            = "s3://"
+	DefaultEndpoint   = "s3.amazonaws.com"
+	GCSEndpoint       = "storage.googleapis.com"
+	contentType       = "application/protobuf"
+	streamContentType = "application/octet-stream"
+	errCodeNoSuchKey  = "NoSuchKey"
+)
+
+type Config struct {
+	Endpoint  string // e.g. s3.amazonaws.com or minio.default:9000
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	Insecure  bool // Disables TLS
+}
+
+type Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewStore creates a blob store backed by the bucket. The bucket is created if it does not exist yet.
+func NewStore(cfg Config) (*Store, error) {
+	if len(cfg.Bucket) == 0 {
+		return nil, fmt.Errorf("no bucket specified")
+	}
+	if len(cfg.Endpoint) == 0 {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	client, err := minio.NewWithRegion(cfg.Endpoint, cfg.AccessKey, cfg.SecretKey, !cfg.Insecure, cfg.Region)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := client.BucketExists(cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket '%s': %v", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(cfg.Bucket, cfg.Region); err != nil {
+			return nil, fmt.Errorf("failed to create bucket '%s': %v", cfg.Bucket, err)
+		}
+	}
+	return &Store{
+		client: client,
+		bucket: cfg.Bucket,
+	}, nil
+}
+
+func (s *Store) Put(key string, data []byte) (string, error) {
+	_, err := s.client.PutObject(s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return "", err
+	}
+	return Scheme + s.bucket + "/" + key, nil
+}
+
+func (s *Store) Get(uri string) ([]byte, error) {
+	obj, err := s.GetStream(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return ioutil.ReadAll(obj)
+}
+
+func (s *Store) PutStream(key string, r io.Reader, size int64) (string, error) {
+	// If the size is unknown (-1), the object is uploaded in parts.
+	_, err := s.client.PutObject(s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: streamContentType,
+	})
+	if err != nil {
+		return "", err
+	}
+	return Scheme + s.bucket + "/" + key, nil
+}
+
+func (s *Store) GetStream(uri string) (io.ReadCloser, error) {
+	bucketPrefix := Scheme + s.bucket + "/"
+	if !strings.HasPrefix(uri, bucketPrefix) {
+		return nil, fmt.Errorf("unsupported uri '%s'", uri)
+	}
+	obj, err := s.client.GetObject(s.bucket, strings.TrimPrefix(uri, bucketPrefix), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	// The object is fetched lazily; stat it to detect missing objects before the data is read.
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == errCodeNoSuchKey {
+			return nil, blobstore.ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,65 @@
+package blobstore
+
+import (
+	"io"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const streamKeyPrefix = "streams/"
+
+var streamedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "blobstore",
+	Name:      "streamed_bytes_total",
+	Help:      "Total size of the streams that were written to the blob store.",
+})
+
+func init() {
+	prometheus.MustRegister(streamedBytes)
+}
+
+// Streamer stores the data of streams in the blob store. It implements typedvalues.StreamStore.
+type Streamer struct {
+	store StreamStore
+}
+
+func NewStreamer(store StreamStore) *Streamer {
+	return &Streamer{
+		store: store,
+	}
+}
+
+// Create writes the data of the reader to a new blob in the blob store. As the data is not buffered, it cannot be
+// content-addressed; each stream is stored under a unique key.
+func (s *Streamer) Create(r io.Reader, contentType string, size int64) (*typedvalues.Stream, error) {
+	cr := &countingReader{r: r}
+	uri, err := s.store.PutStream(streamKeyPrefix+util.UID(), cr, size)
+	if err != nil {
+		return nil, err
+	}
+	streamedBytes.Add(float64(cr.n))
+	return &typedvalues.Stream{
+		Uri:         uri,
+		ContentType: contentType,
+		Size:        cr.n,
+	}, nil
+}
+
+// Open returns a reader of the blob of the stream.
+func (s *Streamer) Open(stream *typedvalues.Stream) (io.ReadCloser, error) {
+	return s.store.GetStream(stream.GetUri())
+}
+
+// countingReader counts the number of bytes that have been read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
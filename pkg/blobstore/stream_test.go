@@ -0,0 +1,46 @@
+package blobstore
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (s mapStore) PutStream(key string, r io.Reader, size int64) (string, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return s.Put(key, data)
+}
+
+func (s mapStore) GetStream(uri string) (io.ReadCloser, error) {
+	data, err := s.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestStreamerRoundTrip(t *testing.T) {
+	store := mapStore{}
+	streamer := NewStreamer(store)
+
+	data := strings.Repeat("a", 4096)
+	stream, err := streamer.Create(strings.NewReader(data), "application/octet-stream", -1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(data), stream.GetSize())
+	assert.Equal(t, "application/octet-stream", stream.GetContentType())
+	assert.Len(t, store, 1)
+
+	rc, err := streamer.Open(stream)
+	assert.NoError(t, err)
+	defer rc.Close()
+	result, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, data, string(result))
+}
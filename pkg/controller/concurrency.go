@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/api/store"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/controller/executor"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// applyConcurrencyPolicy applies the concurrency policy of the workflow to the invocation, which has not started yet.
+// It returns false, along with the result of the evaluation, if the invocation should not be started (yet).
+//
+// Only the running invocations of the workflow that were created before the invocation are considered, so that of
+// concurrent invocations, the oldest invocation is run first. The ancestors of the invocation, such as the invocation
+// of a workflow that invokes itself, are not considered, as they wait for the invocation to finish.
+func (c *InvocationController) applyConcurrencyPolicy(invocation *types.WorkflowInvocation) (ctrl.Result, bool) {
+	policy := invocation.Workflow().GetSpec().GetConcurrencyPolicy()
+	if policy == types.WorkflowSpec_ALLOW || c.invocations == nil || c.running == nil {
+		return nil, true
+	}
+	c.running.Update(invocation)
+
+	// A queued invocation only needs to know whether it waits for any invocation.
+	max := 0
+	if policy == types.WorkflowSpec_QUEUE {
+		max = 1
+	}
+	running, err := runningBefore(c.invocations, c.running, invocation, max)
+	if err != nil {
+		return ctrl.Err{Err: fmt.Errorf("failed to list the running invocations of the workflow: %v", err)}, false
+	}
+	if len(running) == 0 {
+		return nil, true
+	}
+
+	ids := make([]string, len(running))
+	for i, wfi := range running {
+		ids[i] = wfi.ID()
+	}
+	switch policy {
+	case types.WorkflowSpec_FORBID:
+		err := fmt.Errorf("workflow %s already has running invocations: %s", invocation.Workflow().ID(),
+			strings.Join(ids, ", "))
+		c.fail(invocation, err)
+		return ctrl.Err{Err: err}, false
+	case types.WorkflowSpec_REPLACE:
+		// The invocation is started once the replaced invocations have been canceled.
+		for _, id := range ids {
+			id := id
+			c.executor.Submit(&executor.Task{
+				TaskID:   fmt.Sprintf("%s.replace.%s", invocation.ID(), id),
+				GroupID:  invocation.ID(),
+				Priority: executor.PriorityHigh,
+				Retry:    &eventRetryPolicy,
+				Apply: func() error {
+					return c.invocationAPI.CancelCascade(id, fmt.Sprintf("replaced by invocation %s", invocation.ID()),
+						c.invocations)
+				},
+			})
+		}
+		return ctrl.Success{Msg: fmt.Sprintf("replacing running invocations: %s", strings.Join(ids, ", "))}, false
+	default:
+		return ctrl.Success{Msg: fmt.Sprintf("queued behind running invocation %s", ids[0])}, false
+	}
+}
+
+// started returns true if the invocation has been started, which is the case once it has tasks or pending intents.
+func started(invocation *types.WorkflowInvocation) bool {
+	return len(invocation.GetStatus().GetTasks()) > 0 || len(invocation.GetStatus().GetIntents()) > 0
+}
+
+// runningBefore returns the invocations of the workflow of the invocation that have not finished, and that were created
+// before the invocation, excluding its ancestors. The invocations are ordered by their creation. If max is positive, at
+// most max invocations are returned.
+//
+// The invocations are looked up in the index of running invocations, and checked against the store, so that
+// invocations that have finished without the index being notified are removed from the index.
+func runningBefore(invocations *store.Invocations, index *RunningInvocations, invocation *types.WorkflowInvocation,
+	max int) ([]*types.WorkflowInvocation, error) {
+	ancestors := map[string]bool{}
+	for id := parentOf(invocation); len(id) > 0 && !ancestors[id]; {
+		ancestors[id] = true
+		parent, err := invocations.GetInvocation(id)
+		if err != nil {
+			break
+		}
+		id = parentOf(parent)
+	}
+
+	for {
+		var running []*types.WorkflowInvocation
+		stale := false
+		for _, candidate := range index.before(invocation, ancestors, max) {
+			wfi, err := invocations.GetInvocation(candidate.ID())
+			if err != nil {
+				return nil, err
+			}
+			if wfi.GetStatus().Finished() {
+				index.Update(wfi)
+				stale = true
+				continue
+			}
+			running = append(running, wfi)
+		}
+		if !stale {
+			return running, nil
+		}
+	}
+}
+
+// parentOf returns the id of the invocation that started the invocation, if any.
+func parentOf(invocation *types.WorkflowInvocation) string {
+	if parentID := invocation.GetSpec().GetParentId(); len(parentID) > 0 {
+		return parentID
+	}
+	return invocation.GetSpec().GetCallerId()
+}
+
+// createdBefore returns true if invocation a was created before invocation b. Invocations that were created at the
+// same time are ordered by their id.
+func createdBefore(a, b *types.WorkflowInvocation) bool {
+	createdA, errA := ptypes.Timestamp(a.GetMetadata().GetCreatedAt())
+	createdB, errB := ptypes.Timestamp(b.GetMetadata().GetCreatedAt())
+	if errA != nil || errB != nil || createdA.Equal(createdB) {
+		return a.ID() < b.ID()
+	}
+	return createdA.Before(createdB)
+}
+
+// RunningInvocations indexes the invocations that have not finished by their workflow, ordered by their creation, so
+// that the concurrency policies of workflows are applied without listing all invocations. It is updated from the
+// notifications of the invocations store.
+type RunningInvocations struct {
+	mu     sync.Mutex
+	queues map[string][]*types.WorkflowInvocation
+}
+
+func NewRunningInvocations() *RunningInvocations {
+	return &RunningInvocations{
+		queues: map[string][]*types.WorkflowInvocation{},
+	}
+}
+
+// Load indexes the invocations in the store that have not finished, such as the invocations that were started before
+// a restart, which are not notified.
+func (r *RunningInvocations) Load(invocations *store.Invocations) {
+	for _, aggregate := range invocations.List() {
+		if aggregate.Type != types.TypeInvocation {
+			continue
+		}
+		if wfi, err := invocations.GetInvocation(aggregate.Id); err == nil && wfi != nil {
+			r.Update(wfi)
+		}
+	}
+}
+
+// Update adds or replaces the invocation in the index, or removes it once it has finished.
+func (r *RunningInvocations) Update(invocation *types.WorkflowInvocation) {
+	wfID := invocation.Workflow().ID()
+	if len(wfID) == 0 || len(invocation.ID()) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := r.queues[wfID]
+	i := sort.Search(len(queue), func(i int) bool {
+		return !createdBefore(queue[i], invocation)
+	})
+	indexed := i < len(queue) && queue[i].ID() == invocation.ID()
+	switch {
+	case invocation.GetStatus().Finished():
+		if indexed {
+			queue = append(queue[:i], queue[i+1:]...)
+		}
+	case indexed:
+		queue[i] = invocation
+	default:
+		queue = append(queue, nil)
+		copy(queue[i+1:], queue[i:])
+		queue[i] = invocation
+	}
+	if len(queue) == 0 {
+		delete(r.queues, wfID)
+	} else {
+		r.queues[wfID] = queue
+	}
+}
+
+// before returns the indexed invocations of the workflow of the invocation that were created before the invocation,
+// excluding the invocations to skip. If max is positive, at most max invocations are returned.
+func (r *RunningInvocations) before(invocation *types.WorkflowInvocation, skip map[string]bool,
+	max int) []*types.WorkflowInvocation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []*types.WorkflowInvocation
+	for _, wfi := range r.queues[invocation.Workflow().ID()] {
+		if !createdBefore(wfi, invocation) || (max > 0 && len(result) >= max) {
+			break
+		}
+		if wfi.ID() != invocation.ID() && !skip[wfi.ID()] {
+			result = append(result, wfi)
+		}
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunningInvocations(t *testing.T) {
+	now := time.Now()
+	newInvocation := func(wfID string, id string, createdAt time.Time) *types.WorkflowInvocation {
+		wfi := types.NewWorkflowInvocation(wfID, id, now.Add(time.Minute))
+		wfi.Metadata.CreatedAt, _ = ptypes.TimestampProto(createdAt)
+		wfi.Spec.Workflow = &types.Workflow{Metadata: types.NewObjectMetadata(wfID)}
+		wfi.Status.Status = types.WorkflowInvocationStatus_IN_PROGRESS
+		return wfi
+	}
+	ids := func(invocations []*types.WorkflowInvocation) []string {
+		var result []string
+		for _, wfi := range invocations {
+			result = append(result, wfi.ID())
+		}
+		return result
+	}
+
+	index := NewRunningInvocations()
+	first := newInvocation("wf-1", "wi-1", now)
+	second := newInvocation("wf-1", "wi-2", now.Add(time.Second))
+	third := newInvocation("wf-1", "wi-3", now.Add(2*time.Second))
+	other := newInvocation("wf-2", "wi-4", now)
+	for _, wfi := range []*types.WorkflowInvocation{third, other, first, second, second} {
+		index.Update(wfi)
+	}
+	assert.Equal(t, []string{"wi-1", "wi-2"}, ids(index.before(third, nil, 0)))
+	assert.Equal(t, []string{"wi-1"}, ids(index.before(third, nil, 1)))
+	assert.Equal(t, []string{"wi-2"}, ids(index.before(third, map[string]bool{"wi-1": true}, 0)))
+	assert.Empty(t, index.before(first, nil, 0))
+	assert.Empty(t, index.before(other, nil, 0))
+
+	// Finished invocations are removed from the index.
+	first = first.Copy()
+	first.Status.Status = types.WorkflowInvocationStatus_SUCCEEDED
+	index.Update(first)
+	assert.Equal(t, []string{"wi-2"}, ids(index.before(third, nil, 0)))
+}
@@ -2,16 +2,62 @@ package ctrl
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/util/workqueue"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
+var (
+	evalDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "workflows",
+		Subsystem: "ctrl",
+		Name:      "eval_duration_seconds",
+		Help:      "Duration of the evaluations of the controllers by the control system, by result",
+		Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 16),
+	}, []string{"system", "result"})
+
+	evalQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "ctrl",
+		Name:      "eval_queue_length",
+		Help:      "Number of events queued for evaluation in the control system",
+	}, []string{"system"})
+
+	eventsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "ctrl",
+		Name:      "events_dropped_total",
+		Help:      "Number of events that were not queued for evaluation, because the queue was full or shut down",
+	}, []string{"system"})
+
+	quarantinedControllers = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "ctrl",
+		Name:      "quarantined_controllers",
+		Help:      "Number of controllers that are quarantined, because their evaluations failed repeatedly",
+	}, []string{"system"})
+)
+
+func init() {
+	prometheus.MustRegister(evalDuration, evalQueueLength, eventsDropped, quarantinedControllers)
+}
+
+// DefaultQuarantineThreshold is the default number of consecutive failed evaluations after which a controller is
+// quarantined.
+const DefaultQuarantineThreshold = 20
+
+// QuarantineThreshold is the number of consecutive failed evaluations (errors or panics) of a controller, after which
+// the control system quarantines its key. If 0, controllers are never quarantined.
+var QuarantineThreshold = DefaultQuarantineThreshold
+
 // Future: decouple from fes.
 type Event = fes.Notification
 
@@ -23,11 +69,35 @@ type Identifier interface {
 	ID() string
 }
 
+// Sensor submits events to the evaluation queue of a control system. Close stops the sensor, and returns once it has
+// stopped submitting events.
 type Sensor interface {
 	io.Closer
 	Start(evalQueue EvalQueue) error
 }
 
+// StartSensors starts the sensors. If a sensor fails to start, the sensors that were already started are closed.
+func StartSensors(sensors []Sensor, evalQueue EvalQueue) error {
+	for i, sensor := range sensors {
+		if err := sensor.Start(evalQueue); err != nil {
+			CloseSensors(sensors[:i])
+			return fmt.Errorf("failed to start sensor %T: %v", sensor, err)
+		}
+	}
+	return nil
+}
+
+// CloseSensors closes all sensors, and returns the last error that occurred.
+func CloseSensors(sensors []Sensor) error {
+	var err error
+	for _, sensor := range sensors {
+		if serr := sensor.Close(); serr != nil {
+			err = serr
+		}
+	}
+	return err
+}
+
 type EvalQueue interface {
 	Submit(event *Event) bool
 }
@@ -87,8 +157,18 @@ func (c ControllerStats) RecordEval() ControllerStats {
 	return c
 }
 
+// Quarantined describes a controller key that is quarantined. The events of a quarantined key are not evaluated, until
+// it is released.
+type Quarantined struct {
+	Key       string
+	Since     time.Time
+	Failures  int
+	LastError string
+}
+
 // Future: support parallel executions in evaluator
 type System struct {
+	name        string
 	ctrls       map[string]Controller
 	ctrlsMu     *sync.RWMutex
 	ctrlStats   map[string]ControllerStats
@@ -97,19 +177,32 @@ type System struct {
 	evalQueue   workqueue.Interface
 	close       func()
 	runOnce     *sync.Once
+	running     *sync.WaitGroup
 	logger      *log.Logger
+
+	// failures counts the consecutive failed evaluations per key; quarantined contains the keys that failed too often.
+	failures     map[string]int
+	quarantined  map[string]Quarantined
+	quarantineMu *sync.RWMutex
 }
 
-func NewSystem(factory ControllerFactory) *System {
+// NewSystem creates a control system, which evaluates the controllers created by the factory. The name identifies the
+// system in the metrics.
+func NewSystem(name string, factory ControllerFactory) *System {
 	return &System{
-		factory:     factory,
-		ctrlsMu:     &sync.RWMutex{},
-		ctrls:       make(map[string]Controller),
-		evalQueue:   workqueue.NewWorkQueue(workqueue.DefaultMaxSize, true),
-		runOnce:     &sync.Once{},
-		logger:      log.StandardLogger(),
-		ctrlStats:   make(map[string]ControllerStats),
-		ctrlStatsMu: &sync.RWMutex{},
+		name:         name,
+		factory:      factory,
+		ctrlsMu:      &sync.RWMutex{},
+		ctrls:        make(map[string]Controller),
+		evalQueue:    workqueue.NewWorkQueue(workqueue.DefaultMaxSize, true),
+		runOnce:      &sync.Once{},
+		running:      &sync.WaitGroup{},
+		logger:       log.StandardLogger(),
+		ctrlStats:    make(map[string]ControllerStats),
+		ctrlStatsMu:  &sync.RWMutex{},
+		failures:     make(map[string]int),
+		quarantined:  make(map[string]Quarantined),
+		quarantineMu: &sync.RWMutex{},
 	}
 }
 
@@ -142,6 +235,77 @@ func (s *System) RangeControllerStats(consumer func(k string, v ControllerStats)
 	}
 }
 
+// IsQuarantined returns whether the key is quarantined.
+func (s *System) IsQuarantined(key string) bool {
+	s.quarantineMu.RLock()
+	_, ok := s.quarantined[key]
+	s.quarantineMu.RUnlock()
+	return ok
+}
+
+// Quarantined returns the quarantined keys, sorted by key.
+func (s *System) Quarantined() []Quarantined {
+	s.quarantineMu.RLock()
+	result := make([]Quarantined, 0, len(s.quarantined))
+	for _, q := range s.quarantined {
+		result = append(result, q)
+	}
+	s.quarantineMu.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+// Release releases the key from the quarantine, after which its events are evaluated again by its controller. It
+// returns false if the key was not quarantined.
+func (s *System) Release(key string) (Quarantined, bool) {
+	s.quarantineMu.Lock()
+	q, ok := s.quarantined[key]
+	delete(s.quarantined, key)
+	quarantinedControllers.WithLabelValues(s.name).Set(float64(len(s.quarantined)))
+	s.quarantineMu.Unlock()
+	if ok {
+		s.LoggerFor(key).Infof("Released controller from quarantine")
+	}
+	return q, ok
+}
+
+// recordResult tracks the consecutive failed evaluations of the key, and quarantines the key once they reach the
+// QuarantineThreshold. The err is nil if the evaluation succeeded.
+//
+// The controller of a quarantined key is kept, because the actions that it started, such as running tasks, may still
+// be in progress; a new controller would not know about them and would consider them to be interrupted.
+func (s *System) recordResult(key string, err error) {
+	s.quarantineMu.Lock()
+	if err == nil {
+		delete(s.failures, key)
+		s.quarantineMu.Unlock()
+		return
+	}
+	s.failures[key]++
+	failures := s.failures[key]
+	if QuarantineThreshold <= 0 || failures < QuarantineThreshold {
+		s.quarantineMu.Unlock()
+		return
+	}
+	delete(s.failures, key)
+	s.quarantined[key] = Quarantined{
+		Key:       key,
+		Since:     time.Now(),
+		Failures:  failures,
+		LastError: err.Error(),
+	}
+	quarantinedControllers.WithLabelValues(s.name).Set(float64(len(s.quarantined)))
+	s.quarantineMu.Unlock()
+
+	s.LoggerFor(key).Warnf("Quarantined controller after %d consecutive failed evaluations: %v", failures, err)
+}
+
+func (s *System) Name() string {
+	return s.name
+}
+
 func (s *System) Logger() *log.Logger {
 	return s.logger
 }
@@ -151,23 +315,33 @@ func (s *System) LoggerFor(entityID string) *log.Entry {
 }
 
 func (s *System) Submit(event *Event) bool {
-	return s.evalQueue.Add(event)
+	accepted := s.evalQueue.Add(event)
+	if !accepted {
+		eventsDropped.WithLabelValues(s.name).Inc()
+	}
+	evalQueueLength.WithLabelValues(s.name).Set(float64(s.evalQueue.Len()))
+	return accepted
 }
 
 func (s *System) Run() {
 	s.runOnce.Do(func() {
-		go s.run()
+		ctx, cancel := context.WithCancel(context.Background())
+		s.close = cancel
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			s.run(ctx)
+		}()
 	})
 }
 
-func (s *System) run() {
-	ctx, cancel := context.WithCancel(context.Background())
-	s.close = cancel
+func (s *System) run(ctx context.Context) {
 	for {
 		item, shutdown := s.evalQueue.Get()
 		if shutdown {
 			return
 		}
+		evalQueueLength.WithLabelValues(s.name).Set(float64(s.evalQueue.Len()))
 
 		event, ok := item.(*Event)
 		if !ok {
@@ -176,6 +350,12 @@ func (s *System) run() {
 			continue
 		}
 		ctrlKey := event.Aggregate.Id
+		if s.IsQuarantined(ctrlKey) {
+			s.LoggerFor(ctrlKey).Debugf("skipping evaluation of quarantined controller (reason: %v)",
+				event.Event.GetType())
+			s.evalQueue.Done(item)
+			continue
+		}
 		s.LoggerFor(ctrlKey).Debugf("starting evaluation (reason: %v)", event.Event.GetType())
 
 		// Get or create controller for item
@@ -198,12 +378,15 @@ func (s *System) run() {
 }
 
 func (s *System) eval(ctx context.Context, ctrlKey string, ctrl Controller, event *Event) {
+	start := time.Now()
 	defer func() {
 		if r := recover(); r != nil {
+			evalDuration.WithLabelValues(s.name, "panic").Observe(time.Since(start).Seconds())
 			s.logger.Errorf("Recovered from controller crash: %v", r)
 			if log.IsLevelEnabled(log.DebugLevel) {
 				debug.PrintStack()
 			}
+			s.recordResult(ctrlKey, fmt.Errorf("controller crashed: %v", r))
 		}
 	}()
 
@@ -214,11 +397,37 @@ func (s *System) eval(ctx context.Context, ctrlKey string, ctrl Controller, even
 
 	// Trigger the evaluation
 	result := ctrl.Eval(ctx, event)
+	evalDuration.WithLabelValues(s.name, resultLabel(result)).Observe(time.Since(start).Seconds())
 	result.Apply(s, event)
+	switch r := result.(type) {
+	case Err:
+		s.recordResult(ctrlKey, r)
+	case *Err:
+		s.recordResult(ctrlKey, r)
+	default:
+		s.recordResult(ctrlKey, nil)
+	}
 }
 
+// resultLabel returns the label of the result of an evaluation in the metrics.
+func resultLabel(result Result) string {
+	switch result.(type) {
+	case Success, *Success:
+		return "success"
+	case Err, *Err:
+		return "error"
+	case Done, *Done:
+		return "done"
+	default:
+		return "other"
+	}
+}
+
+// Close stops the control system. It stops accepting new events, evaluates the events that are still queued, and
+// waits for the evaluations to finish, before cancelling the context of the controllers.
 func (s *System) Close() error {
 	s.evalQueue.ShutDown()
+	s.running.Wait()
 	if s.close != nil {
 		s.close()
 	}
@@ -229,8 +438,9 @@ type PollSensor struct {
 	interval time.Duration
 	poll     func(evalQueue EvalQueue)
 
-	done   func()
-	closeC <-chan struct{}
+	done    func()
+	closeC  <-chan struct{}
+	running *sync.WaitGroup
 }
 
 func NewPollSensor(interval time.Duration, pollFn func(queue EvalQueue)) *PollSensor {
@@ -240,16 +450,23 @@ func NewPollSensor(interval time.Duration, pollFn func(queue EvalQueue)) *PollSe
 		done:     done,
 		closeC:   ctx.Done(),
 		poll:     pollFn,
+		running:  &sync.WaitGroup{},
 	}
 }
 
+// Close stops the polling, and waits for a poll that is in progress to finish.
 func (s *PollSensor) Close() error {
 	s.done()
+	s.running.Wait()
 	return nil
 }
 
 func (s *PollSensor) Start(evalQueue EvalQueue) error {
-	go s.Run(evalQueue)
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.Run(evalQueue)
+	}()
 	return nil
 }
 
@@ -0,0 +1,310 @@
+package ctrl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type resultController struct {
+	results chan Result
+}
+
+func (c *resultController) Eval(ctx context.Context, event *Event) Result {
+	result := <-c.results
+	if result == nil {
+		panic("no result")
+	}
+	return result
+}
+
+func newEvent(key string) *Event {
+	aggregate := fes.Aggregate{Type: "test", Id: key}
+	return &Event{
+		Aggregate: aggregate,
+		Event:     &fes.Event{Aggregate: &aggregate},
+	}
+}
+
+func evalCount(t *testing.T, system string, result string) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, evalDuration.WithLabelValues(system, result).(prometheus.Histogram).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestSystemMetrics(t *testing.T) {
+	c := &resultController{results: make(chan Result)}
+	s := NewSystem("test-metrics", func(event *Event) (Controller, error) {
+		return c, nil
+	})
+	assert.Equal(t, "test-metrics", s.Name())
+	// The metrics are global, so only their increase is checked.
+	before := map[string]uint64{}
+	for _, result := range []string{"success", "error", "panic", "done"} {
+		before[result] = evalCount(t, "test-metrics", result)
+	}
+	dropped := testutil.ToFloat64(eventsDropped.WithLabelValues("test-metrics"))
+
+	// Queued events are counted until the system evaluates them.
+	assert.True(t, s.Submit(newEvent("a")))
+	assert.True(t, s.Submit(newEvent("b")))
+	assert.EqualValues(t, 2, testutil.ToFloat64(evalQueueLength.WithLabelValues("test-metrics")))
+
+	s.Run()
+	for _, result := range []Result{Success{}, Err{Err: errors.New("failed")}} {
+		c.results <- result
+	}
+	assert.True(t, s.Submit(newEvent("c")))
+	c.results <- nil
+	assert.True(t, s.Submit(newEvent("a")))
+	c.results <- Done{}
+
+	for deadline := time.Now().Add(time.Second); evalCount(t, "test-metrics", "done") == before["done"]; {
+		require.True(t, time.Now().Before(deadline), "evaluation did not finish")
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.EqualValues(t, before["success"]+1, evalCount(t, "test-metrics", "success"))
+	assert.EqualValues(t, before["error"]+1, evalCount(t, "test-metrics", "error"))
+	assert.EqualValues(t, before["panic"]+1, evalCount(t, "test-metrics", "panic"))
+	assert.EqualValues(t, 0, testutil.ToFloat64(evalQueueLength.WithLabelValues("test-metrics")))
+
+	// Events submitted after the system has been closed are dropped.
+	assert.NoError(t, s.Close())
+	assert.False(t, s.Submit(newEvent("d")))
+	assert.EqualValues(t, dropped+1, testutil.ToFloat64(eventsDropped.WithLabelValues("test-metrics")))
+}
+
+// keyController records the keys of the evaluated events, before evaluating them with the shared controller.
+type keyController struct {
+	*resultController
+	evaluated chan string
+}
+
+func (c *keyController) Eval(ctx context.Context, event *Event) Result {
+	c.evaluated <- event.Aggregate.Id
+	return c.resultController.Eval(ctx, event)
+}
+
+func TestSystemQuarantine(t *testing.T) {
+	threshold := QuarantineThreshold
+	QuarantineThreshold = 3
+	defer func() {
+		QuarantineThreshold = threshold
+	}()
+	c := &keyController{
+		resultController: &resultController{results: make(chan Result)},
+		evaluated:        make(chan string, 100),
+	}
+	created := map[string]int{}
+	s := NewSystem("test-quarantine", func(event *Event) (Controller, error) {
+		created[event.Aggregate.Id]++
+		return c, nil
+	})
+	s.Run()
+	defer s.Close()
+
+	// eval evaluates an event of the key with the result, and returns the key that was evaluated. As the system
+	// evaluates one event at a time, the previous evaluation has been completed once the result has been received.
+	eval := func(key string, result Result) string {
+		require.True(t, s.Submit(newEvent(key)))
+		select {
+		case c.results <- result:
+		case <-time.After(time.Second):
+			require.Fail(t, "event was not evaluated", key)
+		}
+		return <-c.evaluated
+	}
+	failed := Err{Err: errors.New("failed")}
+
+	// Successful evaluations reset the consecutive failures.
+	for _, result := range []Result{failed, failed, Success{}, failed, failed} {
+		assert.Equal(t, "a", eval("a", result))
+	}
+	eval("z", Success{})
+	assert.False(t, s.IsQuarantined("a"))
+
+	eval("a", failed)
+	eval("z", Success{})
+	assert.True(t, s.IsQuarantined("a"))
+	_, ok := s.GetController("a")
+	assert.True(t, ok)
+
+	// Panics count as failures.
+	for i := 0; i < 3; i++ {
+		eval("p", nil)
+	}
+	eval("z", Success{})
+	quarantined := s.Quarantined()
+	require.Len(t, quarantined, 2)
+	assert.Equal(t, "a", quarantined[0].Key)
+	assert.Equal(t, 3, quarantined[0].Failures)
+	assert.Equal(t, "failed", quarantined[0].LastError)
+	assert.Equal(t, "p", quarantined[1].Key)
+	assert.Contains(t, quarantined[1].LastError, "no result")
+	assert.EqualValues(t, 2, testutil.ToFloat64(quarantinedControllers.WithLabelValues("test-quarantine")))
+
+	// The events of quarantined keys are not evaluated.
+	require.True(t, s.Submit(newEvent("a")))
+	assert.Equal(t, "z", eval("z", Success{}))
+
+	// Released keys are evaluated again by their controller.
+	released, ok := s.Release("a")
+	assert.True(t, ok)
+	assert.Equal(t, "a", released.Key)
+	assert.False(t, s.IsQuarantined("a"))
+	assert.Equal(t, "a", eval("a", Success{}))
+	eval("z", Success{})
+	assert.Equal(t, 1, created["a"])
+	assert.EqualValues(t, 1, testutil.ToFloat64(quarantinedControllers.WithLabelValues("test-quarantine")))
+
+	_, ok = s.Release("unknown")
+	assert.False(t, ok)
+}
+
+// taskController starts a task in its first evaluation, and fails its evaluations until the task has completed.
+type taskController struct {
+	started   bool
+	completed chan struct{}
+	results   chan Result
+}
+
+func (c *taskController) Eval(ctx context.Context, event *Event) Result {
+	var result Result
+	switch {
+	case !c.started:
+		c.started = true
+		result = Success{Msg: "started task"}
+	default:
+		select {
+		case <-c.completed:
+			result = Done{}
+		default:
+			result = Err{Err: errors.New("task is still running")}
+		}
+	}
+	c.results <- result
+	return result
+}
+
+func TestSystemQuarantineKeepsRunningTasks(t *testing.T) {
+	threshold := QuarantineThreshold
+	QuarantineThreshold = 2
+	defer func() {
+		QuarantineThreshold = threshold
+	}()
+	var created int
+	c := &taskController{completed: make(chan struct{}), results: make(chan Result, 100)}
+	s := NewSystem("test-quarantine-tasks", func(event *Event) (Controller, error) {
+		created++
+		if created > 1 {
+			// A new controller does not know about the running task.
+			return &taskController{completed: make(chan struct{}), results: c.results}, nil
+		}
+		return c, nil
+	})
+	s.Run()
+	defer s.Close()
+	eval := func() Result {
+		require.True(t, s.Submit(newEvent("a")))
+		select {
+		case result := <-c.results:
+			return result
+		case <-time.After(time.Second):
+			require.Fail(t, "event was not evaluated")
+			return nil
+		}
+	}
+
+	assert.Equal(t, Success{Msg: "started task"}, eval())
+	eval()
+	eval()
+	require.True(t, s.IsQuarantined("a"))
+
+	// The task completes while the key is quarantined; after the release the controller still knows the task.
+	close(c.completed)
+	_, ok := s.Release("a")
+	require.True(t, ok)
+	assert.Equal(t, Done{}, eval())
+	assert.Equal(t, 1, created)
+}
+
+type countController struct {
+	evaluated int
+}
+
+func (c *countController) Eval(ctx context.Context, event *Event) Result {
+	c.evaluated++
+	return Success{}
+}
+
+func TestSystemCloseEvaluatesQueuedEvents(t *testing.T) {
+	c := &countController{}
+	s := NewSystem("test-close", func(event *Event) (Controller, error) {
+		return c, nil
+	})
+	for _, key := range []string{"a", "b", "c"} {
+		assert.True(t, s.Submit(newEvent(key)))
+	}
+	s.Run()
+	assert.NoError(t, s.Close())
+	assert.Equal(t, 3, c.evaluated)
+	assert.False(t, s.Submit(newEvent("d")))
+}
+
+type testSensor struct {
+	startErr error
+	started  bool
+	closed   bool
+}
+
+func (s *testSensor) Start(evalQueue EvalQueue) error {
+	s.started = s.startErr == nil
+	return s.startErr
+}
+
+func (s *testSensor) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestStartSensors(t *testing.T) {
+	started, failed, skipped := &testSensor{}, &testSensor{startErr: errors.New("failed")}, &testSensor{}
+	err := StartSensors([]Sensor{started, failed, skipped}, nil)
+	assert.Error(t, err)
+	assert.True(t, started.closed)
+	assert.False(t, failed.closed)
+	assert.False(t, skipped.started)
+
+	sensors := []Sensor{&testSensor{}, &testSensor{}}
+	assert.NoError(t, StartSensors(sensors, nil))
+	assert.NoError(t, CloseSensors(sensors))
+	for _, sensor := range sensors {
+		assert.True(t, sensor.(*testSensor).started)
+		assert.True(t, sensor.(*testSensor).closed)
+	}
+}
+
+func TestPollSensorCloseWaitsForPoll(t *testing.T) {
+	polling := make(chan struct{})
+	var polled bool
+	s := NewPollSensor(time.Millisecond, func(queue EvalQueue) {
+		if polled {
+			return
+		}
+		close(polling)
+		time.Sleep(50 * time.Millisecond)
+		polled = true
+	})
+	require.NoError(t, s.Start(nil))
+	<-polling
+	assert.NoError(t, s.Close())
+	assert.True(t, polled)
+}
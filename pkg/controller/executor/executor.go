@@ -1,33 +1,240 @@
 package executor
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/util/workqueue"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
+	"go.uber.org/atomic"
 )
 
+// saturationReportedGroups is the number of largest groups that are reported when the queue of an executor is
+// saturated.
+const saturationReportedGroups = 5
+
+// runningExecutors are the executors that have been started and not closed yet, of which the queues are measured.
+var (
+	runningExecutors   = map[*LocalExecutor]struct{}{}
+	runningExecutorsMu sync.Mutex
+)
+
+var (
+	queuedTasks = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "queued_tasks",
+		Help:      "Number of tasks in the queues of the executors that have not been picked up by a worker yet",
+	}, func() float64 {
+		return sumExecutors(func(ex *LocalExecutor) int { return ex.queue.Len() })
+	})
+
+	workers = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "workers",
+		Help:      "Number of workers of the executors",
+	}, func() float64 {
+		return sumExecutors(func(ex *LocalExecutor) int { return ex.Workers() })
+	})
+
+	activeWorkers = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "active_workers",
+		Help:      "Number of workers of the executors that are executing a task",
+	}, func() float64 {
+		return sumExecutors(func(ex *LocalExecutor) int { return int(ex.activeWorkers.Load()) })
+	})
+
+	activeGroups = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "active_groups",
+		Help:      "Number of groups, such as invocations, with queued or executing tasks in the executors",
+	}, func() float64 {
+		return sumExecutors(func(ex *LocalExecutor) int {
+			ex.groupsMu.RLock()
+			defer ex.groupsMu.RUnlock()
+			return len(ex.groups)
+		})
+	})
+
+	maxGroupTasks = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "max_group_tasks",
+		Help:      "Number of queued or executing tasks of the largest group in the executors",
+	}, func() float64 {
+		runningExecutorsMu.Lock()
+		defer runningExecutorsMu.Unlock()
+		var max int
+		for ex := range runningExecutors {
+			for _, group := range ex.largestGroups(1) {
+				if group.Tasks > max {
+					max = group.Tasks
+				}
+			}
+		}
+		return float64(max)
+	})
+
+	throttledTasks = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "throttled_tasks",
+		Help:      "Number of tasks that are delayed by the parallelism limit of their group",
+	}, func() float64 {
+		return sumExecutors(func(ex *LocalExecutor) int {
+			ex.groupsMu.RLock()
+			defer ex.groupsMu.RUnlock()
+			var throttled int
+			for _, tasks := range ex.throttled {
+				throttled += len(tasks)
+			}
+			return throttled
+		})
+	})
+
+	rejectedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "rejected_tasks_total",
+		Help:      "Number of tasks that were not submitted, because the queue of the executor was full or shut down",
+	})
+
+	timedOutTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "timed_out_tasks_total",
+		Help:      "Number of tasks that were abandoned, because they did not finish within their timeout",
+	})
+
+	abandonedTasks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "abandoned_tasks",
+		Help:      "Number of tasks that timed out, but are still running in the background",
+	})
+
+	deduplicatedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "deduplicated_tasks_total",
+		Help:      "Number of tasks that were not submitted, because the same task was queued or executing already",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(queuedTasks, workers, activeWorkers, activeGroups, maxGroupTasks, throttledTasks,
+		rejectedTasks, deduplicatedTasks, timedOutTasks, abandonedTasks)
+}
+
+// sumExecutors sums the measure over the running executors.
+func sumExecutors(measure func(ex *LocalExecutor) int) float64 {
+	runningExecutorsMu.Lock()
+	defer runningExecutorsMu.Unlock()
+	var sum int
+	for ex := range runningExecutors {
+		sum += measure(ex)
+	}
+	return float64(sum)
+}
+
+const (
+	// PriorityHigh is the priority of tasks that should not wait for other tasks, such as completing or failing an
+	// invocation.
+	PriorityHigh = 1
+
+	// PriorityNormal is the default priority of tasks, such as running the function of a task of an invocation.
+	PriorityNormal = 0
+
+	// PriorityLow is the priority of tasks that are merely optimizations, such as prewarming functions.
+	PriorityLow = -1
+)
+
+// DefaultSaturationAlarms are the percentages of the capacity of the queue of an executor at which a warning is
+// logged by default.
+var DefaultSaturationAlarms = []int{80, 100}
+
 type LocalExecutor struct {
 	//
 	// Config
 	//
-	maxParallelism int
+	name                string
+	maxParallelism      int
+	maxGroupParallelism int
+	maxQueueSize        int
+	alarms              []int
 
 	//
 	// State
 	//
-	queue    workqueue.DelayingInterface
-	workers  []*worker
-	groups   map[interface{}]int
-	groupsMu *sync.RWMutex
+	queue         workqueue.DelayingInterface
+	started       bool
+	workers       int
+	workersMu     *sync.Mutex
+	groups        map[interface{}]int
+	pending       map[taskKey]struct{}
+	running       map[interface{}]int
+	throttled     map[interface{}][]*Task
+	groupsMu      *sync.RWMutex
+	activeWorkers *atomic.Int32
+	alarmLevel    int
+	alarmMu       *sync.Mutex
+	draining      bool
+	inflight      map[*Task]struct{}
+	abandoned     []taskKey
+	finished      int
+	idle          chan struct{}
+	drainMu       *sync.Mutex
+}
+
+// SubmitResult is the outcome of submitting a task to the executor.
+type SubmitResult int
+
+const (
+	// TaskAccepted indicates that the task was queued for execution.
+	TaskAccepted SubmitResult = iota
+
+	// TaskDeduplicated indicates that the task was not queued, because a task with the same TaskID and GroupID was
+	// queued or executing already.
+	TaskDeduplicated
+
+	// TaskRejected indicates that the task was not queued, because the queue was full or shut down.
+	TaskRejected
+)
+
+func (r SubmitResult) String() string {
+	switch r {
+	case TaskAccepted:
+		return "accepted"
+	case TaskDeduplicated:
+		return "deduplicated"
+	case TaskRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("SubmitResult(%d)", int(r))
+	}
+}
+
+// taskKey identifies a task within its group.
+type taskKey struct {
+	GroupID interface{}
+	TaskID  interface{}
 }
 
 // Task is the unit of execution that the executor will execute.
 type Task struct {
-	// TaskID is used to ensure that there is only one instance of this task
+	// TaskID is used to ensure that there is only one instance of this task. As long as a task is queued or executing,
+	// tasks with the same TaskID and GroupID are deduplicated. Tasks without a TaskID are never deduplicated.
 	TaskID interface{}
 
 	// GroupID is used to group together tasks.
@@ -35,48 +242,302 @@ type Task struct {
 
 	// Apply is the work that the task comprises.
 	Apply func() error
+
+	// Priority determines the order in which the queued tasks are executed; tasks with a higher priority are executed
+	// first. Tasks with a positive priority, such as PriorityHigh, are accepted even if the queue is full.
+	Priority int
+
+	// Timeout is the maximum duration of Apply. If Apply does not return in time, for example because it is stuck on
+	// a call that cannot be canceled, the task is abandoned: it is reported as timed out and its worker moves on to
+	// the next task, while Apply keeps running in the background. If 0, the task does not time out.
+	Timeout time.Duration
+
+	// Retry configures the retries of the task if Apply fails with a transient error. If nil, the task is not retried.
+	Retry *RetryPolicy
+
+	// attempt is the number of times that the task has been retried.
+	attempt int
 }
 
+// ID identifies the task in the queue. As TaskIDs are unique within a group only, the ID includes the group.
 func (t *Task) ID() interface{} {
-	return t.TaskID
+	if t.TaskID == nil {
+		return nil
+	}
+	return taskKey{GroupID: t.GroupID, TaskID: t.TaskID}
+}
+
+func (t *Task) GetPriority() int {
+	return t.Priority
 }
 
 func NewLocalExecutor(maxParallelism, maxQueueSize int) *LocalExecutor {
+	return NewNamedLocalExecutor("", maxParallelism, maxQueueSize)
+}
+
+// NewNamedLocalExecutor creates an executor with at most maxParallelism workers and a queue of at most maxQueueSize
+// tasks. The name identifies the executor in the logs.
+func NewNamedLocalExecutor(name string, maxParallelism, maxQueueSize int) *LocalExecutor {
 	if maxParallelism <= 0 {
 		panic("LocalExecutor: parallelism should be larger than 0")
 	}
 	if maxQueueSize <= 0 {
 		panic("LocalExecutor: queue size should be larger than 0")
 	}
-	return &LocalExecutor{
+	ex := &LocalExecutor{
+		name:           name,
 		maxParallelism: maxParallelism,
+		maxQueueSize:   maxQueueSize,
 		queue:          workqueue.NewDelayingQueue(maxQueueSize),
 		groups:         make(map[interface{}]int),
+		pending:        make(map[taskKey]struct{}),
+		running:        make(map[interface{}]int),
+		throttled:      make(map[interface{}][]*Task),
 		groupsMu:       &sync.RWMutex{},
+		workersMu:      &sync.Mutex{},
+		activeWorkers:  atomic.NewInt32(0),
+		alarmMu:        &sync.Mutex{},
+		inflight:       make(map[*Task]struct{}),
+		drainMu:        &sync.Mutex{},
+	}
+	ex.SetSaturationAlarms(DefaultSaturationAlarms)
+	return ex
+}
+
+// SetSaturationAlarms sets the percentages of the capacity of the queue at which a warning is logged. A warning is
+// logged once the number of queued tasks reaches one of the percentages, and again only after it has dropped below
+// it. Without percentages, no warnings are logged.
+func (ex *LocalExecutor) SetSaturationAlarms(percentages []int) {
+	var alarms []int
+	for _, percentage := range percentages {
+		if percentage > 0 {
+			alarms = append(alarms, percentage)
+		}
 	}
+	sort.Ints(alarms)
+	ex.alarmMu.Lock()
+	ex.alarms = alarms
+	ex.alarmLevel = 0
+	ex.alarmMu.Unlock()
+}
+
+// SetMaxGroupParallelism limits the number of tasks of a group, such as the tasks of an invocation, that are executed
+// at the same time, so that a single group cannot occupy all workers. The other tasks of the group wait until a task
+// of the group has finished, while the tasks of other groups are executed. If max is 0, the groups are not limited.
+func (ex *LocalExecutor) SetMaxGroupParallelism(max int) {
+	ex.groupsMu.Lock()
+	ex.maxGroupParallelism = max
+	ex.groupsMu.Unlock()
 }
 
 func (ex *LocalExecutor) Start() {
-	if ex.maxParallelism <= 0 {
-		panic("LocalExecutor: parallelism should be larger than 0")
+	ex.workersMu.Lock()
+	ex.started = true
+	ex.scale()
+	ex.workersMu.Unlock()
+	runningExecutorsMu.Lock()
+	runningExecutors[ex] = struct{}{}
+	runningExecutorsMu.Unlock()
+}
+
+// Name returns the name of the executor.
+func (ex *LocalExecutor) Name() string {
+	return ex.name
+}
+
+// Parallelism returns the maximum number of tasks that the executor executes at the same time.
+func (ex *LocalExecutor) Parallelism() int {
+	ex.workersMu.Lock()
+	defer ex.workersMu.Unlock()
+	return ex.maxParallelism
+}
+
+// Workers returns the number of running workers. After the parallelism has been decreased, this can exceed the
+// parallelism until the surplus workers have finished their current task.
+func (ex *LocalExecutor) Workers() int {
+	ex.workersMu.Lock()
+	defer ex.workersMu.Unlock()
+	return ex.workers
+}
+
+// SetParallelism changes the maximum number of tasks that the executor executes at the same time. When decreasing
+// the parallelism, the surplus workers stop once they have finished their current task, so tasks are never
+// interrupted.
+func (ex *LocalExecutor) SetParallelism(parallelism int) error {
+	if parallelism <= 0 {
+		return errors.New("parallelism should be larger than 0")
+	}
+	ex.workersMu.Lock()
+	ex.maxParallelism = parallelism
+	if ex.started {
+		ex.scale()
 	}
-	// Add workers based on max parallelism
-	for i := 0; i < ex.maxParallelism; i++ {
+	ex.workersMu.Unlock()
+	return nil
+}
+
+// QueueSize returns the maximum number of queued tasks.
+func (ex *LocalExecutor) QueueSize() int {
+	ex.alarmMu.Lock()
+	defer ex.alarmMu.Unlock()
+	return ex.maxQueueSize
+}
+
+// QueueLen returns the number of tasks that have been queued, but not picked up by a worker yet.
+func (ex *LocalExecutor) QueueLen() int {
+	return ex.queue.Len()
+}
+
+// SetQueueSize changes the maximum number of queued tasks. When decreasing the size below the number of queued
+// tasks, the queued tasks are kept, but new tasks are rejected until the queue has drained below the new size.
+func (ex *LocalExecutor) SetQueueSize(size int) error {
+	if size <= 0 {
+		return errors.New("queue size should be larger than 0")
+	}
+	ex.queue.SetMaxSize(size)
+	ex.alarmMu.Lock()
+	ex.maxQueueSize = size
+	ex.alarmMu.Unlock()
+	ex.checkSaturation()
+	return nil
+}
+
+// scale starts workers until there are as many workers as the parallelism. Surplus workers are signaled to stop, which
+// they do once they have finished their current task. The caller should hold workersMu.
+func (ex *LocalExecutor) scale() {
+	for ; ex.workers < ex.maxParallelism; ex.workers++ {
 		worker := &worker{
-			queue:    ex.queue,
-			groups:   ex.groups,
-			groupsMu: ex.groupsMu,
+			executor: ex,
 		}
-		ex.workers = append(ex.workers, worker)
 		go worker.Run()
 	}
+	// Wake up idle workers, which are waiting for the next task, to let them stop.
+	for i := ex.maxParallelism; i < ex.workers; i++ {
+		ex.queue.Add(&stopSignal{})
+	}
+}
+
+// retire stops the calling worker if there are more workers than the parallelism.
+func (ex *LocalExecutor) retire() bool {
+	ex.workersMu.Lock()
+	defer ex.workersMu.Unlock()
+	if ex.workers > ex.maxParallelism {
+		ex.workers--
+		return true
+	}
+	return false
+}
+
+// stopSignal is queued to wake up an idle worker, to let it check whether it should stop. It is queued before all
+// tasks and regardless of the size of the queue.
+type stopSignal struct{}
+
+func (s *stopSignal) GetPriority() int {
+	return math.MaxInt32
 }
 
 func (ex *LocalExecutor) Close() error {
+	runningExecutorsMu.Lock()
+	delete(runningExecutors, ex)
+	runningExecutorsMu.Unlock()
 	ex.queue.ShutDown()
 	return nil
 }
 
+// DrainReport describes the outcome of draining an executor. Tasks without a TaskID are not listed.
+type DrainReport struct {
+	// Finished is the number of executing tasks that finished during the drain.
+	Finished int
+
+	// Unfinished are the TaskIDs of the tasks that were still executing when the timeout expired.
+	Unfinished []interface{}
+
+	// Abandoned are the TaskIDs of the queued tasks that were not executed.
+	Abandoned []interface{}
+}
+
+// Drain shuts down the executor gracefully. It stops accepting tasks and abandons the queued tasks, and waits until
+// the executing tasks have finished, or until the timeout expires.
+func (ex *LocalExecutor) Drain(timeout time.Duration) *DrainReport {
+	ex.drainMu.Lock()
+	ex.draining = true
+	idle := make(chan struct{})
+	if len(ex.inflight) == 0 {
+		close(idle)
+	} else {
+		ex.idle = idle
+	}
+	ex.drainMu.Unlock()
+	ex.Close()
+
+	select {
+	case <-idle:
+	case <-time.After(timeout):
+	}
+
+	ex.drainMu.Lock()
+	defer ex.drainMu.Unlock()
+	ex.groupsMu.RLock()
+	defer ex.groupsMu.RUnlock()
+	report := &DrainReport{
+		Finished: ex.finished,
+	}
+	reported := map[taskKey]bool{}
+	for task := range ex.inflight {
+		if task.TaskID != nil {
+			reported[taskKey{GroupID: task.GroupID, TaskID: task.TaskID}] = true
+			report.Unfinished = append(report.Unfinished, task.TaskID)
+		}
+	}
+	// The tasks that have not been picked up by a worker are still pending.
+	abandoned := ex.abandoned
+	for key := range ex.pending {
+		abandoned = append(abandoned, key)
+	}
+	for _, key := range abandoned {
+		if key.TaskID != nil && !reported[key] {
+			reported[key] = true
+			report.Abandoned = append(report.Abandoned, key.TaskID)
+		}
+	}
+	sortTaskIDs(report.Unfinished)
+	sortTaskIDs(report.Abandoned)
+	return report
+}
+
+func sortTaskIDs(ids []interface{}) {
+	sort.Slice(ids, func(i, j int) bool {
+		return fmt.Sprint(ids[i]) < fmt.Sprint(ids[j])
+	})
+}
+
+// begin registers the task as executing. It returns false if the executor is being drained, in which case the task is
+// abandoned instead.
+func (ex *LocalExecutor) begin(task *Task) bool {
+	ex.drainMu.Lock()
+	defer ex.drainMu.Unlock()
+	if ex.draining {
+		ex.abandoned = append(ex.abandoned, taskKey{GroupID: task.GroupID, TaskID: task.TaskID})
+		return false
+	}
+	ex.inflight[task] = struct{}{}
+	return true
+}
+
+// end registers that the task has finished executing.
+func (ex *LocalExecutor) end(task *Task) {
+	ex.drainMu.Lock()
+	defer ex.drainMu.Unlock()
+	delete(ex.inflight, task)
+	if ex.draining {
+		ex.finished++
+		if len(ex.inflight) == 0 && ex.idle != nil {
+			close(ex.idle)
+			ex.idle = nil
+		}
+	}
+}
+
 func (ex *LocalExecutor) GetGroupTasks(groupID interface{}) int {
 	ex.groupsMu.RLock()
 	count := ex.groups[groupID]
@@ -84,67 +545,261 @@ func (ex *LocalExecutor) GetGroupTasks(groupID interface{}) int {
 	return count
 }
 
-func (ex *LocalExecutor) SubmitAfter(t *Task, after time.Duration) bool {
+func (ex *LocalExecutor) SubmitAfter(t *Task, after time.Duration) SubmitResult {
+	// Register the task before queueing it, as a worker could finish the task before this function returns.
+	if !ex.addPending(t) {
+		deduplicatedTasks.Inc()
+		return TaskDeduplicated
+	}
+
 	// Add to the queue
+	var accepted bool
 	if after <= 0 {
-		accepted := ex.queue.TryAddAfter(t, after)
-		if !accepted {
-			return false
-		}
+		accepted = ex.queue.TryAddAfter(t, after)
 	} else {
-		accepted := ex.queue.Add(t)
-		if !accepted {
+		accepted = ex.queue.Add(t)
+	}
+	if !accepted {
+		ex.removePending(t)
+		rejectedTasks.Inc()
+		ex.checkSaturation()
+		return TaskRejected
+	}
+	ex.checkSaturation()
+	return TaskAccepted
+}
+
+func (ex *LocalExecutor) Submit(t *Task) SubmitResult {
+	return ex.SubmitAfter(t, 0)
+}
+
+// addPending counts the task as a queued or executing task of its group. It returns false, without counting the task,
+// if a task with the same TaskID is pending in the group already.
+func (ex *LocalExecutor) addPending(t *Task) bool {
+	ex.groupsMu.Lock()
+	defer ex.groupsMu.Unlock()
+	if t.TaskID != nil {
+		key := taskKey{GroupID: t.GroupID, TaskID: t.TaskID}
+		if _, ok := ex.pending[key]; ok {
 			return false
 		}
+		ex.pending[key] = struct{}{}
 	}
-
-	// Increment the group
 	if t.GroupID != nil {
-		ex.groupsMu.Lock()
 		ex.groups[t.GroupID]++
-		ex.groupsMu.Unlock()
 	}
 	return true
 }
 
-func (ex *LocalExecutor) Submit(t *Task) bool {
-	return ex.SubmitAfter(t, 0)
+// removePending removes the task from the queued or executing tasks of its group. Groups without tasks are removed.
+func (ex *LocalExecutor) removePending(t *Task) {
+	ex.groupsMu.Lock()
+	defer ex.groupsMu.Unlock()
+	if t.TaskID != nil {
+		delete(ex.pending, taskKey{GroupID: t.GroupID, TaskID: t.TaskID})
+	}
+	if t.GroupID != nil {
+		ex.groups[t.GroupID]--
+		if ex.groups[t.GroupID] <= 0 {
+			delete(ex.groups, t.GroupID)
+		}
+	}
+}
+
+// groupTasks is the number of queued or executing tasks of a group.
+type groupTasks struct {
+	GroupID interface{}
+	Tasks   int
+}
+
+// largestGroups returns at most n groups with the most queued or executing tasks, in descending order.
+func (ex *LocalExecutor) largestGroups(n int) []groupTasks {
+	ex.groupsMu.RLock()
+	groups := make([]groupTasks, 0, len(ex.groups))
+	for id, count := range ex.groups {
+		groups = append(groups, groupTasks{GroupID: id, Tasks: count})
+	}
+	ex.groupsMu.RUnlock()
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Tasks > groups[j].Tasks
+	})
+	if len(groups) > n {
+		groups = groups[:n]
+	}
+	return groups
+}
+
+// checkSaturation logs a warning if the number of queued tasks has reached a higher alarm than before. The alarms are
+// reset once the number of queued tasks drops below them.
+func (ex *LocalExecutor) checkSaturation() {
+	ex.alarmMu.Lock()
+	defer ex.alarmMu.Unlock()
+	if len(ex.alarms) == 0 {
+		return
+	}
+	queued := ex.queue.Len()
+	var level int
+	for _, percentage := range ex.alarms {
+		if queued*100 < percentage*ex.maxQueueSize {
+			break
+		}
+		level++
+	}
+	if level > ex.alarmLevel {
+		var groups []string
+		for _, group := range ex.largestGroups(saturationReportedGroups) {
+			groups = append(groups, fmt.Sprintf("%v=%d", group.GroupID, group.Tasks))
+		}
+		log.WithFields(log.Fields{
+			"executor":       ex.name,
+			"queued":         queued,
+			"capacity":       ex.maxQueueSize,
+			"threshold":      fmt.Sprintf("%d%%", ex.alarms[level-1]),
+			"workers":        ex.Workers(),
+			"active_workers": ex.activeWorkers.Load(),
+			"largest_groups": strings.Join(groups, ","),
+		}).Warn("Executor queue is saturated; tasks are delayed or rejected")
+	} else if level == 0 && ex.alarmLevel > 0 {
+		log.WithFields(log.Fields{
+			"executor": ex.name,
+			"queued":   queued,
+			"capacity": ex.maxQueueSize,
+		}).Info("Executor queue is no longer saturated")
+	}
+	ex.alarmLevel = level
 }
 
 type worker struct {
-	queue    workqueue.Interface
-	groups   map[interface{}]int
-	groupsMu *sync.RWMutex
+	executor *LocalExecutor
 }
 
 func (w *worker) Run() {
+	ex := w.executor
 	for {
-		item, shutdown := w.queue.Get()
+		if ex.retire() {
+			return
+		}
+		item, shutdown := ex.queue.Get()
 		if shutdown {
 			return
 		}
-		task := item.(*Task)
+		task, ok := item.(*Task)
+		if !ok {
+			ex.queue.Done(item)
+			continue
+		}
+		ex.checkSaturation()
+		if !ex.acquire(task) {
+			continue
+		}
+
+		for task != nil {
+			executing := ex.begin(task)
+			var err error
+			if executing {
+				ex.activeWorkers.Inc()
+				err = executeTask(task)
+				ex.activeWorkers.Dec()
+			}
+			next := ex.release(task)
+			if executing {
+				if err != nil && !ex.retry(task, err) {
+					log.Errorf("Task %s/%s failed: %v", task.GroupID, task.TaskID, err)
+				}
+				ex.end(task)
+			}
+			task = next
+		}
+	}
+}
 
-		executeTask(task)
+// acquire reserves a slot of the group of the task for its execution. If all slots of the group are taken, the task
+// is throttled until a task of the group is released. The throttled task remains in processing in the queue, so that
+// it is not queued again in the meantime.
+func (ex *LocalExecutor) acquire(task *Task) bool {
+	if task.GroupID == nil {
+		return true
+	}
+	ex.groupsMu.Lock()
+	defer ex.groupsMu.Unlock()
+	if ex.maxGroupParallelism > 0 && ex.running[task.GroupID] >= ex.maxGroupParallelism {
+		// Keep the throttled tasks of the group ordered by priority.
+		throttled := ex.throttled[task.GroupID]
+		i := sort.Search(len(throttled), func(i int) bool {
+			return throttled[i].Priority < task.Priority
+		})
+		throttled = append(throttled, nil)
+		copy(throttled[i+1:], throttled[i:])
+		throttled[i] = task
+		ex.throttled[task.GroupID] = throttled
+		return false
+	}
+	ex.running[task.GroupID]++
+	return true
+}
 
-		w.queue.Done(task)
-		if task.GroupID != nil {
-			w.groupsMu.Lock()
-			w.groups[task.GroupID]--
-			w.groupsMu.Unlock()
+// release marks the task as done, and returns the next throttled task of its group, of which the slot has been
+// acquired already, if any.
+func (ex *LocalExecutor) release(task *Task) *Task {
+	ex.queue.Done(task)
+	ex.removePending(task)
+	if task.GroupID == nil {
+		return nil
+	}
+	ex.groupsMu.Lock()
+	defer ex.groupsMu.Unlock()
+	if throttled := ex.throttled[task.GroupID]; len(throttled) > 0 {
+		next := throttled[0]
+		if len(throttled) == 1 {
+			delete(ex.throttled, task.GroupID)
+		} else {
+			ex.throttled[task.GroupID] = throttled[1:]
 		}
+		return next
+	}
+	ex.running[task.GroupID]--
+	if ex.running[task.GroupID] <= 0 {
+		delete(ex.running, task.GroupID)
+	}
+	return nil
+}
+
+// executeTask applies the task, and returns the error with which it failed. Tasks that crashed or timed out are not
+// considered failed, as retrying them is unlikely to help.
+func executeTask(task *Task) error {
+	if task.Timeout <= 0 {
+		return applyTask(task)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- applyTask(task)
+	}()
+	timer := time.NewTimer(task.Timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		timedOutTasks.Inc()
+		abandonedTasks.Inc()
+		log.Errorf("Task %s/%s did not finish within its timeout of %v; abandoning it", task.GroupID, task.TaskID,
+			task.Timeout)
+		go func() {
+			<-done
+			abandonedTasks.Dec()
+			log.Warnf("Abandoned task %s/%s finished after all", task.GroupID, task.TaskID)
+		}()
+		return nil
 	}
 }
 
-func executeTask(task *Task) {
+func applyTask(task *Task) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			log.Errorf("Task %s/%s crashed: %v", task.GroupID, task.TaskID, r)
 			fmt.Println(string(debug.Stack()))
+			err = nil
 		}
 	}()
-	err := task.Apply()
-	if err != nil {
-		log.Errorf("Task %s/%s failed: %v", task.GroupID, task.TaskID, err)
-	}
+	return task.Apply()
 }
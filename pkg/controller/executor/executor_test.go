@@ -4,7 +4,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
 
@@ -18,19 +20,19 @@ func TestLocalExecutor(t *testing.T) {
 	accepted := executor.Submit(&Task{
 		Apply: t1.Apply,
 	})
-	assert.True(t, accepted)
+	assert.Equal(t, TaskAccepted, accepted)
 	accepted = executor.Submit(&Task{
 		Apply: t2.Apply,
 	})
-	assert.True(t, accepted)
+	assert.Equal(t, TaskAccepted, accepted)
 	accepted = executor.Submit(&Task{
 		Apply: t3.Apply,
 	})
-	assert.True(t, accepted)
+	assert.Equal(t, TaskAccepted, accepted)
 	accepted = executor.Submit(&Task{
 		Apply: t4.Apply,
 	})
-	assert.False(t, accepted)
+	assert.Equal(t, TaskRejected, accepted)
 	assert.Equal(t, 3, executor.queue.Len())
 	executor.Start()
 	defer executor.Close()
@@ -48,3 +50,331 @@ func (t *testTask) Apply() error {
 	t.n.Add(1)
 	return nil
 }
+
+func TestLocalExecutorSaturation(t *testing.T) {
+	executor := NewNamedLocalExecutor("test", 1, 4)
+	executor.SetSaturationAlarms([]int{100, 50, 0})
+	rejected := testutil.ToFloat64(rejectedTasks)
+
+	release := make(chan struct{})
+	submit := func(id string, group string) SubmitResult {
+		return executor.Submit(&Task{
+			TaskID:  id,
+			GroupID: group,
+			Apply: func() error {
+				<-release
+				return nil
+			},
+		})
+	}
+	assert.Equal(t, TaskAccepted, submit("t1", "a"))
+	assert.Equal(t, 0, alarmLevel(executor))
+	assert.Equal(t, TaskAccepted, submit("t2", "a"))
+	assert.Equal(t, 1, alarmLevel(executor))
+	assert.Equal(t, TaskAccepted, submit("t3", "b"))
+	assert.Equal(t, TaskAccepted, submit("t4", "a"))
+	assert.Equal(t, 2, alarmLevel(executor))
+	assert.Equal(t, TaskRejected, submit("t5", "b"))
+	assert.Equal(t, rejected+1, testutil.ToFloat64(rejectedTasks))
+	assert.Equal(t, []groupTasks{{GroupID: "a", Tasks: 3}}, executor.largestGroups(1))
+
+	// Once the tasks have been executed, the groups are removed and the alarms are reset.
+	executor.Start()
+	defer executor.Close()
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), executor.activeWorkers.Load())
+	close(release)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(0), executor.activeWorkers.Load())
+	assert.Equal(t, 0, alarmLevel(executor))
+	assert.Empty(t, executor.largestGroups(1))
+}
+
+func alarmLevel(executor *LocalExecutor) int {
+	executor.alarmMu.Lock()
+	defer executor.alarmMu.Unlock()
+	return executor.alarmLevel
+}
+
+func TestLocalExecutorPriorities(t *testing.T) {
+	executor := NewLocalExecutor(1, 2)
+	executed := make(chan string, 4)
+	submit := func(id string, priority int) SubmitResult {
+		return executor.Submit(&Task{
+			TaskID:   id,
+			Priority: priority,
+			Apply: func() error {
+				executed <- id
+				return nil
+			},
+		})
+	}
+	assert.Equal(t, TaskAccepted, submit("prewarm", PriorityLow))
+	assert.Equal(t, TaskAccepted, submit("run", PriorityNormal))
+	assert.Equal(t, TaskRejected, submit("run2", PriorityNormal))
+	// High priority tasks are accepted and executed first, even with a full queue.
+	assert.Equal(t, TaskAccepted, submit("fail", PriorityHigh))
+
+	executor.Start()
+	defer executor.Close()
+	var order []string
+	for i := 0; i < 3; i++ {
+		select {
+		case id := <-executed:
+			order = append(order, id)
+		case <-time.After(time.Second):
+			t.Fatal("tasks were not executed")
+		}
+	}
+	assert.Equal(t, []string{"fail", "run", "prewarm"}, order)
+}
+
+func TestLocalExecutorMaxGroupParallelism(t *testing.T) {
+	executor := NewLocalExecutor(3, 10)
+	executor.SetMaxGroupParallelism(1)
+	executor.Start()
+	defer executor.Close()
+
+	release := make(chan struct{})
+	executed := make(chan string, 10)
+	submit := func(id string, group string, priority int) {
+		assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+			TaskID:   id,
+			GroupID:  group,
+			Priority: priority,
+			Apply: func() error {
+				executed <- id
+				<-release
+				return nil
+			},
+		}))
+	}
+	submit("a1", "a", PriorityNormal)
+	assert.Equal(t, "a1", <-executed)
+	submit("a2", "a", PriorityNormal)
+	submit("a3", "a", PriorityHigh)
+	submit("b1", "b", PriorityNormal)
+
+	// The other group is not blocked by the throttled tasks of the first group.
+	assert.Equal(t, "b1", <-executed)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, executed)
+	assert.Equal(t, 3, executor.GetGroupTasks("a"))
+	assert.EqualValues(t, 2, testutil.ToFloat64(throttledTasks))
+
+	// The throttled tasks are executed one by one, by priority.
+	close(release)
+	assert.Equal(t, "a3", <-executed)
+	assert.Equal(t, "a2", <-executed)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0, executor.GetGroupTasks("a"))
+	assert.EqualValues(t, 0, testutil.ToFloat64(throttledTasks))
+}
+
+func TestLocalExecutorSetParallelism(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	assert.Error(t, executor.SetParallelism(0))
+	executor.Start()
+	defer executor.Close()
+
+	release := make(chan struct{})
+	executed := make(chan string, 10)
+	submit := func(id string) {
+		assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+			TaskID:  id,
+			GroupID: id,
+			Apply: func() error {
+				executed <- id
+				<-release
+				return nil
+			},
+		}))
+	}
+	submit("a")
+	submit("b")
+	assert.Equal(t, "a", <-executed)
+	assert.Equal(t, 1, executor.QueueLen())
+
+	// Additional workers pick up the queued tasks.
+	assert.NoError(t, executor.SetParallelism(3))
+	assert.Equal(t, "b", <-executed)
+	assert.Equal(t, 3, executor.Workers())
+
+	// Surplus workers stop only after finishing their current task.
+	assert.NoError(t, executor.SetParallelism(1))
+	assert.Equal(t, 1, executor.Parallelism())
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 2, executor.Workers())
+	close(release)
+	for deadline := time.Now().Add(time.Second); executor.Workers() > 1; {
+		require.True(t, time.Now().Before(deadline), "surplus workers did not stop")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The remaining worker continues to execute tasks.
+	submit("c")
+	assert.Equal(t, "c", <-executed)
+	assert.Equal(t, 1, executor.Workers())
+}
+
+func TestLocalExecutorSetQueueSize(t *testing.T) {
+	executor := NewLocalExecutor(1, 1)
+	assert.Error(t, executor.SetQueueSize(0))
+	// Without started workers, the submitted tasks stay queued.
+	task := func(id string) *Task {
+		return &Task{TaskID: id, GroupID: id, Apply: func() error { return nil }}
+	}
+	assert.Equal(t, TaskAccepted, executor.Submit(task("a")))
+	assert.Equal(t, TaskRejected, executor.Submit(task("b")))
+
+	assert.NoError(t, executor.SetQueueSize(2))
+	assert.Equal(t, 2, executor.QueueSize())
+	assert.Equal(t, TaskAccepted, executor.Submit(task("b")))
+	assert.Equal(t, TaskRejected, executor.Submit(task("c")))
+
+	// Shrinking the queue keeps the queued tasks.
+	assert.NoError(t, executor.SetQueueSize(1))
+	assert.Equal(t, 2, executor.QueueLen())
+	assert.Equal(t, TaskRejected, executor.Submit(task("c")))
+	executor.Close()
+}
+
+func TestLocalExecutorDeduplication(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	deduplicated := testutil.ToFloat64(deduplicatedTasks)
+	release := make(chan struct{})
+	executed := make(chan string, 10)
+	task := func(id string, group string) *Task {
+		return &Task{
+			TaskID:  id,
+			GroupID: group,
+			Apply: func() error {
+				executed <- group + "/" + id
+				<-release
+				return nil
+			},
+		}
+	}
+
+	// Queued tasks are deduplicated within their group.
+	assert.Equal(t, TaskAccepted, executor.Submit(task("run", "a")))
+	assert.Equal(t, TaskDeduplicated, executor.Submit(task("run", "a")))
+	assert.Equal(t, TaskAccepted, executor.Submit(task("run", "b")))
+	assert.Equal(t, 1, executor.GetGroupTasks("a"))
+	assert.Equal(t, deduplicated+1, testutil.ToFloat64(deduplicatedTasks))
+
+	// Executing tasks are deduplicated as well.
+	executor.Start()
+	defer executor.Close()
+	assert.Equal(t, "a/run", <-executed)
+	assert.Equal(t, TaskDeduplicated, executor.Submit(task("run", "a")))
+
+	// Once a task has finished, it can be submitted again.
+	close(release)
+	assert.Equal(t, "b/run", <-executed)
+	for deadline := time.Now().Add(time.Second); executor.GetGroupTasks("a") > 0; {
+		require.True(t, time.Now().Before(deadline), "task did not finish")
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, TaskAccepted, executor.Submit(task("run", "a")))
+	assert.Equal(t, "a/run", <-executed)
+}
+
+func TestLocalExecutorDrain(t *testing.T) {
+	executor := NewLocalExecutor(2, 10)
+	executor.Start()
+	started := make(chan string, 10)
+	release := map[string]chan struct{}{}
+	submit := func(id string) {
+		release[id] = make(chan struct{})
+		assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+			TaskID:  id,
+			GroupID: id,
+			Apply: func() error {
+				started <- id
+				<-release[id]
+				return nil
+			},
+		}))
+	}
+	submit("fast")
+	submit("slow")
+	submit("queued")
+	assert.ElementsMatch(t, []string{"fast", "slow"}, []string{<-started, <-started})
+
+	// The drain waits for the executing tasks until the timeout expires.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release["fast"])
+	}()
+	report := executor.Drain(200 * time.Millisecond)
+	assert.Equal(t, &DrainReport{
+		Finished:   1,
+		Unfinished: []interface{}{"slow"},
+		Abandoned:  []interface{}{"queued"},
+	}, report)
+	assert.Equal(t, TaskRejected, executor.Submit(&Task{TaskID: "new", Apply: func() error { return nil }}))
+	close(release["slow"])
+	close(release["queued"])
+	assert.Empty(t, started)
+}
+
+func TestLocalExecutorDrainIdle(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	executor.Start()
+	start := time.Now()
+	assert.Equal(t, &DrainReport{}, executor.Drain(time.Second))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestLocalExecutorTaskTimeout(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	executor.Start()
+	defer executor.Close()
+	timedOut := testutil.ToFloat64(timedOutTasks)
+	abandoned := testutil.ToFloat64(abandonedTasks)
+
+	stuck := make(chan struct{})
+	finished := make(chan struct{})
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "stuck",
+		GroupID: "a",
+		Timeout: 50 * time.Millisecond,
+		Apply: func() error {
+			<-stuck
+			close(finished)
+			return nil
+		},
+	}))
+
+	// The stuck task does not occupy the worker beyond its timeout.
+	executed := make(chan struct{})
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "next",
+		GroupID: "a",
+		Apply: func() error {
+			close(executed)
+			return nil
+		},
+	}))
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Fatal("the task after the stuck task was not executed")
+	}
+	assert.Equal(t, timedOut+1, testutil.ToFloat64(timedOutTasks))
+	assert.Equal(t, abandoned+1, testutil.ToFloat64(abandonedTasks))
+	// The abandoned task is no longer pending, so it can be submitted again.
+	for deadline := time.Now().Add(time.Second); executor.GetGroupTasks("a") > 0; {
+		require.True(t, time.Now().Before(deadline), "tasks are still pending")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Once the abandoned task finishes after all, it is no longer counted.
+	close(stuck)
+	<-finished
+	for deadline := time.Now().Add(time.Second); testutil.ToFloat64(abandonedTasks) > abandoned; {
+		require.True(t, time.Now().Before(deadline), "abandoned task is still counted")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,189 @@
+package executor
+
+import (
+	"net"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/util/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	retriedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "retried_tasks_total",
+		Help:      "Number of retries of tasks that failed with a transient error",
+	})
+
+	exhaustedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "executor",
+		Name:      "exhausted_retries_total",
+		Help:      "Number of tasks that failed with a transient error in each of their attempts",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(retriedTasks, exhaustedTasks)
+}
+
+// DefaultRetryPolicy is the retry policy of tasks that are retried with the defaults.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// RetryPolicy configures the retries of a task of which Apply failed with a transient error, such as a network error
+// or an unavailable backend. The task is queued again after a delay, instead of waiting for the next evaluation of
+// the controller to submit it again. While the task waits for its retry, it remains pending: tasks with the same
+// TaskID and GroupID are deduplicated.
+//
+// Zero fields are set to the value of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of the task, including the first attempt.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, which doubles for each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay before a retry.
+	MaxDelay time.Duration
+
+	// Retryable determines whether an error is transient. If nil, IsTransient is used.
+	Retryable func(err error) bool
+}
+
+func (p *RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryPolicy.MaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable == nil {
+		return IsTransient(err)
+	}
+	return p.Retryable(err)
+}
+
+// delay returns the delay before the retry, where retry 1 is the second attempt of the task.
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	base, max := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = DefaultRetryPolicy.BaseDelay
+	}
+	if max <= 0 {
+		max = DefaultRetryPolicy.MaxDelay
+	}
+	delay := backoff.ExponentialBackoff(retry-1, base)
+	if delay <= 0 || delay > max {
+		return max
+	}
+	return delay
+}
+
+// transientError marks an error as transient.
+type transientError struct {
+	error
+}
+
+func (err transientError) Temporary() bool {
+	return true
+}
+
+func (err transientError) Cause() error {
+	return err.error
+}
+
+// Transient marks the error as transient, so that a task that fails with it is retried.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return transientError{err}
+}
+
+// IsTransient returns whether the error is likely to disappear when the task is retried. These are errors that were
+// marked with Transient, temporary network errors and timeouts, failures to connect, and gRPC errors that indicate
+// that the server is unavailable or overloaded.
+func IsTransient(err error) bool {
+	for err != nil {
+		if tmpErr, ok := err.(interface{ Temporary() bool }); ok && tmpErr.Temporary() {
+			return true
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return true
+		}
+		if opErr, ok := err.(*net.OpError); ok && opErr.Op == "dial" {
+			return true
+		}
+		if s, ok := status.FromError(err); ok {
+			switch s.Code() {
+			case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+				return true
+			}
+		}
+		err = unwrap(err)
+	}
+	return false
+}
+
+// unwrap returns the error that the error wraps, or nil. Unlike errors.Cause, it only unwraps a single error, so that
+// the errors that are marked as transient are not skipped.
+func unwrap(err error) error {
+	switch e := err.(type) {
+	case *net.OpError:
+		return e.Err
+	case interface{ Cause() error }:
+		return e.Cause()
+	case interface{ Unwrap() error }:
+		return e.Unwrap()
+	default:
+		return nil
+	}
+}
+
+// retry queues the task again after a delay if it failed with a transient error and it has attempts left. It returns
+// false if the task is not retried, in which case the failure is final.
+func (ex *LocalExecutor) retry(task *Task, err error) bool {
+	policy := task.Retry
+	if policy == nil || !policy.retryable(err) {
+		return false
+	}
+	if task.attempt+1 >= policy.maxAttempts() {
+		exhaustedTasks.Inc()
+		return false
+	}
+	ex.drainMu.Lock()
+	draining := ex.draining
+	ex.drainMu.Unlock()
+	if draining {
+		return false
+	}
+	// The task might have been submitted again after it failed; that submission takes the place of the retry.
+	if !ex.addPending(task) {
+		log.Debugf("Task %s/%s was submitted again; not retrying it", task.GroupID, task.TaskID)
+		return true
+	}
+
+	task.attempt++
+	delay := policy.delay(task.attempt)
+	retriedTasks.Inc()
+	log.Warnf("Task %s/%s failed with a transient error (attempt %d of %d); retrying in %v: %v", task.GroupID,
+		task.TaskID, task.attempt, policy.maxAttempts(), delay, err)
+	time.AfterFunc(delay, func() {
+		if !ex.queue.Add(task) {
+			ex.removePending(task)
+			rejectedTasks.Inc()
+			log.Errorf("Task %s/%s could not be retried, because the queue was full or shut down: %v",
+				task.GroupID, task.TaskID, err)
+		}
+	})
+	return true
+}
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsTransient(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	assert.True(t, IsTransient(Transient(errors.New("unavailable"))))
+	assert.True(t, IsTransient(pkgerrors.Wrap(Transient(errors.New("unavailable")), "failed to append")))
+	assert.True(t, IsTransient(dialErr))
+	assert.True(t, IsTransient(pkgerrors.Wrap(dialErr, "failed to connect")))
+	assert.True(t, IsTransient(status.Error(codes.Unavailable, "unavailable")))
+
+	assert.False(t, IsTransient(nil))
+	assert.False(t, IsTransient(errors.New("invalid input")))
+	assert.False(t, IsTransient(status.Error(codes.InvalidArgument, "invalid input")))
+	assert.Nil(t, Transient(nil))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 4*time.Second, policy.delay(3))
+	assert.Equal(t, 5*time.Second, policy.delay(4))
+	assert.Equal(t, 5*time.Second, policy.delay(100))
+	assert.Equal(t, DefaultRetryPolicy.BaseDelay, (&RetryPolicy{}).delay(1))
+	assert.Equal(t, DefaultRetryPolicy.MaxAttempts, (&RetryPolicy{}).maxAttempts())
+}
+
+func TestLocalExecutorRetry(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	executor.Start()
+	defer executor.Close()
+	retried := testutil.ToFloat64(retriedTasks)
+	exhausted := testutil.ToFloat64(exhaustedTasks)
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	// A task that fails with a transient error is retried until it succeeds.
+	attempts := atomic.NewInt32(0)
+	succeeded := make(chan struct{})
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "flaky",
+		GroupID: "a",
+		Retry:   policy,
+		Apply: func() error {
+			if attempts.Inc() < 3 {
+				return Transient(errors.New("unavailable"))
+			}
+			close(succeeded)
+			return nil
+		},
+	}))
+	select {
+	case <-succeeded:
+	case <-time.After(time.Second):
+		t.Fatal("the task was not retried")
+	}
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, retried+2, testutil.ToFloat64(retriedTasks))
+	waitForGroup(t, executor, "a")
+
+	// A task is not retried beyond its maximum number of attempts.
+	attempts.Store(0)
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "broken",
+		GroupID: "b",
+		Retry:   policy,
+		Apply: func() error {
+			attempts.Inc()
+			return Transient(errors.New("unavailable"))
+		},
+	}))
+	waitForGroup(t, executor, "b")
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, exhausted+1, testutil.ToFloat64(exhaustedTasks))
+
+	// Errors that are not transient, and tasks without a retry policy, are not retried.
+	attempts.Store(0)
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "invalid",
+		GroupID: "c",
+		Retry:   policy,
+		Apply: func() error {
+			attempts.Inc()
+			return errors.New("invalid input")
+		},
+	}))
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "once",
+		GroupID: "c",
+		Apply: func() error {
+			attempts.Inc()
+			return Transient(errors.New("unavailable"))
+		},
+	}))
+	waitForGroup(t, executor, "c")
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+func TestLocalExecutorRetryPending(t *testing.T) {
+	executor := NewLocalExecutor(1, 10)
+	executor.Start()
+	defer executor.Close()
+
+	// While the task waits for its retry, it is still pending, so that it is not submitted again.
+	attempts := atomic.NewInt32(0)
+	assert.Equal(t, TaskAccepted, executor.Submit(&Task{
+		TaskID:  "flaky",
+		GroupID: "a",
+		Retry:   &RetryPolicy{MaxAttempts: 2, BaseDelay: 200 * time.Millisecond},
+		Apply: func() error {
+			if attempts.Inc() == 1 {
+				return Transient(errors.New("unavailable"))
+			}
+			return nil
+		},
+	}))
+	for deadline := time.Now().Add(time.Second); attempts.Load() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("the task was not executed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, 1, executor.GetGroupTasks("a"))
+	assert.Equal(t, TaskDeduplicated, executor.Submit(&Task{
+		TaskID:  "flaky",
+		GroupID: "a",
+		Apply:   func() error { return nil },
+	}))
+	waitForGroup(t, executor, "a")
+	assert.Equal(t, int32(2), attempts.Load())
+}
+
+// waitForGroup waits until the group has no queued or executing tasks.
+func waitForGroup(t *testing.T, executor *LocalExecutor, groupID interface{}) {
+	for deadline := time.Now().Add(time.Second); executor.GetGroupTasks(groupID) > 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("group %v still has pending tasks", groupID)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -3,12 +3,14 @@ package expr
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/fatih/structs"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/robertkrimen/otto"
+	"github.com/robertkrimen/otto/parser"
 	"github.com/sirupsen/logrus"
 
 	// Import the underscore library for the Otto JavaScript engine.
@@ -16,32 +18,157 @@ import (
 )
 
 const (
-	varScope         = "$"
-	varCurrentTask   = "taskId"
-	ResolvingTimeout = time.Duration(100) * time.Millisecond
+	varScope           = "$"
+	varCurrentTask     = "taskId"
+	ResolvingTimeout   = time.Duration(100) * time.Millisecond
+	MetadataLanguage   = "lang"
+	LanguageJavascript = "javascript"
+	LanguageJq         = "jq"
+	LanguageStarlark   = "starlark"
+	languageSeparator  = ":"
 )
 
 var (
-	ErrTimeOut      = errors.New("expression resolver timed out")
-	DefaultResolver = NewJavascriptExpressionParser()
+	ErrTimeOut               = errors.New("expression resolver timed out")
+	DefaultResolver Resolver = NewResolver(DefaultLimits)
 )
 
+// NewResolver creates a resolver that supports all expression languages, of which JavaScript is the default language.
+// The resolution of each expression is bounded by the limits.
+func NewResolver(limits Limits, starlarkOpts ...StarlarkOption) *MultiLanguageResolver {
+	limits = limits.WithDefaults()
+	js := NewJavascriptExpressionParser()
+	js.limits = limits
+	jq := NewJqExpressionParser()
+	jq.limits = limits
+	starlark := NewStarlarkExpressionParser(starlarkOpts...)
+	starlark.limits = limits
+	return NewMultiLanguageResolver(LanguageJavascript, map[string]Resolver{
+		LanguageJavascript: js,
+		LanguageJq:         jq,
+		LanguageStarlark:   starlark,
+	})
+}
+
 func Resolve(rootScope interface{}, currentTask string, expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 	return DefaultResolver.Resolve(rootScope, currentTask, expr)
 }
 
+// Parse checks the syntax of the expressions in the value using the default resolver, without resolving them.
+func Parse(expr *typedvalues.TypedValue) error {
+	if p, ok := DefaultResolver.(Parser); ok {
+		return p.Parse(expr)
+	}
+	return nil
+}
+
 // resolver resolves an expression within a given context/scope.
 type Resolver interface {
 	Resolve(rootScope interface{}, currentTask string, expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error)
 }
 
+// Parser is implemented by the resolvers that are able to check the syntax of expressions without resolving them.
+type Parser interface {
+	// Parse returns an error if the expression, or one of the expressions nested in a list or map, is invalid.
+	Parse(expr *typedvalues.TypedValue) error
+}
+
+// MultiLanguageResolver resolves expressions using the resolver of the language of the expression.
+//
+// The language of an expression is determined by the 'lang' metadata of the typed value, or by a language prefix in
+// the expression itself (e.g. "{ jq: .Tasks.foo.Output }"). Expressions without a language are resolved using the
+// default language.
+type MultiLanguageResolver struct {
+	defaultLanguage string
+	languages       map[string]Resolver
+}
+
+func NewMultiLanguageResolver(defaultLanguage string, languages map[string]Resolver) *MultiLanguageResolver {
+	if _, ok := languages[defaultLanguage]; !ok {
+		panic(fmt.Sprintf("no resolver for default language '%s'", defaultLanguage))
+	}
+	return &MultiLanguageResolver{
+		defaultLanguage: defaultLanguage,
+		languages:       languages,
+	}
+}
+
+func (mr *MultiLanguageResolver) Resolve(rootScope interface{}, currentTask string,
+	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+
+	switch expr.ValueType() {
+	case typedvalues.TypeList:
+		return resolveList(mr, rootScope, currentTask, expr)
+	case typedvalues.TypeMap:
+		return resolveMap(mr, rootScope, currentTask, expr)
+	case typedvalues.TypeExpression:
+		lang, stripped, err := mr.detectLanguage(expr)
+		if err != nil {
+			return nil, err
+		}
+		return mr.languages[lang].Resolve(rootScope, currentTask, stripped)
+	default:
+		return expr, nil
+	}
+}
+
+func (mr *MultiLanguageResolver) Parse(expr *typedvalues.TypedValue) error {
+	switch expr.ValueType() {
+	case typedvalues.TypeList, typedvalues.TypeMap:
+		return parseNested(mr, expr)
+	case typedvalues.TypeExpression:
+		lang, stripped, err := mr.detectLanguage(expr)
+		if err != nil {
+			return err
+		}
+		if p, ok := mr.languages[lang].(Parser); ok {
+			return p.Parse(stripped)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// detectLanguage determines the language of the expression, and returns the expression without the language prefix.
+func (mr *MultiLanguageResolver) detectLanguage(expr *typedvalues.TypedValue) (string, *typedvalues.TypedValue,
+	error) {
+	e, err := typedvalues.UnwrapExpression(expr)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to format expression for resolving (%v)", err)
+	}
+	body := strings.TrimSpace(typedvalues.RemoveExpressionDelimiters(e))
+	for lang := range mr.languages {
+		prefix := lang + languageSeparator
+		if strings.HasPrefix(body, prefix) {
+			stripped, err := typedvalues.Wrap("{" + strings.TrimPrefix(body, prefix) + "}")
+			if err != nil {
+				return "", nil, err
+			}
+			for k, v := range expr.GetMetadata() {
+				stripped.SetMetadata(k, v)
+			}
+			return lang, stripped, nil
+		}
+	}
+
+	if lang := expr.GetMetadata()[MetadataLanguage]; len(lang) > 0 {
+		if _, ok := mr.languages[lang]; !ok {
+			return "", nil, fmt.Errorf("unknown expression language '%s'", lang)
+		}
+		return lang, expr, nil
+	}
+	return mr.defaultLanguage, expr, nil
+}
+
 // Function is an interface for providing functions that are able to be injected into the Otto runtime.
 type Function interface {
 	Apply(vm *otto.Otto, call otto.FunctionCall) otto.Value
 }
 
 type JavascriptExpressionParser struct {
-	vm *otto.Otto
+	vm     *otto.Otto
+	limits Limits
 }
 
 func NewJavascriptExpressionParser() *JavascriptExpressionParser {
@@ -49,7 +176,8 @@ func NewJavascriptExpressionParser() *JavascriptExpressionParser {
 
 	// Load expression functions into Otto
 	return &JavascriptExpressionParser{
-		vm: vm,
+		vm:     vm,
+		limits: DefaultLimits,
 	}
 }
 
@@ -58,9 +186,9 @@ func (oe *JavascriptExpressionParser) Resolve(rootScope interface{}, currentTask
 
 	switch expr.ValueType() {
 	case typedvalues.TypeList:
-		return oe.resolveList(rootScope, currentTask, expr)
+		return resolveList(oe, rootScope, currentTask, expr)
 	case typedvalues.TypeMap:
-		return oe.resolveMap(rootScope, currentTask, expr)
+		return resolveMap(oe, rootScope, currentTask, expr)
 	case typedvalues.TypeExpression:
 		return oe.resolveExpr(rootScope, currentTask, expr)
 	default:
@@ -68,8 +196,24 @@ func (oe *JavascriptExpressionParser) Resolve(rootScope interface{}, currentTask
 	}
 }
 
+func (oe *JavascriptExpressionParser) Parse(expr *typedvalues.TypedValue) error {
+	switch expr.ValueType() {
+	case typedvalues.TypeList, typedvalues.TypeMap:
+		return parseNested(oe, expr)
+	case typedvalues.TypeExpression:
+		e, err := typedvalues.UnwrapExpression(expr)
+		if err != nil {
+			return fmt.Errorf("failed to format expression for parsing (%v)", err)
+		}
+		_, err = parser.ParseFile(nil, "", typedvalues.RemoveExpressionDelimiters(e), 0)
+		return err
+	default:
+		return nil
+	}
+}
+
 func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, currentTask string,
-	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	expr *typedvalues.TypedValue) (result *typedvalues.TypedValue, err error) {
 
 	if expr.ValueType() != typedvalues.TypeExpression {
 		return nil, errors.New("expected expression to resolve")
@@ -80,13 +224,17 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 			if ErrTimeOut != caught {
 				panic(caught)
 			}
+			result = nil
+			err = &LimitExceededError{Limit: "timeout", Max: oe.limits.Timeout}
 		}
 	}()
 
 	// Setup the JavaScript interpreter
 	scoped := oe.vm.Copy()
+	scoped.Interrupt = make(chan func(), 1)
 	injectFunctions(scoped, BuiltinFunctions)
-	err := scoped.Set(varScope, rootScope)
+	injectFunctions(scoped, javascriptHelpers())
+	err = scoped.Set(varScope, rootScope)
 	if err != nil {
 		return nil, err
 	}
@@ -95,8 +243,14 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 		return nil, err
 	}
 
+	done := make(chan struct{})
+	defer close(done)
 	go func() {
-		<-time.After(ResolvingTimeout)
+		select {
+		case <-time.After(oe.limits.Timeout):
+		case <-done:
+			return
+		}
 		select {
 		case scoped.Interrupt <- func() {
 			panic(ErrTimeOut)
@@ -125,15 +279,19 @@ func (oe *JavascriptExpressionParser) resolveExpr(rootScope interface{}, current
 		i = mp
 	}
 
-	result, err := typedvalues.Wrap(i)
+	result, err = typedvalues.Wrap(i)
 	if err != nil {
 		return nil, err
 	}
+	if err := oe.limits.checkOutputSize(result); err != nil {
+		return nil, err
+	}
 	result.SetMetadata("src", e)
 	return result, nil
 }
 
-func (oe *JavascriptExpressionParser) resolveMap(rootScope interface{}, currentTask string,
+// resolveMap resolves each of the values of the map using the resolver.
+func resolveMap(resolver Resolver, rootScope interface{}, currentTask string,
 	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 
 	if expr.ValueType() != typedvalues.TypeMap {
@@ -154,7 +312,7 @@ func (oe *JavascriptExpressionParser) resolveMap(rootScope interface{}, currentT
 			return nil, err
 		}
 
-		resolved, err := oe.Resolve(rootScope, currentTask, field)
+		resolved, err := resolver.Resolve(rootScope, currentTask, field)
 		if err != nil {
 			return nil, err
 		}
@@ -168,7 +326,8 @@ func (oe *JavascriptExpressionParser) resolveMap(rootScope interface{}, currentT
 	return typedvalues.Wrap(result)
 }
 
-func (oe *JavascriptExpressionParser) resolveList(rootScope interface{}, currentTask string,
+// resolveList resolves each of the elements of the list using the resolver.
+func resolveList(resolver Resolver, rootScope interface{}, currentTask string,
 	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 
 	if expr.ValueType() != typedvalues.TypeList {
@@ -189,7 +348,7 @@ func (oe *JavascriptExpressionParser) resolveList(rootScope interface{}, current
 			return nil, err
 		}
 
-		resolved, err := oe.Resolve(rootScope, currentTask, field)
+		resolved, err := resolver.Resolve(rootScope, currentTask, field)
 		if err != nil {
 			return nil, err
 		}
@@ -203,6 +362,34 @@ func (oe *JavascriptExpressionParser) resolveList(rootScope interface{}, current
 	return typedvalues.Wrap(result)
 }
 
+// parseNested parses each of the values of the list or map using the parser.
+func parseNested(p Parser, expr *typedvalues.TypedValue) error {
+	i, err := typedvalues.Unwrap(expr)
+	if err != nil {
+		return err
+	}
+
+	var values []interface{}
+	switch v := i.(type) {
+	case []interface{}:
+		values = v
+	case map[string]interface{}:
+		for _, value := range v {
+			values = append(values, value)
+		}
+	}
+	for _, v := range values {
+		field, err := typedvalues.Wrap(v)
+		if err != nil {
+			return err
+		}
+		if err := p.Parse(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func injectFunctions(vm *otto.Otto, fns map[string]Function) {
 	for varName := range fns {
 		func(fnName string) {
@@ -85,6 +85,27 @@ func TestResolveInjectedFunction(t *testing.T) {
 	assert.NotEmpty(t, resolvedString)
 }
 
+func TestParse(t *testing.T) {
+	assert.NoError(t, Parse(mustParseExpr("{$.Tasks.foo.Output}")))
+	assert.NoError(t, Parse(mustParseExpr("{jq: .Tasks.foo.Output}")))
+	assert.NoError(t, Parse(mustParseExpr("{starlark: output('foo')}")))
+	assert.NoError(t, Parse(mustParseExpr("{starlark: x = output('foo'); result = x}")))
+	assert.NoError(t, Parse(typedvalues.MustWrap("literal")))
+
+	assert.Error(t, Parse(mustParseExpr("{$.Tasks.foo.}")))
+	assert.Error(t, Parse(mustParseExpr("{jq: .Tasks.foo | }")))
+	assert.Error(t, Parse(mustParseExpr("{starlark: output('foo'}")))
+	unknownLang := mustParseExpr("{foo}")
+	unknownLang.SetMetadata(MetadataLanguage, "cobol")
+	assert.Error(t, Parse(unknownLang))
+
+	// Expressions nested in lists and maps are parsed as well.
+	assert.Error(t, Parse(typedvalues.MustWrap(map[string]interface{}{
+		"valid":   "{$.Tasks.foo.Output}",
+		"invalid": []interface{}{"{$.Tasks.foo.}"},
+	})))
+}
+
 func mustParseExpr(s string) *typedvalues.TypedValue {
 	tv := typedvalues.MustWrap(s)
 	if tv.ValueType() != typedvalues.TypeExpression {
@@ -0,0 +1,179 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"plugin"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/itchyny/gojq"
+	"github.com/robertkrimen/otto"
+	"go.starlark.net/starlark"
+)
+
+const (
+	// HelperPluginSymbol is the symbol that a helper plugin should export. It should be a variable of the type
+	// map[string]func(args ...interface{}) (interface{}, error).
+	HelperPluginSymbol = "Helpers"
+	jqMaxHelperArity   = 30
+)
+
+var (
+	ErrInvalidHelperName = errors.New("helper name should be a valid identifier")
+	ErrHelperExists      = errors.New("helper with the same name already exists")
+
+	helperNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	helpers           = map[string]HelperFunc{}
+	helpersVersion    int
+	helpersMu         sync.RWMutex
+)
+
+// HelperFunc is a user-provided function that can be called from expressions. The arguments and the result are
+// JSON-like values: maps, lists, strings, numbers, booleans, and nils.
+type HelperFunc func(args ...interface{}) (interface{}, error)
+
+// RegisterHelper adds the helper function to the scope of the expressions of all languages. Helpers cannot replace
+// the built-in functions or other helpers. Helpers should be registered before expressions are resolved.
+func RegisterHelper(name string, fn HelperFunc) error {
+	if !helperNamePattern.MatchString(name) {
+		return ErrInvalidHelperName
+	}
+	helpersMu.Lock()
+	defer helpersMu.Unlock()
+	if _, ok := BuiltinFunctions[name]; ok {
+		return ErrHelperExists
+	}
+	if _, ok := helpers[name]; ok {
+		return ErrHelperExists
+	}
+	helpers[name] = fn
+	helpersVersion++
+	return nil
+}
+
+// Helpers returns the names of the registered helper functions.
+func Helpers() []string {
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+	var names []string
+	for name := range helpers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadHelperPlugin registers the helpers exported by the Go plugin at the path. The plugin should export the
+// HelperPluginSymbol variable.
+func LoadHelperPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open helper plugin: %v", err)
+	}
+	sym, err := p.Lookup(HelperPluginSymbol)
+	if err != nil {
+		return fmt.Errorf("failed to load helper plugin %s: %v", path, err)
+	}
+	fns, ok := sym.(*map[string]func(args ...interface{}) (interface{}, error))
+	if !ok {
+		return fmt.Errorf("helper plugin %s: symbol %s has invalid type %T", path, HelperPluginSymbol, sym)
+	}
+	for name, fn := range *fns {
+		if err := RegisterHelper(name, fn); err != nil {
+			return fmt.Errorf("helper plugin %s: failed to register '%s': %v", path, name, err)
+		}
+	}
+	return nil
+}
+
+func getHelpers() (map[string]HelperFunc, int) {
+	helpersMu.RLock()
+	defer helpersMu.RUnlock()
+	fns := make(map[string]HelperFunc, len(helpers))
+	for name, fn := range helpers {
+		fns[name] = fn
+	}
+	return fns, helpersVersion
+}
+
+// helperFunction adapts a helper to the JavaScript runtime.
+type helperFunction struct {
+	name string
+	fn   HelperFunc
+}
+
+func (hf *helperFunction) Apply(vm *otto.Otto, call otto.FunctionCall) otto.Value {
+	args := make([]interface{}, len(call.ArgumentList))
+	for i, arg := range call.ArgumentList {
+		args[i], _ = arg.Export() // Err is always nil
+	}
+	result, err := hf.fn(args...)
+	if err != nil {
+		panic(vm.MakeCustomError("HelperError", fmt.Sprintf("%s: %v", hf.name, err)))
+	}
+	value, err := vm.ToValue(result)
+	if err != nil {
+		panic(vm.MakeCustomError("HelperError", fmt.Sprintf("%s: invalid result: %v", hf.name, err)))
+	}
+	return value
+}
+
+// javascriptHelpers returns the helpers as JavaScript functions.
+func javascriptHelpers() map[string]Function {
+	fns, _ := getHelpers()
+	result := make(map[string]Function, len(fns))
+	for name, fn := range fns {
+		result[name] = &helperFunction{name: name, fn: fn}
+	}
+	return result
+}
+
+// jqHelpers returns the helpers as jq compiler options. The input of the filter is ignored; the arguments are passed
+// to the helper.
+func jqHelpers() []gojq.CompilerOption {
+	fns, _ := getHelpers()
+	var opts []gojq.CompilerOption
+	for name, fn := range fns {
+		fn := fn
+		opts = append(opts, gojq.WithFunction(name, 0, jqMaxHelperArity, func(_ interface{},
+			args []interface{}) interface{} {
+			result, err := fn(args...)
+			if err != nil {
+				return err
+			}
+			return result
+		}))
+	}
+	return opts
+}
+
+// starlarkHelpers returns the helpers as Starlark builtins.
+func starlarkHelpers() starlark.StringDict {
+	fns, _ := getHelpers()
+	result := make(starlark.StringDict, len(fns))
+	for name, fn := range fns {
+		fn := fn
+		result[name] = starlark.NewBuiltin(name, func(thread *starlark.Thread, b *starlark.Builtin,
+			starlarkArgs starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			if len(kwargs) > 0 {
+				return nil, fmt.Errorf("%s: keyword arguments are not supported", b.Name())
+			}
+			args := make([]interface{}, len(starlarkArgs))
+			for i, arg := range starlarkArgs {
+				v, err := fromStarlarkValue(thread, arg)
+				if err != nil {
+					return nil, err
+				}
+				args[i] = v
+			}
+			result, err := fn(args...)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", b.Name(), err)
+			}
+			return toStarlarkValue(thread, result)
+		})
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func init() {
+	lookup := map[string]interface{}{
+		"foo": "bar",
+	}
+	err := RegisterHelper("testLookup", func(args ...interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, errors.New("expected 1 argument")
+		}
+		return lookup[fmt.Sprintf("%v", args[0])], nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = RegisterHelper("testJoin", func(args ...interface{}) (interface{}, error) {
+		var parts []string
+		for _, arg := range args {
+			parts = append(parts, fmt.Sprintf("%v", arg))
+		}
+		return strings.Join(parts, "-"), nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterHelperInvalid(t *testing.T) {
+	fn := func(args ...interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	assert.Equal(t, ErrInvalidHelperName, RegisterHelper("foo-bar", fn))
+	assert.Equal(t, ErrInvalidHelperName, RegisterHelper("", fn))
+	assert.Equal(t, ErrHelperExists, RegisterHelper("uid", fn))
+	assert.Equal(t, ErrHelperExists, RegisterHelper("testLookup", fn))
+	assert.Contains(t, Helpers(), "testLookup")
+}
+
+func TestHelpers(t *testing.T) {
+	resolver := NewResolver(DefaultLimits)
+	testScope := makeTestScope()
+
+	testCases := []struct {
+		expr     string
+		expected interface{}
+	}{
+		{"{ testLookup('foo') }", "bar"},
+		{"{ testJoin('a', output('TaskA')) }", "a-some output"},
+		{"{ jq: testLookup(\"foo\") }", "bar"},
+		{"{ jq: testJoin(\"a\"; output(\"TaskA\")) }", "a-some output"},
+		{"{ starlark: testLookup('foo') }", "bar"},
+		{"{ starlark: testJoin('a', output('TaskA')) }", "a-some output"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			result, err := resolver.Resolve(testScope, "", mustParseExpr(testCase.expr))
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, typedvalues.MustUnwrap(result))
+		})
+	}
+}
+
+func TestHelpersError(t *testing.T) {
+	resolver := NewResolver(DefaultLimits)
+	testScope := makeTestScope()
+
+	for _, expr := range []string{
+		"{ testLookup() }",
+		"{ jq: testLookup }",
+		"{ starlark: testLookup() }",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := resolver.Resolve(testScope, "", mustParseExpr(expr))
+			assert.Error(t, err)
+		})
+	}
+}
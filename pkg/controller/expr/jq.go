@@ -0,0 +1,172 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/hashicorp/golang-lru"
+	"github.com/itchyny/gojq"
+)
+
+const (
+	jqVarCurrentTask = "$" + varCurrentTask
+	jqMaxCachedCode  = 1000
+)
+
+// jqPrelude defines the jq equivalents of the built-in functions of the JavaScript expressions.
+const jqPrelude = `
+def task: .Tasks[$taskId];
+def task($id): .Tasks[$id];
+def output: task.Output;
+def output($id): task($id).Output;
+def outputHeaders: task.OutputHeaders;
+def outputHeaders($id): task($id).OutputHeaders;
+def input: task.Inputs.default;
+def input($id): task($id).Inputs.default;
+def input($id; $key): task($id).Inputs[$key];
+def param: .Invocation.Inputs.default;
+def param($key): .Invocation.Inputs[$key];
+`
+
+// JqExpressionParser resolves jq expressions, which are well-suited for extracting and transforming data.
+//
+// The expression is evaluated with the JSON representation of the scope as input; the current task is available as
+// the $taskId variable. Besides the jq builtins, the expression can use the task, output, outputHeaders, input, and
+// param functions, which behave like their JavaScript counterparts. If the expression produces multiple results, the
+// results are returned as a list.
+type JqExpressionParser struct {
+	cache  *lru.Cache // map[string]*gojq.Code
+	limits Limits
+}
+
+func NewJqExpressionParser() *JqExpressionParser {
+	cache, err := lru.New(jqMaxCachedCode)
+	if err != nil {
+		panic(err)
+	}
+	return &JqExpressionParser{
+		cache:  cache,
+		limits: DefaultLimits,
+	}
+}
+
+func (jp *JqExpressionParser) Resolve(rootScope interface{}, currentTask string,
+	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+
+	switch expr.ValueType() {
+	case typedvalues.TypeList:
+		return resolveList(jp, rootScope, currentTask, expr)
+	case typedvalues.TypeMap:
+		return resolveMap(jp, rootScope, currentTask, expr)
+	case typedvalues.TypeExpression:
+		return jp.resolveExpr(rootScope, currentTask, expr)
+	default:
+		return expr, nil
+	}
+}
+
+func (jp *JqExpressionParser) Parse(expr *typedvalues.TypedValue) error {
+	switch expr.ValueType() {
+	case typedvalues.TypeList, typedvalues.TypeMap:
+		return parseNested(jp, expr)
+	case typedvalues.TypeExpression:
+		e, err := typedvalues.UnwrapExpression(expr)
+		if err != nil {
+			return fmt.Errorf("failed to format expression for parsing (%v)", err)
+		}
+		_, err = jp.compile(typedvalues.RemoveExpressionDelimiters(e))
+		return err
+	default:
+		return nil
+	}
+}
+
+func (jp *JqExpressionParser) resolveExpr(rootScope interface{}, currentTask string,
+	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+
+	e, err := typedvalues.UnwrapExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format expression for resolving (%v)", err)
+	}
+	code, err := jp.compile(typedvalues.RemoveExpressionDelimiters(e))
+	if err != nil {
+		return nil, err
+	}
+	input, err := toJSONValue(rootScope)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jp.limits.Timeout)
+	defer cancel()
+	var results []interface{}
+	iter := code.RunWithContext(ctx, input, currentTask)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, &LimitExceededError{Limit: "timeout", Max: jp.limits.Timeout}
+			}
+			return nil, err
+		}
+		results = append(results, v)
+	}
+
+	var i interface{}
+	switch len(results) {
+	case 0:
+	case 1:
+		i = results[0]
+	default:
+		i = results
+	}
+	result, err := typedvalues.Wrap(i)
+	if err != nil {
+		return nil, err
+	}
+	if err := jp.limits.checkOutputSize(result); err != nil {
+		return nil, err
+	}
+	result.SetMetadata("src", e)
+	return result, nil
+}
+
+func (jp *JqExpressionParser) compile(expr string) (*gojq.Code, error) {
+	// The compiled code depends on the registered helpers, so recompile the expression if the helpers changed.
+	_, version := getHelpers()
+	key := fmt.Sprintf("%d:%s", version, expr)
+	if cached, ok := jp.cache.Get(key); ok {
+		return cached.(*gojq.Code), nil
+	}
+	query, err := gojq.Parse(jqPrelude + expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jq expression: %v", err)
+	}
+	opts := append([]gojq.CompilerOption{gojq.WithVariables([]string{jqVarCurrentTask})}, jqHelpers()...)
+	code, err := gojq.Compile(query, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile jq expression: %v", err)
+	}
+	jp.cache.Add(key, code)
+	return code, nil
+}
+
+// toJSONValue converts the value into the generic JSON representation (maps, lists, float64s, strings, bools, and nils)
+// that the jq evaluator operates on.
+func toJSONValue(i interface{}) (interface{}, error) {
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return nil, errors.New("scope cannot be represented as JSON: " + err.Error())
+	}
+	var value interface{}
+	if err := json.Unmarshal(bs, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
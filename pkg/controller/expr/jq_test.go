@@ -0,0 +1,89 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJqExpressionParser_Resolve(t *testing.T) {
+	parser := NewJqExpressionParser()
+	testScope := makeTestScope()
+
+	testCases := []struct {
+		expr     string
+		task     string
+		expected interface{}
+	}{
+		{"{ .Tasks.TaskA.Output }", "", "some output"},
+		{"{ output(\"TaskA\") | ascii_upcase }", "", "SOME OUTPUT"},
+		{"{ output }", "TaskA", "some output"},
+		{"{ outputHeaders(\"TaskA\")[\"some-key\"] }", "", "some-value"},
+		{"{ input(\"TaskA\"; \"otherInput\") }", "", "input-otherInput"},
+		{"{ param(\"headers\") }", "", "http-headers"},
+		{"{ $taskId }", "TaskA", "TaskA"},
+		{"{ .Tasks | keys }", "", []interface{}{"TaskA"}},
+		{"{ 1, 2 }", "", []interface{}{int32(1), int32(2)}},
+		{"{ empty }", "", nil},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			result, err := parser.Resolve(testScope, testCase.task, mustParseExpr(testCase.expr))
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, typedvalues.MustUnwrap(result))
+		})
+	}
+}
+
+func TestJqExpressionParser_ResolveInvalid(t *testing.T) {
+	parser := NewJqExpressionParser()
+
+	_, err := parser.Resolve(rootScope, "", mustParseExpr("{ .foo | }"))
+	assert.Error(t, err)
+
+	_, err = parser.Resolve(rootScope, "", mustParseExpr("{ error(\"foo\") }"))
+	assert.Error(t, err)
+}
+
+func TestMultiLanguageResolver_Resolve(t *testing.T) {
+	resolver := DefaultResolver
+
+	// Default language
+	resolved, err := resolver.Resolve(rootScope, "", mustParseExpr("{ $.currentScope.bit.toUpperCase() }"))
+	assert.NoError(t, err)
+	assert.Equal(t, "BAT", typedvalues.MustUnwrap(resolved))
+
+	// Language prefix
+	resolved, err = resolver.Resolve(rootScope, "", mustParseExpr("{ jq: .currentScope.bit | ascii_upcase }"))
+	assert.NoError(t, err)
+	assert.Equal(t, "BAT", typedvalues.MustUnwrap(resolved))
+
+	resolved, err = resolver.Resolve(rootScope, "", mustParseExpr("{ starlark: scope['foo'].upper() }"))
+	assert.NoError(t, err)
+	assert.Equal(t, "BAR", typedvalues.MustUnwrap(resolved))
+
+	// Language metadata
+	expr := mustParseExpr("{ .foo }")
+	expr.SetMetadata(MetadataLanguage, LanguageJq)
+	resolved, err = resolver.Resolve(rootScope, "", expr)
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", typedvalues.MustUnwrap(resolved))
+
+	// Mixed languages in a composite value
+	resolved, err = resolver.Resolve(rootScope, "", typedvalues.MustWrap(map[string]interface{}{
+		"js": "{ $.foo }",
+		"jq": []interface{}{"{ jq: .currentScope.bit }"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"js": "bar",
+		"jq": []interface{}{"bat"},
+	}, typedvalues.MustUnwrap(resolved))
+
+	// Unknown language
+	expr = mustParseExpr("{ .foo }")
+	expr.SetMetadata(MetadataLanguage, "cobol")
+	_, err = resolver.Resolve(rootScope, "", expr)
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+package expr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+)
+
+var DefaultLimits = Limits{
+	Timeout:       ResolvingTimeout,
+	MaxOutputSize: 1024 * 1024, // 1 MB
+	MaxSteps:      1e6,
+}
+
+// Limits bounds the resources that the resolution of a single expression can use.
+type Limits struct {
+	// Timeout is the maximum duration of the resolution of an expression.
+	Timeout time.Duration
+
+	// MaxOutputSize is the maximum size (in bytes) of the output of an expression.
+	MaxOutputSize int
+
+	// MaxSteps is the maximum number of instructions that the interpreter executes for an expression.
+	// Currently, this is only enforced for Starlark expressions, as the other interpreters do not support this.
+	MaxSteps uint64
+}
+
+// WithDefaults returns a copy of the limits, in which the unset (zero) limits are replaced by the default limits.
+func (l Limits) WithDefaults() Limits {
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultLimits.Timeout
+	}
+	if l.MaxOutputSize <= 0 {
+		l.MaxOutputSize = DefaultLimits.MaxOutputSize
+	}
+	if l.MaxSteps == 0 {
+		l.MaxSteps = DefaultLimits.MaxSteps
+	}
+	return l
+}
+
+// LimitExceededError is returned when the resolution of an expression exceeds one of its limits.
+type LimitExceededError struct {
+	Limit string
+	Max   interface{}
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("expression exceeded the %s limit (%v)", e.Limit, e.Max)
+}
+
+// checkOutputSize returns an error if the resolved expression exceeds the maximum output size.
+func (l Limits) checkOutputSize(result *typedvalues.TypedValue) error {
+	if size := proto.Size(result); size > l.MaxOutputSize {
+		return &LimitExceededError{
+			Limit: "output size",
+			Max:   fmt.Sprintf("%d > %d bytes", size, l.MaxOutputSize),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package expr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimits_Timeout(t *testing.T) {
+	resolver := NewResolver(Limits{Timeout: 10 * time.Millisecond})
+
+	testCases := []string{
+		"{ while(true) {} }",
+		"{ jq: [range(1e9)] | length }",
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := resolver.Resolve(rootScope, "", mustParseExpr(testCase))
+			assert.IsType(t, &LimitExceededError{}, err)
+			assert.Contains(t, err.Error(), "timeout")
+		})
+	}
+}
+
+func TestLimits_MaxOutputSize(t *testing.T) {
+	resolver := NewResolver(Limits{MaxOutputSize: 100})
+
+	testCases := []string{
+		"{ new Array(200).join('a') }",
+		"{ jq: [range(200)] | map(\"a\") | join(\"\") }",
+		"{ starlark: 'a' * 200 }",
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := resolver.Resolve(rootScope, "", mustParseExpr(testCase))
+			assert.IsType(t, &LimitExceededError{}, err)
+			assert.Contains(t, err.Error(), "output size")
+		})
+	}
+
+	_, err := resolver.Resolve(rootScope, "", mustParseExpr("{ 'a' }"))
+	assert.NoError(t, err)
+}
+
+func TestLimits_MaxSteps(t *testing.T) {
+	resolver := NewResolver(Limits{MaxSteps: 1000})
+
+	_, err := resolver.Resolve(rootScope, "", mustParseExpr("{ starlark: [x for x in range(10000)] }"))
+	assert.IsType(t, &LimitExceededError{}, err)
+	assert.True(t, strings.Contains(err.Error(), "execution steps"))
+
+	_, err = resolver.Resolve(rootScope, "", mustParseExpr("{ starlark: [x for x in range(10)] }"))
+	assert.NoError(t, err)
+}
@@ -26,6 +26,7 @@ type Scope struct {
 	Workflow   *WorkflowScope
 	Invocation *InvocationScope
 	Tasks      Tasks
+	Consts     map[string]interface{} // the constants of the workflow
 }
 
 func (s *Scope) DeepCopy() DeepCopier {
@@ -36,6 +37,7 @@ func (s *Scope) DeepCopy() DeepCopier {
 		Workflow:   s.Workflow.DeepCopy().(*WorkflowScope),
 		Invocation: s.Invocation.DeepCopy().(*InvocationScope),
 		Tasks:      s.Tasks.DeepCopy().(Tasks),
+		Consts:     DeepCopy(s.Consts).(map[string]interface{}),
 	}
 }
 
@@ -147,6 +149,11 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 	updated := &Scope{}
 	if wf := wfi.Workflow(); wf != nil {
 		updated.Workflow = formatWorkflow(wf)
+		consts, err := formatConsts(wf)
+		if err != nil {
+			return nil, err
+		}
+		updated.Consts = consts
 	}
 	if wfi != nil {
 		invocationParams, err := typedvalues.UnwrapMapTypedValue(wfi.Spec.Inputs)
@@ -163,33 +170,11 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 		if updated.Tasks == nil {
 			updated.Tasks = map[string]*TaskScope{}
 		}
-
-		// Dep: pipe output of dynamic tasks
-		t := controlflow.ResolveTaskOutput(taskId, wfi)
-		output, err := typedvalues.Unwrap(t)
+		taskScope, err := formatTask(taskId, task, wfi)
 		if err != nil {
-			panic(err)
-		}
-
-		h := controlflow.ResolveTaskOutputHeaders(taskId, wfi)
-		outputHeaders, err := typedvalues.Unwrap(h)
-		if err != nil {
-			panic(err)
-		}
-		inputs, err := typedvalues.UnwrapMapTypedValue(task.GetSpec().GetInputs())
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to format inputs of task %v", taskId)
-		}
-		updated.Tasks[taskId] = &TaskScope{
-			ObjectMetadata: formatMetadata(task.Metadata),
-			Status:         task.Status.Status.String(),
-			UpdatedAt:      formatTimestamp(task.Status.UpdatedAt),
-			Inputs:         inputs,
-			Requires:       task.GetSpec().GetRequires(),
-			Output:         output,
-			OutputHeaders:  outputHeaders,
-			Function:       task.GetSpec().GetFunctionRef(),
+			return nil, err
 		}
+		updated.Tasks[taskId] = taskScope
 	}
 
 	if base == nil {
@@ -205,6 +190,108 @@ func NewScope(base *Scope, wfi *types.WorkflowInvocation) (*Scope, error) {
 	return updated, nil
 }
 
+// inherit returns a copy of the scope in which the missing workflow, invocation inputs, constants, and tasks are taken
+// from the parent scope. Unlike NewScope, the tasks that are present in both scopes are not merged.
+func (s *Scope) inherit(parent *Scope) *Scope {
+	if parent == nil {
+		return s
+	}
+	inherited := &Scope{
+		Workflow:   s.Workflow,
+		Invocation: s.Invocation,
+		Tasks:      make(Tasks, len(s.Tasks)+len(parent.Tasks)),
+		Consts:     s.Consts,
+	}
+	if inherited.Workflow == nil {
+		inherited.Workflow = parent.Workflow
+	}
+	if inherited.Invocation == nil {
+		inherited.Invocation = parent.Invocation
+	} else if parent.Invocation != nil {
+		inputs := make(map[string]interface{}, len(s.Invocation.Inputs)+len(parent.Invocation.Inputs))
+		for k, v := range parent.Invocation.Inputs {
+			inputs[k] = v
+		}
+		for k, v := range s.Invocation.Inputs {
+			inputs[k] = v
+		}
+		inherited.Invocation = &InvocationScope{
+			ObjectMetadata: s.Invocation.ObjectMetadata,
+			Inputs:         inputs,
+		}
+	}
+	if len(parent.Consts) > 0 {
+		inherited.Consts = make(map[string]interface{}, len(s.Consts)+len(parent.Consts))
+		for k, v := range parent.Consts {
+			inherited.Consts[k] = v
+		}
+		for k, v := range s.Consts {
+			inherited.Consts[k] = v
+		}
+	}
+	for k, v := range parent.Tasks {
+		inherited.Tasks[k] = v
+	}
+	for k, v := range s.Tasks {
+		inherited.Tasks[k] = v
+	}
+	return inherited
+}
+
+// ShallowCopy returns a copy of the task scope that shares the values of the original, except for the map of inputs.
+// It allows the inputs, output and headers of the copy to be replaced without affecting the original.
+func (s *TaskScope) ShallowCopy() *TaskScope {
+	if s == nil {
+		return nil
+	}
+	copied := *s
+	copied.Inputs = make(map[string]interface{}, len(s.Inputs))
+	for k, v := range s.Inputs {
+		copied.Inputs[k] = v
+	}
+	return &copied
+}
+
+func formatTask(taskID string, task *types.Task, wfi *types.WorkflowInvocation) (*TaskScope, error) {
+	// Dep: pipe output of dynamic tasks
+	t := controlflow.ResolveTaskOutput(taskID, wfi)
+	output, err := typedvalues.Unwrap(t)
+	if err != nil {
+		panic(err)
+	}
+
+	h := controlflow.ResolveTaskOutputHeaders(taskID, wfi)
+	outputHeaders, err := typedvalues.Unwrap(h)
+	if err != nil {
+		panic(err)
+	}
+	inputs, err := typedvalues.UnwrapMapTypedValue(task.GetSpec().GetInputs())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to format inputs of task %v", taskID)
+	}
+	return &TaskScope{
+		ObjectMetadata: formatMetadata(task.Metadata),
+		Status:         task.Status.Status.String(),
+		UpdatedAt:      formatTimestamp(task.Status.UpdatedAt),
+		Inputs:         inputs,
+		Requires:       task.GetSpec().GetRequires(),
+		Output:         output,
+		OutputHeaders:  outputHeaders,
+		Function:       task.GetSpec().GetFunctionRef(),
+	}, nil
+}
+
+// taskVersion identifies the state of the task in the invocation that the scope of the task is formatted from. It
+// returns false if the scope of the task cannot be reused, because its output is taken from other (dynamic) tasks.
+func taskVersion(taskID string, task *types.Task, wfi *types.WorkflowInvocation) (string, bool) {
+	run, ok := wfi.TaskInvocation(taskID)
+	if ok && controlflow.IsControlFlow(run.GetStatus().GetOutput()) {
+		return "", false
+	}
+	return fmt.Sprintf("%s/%d/%s/%d", task.GetStatus().GetStatus(), formatTimestamp(task.GetStatus().GetUpdatedAt()),
+		run.GetStatus().GetStatus(), formatTimestamp(run.GetStatus().GetUpdatedAt())), true
+}
+
 func formatWorkflow(wf *types.Workflow) *WorkflowScope {
 	return &WorkflowScope{
 		ObjectMetadata: formatMetadata(wf.Metadata),
@@ -215,6 +302,19 @@ func formatWorkflow(wf *types.Workflow) *WorkflowScope {
 	}
 }
 
+// formatConsts unwraps the constants of the workflow. Without constants, the result is an empty map, so that
+// expressions can refer to missing constants.
+func formatConsts(wf *types.Workflow) (map[string]interface{}, error) {
+	consts, err := typedvalues.UnwrapMapTypedValue(wf.GetSpec().GetConsts())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format workflow constants")
+	}
+	if consts == nil {
+		consts = map[string]interface{}{}
+	}
+	return consts, nil
+}
+
 func formatMetadata(meta *types.ObjectMetadata) *ObjectMetadata {
 	if meta == nil {
 		return nil
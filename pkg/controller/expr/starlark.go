@@ -0,0 +1,243 @@
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"go.starlark.net/resolve"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+	"go.starlark.net/syntax"
+)
+
+const (
+	starlarkVarScope       = "scope"
+	starlarkVarCurrentTask = "task_id"
+	starlarkVarResult      = "result"
+	starlarkFilename       = "expression"
+)
+
+// starlarkPrelude defines the Starlark equivalents of the built-in functions of the JavaScript expressions.
+const starlarkPrelude = `
+def task(id = None):
+    return (scope.get("Tasks") or {}).get(task_id if id == None else id)
+
+def output(id = None):
+    return (task(id) or {}).get("Output")
+
+def outputHeaders(id = None):
+    return (task(id) or {}).get("OutputHeaders")
+
+def input(id = None, key = "default"):
+    return ((task(id) or {}).get("Inputs") or {}).get(key)
+
+def param(key = "default"):
+    return ((scope.get("Invocation") or {}).get("Inputs") or {}).get(key)
+`
+
+var ErrStarlarkNoResult = errors.New("starlark program did not assign a value to 'result'")
+
+var enableStarlarkDialect sync.Once
+
+// StarlarkOption configures a StarlarkExpressionParser.
+type StarlarkOption func(sp *StarlarkExpressionParser)
+
+// WithStarlarkDialect enables the dialect features that are needed for one-line programs and the JSON data model:
+// global reassignment, floats, sets and lambdas. Unbounded loops (while and recursion) remain disabled.
+//
+// This version of Starlark only supports these features as process-wide flags of its resolve package, so enabling them
+// changes the dialect for every other user of Starlark in the process as well.
+func WithStarlarkDialect() StarlarkOption {
+	return func(sp *StarlarkExpressionParser) {
+		enableStarlarkDialect.Do(func() {
+			resolve.AllowGlobalReassign = true
+			resolve.AllowFloat = true
+			resolve.AllowSet = true
+			resolve.AllowLambda = true
+		})
+	}
+}
+
+// StarlarkExpressionParser resolves Starlark expressions.
+//
+// Starlark is a deterministic dialect of Python, which offers loops and conditionals, without access to the
+// environment, clock, or randomness. The resolution is bounded in time and in the number of executed steps.
+//
+// The scope is available as the 'scope' dict, and the current task as the 'task_id' variable. Besides the json module,
+// the task, output, outputHeaders, input, and param functions are provided, which behave like their JavaScript
+// counterparts. The expression can either be a single Starlark expression, or a program of statements (separated by
+// semicolons) that assigns the output to the 'result' variable.
+type StarlarkExpressionParser struct {
+	limits Limits
+}
+
+func NewStarlarkExpressionParser(opts ...StarlarkOption) *StarlarkExpressionParser {
+	sp := &StarlarkExpressionParser{
+		limits: DefaultLimits,
+	}
+	for _, opt := range opts {
+		opt(sp)
+	}
+	return sp
+}
+
+func (sp *StarlarkExpressionParser) Resolve(rootScope interface{}, currentTask string,
+	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+
+	switch expr.ValueType() {
+	case typedvalues.TypeList:
+		return resolveList(sp, rootScope, currentTask, expr)
+	case typedvalues.TypeMap:
+		return resolveMap(sp, rootScope, currentTask, expr)
+	case typedvalues.TypeExpression:
+		return sp.resolveExpr(rootScope, currentTask, expr)
+	default:
+		return expr, nil
+	}
+}
+
+func (sp *StarlarkExpressionParser) Parse(expr *typedvalues.TypedValue) error {
+	switch expr.ValueType() {
+	case typedvalues.TypeList, typedvalues.TypeMap:
+		return parseNested(sp, expr)
+	case typedvalues.TypeExpression:
+		e, err := typedvalues.UnwrapExpression(expr)
+		if err != nil {
+			return fmt.Errorf("failed to format expression for parsing (%v)", err)
+		}
+		src := strings.TrimSpace(typedvalues.RemoveExpressionDelimiters(e))
+		if _, err := syntax.ParseExpr(starlarkFilename, src, 0); err == nil {
+			return nil
+		}
+		_, err = syntax.Parse(starlarkFilename, src, 0)
+		return err
+	default:
+		return nil
+	}
+}
+
+func (sp *StarlarkExpressionParser) resolveExpr(rootScope interface{}, currentTask string,
+	expr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+
+	e, err := typedvalues.UnwrapExpression(expr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format expression for resolving (%v)", err)
+	}
+	// Leading whitespace would be interpreted as indentation
+	src := strings.TrimSpace(typedvalues.RemoveExpressionDelimiters(e))
+
+	thread := &starlark.Thread{Name: currentTask}
+	thread.SetMaxExecutionSteps(sp.limits.MaxSteps)
+	timedOut := make(chan struct{})
+	timer := time.AfterFunc(sp.limits.Timeout, func() {
+		close(timedOut)
+		thread.Cancel(ErrTimeOut.Error())
+	})
+	defer timer.Stop()
+
+	env, err := sp.createEnv(thread, rootScope, currentTask)
+	if err != nil {
+		return nil, err
+	}
+
+	var value starlark.Value
+	if parsed, err := syntax.ParseExpr(starlarkFilename, src, 0); err == nil {
+		value, err = starlark.EvalExpr(thread, parsed, env)
+		if err != nil {
+			return nil, sp.wrapExecutionError(thread, timedOut, err)
+		}
+	} else {
+		globals, err := starlark.ExecFile(thread, starlarkFilename, src, env)
+		if err != nil {
+			return nil, sp.wrapExecutionError(thread, timedOut, err)
+		}
+		var ok bool
+		value, ok = globals[starlarkVarResult]
+		if !ok {
+			return nil, ErrStarlarkNoResult
+		}
+	}
+
+	i, err := fromStarlarkValue(thread, value)
+	if err != nil {
+		return nil, err
+	}
+	result, err := typedvalues.Wrap(i)
+	if err != nil {
+		return nil, err
+	}
+	if err := sp.limits.checkOutputSize(result); err != nil {
+		return nil, err
+	}
+	result.SetMetadata("src", e)
+	return result, nil
+}
+
+// wrapExecutionError replaces the cancellation errors of the thread with errors that describe the exceeded limit.
+func (sp *StarlarkExpressionParser) wrapExecutionError(thread *starlark.Thread, timedOut <-chan struct{},
+	err error) error {
+	select {
+	case <-timedOut:
+		return &LimitExceededError{Limit: "timeout", Max: sp.limits.Timeout}
+	default:
+	}
+	if thread.ExecutionSteps() >= sp.limits.MaxSteps {
+		return &LimitExceededError{Limit: "execution steps", Max: sp.limits.MaxSteps}
+	}
+	return err
+}
+
+// createEnv creates the predeclared environment of the expression, consisting of the scope, current task, the json
+// module, the registered helpers, and the prelude functions.
+func (sp *StarlarkExpressionParser) createEnv(thread *starlark.Thread, rootScope interface{},
+	currentTask string) (starlark.StringDict, error) {
+	scope, err := toStarlarkValue(thread, rootScope)
+	if err != nil {
+		return nil, err
+	}
+	env := starlark.StringDict{
+		starlarkVarScope:       scope,
+		starlarkVarCurrentTask: starlark.String(currentTask),
+		"json":                 starlarkjson.Module,
+	}
+	for k, v := range starlarkHelpers() {
+		env[k] = v
+	}
+	prelude, err := starlark.ExecFile(thread, "prelude", starlarkPrelude, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load starlark prelude: %v", err)
+	}
+	for k, v := range prelude {
+		env[k] = v
+	}
+	return env, nil
+}
+
+// toStarlarkValue converts the value to Starlark values using its JSON representation.
+func toStarlarkValue(thread *starlark.Thread, i interface{}) (starlark.Value, error) {
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return nil, errors.New("scope cannot be represented as JSON: " + err.Error())
+	}
+	decode := starlarkjson.Module.Members["decode"]
+	return starlark.Call(thread, decode, starlark.Tuple{starlark.String(bs)}, nil)
+}
+
+// fromStarlarkValue converts the Starlark value to the generic JSON representation.
+func fromStarlarkValue(thread *starlark.Thread, value starlark.Value) (interface{}, error) {
+	encode := starlarkjson.Module.Members["encode"]
+	encoded, err := starlark.Call(thread, encode, starlark.Tuple{value}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("result of type %s cannot be represented as JSON: %v", value.Type(), err)
+	}
+	var i interface{}
+	if err := json.Unmarshal([]byte(encoded.(starlark.String)), &i); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
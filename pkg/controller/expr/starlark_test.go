@@ -0,0 +1,57 @@
+package expr
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarlarkExpressionParser_Resolve(t *testing.T) {
+	parser := NewStarlarkExpressionParser(WithStarlarkDialect())
+	testScope := makeTestScope()
+
+	testCases := []struct {
+		expr     string
+		task     string
+		expected interface{}
+	}{
+		{"{ scope['Tasks']['TaskA']['Output'] }", "", "some output"},
+		{"{ output('TaskA').upper() }", "", "SOME OUTPUT"},
+		{"{ output() }", "TaskA", "some output"},
+		{"{ outputHeaders('TaskA')['some-key'] }", "", "some-value"},
+		{"{ input('TaskA', 'otherInput') }", "", "input-otherInput"},
+		{"{ param('headers') }", "", "http-headers"},
+		{"{ task_id }", "TaskA", "TaskA"},
+		{"{ [x * 2 for x in range(3) if x != 1] }", "", []interface{}{float64(0), float64(4)}},
+		{"{ 'yes' if output('TaskA') else 'no' }", "", "yes"},
+		{"{ json.encode({'a': 1}) }", "", "{\"a\":1}"},
+		{"{ result = 0; result += len(output('TaskA')) }", "", float64(11)},
+		{"{ for k in scope['Tasks']: result = k.lower() }", "", "taska"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expr, func(t *testing.T) {
+			result, err := parser.Resolve(testScope, testCase.task, mustParseExpr(testCase.expr))
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expected, typedvalues.MustUnwrap(result))
+		})
+	}
+}
+
+func TestStarlarkExpressionParser_ResolveInvalid(t *testing.T) {
+	parser := NewStarlarkExpressionParser(WithStarlarkDialect())
+
+	testCases := []string{
+		"{ foo( }",
+		"{ fail('foo') }",
+		"{ x = 1 }",
+		"{ lambda: 1 }",
+		"{ [x for x in range(100000000)] }",
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase, func(t *testing.T) {
+			_, err := parser.Resolve(rootScope, "", mustParseExpr(testCase))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -2,12 +2,40 @@ package expr
 
 import (
 	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
+// maxRebuildDepth is the maximum number of ancestors of an invocation of which the scopes are rebuilt, which guards
+// against cycles in the parents of invocations.
+const maxRebuildDepth = 100
+
+// InvocationGetter provides access to the invocations, which is needed to rebuild the scopes that are missing from
+// the store.
+type InvocationGetter interface {
+	GetInvocation(invocationID string) (*types.WorkflowInvocation, error)
+}
+
 // TODO Keep old states (but prune if OOM)
 // TODO provide garbage collector
 type Store struct {
-	entries sync.Map // map[string]interface{}
+	entries     sync.Map // map[string]*storeEntry
+	invocations InvocationGetter
+}
+
+// storeEntry holds the scope of an invocation, along with the versions of the tasks that it was formatted from.
+type storeEntry struct {
+	mu       sync.Mutex
+	own      *Scope            // the scope of the invocation itself, without the inherited parts of the parent.
+	scope    *Scope            // the scope of the invocation including the inherited parts of the parent.
+	versions map[string]string // map[taskID]version
+
+	// workflowVersion is the version of the workflow that the scope was formatted from. It changes if the invocation
+	// is migrated to another version of the workflow.
+	workflowVersion int64
 }
 
 func NewStore() *Store {
@@ -16,21 +44,62 @@ func NewStore() *Store {
 	}
 }
 
+// NewStoreWithInvocations returns a store that rebuilds the scopes that it does not hold from the invocations. The
+// store only lives in memory, so this allows child invocations to inherit the scope of their parent after a restart of
+// the workflow engine.
+func NewStoreWithInvocations(invocations InvocationGetter) *Store {
+	return &Store{
+		entries:     sync.Map{},
+		invocations: invocations,
+	}
+}
+
 func (rs *Store) Set(id string, data *Scope) {
-	rs.entries.Store(id, data)
+	rs.entries.Store(id, &storeEntry{
+		own:   data,
+		scope: data,
+	})
 }
 
 func (rs *Store) Delete(id string) {
 	rs.entries.Delete(id)
 }
 
+// Get returns the scope of the invocation. If the store does not hold the scope, and it has access to the invocations,
+// the scope is rebuilt from the invocation, inheriting the (rebuilt) scope of its parent.
 func (rs *Store) Get(id string) (*Scope, bool) {
-	i, ok := rs.entries.Load(id)
-	if !ok {
-		return nil, ok
+	return rs.get(id, maxRebuildDepth)
+}
+
+func (rs *Store) get(id string, depth int) (*Scope, bool) {
+	if entry, ok := rs.getEntry(id); ok {
+		entry.mu.Lock()
+		scope := entry.scope
+		entry.mu.Unlock()
+		if scope != nil {
+			return scope, true
+		}
+	}
+	if rs.invocations == nil || depth <= 0 {
+		return nil, false
+	}
+
+	invocation, err := rs.invocations.GetInvocation(id)
+	if err != nil {
+		logrus.Debugf("Could not find invocation %s to rebuild its scope: %v", id, err)
+		return nil, false
 	}
-	scope, ok := i.(*Scope)
-	return scope, ok
+	var parent *Scope
+	if parentID := invocation.GetSpec().GetParentId(); len(parentID) != 0 {
+		parent, _ = rs.get(parentID, depth-1)
+	}
+	scope, err := rs.Sync(invocation, parent)
+	if err != nil {
+		logrus.Warnf("Failed to rebuild the scope of invocation %s: %v", id, err)
+		return nil, false
+	}
+	logrus.Debugf("Rebuilt the scope of invocation %s", id)
+	return scope, true
 }
 
 func (rs *Store) Update(id string, updater func(entry *Scope) *Scope) {
@@ -44,6 +113,99 @@ func (rs *Store) Update(id string, updater func(entry *Scope) *Scope) {
 // If f returns false, range stops the iteration.
 func (rs *Store) Range(fn func(key string, value *Scope) bool) {
 	rs.entries.Range(func(key, value interface{}) bool {
-		return fn(key.(string), value.(*Scope))
+		scope, ok := rs.get(key.(string), 0)
+		if !ok {
+			return true
+		}
+		return fn(key.(string), scope)
 	})
 }
+
+// Sync updates the stored scope of the invocation to the current state of the invocation, and returns it.
+//
+// The scope is built incrementally: only the tasks that changed since the previous sync, such as tasks that
+// completed, are formatted again. The other parts are reused from the stored scope. If a parent scope is provided, the
+// scope inherits the missing parts from it.
+//
+// The returned scope shares its values with the stored scope, so these should not be modified. The entries of
+// returned Tasks map can be replaced; use TaskScope.ShallowCopy to modify the scope of a task.
+func (rs *Store) Sync(wfi *types.WorkflowInvocation, parent *Scope) (*Scope, error) {
+	i, _ := rs.entries.LoadOrStore(wfi.ID(), &storeEntry{})
+	entry := i.(*storeEntry)
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	own := &Scope{}
+	if entry.own != nil {
+		if entry.workflowVersion == wfi.GetSpec().GetWorkflowVersion() {
+			own.Workflow = entry.own.Workflow
+			own.Consts = entry.own.Consts
+		}
+		own.Invocation = entry.own.Invocation
+	}
+	if own.Workflow == nil {
+		if wf := wfi.Workflow(); wf != nil {
+			own.Workflow = formatWorkflow(wf)
+			consts, err := formatConsts(wf)
+			if err != nil {
+				return nil, err
+			}
+			own.Consts = consts
+		}
+	}
+	if own.Invocation == nil {
+		invocationParams, err := typedvalues.UnwrapMapTypedValue(wfi.Spec.Inputs)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to format invocation inputs")
+		}
+		own.Invocation = &InvocationScope{
+			ObjectMetadata: formatMetadata(wfi.Metadata),
+			Inputs:         invocationParams,
+		}
+	}
+
+	// Only format the tasks of which the state changed since the previous sync.
+	tasks := wfi.Tasks()
+	own.Tasks = make(Tasks, len(tasks))
+	versions := make(map[string]string, len(tasks))
+	for taskID, task := range tasks {
+		version, cacheable := taskVersion(taskID, task, wfi)
+		if prev, ok := entry.versions[taskID]; ok && cacheable && prev == version && entry.own != nil {
+			if taskScope, ok := entry.own.Tasks[taskID]; ok {
+				own.Tasks[taskID] = taskScope
+				versions[taskID] = version
+				continue
+			}
+		}
+		taskScope, err := formatTask(taskID, task, wfi)
+		if err != nil {
+			return nil, err
+		}
+		own.Tasks[taskID] = taskScope
+		if cacheable {
+			versions[taskID] = version
+		}
+	}
+
+	entry.own = own
+	entry.scope = own.inherit(parent)
+	entry.versions = versions
+	entry.workflowVersion = wfi.GetSpec().GetWorkflowVersion()
+
+	// Copy the tasks, to allow the caller to replace the task scopes without affecting the stored scope.
+	synced := *entry.scope
+	synced.Tasks = make(Tasks, len(entry.scope.Tasks))
+	for k, v := range entry.scope.Tasks {
+		synced.Tasks[k] = v
+	}
+	return &synced, nil
+}
+
+func (rs *Store) getEntry(id string) (*storeEntry, bool) {
+	i, ok := rs.entries.Load(id)
+	if !ok {
+		return nil, ok
+	}
+	entry, ok := i.(*storeEntry)
+	return entry, ok
+}
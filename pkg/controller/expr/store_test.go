@@ -0,0 +1,257 @@
+package expr
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newStoreTestInvocation(id string, parentID string) *types.WorkflowInvocation {
+	return &types.WorkflowInvocation{
+		Metadata: &types.ObjectMetadata{
+			Id:        id,
+			CreatedAt: ptypes.TimestampNow(),
+		},
+		Spec: &types.WorkflowInvocationSpec{
+			ParentId: parentID,
+			Inputs: typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+				"default": id,
+			}),
+			Workflow: &types.Workflow{
+				Metadata: &types.ObjectMetadata{
+					Id:        "testWorkflow",
+					CreatedAt: ptypes.TimestampNow(),
+				},
+				Spec: &types.WorkflowSpec{
+					ApiVersion: "1",
+					OutputTask: "b",
+				},
+				Status: &types.WorkflowStatus{
+					Status:    types.WorkflowStatus_READY,
+					UpdatedAt: ptypes.TimestampNow(),
+					Tasks: map[string]*types.Task{
+						"a": {
+							Metadata: &types.ObjectMetadata{Id: "a"},
+							Spec:     &types.TaskSpec{FunctionRef: "noop"},
+							Status:   &types.TaskStatus{},
+						},
+						"b": {
+							Metadata: &types.ObjectMetadata{Id: "b"},
+							Spec:     &types.TaskSpec{FunctionRef: "noop"},
+							Status:   &types.TaskStatus{},
+						},
+					},
+				},
+			},
+		},
+		Status: &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_IN_PROGRESS,
+			Tasks:  map[string]*types.TaskInvocation{},
+		},
+	}
+}
+
+func completeStoreTestTask(wfi *types.WorkflowInvocation, taskID string, output interface{}) {
+	wfi.Status.Tasks[taskID] = &types.TaskInvocation{
+		Spec: &types.TaskInvocationSpec{},
+		Status: &types.TaskInvocationStatus{
+			Status:    types.TaskInvocationStatus_SUCCEEDED,
+			Output:    typedvalues.MustWrap(output),
+			UpdatedAt: ptypes.TimestampNow(),
+		},
+	}
+}
+
+func TestStore_SyncIncremental(t *testing.T) {
+	store := NewStore()
+	wfi := newStoreTestInvocation("wfi", "")
+
+	scope, err := store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, scope.Tasks["a"].Output)
+	assert.Equal(t, "wfi", scope.Invocation.Inputs["default"])
+
+	// Only the completed task should be formatted again
+	completeStoreTestTask(wfi, "a", "foo")
+	updated, err := store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", updated.Tasks["a"].Output)
+	assert.False(t, scope.Tasks["a"] == updated.Tasks["a"])
+	assert.True(t, scope.Tasks["b"] == updated.Tasks["b"])
+	assert.True(t, scope.Invocation == updated.Invocation)
+	assert.True(t, scope.Workflow == updated.Workflow)
+
+	// The stored scope should be equal to a scope created from scratch
+	expected, err := NewScope(nil, wfi)
+	assert.NoError(t, err)
+	stored, ok := store.Get("wfi")
+	assert.True(t, ok)
+	assert.Equal(t, expected, stored)
+}
+
+func TestStore_SyncModifyTask(t *testing.T) {
+	store := NewStore()
+	wfi := newStoreTestInvocation("wfi", "")
+
+	scope, err := store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	taskScope := scope.Tasks["a"].ShallowCopy()
+	taskScope.Inputs["foo"] = "bar"
+	taskScope.Output = "bar"
+	scope.Tasks["a"] = taskScope
+
+	stored, ok := store.Get("wfi")
+	assert.True(t, ok)
+	assert.NotContains(t, stored.Tasks["a"].Inputs, "foo")
+	assert.Nil(t, stored.Tasks["a"].Output)
+}
+
+func TestStore_SyncInheritParent(t *testing.T) {
+	store := NewStore()
+	parent := newStoreTestInvocation("parent", "")
+	parent.Spec.Inputs = typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		"default": "parent",
+		"extra":   "parent",
+	})
+	parent.Spec.Workflow.Status.Tasks["parentTask"] = &types.Task{
+		Metadata: &types.ObjectMetadata{Id: "parentTask"},
+		Spec:     &types.TaskSpec{FunctionRef: "noop"},
+		Status:   &types.TaskStatus{},
+	}
+	completeStoreTestTask(parent, "parentTask", "parentOutput")
+	parentScope, err := store.Sync(parent, nil)
+	assert.NoError(t, err)
+
+	child := newStoreTestInvocation("child", "parent")
+	completeStoreTestTask(child, "a", "childOutput")
+	scope, err := store.Sync(child, parentScope)
+	assert.NoError(t, err)
+	assert.Equal(t, "child", scope.Invocation.Inputs["default"])
+	assert.Equal(t, "parent", scope.Invocation.Inputs["extra"])
+	assert.Equal(t, "childOutput", scope.Tasks["a"].Output)
+	assert.Equal(t, "parentOutput", scope.Tasks["parentTask"].Output)
+
+	// The parent scope should not be affected by the child
+	stored, ok := store.Get("parent")
+	assert.True(t, ok)
+	assert.Nil(t, stored.Tasks["a"].Output)
+}
+
+func TestStore_SyncMigratedWorkflow(t *testing.T) {
+	store := NewStore()
+	wfi := newStoreTestInvocation("wfi", "")
+	wfi.Spec.WorkflowVersion = 1
+	scope, err := store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "READY", scope.Workflow.Status)
+
+	// The workflow is formatted again once the invocation has been migrated to another version of the workflow.
+	wfi.Spec.Workflow.Status.Status = types.WorkflowStatus_QUEUED
+	scope, err = store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "READY", scope.Workflow.Status)
+
+	wfi.Spec.WorkflowVersion = 2
+	scope, err = store.Sync(wfi, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "QUEUED", scope.Workflow.Status)
+}
+
+func TestStore_SyncConsts(t *testing.T) {
+	store := NewStore()
+	parent := newStoreTestInvocation("parent", "")
+	parent.Spec.Workflow.Spec.Consts = typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		"bucket":   "parent-bucket",
+		"endpoint": "http://parent",
+	})
+	parentScope, err := store.Sync(parent, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "parent-bucket", parentScope.Consts["bucket"])
+
+	// The constants of the child override those of the parent.
+	child := newStoreTestInvocation("child", "parent")
+	child.Spec.Workflow.Spec.Consts = typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		"bucket": "child-bucket",
+	})
+	scope, err := store.Sync(child, parentScope)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"bucket":   "child-bucket",
+		"endpoint": "http://parent",
+	}, scope.Consts)
+
+	resolved, err := NewJavascriptExpressionParser().Resolve(scope, "a",
+		mustParseExpr("{$.Consts.endpoint + '/' + $.Consts.bucket}"))
+	assert.NoError(t, err)
+	assert.Equal(t, "http://parent/child-bucket", typedvalues.MustUnwrap(resolved))
+
+	// Without constants, the expressions can still refer to missing constants.
+	scope, err = store.Sync(newStoreTestInvocation("other", ""), nil)
+	assert.NoError(t, err)
+	resolved, err = NewJavascriptExpressionParser().Resolve(scope, "a", mustParseExpr("{$.Consts.bucket}"))
+	assert.NoError(t, err)
+	assert.Nil(t, typedvalues.MustUnwrap(resolved))
+}
+
+type storeTestInvocations map[string]*types.WorkflowInvocation
+
+func (invocations storeTestInvocations) GetInvocation(invocationID string) (*types.WorkflowInvocation, error) {
+	invocation, ok := invocations[invocationID]
+	if !ok {
+		return nil, fmt.Errorf("invocation %s not found", invocationID)
+	}
+	return invocation, nil
+}
+
+func TestStore_GetRebuild(t *testing.T) {
+	grandparent := newStoreTestInvocation("grandparent", "")
+	grandparent.Spec.Workflow.Status.Tasks["grandparentTask"] = &types.Task{
+		Metadata: &types.ObjectMetadata{Id: "grandparentTask"},
+		Spec:     &types.TaskSpec{FunctionRef: "noop"},
+		Status:   &types.TaskStatus{},
+	}
+	completeStoreTestTask(grandparent, "grandparentTask", "grandparentOutput")
+	parent := newStoreTestInvocation("parent", "grandparent")
+	parent.Spec.Workflow.Status.Tasks["parentTask"] = &types.Task{
+		Metadata: &types.ObjectMetadata{Id: "parentTask"},
+		Spec:     &types.TaskSpec{FunctionRef: "noop"},
+		Status:   &types.TaskStatus{},
+	}
+	completeStoreTestTask(parent, "parentTask", "parentOutput")
+	cyclic := newStoreTestInvocation("cyclic", "cyclic")
+	invocations := storeTestInvocations{
+		"grandparent": grandparent,
+		"parent":      parent,
+		"cyclic":      cyclic,
+	}
+
+	// Without access to the invocations, missing scopes are not rebuilt.
+	_, ok := NewStore().Get("parent")
+	assert.False(t, ok)
+
+	// The scope of the parent is rebuilt from the invocations, including the scope that it inherits.
+	store := NewStoreWithInvocations(invocations)
+	parentScope, ok := store.Get("parent")
+	assert.True(t, ok)
+	assert.Equal(t, "parent", parentScope.Invocation.Inputs["default"])
+	assert.Equal(t, "parentOutput", parentScope.Tasks["parentTask"].Output)
+	assert.Equal(t, "grandparentOutput", parentScope.Tasks["grandparentTask"].Output)
+	_, ok = store.Get("grandparent")
+	assert.True(t, ok)
+
+	child := newStoreTestInvocation("child", "parent")
+	scope, err := store.Sync(child, parentScope)
+	assert.NoError(t, err)
+	assert.Equal(t, "child", scope.Invocation.Inputs["default"])
+	assert.Equal(t, "parentOutput", scope.Tasks["parentTask"].Output)
+	assert.Equal(t, "grandparentOutput", scope.Tasks["grandparentTask"].Output)
+
+	_, ok = store.Get("unknown")
+	assert.False(t, ok)
+	_, ok = store.Get("cyclic")
+	assert.True(t, ok)
+}
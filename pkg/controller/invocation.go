@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,50 +14,123 @@ import (
 	"github.com/fission/fission-workflows/pkg/controller/executor"
 	"github.com/fission/fission-workflows/pkg/controller/expr"
 	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/quota"
 	"github.com/fission/fission-workflows/pkg/scheduler"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
 	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	DefaultMaxRuntime       = 10 * time.Minute
 	awaitWorkflowMaxRuntime = 10 * time.Second
+
+	// taskTimeoutGrace is the time after the deadline of the invocation at which the executor abandons the tasks of
+	// the invocation that are still running, such as function invocations that do not respect the deadline.
+	taskTimeoutGrace = 30 * time.Second
 )
 
+// ErrTaskInterrupted is the error of a task that was in progress when the workflow engine restarted, and that could
+// not be invoked again, because it is not idempotent.
+var ErrTaskInterrupted = errors.New("task was interrupted by a restart of the workflow engine")
+
+// eventRetryPolicy is the retry policy of the tasks that complete or fail invocations. These tasks only append an
+// event, so they are retried if the event store is temporarily unavailable.
+var eventRetryPolicy = executor.DefaultRetryPolicy
+
+var taskQueueTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "workflows",
+	Subsystem: "task",
+	Name:      "queue_time_seconds",
+	Help:      "Time between the scheduling of a task and the start of its execution by the executor",
+	Buckets:   prometheus.ExponentialBuckets(0.001, 2, 16),
+}, []string{"workflow", "task", "fnenv"})
+
+var stalenessRefreshes = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "ctrl",
+	Name:      "staleness_refreshes_total",
+	Help:      "Number of evaluations that were triggered because a controller had not been evaluated for too long",
+}, []string{"system"})
+
+var resumedIntents = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "ctrl",
+	Name:      "resumed_intents_total",
+	Help:      "Number of pending intents of invocations that were resumed, such as after a restart of the controller",
+}, []string{"action"})
+
+var recoveredTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "ctrl",
+	Name:      "recovered_tasks_total",
+	Help:      "Number of tasks that were in progress when the controller restarted, and were rerun or failed",
+}, []string{"action"})
+
+func init() {
+	prometheus.MustRegister(taskQueueTime, stalenessRefreshes, resumedIntents, recoveredTasks)
+}
+
 // InvocationController is the controller for ensuring the processing of a single workflow invocation.
+//
+// If the controller has quotas, the invocation counts towards the running invocations of its namespace until it has
+// finished, and its tasks are started at the maximum task rate of the namespace.
 type InvocationController struct {
 	invocationID  string
 	executor      *executor.LocalExecutor
 	invocationAPI *api.Invocation
 	taskAPI       *api.Task
+	invocations   *store.Invocations
+	running       *RunningInvocations
 	scheduler     *scheduler.InvocationScheduler
-	StateStore    *expr.Store // Future: just grab the initial state of the parent, instead of constantly rebuilding it.
+	StateStore    *expr.Store
 	span          opentracing.Span
 	logger        *logrus.Entry
-	startedTasks  map[string]struct{}
+	quotas        *quota.Manager
+
+	// startedTasks are the tasks that the controller has submitted, along with the time at which their task
+	// invocation was last updated before the submission, or the zero time if the task had not been invoked yet.
+	startedTasks map[string]time.Time
 
 	errorCount int
+
+	// intents are the ids of the intents that the controller has submitted, as opposed to pending intents that were
+	// recorded by a previous controller.
+	intents map[string]struct{}
+
+	// tasksCtx is the parent context of the task invocations, which is canceled once the invocation has finished,
+	// such as when it has been canceled, so that the functions that support it stop early.
+	tasksCtx    context.Context
+	cancelTasks context.CancelFunc
 }
 
 func NewInvocationController(invocationID string, executor *executor.LocalExecutor, invocationAPI *api.Invocation,
-	taskAPI *api.Task, scheduler *scheduler.InvocationScheduler, stateStore *expr.Store,
-	span opentracing.Span, logger *logrus.Entry) *InvocationController {
-
+	taskAPI *api.Task, invocations *store.Invocations, running *RunningInvocations,
+	scheduler *scheduler.InvocationScheduler, stateStore *expr.Store, span opentracing.Span, logger *logrus.Entry,
+	quotas *quota.Manager) *InvocationController {
+	tasksCtx, cancelTasks := context.WithCancel(context.Background())
 	return &InvocationController{
 		invocationID:  invocationID,
 		executor:      executor,
 		invocationAPI: invocationAPI,
 		taskAPI:       taskAPI,
+		invocations:   invocations,
+		running:       running,
 		scheduler:     scheduler,
 		StateStore:    stateStore,
 		span:          span,
 		logger:        logger,
-		startedTasks:  map[string]struct{}{},
+		startedTasks:  map[string]time.Time{},
+		quotas:        quotas,
+		intents:       map[string]struct{}{},
+		tasksCtx:      tasksCtx,
+		cancelTasks:   cancelTasks,
 	}
 }
 
@@ -76,97 +150,99 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 	// Ensure that the workflow is present in the invocation
 	if invocation.Workflow() == nil {
 		err := errors.New("workflow is not present in the invocation")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		c.fail(invocation, err)
 		return ctrl.Err{Err: err}
 	}
 
-	// Do not evaluate as long as there still tasks to be executed
+	// Stop the tasks that are still running once the invocation has finished.
+	if invocation.GetStatus().Finished() {
+		c.cancelTasks()
+	}
+
+	// Do not evaluate as long as there still tasks to be executed. Waiting is not a failure, as failures count towards
+	// the quarantine of the invocation.
 	if activeTaskCount := c.executor.GetGroupTasks(invocation.ID()); activeTaskCount > 0 {
-		return ctrl.Err{Err: fmt.Errorf("invocation still has %d open task(s) to be executed", activeTaskCount)}
+		return ctrl.Success{Msg: fmt.Sprintf("invocation still has %d open task(s) to be executed", activeTaskCount)}
 	}
 
 	// To avoid scheduling tasks that are being processed, ensure that all tasks that were successfully submitted have
-	// finished before reevaluating.
-	for taskID := range c.startedTasks {
-		if taskRun, ok := invocation.TaskInvocation(taskID); !ok || !taskRun.GetStatus().Finished() {
+	// finished before reevaluating. A task that was submitted again, such as a retry of a failed task, has only
+	// finished once its task invocation has been updated since the submission.
+	for taskID, updatedAt := range c.startedTasks {
+		taskRun, ok := invocation.TaskInvocation(taskID)
+		if !ok || !taskRun.GetStatus().Finished() || !lastUpdate(taskRun).After(updatedAt) {
 			return ctrl.Success{}
 		}
 	}
 
 	// Check if the invocation is not in a terminal state
+	namespace := invocation.Workflow().GetSpec().GetNamespace()
 	if invocation.GetStatus().Finished() {
+		if c.quotas != nil {
+			c.quotas.Release(namespace, invocation.ID())
+		}
 		return ctrl.Done{Msg: fmt.Sprintf("invocation is in a terminal state (%v)",
 			invocation.GetStatus().GetStatus().String())}
 	}
 
+	if c.quotas != nil {
+		c.quotas.Track(namespace, invocation.ID())
+	}
+
 	// Check if the deadline has not been exceeded
 	deadline, err := ptypes.Timestamp(invocation.GetSpec().GetDeadline())
 	if err != nil {
 		createdAt, err := ptypes.Timestamp(invocation.GetMetadata().GetCreatedAt())
 		if err != nil {
 			err := errors.New("failed to read deadline and createdAt")
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".fail",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Fail(invocation.ID(), err)
-				},
-			})
+			c.fail(invocation, err)
 			return ctrl.Err{Err: err}
 		}
 		deadline = createdAt.Add(DefaultMaxRuntime)
 	}
 	if time.Now().After(deadline) {
-		err := errors.New("deadline exceeded")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		err := errors.New(api.ErrInvocationDeadlineExceeded)
+		c.fail(invocation, err)
 		return ctrl.Err{Err: err}
 	}
 
+	// Check if the concurrency policy of the workflow allows the invocation to start
+	if !started(invocation) && len(c.startedTasks) == 0 {
+		if result, ok := c.applyConcurrencyPolicy(invocation); !ok {
+			return result
+		}
+	}
+
+	// Resume the intents that a previous controller recorded but did not execute, for example because it crashed.
+	if resumed := c.resumeIntents(invocation, deadline); resumed > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("resumed %d pending intent(s)", resumed)}
+	}
+
+	// Do not start any tasks once the invocation is being failed, as the failure might not have been projected yet.
+	if _, ok := c.intents[(&types.Intent{Action: types.Intent_FAIL}).ID()]; ok {
+		return ctrl.Success{Msg: "invocation is being failed"}
+	}
+
+	// Recover the tasks that were in progress when a previous controller stopped.
+	if recovered := c.recoverTasks(invocation, deadline); recovered > 0 {
+		return ctrl.Success{Msg: fmt.Sprintf("recovered %d interrupted task(s)", recovered)}
+	}
+
 	// Check if we did not exceed the error count
 	if c.errorCount > 0 {
 		err := errors.New("error count exceeded")
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		c.fail(invocation, err)
 		return ctrl.Err{Err: err}
 	}
 
 	// Check if all tasks have finished
 	if allTasksFinished(invocation) {
-		output, outputHeaders, err := determineTaskOutput(invocation)
+		output, outputHeaders, err := c.determineTaskOutput(invocation)
 		if err != nil {
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".fail",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Fail(invocation.ID(), err)
-				},
-			})
+			c.fail(invocation, err)
 			return ctrl.Err{Err: err}
 		} else {
-			c.executor.Submit(&executor.Task{
-				TaskID:  invocation.ID() + ".success",
-				GroupID: invocation.ID(),
-				Apply: func() error {
-					return c.invocationAPI.Complete(invocation.ID(), output, outputHeaders)
-				},
-			})
+			c.complete(invocation, output, outputHeaders)
 			return ctrl.Success{Msg: "all tasks of the invocation have completed"}
 		}
 	}
@@ -180,21 +256,18 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 	// If the scheduler indicates to fail, fail the invocation immediately.
 	if abortAction := schedule.GetAbort(); abortAction != nil {
 		err := errors.New(abortAction.Reason)
-		c.executor.Submit(&executor.Task{
-			TaskID:  invocation.ID() + ".fail",
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.invocationAPI.Fail(invocation.ID(), err)
-			},
-		})
+		c.fail(invocation, err)
 		return ctrl.Err{Err: err}
 	}
 
 	// Prepare (prewarm) the tasks listed in the schedule.
+	taskTimeout := time.Until(deadline) + taskTimeoutGrace
 	for _, action := range schedule.GetPrepareTasks() {
 		c.executor.Submit(&executor.Task{
-			TaskID:  fmt.Sprintf("%s.prewarm.%s", invocation.ID(), action.TaskID),
-			GroupID: invocation.ID(),
+			TaskID:   fmt.Sprintf("%s.prewarm.%s", invocation.ID(), action.TaskID),
+			GroupID:  invocation.ID(),
+			Priority: executor.PriorityLow,
+			Timeout:  taskTimeout,
 			Apply: func() error {
 				task, ok := invocation.Task(action.TaskID)
 				if !ok || task == nil {
@@ -207,26 +280,190 @@ func (c *InvocationController) Eval(ctx context.Context, processValue *ctrl.Even
 	}
 
 	// Execute the tasks listed in the schedule.
+	delayed := map[string]bool{}
 	for _, action := range schedule.GetRunTasks() {
 		taskID := action.TaskID
-		if c.executor.Submit(&executor.Task{
-			TaskID:  fmt.Sprintf("%s.run.%s", invocation.ID(), taskID),
-			GroupID: invocation.ID(),
-			Apply: func() error {
-				return c.execTask(invocation, taskID)
-			},
-		}) {
-			c.startedTasks[action.TaskID] = struct{}{}
+		// Tasks that exceed the task rate of the namespace are scheduled again in a later evaluation, as are the tasks
+		// that have a control dependency on a delayed task, so that they are not started before the dependency.
+		if c.quotas != nil && (requiresAny(invocation, taskID, delayed) || !c.quotas.AllowTask(namespace)) {
+			delayed[taskID] = true
+			continue
 		}
+		c.run(invocation, taskID, taskTimeout)
 	}
+	delayedTasks := len(delayed)
 
 	return ctrl.Success{
-		Msg: fmt.Sprintf("scheduled execution of %d tasks and preparation of %d tasks",
-			len(schedule.GetRunTasks()), len(schedule.GetPrepareTasks())),
+		Msg: fmt.Sprintf("scheduled execution of %d tasks (%d delayed) and preparation of %d tasks",
+			len(schedule.GetRunTasks())-delayedTasks, delayedTasks, len(schedule.GetPrepareTasks())),
+	}
+}
+
+// recordIntent records the intent in the event store before its action is submitted, unless the intent is pending
+// already. If the intent cannot be recorded, the action is submitted regardless, so that the invocation progresses.
+func (c *InvocationController) recordIntent(invocation *types.WorkflowInvocation, intent *types.Intent) {
+	c.intents[intent.ID()] = struct{}{}
+	if _, ok := invocation.GetStatus().GetIntents()[intent.ID()]; ok {
+		return
+	}
+	if err := c.invocationAPI.RecordIntent(invocation.ID(), intent); err != nil {
+		c.logger.Warnf("Failed to record intent %s: %v", intent.ID(), err)
+	}
+}
+
+// fail records the intent to fail the invocation, and submits the task that fails it.
+func (c *InvocationController) fail(invocation *types.WorkflowInvocation, err error) {
+	c.recordIntent(invocation, types.NewFailIntent(err))
+	c.submitFail(invocation, err)
+}
+
+func (c *InvocationController) submitFail(invocation *types.WorkflowInvocation, err error) {
+	c.executor.Submit(&executor.Task{
+		TaskID:   invocation.ID() + ".fail",
+		GroupID:  invocation.ID(),
+		Priority: executor.PriorityHigh,
+		Retry:    &eventRetryPolicy,
+		Apply: func() error {
+			return c.invocationAPI.Fail(invocation.ID(), err)
+		},
+	})
+}
+
+// complete records the intent to complete the invocation, and submits the task that completes it.
+func (c *InvocationController) complete(invocation *types.WorkflowInvocation, output *typedvalues.TypedValue,
+	outputHeaders *typedvalues.TypedValue) {
+	c.recordIntent(invocation, types.NewCompleteIntent())
+	c.executor.Submit(&executor.Task{
+		TaskID:   invocation.ID() + ".success",
+		GroupID:  invocation.ID(),
+		Priority: executor.PriorityHigh,
+		Retry:    &eventRetryPolicy,
+		Apply: func() error {
+			return c.invocationAPI.Complete(invocation.ID(), output, outputHeaders)
+		},
+	})
+}
+
+// run records the intent to run the task, and submits the task to the executor.
+func (c *InvocationController) run(invocation *types.WorkflowInvocation, taskID string, timeout time.Duration) {
+	c.recordIntent(invocation, types.NewRunIntent(taskID))
+	c.submitRun(invocation, taskID, timeout)
+}
+
+func (c *InvocationController) submitRun(invocation *types.WorkflowInvocation, taskID string, timeout time.Duration) {
+	queuedAt := time.Now()
+	attempt := nextAttempt(invocation, taskID)
+	var updatedAt time.Time
+	if taskRun, ok := invocation.TaskInvocation(taskID); ok {
+		updatedAt = lastUpdate(taskRun)
+		if taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+			c.logger.Infof("Retrying task %s (attempt %d of %d) after it failed: %s", taskID, attempt+1,
+				taskRun.GetSpec().GetTask().GetSpec().GetMaxRetries()+1, taskRun.GetStatus().GetError().GetMessage())
+		}
+	}
+	// A task that is still queued or executing from a previous evaluation is not submitted again.
+	result := c.executor.Submit(&executor.Task{
+		TaskID:  fmt.Sprintf("%s.run.%s", invocation.ID(), taskID),
+		GroupID: invocation.ID(),
+		Timeout: timeout,
+		Apply: func() error {
+			return c.execTask(invocation, taskID, attempt, queuedAt)
+		},
+	})
+	if result != executor.TaskRejected {
+		c.startedTasks[taskID] = updatedAt
 	}
 }
 
-func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, taskID string) error {
+// resumeIntents resubmits the pending intents of the invocation that the controller has not submitted itself, and
+// returns the number of resumed intents. A pending intent to fail the invocation takes precedence over the other
+// intents. Intents to complete the invocation are not resubmitted, as the evaluation determines the output of the
+// invocation again.
+func (c *InvocationController) resumeIntents(invocation *types.WorkflowInvocation, deadline time.Time) int {
+	var runs []string
+	for id, intent := range invocation.GetStatus().GetIntents() {
+		if _, ok := c.intents[id]; ok {
+			continue
+		}
+		c.intents[id] = struct{}{}
+		switch intent.GetAction() {
+		case types.Intent_FAIL:
+			c.logger.Infof("Resuming pending intent to fail the invocation: %s", intent.GetError().GetMessage())
+			c.submitFail(invocation, errors.New(intent.GetError().GetMessage()))
+			resumedIntents.WithLabelValues("fail").Inc()
+			return 1
+		case types.Intent_RUN:
+			runs = append(runs, intent.GetTaskId())
+		}
+	}
+
+	sort.Strings(runs)
+	var resumed int
+	for _, taskID := range runs {
+		if _, ok := invocation.Task(taskID); !ok {
+			c.logger.Warnf("Dropping pending intent to run unknown task %s", taskID)
+			continue
+		}
+		// Tasks that have started already are left to recoverTasks, as their function might have been invoked.
+		if taskRun, ok := invocation.TaskInvocation(taskID); ok &&
+			taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_IN_PROGRESS {
+			continue
+		}
+		c.logger.Infof("Resuming pending intent to run task %s", taskID)
+		c.submitRun(invocation, taskID, time.Until(deadline)+taskTimeoutGrace)
+		resumedIntents.WithLabelValues("run").Inc()
+		resumed++
+	}
+	return resumed
+}
+
+// recoverTasks recovers the tasks of the invocation that are in progress, but that were not started by the controller,
+// and returns the number of recovered tasks. These tasks were interrupted by a restart of the workflow engine, so they
+// would otherwise not finish until the deadline of the invocation. Idempotent tasks are invoked again; other tasks are
+// failed with ErrTaskInterrupted.
+func (c *InvocationController) recoverTasks(invocation *types.WorkflowInvocation, deadline time.Time) int {
+	var interrupted []string
+	for taskID, taskRun := range invocation.GetStatus().GetTasks() {
+		if _, ok := c.startedTasks[taskID]; ok {
+			continue
+		}
+		if taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_IN_PROGRESS {
+			interrupted = append(interrupted, taskID)
+		}
+	}
+
+	sort.Strings(interrupted)
+	for _, taskID := range interrupted {
+		// The pending intent to run the task, if any, is resolved by the recovery.
+		c.intents[types.NewRunIntent(taskID).ID()] = struct{}{}
+		if task, ok := invocation.Task(taskID); ok && task.GetSpec().GetIdempotent() {
+			c.logger.Infof("Rerunning idempotent task %s that was interrupted", taskID)
+			c.run(invocation, taskID, time.Until(deadline)+taskTimeoutGrace)
+			recoveredTasks.WithLabelValues("rerun").Inc()
+			continue
+		}
+
+		c.logger.Infof("Failing task %s that was interrupted", taskID)
+		taskID := taskID
+		result := c.executor.Submit(&executor.Task{
+			TaskID:   fmt.Sprintf("%s.recover.%s", invocation.ID(), taskID),
+			GroupID:  invocation.ID(),
+			Priority: executor.PriorityHigh,
+			Retry:    &eventRetryPolicy,
+			Apply: func() error {
+				return c.taskAPI.Fail(invocation.ID(), taskID, ErrTaskInterrupted.Error())
+			},
+		})
+		if result != executor.TaskRejected {
+			c.startedTasks[taskID] = lastUpdate(invocation.GetStatus().GetTasks()[taskID])
+		}
+		recoveredTasks.WithLabelValues("fail").Inc()
+	}
+	return len(interrupted)
+}
+
+func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, taskID string, attempt int32,
+	queuedAt time.Time) error {
 	log := c.logger
 	span := opentracing.StartSpan(fmt.Sprintf("/task/%s", taskID), opentracing.ChildOf(c.span.Context()))
 	span.SetTag("task", taskID)
@@ -239,6 +476,8 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 		span.LogKV("error", err)
 		return err
 	}
+	taskQueueTime.WithLabelValues(invocation.GetSpec().GetWorkflowId(), taskID,
+		task.GetStatus().GetFnRef().GetRuntime()).Observe(time.Since(queuedAt).Seconds())
 
 	span.SetTag("fnref", task.GetStatus().GetFnRef())
 	if log.Level == logrus.DebugLevel {
@@ -248,7 +487,7 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 		if err != nil {
 			inputs = fmt.Sprintf("error: %v", err)
 		}
-		span.LogKV("inputs", inputs)
+		span.LogKV("inputs", redact.DefaultRedactor.Value("inputs", inputs))
 	}
 
 	// Check if function has been resolved
@@ -258,6 +497,25 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 		return err
 	}
 
+	// Skip the task if its condition does not hold. A condition that cannot be evaluated fails the task.
+	if when := task.GetSpec().GetWhen(); when != nil {
+		run, err := c.evalCondition(invocation, taskID, when)
+		if err != nil {
+			span.LogKV("error", err)
+			if esErr := c.taskAPI.Fail(invocation.ID(), taskID, err.Error()); esErr != nil {
+				return esErr
+			}
+			return err
+		}
+		if !run {
+			log.Infof("Skipping task %s, because its condition does not hold", taskID)
+			span.SetTag("status", types.TaskInvocationStatus_SKIPPED.String())
+			taskRunSpec := types.NewTaskInvocationSpec(invocation, task, time.Now())
+			taskRunSpec.Attempt = attempt
+			return c.taskAPI.Skip(taskRunSpec)
+		}
+	}
+
 	// Resolve expression inputs
 	var inputs map[string]*typedvalues.TypedValue
 	if len(task.GetSpec().GetInputs()) > 0 {
@@ -276,24 +534,31 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 			if err != nil {
 				resolvedInputs = fmt.Sprintf("error: %v", err)
 			}
-			span.LogKV("resolved_inputs", resolvedInputs)
+			span.LogKV("resolved_inputs", redact.DefaultRedactor.Value("inputs", resolvedInputs))
+		}
+	}
+	if c.quotas != nil {
+		if err := c.quotas.CheckPayload(invocation.Workflow().GetSpec().GetNamespace(), inputs); err != nil {
+			span.LogKV("error", err)
+			return err
 		}
 	}
 
 	// Create the task run
 	taskRunSpec := types.NewTaskInvocationSpec(invocation, task, time.Now())
 	taskRunSpec.Inputs = inputs
+	taskRunSpec.Attempt = attempt
 	if log.Level == logrus.DebugLevel {
 		i, err := typedvalues.UnwrapMapTypedValue(taskRunSpec.GetInputs())
 		if err != nil {
 			log.Errorf("Failed to format inputs for debugging: %v", err)
 		} else {
-			log.Debugf("Using inputs: %v", i)
+			log.Debugf("Using inputs: %v", redact.DefaultRedactor.Map("inputs", i))
 		}
 	}
 
 	// Create the context with the deadline specified in the task run spec.
-	ctx := context.Background()
+	ctx := c.tasksCtx
 	deadline, err := ptypes.Timestamp(taskRunSpec.Deadline)
 	if err == nil {
 		var cancel func()
@@ -324,30 +589,22 @@ func (c *InvocationController) execTask(invocation *types.WorkflowInvocation, ta
 		if err != nil {
 			output = fmt.Sprintf("error: %v", err)
 		}
-		span.LogKV("output", output)
+		span.LogKV("output", redact.DefaultRedactor.Value("output", output))
 	}
 	return nil
 }
 
 func (c *InvocationController) resolveInputs(invocation *types.WorkflowInvocation, taskID string,
 	inputs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue, error) {
-	// Inherit scope if invocation has a parent
 	log := c.logger
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			log.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
-	}
-
-	// Setup the scope for the expressions
-	scope, err := expr.NewScope(parentScope, invocation)
+	scope, err := c.scope(invocation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scope for task '%v': %v", taskID, err)
 	}
-	c.StateStore.Set(invocation.ID(), scope)
+
+	// Copy the scope of the task, as it is updated with the resolved inputs
+	taskScope := scope.Tasks[taskID].ShallowCopy()
+	scope.Tasks[taskID] = taskScope
 
 	// Resolve each of the inputs (based on priority)
 	resolvedInputs := map[string]*typedvalues.TypedValue{}
@@ -367,35 +624,46 @@ func (c *InvocationController) resolveInputs(invocation *types.WorkflowInvocatio
 		}
 
 		// Update the scope with the resolved type
-		scope.Tasks[taskID].Inputs[input.Key] = typedvalues.MustUnwrap(resolvedInput)
+		taskScope.Inputs[input.Key] = typedvalues.MustUnwrap(resolvedInput)
 	}
 	return resolvedInputs, nil
 }
 
+// evalCondition evaluates the condition of the task, which should evaluate to a boolean, in the scope of the
+// invocation.
+func (c *InvocationController) evalCondition(invocation *types.WorkflowInvocation, taskID string,
+	when *typedvalues.TypedValue) (bool, error) {
+	scope, err := c.scope(invocation)
+	if err != nil {
+		return false, fmt.Errorf("failed to create scope for task '%v': %v", taskID, err)
+	}
+	resolved, err := expr.Resolve(scope, taskID, when)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the condition of task '%v': %v", taskID, err)
+	}
+	i, err := typedvalues.Unwrap(resolved)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate the condition of task '%v': %v", taskID, err)
+	}
+	run, ok := i.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition of task '%v' should evaluate to a boolean, but was %T", taskID, i)
+	}
+	return run, nil
+}
+
 func (c *InvocationController) resolveOutput(invocation *types.WorkflowInvocation, ti *types.TaskInvocation,
 	outputExpr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
-	log := c.logger
-
-	// Inherit scope if invocation has a parent
 	taskID := ti.GetSpec().GetTask().GetMetadata().GetId()
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			log.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
-	}
-
-	// Setup the scope for the expressions
-	scope, err := expr.NewScope(parentScope, invocation)
+	scope, err := c.scope(invocation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scope for task '%v': %v", taskID, err)
 	}
-	c.StateStore.Set(invocation.ID(), scope)
 
 	// Add the current output
-	scope.Tasks[taskID].Output = typedvalues.MustUnwrap(ti.GetStatus().GetOutput())
+	taskScope := scope.Tasks[taskID].ShallowCopy()
+	taskScope.Output = typedvalues.MustUnwrap(ti.GetStatus().GetOutput())
+	scope.Tasks[taskID] = taskScope
 
 	// Resolve the output expression
 	resolvedOutput, err := expr.Resolve(scope, taskID, outputExpr)
@@ -451,25 +719,15 @@ func (c *InvocationController) resolveOutputHeaders(invocation *types.WorkflowIn
 	outputHeadersExpr *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
 
 	taskID := ti.GetSpec().GetTask().GetMetadata().GetId()
-	// Inherit scope if invocation has a parent
-	var parentScope *expr.Scope
-	if len(invocation.Spec.ParentId) != 0 {
-		var ok bool
-		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
-		if !ok {
-			c.logger.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
-		}
-	}
-
-	// Setup the scope for the expressions
-	scope, err := expr.NewScope(parentScope, invocation)
+	scope, err := c.scope(invocation)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scope for task '%v': %v", taskID, err)
 	}
-	c.StateStore.Set(invocation.ID(), scope)
 
 	// Add the current outputHeaders
-	scope.Tasks[taskID].OutputHeaders = typedvalues.MustUnwrap(ti.GetStatus().GetOutputHeaders())
+	taskScope := scope.Tasks[taskID].ShallowCopy()
+	taskScope.OutputHeaders = typedvalues.MustUnwrap(ti.GetStatus().GetOutputHeaders())
+	scope.Tasks[taskID] = taskScope
 
 	// Resolve the outputHeaders expression
 	resolvedOutputHeaders, err := expr.Resolve(scope, taskID, outputHeadersExpr)
@@ -479,18 +737,38 @@ func (c *InvocationController) resolveOutputHeaders(invocation *types.WorkflowIn
 	return resolvedOutputHeaders, nil
 }
 
-func determineTaskOutput(invocation *types.WorkflowInvocation) (output *typedvalues.TypedValue,
-	outputHeaders *typedvalues.TypedValue, err error) {
+// scope returns the expression scope of the invocation, which is updated incrementally in the state store. If the
+// invocation has a parent, the scope inherits the scope of the parent invocation.
+func (c *InvocationController) scope(invocation *types.WorkflowInvocation) (*expr.Scope, error) {
+	var parentScope *expr.Scope
+	if len(invocation.Spec.ParentId) != 0 {
+		var ok bool
+		parentScope, ok = c.StateStore.Get(invocation.Spec.ParentId)
+		if !ok {
+			c.logger.Warnf("Could not find parent scope (%s) of scope (%s)", invocation.Spec.ParentId, invocation.ID())
+		}
+	}
+	return c.StateStore.Sync(invocation, parentScope)
+}
+
+// determineTaskOutput determines the output of the completed invocation. If the workflow declares named outputs, the
+// output is a map of the resolved outputs. Otherwise, it is the output of the output task.
+func (c *InvocationController) determineTaskOutput(invocation *types.WorkflowInvocation) (
+	output *typedvalues.TypedValue, outputHeaders *typedvalues.TypedValue, err error) {
 
 	success := true
 	wf := invocation.GetSpec().GetWorkflow()
 	for id := range invocation.Tasks() {
 		task := invocation.Status.Tasks[id]
-		if !task.GetStatus().Successful() {
+		// Skipped tasks do not fail the invocation.
+		if !task.GetStatus().Successful() && task.GetStatus().GetStatus() != types.TaskInvocationStatus_SKIPPED {
 			success = false
 			break
 		}
 	}
+	if !success {
+		return nil, nil, errors.New("one or more tasks in the workflow have failed")
+	}
 
 	var finalOutput *typedvalues.TypedValue
 	var finalOutputHeaders *typedvalues.TypedValue
@@ -498,14 +776,41 @@ func determineTaskOutput(invocation *types.WorkflowInvocation) (output *typedval
 		finalOutput = controlflow.ResolveTaskOutput(wf.Spec.OutputTask, invocation)
 		finalOutputHeaders = controlflow.ResolveTaskOutputHeaders(wf.Spec.OutputTask, invocation)
 	}
+	if len(wf.GetSpec().GetOutputs()) != 0 {
+		finalOutput, err = c.resolveWorkflowOutputs(invocation, wf.Spec.Outputs)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return finalOutput, finalOutputHeaders, nil
+}
 
-	if success {
-		return finalOutput, finalOutputHeaders, nil
-	} else {
-		return nil, nil, errors.New("one or more tasks in the workflow have failed")
+// resolveWorkflowOutputs resolves the named outputs of the workflow in the scope of the invocation.
+func (c *InvocationController) resolveWorkflowOutputs(invocation *types.WorkflowInvocation,
+	outputs map[string]*typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	scope, err := c.scope(invocation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scope for the outputs: %v", err)
+	}
+	outputTask := invocation.GetSpec().GetWorkflow().GetSpec().GetOutputTask()
+
+	resolvedOutputs := map[string]interface{}{}
+	for key, output := range outputs {
+		resolved, err := expr.Resolve(scope, outputTask, output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve output '%v': %v", key, err)
+		}
+		i, err := typedvalues.Unwrap(resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve output '%v': %v", key, err)
+		}
+		resolvedOutputs[key] = i
 	}
+	return typedvalues.Wrap(resolvedOutputs)
 }
 
+// allTasksFinished returns true if all tasks of the invocation are in a terminal state. A failed task that is retried
+// has not finished.
 func allTasksFinished(invocation *types.WorkflowInvocation) bool {
 	finished := true
 	for id := range invocation.Tasks() {
@@ -514,10 +819,50 @@ func allTasksFinished(invocation *types.WorkflowInvocation) bool {
 			finished = false
 			break
 		}
+		if _, retry := task.RetryAt(); retry {
+			finished = false
+			break
+		}
 	}
 	return finished
 }
 
+// requiresAny returns true if the task depends on any of the tasks.
+func requiresAny(invocation *types.WorkflowInvocation, taskID string, tasks map[string]bool) bool {
+	task, ok := invocation.Task(taskID)
+	if !ok {
+		return false
+	}
+	for dep := range task.GetSpec().GetRequires() {
+		if tasks[dep] {
+			return true
+		}
+	}
+	return false
+}
+
+// nextAttempt returns the attempt of the next run of the task. A failed task is run as a new attempt, whereas a task
+// that is run again otherwise, such as an idempotent task that was interrupted, keeps its attempt.
+func nextAttempt(invocation *types.WorkflowInvocation, taskID string) int32 {
+	taskRun, ok := invocation.TaskInvocation(taskID)
+	if !ok {
+		return 0
+	}
+	if taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
+		return taskRun.GetSpec().GetAttempt() + 1
+	}
+	return taskRun.GetSpec().GetAttempt()
+}
+
+// lastUpdate returns the time at which the task invocation was last updated, or the zero time if it is unknown.
+func lastUpdate(taskRun *types.TaskInvocation) time.Time {
+	updatedAt, err := ptypes.Timestamp(taskRun.GetStatus().GetUpdatedAt())
+	if err != nil {
+		return time.Time{}
+	}
+	return updatedAt
+}
+
 // InvocationMetaController is the component responsible for the full integration of the invocations reconciliation loop.
 //
 // Specifically, the meta-controller is responsible for the following:
@@ -529,21 +874,31 @@ type InvocationMetaController struct {
 	executor    *executor.LocalExecutor
 	runOnce     *sync.Once
 	invocations *store.Invocations
+	running     *RunningInvocations
 	system      *ctrl.System
 }
 
 func NewInvocationMetaController(executor *executor.LocalExecutor, invocations *store.Invocations,
 	invocationAPI *api.Invocation, taskAPI *api.Task, scheduler *scheduler.InvocationScheduler, stateStore *expr.Store,
-	cachePollInterval time.Duration) *InvocationMetaController {
+	cachePollInterval time.Duration, quotas *quota.Manager) *InvocationMetaController {
+	running := NewRunningInvocations()
 	c := &InvocationMetaController{
 		executor:    executor,
 		runOnce:     &sync.Once{},
 		invocations: invocations,
-		system: ctrl.NewSystem(func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
+		running:     running,
+		system: ctrl.NewSystem("invocation", func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
 			spanCtx, err := fes.ExtractTracingFromEventMetadata(event.Event.GetMetadata())
 			if err != nil {
 				logrus.Debugf("Could not extract span from event metadata: %v", err)
 			}
+			// Fallback to the tracing context of the invocation, to relate the evaluation to the caller.
+			if invocation, ok := event.Updated.(*types.WorkflowInvocation); spanCtx == nil && ok {
+				spanCtx, err = fes.ExtractTracingFromEventMetadata(invocation.GetStatus().GetTracingContext())
+				if err != nil {
+					logrus.Debugf("Could not extract span from invocation: %v", err)
+				}
+			}
 			var span opentracing.Span
 			if spanCtx != nil {
 				span = opentracing.StartSpan("/controller/eval", opentracing.FollowsFrom(spanCtx))
@@ -554,13 +909,13 @@ func NewInvocationMetaController(executor *executor.LocalExecutor, invocations *
 			if len(invocationID) == 0 {
 				return nil, fmt.Errorf("invocation ID missing in event: %v %v", event.Aggregate, event.Event.GetType())
 			}
-			return NewInvocationController(invocationID, executor, invocationAPI, taskAPI, scheduler,
-				stateStore, span, logrus.WithField("key", invocationID)), nil
+			return NewInvocationController(invocationID, executor, invocationAPI, taskAPI, invocations, running,
+				scheduler, stateStore, span, logrus.WithField("key", invocationID), quotas), nil
 		}),
 	}
 	c.sensors = []ctrl.Sensor{
-		NewInvocationNotificationSensor(invocations),
-		NewInvocationStorePollSensor(invocations, cachePollInterval),
+		NewInvocationNotificationSensor(invocations, running),
+		NewInvocationStorePollSensor(invocations, running, cachePollInterval),
 		NewStalenessPollSensor(c.system, func(ctrlKey string) (fes.Aggregate, fes.Entity, error) {
 			aggregate := fes.Aggregate{
 				Type: types.TypeInvocation,
@@ -576,56 +931,116 @@ func NewInvocationMetaController(executor *executor.LocalExecutor, invocations *
 	return c
 }
 
-func (c *InvocationMetaController) Run() {
+// System returns the control system that evaluates the invocation controllers.
+func (c *InvocationMetaController) System() *ctrl.System {
+	return c.system
+}
+
+// Run starts the executor, the sensors and the control system. It returns once they have been started, or returns an
+// error if a sensor could not be started.
+func (c *InvocationMetaController) Run() error {
+	var err error
 	c.runOnce.Do(func() {
-		go c.run()
+		err = c.run()
 	})
+	return err
 }
 
-func (c *InvocationMetaController) run() {
+func (c *InvocationMetaController) run() error {
+	// Index the invocations that are already running, such as after a restart, as they are not notified.
+	c.running.Load(c.invocations)
+
 	// Start the task executor
 	c.executor.Start()
 
 	// Start the sensors
-	for _, sensor := range c.sensors {
-		err := sensor.Start(c.system)
-		if err != nil {
-			panic(err)
-		}
+	if err := ctrl.StartSensors(c.sensors, c.system); err != nil {
+		util.LogIfError(c.executor.Close())
+		return err
 	}
 
 	// Run control system
 	c.system.Run()
-
+	return nil
 }
 
+// Close stops the sensors, the control system and the executor, in that order, without waiting for the executing tasks.
 func (c *InvocationMetaController) Close() error {
-	err := c.executor.Close()
-	err = c.system.Close()
-	for _, sensor := range c.sensors {
-		err = sensor.Close()
+	err := ctrl.CloseSensors(c.sensors)
+	if serr := c.system.Close(); serr != nil {
+		err = serr
+	}
+	if serr := c.executor.Close(); serr != nil {
+		err = serr
+	}
+	return err
+}
+
+// Drain stops the controller gracefully. It stops the sensors, evaluates the events that are still queued, and then
+// drains the executor, waiting at most for the timeout for the executing tasks to finish. The abandoned tasks are
+// executed again by the next controller once it evaluates their invocations.
+func (c *InvocationMetaController) Drain(timeout time.Duration) error {
+	err := ctrl.CloseSensors(c.sensors)
+	if serr := c.system.Close(); serr != nil {
+		err = serr
 	}
+	report := c.executor.Drain(timeout)
+	logDrainReport(c.executor.Name(), report)
 	return err
 }
 
-// InvocationNotificationSensor watches the invocations store notifications for workflow events.
+// maxReportedTasks is the maximum number of unfinished and abandoned tasks that are logged after draining an executor.
+const maxReportedTasks = 20
+
+func logDrainReport(name string, report *executor.DrainReport) {
+	logger := logrus.WithFields(logrus.Fields{
+		"executor":   name,
+		"finished":   report.Finished,
+		"unfinished": len(report.Unfinished),
+		"abandoned":  len(report.Abandoned),
+	})
+	if len(report.Unfinished) == 0 && len(report.Abandoned) == 0 {
+		logger.Info("Drained executor")
+		return
+	}
+	unfinished, abandoned := report.Unfinished, report.Abandoned
+	if len(unfinished) > maxReportedTasks {
+		unfinished = unfinished[:maxReportedTasks]
+	}
+	if len(abandoned) > maxReportedTasks {
+		abandoned = abandoned[:maxReportedTasks]
+	}
+	logger.Warnf("Drained executor with unfinished tasks %v and abandoned tasks %v", unfinished, abandoned)
+}
+
+// InvocationNotificationSensor watches the invocations store notifications for workflow events. It keeps the index of
+// running invocations up to date with the notifications, before the invocations are evaluated.
 type InvocationNotificationSensor struct {
 	invocations *store.Invocations
+	index       *RunningInvocations
 	done        func()
 	closeC      <-chan struct{}
+	running     *sync.WaitGroup
 }
 
-func NewInvocationNotificationSensor(invocations *store.Invocations) *InvocationNotificationSensor {
+func NewInvocationNotificationSensor(invocations *store.Invocations,
+	index *RunningInvocations) *InvocationNotificationSensor {
 	ctx, done := context.WithCancel(context.Background())
 	return &InvocationNotificationSensor{
 		invocations: invocations,
+		index:       index,
 		done:        done,
 		closeC:      ctx.Done(),
+		running:     &sync.WaitGroup{},
 	}
 }
 
 func (s *InvocationNotificationSensor) Start(evalQueue ctrl.EvalQueue) error {
-	go s.Run(evalQueue)
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.Run(evalQueue)
+	}()
 	return nil
 }
 
@@ -643,6 +1058,11 @@ func (s *InvocationNotificationSensor) Run(evalQueue ctrl.EvalQueue) {
 			if err != nil {
 				logrus.Warnf("Failed to convert pubsub message to notification: %v", err)
 			}
+			if notification != nil && s.index != nil {
+				if invocation, ok := notification.Updated.(*types.WorkflowInvocation); ok {
+					s.index.Update(invocation)
+				}
+			}
 			evalQueue.Submit(notification)
 		case <-s.closeC:
 			err := sub.Close()
@@ -655,21 +1075,27 @@ func (s *InvocationNotificationSensor) Run(evalQueue ctrl.EvalQueue) {
 	}
 }
 
+// Close stops listening for notifications, and waits for the notification that is being submitted.
 func (s *InvocationNotificationSensor) Close() error {
 	s.done()
+	s.running.Wait()
 	return nil
 }
 
-// InvocationStorePollSensor polls the invocations store on a set interval.
+// InvocationStorePollSensor polls the invocations store on a set interval. It updates the index of running
+// invocations with the polled invocations, in case that notifications were missed.
 type InvocationStorePollSensor struct {
 	*ctrl.PollSensor
 	invocations *store.Invocations
+	index       *RunningInvocations
 	system      *ctrl.System
 }
 
-func NewInvocationStorePollSensor(invocations *store.Invocations, interval time.Duration) *InvocationStorePollSensor {
+func NewInvocationStorePollSensor(invocations *store.Invocations, index *RunningInvocations,
+	interval time.Duration) *InvocationStorePollSensor {
 	s := &InvocationStorePollSensor{
 		invocations: invocations,
+		index:       index,
 	}
 	s.PollSensor = ctrl.NewPollSensor(interval, s.Poll)
 	return s
@@ -696,6 +1122,9 @@ func (s *InvocationStorePollSensor) Poll(evalQueue ctrl.EvalQueue) {
 			logrus.Warnf("Could not retrieve entity from invocations store: %v", aggregate)
 			continue
 		}
+		if s.index != nil {
+			s.index.Update(wf)
+		}
 
 		// Check if the status is not in a terminal state
 		switch wf.GetStatus().GetStatus() {
@@ -746,7 +1175,7 @@ func (s *StalenessPollSensor) Poll(queue ctrl.EvalQueue) {
 			return true
 		}
 		_, ok := s.system.GetController(ctrlKey)
-		if ok {
+		if ok || s.system.IsQuarantined(ctrlKey) {
 			return true
 		}
 
@@ -765,6 +1194,7 @@ func (s *StalenessPollSensor) Poll(queue ctrl.EvalQueue) {
 			}
 		}
 
+		stalenessRefreshes.WithLabelValues(s.system.Name()).Inc()
 		queue.Submit(&ctrl.Event{
 			Old:     entity,
 			Updated: entity,
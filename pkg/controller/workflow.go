@@ -13,6 +13,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/controller/executor"
 	"github.com/fission/fission-workflows/pkg/fes"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/golang/protobuf/ptypes"
 	log "github.com/sirupsen/logrus"
 )
@@ -47,7 +48,7 @@ func (c *WorkflowController) Eval(ctx context.Context, processValue *ctrl.Event)
 
 	// Do not evaluate as long as there still tasks to be executed
 	if c.executor.GetGroupTasks(workflow.ID()) > 0 {
-		return ctrl.Err{Err: errors.New("still executing tasks for workflow")}
+		return ctrl.Success{Msg: "still executing tasks for workflow"}
 	}
 
 	switch workflow.GetStatus().GetStatus() {
@@ -111,44 +112,68 @@ func NewWorkflowMetaController(api *api.Workflow, workflows *store.Workflows, ex
 			NewWorkflowNotificationSensor(workflows),
 			NewWorkflowStorePollSensor(workflows, storePollInterval),
 		},
-		system: ctrl.NewSystem(func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
+		system: ctrl.NewSystem("workflow", func(event *ctrl.Event) (ctrl ctrl.Controller, err error) {
 			return NewWorkflowController(api, executor, event.Aggregate.Id), nil
 		}),
 	}
 }
 
-func (c *WorkflowMetaController) Run() {
+// System returns the control system that evaluates the workflow controllers.
+func (c *WorkflowMetaController) System() *ctrl.System {
+	return c.system
+}
+
+// Run starts the executor, the sensors and the control system. It returns once they have been started, or returns an
+// error if a sensor could not be started.
+func (c *WorkflowMetaController) Run() error {
+	var err error
 	c.run.Do(func() {
 		// Start the task executor
 		c.executor.Start()
 
 		// Start the sensors
-		for _, sensor := range c.sensors {
-			err := sensor.Start(c.system)
-			if err != nil {
-				panic(err)
-			}
+		if err = ctrl.StartSensors(c.sensors, c.system); err != nil {
+			util.LogIfError(c.executor.Close())
+			return
 		}
 
 		// Run control system
 		c.system.Run()
 	})
+	return err
 }
 
+// Close stops the sensors, the control system and the executor, in that order, without waiting for the executing tasks.
 func (c *WorkflowMetaController) Close() error {
-	err := c.executor.Close()
-	err = c.system.Close()
-	for _, sensor := range c.sensors {
-		err = sensor.Close()
+	err := ctrl.CloseSensors(c.sensors)
+	if serr := c.system.Close(); serr != nil {
+		err = serr
+	}
+	if serr := c.executor.Close(); serr != nil {
+		err = serr
 	}
 	return err
 }
 
+// Drain stops the controller gracefully. It stops the sensors, evaluates the events that are still queued, and then
+// drains the executor, waiting at most for the timeout for the executing tasks to finish. The abandoned tasks are
+// executed again by the next controller once it evaluates their workflows.
+func (c *WorkflowMetaController) Drain(timeout time.Duration) error {
+	err := ctrl.CloseSensors(c.sensors)
+	if serr := c.system.Close(); serr != nil {
+		err = serr
+	}
+	report := c.executor.Drain(timeout)
+	logDrainReport(c.executor.Name(), report)
+	return err
+}
+
 // WorkflowNotificationSensor watches the workflow store notifications for workflow events.
 type WorkflowNotificationSensor struct {
 	workflows *store.Workflows
 	done      func()
 	closeC    <-chan struct{}
+	running   *sync.WaitGroup
 }
 
 func NewWorkflowNotificationSensor(workflows *store.Workflows) *WorkflowNotificationSensor {
@@ -157,11 +182,16 @@ func NewWorkflowNotificationSensor(workflows *store.Workflows) *WorkflowNotifica
 		workflows: workflows,
 		done:      done,
 		closeC:    ctx.Done(),
+		running:   &sync.WaitGroup{},
 	}
 }
 
 func (s *WorkflowNotificationSensor) Start(evalQueue ctrl.EvalQueue) error {
-	go s.Run(evalQueue)
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.Run(evalQueue)
+	}()
 	return nil
 }
 
@@ -191,8 +221,10 @@ func (s *WorkflowNotificationSensor) Run(evalQueue ctrl.EvalQueue) {
 	}
 }
 
+// Close stops listening for notifications, and waits for the notification that is being submitted.
 func (s *WorkflowNotificationSensor) Close() error {
 	s.done()
+	s.running.Wait()
 	return nil
 }
 
@@ -247,3 +279,37 @@ func (s *WorkflowStorePollSensor) Poll(evalQueue ctrl.EvalQueue) {
 		})
 	}
 }
+
+// ReparseWorkflows parses the ready workflows again that have a task with a function reference that matches, which
+// resolves the functions of the workflows again. This allows the workflow engine to pick up on changes to functions,
+// such as a function that was recreated. It returns the number of workflows that were parsed.
+func ReparseWorkflows(wfAPI *api.Workflow, workflows *store.Workflows, match func(ref types.FnRef) bool) int {
+	var parsed int
+	for _, aggregate := range workflows.List() {
+		if aggregate.Type != types.TypeWorkflow {
+			continue
+		}
+		wf, err := workflows.GetWorkflow(aggregate.GetId())
+		if err != nil {
+			log.Warnf("Could not retrieve entity from workflows store: %v", aggregate)
+			continue
+		}
+		if wf.GetStatus().GetStatus() != types.WorkflowStatus_READY {
+			continue
+		}
+
+		for _, task := range wf.GetStatus().GetTasks() {
+			fnRef := task.GetStatus().GetFnRef()
+			if fnRef == nil || !match(*fnRef) {
+				continue
+			}
+			if _, err := wfAPI.Parse(wf); err != nil {
+				log.Errorf("Failed to parse workflow %s again: %v", wf.ID(), err)
+			} else {
+				parsed++
+			}
+			break
+		}
+	}
+	return parsed
+}
@@ -0,0 +1,331 @@
+// package buffer contains a backend that buffers the events that cannot be appended to the event store, because it
+// is temporarily unavailable, and replays them once the event store is available again.
+//
+// While events are buffered, the backend reports that it is unavailable (see fes.AvailabilityChecker), which pauses
+// the acceptance of new invocations. The buffer is bounded; once it is full, appends fail. Optionally, the buffered
+// events are written to a local file, so that they are replayed after a restart as well.
+package buffer
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultMaxSize        = 10000
+	DefaultReplayInterval = time.Second
+)
+
+var (
+	bufferedEvents = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "fes",
+		Subsystem: "buffer",
+		Name:      "events",
+		Help:      "Number of events that are buffered until the event store is available.",
+	})
+
+	replayedEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "fes",
+		Subsystem: "buffer",
+		Name:      "replayed_events_total",
+		Help:      "Number of buffered events that were appended to the event store once it was available again.",
+	})
+
+	rejectedEvents = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "fes",
+		Subsystem: "buffer",
+		Name:      "rejected_events_total",
+		Help:      "Number of events that were rejected, because the event store was unavailable and the buffer was full.",
+	})
+)
+
+var errBufferFull = errors.New("the event store is unavailable and the buffer is full")
+
+func init() {
+	prometheus.MustRegister(bufferedEvents, replayedEvents, rejectedEvents)
+}
+
+// Config contains the user-configurable options of the buffer.
+type Config struct {
+	// MaxSize is the maximum number of buffered events. If 0, DefaultMaxSize is used.
+	MaxSize int
+
+	// ReplayInterval is the interval at which the buffered events are replayed. If 0, DefaultReplayInterval is used.
+	ReplayInterval time.Duration
+
+	// Path is the file to which the buffered events are written. If empty, the events are only buffered in memory.
+	Path string
+}
+
+// Backend wraps a backend, buffering the events that could not be appended because of temporary errors, such as a
+// lost connection.
+type Backend struct {
+	fes.Backend
+	cfg Config
+
+	// appendMu serializes the appends, so that an event is only appended directly if no earlier event is buffered.
+	appendMu sync.Mutex
+	mu       sync.Mutex
+	buffered []*fes.Event
+	file     *os.File
+
+	closeC    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// New creates a buffer for the backend, and starts replaying the buffered events. If the file at cfg.Path contains
+// events that were buffered before a restart, they are replayed first.
+func New(backend fes.Backend, cfg Config) (*Backend, error) {
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = DefaultMaxSize
+	}
+	if cfg.ReplayInterval <= 0 {
+		cfg.ReplayInterval = DefaultReplayInterval
+	}
+	b := &Backend{
+		Backend: backend,
+		cfg:     cfg,
+		closeC:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if len(cfg.Path) > 0 {
+		events, err := readEvents(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) > 0 {
+			logrus.Infof("Replaying %d event(s) that were buffered before the restart", len(events))
+		}
+		b.buffered = events
+		bufferedEvents.Set(float64(len(events)))
+		b.file, err = os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+		if err != nil {
+			return nil, err
+		}
+	}
+	go b.run()
+	return b, nil
+}
+
+// Append appends the event to the backend. If the backend is unavailable, or events are still buffered, the event is
+// buffered to preserve the order of the events. If the buffer is full, fes.ErrEventStoreOverflow is returned.
+func (b *Backend) Append(event *fes.Event) error {
+	// Without serializing the appends, a concurrent append could reach the backend while this event is being
+	// buffered, after which the events would be stored out of order.
+	b.appendMu.Lock()
+	defer b.appendMu.Unlock()
+
+	b.mu.Lock()
+	buffering := len(b.buffered) > 0
+	b.mu.Unlock()
+	if !buffering {
+		err := b.Backend.Append(event)
+		if err == nil || !isTemporary(err) {
+			return err
+		}
+		logrus.Warnf("Event store is unavailable; buffering events until it is available again: %v", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.buffered) >= b.cfg.MaxSize {
+		rejectedEvents.Inc()
+		return fes.ErrEventStoreOverflow.WithAggregate(event.GetAggregate()).WithError(errBufferFull)
+	}
+	if b.file != nil {
+		if err := writeEvent(b.file, event); err != nil {
+			return err
+		}
+	}
+	b.buffered = append(b.buffered, event)
+	bufferedEvents.Set(float64(len(b.buffered)))
+	return nil
+}
+
+// Get returns the events of the aggregate in the backend, followed by its buffered events.
+func (b *Backend) Get(aggregate fes.Aggregate) ([]*fes.Event, error) {
+	events, err := b.Backend.Get(aggregate)
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, event := range b.buffered {
+		key := event.GetAggregate()
+		if event.GetParent() != nil {
+			key = event.GetParent()
+		}
+		if key != nil && *key == aggregate {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// Available returns whether the backend is available, which is the case if no events are buffered.
+func (b *Backend) Available() bool {
+	return b.Len() == 0
+}
+
+// Len returns the number of buffered events.
+func (b *Backend) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buffered)
+}
+
+// Close stops replaying the events, after a final attempt to replay the buffered events. Events that are still
+// buffered are lost, unless they are written to a file. Subsequent calls return the result of the first call.
+func (b *Backend) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.closeC)
+		<-b.done
+		b.Replay()
+		if b.file != nil {
+			b.closeErr = b.file.Close()
+		}
+	})
+	return b.closeErr
+}
+
+func (b *Backend) run() {
+	defer close(b.done)
+	ticker := time.NewTicker(b.cfg.ReplayInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.closeC:
+			return
+		case <-ticker.C:
+			b.Replay()
+		}
+	}
+}
+
+// Replay appends the buffered events to the backend in order, until the buffer is empty or an append fails. It
+// returns the number of replayed events.
+//
+// If the process stops between appending an event and removing it from the file, the event is replayed again after
+// the restart. The projectors skip these duplicates based on the uid of the event.
+func (b *Backend) Replay() int {
+	var replayed, removed int
+	for {
+		// Only the replay removes events, so the first event is stable while it is being appended.
+		b.mu.Lock()
+		if len(b.buffered) == 0 {
+			b.mu.Unlock()
+			break
+		}
+		event := b.buffered[0]
+		b.mu.Unlock()
+
+		if err := b.Backend.Append(event); err != nil {
+			if isTemporary(err) {
+				logrus.Debugf("Event store is still unavailable: %v", err)
+				break
+			}
+			// Permanent errors, such as invalid events, will not resolve by retrying.
+			logrus.Errorf("Dropping buffered event %s of %s: %v", event.GetType(), event.GetAggregate().Format(), err)
+		} else {
+			replayed++
+			replayedEvents.Inc()
+		}
+
+		b.mu.Lock()
+		b.buffered[0] = nil
+		b.buffered = b.buffered[1:]
+		bufferedEvents.Set(float64(len(b.buffered)))
+		b.mu.Unlock()
+		removed++
+	}
+	if replayed > 0 {
+		logrus.Infof("Replayed %d buffered event(s) to the event store", replayed)
+	}
+	if removed > 0 {
+		b.compact()
+	}
+	return replayed
+}
+
+// compact rewrites the file with the events that are still buffered.
+func (b *Backend) compact() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.file == nil {
+		return
+	}
+	err := b.file.Truncate(0)
+	for _, event := range b.buffered {
+		if err != nil {
+			break
+		}
+		err = writeEvent(b.file, event)
+	}
+	if err != nil {
+		logrus.Errorf("Failed to rewrite the event buffer %s: %v", b.cfg.Path, err)
+	}
+}
+
+// isTemporary returns whether the error is temporary, such as a timeout or a lost connection to the event store.
+func isTemporary(err error) bool {
+	tmpErr, ok := err.(interface{ Temporary() bool })
+	return ok && tmpErr.Temporary()
+}
+
+// writeEvent writes the event to the file, prefixed by its length, and syncs the file.
+func writeEvent(file *os.File, event *fes.Event) error {
+	data, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+	if _, err := file.Write(append(size[:], data...)); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// readEvents reads the events that were written to the file. A truncated last event, such as an event that was being
+// written during a crash, is ignored.
+func readEvents(path string) ([]*fes.Event, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	r := bufio.NewReader(file)
+	var events []*fes.Event
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			break
+		}
+		data := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			logrus.Warnf("Ignoring truncated event at the end of the event buffer %s", path)
+			break
+		}
+		event := &fes.Event{}
+		if err := proto.Unmarshal(data, event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,220 @@
+package buffer
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type temporaryError struct {
+	error
+}
+
+func (err temporaryError) Temporary() bool {
+	return true
+}
+
+// flakyBackend is an in-memory backend that fails to append events with a temporary error while it is down.
+type flakyBackend struct {
+	*mem.Backend
+	mu   sync.Mutex
+	down bool
+}
+
+func (b *flakyBackend) Append(event *fes.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.down {
+		return temporaryError{errors.New("connection lost")}
+	}
+	return b.Backend.Append(event)
+}
+
+func (b *flakyBackend) setDown(down bool) {
+	b.mu.Lock()
+	b.down = down
+	b.mu.Unlock()
+}
+
+var key = fes.Aggregate{Type: "type", Id: "id"}
+
+func newEvent(data string) *fes.Event {
+	event, err := fes.NewEvent(key, &wrappers.StringValue{Value: data})
+	if err != nil {
+		panic(err)
+	}
+	return event
+}
+
+func eventData(t *testing.T, events []*fes.Event) []string {
+	var data []string
+	for _, event := range events {
+		msg, err := fes.ParseEventData(event)
+		require.NoError(t, err)
+		data = append(data, msg.(*wrappers.StringValue).GetValue())
+	}
+	return data
+}
+
+func TestBackend_BufferAndReplay(t *testing.T) {
+	flaky := &flakyBackend{Backend: mem.NewBackend()}
+	b, err := New(flaky, Config{MaxSize: 3, ReplayInterval: time.Hour})
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, b.Append(newEvent("a")))
+	assert.True(t, b.Available())
+
+	// While the backend is down, the events are buffered, but returned by Get.
+	flaky.setDown(true)
+	require.NoError(t, b.Append(newEvent("b")))
+	require.NoError(t, b.Append(newEvent("c")))
+	assert.False(t, b.Available())
+	assert.Equal(t, 2, b.Len())
+	events, err := b.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, eventData(t, events))
+	assert.Equal(t, 0, b.Replay())
+
+	// Events are buffered until the buffer has been replayed, to preserve their order.
+	flaky.setDown(false)
+	require.NoError(t, b.Append(newEvent("d")))
+	assert.Equal(t, 3, b.Len())
+	err = b.Append(newEvent("e"))
+	assert.True(t, fes.ErrEventStoreOverflow.Is(err), "unexpected error: %v", err)
+
+	assert.Equal(t, 3, b.Replay())
+	assert.True(t, b.Available())
+	events, err = flaky.Backend.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, eventData(t, events))
+}
+
+// stallingBackend is an in-memory backend that stalls the first append until it is released, after which it fails
+// with a temporary error.
+type stallingBackend struct {
+	*mem.Backend
+	once    sync.Once
+	stalled chan struct{}
+	release chan struct{}
+}
+
+func (b *stallingBackend) Append(event *fes.Event) error {
+	var stall bool
+	b.once.Do(func() {
+		stall = true
+	})
+	if stall {
+		close(b.stalled)
+		<-b.release
+		return temporaryError{errors.New("connection lost")}
+	}
+	return b.Backend.Append(event)
+}
+
+func TestBackend_ConcurrentAppends(t *testing.T) {
+	stalling := &stallingBackend{
+		Backend: mem.NewBackend(),
+		stalled: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	b, err := New(stalling, Config{ReplayInterval: time.Hour})
+	require.NoError(t, err)
+	defer b.Close()
+
+	// An append that starts while the failing append of an earlier event is in progress, is buffered after it.
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, b.Append(newEvent("a")))
+	}()
+	<-stalling.stalled
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, b.Append(newEvent("b")))
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(stalling.release)
+	wg.Wait()
+
+	assert.Equal(t, 2, b.Replay())
+	events, err := stalling.Backend.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, eventData(t, events))
+}
+
+func TestBackend_CloseTwice(t *testing.T) {
+	b, err := New(mem.NewBackend(), Config{})
+	require.NoError(t, err)
+	assert.NoError(t, b.Close())
+	assert.NoError(t, b.Close())
+}
+
+func TestBackend_PermanentErrors(t *testing.T) {
+	flaky := &flakyBackend{Backend: mem.NewBackend()}
+	b, err := New(flaky, Config{ReplayInterval: time.Hour})
+	require.NoError(t, err)
+	defer b.Close()
+
+	// Errors that are not temporary are not buffered.
+	err = b.Append(&fes.Event{})
+	assert.Error(t, err)
+	assert.True(t, b.Available())
+}
+
+func TestBackend_ReplayInterval(t *testing.T) {
+	flaky := &flakyBackend{Backend: mem.NewBackend(), down: true}
+	b, err := New(flaky, Config{ReplayInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer b.Close()
+
+	require.NoError(t, b.Append(newEvent("a")))
+	assert.False(t, b.Available())
+	flaky.setDown(false)
+	for deadline := time.Now().Add(time.Second); !b.Available(); {
+		require.True(t, time.Now().Before(deadline), "buffer was not replayed")
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBackend_File(t *testing.T) {
+	dir, err := ioutil.TempDir("", "buffer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "events")
+
+	flaky := &flakyBackend{Backend: mem.NewBackend(), down: true}
+	b, err := New(flaky, Config{ReplayInterval: time.Hour, Path: path})
+	require.NoError(t, err)
+	require.NoError(t, b.Append(newEvent("a")))
+	require.NoError(t, b.Append(newEvent("b")))
+	require.NoError(t, b.Close())
+
+	// The events that were buffered before the restart are replayed.
+	b, err = New(flaky, Config{ReplayInterval: time.Hour, Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, 2, b.Len())
+	flaky.setDown(false)
+	assert.Equal(t, 2, b.Replay())
+	require.NoError(t, b.Close())
+	events, err := flaky.Backend.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, eventData(t, events))
+
+	// Replayed events are removed from the file.
+	b, err = New(flaky, Config{ReplayInterval: time.Hour, Path: path})
+	require.NoError(t, err)
+	assert.Equal(t, 0, b.Len())
+	require.NoError(t, b.Close())
+}
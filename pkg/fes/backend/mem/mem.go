@@ -172,6 +172,7 @@ func (b *Backend) Len() int {
 }
 
 // Note: list does not return the cache contents
+// List returns the keys of the active aggregates, as well as of the completed aggregates that have not been evicted.
 func (b *Backend) List(matcher fes.AggregateMatcher) ([]fes.Aggregate, error) {
 	var results []fes.Aggregate
 	b.storeLock.RLock()
@@ -180,6 +181,12 @@ func (b *Backend) List(matcher fes.AggregateMatcher) ([]fes.Aggregate, error) {
 			results = append(results, key)
 		}
 	}
+	for _, k := range b.buf.Keys() {
+		key := assertAggregate(k)
+		if matcher == nil || matcher(key) {
+			results = append(results, key)
+		}
+	}
 	b.storeLock.RUnlock()
 	return results, nil
 }
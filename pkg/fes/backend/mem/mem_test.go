@@ -138,6 +138,26 @@ func TestBackendBufferEvict(t *testing.T) {
 	assert.Equal(t, 3, mem.Len())
 }
 
+func TestBackend_List(t *testing.T) {
+	mem := setupBackend()
+	active := fes.Aggregate{Type: "entity", Id: "active"}
+	completed := fes.Aggregate{Type: "entity", Id: "completed"}
+	assert.NoError(t, mem.Append(newEvent(active, []byte("active stream"))))
+	event := newEvent(completed, []byte("completed stream"))
+	event.Hints = &fes.EventHints{
+		Completed: true,
+	}
+	assert.NoError(t, mem.Append(event))
+	assert.NoError(t, mem.Append(newEvent(fes.Aggregate{Type: "other", Id: "1"}, []byte("other stream"))))
+	assert.Equal(t, 1, mem.buf.Len())
+
+	keys, err := mem.List(func(key fes.Aggregate) bool {
+		return key.Type == "entity"
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []fes.Aggregate{active, completed}, keys)
+}
+
 func TestBackend_Append(t *testing.T) {
 	mem := setupBackend()
 
@@ -221,6 +221,12 @@ func (es *EventStore) Close() error {
 	return nil
 }
 
+// NatsConn returns the underlying (non-streaming) NATS connection, which can be used to publish messages that do not
+// need to be persisted.
+func (es *EventStore) NatsConn() *nats.Conn {
+	return es.conn.NatsConn()
+}
+
 // Append publishes (and persists) an event on the NATS message queue
 func (es *EventStore) Append(event *fes.Event) error {
 	if err := fes.ValidateEvent(event); err != nil {
@@ -239,7 +245,7 @@ func (es *EventStore) Append(event *fes.Event) error {
 
 	err = es.conn.Publish(subject, data)
 	if err != nil {
-		return err
+		return publishError{err}
 	}
 
 	logrus.WithFields(logrus.Fields{
@@ -252,6 +258,20 @@ func (es *EventStore) Append(event *fes.Event) error {
 	return nil
 }
 
+// publishError is a failure to publish an event to NATS, such as a timeout or a lost connection. It is reported as
+// temporary, so that the event is appended again once NATS is available.
+type publishError struct {
+	error
+}
+
+func (err publishError) Temporary() bool {
+	return true
+}
+
+func (err publishError) Cause() error {
+	return err.error
+}
+
 // Get returns all events related to a specific aggregate
 func (es *EventStore) Get(aggregate fes.Aggregate) ([]*fes.Event, error) {
 	if err := fes.ValidateAggregate(&aggregate); err != nil {
@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/fes"
@@ -18,10 +20,24 @@ var (
 		Name:      "current_cache_counts",
 		Help:      "The current number of entries in the caches",
 	}, []string{"name"})
+
+	rebuiltEntities = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "fes",
+		Subsystem: "cache",
+		Name:      "rebuilt_entities_total",
+		Help:      "Number of cached entities that were rebuilt from the events in the backend, labeled by their type.",
+	}, []string{"type"})
 )
 
+var errRebuildUnsupported = errors.New("the cache does not support rebuilding entities")
+
+// resyncer is implemented by caches that resync with the backend, rebuilding the entities with the provided function.
+type resyncer interface {
+	resync(matcher fes.AggregateMatcher, rebuild func(key fes.Aggregate) (fes.Entity, error)) (*fes.ResyncResult, error)
+}
+
 func init() {
-	prometheus.MustRegister(cacheCount)
+	prometheus.MustRegister(cacheCount, rebuiltEntities)
 }
 
 type LRUCache struct {
@@ -86,6 +102,9 @@ type SubscribedCache struct {
 	createdAt time.Time
 	projector fes.Projector
 	closeC    chan struct{}
+
+	// mu ensures that rebuilds do not overwrite the events that are applied concurrently.
+	mu sync.Mutex
 }
 
 func NewSubscribedCache(cache fes.CacheReaderWriter, projector fes.Projector,
@@ -137,6 +156,9 @@ func (uc *SubscribedCache) applyEvent(event *fes.Event) error {
 		return err
 	}
 
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
 	// Attempt to fetch the entity from the cache.
 	old, err := uc.getOrCreateAggregateForEvent(event)
 	if err != nil {
@@ -172,6 +194,28 @@ func (uc *SubscribedCache) applyEvent(event *fes.Event) error {
 	return nil
 }
 
+// Rebuild discards the cached entity of the aggregate, and rebuilds it from the events in the backend, if the
+// underlying cache supports it. No notifications are published for the rebuilt entity.
+func (uc *SubscribedCache) Rebuild(key fes.Aggregate) (fes.Entity, error) {
+	rebuilder, ok := uc.CacheReaderWriter.(fes.CacheRebuilder)
+	if !ok {
+		return nil, errRebuildUnsupported
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return rebuilder.Rebuild(key)
+}
+
+// Resync resyncs the underlying cache with the backend, if it supports it. The events that arrive during the resync
+// are applied in between the rebuilds of the entities, so that they are not dropped.
+func (uc *SubscribedCache) Resync(matcher fes.AggregateMatcher) (*fes.ResyncResult, error) {
+	r, ok := uc.CacheReaderWriter.(resyncer)
+	if !ok {
+		return nil, errRebuildUnsupported
+	}
+	return r.resync(matcher, uc.Rebuild)
+}
+
 func (uc *SubscribedCache) Close() error {
 	close(uc.closeC)
 	return nil
@@ -297,6 +341,57 @@ func (c *LoadingCache) Refresh(key fes.Aggregate) {
 	}
 }
 
+// Rebuild discards the cached entity of the aggregate, and rebuilds it from the events in the backend. If the
+// aggregate has no events in the backend, fes.ErrEntityNotFound is returned.
+func (c *LoadingCache) Rebuild(key fes.Aggregate) (fes.Entity, error) {
+	if err := fes.ValidateAggregate(&key); err != nil {
+		return nil, err
+	}
+	c.Invalidate(key)
+	entity, err := c.getFromEventStore(key)
+	if err != nil {
+		return nil, err
+	}
+	rebuiltEntities.WithLabelValues(key.Type).Inc()
+	return entity, nil
+}
+
+// Resync rebuilds the entities of all aggregates in the backend that match the matcher, and discards the cached
+// entities that match, but no longer have events in the backend. Aggregates that fail to rebuild do not stop the
+// resync; their errors are collected in the result.
+func (c *LoadingCache) Resync(matcher fes.AggregateMatcher) (*fes.ResyncResult, error) {
+	return c.resync(matcher, c.Rebuild)
+}
+
+func (c *LoadingCache) resync(matcher fes.AggregateMatcher,
+	rebuild func(key fes.Aggregate) (fes.Entity, error)) (*fes.ResyncResult, error) {
+	keys, err := c.client.List(matcher)
+	if err != nil {
+		return nil, err
+	}
+	result := &fes.ResyncResult{
+		Failed: map[fes.Aggregate]error{},
+	}
+	stored := map[fes.Aggregate]bool{}
+	for _, key := range keys {
+		stored[key] = true
+		if _, err := rebuild(key); err != nil {
+			result.Failed[key] = err
+			continue
+		}
+		result.Rebuilt++
+	}
+	for _, key := range c.CacheReaderWriter.List() {
+		if !stored[key] && (matcher == nil || matcher(key)) {
+			c.Invalidate(key)
+			result.Removed++
+		}
+	}
+	logrus.Infof("Resynced cache with the backend: rebuilt %d, removed %d and failed to rebuild %d entities",
+		result.Rebuilt, result.Removed, len(result.Failed))
+	return result, nil
+}
+
 // To ensure that tasks end up in invocation entities: if an event has a parent aggregate,
 // this means that the event should be send to the parent aggregate instead.
 func getKey(e *fes.Event) fes.Aggregate {
@@ -41,6 +41,58 @@ func TestLoadingCache_GetAggregateStoreAndCache(t *testing.T) {
 	assert.Equal(t, target, cachedEntity.S)
 }
 
+func TestLoadingCache_Rebuild(t *testing.T) {
+	cache, backingCache, eventStore := setupLoadingCache()
+	key := fes.Aggregate{Type: testutil.MockEntityType, Id: "1"}
+	for _, event := range testutil.ToDummyEvents(key, "abc") {
+		assert.NoError(t, eventStore.Append(event))
+	}
+
+	// Corrupt the cached entity, after which the rebuild restores it from the events.
+	assert.NoError(t, backingCache.Put(&testutil.MockEntity{Id: key.Id, S: "corrupted"}))
+	e, err := cache.Rebuild(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", e.(*testutil.MockEntity).S)
+	cached, err := backingCache.GetAggregate(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", cached.(*testutil.MockEntity).S)
+
+	// Entities without events are discarded.
+	missing := fes.Aggregate{Type: testutil.MockEntityType, Id: "2"}
+	assert.NoError(t, backingCache.Put(&testutil.MockEntity{Id: missing.Id, S: "stale"}))
+	_, err = cache.Rebuild(missing)
+	assert.Error(t, err)
+	_, err = backingCache.GetAggregate(missing)
+	assert.Equal(t, fes.ErrEntityNotFound, err)
+}
+
+func TestLoadingCache_Resync(t *testing.T) {
+	cache, backingCache, eventStore := setupLoadingCache()
+	key1 := fes.Aggregate{Type: testutil.MockEntityType, Id: "1"}
+	key2 := fes.Aggregate{Type: testutil.MockEntityType, Id: "2"}
+	for _, event := range append(testutil.ToDummyEvents(key1, "ab"), testutil.ToDummyEvents(key2, "cd")...) {
+		assert.NoError(t, eventStore.Append(event))
+	}
+	assert.NoError(t, backingCache.Put(&testutil.MockEntity{Id: key1.Id, S: "corrupted"}))
+	assert.NoError(t, backingCache.Put(&testutil.MockEntity{Id: "3", S: "stale"}))
+
+	result, err := cache.Resync(func(key fes.Aggregate) bool {
+		return key.Type == testutil.MockEntityType
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Rebuilt)
+	assert.Equal(t, 1, result.Removed)
+	assert.Empty(t, result.Failed)
+
+	e, err := backingCache.GetAggregate(key1)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", e.(*testutil.MockEntity).S)
+	e, err = backingCache.GetAggregate(key2)
+	assert.NoError(t, err)
+	assert.Equal(t, "cd", e.(*testutil.MockEntity).S)
+	assert.Len(t, backingCache.List(), 2)
+}
+
 func TestLRUCache(t *testing.T) {
 	cache := NewLRUCache(2)
 	e1, _ := testutil.Projector.NewProjection(fes.Aggregate{Type: "test", Id: "1"})
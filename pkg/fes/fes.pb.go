@@ -64,6 +64,9 @@ type Event struct {
 	Parent    *Aggregate                 `protobuf:"bytes,6,opt,name=parent" json:"parent,omitempty"`
 	Hints     *EventHints                `protobuf:"bytes,7,opt,name=hints" json:"hints,omitempty"`
 	Metadata  map[string]string          `protobuf:"bytes,8,rep,name=metadata" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Uid uniquely identifies the event. Unlike the id, which is assigned by the backend, it is assigned when the
+	// event is created, so that an event that is published or delivered more than once can be detected.
+	Uid string `protobuf:"bytes,9,opt,name=uid" json:"uid,omitempty"`
 }
 
 func (m *Event) Reset()                    { *m = Event{} }
@@ -127,6 +130,13 @@ func (m *Event) GetMetadata() map[string]string {
 	return nil
 }
 
+func (m *Event) GetUid() string {
+	if m != nil {
+		return m.Uid
+	}
+	return ""
+}
+
 // EventHints is a collection of optional metadata that help components in the event store to improve performance.
 type EventHints struct {
 	Completed bool `protobuf:"varint,1,opt,name=completed" json:"completed,omitempty"`
@@ -153,28 +163,28 @@ func init() {
 func init() { proto.RegisterFile("pkg/fes/fes.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 353 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0x41, 0x4b, 0xfb, 0x30,
-	0x18, 0xc6, 0x69, 0xbb, 0xee, 0xbf, 0xbe, 0xe3, 0x2f, 0x1a, 0x76, 0xa8, 0x65, 0xe0, 0xd8, 0xc5,
-	0xe2, 0x21, 0xc5, 0x79, 0x19, 0x0a, 0xca, 0x84, 0x81, 0x97, 0x5d, 0x8a, 0x27, 0x6f, 0x99, 0x7b,
-	0x5b, 0xcb, 0xda, 0xa4, 0x34, 0xd9, 0x46, 0x3f, 0xaf, 0x5f, 0x44, 0x9a, 0xae, 0x2b, 0x2a, 0x8c,
-	0x79, 0x28, 0xa4, 0xc9, 0xf3, 0x7b, 0xf2, 0x3c, 0x79, 0xe1, 0x22, 0x5f, 0xc7, 0x41, 0x84, 0xb2,
-	0xfa, 0x68, 0x5e, 0x08, 0x25, 0xc8, 0x30, 0x4a, 0xa4, 0x4c, 0x04, 0xa7, 0x3b, 0x51, 0xac, 0xa3,
-	0x54, 0xec, 0x24, 0xc5, 0x2d, 0x72, 0x25, 0x95, 0x28, 0xd0, 0xbb, 0x8a, 0x85, 0x88, 0x53, 0x0c,
-	0xb4, 0x76, 0xb9, 0x89, 0x02, 0x95, 0x64, 0x28, 0x15, 0xcb, 0xf2, 0x1a, 0xf7, 0x2e, 0x7f, 0x0a,
-	0x18, 0x2f, 0xeb, 0xa3, 0x71, 0x00, 0xce, 0x2c, 0x8e, 0x0b, 0x8c, 0x99, 0x42, 0x72, 0x06, 0x66,
-	0xb2, 0x72, 0x8d, 0x91, 0xe1, 0x3b, 0xa1, 0x99, 0xac, 0x08, 0x81, 0x8e, 0x2a, 0x73, 0x74, 0x4d,
-	0xbd, 0xa3, 0xd7, 0xe3, 0x4f, 0x0b, 0xec, 0x79, 0x75, 0xf7, 0x29, 0x6a, 0x32, 0x07, 0x87, 0x35,
-	0xf6, 0xae, 0x35, 0x32, 0xfc, 0xfe, 0xe4, 0x9a, 0x1e, 0x2b, 0x43, 0x0f, 0x69, 0xc2, 0x96, 0x24,
-	0x53, 0x70, 0x0e, 0x9d, 0xdc, 0x8e, 0xb6, 0xf1, 0x68, 0x5d, 0x8a, 0x36, 0xa5, 0xe8, 0x6b, 0xa3,
-	0x08, 0x5b, 0x31, 0xf1, 0xa1, 0xb3, 0x62, 0x8a, 0xb9, 0xb6, 0x86, 0x06, 0xbf, 0xa0, 0x19, 0x2f,
-	0x43, 0xad, 0x20, 0x4f, 0xd0, 0xcd, 0x59, 0x81, 0x5c, 0xb9, 0xdd, 0xbf, 0xe5, 0xdc, 0x63, 0xe4,
-	0x11, 0xec, 0x8f, 0x84, 0x2b, 0xe9, 0xfe, 0xd3, 0xbc, 0x7f, 0x9c, 0xd7, 0x6f, 0xf8, 0x52, 0xe9,
-	0xc3, 0x1a, 0x23, 0x0b, 0xe8, 0x65, 0xa8, 0x98, 0x8e, 0xdb, 0x1b, 0x59, 0x7e, 0x7f, 0x72, 0x7b,
-	0x82, 0x05, 0x5d, 0xec, 0x99, 0x39, 0x57, 0x45, 0x19, 0x1e, 0x2c, 0xbc, 0x07, 0xf8, 0xff, 0xed,
-	0x88, 0x9c, 0x83, 0xb5, 0xc6, 0x72, 0x3f, 0xb0, 0x6a, 0x49, 0x06, 0x60, 0x6f, 0x59, 0xba, 0x69,
-	0x46, 0x56, 0xff, 0xdc, 0x9b, 0x53, 0x63, 0x7c, 0x03, 0xd0, 0x06, 0x24, 0x43, 0x70, 0xde, 0x45,
-	0x96, 0xa7, 0xa8, 0xb0, 0x1e, 0x78, 0x2f, 0x6c, 0x37, 0x9e, 0xed, 0x37, 0x2b, 0x42, 0xb9, 0xec,
-	0xea, 0x37, 0xbd, 0xfb, 0x0a, 0x00, 0x00, 0xff, 0xff, 0xf1, 0xee, 0x2c, 0xf3, 0xbf, 0x02, 0x00,
-	0x00,
+	// 359 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0xcf, 0x6b, 0xa3, 0x40,
+	0x14, 0x80, 0x51, 0x63, 0x36, 0xbe, 0xb0, 0xcb, 0xee, 0x90, 0xc3, 0xac, 0x04, 0x1a, 0x72, 0xa9,
+	0xf4, 0x30, 0xd2, 0xf4, 0x12, 0x5a, 0x68, 0x49, 0x21, 0xd0, 0x4b, 0x2e, 0xd2, 0x53, 0x6f, 0x93,
+	0xfa, 0xb4, 0x12, 0x75, 0xc4, 0x99, 0x24, 0xf8, 0x87, 0xf7, 0x5e, 0x1c, 0x63, 0xa4, 0x2d, 0x84,
+	0xf4, 0x20, 0x3c, 0xc7, 0xef, 0x7b, 0xbe, 0x1f, 0x03, 0xff, 0x8a, 0x4d, 0xec, 0x47, 0x28, 0xeb,
+	0x87, 0x15, 0xa5, 0x50, 0x82, 0x8c, 0xa3, 0x44, 0xca, 0x44, 0xe4, 0x6c, 0x2f, 0xca, 0x4d, 0x94,
+	0x8a, 0xbd, 0x64, 0xb8, 0xc3, 0x5c, 0x49, 0x25, 0x4a, 0x74, 0x2f, 0x62, 0x21, 0xe2, 0x14, 0x7d,
+	0xcd, 0xae, 0xb7, 0x91, 0xaf, 0x92, 0x0c, 0xa5, 0xe2, 0x59, 0xd1, 0xe8, 0xee, 0xff, 0xaf, 0x00,
+	0xcf, 0xab, 0xe6, 0xd3, 0xd4, 0x07, 0x67, 0x11, 0xc7, 0x25, 0xc6, 0x5c, 0x21, 0xf9, 0x03, 0x66,
+	0x12, 0x52, 0x63, 0x62, 0x78, 0x4e, 0x60, 0x26, 0x21, 0x21, 0xd0, 0x53, 0x55, 0x81, 0xd4, 0xd4,
+	0x27, 0x3a, 0x9e, 0xbe, 0x5b, 0x60, 0x2f, 0xeb, 0x7f, 0x9f, 0x43, 0x93, 0x25, 0x38, 0xbc, 0x4d,
+	0x4f, 0xad, 0x89, 0xe1, 0x0d, 0x67, 0x97, 0xec, 0x54, 0x33, 0xec, 0x58, 0x4d, 0xd0, 0x99, 0x64,
+	0x0e, 0xce, 0xb1, 0x27, 0xda, 0xd3, 0x69, 0x5c, 0xd6, 0x34, 0xc5, 0xda, 0xa6, 0xd8, 0x73, 0x4b,
+	0x04, 0x1d, 0x4c, 0x3c, 0xe8, 0x85, 0x5c, 0x71, 0x6a, 0x6b, 0x69, 0xf4, 0x4d, 0x5a, 0xe4, 0x55,
+	0xa0, 0x09, 0xf2, 0x00, 0xfd, 0x82, 0x97, 0x98, 0x2b, 0xda, 0xff, 0x59, 0x9d, 0x07, 0x8d, 0xdc,
+	0x83, 0xfd, 0x96, 0xe4, 0x4a, 0xd2, 0x5f, 0xda, 0xf7, 0x4e, 0xfb, 0x7a, 0x86, 0x4f, 0x35, 0x1f,
+	0x34, 0x1a, 0x59, 0xc1, 0x20, 0x43, 0xc5, 0x75, 0xb9, 0x83, 0x89, 0xe5, 0x0d, 0x67, 0xd7, 0x67,
+	0xa4, 0x60, 0xab, 0x83, 0xb3, 0xcc, 0x55, 0x59, 0x05, 0xc7, 0x14, 0xe4, 0x2f, 0x58, 0xdb, 0x24,
+	0xa4, 0x8e, 0xde, 0x46, 0x1d, 0xba, 0x77, 0xf0, 0xfb, 0x13, 0x5c, 0x23, 0x1b, 0xac, 0x0e, 0x2b,
+	0xac, 0x43, 0x32, 0x02, 0x7b, 0xc7, 0xd3, 0x6d, 0xbb, 0xc4, 0xe6, 0xe5, 0xd6, 0x9c, 0x1b, 0xd3,
+	0x2b, 0x80, 0xae, 0x64, 0x32, 0x06, 0xe7, 0x55, 0x64, 0x45, 0x8a, 0x0a, 0x9b, 0x2b, 0x30, 0x08,
+	0xba, 0x83, 0x47, 0xfb, 0xc5, 0x8a, 0x50, 0xae, 0xfb, 0x7a, 0xca, 0x37, 0x1f, 0x03, 0x00, 0x08,
+	0x83, 0x48, 0x81, 0xd1, 0x02, 0x00, 0x00,
 }
@@ -122,7 +122,9 @@ func (b *Backend) List(matcher fes.AggregateMatcher) ([]fes.Aggregate, error) {
 	defer b.lock.RUnlock()
 	var keys []fes.Aggregate
 	for k := range b.events {
-		keys = append(keys, k)
+		if matcher == nil || matcher(k) {
+			keys = append(keys, k)
+		}
 	}
 	return keys, nil
 }
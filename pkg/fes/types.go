@@ -50,6 +50,32 @@ type CacheRefresher interface {
 	Refresh(key Aggregate)
 }
 
+// CacheRebuilder is implemented by caches that can discard their cached entities and rebuild them from the events in
+// the backend, such as to repair entities that were corrupted by a bug in a projector.
+type CacheRebuilder interface {
+	// Rebuild discards the cached entity of the aggregate, and rebuilds it from the events in the backend.
+	Rebuild(key Aggregate) (Entity, error)
+
+	// Resync rebuilds the entities of all aggregates in the backend that match the matcher, and discards the cached
+	// entities that match, but no longer have events in the backend.
+	Resync(matcher AggregateMatcher) (*ResyncResult, error)
+}
+
+// ResyncResult summarizes the resync of a cache with the backend.
+type ResyncResult struct {
+	Rebuilt int
+	Removed int
+
+	// Failed contains the errors of the aggregates that could not be rebuilt.
+	Failed map[Aggregate]error
+}
+
+// AvailabilityChecker is implemented by backends that can be temporarily unavailable, such as a backend that buffers
+// the events while the event store cannot be reached.
+type AvailabilityChecker interface {
+	Available() bool
+}
+
 type CacheWriter interface {
 	Put(entity Entity) error
 	Invalidate(entity Aggregate)
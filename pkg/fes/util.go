@@ -9,6 +9,8 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/opentracing/opentracing-go"
+	"github.com/satori/go.uuid"
+	"github.com/uber/jaeger-client-go"
 )
 
 // NewEvent returns a new event with the provided payload for the provided aggregate or an error if the input data
@@ -44,6 +46,7 @@ func NewEvent(aggregate Aggregate, payload proto.Message) (*Event, error) {
 		Timestamp: ptypes.TimestampNow(),
 		Type:      t,
 		Metadata:  map[string]string{},
+		Uid:       uuid.NewV4().String(),
 	}, nil
 }
 
@@ -70,6 +73,18 @@ func ExtractTracingFromEventMetadata(metadata map[string]string) (opentracing.Sp
 	return ctx, nil
 }
 
+// TraceID returns the id of the trace of the span context as a 32-character hex string, which both Jaeger and
+// OpenTelemetry backends accept. It returns an empty string if the trace is not sampled, as it cannot be looked up in
+// the tracing backend, or if the span context is not of the Jaeger tracer.
+func TraceID(spanCtx opentracing.SpanContext) string {
+	jaegerCtx, ok := spanCtx.(jaeger.SpanContext)
+	if !ok || !jaegerCtx.IsSampled() {
+		return ""
+	}
+	traceID := jaegerCtx.TraceID()
+	return fmt.Sprintf("%016x%016x", traceID.High, traceID.Low)
+}
+
 func GetAggregate(v Entity) Aggregate {
 	var t string
 	if ct, ok := v.(CustomType); ok {
@@ -0,0 +1,388 @@
+// Package container provides a function environment that runs a container image for each task.
+//
+// It is useful for orchestrating CLI tools that were never wrapped as functions. The containers are run with a
+// Docker-compatible CLI, such as docker or podman. The main input of the task is written to the stdin of the
+// container, and the stdout of the container is captured as the output of the task.
+package container
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	Name = "container"
+
+	InputCommand = "command"
+	InputArgs    = "args"
+	InputEnv     = "env"
+
+	DefaultCommand       = "docker"
+	DefaultMaxOutputSize = 4 * 1024 * 1024
+	maxErrorSize         = 1000
+)
+
+var (
+	ErrUnsupportedRuntime = errors.New("fnenv/container: function reference should have the container:// scheme")
+	ErrOutputTooLarge     = errors.New("fnenv/container: stdout exceeds the maximum output size")
+
+	log = logrus.WithField("component", "fnenv.container")
+)
+
+// Config contains the configuration of the container function environment.
+type Config struct {
+	// Command is the Docker-compatible CLI that is used to run the containers (default: docker).
+	Command string
+
+	// RunArgs are additional arguments of the run command, such as --network=host or --memory=1g.
+	RunArgs []string
+
+	// MaxOutputSize is the maximum number of bytes of the stdout that are captured as the output. If the stdout of a
+	// container exceeds it, the task fails.
+	MaxOutputSize int64
+}
+
+// FunctionEnv runs a container for each task invocation.
+//
+// Function references have the format container://[registry]/image, for example
+// container://docker.io/library/alpine:3.12 or container:///alpine:3.12.
+type FunctionEnv struct {
+	cfg Config
+}
+
+func New(cfg Config) *FunctionEnv {
+	if len(cfg.Command) == 0 {
+		cfg.Command = DefaultCommand
+	}
+	if cfg.MaxOutputSize <= 0 {
+		cfg.MaxOutputSize = DefaultMaxOutputSize
+	}
+	return &FunctionEnv{
+		cfg: cfg,
+	}
+}
+
+// Resolve resolves the function reference to the image. Only explicit container:// references are resolved.
+func (fe *FunctionEnv) Resolve(ref types.FnRef) (string, error) {
+	if ref.Runtime != Name {
+		return "", ErrUnsupportedRuntime
+	}
+	if err := types.ValidateFnRef(ref, false); err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+// Invoke runs the container of the task in a blocking way.
+func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	cfg := fnenv.ParseInvokeOptions(opts)
+	ctx := cfg.Ctx
+	if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil {
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	name := "wf-" + util.UID()
+	env, err := formatEnv(spec.GetInputs())
+	if err != nil {
+		return nil, err
+	}
+	var envFile string
+	if len(env) > 0 {
+		envFile, err = writeEnvFile(env)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(envFile)
+	}
+	args, err := fe.formatRunArgs(name, envFile, spec)
+	if err != nil {
+		return nil, err
+	}
+	stdin, err := formatStdin(spec.GetInputs())
+	if err != nil {
+		return nil, err
+	}
+
+	stdout := &limitedBuffer{max: fe.cfg.MaxOutputSize}
+	stderr := &limitedBuffer{max: maxErrorSize}
+	cmd := exec.CommandContext(ctx, fe.cfg.Command, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	ctxLog := log.WithFields(logrus.Fields{
+		"container": name,
+		"image":     image(spec.GetFnRef()),
+	})
+	ctxLog.Info("Running container")
+	fnenv.FnActive.WithLabelValues(Name).Inc()
+	fnenv.FnCount.WithLabelValues(Name).Inc()
+	start := time.Now()
+	err = cmd.Run()
+	fnenv.FnActive.WithLabelValues(Name).Dec()
+	fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(start) / time.Millisecond))
+
+	if ctx.Err() != nil {
+		// Killing the CLI does not necessarily stop the container itself.
+		fe.remove(name)
+		return nil, fmt.Errorf("container %s did not finish: %v", name, ctx.Err())
+	}
+	if err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			return nil, fmt.Errorf("failed to run container: %v", err)
+		}
+		ctxLog.Infof("Container failed with exit code %d", exitErr.ExitCode())
+		msg := fmt.Sprintf("container %s failed with exit code %d", name, exitErr.ExitCode())
+		if stderr.Len() > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, strings.TrimSpace(stderr.String()))
+		}
+		return &types.TaskInvocationStatus{
+			Status: types.TaskInvocationStatus_FAILED,
+			Error: &types.Error{
+				Message:    msg,
+				StatusCode: int32(exitErr.ExitCode()),
+			},
+		}, nil
+	}
+	ctxLog.Infof("Container finished (%v)", time.Since(start))
+	if stdout.exceeded {
+		return nil, fmt.Errorf("%v of %d bytes", ErrOutputTooLarge, fe.cfg.MaxOutputSize)
+	}
+
+	output, err := parseOutput(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: output,
+	}, nil
+}
+
+// formatRunArgs returns the arguments of the run command. The environment variables of the container are read from
+// the env file, if any, so that neither their values are exposed in the arguments, nor the environment of the CLI
+// itself is affected by the task.
+func (fe *FunctionEnv) formatRunArgs(name string, envFile string, spec *types.TaskInvocationSpec) ([]string, error) {
+	fnref := spec.GetFnRef()
+	if len(fnref.GetID()) == 0 {
+		return nil, types.ErrFnRefNoID
+	}
+	inputs := spec.GetInputs()
+	command, err := unwrapStrings(inputs[InputCommand])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", InputCommand, err)
+	}
+	containerArgs, err := unwrapStrings(inputs[InputArgs])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", InputArgs, err)
+	}
+
+	args := []string{"run", "--rm", "-i", "--name", name}
+	if len(envFile) > 0 {
+		args = append(args, "--env-file", envFile)
+	}
+	args = append(args, fe.cfg.RunArgs...)
+	if len(command) > 0 {
+		// The entrypoint flag only accepts the executable; the remainder of the command precedes the args.
+		args = append(args, "--entrypoint", command[0])
+		containerArgs = append(command[1:], containerArgs...)
+	}
+	args = append(args, image(fnref))
+	args = append(args, containerArgs...)
+	return args, nil
+}
+
+// formatEnv returns the environment variables of the env input as sorted KEY=VALUE lines.
+func formatEnv(inputs map[string]*typedvalues.TypedValue) ([]string, error) {
+	vars, err := unwrapEnv(inputs[InputEnv])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", InputEnv, err)
+	}
+	var env []string
+	for _, k := range sortedKeys(vars) {
+		// The env file format has no escaping, so names and values cannot span lines.
+		if len(k) == 0 || strings.ContainsAny(k, "=\n\r") {
+			return nil, fmt.Errorf("invalid %s input: invalid variable name %q", InputEnv, k)
+		}
+		if strings.ContainsAny(vars[k], "\n\r") {
+			return nil, fmt.Errorf("invalid %s input: value of %s contains a line break", InputEnv, k)
+		}
+		env = append(env, k+"="+vars[k])
+	}
+	return env, nil
+}
+
+// writeEnvFile writes the environment variables to a temporary file, which is created with 0600 permissions, and
+// returns its path.
+func writeEnvFile(env []string) (string, error) {
+	f, err := ioutil.TempFile("", "workflows-env-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create env file: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(strings.Join(env, "\n") + "\n"); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write env file: %v", err)
+	}
+	return f.Name(), nil
+}
+
+func (fe *FunctionEnv) remove(name string) {
+	out, err := exec.Command(fe.cfg.Command, "rm", "-f", name).CombinedOutput()
+	if err != nil {
+		log.Warnf("Failed to remove container %s: %v (%s)", name, err, strings.TrimSpace(string(out)))
+	}
+}
+
+// image returns the image of the function reference. The namespace of the function reference is the registry.
+func image(fnref *types.FnRef) string {
+	if len(fnref.GetNamespace()) > 0 {
+		return fnref.GetNamespace() + "/" + fnref.GetID()
+	}
+	return fnref.GetID()
+}
+
+// formatStdin returns the main input of the task as a reader. Strings and bytes are passed as is, other values are
+// encoded as JSON.
+func formatStdin(inputs map[string]*typedvalues.TypedValue) (io.Reader, error) {
+	tv, ok := inputs[types.InputMain]
+	if !ok {
+		tv, ok = inputs[types.InputBody]
+	}
+	if !ok {
+		return nil, nil
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	switch t := i.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return strings.NewReader(t), nil
+	case []byte:
+		return bytes.NewReader(t), nil
+	default:
+		bs, err := json.Marshal(t)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode input: %v", err)
+		}
+		return bytes.NewReader(bs), nil
+	}
+}
+
+// parseOutput interprets the stdout as JSON, falling back to a string if the stdout is not valid JSON.
+func parseOutput(stdout []byte) (*typedvalues.TypedValue, error) {
+	if len(stdout) == 0 {
+		return nil, nil
+	}
+	var i interface{}
+	if err := json.Unmarshal(stdout, &i); err == nil {
+		return typedvalues.Wrap(i)
+	}
+	return typedvalues.Wrap(string(stdout))
+}
+
+// unwrapStrings unwraps a string or a list of values to a list of strings.
+func unwrapStrings(tv *typedvalues.TypedValue) ([]string, error) {
+	if tv == nil {
+		return nil, nil
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	switch t := i.(type) {
+	case string:
+		return []string{t}, nil
+	case []interface{}:
+		result := make([]string, len(t))
+		for k, v := range t {
+			result[k] = fmt.Sprintf("%v", v)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected a string or list, but was %s", tv.ValueType())
+	}
+}
+
+func unwrapEnv(tv *typedvalues.TypedValue) (map[string]string, error) {
+	if tv == nil {
+		return nil, nil
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := i.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a map, but was %s", tv.ValueType())
+	}
+	vars := make(map[string]string, len(m))
+	for k, v := range m {
+		vars[k] = fmt.Sprintf("%v", v)
+	}
+	return vars, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// limitedBuffer is a buffer that discards the writes that exceed the maximum size, recording that it did so. The
+// buffer is not embedded, as its ReadFrom would allow io.Copy to bypass the limit.
+type limitedBuffer struct {
+	buf      bytes.Buffer
+	max      int64
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - int64(b.buf.Len()); remaining < int64(len(p)) {
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		b.exceeded = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) Len() int {
+	return b.buf.Len()
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
@@ -0,0 +1,173 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	envHelperProcess  = "FNENV_CONTAINER_TEST_HELPER"
+	testMaxOutputSize = 1024
+)
+
+// TestMain allows the test binary to act as a fake container CLI.
+func TestMain(m *testing.M) {
+	if os.Getenv(envHelperProcess) == "1" {
+		os.Exit(fakeCLI(os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// fakeCLI echoes the image, arguments, env file, and stdin of the run command as JSON. The image "fail" exits
+// with a non-zero exit code, and the image "large" writes more than the maximum output size.
+func fakeCLI(args []string) int {
+	if len(args) == 0 || args[0] != "run" {
+		return 0
+	}
+	var image string
+	var env string
+	var containerArgs []string
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--env-file":
+			i++
+			bs, _ := ioutil.ReadFile(args[i])
+			env = string(bs)
+		case "--name", "--entrypoint":
+			i++
+		case "--rm", "-i":
+		default:
+			if len(image) == 0 {
+				image = args[i]
+			} else {
+				containerArgs = append(containerArgs, args[i])
+			}
+		}
+	}
+	if image == "fail" {
+		fmt.Fprintln(os.Stderr, "something went wrong")
+		return 3
+	}
+	if image == "large" {
+		fmt.Fprint(os.Stdout, strings.Repeat("a", 2*testMaxOutputSize))
+		return 0
+	}
+	stdin, _ := ioutil.ReadAll(os.Stdin)
+	json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+		"image": image,
+		"args":  containerArgs,
+		"env":   env,
+		"foo":   os.Getenv("FOO"),
+		"stdin": string(stdin),
+	})
+	return 0
+}
+
+func newTestFunctionEnv() *FunctionEnv {
+	os.Setenv(envHelperProcess, "1")
+	return New(Config{Command: os.Args[0], MaxOutputSize: testMaxOutputSize})
+}
+
+func newSpec(fnref string, inputs map[string]interface{}) *types.TaskInvocationSpec {
+	ref, err := types.ParseFnRef(fnref)
+	if err != nil {
+		panic(err)
+	}
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+	return &types.TaskInvocationSpec{
+		FnRef:    &ref,
+		Inputs:   typedvalues.MustWrapMapTypedValue(inputs),
+		Deadline: deadline,
+	}
+}
+
+func TestFunctionEnv_Resolve(t *testing.T) {
+	fe := New(Config{})
+	fnref, err := types.ParseFnRef("container:///alpine:3.12")
+	assert.NoError(t, err)
+	id, err := fe.Resolve(fnref)
+	assert.NoError(t, err)
+	assert.Equal(t, "alpine:3.12", id)
+
+	_, err = fe.Resolve(types.NewFnRef("", "", "alpine"))
+	assert.Equal(t, ErrUnsupportedRuntime, err)
+}
+
+func TestFunctionEnv_FormatRunArgs(t *testing.T) {
+	fe := New(Config{RunArgs: []string{"--network=host"}})
+	spec := newSpec("container://docker.io/library/alpine:3.12", map[string]interface{}{
+		InputCommand: []interface{}{"sh", "-c"},
+		InputArgs:    "echo $FOO",
+		InputEnv:     map[string]interface{}{"FOO": "bar", "BAR": 1},
+	})
+	args, err := fe.formatRunArgs("test", "/tmp/env", spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"run", "--rm", "-i", "--name", "test", "--env-file", "/tmp/env",
+		"--network=host", "--entrypoint", "sh", "docker.io/library/alpine:3.12", "-c", "echo $FOO"}, args)
+
+	env, err := formatEnv(spec.GetInputs())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BAR=1", "FOO=bar"}, env)
+}
+
+func TestFormatEnv_Invalid(t *testing.T) {
+	for _, vars := range []map[string]interface{}{
+		{"FOO": "a\nBAR=b"},
+		{"FOO=BAR": "a"},
+		{"": "a"},
+	} {
+		_, err := formatEnv(typedvalues.MustWrapMapTypedValue(map[string]interface{}{InputEnv: vars}))
+		assert.Error(t, err, "%v", vars)
+	}
+}
+
+func TestFunctionEnv_Invoke(t *testing.T) {
+	fe := newTestFunctionEnv()
+	defer os.Unsetenv(envHelperProcess)
+
+	status, err := fe.Invoke(newSpec("container:///alpine:3.12", map[string]interface{}{
+		types.InputMain: map[string]interface{}{"a": "b"},
+		InputArgs:       []interface{}{"x", "y"},
+		InputEnv:        map[string]interface{}{"FOO": "bar"},
+	}))
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, map[string]interface{}{
+		"image": "alpine:3.12",
+		"args":  []interface{}{"x", "y"},
+		"env":   "FOO=bar\n",
+		"foo":   "",
+		"stdin": `{"a":"b"}`,
+	}, typedvalues.MustUnwrap(status.GetOutput()))
+}
+
+func TestFunctionEnv_InvokeFailed(t *testing.T) {
+	fe := newTestFunctionEnv()
+	defer os.Unsetenv(envHelperProcess)
+
+	status, err := fe.Invoke(newSpec("container://fail", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, status.GetStatus())
+	assert.True(t, strings.HasSuffix(status.GetError().GetMessage(), "failed with exit code 3: something went wrong"),
+		status.GetError().GetMessage())
+}
+
+func TestFunctionEnv_InvokeOutputTooLarge(t *testing.T) {
+	fe := newTestFunctionEnv()
+	defer os.Unsetenv(envHelperProcess)
+
+	status, err := fe.Invoke(newSpec("container://large", nil))
+	assert.Nil(t, status)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), ErrOutputTooLarge.Error()), err.Error())
+}
@@ -0,0 +1,118 @@
+package fission
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	fnenvhttp "github.com/fission/fission-workflows/pkg/fnenv/http"
+)
+
+const headerAuthorization = "Authorization"
+
+// AuthConfig configures the authentication of the requests to the Fission router, executor, and controller. This is
+// needed in clusters in which the Fission components are behind an authenticating proxy.
+//
+// The bearer token is only added to requests to the hosts of the components that do not have an Authorization header
+// yet; requests to the pods of functions (see DirectConfig) are not authenticated. The TLS of the router is configured
+// by ClientConfig.TLS.
+type AuthConfig struct {
+	// Token is the bearer token to authenticate the requests with.
+	Token string
+
+	// TokenFile is the path to a file that contains the bearer token. The file is read again when it changes, which
+	// allows tokens to be rotated, such as projected service account tokens. It takes precedence over Token.
+	TokenFile string
+
+	// ExecutorTLS configures the connections to an executor that is served over HTTPS.
+	ExecutorTLS fnenvhttp.TLSConfig
+
+	// ControllerTLS configures the connections to a controller that is served over HTTPS.
+	ControllerTLS fnenvhttp.TLSConfig
+}
+
+// tokenSource provides the bearer token, either static or read from a file.
+type tokenSource struct {
+	token   string
+	file    string
+	modTime time.Time
+	mu      sync.Mutex
+}
+
+func newTokenSource(cfg AuthConfig) *tokenSource {
+	if len(cfg.Token) == 0 && len(cfg.TokenFile) == 0 {
+		return nil
+	}
+	return &tokenSource{
+		token: cfg.Token,
+		file:  cfg.TokenFile,
+	}
+}
+
+// Token returns the bearer token, reading the token file if it has changed since it was last read.
+func (ts *tokenSource) Token() (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.file) == 0 {
+		return ts.token, nil
+	}
+	info, err := os.Stat(ts.file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+	if info.ModTime().Equal(ts.modTime) {
+		return ts.token, nil
+	}
+	bs, err := ioutil.ReadFile(ts.file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read token file: %v", err)
+	}
+	ts.token = strings.TrimSpace(string(bs))
+	ts.modTime = info.ModTime()
+	return ts.token, nil
+}
+
+// authTransport adds the bearer token to the requests to the hosts of the Fission components.
+type authTransport struct {
+	base   http.RoundTripper
+	tokens *tokenSource
+	hosts  map[string]bool
+}
+
+// newAuthTransport wraps the transport to authenticate the requests to the URLs. If no token is configured, the
+// transport is returned as is.
+func newAuthTransport(base http.RoundTripper, tokens *tokenSource, urls ...string) http.RoundTripper {
+	if tokens == nil {
+		return base
+	}
+	hosts := map[string]bool{}
+	for _, rawURL := range urls {
+		if u, err := url.Parse(rawURL); err == nil {
+			hosts[u.Host] = true
+		}
+	}
+	return &authTransport{
+		base:   base,
+		tokens: tokens,
+		hosts:  hosts,
+	}
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.hosts[req.URL.Host] || len(req.Header.Get(headerAuthorization)) > 0 {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.tokens.Token()
+	if err != nil {
+		return nil, err
+	}
+	// RoundTrippers should not modify the original request.
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set(headerAuthorization, "Bearer "+token)
+	return t.base.RoundTrip(authReq)
+}
@@ -0,0 +1,121 @@
+package fission
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+// authRecorder records the Authorization headers of the requests, by path.
+type authRecorder struct {
+	headers map[string]string
+	mu      sync.Mutex
+}
+
+func (r *authRecorder) record(req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.headers[req.URL.Path] = req.Header.Get(headerAuthorization)
+}
+
+func (r *authRecorder) get(path string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.headers[path]
+}
+
+func TestFunctionEnv_Auth(t *testing.T) {
+	recorder := &authRecorder{headers: map[string]string{}}
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+	}))
+	defer router.Close()
+	executor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		w.Write([]byte("pod.test"))
+	}))
+	defer executor.Close()
+	controller := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder.record(r)
+		w.Write([]byte("{}"))
+	}))
+	defer controller.Close()
+	fe, err := NewWithConfig(executor.URL, controller.URL, router.URL, Config{
+		Auth: AuthConfig{Token: "secret"},
+	})
+	assert.NoError(t, err)
+
+	_, err = fe.Resolve(types.FnRef{Runtime: Name, ID: "foo"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", recorder.get("/v2/functions/foo"))
+
+	err = fe.Prepare(types.FnRef{Runtime: Name, ID: "foo"}, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", recorder.get("/v2/getServiceForFunction"))
+	assert.Equal(t, "Bearer secret", recorder.get("/v2/tapService"))
+
+	_, err = fe.Invoke(newSpec("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer secret", recorder.get("/fission-function/echo"))
+
+	// Authorization headers of the task are not overwritten.
+	spec := newSpec("foo")
+	spec.FnRef.ID = "bar"
+	spec.Inputs[types.InputHeaders] = typedvalues.MustWrap(map[string]interface{}{
+		headerAuthorization: "Bearer task",
+	})
+	_, err = fe.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer task", recorder.get("/fission-function/bar"))
+}
+
+func TestNewAuthTransport(t *testing.T) {
+	assert.Equal(t, http.DefaultTransport, newAuthTransport(http.DefaultTransport, nil, "http://router.test"))
+
+	var auth string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get(headerAuthorization)
+	}))
+	defer other.Close()
+	client := &http.Client{
+		Transport: newAuthTransport(http.DefaultTransport, &tokenSource{token: "secret"}, "http://router.test"),
+	}
+	resp, err := client.Get(other.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Empty(t, auth)
+}
+
+func TestTokenSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fission-token")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("first\n"), 0600))
+
+	assert.Nil(t, newTokenSource(AuthConfig{}))
+	ts := newTokenSource(AuthConfig{Token: "static", TokenFile: path})
+	token, err := ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", token)
+
+	// Rotated tokens are read again.
+	assert.NoError(t, ioutil.WriteFile(path, []byte("second"), 0600))
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	token, err = ts.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", token)
+
+	assert.NoError(t, os.Remove(path))
+	_, err = ts.Token()
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+package fission
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DefaultBreakerFailureThreshold = 5
+	DefaultBreakerOpenDuration     = 30 * time.Second
+)
+
+var (
+	circuitState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "circuit_state",
+		Help:      "State of the circuit breaker of each Fission function (0: closed, 1: half-open, 2: open)",
+	}, []string{"fn"})
+
+	circuitRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "circuit_rejected_total",
+		Help:      "Total number of Fission function invocations rejected by an open circuit breaker",
+	}, []string{"fn"})
+)
+
+func init() {
+	prometheus.MustRegister(circuitState, circuitRejected)
+}
+
+// BreakerConfig configures the per-function circuit breakers of the Fission function environment.
+//
+// Once a function has failed FailureThreshold times in a row, its circuit opens and invocations fail fast for
+// OpenDuration. After that, a single trial invocation is let through: if it succeeds the circuit closes again,
+// otherwise it stays open for another OpenDuration.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures after which the circuit opens. If 0, circuit breaking
+	// is disabled.
+	FailureThreshold int
+
+	// OpenDuration is the duration that the circuit stays open before allowing a trial invocation.
+	OpenDuration time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerHalfOpen
+	breakerOpen
+)
+
+type circuit struct {
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// breaker keeps track of a circuit for each function.
+type breaker struct {
+	cfg      BreakerConfig
+	circuits map[string]*circuit
+	mu       sync.Mutex
+	now      func() time.Time
+}
+
+func newBreaker(cfg BreakerConfig) *breaker {
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = DefaultBreakerOpenDuration
+	}
+	return &breaker{
+		cfg:      cfg,
+		circuits: map[string]*circuit{},
+		now:      time.Now,
+	}
+}
+
+// Allow returns whether an invocation of the function is allowed. If the invocation is allowed, the caller should
+// report its outcome with Report.
+func (b *breaker) Allow(fn string) bool {
+	if b.cfg.FailureThreshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[fn]
+	if !ok {
+		return true
+	}
+	switch c.state {
+	case breakerOpen:
+		if b.now().Sub(c.openedAt) < b.cfg.OpenDuration {
+			circuitRejected.WithLabelValues(fn).Inc()
+			return false
+		}
+		b.setState(fn, c, breakerHalfOpen)
+		return true
+	case breakerHalfOpen:
+		// Only the trial invocation is allowed until it has reported its outcome.
+		circuitRejected.WithLabelValues(fn).Inc()
+		return false
+	default:
+		return true
+	}
+}
+
+// Report records the outcome of an invocation of the function.
+func (b *breaker) Report(fn string, success bool) {
+	if b.cfg.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	c, ok := b.circuits[fn]
+	if success {
+		if ok {
+			delete(b.circuits, fn)
+			circuitState.WithLabelValues(fn).Set(float64(breakerClosed))
+		}
+		return
+	}
+	if !ok {
+		c = &circuit{}
+		b.circuits[fn] = c
+	}
+	c.failures++
+	if c.state == breakerHalfOpen || c.failures >= b.cfg.FailureThreshold {
+		c.openedAt = b.now()
+		b.setState(fn, c, breakerOpen)
+	}
+}
+
+func (b *breaker) setState(fn string, c *circuit, state breakerState) {
+	if c.state != state {
+		log.WithField("fn", fn).Infof("Circuit breaker state changed from %v to %v", c.state, state)
+	}
+	c.state = state
+	circuitState.WithLabelValues(fn).Set(float64(state))
+}
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerHalfOpen:
+		return "half-open"
+	case breakerOpen:
+		return "open"
+	default:
+		return "closed"
+	}
+}
@@ -0,0 +1,48 @@
+package fission
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := newBreaker(BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	b.now = func() time.Time { return now }
+
+	// A single failure should not open the circuit
+	assert.True(t, b.Allow("fn"))
+	b.Report("fn", false)
+	assert.True(t, b.Allow("fn"))
+	b.Report("fn", false)
+
+	// The circuit should be open for the function only
+	assert.False(t, b.Allow("fn"))
+	assert.True(t, b.Allow("other"))
+
+	// After the open duration, a single trial invocation is allowed
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow("fn"))
+	assert.False(t, b.Allow("fn"))
+
+	// A failed trial should reopen the circuit
+	b.Report("fn", false)
+	assert.False(t, b.Allow("fn"))
+
+	// A successful trial should close the circuit
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow("fn"))
+	b.Report("fn", true)
+	assert.True(t, b.Allow("fn"))
+	assert.True(t, b.Allow("fn"))
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := newBreaker(BreakerConfig{})
+	for i := 0; i < 10; i++ {
+		b.Report("fn", false)
+	}
+	assert.True(t, b.Allow("fn"))
+}
@@ -0,0 +1,127 @@
+package fission
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fission/fission"
+	"github.com/fission/fission/crd"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// tapBatchInterval is the interval at which the services of functions that were invoked directly are tapped.
+const tapBatchInterval = 5 * time.Second
+
+// The clients of the Fission executor and controller replace the clients of Fission, which only use the default HTTP
+// client, so that the requests to the components can be authenticated.
+
+// executorClient is a client of the Fission executor.
+type executorClient struct {
+	url    string
+	client *http.Client
+	tapped map[string]bool
+	mu     sync.Mutex
+}
+
+func newExecutorClient(executorURL string, client *http.Client) *executorClient {
+	return &executorClient{
+		url:    strings.TrimSuffix(executorURL, "/"),
+		client: client,
+	}
+}
+
+// GetServiceForFunction returns the address of a service of the function, specializing a pod if needed.
+func (c *executorClient) GetServiceForFunction(meta *metav1.ObjectMeta) (string, error) {
+	body, err := json.Marshal(meta)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.client.Post(c.url+"/v2/getServiceForFunction", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fission.MakeErrorFromHTTP(resp)
+	}
+	svc, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(svc), nil
+}
+
+// TapService signals the executor that the service is in use, so that it is not cleaned up as idle.
+func (c *executorClient) TapService(serviceURL string) error {
+	resp, err := c.client.Post(c.url+"/v2/tapService", "application/octet-stream",
+		bytes.NewReader([]byte(serviceURL)))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fission.MakeErrorFromHTTP(resp)
+	}
+	return nil
+}
+
+// TapServiceAsync taps the service in the background. The taps are batched per tapBatchInterval, to avoid a request
+// to the executor for every invocation.
+func (c *executorClient) TapServiceAsync(serviceURL *url.URL) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tapped == nil {
+		c.tapped = map[string]bool{}
+		time.AfterFunc(tapBatchInterval, c.flushTaps)
+	}
+	c.tapped[serviceURL.String()] = true
+}
+
+func (c *executorClient) flushTaps() {
+	c.mu.Lock()
+	tapped := c.tapped
+	c.tapped = nil
+	c.mu.Unlock()
+	for serviceURL := range tapped {
+		if err := c.TapService(serviceURL); err != nil {
+			log.Warnf("Failed to tap service %s of Fission function: %v", serviceURL, err)
+		}
+	}
+}
+
+// controllerClient is a client of the Fission controller.
+type controllerClient struct {
+	url    string
+	client *http.Client
+}
+
+func newControllerClient(controllerURL string, client *http.Client) *controllerClient {
+	return &controllerClient{
+		url:    strings.TrimSuffix(controllerURL, "/"),
+		client: client,
+	}
+}
+
+// FunctionGet returns the Fission function.
+func (c *controllerClient) FunctionGet(meta *metav1.ObjectMeta) (*crd.Function, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/v2/functions/%s?namespace=%s", c.url, meta.Name, meta.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fission.MakeErrorFromHTTP(resp)
+	}
+	fn := &crd.Function{}
+	if err := json.NewDecoder(resp.Body).Decode(fn); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
@@ -0,0 +1,185 @@
+package fission
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	DefaultAddressTTL          = time.Minute
+	DefaultHealthCheckInterval = 10 * time.Second
+	healthCheckTimeout         = time.Second
+)
+
+var (
+	directInvocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "direct_invocations_total",
+		Help:      "Total number of invocations sent directly to function pods, by whether they fell back to the router",
+	}, []string{"fn", "fallback"})
+)
+
+func init() {
+	prometheus.MustRegister(directInvocations)
+}
+
+// DirectConfig configures invoking functions directly at the address of their pods, bypassing the router.
+//
+// The address of a function is looked up with the executor, which specializes a pod for the function if needed, and
+// is cached for the AddressTTL. If an invocation fails to reach the pod, or the pod fails the health check, the
+// address is evicted and the invocation falls back to the router. Like the router, the function environment taps the
+// pods that it invokes, to prevent the executor from reaping them as idle.
+type DirectConfig struct {
+	// Enabled enables direct invocations. Requests with bodies that cannot be replayed (streams) always go through
+	// the router.
+	Enabled bool
+
+	// AddressTTL is the duration for which the address of a function is cached.
+	AddressTTL time.Duration
+
+	// HealthCheckInterval is the interval after which a cached address is checked - by connecting to it - before
+	// it is used again.
+	HealthCheckInterval time.Duration
+}
+
+// podAddress is a cached address of a pod of a function.
+type podAddress struct {
+	url       *url.URL
+	fetchedAt time.Time
+	checkedAt time.Time
+}
+
+// addressCache caches the addresses of the pods of functions, keyed by the formatted function reference.
+type addressCache struct {
+	entries map[string]*podAddress
+	mu      sync.Mutex
+}
+
+func (c *addressCache) get(key string) (podAddress, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return podAddress{}, false
+	}
+	return *entry, true
+}
+
+func (c *addressCache) put(key string, entry podAddress) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]*podAddress{}
+	}
+	c.entries[key] = &entry
+}
+
+func (c *addressCache) evict(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// podURL returns the URL of a pod of the function, or nil if the function should be invoked through the router.
+func (fe *FunctionEnv) podURL(fn types.FnRef) *url.URL {
+	key := fn.Format()
+	now := time.Now()
+	if entry, ok := fe.addresses.get(key); ok && now.Sub(entry.fetchedAt) < fe.direct.AddressTTL {
+		if now.Sub(entry.checkedAt) < fe.direct.HealthCheckInterval {
+			return entry.url
+		}
+		err := checkPodAddress(entry.url)
+		if err == nil {
+			entry.checkedAt = now
+			fe.addresses.put(key, entry)
+			return entry.url
+		}
+		log.Infof("Pod of Fission function %s at %s failed the health check: %v", key, entry.url.Host, err)
+	}
+
+	fe.addresses.evict(key)
+	u, err := fe.getFnURL(fn)
+	if err != nil {
+		return nil
+	}
+	fe.addresses.put(key, podAddress{
+		url:       u,
+		fetchedAt: now,
+		checkedAt: now,
+	})
+	return u
+}
+
+// checkPodAddress checks whether the pod accepts connections.
+func checkPodAddress(u *url.URL) error {
+	host := u.Host
+	if len(u.Port()) == 0 {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+	conn, err := net.DialTimeout("tcp", host, healthCheckTimeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// doDirect performs the request directly at a pod of the function. It returns false if the request should be sent
+// to the router instead, because direct invocations are disabled or the pod could not be reached.
+func (fe *FunctionEnv) doDirect(ctx context.Context, fnID string, fn types.FnRef, req *http.Request) (*http.Response,
+	bool, error) {
+	if !fe.direct.Enabled || (req.Body != nil && req.GetBody == nil) {
+		return nil, false, nil
+	}
+	podURL := fe.podURL(fn)
+	if podURL == nil {
+		return nil, false, nil
+	}
+
+	target := *podURL
+	target.Path = "/"
+	target.RawQuery = req.URL.RawQuery
+	directReq := req.WithContext(ctx)
+	directReq.URL = &target
+	directReq.Host = ""
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false, nil
+		}
+		directReq.Body = body
+	}
+	resp, err := fe.client.Do(directReq)
+	if ctx.Err() == nil && fe.retry.retryable(req, resp, err) {
+		// The pod is gone or unhealthy; evict the address and fall back to the router.
+		if err != nil {
+			log.Infof("Failed to invoke pod of Fission function %s at %s, falling back to the router: %v", fnID,
+				target.Host, err)
+		} else {
+			log.Infof("Pod of Fission function %s at %s responded with %s, falling back to the router", fnID,
+				target.Host, resp.Status)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		fe.addresses.evict(fn.Format())
+		directInvocations.WithLabelValues(fnID, "true").Inc()
+		return nil, false, nil
+	}
+	directInvocations.WithLabelValues(fnID, "false").Inc()
+	fe.executor.TapServiceAsync(podURL)
+	if err != nil {
+		// The request might have reached the function, so it is not resent to the router.
+		return nil, true, fmt.Errorf("error executing fission function at %s: %v", target.Host, err)
+	}
+	return resp, true, nil
+}
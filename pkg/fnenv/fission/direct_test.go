@@ -0,0 +1,92 @@
+package fission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestExecutor returns an executor that returns the address of the pod for every function.
+func newTestExecutor(podURL string) (*httptest.Server, *int32) {
+	var lookups int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/getServiceForFunction" {
+			atomic.AddInt32(&lookups, 1)
+			w.Write([]byte(strings.TrimPrefix(podURL, "http://")))
+		}
+	}))
+	return srv, &lookups
+}
+
+func TestFunctionEnv_InvokeDirect(t *testing.T) {
+	router, routerCalls := newTestRouter()
+	defer router.Close()
+	pod, podCalls := newTestRouter()
+	defer pod.Close()
+	executor, lookups := newTestExecutor(pod.URL)
+	defer executor.Close()
+	fe, err := NewWithConfig(executor.URL, "http://controller.test", router.URL, Config{
+		Direct: DirectConfig{Enabled: true},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		status, err := fe.Invoke(newSpec("foo"))
+		assert.NoError(t, err)
+		assert.True(t, status.Successful())
+		assert.Equal(t, "foo", typedvalues.MustUnwrap(status.GetOutput()))
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt32(podCalls))
+	assert.EqualValues(t, 0, atomic.LoadInt32(routerCalls))
+	// The address of the pod should have been cached
+	assert.EqualValues(t, 1, atomic.LoadInt32(lookups))
+}
+
+func TestFunctionEnv_InvokeDirectFallback(t *testing.T) {
+	router, routerCalls := newTestRouter()
+	defer router.Close()
+	pod := httptest.NewServer(http.NotFoundHandler())
+	pod.Close()
+	executor, _ := newTestExecutor(pod.URL)
+	defer executor.Close()
+	fe, err := NewWithConfig(executor.URL, "http://controller.test", router.URL, Config{
+		Direct: DirectConfig{Enabled: true},
+	})
+	assert.NoError(t, err)
+
+	status, err := fe.Invoke(newSpec("foo"))
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "foo", typedvalues.MustUnwrap(status.GetOutput()))
+	assert.EqualValues(t, 1, atomic.LoadInt32(routerCalls))
+	_, cached := fe.addresses.get(newSpec("foo").FnRef.Format())
+	assert.False(t, cached)
+}
+
+func TestFunctionEnv_PodURLHealthCheck(t *testing.T) {
+	pod := httptest.NewServer(http.NotFoundHandler())
+	executor, lookups := newTestExecutor(pod.URL)
+	defer executor.Close()
+	fe, err := NewWithConfig(executor.URL, "http://controller.test", "http://router.test", Config{
+		Direct: DirectConfig{Enabled: true, HealthCheckInterval: time.Nanosecond},
+	})
+	assert.NoError(t, err)
+	fn := *newSpec("foo").FnRef
+
+	podURL, _ := url.Parse(pod.URL)
+	assert.Equal(t, podURL.Host, fe.podURL(fn).Host)
+	assert.Equal(t, podURL.Host, fe.podURL(fn).Host)
+	assert.EqualValues(t, 1, atomic.LoadInt32(lookups))
+
+	// Once the pod is gone, the address should be looked up again
+	pod.Close()
+	fe.podURL(fn)
+	assert.EqualValues(t, 2, atomic.LoadInt32(lookups))
+}
@@ -1,29 +1,32 @@
 package fission
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/fission/fission"
 	"github.com/fission/fission-workflows/pkg/fnenv"
+	fnenvhttp "github.com/fission/fission-workflows/pkg/fnenv/http"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util/backoff"
-	controller "github.com/fission/fission/controller/client"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 
-	executor "github.com/fission/fission/executor/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,16 +34,139 @@ const (
 	Name = "fission"
 )
 
-var log = logrus.WithField("component", "fnenv.fission")
+var (
+	ErrCircuitOpen = errors.New("fnenv/fission: circuit breaker of the function is open")
+
+	log = logrus.WithField("component", "fnenv.fission")
+
+	requestRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "request_retries_total",
+		Help:      "Total number of retried requests to Fission functions",
+	}, []string{"fn"})
+
+	// DefaultConfig is the configuration used by New. Circuit breaking is disabled by default.
+	DefaultConfig = Config{
+		Namespace: metav1.NamespaceDefault,
+		Client: ClientConfig{
+			MaxIdleConnsPerHost: 100,
+			IdleConnTimeout:     90 * time.Second,
+			KeepAlive:           30 * time.Second,
+		},
+		Retry: RetryConfig{
+			MaxAttempts: 12,
+			BaseDelay:   100 * time.Millisecond,
+			MaxDelay:    10 * time.Second,
+			StatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+		},
+		Async: AsyncConfig{
+			PollInterval: time.Second,
+		},
+		Direct: DirectConfig{
+			AddressTTL:          DefaultAddressTTL,
+			HealthCheckInterval: DefaultHealthCheckInterval,
+		},
+		Resolve: ResolveConfig{
+			TTL: DefaultResolveTTL,
+		},
+	}
+)
+
+func init() {
+	prometheus.MustRegister(requestRetries)
+}
+
+// Config contains the optional configuration of the Fission function environment.
+type Config struct {
+	// Namespace is the namespace of the functions that are referenced without a namespace.
+	Namespace string
+
+	Client  ClientConfig
+	Retry   RetryConfig
+	Breaker BreakerConfig
+	Async   AsyncConfig
+	Direct  DirectConfig
+	Resolve ResolveConfig
+	Auth    AuthConfig
+}
+
+// AsyncConfig configures the asynchronous invocations of functions.
+//
+// A function can run longer than the timeouts of the router by responding with 202 Accepted and a Location header
+// that points to a status URL. The status URL is polled until it responds with anything other than 202 Accepted; that
+// response is the result of the invocation. If the invocation is aborted or times out while polling, a DELETE request
+// is sent to the status URL to allow the function to cancel the work.
+type AsyncConfig struct {
+	// Disabled disables asynchronous invocations, treating 202 Accepted responses as regular responses.
+	Disabled bool
+
+	// PollInterval is the interval at which the status URL is polled, unless the response of the status URL has a
+	// Retry-After header.
+	PollInterval time.Duration
+}
+
+// ClientConfig configures the HTTP client that invokes the functions through the router.
+//
+// All function requests go to the same router, so the client keeps a large pool of idle connections to it by
+// default. This avoids the connection churn of the default transport, which keeps only two idle connections per host.
+type ClientConfig struct {
+	// Timeout is the maximum duration of an invocation, including retries. If 0, invocations are only bounded by
+	// the deadline of the task.
+	Timeout time.Duration
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections to keep open to the router.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is the duration after which idle connections are closed.
+	IdleConnTimeout time.Duration
+
+	// KeepAlive is the interval of the TCP keep-alive probes of the connections. If negative, the probes are
+	// disabled.
+	KeepAlive time.Duration
+
+	// TLS configures the connections to a router that is served over HTTPS.
+	TLS fnenvhttp.TLSConfig
+}
+
+// RetryConfig configures the retries of requests to the Fission router. These retries only cover transient failures
+// of reaching a function; they are separate from the retries of failed tasks in workflows.
+//
+// Requests that failed to connect, or that received one of the StatusCodes, are retried regardless of the HTTP
+// method. Other transport errors are only retried for idempotent methods, because the function might have already
+// processed the request.
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of attempts of a request, including the first attempt.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry, which doubles for each subsequent retry.
+	BaseDelay time.Duration
+
+	// MaxDelay is the maximum delay between two attempts.
+	MaxDelay time.Duration
+
+	// StatusCodes are the response status codes to retry. By default, these are the 502 and 503 responses with which
+	// the router reports that it could not reach the function.
+	StatusCodes []int
+}
 
 // FunctionEnv adapts the Fission platform to the function execution runtime. This allows the workflow engine
 // to invoke Fission functions.
 type FunctionEnv struct {
-	executor    *executor.Client
-	executorURL string
-	controller  *controller.Client
-	routerURL   string
-	client      *http.Client
+	executor   *executorClient
+	controller *controllerClient
+	routerURL  string
+	namespace  string
+	client     *http.Client
+	timeout    time.Duration
+	retry      RetryConfig
+	breaker    *breaker
+	async      AsyncConfig
+	direct     DirectConfig
+	addresses  *addressCache
+	resolveTTL time.Duration
+	resolved   *resolveCache
+	limiter    *concurrencyLimiter
 }
 
 const (
@@ -49,14 +175,121 @@ const (
 )
 
 func New(executorURL, serverURL, routerURL string) *FunctionEnv {
+	fe, err := NewWithConfig(executorURL, serverURL, routerURL, DefaultConfig)
+	if err != nil {
+		// The default configuration does not contain any certificates to load.
+		panic(err)
+	}
+	return fe
+}
+
+// NewWithConfig creates a Fission function environment with the provided client, retry, and circuit breaker
+// configuration. Unset client and retry options are set to their defaults.
+func NewWithConfig(executorURL, serverURL, routerURL string, cfg Config) (*FunctionEnv, error) {
+	transport, err := newTransport(cfg.Client)
+	if err != nil {
+		return nil, err
+	}
+	tokens := newTokenSource(cfg.Auth)
+	executorTransport, err := newServiceTransport(cfg.Auth.ExecutorTLS)
+	if err != nil {
+		return nil, fmt.Errorf("executor: %v", err)
+	}
+	controllerTransport, err := newServiceTransport(cfg.Auth.ControllerTLS)
+	if err != nil {
+		return nil, fmt.Errorf("controller: %v", err)
+	}
+	if cfg.Retry.MaxAttempts <= 0 {
+		cfg.Retry.MaxAttempts = DefaultConfig.Retry.MaxAttempts
+	}
+	if cfg.Retry.BaseDelay <= 0 {
+		cfg.Retry.BaseDelay = DefaultConfig.Retry.BaseDelay
+	}
+	if cfg.Retry.MaxDelay <= 0 {
+		cfg.Retry.MaxDelay = DefaultConfig.Retry.MaxDelay
+	}
+	if cfg.Retry.StatusCodes == nil {
+		cfg.Retry.StatusCodes = DefaultConfig.Retry.StatusCodes
+	}
+	if cfg.Async.PollInterval <= 0 {
+		cfg.Async.PollInterval = DefaultConfig.Async.PollInterval
+	}
+	if len(cfg.Namespace) == 0 {
+		cfg.Namespace = DefaultConfig.Namespace
+	}
+	if cfg.Direct.AddressTTL <= 0 {
+		cfg.Direct.AddressTTL = DefaultConfig.Direct.AddressTTL
+	}
+	if cfg.Direct.HealthCheckInterval <= 0 {
+		cfg.Direct.HealthCheckInterval = DefaultConfig.Direct.HealthCheckInterval
+	}
+	if cfg.Resolve.TTL == 0 {
+		cfg.Resolve.TTL = DefaultConfig.Resolve.TTL
+	}
 
 	return &FunctionEnv{
-		executor:    executor.MakeClient(executorURL),
-		controller:  controller.MakeClient(serverURL),
-		routerURL:   routerURL,
-		executorURL: executorURL,
-		client:      &http.Client{},
+		executor: newExecutorClient(executorURL, &http.Client{
+			Transport: newAuthTransport(executorTransport, tokens, executorURL),
+		}),
+		controller: newControllerClient(serverURL, &http.Client{
+			Transport: newAuthTransport(controllerTransport, tokens, serverURL),
+		}),
+		routerURL:  routerURL,
+		namespace:  cfg.Namespace,
+		client:     &http.Client{Transport: newAuthTransport(transport, tokens, routerURL)},
+		timeout:    cfg.Client.Timeout,
+		retry:      cfg.Retry,
+		breaker:    newBreaker(cfg.Breaker),
+		async:      cfg.Async,
+		direct:     cfg.Direct,
+		addresses:  &addressCache{},
+		resolveTTL: cfg.Resolve.TTL,
+		resolved:   &resolveCache{},
+		limiter:    &concurrencyLimiter{},
+	}, nil
+}
+
+// newServiceTransport creates the transport for the requests to the executor or controller.
+func newServiceTransport(cfg fnenvhttp.TLSConfig) (http.RoundTripper, error) {
+	tlsConfig, err := cfg.Build()
+	if err != nil {
+		return nil, err
 	}
+	if tlsConfig == nil {
+		return http.DefaultTransport, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+func newTransport(cfg ClientConfig) (*http.Transport, error) {
+	if cfg.MaxIdleConnsPerHost <= 0 {
+		cfg.MaxIdleConnsPerHost = DefaultConfig.Client.MaxIdleConnsPerHost
+	}
+	if cfg.IdleConnTimeout <= 0 {
+		cfg.IdleConnTimeout = DefaultConfig.Client.IdleConnTimeout
+	}
+	if cfg.KeepAlive == 0 {
+		cfg.KeepAlive = DefaultConfig.Client.KeepAlive
+	}
+	tlsConfig, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: cfg.KeepAlive,
+	}).DialContext
+	transport.MaxIdleConns = cfg.MaxIdleConnsPerHost
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.IdleConnTimeout = cfg.IdleConnTimeout
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
 }
 
 // Invoke executes the task in a blocking way.
@@ -72,8 +305,17 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	}
 	span, _ := opentracing.StartSpanFromContext(cfg.Ctx, "/fnenv/fission")
 	defer span.Finish()
+	span.SetTag("fnref", spec.FnRef.Format())
+
+	// Route the invocation to one of the versions of the function
+	fn, err := parseVersionedFn(spec.FnRef.ID)
+	if err != nil {
+		return nil, err
+	}
+	version := fn.Select()
 	fnRef := *spec.FnRef
-	span.SetTag("fnref", fnRef.Format())
+	fnRef.ID = fn.FunctionName(version)
+	versionInvocations.WithLabelValues(fn.Name, version.Version).Inc()
 
 	// Construct request and add body
 	fnUrl := fe.createRouterURL(fnRef)
@@ -87,6 +329,9 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	if err != nil {
 		return nil, err
 	}
+	if len(req.Header.Get(fnenv.HeaderIdempotencyKey)) == 0 {
+		req.Header.Set(fnenv.HeaderIdempotencyKey, spec.IdempotencyKey())
+	}
 
 	// Add tracing
 	if span := opentracing.SpanFromContext(cfg.Ctx); span != nil {
@@ -100,45 +345,63 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	// Perform request
 	timeStart := time.Now()
 	fnenv.FnActive.WithLabelValues(Name).Inc()
-	defer fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart)))
-	ctxLog.Infof("Invoking Fission function: '%v'.", req.URL)
+	defer func() {
+		fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart) / time.Millisecond))
+	}()
+	ctxLog.Infof("Invoking Fission function: '%v'.", cfg.Redact(req.URL.String()))
 	if logrus.GetLevel() == logrus.DebugLevel {
 		fmt.Println("--- HTTP Request ---")
-		bs, err := httputil.DumpRequest(req, true)
+		bs, err := redact.DefaultRedactor.DumpRequest(req)
 		if err != nil {
 			logrus.Error(err)
 		}
-		fmt.Println(string(bs))
+		dump := cfg.Redact(string(bs))
+		fmt.Println(dump)
 		fmt.Println("--- HTTP Request end ---")
-		span.LogKV("HTTP request", string(bs))
+		span.LogKV("HTTP request", dump)
 	}
-	span.LogKV("http", fmt.Sprintf("%s %v", req.Method, req.URL))
-	var resp *http.Response
+	span.LogKV("http", cfg.Redact(fmt.Sprintf("%s %v", req.Method, req.URL)))
 
 	// Setup  context
 	deadline, err := ptypes.Timestamp(spec.Deadline)
 	if err != nil {
 		return nil, err
 	}
-	maxAttempts := 12 // About 6 min
+	// The timeout hint of the function overrides the timeout of the client.
+	hints := spec.GetTask().GetStatus().GetFnHints()
+	timeout := fe.timeout
+	if hint, err := ptypes.Duration(hints.GetTimeout()); err == nil {
+		timeout = hint
+	}
+	if timeout > 0 && time.Until(deadline) > timeout {
+		deadline = time.Now().Add(timeout)
+	}
 	ctx, cancel := context.WithDeadline(cfg.Ctx, deadline)
-	for attempt := range (&backoff.Instance{
-		MaxRetries:         maxAttempts,
-		BaseRetryDuration:  100 * time.Millisecond,
-		BackoffPolicy:      backoff.ExponentialBackoff,
-		MaxBackoffDuration: 10 * time.Second,
-	}).C(ctx) {
-		resp, err = fe.client.Do(req.WithContext(cfg.Ctx))
-		if err == nil {
-			break
-		}
-		log.Debugf("Failed to execute Fission function at %s (%d/%d): %v", fnUrl, err, attempt, maxAttempts)
+	defer cancel()
+
+	release, err := fe.limiter.acquire(ctx, spec.FnRef.Format(), hints.GetMaxConcurrency())
+	if err != nil {
+		fnenv.FnActive.WithLabelValues(Name).Dec()
+		return nil, err
 	}
-	cancel()
+	defer release()
 
-	// Check if max try attempts was exceeded
-	if resp == nil {
-		return nil, fmt.Errorf("error executing fission function at %s after %d attempts: %v", fnUrl, maxAttempts, err)
+	fnID := fnRef.Format()
+	if !fe.breaker.Allow(fnID) {
+		fnenv.FnActive.WithLabelValues(Name).Dec()
+		return nil, fmt.Errorf("%v: %s", ErrCircuitOpen, fnID)
+	}
+	resp, direct, err := fe.doDirect(ctx, fnID, fnRef, req)
+	if !direct {
+		resp, err = fe.do(ctx, fnID, req)
+	}
+	if err == nil && resp.StatusCode == http.StatusAccepted && !fe.async.Disabled {
+		resp, err = fe.await(ctx, fnID, req.URL, resp, cfg.Beat)
+	}
+	fe.breaker.Report(fnID, err == nil && resp.StatusCode < http.StatusInternalServerError)
+	if err != nil {
+		fnenv.FnActive.WithLabelValues(Name).Dec()
+		return nil, err
 	}
 	span.LogKV("status code", resp.Status)
 
@@ -147,13 +410,14 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	ctxLog.Infof("Fission function response: %d - %s", resp.StatusCode, resp.Header.Get("Content-Type"))
 	if logrus.GetLevel() == logrus.DebugLevel {
 		fmt.Println("--- HTTP Response ---")
-		bs, err := httputil.DumpResponse(resp, true)
+		bs, err := redact.DefaultRedactor.DumpResponse(resp)
 		if err != nil {
 			logrus.Error(err)
 		}
-		fmt.Println(string(bs))
+		dump := cfg.Redact(string(bs))
+		fmt.Println(dump)
 		fmt.Println("--- HTTP Response end ---")
-		span.LogKV("HTTP response", string(bs))
+		span.LogKV("HTTP response", dump)
 	}
 
 	// Parse output
@@ -172,7 +436,8 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 		return &types.TaskInvocationStatus{
 			Status: types.TaskInvocationStatus_FAILED,
 			Error: &types.Error{
-				Message: fmt.Sprintf("fission function error: %v", msg),
+				Message:    fmt.Sprintf("fission function error: %v", msg),
+				StatusCode: int32(resp.StatusCode),
 			},
 		}, nil
 	}
@@ -184,33 +449,200 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	}, nil
 }
 
+// do performs the request, retrying transient failures according to the retry configuration.
+func (fe *FunctionEnv) do(ctx context.Context, fnID string, req *http.Request) (*http.Response, error) {
+	maxAttempts := fe.retry.MaxAttempts
+	if req.Body != nil && req.GetBody == nil {
+		// Streamed bodies cannot be replayed.
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptReq := req.WithContext(ctx)
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq.Body = body
+		}
+		resp, err := fe.client.Do(attemptReq)
+		if attempt >= maxAttempts || !fe.retry.retryable(req, resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("error executing fission function at %s after %d attempts: %v", req.URL,
+					attempt, err)
+			}
+			return resp, nil
+		}
+		if err != nil {
+			log.Debugf("Failed to execute Fission function at %s (%d/%d): %v", req.URL, attempt, maxAttempts, err)
+		} else {
+			log.Debugf("Fission function at %s responded with %s (%d/%d)", req.URL, resp.Status, attempt,
+				maxAttempts)
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		requestRetries.WithLabelValues(fnID).Inc()
+
+		delay := backoff.ExponentialBackoff(attempt-1, fe.retry.BaseDelay)
+		if delay > fe.retry.MaxDelay {
+			delay = fe.retry.MaxDelay
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("error executing fission function at %s after %d attempts: %v", req.URL,
+				attempt, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+}
+
+// await polls the status URL of an asynchronous invocation until the function has completed. The accepted response
+// is closed; the final response of the status URL is returned as the response of the invocation. Each response that
+// indicates that the function is still running is reported as a heartbeat.
+func (fe *FunctionEnv) await(ctx context.Context, fnID string, fnURL *url.URL, resp *http.Response,
+	beat func()) (*http.Response, error) {
+	var statusURL *url.URL
+	for resp.StatusCode == http.StatusAccepted {
+		if location := resp.Header.Get("Location"); len(location) > 0 {
+			u, err := fnURL.Parse(location)
+			if err != nil {
+				resp.Body.Close()
+				return nil, fmt.Errorf("invalid status URL of asynchronous invocation: %v", err)
+			}
+			statusURL = u
+		}
+		if statusURL == nil {
+			// Without a status URL, the 202 response is the result of the invocation.
+			return resp, nil
+		}
+		beat()
+		wait := retryAfter(resp, fe.async.PollInterval)
+		if max := fnenv.MaxHeartbeatInterval(); max > 0 && wait > max {
+			// Polling later than requested keeps the heartbeats of the function within the heartbeat timeout.
+			wait = max
+		}
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-ctx.Done():
+			fe.cancelAsync(statusURL)
+			return nil, fmt.Errorf("asynchronous invocation at %s did not complete: %v", statusURL, ctx.Err())
+		case <-time.After(wait):
+		}
+		log.Debugf("Polling status of asynchronous invocation at %s", statusURL)
+		req, err := http.NewRequest(http.MethodGet, statusURL.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = fe.do(ctx, fnID, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				fe.cancelAsync(statusURL)
+			}
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// cancelAsync signals the function to cancel the asynchronous invocation. It is best-effort; functions that do not
+// support cancellation can ignore the request.
+func (fe *FunctionEnv) cancelAsync(statusURL *url.URL) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodDelete, statusURL.String(), nil)
+	if err != nil {
+		return
+	}
+	resp, err := fe.client.Do(req.WithContext(ctx))
+	if err != nil {
+		log.Warnf("Failed to cancel asynchronous invocation at %s: %v", statusURL, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// retryAfter returns the delay of the Retry-After header of the response in seconds, or the fallback if the response
+// does not have a valid Retry-After header.
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// retryable returns whether the outcome of the request is a transient failure that should be retried.
+func (rc RetryConfig) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		return isDialError(err) || isIdempotent(req.Method)
+	}
+	for _, code := range rc.StatusCodes {
+		if resp.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isDialError returns whether the error occurred while connecting, in which case the request was never sent.
+func isDialError(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+	opErr, ok := err.(*net.OpError)
+	return ok && opErr.Op == "dial"
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
 // Prepare signals the Fission runtime that a function request is expected at a specific time.
 // For now this function will tap immediately regardless of the expected execution time.
+// All versions of the function are prewarmed, because it is not known yet to which version the request will be routed.
 func (fe *FunctionEnv) Prepare(fn types.FnRef, expectedAt time.Time) error {
-	reqURL, err := fe.getFnURL(fn)
+	versioned, err := parseVersionedFn(fn.ID)
 	if err != nil {
 		return err
 	}
+	for _, version := range versioned.Versions {
+		target := fn
+		target.ID = versioned.FunctionName(version)
+		reqURL, err := fe.getFnURL(target)
+		if err != nil {
+			return err
+		}
 
-	// Tap the Fission function at the right time
-	log.WithField("fn", fn).Infof("Prewarming Fission function: %v", reqURL)
-	return fe.tapService(reqURL.String())
+		// Tap the Fission function at the right time
+		log.WithField("fn", target).Infof("Prewarming Fission function: %v", reqURL)
+		if err := fe.executor.TapService(reqURL.String()); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (fe *FunctionEnv) Resolve(ref types.FnRef) (string, error) {
 	// Currently we just use the controller API to check if the function exists.
 	log.Infof("Resolving function: %s", ref.ID)
-	ns := ref.Namespace
-	if len(ns) == 0 {
-		ns = metav1.NamespaceDefault
-	}
-	_, err := fe.controller.FunctionGet(&metav1.ObjectMeta{
-		Name:      ref.ID,
-		Namespace: ns,
-	})
+	ns := fe.fnNamespace(ref)
+	fn, err := parseVersionedFn(ref.ID)
 	if err != nil {
 		return "", err
 	}
+	for _, version := range fn.Versions {
+		if _, err := fe.lookupFunction(ns, fn.FunctionName(version)); err != nil {
+			return "", err
+		}
+	}
 	id := ref.ID
 
 	log.Infof("Resolved fission function %s to %s", ref.ID, id)
@@ -218,7 +650,7 @@ func (fe *FunctionEnv) Resolve(ref types.FnRef) (string, error) {
 }
 
 func (fe *FunctionEnv) getFnURL(fn types.FnRef) (*url.URL, error) {
-	meta := createFunctionMeta(fn)
+	meta := fe.createFunctionMeta(fn)
 	serviceURL, err := fe.executor.GetServiceForFunction(meta)
 	if err != nil {
 		log.WithFields(logrus.Fields{
@@ -236,34 +668,34 @@ func (fe *FunctionEnv) getFnURL(fn types.FnRef) (*url.URL, error) {
 	return reqURL, nil
 }
 
-func createFunctionMeta(fn types.FnRef) *metav1.ObjectMeta {
+// NamespaceScoped signals that the namespace of a function reference is the namespace of the Fission function.
+func (fe *FunctionEnv) NamespaceScoped() bool {
+	return true
+}
 
+// fnNamespace returns the namespace of the function, which defaults to the namespace of the function environment.
+func (fe *FunctionEnv) fnNamespace(fn types.FnRef) string {
+	if len(fn.Namespace) == 0 {
+		return fe.namespace
+	}
+	return fn.Namespace
+}
+
+func (fe *FunctionEnv) createFunctionMeta(fn types.FnRef) *metav1.ObjectMeta {
 	return &metav1.ObjectMeta{
 		Name:      fn.ID,
-		Namespace: metav1.NamespaceDefault,
+		Namespace: fe.fnNamespace(fn),
 	}
 }
 
+// createRouterURL returns the URL of the function on the router. The router serves the functions in the default
+// namespace at /fission-function/<name>, and the functions in other namespaces at /fission-function/<ns>/<name>.
 func (fe *FunctionEnv) createRouterURL(fn types.FnRef) string {
 	id := strings.TrimLeft(fn.ID, "/")
 	baseUrl := strings.TrimRight(fe.routerURL, "/")
 	var ns string
-	if fn.Namespace != metav1.NamespaceDefault && len(fn.Namespace) != 0 {
-		ns = strings.Trim(fn.Namespace, "/") + "/"
+	if fnNs := strings.Trim(fe.fnNamespace(fn), "/"); fnNs != metav1.NamespaceDefault {
+		ns = fnNs + "/"
 	}
 	return fmt.Sprintf("%s/fission-function/%s%s", baseUrl, ns, id)
 }
-
-func (fe *FunctionEnv) tapService(serviceUrlStr string) error {
-	executorUrl := fe.executorURL + "/v2/tapService"
-
-	resp, err := http.Post(executorUrl, "application/octet-stream", bytes.NewReader([]byte(serviceUrlStr)))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != 200 {
-		return fission.MakeErrorFromHTTP(resp)
-	}
-	return nil
-}
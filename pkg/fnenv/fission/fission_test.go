@@ -0,0 +1,232 @@
+package fission
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	fnenvhttp "github.com/fission/fission-workflows/pkg/fnenv/http"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRouter returns a router that responds with the status codes in order, and echoes the request body once the
+// status codes are exhausted.
+func newTestRouter(statusCodes ...int) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i < len(statusCodes) {
+			w.WriteHeader(statusCodes[i])
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(body)
+	}))
+	return srv, &calls
+}
+
+func newTestFunctionEnv(routerURL string, cfg Config) *FunctionEnv {
+	cfg.Retry.BaseDelay = time.Millisecond
+	cfg.Async.PollInterval = time.Millisecond
+	fe, err := NewWithConfig("http://executor.test", "http://controller.test", routerURL, cfg)
+	if err != nil {
+		panic(err)
+	}
+	return fe
+}
+
+func newSpec(input string) *types.TaskInvocationSpec {
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+	return &types.TaskInvocationSpec{
+		FnRef:        &types.FnRef{Runtime: Name, ID: "echo"},
+		InvocationId: "wi-123",
+		TaskId:       "task",
+		Inputs: typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+			types.InputMain: input,
+		}),
+		Deadline: deadline,
+	}
+}
+
+func TestFunctionEnv_InvokeRetry(t *testing.T) {
+	router, calls := newTestRouter(http.StatusServiceUnavailable, http.StatusBadGateway)
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	status, err := fe.Invoke(newSpec("foo"))
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "foo", typedvalues.MustUnwrap(status.GetOutput()))
+	assert.EqualValues(t, 3, atomic.LoadInt32(calls))
+}
+
+func TestFunctionEnv_InvokeIdempotencyKey(t *testing.T) {
+	var keys []string
+	var mu sync.Mutex
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(fnenv.HeaderIdempotencyKey))
+		retry := len(keys) == 1
+		mu.Unlock()
+		if retry {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	// The retried requests of an attempt share the idempotency key.
+	spec := newSpec("foo")
+	_, err := fe.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{spec.IdempotencyKey(), spec.IdempotencyKey()}, keys)
+
+	// A subsequent attempt of the task has another key.
+	keys = nil
+	spec.Attempt = 1
+	_, err = fe.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{spec.IdempotencyKey(), spec.IdempotencyKey()}, keys)
+	assert.NotEqual(t, newSpec("foo").IdempotencyKey(), spec.IdempotencyKey())
+
+	// A key in the headers of the task is not overridden.
+	keys = []string{"retried"}
+	spec.Inputs[types.InputHeaders] = typedvalues.MustWrap(map[string]interface{}{
+		fnenv.HeaderIdempotencyKey: "custom",
+	})
+	_, err = fe.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"retried", "custom"}, keys)
+}
+
+func TestFunctionEnv_InvokeNoRetry(t *testing.T) {
+	router, calls := newTestRouter(http.StatusInternalServerError)
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	status, err := fe.Invoke(newSpec("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, status.GetStatus())
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+func TestFunctionEnv_InvokeTimeout(t *testing.T) {
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Second)
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{
+		Client: ClientConfig{Timeout: 10 * time.Millisecond},
+	})
+
+	start := time.Now()
+	_, err := fe.Invoke(newSpec("foo"))
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestNewTransport(t *testing.T) {
+	transport, err := newTransport(ClientConfig{MaxIdleConnsPerHost: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, DefaultConfig.Client.IdleConnTimeout, transport.IdleConnTimeout)
+
+	_, err = newTransport(ClientConfig{TLS: fnenvhttp.TLSConfig{CAFile: "/nonexistent"}})
+	assert.Error(t, err)
+}
+
+func TestFunctionEnv_InvokeCircuitOpen(t *testing.T) {
+	router, calls := newTestRouter(http.StatusInternalServerError, http.StatusInternalServerError)
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{
+		Breaker: BreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute},
+	})
+
+	for i := 0; i < 2; i++ {
+		status, err := fe.Invoke(newSpec("foo"))
+		assert.NoError(t, err)
+		assert.Equal(t, types.TaskInvocationStatus_FAILED, status.GetStatus())
+	}
+
+	// The function should not be invoked while the circuit is open
+	_, err := fe.Invoke(newSpec("foo"))
+	assert.Error(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestFunctionEnv_InvokeAsync(t *testing.T) {
+	var polls int32
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/status/1" {
+			w.Header().Set("Location", "/status/1")
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if atomic.AddInt32(&polls, 1) < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("done"))
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	// Each response that indicates that the function is still running is reported as a heartbeat.
+	var beats int32
+	status, err := fe.Invoke(newSpec("foo"), fnenv.WithHeartbeat(func() { atomic.AddInt32(&beats, 1) }))
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "done", typedvalues.MustUnwrap(status.GetOutput()))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&polls))
+	assert.EqualValues(t, 3, atomic.LoadInt32(&beats))
+}
+
+func TestFunctionEnv_InvokeAsyncTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			close(cancelled)
+			return
+		}
+		w.Header().Set("Location", "/status/1")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{
+		Client: ClientConfig{Timeout: 50 * time.Millisecond},
+	})
+
+	_, err := fe.Invoke(newSpec("foo"))
+	assert.Error(t, err)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		assert.Fail(t, "asynchronous invocation was not cancelled")
+	}
+}
+
+func TestFunctionEnv_CreateRouterURL(t *testing.T) {
+	fe := newTestFunctionEnv("http://router.test/", Config{})
+	assert.Equal(t, "http://router.test/fission-function/hello",
+		fe.createRouterURL(types.FnRef{Runtime: Name, ID: "hello"}))
+	assert.Equal(t, "http://router.test/fission-function/hello",
+		fe.createRouterURL(types.FnRef{Runtime: Name, Namespace: "default", ID: "hello"}))
+	assert.Equal(t, "http://router.test/fission-function/team-a/hello",
+		fe.createRouterURL(types.FnRef{Runtime: Name, Namespace: "team-a", ID: "hello"}))
+
+	fe = newTestFunctionEnv("http://router.test/", Config{Namespace: "team-b"})
+	assert.Equal(t, "http://router.test/fission-function/team-b/hello",
+		fe.createRouterURL(types.FnRef{Runtime: Name, ID: "hello"}))
+	assert.Equal(t, "http://router.test/fission-function/hello",
+		fe.createRouterURL(types.FnRef{Runtime: Name, Namespace: "default", ID: "hello"}))
+}
@@ -0,0 +1,139 @@
+package fission
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/duration"
+)
+
+// The annotations of Fission functions from which the hints of the functions are read.
+const (
+	AnnotationTimeout          = "workflows.fission.io/timeout"
+	AnnotationExpectedDuration = "workflows.fission.io/expected-duration"
+	AnnotationMaxConcurrency   = "workflows.fission.io/max-concurrency"
+)
+
+// parseHints parses the hints from the annotations of a Fission function. It returns nil if the function has no
+// hints.
+func parseHints(annotations map[string]string) (*types.FnHints, error) {
+	hints := &types.FnHints{}
+	var hinted bool
+	if s, ok := annotations[AnnotationTimeout]; ok {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid annotation %s: '%s'", AnnotationTimeout, s)
+		}
+		hints.Timeout = ptypes.DurationProto(d)
+		hinted = true
+	}
+	if s, ok := annotations[AnnotationExpectedDuration]; ok {
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid annotation %s: '%s'", AnnotationExpectedDuration, s)
+		}
+		hints.ExpectedDuration = ptypes.DurationProto(d)
+		hinted = true
+	}
+	if s, ok := annotations[AnnotationMaxConcurrency]; ok {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid annotation %s: '%s'", AnnotationMaxConcurrency, s)
+		}
+		hints.MaxConcurrency = int32(n)
+		hinted = true
+	}
+	if !hinted {
+		return nil, nil
+	}
+	return hints, nil
+}
+
+// mergeHints combines the hints of the versions of a function, as an invocation can be routed to any of them. It
+// uses the longest durations, and the lowest concurrency limit.
+func mergeHints(a, b *types.FnHints) *types.FnHints {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	merged := *a
+	if longerDuration(b.GetTimeout(), a.GetTimeout()) {
+		merged.Timeout = b.GetTimeout()
+	}
+	if longerDuration(b.GetExpectedDuration(), a.GetExpectedDuration()) {
+		merged.ExpectedDuration = b.GetExpectedDuration()
+	}
+	if b.GetMaxConcurrency() > 0 && (a.GetMaxConcurrency() == 0 || b.GetMaxConcurrency() < a.GetMaxConcurrency()) {
+		merged.MaxConcurrency = b.GetMaxConcurrency()
+	}
+	return &merged
+}
+
+// longerDuration returns whether duration a is longer than duration b. Unset durations are the shortest.
+func longerDuration(a, b *duration.Duration) bool {
+	da, err := ptypes.Duration(a)
+	if err != nil {
+		return false
+	}
+	db, err := ptypes.Duration(b)
+	return err != nil || da > db
+}
+
+// ResolveHints returns the hints of the function, which are read from the annotations of the Fission function. For
+// functions with multiple versions, the hints of the versions are combined.
+func (fe *FunctionEnv) ResolveHints(ref types.FnRef) (*types.FnHints, error) {
+	ns := fe.fnNamespace(ref)
+	fn, err := parseVersionedFn(ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	var hints *types.FnHints
+	for _, version := range fn.Versions {
+		versionHints, err := fe.lookupFunction(ns, fn.FunctionName(version))
+		if err != nil {
+			return nil, err
+		}
+		hints = mergeHints(hints, versionHints)
+	}
+	return hints, nil
+}
+
+// concurrencyLimiter limits the number of concurrent invocations of functions.
+type concurrencyLimiter struct {
+	slots map[string]chan struct{}
+	mu    sync.Mutex
+}
+
+// acquire waits for a slot to invoke the function, until the context is done. If the limit is 0, the number of
+// invocations is not limited. The returned function releases the slot.
+func (l *concurrencyLimiter) acquire(ctx context.Context, key string, limit int32) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	if l.slots == nil {
+		l.slots = map[string]chan struct{}{}
+	}
+	slots, ok := l.slots[key]
+	if !ok || cap(slots) != int(limit) {
+		// The limit of the function changed; invocations that hold a slot of the old limit release it there.
+		slots = make(chan struct{}, limit)
+		l.slots[key] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for one of the %d concurrent invocations of %s: %v", limit, key,
+			ctx.Err())
+	}
+}
@@ -0,0 +1,115 @@
+package fission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission/crd"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseHints(t *testing.T) {
+	hints, err := parseHints(map[string]string{
+		AnnotationTimeout:          "5m",
+		AnnotationExpectedDuration: "2s",
+		AnnotationMaxConcurrency:   "10",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &types.FnHints{
+		Timeout:          ptypes.DurationProto(5 * time.Minute),
+		ExpectedDuration: ptypes.DurationProto(2 * time.Second),
+		MaxConcurrency:   10,
+	}, hints)
+
+	hints, err = parseHints(map[string]string{"foo": "bar"})
+	assert.NoError(t, err)
+	assert.Nil(t, hints)
+
+	_, err = parseHints(map[string]string{AnnotationTimeout: "forever"})
+	assert.Error(t, err)
+	_, err = parseHints(map[string]string{AnnotationMaxConcurrency: "-1"})
+	assert.Error(t, err)
+}
+
+func TestFunctionEnv_ResolveHints(t *testing.T) {
+	annotations := map[string]map[string]string{
+		"hello-v1": {AnnotationTimeout: "1m", AnnotationMaxConcurrency: "10"},
+		"hello-v2": {AnnotationTimeout: "2m", AnnotationMaxConcurrency: "5", AnnotationExpectedDuration: "1s"},
+	}
+	controller := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(&crd.Function{
+			Metadata: metav1.ObjectMeta{Annotations: annotations[path.Base(r.URL.Path)]},
+		})
+	}))
+	defer controller.Close()
+	fe, err := NewWithConfig("http://executor.test", controller.URL, "http://router.test", Config{})
+	assert.NoError(t, err)
+
+	hints, err := fe.ResolveHints(types.FnRef{Runtime: Name, ID: "hello@v1"})
+	assert.NoError(t, err)
+	assert.Equal(t, &types.FnHints{Timeout: ptypes.DurationProto(time.Minute), MaxConcurrency: 10}, hints)
+
+	// The hints of the versions of a function are combined
+	hints, err = fe.ResolveHints(types.FnRef{Runtime: Name, ID: "hello@v1=90,v2=10"})
+	assert.NoError(t, err)
+	assert.Equal(t, &types.FnHints{
+		Timeout:          ptypes.DurationProto(2 * time.Minute),
+		ExpectedDuration: ptypes.DurationProto(time.Second),
+		MaxConcurrency:   5,
+	}, hints)
+
+	hints, err = fe.ResolveHints(types.FnRef{Runtime: Name, ID: "hello"})
+	assert.NoError(t, err)
+	assert.Nil(t, hints)
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := &concurrencyLimiter{}
+	release, err := limiter.acquire(context.Background(), "fn", 1)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = limiter.acquire(ctx, "fn", 1)
+	assert.Error(t, err)
+
+	// Other functions, and functions without a limit, are not affected
+	_, err = limiter.acquire(context.Background(), "other", 1)
+	assert.NoError(t, err)
+	_, err = limiter.acquire(context.Background(), "fn", 0)
+	assert.NoError(t, err)
+
+	release()
+	_, err = limiter.acquire(context.Background(), "fn", 1)
+	assert.NoError(t, err)
+}
+
+func TestFunctionEnv_InvokeTimeoutHint(t *testing.T) {
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	spec := newSpec("foo")
+	spec.Task = &types.Task{
+		Status: &types.TaskStatus{
+			FnHints: &types.FnHints{Timeout: ptypes.DurationProto(10 * time.Millisecond)},
+		},
+	}
+	start := time.Now()
+	_, err := fe.Invoke(spec)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
@@ -0,0 +1,165 @@
+package fission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fission/fission"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MaxLogEntries is the maximum number of log lines that are returned for a function.
+	MaxLogEntries = 1000
+
+	// logDatabase is the InfluxDB database in which Fission stores the logs of functions.
+	logDatabase = "fissionFunctionLog"
+)
+
+// Logs returns the log output of the function, which is read from the log database of Fission through the
+// controller. For functions with multiple versions, the logs of all versions are returned.
+func (fe *FunctionEnv) Logs(ctx context.Context, ref types.FnRef, since, until time.Time) ([]fnenv.LogEntry, error) {
+	ns := fe.fnNamespace(ref)
+	fn, err := parseVersionedFn(ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	// The logs are labeled with the uid of the function, rather than its name.
+	var uids []string
+	for _, version := range fn.Versions {
+		f, err := fe.controller.FunctionGet(&metav1.ObjectMeta{
+			Name:      fn.FunctionName(version),
+			Namespace: ns,
+		})
+		if err != nil {
+			return nil, err
+		}
+		uids = append(uids, string(f.Metadata.UID))
+	}
+	return fe.controller.FunctionLogs(ctx, uids, since, until)
+}
+
+// influxResponse is the response of InfluxDB to a query.
+type influxResponse struct {
+	Results []struct {
+		Series []struct {
+			Columns []string        `json:"columns"`
+			Values  [][]interface{} `json:"values"`
+		} `json:"series"`
+		Error string `json:"error"`
+	} `json:"results"`
+	Error string `json:"error"`
+}
+
+// FunctionLogs queries the log database of Fission, which the controller proxies, for the logs of the functions
+// with the uids between since and until.
+func (c *controllerClient) FunctionLogs(ctx context.Context, uids []string, since,
+	until time.Time) ([]fnenv.LogEntry, error) {
+	// The values are bound as parameters of the query, rather than formatted into it.
+	params := map[string]interface{}{
+		"since": since.UnixNano(),
+		"until": until.UnixNano(),
+	}
+	var conds []string
+	for i, uid := range uids {
+		param := "funcuid" + strconv.Itoa(i)
+		params[param] = uid
+		conds = append(conds, fmt.Sprintf(`"funcuid" = $%s`, param))
+	}
+	query := fmt.Sprintf(`select * from "log" where (%s) AND "time" >= $since AND "time" <= $until LIMIT %d`,
+		strings.Join(conds, " OR "), MaxLogEntries)
+	bs, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	values := url.Values{}
+	values.Set("q", query)
+	values.Set("db", logDatabase)
+	values.Set("params", string(bs))
+	req, err := http.NewRequest(http.MethodPost, c.url+"/proxy/influxdb?"+values.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fission.MakeErrorFromHTTP(resp)
+	}
+	result := &influxResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode the logs of the function: %v", err)
+	}
+	return parseLogEntries(result)
+}
+
+// parseLogEntries converts the rows of the response to log entries, ordered by the time and sequence number at
+// which they were written.
+func parseLogEntries(resp *influxResponse) ([]fnenv.LogEntry, error) {
+	if len(resp.Error) > 0 {
+		return nil, fmt.Errorf("failed to query the logs of the function: %s", resp.Error)
+	}
+	type sequencedEntry struct {
+		fnenv.LogEntry
+		seq int
+	}
+	var entries []sequencedEntry
+	for _, result := range resp.Results {
+		if len(result.Error) > 0 {
+			return nil, fmt.Errorf("failed to query the logs of the function: %s", result.Error)
+		}
+		for _, series := range result.Series {
+			cols := map[string]int{}
+			for i, col := range series.Columns {
+				cols[col] = i
+			}
+			column := func(row []interface{}, name string) string {
+				i, ok := cols[name]
+				if !ok || i >= len(row) || row[i] == nil {
+					return ""
+				}
+				return fmt.Sprint(row[i])
+			}
+			for _, row := range series.Values {
+				ts, err := time.Parse(time.RFC3339Nano, column(row, "time"))
+				if err != nil {
+					return nil, fmt.Errorf("invalid time of log entry: %v", err)
+				}
+				seq, _ := strconv.Atoi(column(row, "_seq"))
+				entries = append(entries, sequencedEntry{
+					LogEntry: fnenv.LogEntry{
+						Timestamp: ts,
+						Message:   strings.TrimSuffix(column(row, "log"), "\n"),
+						Stream:    column(row, "stream"),
+						Source:    column(row, "kubernetes_pod_name"),
+					},
+					seq: seq,
+				})
+			}
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		return entries[i].seq < entries[j].seq
+	})
+	logs := make([]fnenv.LogEntry, len(entries))
+	for i, entry := range entries {
+		logs[i] = entry.LogEntry
+	}
+	return logs, nil
+}
@@ -0,0 +1,70 @@
+package fission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission/crd"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+func TestFunctionEnv_Logs(t *testing.T) {
+	var queries []string
+	var params []map[string]interface{}
+	controller := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/proxy/influxdb" {
+			queries = append(queries, r.URL.Query().Get("q"))
+			p := map[string]interface{}{}
+			json.Unmarshal([]byte(r.URL.Query().Get("params")), &p)
+			params = append(params, p)
+			w.Write([]byte(`{"results":[{"series":[{"name":"log",
+				"columns":["time","_seq","kubernetes_pod_name","log","stream"],
+				"values":[
+					["2018-11-01T10:00:02Z","1","hello-pod","second\n","stderr"],
+					["2018-11-01T10:00:01Z","2","hello-pod","first\n","stdout"],
+					["2018-11-01T10:00:02Z","0","hello-pod","between\n","stdout"]
+				]}]}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(&crd.Function{
+			Metadata: metav1.ObjectMeta{UID: k8stypes.UID("uid-" + path.Base(r.URL.Path))},
+		})
+	}))
+	defer controller.Close()
+	fe, err := NewWithConfig("http://executor.test", controller.URL, "http://router.test", Config{})
+	assert.NoError(t, err)
+
+	since := time.Date(2018, 11, 1, 10, 0, 0, 0, time.UTC)
+	until := since.Add(time.Minute)
+	logs, err := fe.Logs(context.Background(), types.FnRef{Runtime: Name, ID: "hello@v1,v2"}, since, until)
+	assert.NoError(t, err)
+	assert.Equal(t, []fnenv.LogEntry{
+		{Timestamp: since.Add(time.Second), Message: "first", Stream: "stdout", Source: "hello-pod"},
+		{Timestamp: since.Add(2 * time.Second), Message: "between", Stream: "stdout", Source: "hello-pod"},
+		{Timestamp: since.Add(2 * time.Second), Message: "second", Stream: "stderr", Source: "hello-pod"},
+	}, logs)
+
+	// The logs of all versions of the function are queried within the period.
+	assert.Len(t, queries, 1)
+	assert.Contains(t, queries[0], `("funcuid" = $funcuid0 OR "funcuid" = $funcuid1)`)
+	assert.Equal(t, "uid-hello-v1", params[0]["funcuid0"])
+	assert.Equal(t, "uid-hello-v2", params[0]["funcuid1"])
+	assert.Equal(t, float64(since.UnixNano()), params[0]["since"])
+	assert.Equal(t, float64(until.UnixNano()), params[0]["until"])
+}
+
+func TestParseLogEntries_Error(t *testing.T) {
+	resp := &influxResponse{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"results":[{"error":"database not found"}]}`), resp))
+	_, err := parseLogEntries(resp)
+	assert.Error(t, err)
+}
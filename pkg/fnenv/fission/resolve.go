@@ -0,0 +1,197 @@
+package fission
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission/crd"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const (
+	DefaultResolveTTL = 5 * time.Minute
+	rewatchDelay      = 5 * time.Second
+)
+
+var (
+	resolveCacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "resolve_cache_lookups_total",
+		Help:      "Total number of lookups of Fission functions in the resolution cache, by whether they were cached",
+	}, []string{"cached"})
+)
+
+func init() {
+	prometheus.MustRegister(resolveCacheLookups)
+}
+
+// ResolveConfig configures the caching of resolved functions.
+//
+// Resolving a function requires a request to the Fission controller, which is done for every function of every
+// workflow that is parsed. To avoid this, the function environment caches which functions exist for the TTL. Changes
+// to functions within the TTL are only noticed if the function environment watches the functions (see
+// WatchFunctions).
+type ResolveConfig struct {
+	// TTL is the duration for which a resolved function is cached. If negative, resolved functions are not cached.
+	TTL time.Duration
+}
+
+// resolvedFn is a function that has been resolved, along with its hints.
+type resolvedFn struct {
+	hints      *types.FnHints
+	resolvedAt time.Time
+}
+
+// resolveCache caches the functions that have been resolved, keyed by <namespace>/<name>.
+type resolveCache struct {
+	entries map[string]resolvedFn
+	mu      sync.Mutex
+}
+
+func (c *resolveCache) get(key string, ttl time.Duration) (*types.FnHints, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Since(entry.resolvedAt) >= ttl {
+		return nil, false
+	}
+	return entry.hints, true
+}
+
+func (c *resolveCache) put(key string, hints *types.FnHints) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]resolvedFn{}
+	}
+	c.entries[key] = resolvedFn{
+		hints:      hints,
+		resolvedAt: time.Now(),
+	}
+}
+
+func (c *resolveCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+func resolveKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// lookupFunction checks that the Fission function exists, and returns its hints. The result is cached for the TTL.
+func (fe *FunctionEnv) lookupFunction(namespace, name string) (*types.FnHints, error) {
+	key := resolveKey(namespace, name)
+	if hints, ok := fe.resolved.get(key, fe.resolveTTL); ok {
+		resolveCacheLookups.WithLabelValues("true").Inc()
+		return hints, nil
+	}
+	resolveCacheLookups.WithLabelValues("false").Inc()
+	fn, err := fe.controller.FunctionGet(&metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+	})
+	if err != nil {
+		return nil, err
+	}
+	hints, err := parseHints(fn.Metadata.Annotations)
+	if err != nil {
+		return nil, fmt.Errorf("fission function %s: %v", key, err)
+	}
+	fe.resolved.put(key, hints)
+	return hints, nil
+}
+
+// FunctionWatcher watches Fission functions. It is implemented by the crd.FunctionInterface.
+type FunctionWatcher interface {
+	Watch(opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// WatchFunctions watches the Fission functions until the context is canceled.
+//
+// Updated and deleted functions are evicted from the resolution cache, so that the next resolution of the function
+// checks the function with the controller again. If a function is recreated - deleted and created again, which gives
+// it a new UID - onRecreate is called with a reference to the function.
+func (fe *FunctionEnv) WatchFunctions(ctx context.Context, functions FunctionWatcher, onRecreate func(fn types.FnRef)) {
+	// The UIDs of the functions that have been seen, including the deleted ones, to detect recreated functions.
+	uids := map[string]k8stypes.UID{}
+	for {
+		watcher, err := functions.Watch(metav1.ListOptions{})
+		if err != nil {
+			log.Errorf("Failed to watch Fission functions: %v", err)
+		} else {
+			log.Info("Watching Fission functions")
+			fe.handleFunctionEvents(ctx, watcher, uids, onRecreate)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Stopped watching Fission functions")
+			return
+		case <-time.After(rewatchDelay):
+		}
+	}
+}
+
+// handleFunctionEvents handles the events of the watch until the watch is closed or the context is canceled.
+func (fe *FunctionEnv) handleFunctionEvents(ctx context.Context, watcher watch.Interface,
+	uids map[string]k8stypes.UID, onRecreate func(fn types.FnRef)) {
+	defer watcher.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return
+			}
+			fn, ok := event.Object.(*crd.Function)
+			if !ok {
+				log.Warnf("Unexpected object in watch of Fission functions: %T", event.Object)
+				continue
+			}
+			key := resolveKey(fn.Metadata.Namespace, fn.Metadata.Name)
+			fe.resolved.invalidate(key)
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				uid, seen := uids[key]
+				uids[key] = fn.Metadata.UID
+				if seen && uid != fn.Metadata.UID {
+					log.Infof("Fission function %s was recreated", key)
+					onRecreate(types.FnRef{
+						Runtime:   Name,
+						Namespace: fn.Metadata.Namespace,
+						ID:        fn.Metadata.Name,
+					})
+				}
+			}
+		}
+	}
+}
+
+// RefersTo returns whether the function reference refers to the Fission function, either directly or as one of its
+// versions.
+func (fe *FunctionEnv) RefersTo(ref types.FnRef, fn types.FnRef) bool {
+	if ref.Runtime != Name || fe.fnNamespace(ref) != fe.fnNamespace(fn) {
+		return false
+	}
+	versioned, err := parseVersionedFn(ref.ID)
+	if err != nil {
+		return false
+	}
+	for _, version := range versioned.Versions {
+		if versioned.FunctionName(version) == fn.ID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package fission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission/crd"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// newTestController returns a controller that knows every function.
+func newTestController() (*httptest.Server, *int32) {
+	var lookups int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		w.Write([]byte("{}"))
+	}))
+	return srv, &lookups
+}
+
+type testFunctionWatcher struct {
+	watcher *watch.FakeWatcher
+}
+
+func (w *testFunctionWatcher) Watch(opts metav1.ListOptions) (watch.Interface, error) {
+	return w.watcher, nil
+}
+
+func newFunction(name string, uid string) *crd.Function {
+	return &crd.Function{
+		Metadata: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceDefault,
+			UID:       k8stypes.UID(uid),
+		},
+	}
+}
+
+func TestFunctionEnv_ResolveCached(t *testing.T) {
+	controller, lookups := newTestController()
+	defer controller.Close()
+	fe, err := NewWithConfig("http://executor.test", controller.URL, "http://router.test", Config{})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		id, err := fe.Resolve(types.FnRef{Runtime: Name, ID: "hello@v1=90,v2=10"})
+		assert.NoError(t, err)
+		assert.Equal(t, "hello@v1=90,v2=10", id)
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt32(lookups))
+
+	// Functions in other namespaces are cached separately.
+	_, err = fe.Resolve(types.FnRef{Runtime: Name, Namespace: "team-a", ID: "hello-v1"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(lookups))
+}
+
+func TestFunctionEnv_ResolveNotCached(t *testing.T) {
+	controller, lookups := newTestController()
+	defer controller.Close()
+	fe, err := NewWithConfig("http://executor.test", controller.URL, "http://router.test", Config{
+		Resolve: ResolveConfig{TTL: -1},
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := fe.Resolve(types.FnRef{Runtime: Name, ID: "hello"})
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 2, atomic.LoadInt32(lookups))
+}
+
+func TestFunctionEnv_WatchFunctions(t *testing.T) {
+	controller, lookups := newTestController()
+	defer controller.Close()
+	fe, err := NewWithConfig("http://executor.test", controller.URL, "http://router.test", Config{})
+	assert.NoError(t, err)
+	functions := &testFunctionWatcher{watcher: watch.NewFake()}
+	recreated := make(chan types.FnRef, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go fe.WatchFunctions(ctx, functions, func(fn types.FnRef) {
+		recreated <- fn
+	})
+
+	functions.watcher.Add(newFunction("hello", "1"))
+	_, err = fe.Resolve(types.FnRef{Runtime: Name, ID: "hello"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(lookups))
+
+	// Changes to the function should invalidate the cached resolution.
+	functions.watcher.Modify(newFunction("hello", "1"))
+	functions.watcher.Delete(newFunction("hello", "1"))
+	functions.watcher.Add(newFunction("hello", "2"))
+	select {
+	case fn := <-recreated:
+		assert.Equal(t, types.FnRef{Runtime: Name, Namespace: metav1.NamespaceDefault, ID: "hello"}, fn)
+	case <-time.After(time.Second):
+		assert.Fail(t, "recreated function was not reported")
+	}
+	_, err = fe.Resolve(types.FnRef{Runtime: Name, ID: "hello"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(lookups))
+}
+
+func TestFunctionEnv_RefersTo(t *testing.T) {
+	fe := New("http://executor.test", "http://controller.test", "http://router.test")
+	fn := types.FnRef{Runtime: Name, Namespace: metav1.NamespaceDefault, ID: "hello-v2"}
+
+	assert.True(t, fe.RefersTo(types.FnRef{Runtime: Name, ID: "hello@v1=90,v2=10"}, fn))
+	assert.True(t, fe.RefersTo(types.FnRef{Runtime: Name, Namespace: metav1.NamespaceDefault, ID: "hello-v2"}, fn))
+	assert.False(t, fe.RefersTo(types.FnRef{Runtime: Name, ID: "hello"}, fn))
+	assert.False(t, fe.RefersTo(types.FnRef{Runtime: Name, Namespace: "team-a", ID: "hello@v2"}, fn))
+	assert.False(t, fe.RefersTo(types.FnRef{Runtime: "http", ID: "hello-v2"}, fn))
+}
@@ -0,0 +1,133 @@
+package fission
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	versionDelimiter = "@"
+	weightDelimiter  = "="
+	canaryDelimiter  = ","
+)
+
+var (
+	ErrInvalidVersion = errors.New("fnenv/fission: invalid function version")
+
+	versionInvocations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "fnenv_fission",
+		Name:      "version_invocations_total",
+		Help:      "Total number of invocations routed to each version of a Fission function",
+	}, []string{"fn", "version"})
+)
+
+func init() {
+	prometheus.MustRegister(versionInvocations)
+}
+
+// fnVersion is a version of a function that invocations are routed to.
+//
+// Fission has no notion of function versions; like the Fission canary deployments, each version is deployed as a
+// separate function. The version v2 of the function hello is the Fission function hello-v2.
+type fnVersion struct {
+	// Version is the version, or empty for the unversioned function.
+	Version string
+
+	// Weight is the relative share of the invocations that are routed to the version.
+	Weight int
+}
+
+// versionedFn is the function ID of a function reference, which optionally pins a version or splits the invocations
+// over weighted versions:
+//
+// - `hello`: the Fission function hello.
+// - `hello@v2`: the Fission function hello-v2.
+// - `hello@v1=90,v2=10`: 90% of the invocations to hello-v1, and 10% to hello-v2.
+type versionedFn struct {
+	Name     string
+	Versions []fnVersion
+}
+
+func parseVersionedFn(id string) (*versionedFn, error) {
+	parts := strings.SplitN(id, versionDelimiter, 2)
+	fn := &versionedFn{
+		Name: parts[0],
+	}
+	if len(fn.Name) == 0 {
+		return nil, fmt.Errorf("%v: function reference '%s' has no name", ErrInvalidVersion, id)
+	}
+	if len(parts) == 1 {
+		fn.Versions = []fnVersion{{Weight: 1}}
+		return fn, nil
+	}
+
+	var total int
+	for _, s := range strings.Split(parts[1], canaryDelimiter) {
+		version := fnVersion{Weight: 1}
+		if i := strings.Index(s, weightDelimiter); i >= 0 {
+			weight, err := strconv.Atoi(s[i+1:])
+			if err != nil || weight < 0 {
+				return nil, fmt.Errorf("%v: invalid weight in '%s'", ErrInvalidVersion, s)
+			}
+			version.Version = s[:i]
+			version.Weight = weight
+		} else {
+			version.Version = s
+		}
+		if len(version.Version) == 0 {
+			return nil, fmt.Errorf("%v: function reference '%s' has an empty version", ErrInvalidVersion, id)
+		}
+		total += version.Weight
+		fn.Versions = append(fn.Versions, version)
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%v: the weights of '%s' are all zero", ErrInvalidVersion, id)
+	}
+	return fn, nil
+}
+
+// FunctionName returns the name of the Fission function of the version.
+func (fn *versionedFn) FunctionName(version fnVersion) string {
+	if len(version.Version) == 0 {
+		return fn.Name
+	}
+	return fn.Name + "-" + version.Version
+}
+
+// Select picks the version to route an invocation to according to the weights of the versions.
+func (fn *versionedFn) Select() fnVersion {
+	if len(fn.Versions) == 1 {
+		return fn.Versions[0]
+	}
+	var total int
+	for _, version := range fn.Versions {
+		total += version.Weight
+	}
+	n := randIntn(total)
+	for _, version := range fn.Versions {
+		if n < version.Weight {
+			return version
+		}
+		n -= version.Weight
+	}
+	return fn.Versions[len(fn.Versions)-1]
+}
+
+var (
+	rnd   = rand.New(rand.NewSource(time.Now().UnixNano()))
+	rndMu sync.Mutex
+)
+
+func randIntn(n int) int {
+	rndMu.Lock()
+	defer rndMu.Unlock()
+	return rnd.Intn(n)
+}
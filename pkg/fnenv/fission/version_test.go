@@ -0,0 +1,61 @@
+package fission
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersionedFn(t *testing.T) {
+	for id, expected := range map[string]*versionedFn{
+		"hello":             {Name: "hello", Versions: []fnVersion{{Weight: 1}}},
+		"hello@v2":          {Name: "hello", Versions: []fnVersion{{Version: "v2", Weight: 1}}},
+		"hello@v1=90,v2=10": {Name: "hello", Versions: []fnVersion{{Version: "v1", Weight: 90}, {Version: "v2", Weight: 10}}},
+		"hello@v1=0,v2":     {Name: "hello", Versions: []fnVersion{{Version: "v1", Weight: 0}, {Version: "v2", Weight: 1}}},
+	} {
+		t.Run(id, func(t *testing.T) {
+			fn, err := parseVersionedFn(id)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, fn)
+		})
+	}
+
+	for _, id := range []string{"@v2", "hello@", "hello@v1,,v2", "hello@v1=a", "hello@v1=-1", "hello@v1=0"} {
+		t.Run(id, func(t *testing.T) {
+			_, err := parseVersionedFn(id)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVersionedFn_Select(t *testing.T) {
+	fn, err := parseVersionedFn("hello@v1=3,v2=1,v3=0")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello-v1", fn.FunctionName(fn.Versions[0]))
+
+	selected := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		selected[fn.Select().Version]++
+	}
+	assert.InDelta(t, 3000, selected["v1"], 200)
+	assert.InDelta(t, 1000, selected["v2"], 200)
+	assert.Zero(t, selected["v3"])
+}
+
+func TestFunctionEnv_InvokeVersion(t *testing.T) {
+	var path string
+	router := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+	}))
+	defer router.Close()
+	fe := newTestFunctionEnv(router.URL, Config{})
+
+	spec := newSpec("foo")
+	spec.FnRef.ID = "echo@v2"
+	status, err := fe.Invoke(spec)
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "/fission-function/echo-v2", path)
+}
@@ -17,9 +17,11 @@ package fnenv
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -100,6 +102,29 @@ type Resolver interface {
 	Resolve(targetFn string) (types.FnRef, error)
 }
 
+// NamespacedResolver is a Resolver that can resolve the function references of a workflow in the default namespace
+// of the workflow.
+type NamespacedResolver interface {
+	Resolver
+
+	// InNamespace returns a resolver that resolves function references without a namespace in the namespace.
+	InNamespace(namespace string) Resolver
+}
+
+// NamespaceScoped is implemented by the runtimes that scope functions to (Kubernetes) namespaces. Only for these
+// runtimes, function references without a namespace are resolved in the default namespace of the workflow; other
+// runtimes use the namespace of a function reference for other purposes, such as the address of the function.
+type NamespaceScoped interface {
+	NamespaceScoped() bool
+}
+
+// RefParser is implemented by the resolvers of runtimes that have their own format of function references, which
+// types.ParseFnRef cannot parse, such as the container images of jobs.
+type RefParser interface {
+	// ParseFnRef parses the function reference, including the scheme of the runtime.
+	ParseFnRef(s string) (types.FnRef, error)
+}
+
 // RuntimeResolver is the runtime environment component that resolves a reference to a function to a deterministic,
 // runtime-specific function UID.
 type RuntimeResolver interface {
@@ -108,9 +133,67 @@ type RuntimeResolver interface {
 	Resolve(ref types.FnRef) (string, error)
 }
 
+// HintResolver is implemented by the resolvers that can provide hints about the runtime behavior of the functions
+// that they resolve, such as the timeout of a function.
+type HintResolver interface {
+	// ResolveHints returns the hints of the resolved function, or nil if the function has no hints.
+	ResolveHints(fn types.FnRef) (*types.FnHints, error)
+}
+
+// LogProvider is implemented by the runtimes that can provide the log output of the functions that they run.
+type LogProvider interface {
+	// Logs returns the log lines that the function wrote between since and until, in chronological order. As
+	// instances of a function can be shared by several tasks, the logs may contain the lines of other invocations of
+	// the function within this period.
+	Logs(ctx context.Context, fn types.FnRef, since, until time.Time) ([]LogEntry, error)
+}
+
+// LogEntry is a line of the log output of a function.
+type LogEntry struct {
+	Timestamp time.Time
+	Message   string
+
+	// Stream is the output stream of the line, such as stdout or stderr.
+	Stream string
+
+	// Source identifies the instance of the function that wrote the line, such as the name of the pod.
+	Source string
+}
+
+// HeaderIdempotencyKey is the request header in which the HTTP-based runtimes pass the idempotency key of the task
+// invocation to the function, unless the inputs of the task set the header already.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// Redacted replaces the sensitive values in logs and traces.
+const Redacted = redact.Placeholder
+
 type InvokeConfig struct {
 	Ctx           context.Context
 	AwaitWorkflow time.Duration
+
+	// Sensitive contains the values in the inputs of the task, such as resolved secrets, that should not end up in
+	// logs and traces.
+	Sensitive []string
+
+	// Heartbeat is called by runtimes that support heartbeats to report that the function is still running.
+	Heartbeat func()
+}
+
+// Beat reports a heartbeat of the function invocation, if the caller watches the heartbeats.
+func (c *InvokeConfig) Beat() {
+	if c.Heartbeat != nil {
+		c.Heartbeat()
+	}
+}
+
+// Redact replaces the sensitive values in s, so that s can be logged or traced.
+func (c *InvokeConfig) Redact(s string) string {
+	for _, value := range c.Sensitive {
+		if len(value) > 0 {
+			s = strings.Replace(s, value, Redacted, -1)
+		}
+	}
+	return s
 }
 
 type InvokeOption func(config *InvokeConfig)
@@ -133,6 +216,20 @@ func AwaitWorkflow(timeout time.Duration) InvokeOption {
 	}
 }
 
+// Sensitive marks values in the inputs of the task as sensitive, so that runtimes redact them from logs and traces.
+func Sensitive(values ...string) InvokeOption {
+	return func(config *InvokeConfig) {
+		config.Sensitive = append(config.Sensitive, values...)
+	}
+}
+
+// WithHeartbeat sets the function that runtimes call to report the heartbeats of long-running functions.
+func WithHeartbeat(fn func()) InvokeOption {
+	return func(config *InvokeConfig) {
+		config.Heartbeat = fn
+	}
+}
+
 func WithContext(ctx context.Context) InvokeOption {
 	return func(config *InvokeConfig) {
 		config.Ctx = ctx
@@ -0,0 +1,236 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/fnenv/grpc/function.proto
+
+/*
+Package grpc is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/fnenv/grpc/function.proto
+
+It has these top-level messages:
+	InvokeRequest
+	InvokeResponse
+*/
+package grpc
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import fission_workflows_types "github.com/fission/fission-workflows/pkg/types/typedvalues"
+import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+
+import (
+	context "golang.org/x/net/context"
+	grpc1 "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type InvokeRequest struct {
+	// Function is the identifier of the function within the server.
+	Function string `protobuf:"bytes,1,opt,name=function" json:"function,omitempty"`
+	// Inputs contains the (resolved) inputs of the task.
+	Inputs map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,2,rep,name=inputs" json:"inputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// InvocationId is the id of the workflow invocation that the task is part of.
+	InvocationId string `protobuf:"bytes,3,opt,name=invocationId" json:"invocationId,omitempty"`
+	// TaskId is the id of the task within the workflow.
+	TaskId string `protobuf:"bytes,4,opt,name=taskId" json:"taskId,omitempty"`
+	// Deadline is the timestamp before which the function needs to be completed.
+	Deadline *google_protobuf1.Timestamp `protobuf:"bytes,5,opt,name=deadline" json:"deadline,omitempty"`
+}
+
+func (m *InvokeRequest) Reset()                    { *m = InvokeRequest{} }
+func (m *InvokeRequest) String() string            { return proto.CompactTextString(m) }
+func (*InvokeRequest) ProtoMessage()               {}
+func (*InvokeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *InvokeRequest) GetFunction() string {
+	if m != nil {
+		return m.Function
+	}
+	return ""
+}
+
+func (m *InvokeRequest) GetInputs() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Inputs
+	}
+	return nil
+}
+
+func (m *InvokeRequest) GetInvocationId() string {
+	if m != nil {
+		return m.InvocationId
+	}
+	return ""
+}
+
+func (m *InvokeRequest) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *InvokeRequest) GetDeadline() *google_protobuf1.Timestamp {
+	if m != nil {
+		return m.Deadline
+	}
+	return nil
+}
+
+type InvokeResponse struct {
+	// Output is the output of the function.
+	Output *fission_workflows_types.TypedValue `protobuf:"bytes,1,opt,name=output" json:"output,omitempty"`
+	// OutputHeaders contains optional metadata about the output.
+	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,2,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// Error is the message of the error that caused the function to fail. If it is set, the task fails.
+	Error string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *InvokeResponse) Reset()                    { *m = InvokeResponse{} }
+func (m *InvokeResponse) String() string            { return proto.CompactTextString(m) }
+func (*InvokeResponse) ProtoMessage()               {}
+func (*InvokeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *InvokeResponse) GetOutput() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Output
+	}
+	return nil
+}
+
+func (m *InvokeResponse) GetOutputHeaders() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.OutputHeaders
+	}
+	return nil
+}
+
+func (m *InvokeResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*InvokeRequest)(nil), "fission.workflows.fnenv.grpc.InvokeRequest")
+	proto.RegisterType((*InvokeResponse)(nil), "fission.workflows.fnenv.grpc.InvokeResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc1.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc1.SupportPackageIsVersion4
+
+// Client API for Function service
+
+type FunctionClient interface {
+	// Invoke executes the function to completion. Errors of the function itself should be returned in the error
+	// field of the response; gRPC errors are considered to be failures outside of the control of the function.
+	Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc1.CallOption) (*InvokeResponse, error)
+}
+
+type functionClient struct {
+	cc *grpc1.ClientConn
+}
+
+func NewFunctionClient(cc *grpc1.ClientConn) FunctionClient {
+	return &functionClient{cc}
+}
+
+func (c *functionClient) Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc1.CallOption) (*InvokeResponse, error) {
+	out := new(InvokeResponse)
+	err := grpc1.Invoke(ctx, "/fission.workflows.fnenv.grpc.Function/Invoke", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Function service
+
+type FunctionServer interface {
+	// Invoke executes the function to completion. Errors of the function itself should be returned in the error
+	// field of the response; gRPC errors are considered to be failures outside of the control of the function.
+	Invoke(context.Context, *InvokeRequest) (*InvokeResponse, error)
+}
+
+func RegisterFunctionServer(s *grpc1.Server, srv FunctionServer) {
+	s.RegisterService(&_Function_serviceDesc, srv)
+}
+
+func _Function_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc1.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FunctionServer).Invoke(ctx, in)
+	}
+	info := &grpc1.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.fnenv.grpc.Function/Invoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FunctionServer).Invoke(ctx, req.(*InvokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Function_serviceDesc = grpc1.ServiceDesc{
+	ServiceName: "fission.workflows.fnenv.grpc.Function",
+	HandlerType: (*FunctionServer)(nil),
+	Methods: []grpc1.MethodDesc{
+		{
+			MethodName: "Invoke",
+			Handler:    _Function_Invoke_Handler,
+		},
+	},
+	Streams:  []grpc1.StreamDesc{},
+	Metadata: "pkg/fnenv/grpc/function.proto",
+}
+
+func init() { proto.RegisterFile("pkg/fnenv/grpc/function.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 400 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x51, 0x5d, 0xab, 0xd3, 0x40,
+	0x10, 0x35, 0xe9, 0x6d, 0xa8, 0x53, 0xaf, 0xc8, 0x22, 0x12, 0x82, 0x62, 0x89, 0x2f, 0x05, 0x75,
+	0x03, 0x15, 0xfc, 0x7c, 0x13, 0x14, 0x03, 0x82, 0x10, 0x2e, 0x3e, 0xf8, 0x20, 0xa4, 0xc9, 0x26,
+	0x86, 0xa4, 0xbb, 0xdb, 0xfd, 0x48, 0xe9, 0x9f, 0xf2, 0xb7, 0xf8, 0x93, 0x64, 0x77, 0x93, 0x62,
+	0x40, 0x2e, 0xed, 0x4b, 0x3b, 0x93, 0x3d, 0xe7, 0xcc, 0x9c, 0x33, 0xf0, 0x84, 0xb7, 0x75, 0x52,
+	0x51, 0x42, 0xfb, 0xa4, 0x16, 0xbc, 0x48, 0x2a, 0x4d, 0x0b, 0xd5, 0x30, 0x8a, 0xb9, 0x60, 0x8a,
+	0xa1, 0xc7, 0x55, 0x23, 0xa5, 0x69, 0x0f, 0x4c, 0xb4, 0x55, 0xc7, 0x0e, 0x12, 0x5b, 0x30, 0x36,
+	0xe0, 0xe8, 0x6b, 0xdd, 0xa8, 0x5f, 0x7a, 0x8b, 0x0b, 0xb6, 0x4b, 0x06, 0xe0, 0xf8, 0xff, 0xf2,
+	0x44, 0x48, 0xcc, 0x04, 0x75, 0xe4, 0x44, 0xda, 0xdf, 0xb2, 0xcf, 0x3b, 0x3d, 0xad, 0xdd, 0xac,
+	0xe8, 0x69, 0xcd, 0x58, 0xdd, 0x91, 0xc4, 0x76, 0x5b, 0x5d, 0x25, 0xaa, 0xd9, 0x11, 0xa9, 0xf2,
+	0x1d, 0x77, 0x80, 0xf8, 0x8f, 0x0f, 0xd7, 0x29, 0xed, 0x59, 0x4b, 0x32, 0xb2, 0xd7, 0x44, 0x2a,
+	0x14, 0xc1, 0x62, 0x5c, 0x38, 0xf4, 0x56, 0xde, 0xfa, 0x6e, 0x76, 0xea, 0xd1, 0x37, 0x08, 0x1a,
+	0xca, 0xb5, 0x92, 0xa1, 0xbf, 0x9a, 0xad, 0x97, 0x9b, 0x37, 0xf8, 0x36, 0x2f, 0x78, 0x22, 0x8c,
+	0x53, 0xcb, 0xfc, 0x44, 0x95, 0x38, 0x66, 0x83, 0x0c, 0x8a, 0xe1, 0x5e, 0x43, 0x7b, 0x56, 0xe4,
+	0x46, 0x3e, 0x2d, 0xc3, 0x99, 0x1d, 0x38, 0xf9, 0x86, 0x1e, 0x41, 0xa0, 0x72, 0xd9, 0xa6, 0x65,
+	0x78, 0x65, 0x5f, 0x87, 0x0e, 0xbd, 0x86, 0x45, 0x49, 0xf2, 0xb2, 0x6b, 0x28, 0x09, 0xe7, 0x2b,
+	0x6f, 0xbd, 0xdc, 0x44, 0xd8, 0xd9, 0xc5, 0xa3, 0x5d, 0x7c, 0x33, 0xda, 0xcd, 0x4e, 0xd8, 0xe8,
+	0x27, 0x2c, 0xff, 0x59, 0x05, 0x3d, 0x80, 0x59, 0x4b, 0x8e, 0x83, 0x55, 0x53, 0xa2, 0x77, 0x30,
+	0xb7, 0x21, 0x86, 0xbe, 0x55, 0x7d, 0xf6, 0x1f, 0x93, 0x36, 0x7b, 0x7c, 0x63, 0xf2, 0xfe, 0x6e,
+	0xa0, 0x99, 0x63, 0xbc, 0xf7, 0xdf, 0x7a, 0xf1, 0x6f, 0x0f, 0xee, 0x8f, 0xce, 0x25, 0x67, 0x54,
+	0x12, 0xf4, 0x01, 0x02, 0xa6, 0x15, 0xd7, 0x2a, 0xf4, 0xce, 0x97, 0x1c, 0x28, 0x28, 0x85, 0x6b,
+	0x57, 0x7d, 0x21, 0x79, 0x49, 0x84, 0xbc, 0x64, 0xad, 0x29, 0x13, 0x3d, 0x84, 0x39, 0x11, 0x82,
+	0x89, 0x21, 0x67, 0xd7, 0x6c, 0xf6, 0xb0, 0xf8, 0x3c, 0x5e, 0x98, 0x40, 0xe0, 0x76, 0x47, 0xcf,
+	0x2f, 0xb8, 0x6d, 0xf4, 0xe2, 0x3c, 0xb0, 0x8b, 0x23, 0xbe, 0xf3, 0x31, 0xf8, 0x71, 0x65, 0x1e,
+	0xb6, 0x81, 0xbd, 0xd4, 0xab, 0xbf, 0x03, 0x00, 0x1f, 0xda, 0x63, 0xd4, 0x33, 0x03, 0x00, 0x00,
+}
@@ -0,0 +1,180 @@
+// Package grpc provides a function environment that invokes functions over gRPC.
+//
+// Function servers implement the Function service defined in function.proto. Compared to the HTTP-based function
+// environments, the inputs and outputs are exchanged as typed values, which avoids mapping them to and from HTTP
+// requests and responses.
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	Name = "grpc"
+)
+
+var (
+	ErrUnsupportedRuntime = errors.New("fnenv/grpc: function reference should have the grpc:// scheme")
+	ErrNoAddress          = errors.New("fnenv/grpc: function reference should contain the address of the server")
+
+	log = logrus.WithField("component", "fnenv.grpc")
+)
+
+// Config contains the configuration of the gRPC function environment.
+type Config struct {
+	// TLS enables TLS for the connections to the function servers.
+	TLS bool
+
+	// CAFile contains PEM-encoded CA certificates to verify the function servers with. If empty, the system CA
+	// certificates are used.
+	CAFile string
+}
+
+// FunctionEnv invokes functions on gRPC function servers.
+//
+// Function references have the format grpc://host:port/function. The connections to the servers are reused across
+// invocations.
+type FunctionEnv struct {
+	dialOpts []grpc.DialOption
+	conns    map[string]*grpc.ClientConn
+	connsMu  sync.Mutex
+}
+
+func New(dialOpts ...grpc.DialOption) *FunctionEnv {
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	return &FunctionEnv{
+		dialOpts: dialOpts,
+		conns:    map[string]*grpc.ClientConn{},
+	}
+}
+
+// NewFromConfig creates a gRPC function environment with the provided TLS configuration.
+func NewFromConfig(cfg Config) (*FunctionEnv, error) {
+	if !cfg.TLS && len(cfg.CAFile) == 0 {
+		return New(), nil
+	}
+	tlsConfig := &tls.Config{}
+	if len(cfg.CAFile) > 0 {
+		pem, err := ioutil.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates in CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return New(grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))), nil
+}
+
+// Resolve resolves the function reference to the function identifier within the server. The address of the server
+// is kept as the namespace of the function reference.
+func (fe *FunctionEnv) Resolve(ref types.FnRef) (string, error) {
+	if ref.Runtime != Name {
+		return "", ErrUnsupportedRuntime
+	}
+	if err := types.ValidateFnRef(ref, false); err != nil {
+		return "", err
+	}
+	if len(ref.Namespace) == 0 {
+		return "", ErrNoAddress
+	}
+	return ref.ID, nil
+}
+
+// Invoke executes the task on the function server in a blocking way.
+func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	cfg := fnenv.ParseInvokeOptions(opts)
+	fnref := spec.GetFnRef()
+	if len(fnref.GetNamespace()) == 0 {
+		return nil, ErrNoAddress
+	}
+	ctx := cfg.Ctx
+	if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil {
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	conn, err := fe.conn(fnref.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+
+	fnenv.FnActive.WithLabelValues(Name).Inc()
+	fnenv.FnCount.WithLabelValues(Name).Inc()
+	start := time.Now()
+	resp, err := NewFunctionClient(conn).Invoke(ctx, &InvokeRequest{
+		Function:     fnref.GetID(),
+		Inputs:       spec.GetInputs(),
+		InvocationId: spec.GetInvocationId(),
+		TaskId:       spec.GetTask().ID(),
+		Deadline:     spec.GetDeadline(),
+	})
+	fnenv.FnActive.WithLabelValues(Name).Dec()
+	fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(start) / time.Millisecond))
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke %s: %v", fnref.Format(), err)
+	}
+	log.Debugf("Invoked function %s (%v)", fnref.Format(), time.Since(start))
+
+	if len(resp.GetError()) > 0 {
+		return &types.TaskInvocationStatus{
+			Status: types.TaskInvocationStatus_FAILED,
+			Error: &types.Error{
+				Message: resp.GetError(),
+			},
+		}, nil
+	}
+	return &types.TaskInvocationStatus{
+		Status:        types.TaskInvocationStatus_SUCCEEDED,
+		Output:        resp.GetOutput(),
+		OutputHeaders: resp.GetOutputHeaders(),
+	}, nil
+}
+
+// Close closes the connections to the function servers.
+func (fe *FunctionEnv) Close() error {
+	fe.connsMu.Lock()
+	defer fe.connsMu.Unlock()
+	var err error
+	for addr, conn := range fe.conns {
+		if cerr := conn.Close(); cerr != nil {
+			err = cerr
+		}
+		delete(fe.conns, addr)
+	}
+	return err
+}
+
+func (fe *FunctionEnv) conn(addr string) (*grpc.ClientConn, error) {
+	fe.connsMu.Lock()
+	defer fe.connsMu.Unlock()
+	if conn, ok := fe.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(addr, fe.dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to function server %s: %v", addr, err)
+	}
+	fe.conns[addr] = conn
+	return conn, nil
+}
@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+type testFunctionServer struct{}
+
+func (s *testFunctionServer) Invoke(ctx context.Context, req *InvokeRequest) (*InvokeResponse, error) {
+	switch req.GetFunction() {
+	case "echo":
+		return &InvokeResponse{
+			Output: req.GetInputs()[types.InputMain],
+		}, nil
+	case "fail":
+		return &InvokeResponse{
+			Error: fmt.Sprintf("task %s failed", req.GetTaskId()),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown function %s", req.GetFunction())
+	}
+}
+
+func startTestServer(t *testing.T) (addr string, stop func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	RegisterFunctionServer(srv, &testFunctionServer{})
+	go srv.Serve(lis)
+	return lis.Addr().String(), srv.Stop
+}
+
+func newSpec(addr string, fn string, input interface{}) *types.TaskInvocationSpec {
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+	return &types.TaskInvocationSpec{
+		FnRef: &types.FnRef{Runtime: Name, Namespace: addr, ID: fn},
+		Inputs: typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+			types.InputMain: input,
+		}),
+		Task: &types.Task{
+			Metadata: &types.ObjectMetadata{Id: "task"},
+		},
+		Deadline: deadline,
+	}
+}
+
+func TestFunctionEnv_Resolve(t *testing.T) {
+	fe := New()
+
+	fnref, err := types.ParseFnRef("grpc://localhost:9000/echo")
+	assert.NoError(t, err)
+	fnID, err := fe.Resolve(fnref)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", fnID)
+
+	_, err = fe.Resolve(types.NewFnRef(Name, "", "echo"))
+	assert.Equal(t, ErrNoAddress, err)
+	_, err = fe.Resolve(types.NewFnRef("", "", "echo"))
+	assert.Equal(t, ErrUnsupportedRuntime, err)
+}
+
+func TestFunctionEnv_Invoke(t *testing.T) {
+	addr, stop := startTestServer(t)
+	defer stop()
+	fe := New()
+	defer fe.Close()
+
+	status, err := fe.Invoke(newSpec(addr, "echo", map[string]interface{}{"foo": "bar"}))
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, typedvalues.MustUnwrap(status.GetOutput()))
+
+	// Functions errors should fail the task
+	status, err = fe.Invoke(newSpec(addr, "fail", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, status.GetStatus())
+	assert.Equal(t, "task task failed", status.GetError().GetMessage())
+
+	// gRPC errors should be returned as errors
+	_, err = fe.Invoke(newSpec(addr, "unknown", nil))
+	assert.Error(t, err)
+}
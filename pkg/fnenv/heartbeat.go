@@ -0,0 +1,105 @@
+package fnenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatTimeout is the default maximum time between the heartbeats of a function invocation.
+const DefaultHeartbeatTimeout = 5 * time.Minute
+
+// ErrHeartbeatTimeout is returned for function invocations of which the heartbeats stopped before they completed.
+var ErrHeartbeatTimeout = errors.New("heartbeat timeout")
+
+// HeartbeatTimeout is the maximum time between the heartbeats of a function invocation, after which the invocation
+// is considered dead. If 0, heartbeats are not checked.
+var HeartbeatTimeout = DefaultHeartbeatTimeout
+
+// HeartbeatPollRatio is the minimum ratio between the HeartbeatTimeout and the poll intervals of the runtimes that
+// report a heartbeat on each poll. With a smaller ratio, a single delayed poll would fail a healthy invocation.
+const HeartbeatPollRatio = 3
+
+// ValidateHeartbeatTimeout returns an error if the heartbeat timeout is less than HeartbeatPollRatio times one of the
+// poll intervals, which are keyed by the name of their runtime.
+func ValidateHeartbeatTimeout(timeout time.Duration, pollIntervals map[string]time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	var names []string
+	for name := range pollIntervals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if interval := pollIntervals[name]; timeout < HeartbeatPollRatio*interval {
+			return fmt.Errorf("heartbeat timeout %v should be at least %d times the poll interval %v of %s",
+				timeout, HeartbeatPollRatio, interval, name)
+		}
+	}
+	return nil
+}
+
+// MaxHeartbeatInterval returns the maximum time between the heartbeats of a function invocation that runtimes should
+// allow, such as when a function asks to be polled later. If 0, heartbeats are not checked.
+func MaxHeartbeatInterval() time.Duration {
+	return HeartbeatTimeout / HeartbeatPollRatio
+}
+
+// Heartbeat tracks the liveness of a function invocation. Runtimes that support heartbeats report them while the
+// function is running (see InvokeConfig.Beat), such as on each poll of a long-running function that is still in
+// progress. As other runtimes do not report heartbeats at all, an invocation is only considered dead if its heartbeats
+// stop after the first one.
+type Heartbeat struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	last    time.Time
+}
+
+func NewHeartbeat(timeout time.Duration) *Heartbeat {
+	return &Heartbeat{
+		timeout: timeout,
+	}
+}
+
+// Beat reports that the function invocation is still alive.
+func (h *Heartbeat) Beat() {
+	h.mu.Lock()
+	h.last = time.Now()
+	h.mu.Unlock()
+}
+
+// Last returns the time of the last heartbeat, or the zero time if there has not been any.
+func (h *Heartbeat) Last() time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+// Watch returns a channel that is closed once the heartbeats have stopped for longer than the timeout. The heartbeats
+// are watched until the context is done. If the timeout is 0, the returned channel is nil, which blocks forever.
+func (h *Heartbeat) Watch(ctx context.Context) <-chan struct{} {
+	if h.timeout <= 0 {
+		return nil
+	}
+	dead := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(h.timeout / 4)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if last := h.Last(); !last.IsZero() && time.Since(last) > h.timeout {
+					close(dead)
+					return
+				}
+			}
+		}
+	}()
+	return dead
+}
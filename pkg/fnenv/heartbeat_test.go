@@ -0,0 +1,63 @@
+package fnenv
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeartbeat(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	timeout := 40 * time.Millisecond
+
+	// Without heartbeats, the invocation is never considered dead.
+	heartbeat := NewHeartbeat(timeout)
+	dead := heartbeat.Watch(ctx)
+	select {
+	case <-dead:
+		t.Fatal("invocation without heartbeats considered dead")
+	case <-time.After(3 * timeout):
+	}
+
+	// As long as the heartbeats continue, the invocation is alive.
+	for i := 0; i < 6; i++ {
+		heartbeat.Beat()
+		select {
+		case <-dead:
+			t.Fatal("invocation with heartbeats considered dead")
+		case <-time.After(timeout / 2):
+		}
+	}
+	assert.False(t, heartbeat.Last().IsZero())
+
+	// Once the heartbeats stop, the invocation is dead.
+	select {
+	case <-dead:
+	case <-time.After(5 * timeout):
+		t.Fatal("invocation of which the heartbeats stopped not considered dead")
+	}
+
+	assert.Nil(t, NewHeartbeat(0).Watch(ctx))
+}
+
+func TestInvokeConfigBeat(t *testing.T) {
+	var beats int
+	ParseInvokeOptions(nil).Beat()
+	ParseInvokeOptions([]InvokeOption{WithHeartbeat(func() { beats++ })}).Beat()
+	assert.Equal(t, 1, beats)
+}
+
+func TestValidateHeartbeatTimeout(t *testing.T) {
+	intervals := map[string]time.Duration{
+		"fission": time.Second,
+		"job":     5 * time.Second,
+	}
+	assert.NoError(t, ValidateHeartbeatTimeout(DefaultHeartbeatTimeout, intervals))
+	assert.NoError(t, ValidateHeartbeatTimeout(15*time.Second, intervals))
+	assert.NoError(t, ValidateHeartbeatTimeout(0, intervals))
+	err := ValidateHeartbeatTimeout(10*time.Second, intervals)
+	assert.EqualError(t, err, "heartbeat timeout 10s should be at least 3 times the poll interval 5s of job")
+}
@@ -2,11 +2,15 @@ package http
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/fnenv"
@@ -14,14 +18,92 @@ import (
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/httpconv"
 	"github.com/fission/fission-workflows/pkg/util/backoff"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	AuthTypeBasic  = "basic"
+	AuthTypeBearer = "bearer"
+	headerAuth     = "Authorization"
+)
+
 var (
 	ErrUnsupportedScheme = errors.New("fnenv/http: unsupported scheme")
+	ErrInvalidAuth       = errors.New("fnenv/http: invalid auth, expected <host>=basic:<username>:<password> or " +
+		"<host>=bearer:<token>")
 )
 
+// Config contains the configuration of the HTTP function environment.
+type Config struct {
+	// DefaultMethod is the HTTP method used when the task does not specify the method input (default: GET).
+	DefaultMethod string
+
+	// TLS configures the TLS connections to HTTPS endpoints.
+	TLS TLSConfig
+
+	// Auth contains the credentials to authenticate requests with, by host (including the port, if specified).
+	// Credentials are only added to requests that do not have an Authorization header yet.
+	Auth map[string]Auth
+}
+
+// TLSConfig contains the TLS configuration of the HTTP function environment.
+type TLSConfig struct {
+	CAFile             string // PEM-encoded CA certificates, in addition to the system CA certificates.
+	CertFile           string // PEM-encoded client certificate, used for mutual TLS.
+	KeyFile            string // PEM-encoded key of the client certificate.
+	InsecureSkipVerify bool
+}
+
+// Auth contains the credentials of either basic or bearer authentication.
+type Auth struct {
+	Type     string
+	Username string
+	Password string
+	Token    string
+}
+
+// Header formats the credentials as the value of the Authorization header.
+func (a Auth) Header() string {
+	switch a.Type {
+	case AuthTypeBasic:
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password))
+	default:
+		return "Bearer " + a.Token
+	}
+}
+
+// ParseAuth parses credentials for a host in the format <host>=basic:<username>:<password> or
+// <host>=bearer:<token>.
+func ParseAuth(s string) (host string, auth Auth, err error) {
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 {
+		return "", Auth{}, ErrInvalidAuth
+	}
+	host = parts[0]
+	creds := strings.SplitN(parts[1], ":", 2)
+	if len(creds) != 2 {
+		return "", Auth{}, ErrInvalidAuth
+	}
+	switch strings.ToLower(creds[0]) {
+	case AuthTypeBasic:
+		userpass := strings.SplitN(creds[1], ":", 2)
+		if len(userpass) != 2 || len(userpass[0]) == 0 {
+			return "", Auth{}, ErrInvalidAuth
+		}
+		auth = Auth{Type: AuthTypeBasic, Username: userpass[0], Password: userpass[1]}
+	case AuthTypeBearer:
+		if len(creds[1]) == 0 {
+			return "", Auth{}, ErrInvalidAuth
+		}
+		auth = Auth{Type: AuthTypeBearer, Token: creds[1]}
+	default:
+		return "", Auth{}, ErrInvalidAuth
+	}
+	return host, auth, nil
+}
+
 func New() *Runtime {
 	mapper := httpconv.DefaultHTTPMapper.Clone()
 	mapper.DefaultHTTPMethod = http.MethodGet
@@ -31,9 +113,61 @@ func New() *Runtime {
 	}
 }
 
+// NewFromConfig creates an HTTP function environment with the provided method, TLS, and auth configuration.
+func NewFromConfig(cfg Config) (*Runtime, error) {
+	runtime := New()
+	if len(cfg.DefaultMethod) > 0 {
+		runtime.httpconv.DefaultHTTPMethod = strings.ToUpper(cfg.DefaultMethod)
+	}
+	tlsConfig, err := cfg.TLS.Build()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		runtime.Client.Transport = transport
+	}
+	runtime.auth = cfg.Auth
+	return runtime, nil
+}
+
 type Runtime struct {
 	Client   *http.Client
 	httpconv *httpconv.HTTPMapper
+	auth     map[string]Auth
+}
+
+// Build loads the certificates of the TLS configuration. It returns nil if the configuration is empty.
+func (c TLSConfig) Build() (*tls.Config, error) {
+	if c == (TLSConfig{}) {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if len(c.CAFile) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates in CA file %s", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(c.CertFile) > 0 || len(c.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
 }
 
 // Example: https://us-east1-personal-erwinvaneyk.cloudfunctions.net/helloworld
@@ -59,8 +193,7 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 	req := (&http.Request{}).WithContext(cfg.Ctx)
 
 	// Parse URL
-	fnref := spec.FnRef
-	fnUrl, err := url.Parse(fnref.Format())
+	fnUrl, err := parseFnURL(spec.FnRef)
 	if err != nil {
 		return nil, err
 	}
@@ -72,14 +205,23 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 		return nil, err
 	}
 
-	logrus.Infof("HTTP request: %s %v", req.Method, req.URL)
+	if len(req.Header.Get(fnenv.HeaderIdempotencyKey)) == 0 {
+		req.Header.Set(fnenv.HeaderIdempotencyKey, spec.IdempotencyKey())
+	}
+
+	// Add the configured credentials of the host
+	if auth, ok := r.auth[fnUrl.Host]; ok && len(req.Header.Get(headerAuth)) == 0 {
+		req.Header.Set(headerAuth, auth.Header())
+	}
+
+	logrus.Infof("HTTP request: %s %v", req.Method, cfg.Redact(req.URL.String()))
 	if logrus.GetLevel() == logrus.DebugLevel {
 		fmt.Println("--- HTTP Request ---")
-		bs, err := httputil.DumpRequest(req, true)
+		bs, err := redact.DefaultRedactor.DumpRequest(req)
 		if err != nil {
 			logrus.Error(err)
 		}
-		fmt.Println(string(bs))
+		fmt.Println(cfg.Redact(string(bs)))
 		fmt.Println("--- HTTP Request end ---")
 	}
 
@@ -112,11 +254,11 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 	logrus.Infof("HTTP response: %d - %s", resp.StatusCode, resp.Header.Get("Content-Type"))
 	if logrus.GetLevel() == logrus.DebugLevel {
 		fmt.Println("--- HTTP Response ---")
-		bs, err := httputil.DumpResponse(resp, true)
+		bs, err := redact.DefaultRedactor.DumpResponse(resp)
 		if err != nil {
 			logrus.Error(err)
 		}
-		fmt.Println(string(bs))
+		fmt.Println(cfg.Redact(string(bs)))
 		fmt.Println("--- HTTP Response end ---")
 	}
 
@@ -129,7 +271,8 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 		return &types.TaskInvocationStatus{
 			Status: types.TaskInvocationStatus_FAILED,
 			Error: &types.Error{
-				Message: fmt.Sprintf("HTTP runtime request error: %v", msg),
+				Message:    fmt.Sprintf("HTTP runtime request error: %v", msg),
+				StatusCode: int32(resp.StatusCode),
 			},
 		}, nil
 	}
@@ -138,3 +281,12 @@ func (r *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOpt
 		Output: output,
 	}, nil
 }
+
+// parseFnURL returns the URL of the function. The ID of a resolved fnref is the full URL of the function, whereas for
+// an unresolved fnref the URL is formatted from the runtime (scheme), namespace (host), and ID (path).
+func parseFnURL(fnref *types.FnRef) (*url.URL, error) {
+	if u, err := url.Parse(fnref.GetID()); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return u, nil
+	}
+	return url.Parse(fnref.Format())
+}
@@ -1,9 +1,21 @@
 package http
 
 import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -42,3 +54,114 @@ func TestRuntime_ResolveInvalid(t *testing.T) {
 		})
 	}
 }
+
+func TestRuntime_InvokeResolved(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, "/fn", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, (&types.TaskInvocationSpec{InvocationId: "wi-123", TaskId: "task"}).IdempotencyKey(),
+			r.Header.Get(fnenv.HeaderIdempotencyKey))
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello"))
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+
+	runtime, err := NewFromConfig(Config{
+		DefaultMethod: "put",
+		Auth: map[string]Auth{
+			u.Host: {Type: AuthTypeBearer, Token: "secret"},
+		},
+	})
+	assert.NoError(t, err)
+
+	// Resolve the function reference as the resolver would
+	fnref, err := types.ParseFnRef(ts.URL + "/fn")
+	assert.NoError(t, err)
+	fnID, err := runtime.Resolve(fnref)
+	assert.NoError(t, err)
+	status, err := runtime.Invoke(&types.TaskInvocationSpec{
+		FnRef:        &types.FnRef{Runtime: fnref.Runtime, Namespace: fnref.Namespace, ID: fnID},
+		InvocationId: "wi-123",
+		TaskId:       "task",
+		Deadline:     mustTimestamp(time.Now().Add(10 * time.Second)),
+	})
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "hello", typedvalues.MustUnwrap(status.GetOutput()))
+}
+
+func TestRuntime_InvokeTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "user", user)
+		assert.Equal(t, "pass", pass)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("secure"))
+	}))
+	defer ts.Close()
+	u, err := url.Parse(ts.URL)
+	assert.NoError(t, err)
+	fnref, err := types.ParseFnRef(ts.URL + "/fn")
+	assert.NoError(t, err)
+	spec := &types.TaskInvocationSpec{
+		FnRef:    &fnref,
+		Deadline: mustTimestamp(time.Now().Add(10 * time.Second)),
+	}
+
+	// Write the certificate of the test server to a CA file
+	dir, err := ioutil.TempDir("", "fnenv-http")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	caFile := filepath.Join(dir, "ca.pem")
+	err = ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: ts.Certificate().Raw,
+	}), 0600)
+	assert.NoError(t, err)
+
+	runtime, err := NewFromConfig(Config{
+		TLS: TLSConfig{CAFile: caFile},
+		Auth: map[string]Auth{
+			u.Host: {Type: AuthTypeBasic, Username: "user", Password: "pass"},
+		},
+	})
+	assert.NoError(t, err)
+	status, err := runtime.Invoke(spec)
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, "secure", typedvalues.MustUnwrap(status.GetOutput()))
+
+	// A missing CA file should be rejected
+	_, err = NewFromConfig(Config{TLS: TLSConfig{CAFile: filepath.Join(dir, "missing.pem")}})
+	assert.Error(t, err)
+}
+
+func TestParseAuth(t *testing.T) {
+	host, auth, err := ParseAuth("api.example.com=basic:user:pa:ss")
+	assert.NoError(t, err)
+	assert.Equal(t, "api.example.com", host)
+	assert.Equal(t, Auth{Type: AuthTypeBasic, Username: "user", Password: "pa:ss"}, auth)
+
+	host, auth, err = ParseAuth("localhost:8080=Bearer:abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:8080", host)
+	assert.Equal(t, "Bearer abc", auth.Header())
+
+	for _, invalid := range []string{"", "api.example.com", "=bearer:abc", "api.example.com=bearer:",
+		"api.example.com=basic:user", "api.example.com=digest:abc"} {
+		_, _, err := ParseAuth(invalid)
+		assert.Equal(t, ErrInvalidAuth, err, invalid)
+	}
+}
+
+func mustTimestamp(t time.Time) *timestamp.Timestamp {
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		panic(err)
+	}
+	return ts
+}
@@ -0,0 +1,510 @@
+// Package job provides a function environment that runs tasks as Kubernetes Jobs.
+//
+// Unlike Fission functions, which are bounded by the lifetime of an HTTP request, jobs are suited for long-running
+// batch tasks. For each task invocation a Job is created, which is watched until it completes or fails. The logs of
+// the container are captured as the output of the task.
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	batchclient "k8s.io/client-go/kubernetes/typed/batch/v1"
+)
+
+const (
+	Name = "job"
+
+	InputCommand = "command"
+	InputArgs    = "args"
+	InputEnv     = "env"
+
+	// EnvInput is the environment variable that contains the main input of the task. Strings are passed as is,
+	// other values are encoded as JSON.
+	EnvInput = "TASK_INPUT"
+
+	DefaultNamespace    = "default"
+	DefaultPollInterval = 5 * time.Second
+	DefaultMaxLogSize   = 4 * 1024 * 1024
+
+	containerName        = "task"
+	labelJobName         = "job-name"
+	labelManagedBy       = "app.kubernetes.io/managed-by"
+	annotationInvocation = "workflows.fission.io/invocation"
+	annotationTask       = "workflows.fission.io/task"
+	managedBy            = "fission-workflows"
+)
+
+var (
+	ErrUnsupportedRuntime = errors.New("fnenv/job: function reference should have the job:// scheme")
+
+	log = logrus.WithField("component", "fnenv.job")
+)
+
+// Config contains the configuration of the Job function environment.
+type Config struct {
+	// Namespace is the namespace in which jobs are created if the function reference does not specify one.
+	Namespace string
+
+	// ServiceAccount is the service account that the pods of the jobs run as (optional).
+	ServiceAccount string
+
+	// PollInterval is the interval at which the job is checked, in addition to watching it.
+	PollInterval time.Duration
+
+	// MaxLogSize is the maximum number of bytes of the logs that are captured as the output.
+	MaxLogSize int64
+
+	// KeepJobs disables the deletion of jobs after they have finished.
+	KeepJobs bool
+}
+
+// LogReader returns a reader of the logs of the task container in the pod.
+type LogReader func(namespace, pod string) (io.ReadCloser, error)
+
+// FunctionEnv runs tasks as Kubernetes Jobs.
+//
+// Function references have the format job://[namespace/]image, for example job://busybox:1.31,
+// job://batch/busybox:1.31 or job://gcr.io/project/image:1.0. See ParseFnRef for how the namespace is distinguished
+// from the image.
+type FunctionEnv struct {
+	client kubernetes.Interface
+	cfg    Config
+
+	// Logs reads the logs of the pods of the jobs. By default the logs are read using the Kubernetes API.
+	Logs LogReader
+}
+
+func New(client kubernetes.Interface, cfg Config) *FunctionEnv {
+	if len(cfg.Namespace) == 0 {
+		cfg.Namespace = DefaultNamespace
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.MaxLogSize <= 0 {
+		cfg.MaxLogSize = DefaultMaxLogSize
+	}
+	fe := &FunctionEnv{
+		client: client,
+		cfg:    cfg,
+	}
+	fe.Logs = func(namespace, pod string) (io.ReadCloser, error) {
+		return fe.client.CoreV1().Pods(namespace).GetLogs(pod, &corev1.PodLogOptions{
+			Container: containerName,
+		}).Stream()
+	}
+	return fe
+}
+
+// NamespaceScoped signals that the namespace of a function reference is the namespace of the job.
+func (fe *FunctionEnv) NamespaceScoped() bool {
+	return true
+}
+
+// ParseFnRef parses a job:// function reference into the namespace and the image of the job. Image references are
+// not valid URLs (such as busybox:1.31), so they are not parsed by types.ParseFnRef.
+//
+// The first path segment of the reference is the namespace, unless it is the registry host of the image. Registry
+// hosts contain a dot or a colon, or are localhost, which namespaces cannot be. An empty first segment (such as in
+// job:///tensorflow/tensorflow) means that the reference has no namespace.
+func (fe *FunctionEnv) ParseFnRef(s string) (types.FnRef, error) {
+	prefix := Name + "://"
+	if !strings.HasPrefix(s, prefix) {
+		return types.FnRef{}, ErrUnsupportedRuntime
+	}
+	image := strings.TrimPrefix(s, prefix)
+	var namespace string
+	if i := strings.Index(image, "/"); i >= 0 && !isRegistryHost(image[:i]) {
+		namespace, image = image[:i], image[i+1:]
+	}
+	if len(image) == 0 {
+		return types.FnRef{}, types.ErrInvalidFnRef
+	}
+	return types.NewFnRef(Name, namespace, image), nil
+}
+
+// Resolve resolves the function reference to the image of the job. Only explicit job:// references are resolved.
+func (fe *FunctionEnv) Resolve(ref types.FnRef) (string, error) {
+	if ref.Runtime != Name {
+		return "", ErrUnsupportedRuntime
+	}
+	if err := types.ValidateFnRef(ref, false); err != nil {
+		return "", err
+	}
+	return ref.ID, nil
+}
+
+// Invoke creates a job for the task invocation, and blocks until the job has finished.
+func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	cfg := fnenv.ParseInvokeOptions(opts)
+	ctx := cfg.Ctx
+	if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil {
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	job, err := fe.createJobSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	namespace := job.Namespace
+	jobs := fe.client.BatchV1().Jobs(namespace)
+	job, err = jobs.Create(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+	ctxLog := log.WithFields(logrus.Fields{
+		"job":       job.Name,
+		"namespace": namespace,
+		"image":     spec.GetFnRef().GetID(),
+	})
+	ctxLog.Info("Created job")
+	fnenv.FnActive.WithLabelValues(Name).Inc()
+	fnenv.FnCount.WithLabelValues(Name).Inc()
+	start := time.Now()
+	defer func() {
+		fnenv.FnActive.WithLabelValues(Name).Dec()
+		fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(start) / time.Millisecond))
+	}()
+
+	finished, err := fe.await(ctx, jobs, job.Name, cfg.Beat)
+	if !fe.cfg.KeepJobs {
+		defer fe.delete(jobs, job.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to await job %s: %v", job.Name, err)
+	}
+	ctxLog.Infof("Job finished (succeeded: %v)", jobSucceeded(finished))
+	return fe.collectStatus(finished)
+}
+
+func (fe *FunctionEnv) createJobSpec(spec *types.TaskInvocationSpec) (*batchv1.Job, error) {
+	fnref := spec.GetFnRef()
+	if len(fnref.GetID()) == 0 {
+		return nil, types.ErrFnRefNoID
+	}
+	namespace := fnref.GetNamespace()
+	if len(namespace) == 0 {
+		namespace = fe.cfg.Namespace
+	}
+
+	container := corev1.Container{
+		Name:  containerName,
+		Image: fnref.GetID(),
+	}
+	var err error
+	inputs := spec.GetInputs()
+	if container.Command, err = unwrapStrings(inputs[InputCommand]); err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", InputCommand, err)
+	}
+	if container.Args, err = unwrapStrings(inputs[InputArgs]); err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", InputArgs, err)
+	}
+	if container.Env, err = formatEnv(inputs); err != nil {
+		return nil, err
+	}
+
+	var backoffLimit int32
+	labels := map[string]string{
+		labelManagedBy: managedBy,
+	}
+	annotations := map[string]string{
+		annotationInvocation: spec.GetInvocationId(),
+		annotationTask:       spec.GetTask().ID(),
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "wf-" + util.UID(),
+			Namespace:   namespace,
+			Labels:      labels,
+			Annotations: annotations,
+		},
+		Spec: batchv1.JobSpec{
+			// Retries are the responsibility of the workflow engine.
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: fe.cfg.ServiceAccount,
+					Containers:         []corev1.Container{container},
+				},
+			},
+		},
+	}
+	if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil {
+		seconds := int64(time.Until(deadline) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		job.Spec.ActiveDeadlineSeconds = &seconds
+	}
+	return job, nil
+}
+
+// await watches the job until it has finished. In case the watch fails or is closed, the job is polled instead. Each
+// check of a job that is still running is reported as a heartbeat.
+func (fe *FunctionEnv) await(ctx context.Context, jobs batchclient.JobInterface, name string,
+	beat func()) (*batchv1.Job, error) {
+	var events <-chan watch.Event
+	w, err := jobs.Watch(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		log.Debugf("Failed to watch job %s, falling back to polling: %v", name, err)
+	} else {
+		defer w.Stop()
+		events = w.ResultChan()
+	}
+	ticker := time.NewTicker(fe.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := jobs.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if jobFinished(job) {
+			return job, nil
+		}
+		beat()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+func (fe *FunctionEnv) collectStatus(job *batchv1.Job) (*types.TaskInvocationStatus, error) {
+	pod, err := fe.findPod(job)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []byte
+	if pod != nil {
+		logs, err = fe.readLogs(pod)
+		if err != nil {
+			log.Warnf("Failed to read logs of job %s: %v", job.Name, err)
+		}
+	}
+
+	if !jobSucceeded(job) {
+		msg := fmt.Sprintf("job %s failed", job.Name)
+		if terminated := containerTermination(pod); terminated != nil {
+			msg = fmt.Sprintf("%s with exit code %d", msg, terminated.ExitCode)
+			if len(terminated.Message) > 0 {
+				msg = fmt.Sprintf("%s: %s", msg, strings.TrimSpace(terminated.Message))
+			}
+		} else if reason := jobFailureReason(job); len(reason) > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, reason)
+		}
+		if len(logs) > 0 {
+			msg = fmt.Sprintf("%s\n%s", msg, util.Truncate(strings.TrimSpace(string(logs)), 1000))
+		}
+		return &types.TaskInvocationStatus{
+			Status: types.TaskInvocationStatus_FAILED,
+			Error: &types.Error{
+				Message: msg,
+			},
+		}, nil
+	}
+
+	output, err := parseOutput(logs)
+	if err != nil {
+		return nil, err
+	}
+	return &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: output,
+	}, nil
+}
+
+// findPod returns the most recently created pod of the job, or nil if there is none.
+func (fe *FunctionEnv) findPod(job *batchv1.Job) (*corev1.Pod, error) {
+	pods, err := fe.client.CoreV1().Pods(job.Namespace).List(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s", labelJobName, job.Name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pods of job %s: %v", job.Name, err)
+	}
+	var latest *corev1.Pod
+	for i, pod := range pods.Items {
+		if latest == nil || latest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			latest = &pods.Items[i]
+		}
+	}
+	return latest, nil
+}
+
+func (fe *FunctionEnv) readLogs(pod *corev1.Pod) ([]byte, error) {
+	rc, err := fe.Logs(pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(io.LimitReader(rc, fe.cfg.MaxLogSize))
+}
+
+func (fe *FunctionEnv) delete(jobs batchclient.JobInterface, name string) {
+	propagation := metav1.DeletePropagationBackground
+	err := jobs.Delete(name, &metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil {
+		log.Warnf("Failed to delete job %s: %v", name, err)
+	}
+}
+
+// parseOutput interprets the logs as JSON, falling back to a string if the logs are not valid JSON.
+func parseOutput(logs []byte) (*typedvalues.TypedValue, error) {
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	var i interface{}
+	if err := json.Unmarshal(logs, &i); err == nil {
+		return typedvalues.Wrap(i)
+	}
+	return typedvalues.Wrap(string(logs))
+}
+
+func formatEnv(inputs map[string]*typedvalues.TypedValue) ([]corev1.EnvVar, error) {
+	var env []corev1.EnvVar
+	if tv, ok := inputs[InputEnv]; ok {
+		i, err := typedvalues.Unwrap(tv)
+		if err != nil {
+			return nil, err
+		}
+		vars, ok := i.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s input: expected a map, but was %s", InputEnv, tv.ValueType())
+		}
+		names := make([]string, 0, len(vars))
+		for k := range vars {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			env = append(env, corev1.EnvVar{Name: k, Value: fmt.Sprintf("%v", vars[k])})
+		}
+	}
+
+	main, ok := inputs[types.InputMain]
+	if !ok {
+		main, ok = inputs[types.InputBody]
+	}
+	if ok {
+		i, err := typedvalues.Unwrap(main)
+		if err != nil {
+			return nil, err
+		}
+		value, ok := i.(string)
+		if !ok {
+			bs, err := json.Marshal(i)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode input: %v", err)
+			}
+			value = string(bs)
+		}
+		env = append(env, corev1.EnvVar{Name: EnvInput, Value: value})
+	}
+	return env, nil
+}
+
+// unwrapStrings unwraps a string or a list of values to a list of strings.
+func unwrapStrings(tv *typedvalues.TypedValue) ([]string, error) {
+	if tv == nil {
+		return nil, nil
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	switch t := i.(type) {
+	case string:
+		return []string{t}, nil
+	case []interface{}:
+		result := make([]string, len(t))
+		for k, v := range t {
+			result[k] = fmt.Sprintf("%v", v)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected a string or list, but was %s", tv.ValueType())
+	}
+}
+
+func jobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func jobSucceeded(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func jobFailureReason(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return strings.TrimSpace(c.Reason + " " + c.Message)
+		}
+	}
+	return ""
+}
+
+func containerTermination(pod *corev1.Pod) *corev1.ContainerStateTerminated {
+	if pod == nil {
+		return nil
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == containerName && status.State.Terminated != nil {
+			return status.State.Terminated
+		}
+	}
+	return nil
+}
+
+// isRegistryHost returns whether the first path segment of an image reference is a registry host, as opposed to the
+// namespace of the job.
+func isRegistryHost(segment string) bool {
+	return strings.ContainsAny(segment, ".:") || segment == "localhost"
+}
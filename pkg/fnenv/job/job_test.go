@@ -0,0 +1,195 @@
+package job
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestFunctionEnv_Resolve(t *testing.T) {
+	fe := New(fake.NewSimpleClientset(), Config{})
+
+	fnref, err := types.ParseFnRef("job://batch/busybox:1.31")
+	assert.NoError(t, err)
+	image, err := fe.Resolve(fnref)
+	assert.NoError(t, err)
+	assert.Equal(t, "busybox:1.31", image)
+
+	_, err = fe.Resolve(types.NewFnRef("", "", "busybox"))
+	assert.Equal(t, ErrUnsupportedRuntime, err)
+}
+
+func TestFunctionEnv_ParseFnRef(t *testing.T) {
+	fe := New(fake.NewSimpleClientset(), Config{})
+	for ref, expected := range map[string]types.FnRef{
+		"job://busybox":                      types.NewFnRef(Name, "", "busybox"),
+		"job://busybox:1.31":                 types.NewFnRef(Name, "", "busybox:1.31"),
+		"job:///busybox:1.31":                types.NewFnRef(Name, "", "busybox:1.31"),
+		"job://batch/busybox:1.31":           types.NewFnRef(Name, "batch", "busybox:1.31"),
+		"job:///tensorflow/tensorflow:2.3.0": types.NewFnRef(Name, "", "tensorflow/tensorflow:2.3.0"),
+		"job://gcr.io/x/y:1":                 types.NewFnRef(Name, "", "gcr.io/x/y:1"),
+		"job://localhost:5000/y":             types.NewFnRef(Name, "", "localhost:5000/y"),
+		"job://localhost/y@sha256:abc":       types.NewFnRef(Name, "", "localhost/y@sha256:abc"),
+		"job://batch/gcr.io/x/y:1":           types.NewFnRef(Name, "batch", "gcr.io/x/y:1"),
+	} {
+		fnref, err := fe.ParseFnRef(ref)
+		assert.NoError(t, err, ref)
+		assert.Equal(t, expected, fnref, ref)
+	}
+
+	for _, ref := range []string{"job://", "job://batch/", "fission://busybox"} {
+		_, err := fe.ParseFnRef(ref)
+		assert.Error(t, err, ref)
+	}
+
+	// The meta resolver parses job references using the function environment.
+	resolver := fnenv.NewMetaResolver(map[string]fnenv.RuntimeResolver{Name: fe}).InNamespace("team-a")
+	fnref, err := resolver.Resolve("job://busybox:1.31")
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewFnRef(Name, "team-a", "busybox:1.31"), fnref)
+}
+
+func TestFunctionEnv_CreateJobSpec(t *testing.T) {
+	fe := New(fake.NewSimpleClientset(), Config{ServiceAccount: "runner"})
+	job, err := fe.createJobSpec(&types.TaskInvocationSpec{
+		FnRef: &types.FnRef{Runtime: Name, ID: "busybox"},
+		Inputs: typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+			InputCommand:    "sh",
+			InputArgs:       []interface{}{"-c", "echo $FOO"},
+			InputEnv:        map[string]interface{}{"FOO": "bar", "BAR": 42},
+			types.InputMain: map[string]interface{}{"a": "b"},
+		}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultNamespace, job.Namespace)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	podSpec := job.Spec.Template.Spec
+	assert.Equal(t, "runner", podSpec.ServiceAccountName)
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	container := podSpec.Containers[0]
+	assert.Equal(t, "busybox", container.Image)
+	assert.Equal(t, []string{"sh"}, container.Command)
+	assert.Equal(t, []string{"-c", "echo $FOO"}, container.Args)
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "BAR", Value: "42"},
+		{Name: "FOO", Value: "bar"},
+		{Name: EnvInput, Value: `{"a":"b"}`},
+	}, container.Env)
+}
+
+func TestFunctionEnv_InvokeSucceeded(t *testing.T) {
+	client := newFakeClient()
+	fe := New(client, Config{Namespace: "batch", PollInterval: 10 * time.Millisecond})
+	fe.Logs = stubLogs(`{"result": 42}`)
+	go completeJob(t, client, "batch", batchv1.JobComplete, 0)
+
+	status, err := fe.Invoke(newSpec())
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	assert.Equal(t, map[string]interface{}{"result": float64(42)}, typedvalues.MustUnwrap(status.GetOutput()))
+
+	// The job should have been cleaned up
+	jobs, err := client.BatchV1().Jobs("batch").List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, jobs.Items)
+}
+
+func TestFunctionEnv_InvokeFailed(t *testing.T) {
+	client := newFakeClient()
+	fe := New(client, Config{Namespace: "batch", PollInterval: 10 * time.Millisecond, KeepJobs: true})
+	fe.Logs = stubLogs("something went wrong")
+	go completeJob(t, client, "batch", batchv1.JobFailed, 2)
+
+	status, err := fe.Invoke(newSpec())
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, status.GetStatus())
+	assert.Contains(t, status.GetError().GetMessage(), "exit code 2")
+	assert.Contains(t, status.GetError().GetMessage(), "something went wrong")
+
+	jobs, err := client.BatchV1().Jobs("batch").List(metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, jobs.Items, 1)
+}
+
+// newFakeClient returns a fake clientset of which the watches do not receive events. The watchers of the fake tracker
+// panic on events after being stopped, so the tests rely on polling instead.
+func newFakeClient() *fake.Clientset {
+	client := fake.NewSimpleClientset()
+	client.PrependWatchReactor("jobs", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		return true, watch.NewFake(), nil
+	})
+	return client
+}
+
+func newSpec() *types.TaskInvocationSpec {
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+	return &types.TaskInvocationSpec{
+		FnRef:        &types.FnRef{Runtime: Name, ID: "busybox"},
+		InvocationId: "wi-123",
+		Task: &types.Task{
+			Metadata: &types.ObjectMetadata{Id: "task"},
+		},
+		Deadline: deadline,
+	}
+}
+
+func stubLogs(logs string) LogReader {
+	return func(namespace, pod string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(logs)), nil
+	}
+}
+
+// completeJob simulates the job controller by finishing the first job that is created in the namespace.
+func completeJob(t *testing.T, client kubernetes.Interface, namespace string, condition batchv1.JobConditionType,
+	exitCode int32) {
+	jobs := client.BatchV1().Jobs(namespace)
+	var job *batchv1.Job
+	for job == nil {
+		list, err := jobs.List(metav1.ListOptions{})
+		assert.NoError(t, err)
+		if len(list.Items) == 0 {
+			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+		job = &list.Items[0]
+	}
+
+	_, err := client.CoreV1().Pods(namespace).Create(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name + "-pod",
+			Namespace: namespace,
+			Labels:    map[string]string{labelJobName: job.Name},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name: containerName,
+				State: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode},
+				},
+			}},
+		},
+	})
+	assert.NoError(t, err)
+
+	job.Status.Conditions = append(job.Status.Conditions, batchv1.JobCondition{
+		Type:   condition,
+		Status: corev1.ConditionTrue,
+	})
+	_, err = jobs.UpdateStatus(job)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,208 @@
+// Package keyref resolves the references to keys of Kubernetes secrets and configmaps in the inputs of tasks.
+//
+// Workflows reference secrets - such as API tokens - rather than containing them. The references are stored in the
+// event store as is; they are only resolved right before a function is invoked, by wrapping the runtime of the
+// function in a Runtime.
+package keyref
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	DefaultNamespace = "default"
+)
+
+var log = logrus.WithField("component", "fnenv.keyref")
+
+// SecretStore reads the keys of secrets.
+type SecretStore interface {
+	// GetSecret returns the value of the key of the secret in the namespace.
+	GetSecret(namespace, name, key string) ([]byte, error)
+}
+
+// KubernetesSecrets reads the keys of Kubernetes secrets.
+type KubernetesSecrets struct {
+	Client kubernetes.Interface
+}
+
+func (ks *KubernetesSecrets) GetSecret(namespace, name, key string) ([]byte, error) {
+	secret, err := ks.Client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key '%s'", namespace, name, key)
+	}
+	return data, nil
+}
+
+// Resolver reads the values of key references from the secret store and the Kubernetes configmaps.
+type Resolver struct {
+	client    kubernetes.Interface
+	secrets   SecretStore
+	namespace string
+}
+
+// NewResolver creates a resolver that resolves references to the Kubernetes secrets and configmaps in the namespace,
+// unless the function is scoped to another namespace.
+func NewResolver(client kubernetes.Interface, namespace string) *Resolver {
+	return NewResolverWithSecrets(client, &KubernetesSecrets{Client: client}, namespace)
+}
+
+// NewResolverWithSecrets creates a resolver that reads secrets from the secret store, such as Vault, instead of from
+// Kubernetes. If the client is nil, references to configmaps cannot be resolved.
+func NewResolverWithSecrets(client kubernetes.Interface, secrets SecretStore, namespace string) *Resolver {
+	if len(namespace) == 0 {
+		namespace = DefaultNamespace
+	}
+	return &Resolver{
+		client:    client,
+		secrets:   secrets,
+		namespace: namespace,
+	}
+}
+
+// Resolve reads the value of the key of the referenced secret or configmap in the namespace. Text values are
+// resolved to strings, and binary values to bytes.
+func (r *Resolver) Resolve(namespace string, ref *typedvalues.KeyRef) (*typedvalues.TypedValue, error) {
+	switch ref.GetKind() {
+	case typedvalues.KeyRefSecret:
+		data, err := r.secrets.GetSecret(namespace, ref.GetName(), ref.GetKey())
+		if err != nil {
+			return nil, err
+		}
+		if utf8.Valid(data) {
+			return typedvalues.Wrap(string(data))
+		}
+		return typedvalues.Wrap(data)
+	case typedvalues.KeyRefConfigMap:
+		if r.client == nil {
+			return nil, fmt.Errorf("cannot resolve configmap %s/%s without Kubernetes", namespace, ref.GetName())
+		}
+		cm, err := r.client.CoreV1().ConfigMaps(namespace).Get(ref.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if data, ok := cm.Data[ref.GetKey()]; ok {
+			return typedvalues.Wrap(data)
+		}
+		if data, ok := cm.BinaryData[ref.GetKey()]; ok {
+			return typedvalues.Wrap(data)
+		}
+		return nil, fmt.Errorf("configmap %s/%s has no key '%s'", namespace, ref.GetName(), ref.GetKey())
+	default:
+		return nil, fmt.Errorf("unknown kind of key reference: '%s'", ref.GetKind())
+	}
+}
+
+// Runtime resolves the key references in the inputs of a task before invoking the task with the wrapped runtime.
+// The resolved values are only passed to the function; the spec of the task invocation is not modified. The resolved
+// secrets are marked as fnenv.Sensitive, so that the wrapped runtime redacts them from its logs and traces.
+//
+// Only runtimes that invoke functions outside of the workflow engine should be wrapped. Runtimes that pass their
+// inputs back into the workflow engine, such as the workflows runtime and the control flow functions of the internal
+// runtime, would otherwise store the resolved values in the event store.
+type Runtime struct {
+	runtime  fnenv.Runtime
+	resolver *Resolver
+}
+
+func Wrap(runtime fnenv.Runtime, resolver *Resolver) *Runtime {
+	return &Runtime{
+		runtime:  runtime,
+		resolver: resolver,
+	}
+}
+
+// Invoke resolves the key references in the inputs of the task, and invokes the task with the resolved inputs.
+//
+// For runtimes that are NamespaceScoped, references are resolved in the namespace of the function reference if it
+// has one. Otherwise, the references are resolved in the namespace of the resolver.
+func (rt *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	namespace := rt.resolver.namespace
+	if scoped, ok := rt.runtime.(fnenv.NamespaceScoped); ok && scoped.NamespaceScoped() {
+		if ns := spec.GetFnRef().GetNamespace(); len(ns) > 0 {
+			namespace = ns
+		}
+	}
+
+	var inputs map[string]*typedvalues.TypedValue
+	var sensitive []string
+	for k, v := range spec.GetInputs() {
+		resolved, err := typedvalues.ResolveKeyRefs(v, func(ref *typedvalues.KeyRef) (*typedvalues.TypedValue,
+			error) {
+			tv, err := rt.resolver.Resolve(namespace, ref)
+			if err == nil && ref.GetKind() == typedvalues.KeyRefSecret {
+				sensitive = append(sensitive, sensitiveValue(tv))
+			}
+			return tv, err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve input '%s': %v", k, err)
+		}
+		if resolved == v {
+			continue
+		}
+		if inputs == nil {
+			inputs = make(map[string]*typedvalues.TypedValue, len(spec.GetInputs()))
+			for k, v := range spec.GetInputs() {
+				inputs[k] = v
+			}
+		}
+		inputs[k] = resolved
+	}
+	if inputs != nil {
+		log.WithField("fn", spec.GetFnRef()).Debugf("Resolved key references in the inputs of task %s",
+			spec.GetTaskId())
+		spec = proto.Clone(spec).(*types.TaskInvocationSpec)
+		spec.Inputs = inputs
+	}
+	if len(sensitive) == 0 {
+		return rt.runtime.Invoke(spec, opts...)
+	}
+
+	// Errors end up in the events of the invocation, so keep the secrets out of them.
+	cfg := &fnenv.InvokeConfig{Sensitive: sensitive}
+	status, err := rt.runtime.Invoke(spec, append(opts, fnenv.Sensitive(sensitive...))...)
+	if err != nil {
+		return status, errors.New(cfg.Redact(err.Error()))
+	}
+	if status.GetError() != nil {
+		status.Error.Message = cfg.Redact(status.Error.Message)
+	}
+	return status, nil
+}
+
+// sensitiveValue returns the value of the resolved secret as it appears in requests.
+func sensitiveValue(tv *typedvalues.TypedValue) string {
+	switch v := typedvalues.MustUnwrap(tv).(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+// Prepare forwards the signal to the wrapped runtime, if it is a fnenv.Preparer.
+func (rt *Runtime) Prepare(fn types.FnRef, expectedAt time.Time) error {
+	if preparer, ok := rt.runtime.(fnenv.Preparer); ok {
+		return preparer.Prepare(fn, expectedAt)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package keyref
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/fnenv/mock"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestResolver() *Resolver {
+	return NewResolver(fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-api", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "team-a"},
+			Data:       map[string]string{"host": "api.example.com"},
+		},
+	), "")
+}
+
+func TestResolver_Resolve(t *testing.T) {
+	resolver := newTestResolver()
+
+	tv, err := resolver.Resolve("default", &typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-api",
+		Key: "token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", typedvalues.MustUnwrap(tv))
+
+	tv, err = resolver.Resolve("team-a", &typedvalues.KeyRef{Kind: typedvalues.KeyRefConfigMap, Name: "config",
+		Key: "host"})
+	assert.NoError(t, err)
+	assert.Equal(t, "api.example.com", typedvalues.MustUnwrap(tv))
+
+	_, err = resolver.Resolve("default", &typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-api",
+		Key: "password"})
+	assert.Error(t, err)
+
+	_, err = resolver.Resolve("default", &typedvalues.KeyRef{Kind: typedvalues.KeyRefConfigMap, Name: "config",
+		Key: "host"})
+	assert.Error(t, err)
+}
+
+func TestRuntime_Invoke(t *testing.T) {
+	var received map[string]*typedvalues.TypedValue
+	runtime := mock.NewRuntime()
+	runtime.Functions["fn"] = func(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+		received = spec.GetInputs()
+		return nil, nil
+	}
+
+	spec := &types.TaskInvocationSpec{
+		FnRef: &types.FnRef{Runtime: "mock", ID: "fn"},
+		Inputs: map[string]*typedvalues.TypedValue{
+			types.InputBody: typedvalues.MustWrap("foo"),
+			types.InputHeaders: typedvalues.MustWrap(map[string]interface{}{
+				"Authorization": typedvalues.MustWrap(&typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret,
+					Name: "my-api", Key: "token"}),
+			}),
+		},
+	}
+	status, err := Wrap(runtime, newTestResolver()).Invoke(spec)
+	assert.NoError(t, err)
+	assert.True(t, status.Successful())
+	unwrapped, err := typedvalues.UnwrapMapTypedValue(received)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		types.InputBody:    "foo",
+		types.InputHeaders: map[string]interface{}{"Authorization": "s3cr3t"},
+	}, unwrapped)
+
+	// The spec of the task invocation should still contain the reference
+	headers, err := typedvalues.UnwrapMap(spec.Inputs[types.InputHeaders])
+	assert.NoError(t, err)
+	assert.IsType(t, &typedvalues.KeyRef{}, headers["Authorization"])
+}
+
+// recordingRuntime records the invoke options that it was called with.
+type recordingRuntime struct {
+	cfg *fnenv.InvokeConfig
+	err string
+}
+
+func (rt *recordingRuntime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (
+	*types.TaskInvocationStatus, error) {
+	rt.cfg = fnenv.ParseInvokeOptions(opts)
+	return &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_FAILED,
+		Error:  &types.Error{Message: rt.err},
+	}, nil
+}
+
+func TestRuntime_InvokeSensitive(t *testing.T) {
+	runtime := &recordingRuntime{err: "unauthorized token: s3cr3t"}
+	spec := &types.TaskInvocationSpec{
+		FnRef: &types.FnRef{Runtime: "mock", ID: "fn"},
+		Inputs: map[string]*typedvalues.TypedValue{
+			"token": typedvalues.MustWrap(&typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-api",
+				Key: "token"}),
+			"host": typedvalues.MustWrap(&typedvalues.KeyRef{Kind: typedvalues.KeyRefConfigMap, Name: "config",
+				Key: "host"}),
+		},
+	}
+	resolver := newTestResolver()
+	resolver.namespace = "team-a"
+	resolver.secrets = &KubernetesSecrets{Client: fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-api", Namespace: "team-a"},
+		Data:       map[string][]byte{"token": []byte("s3cr3t")},
+	})}
+
+	status, err := Wrap(runtime, resolver).Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"s3cr3t"}, runtime.cfg.Sensitive)
+	assert.Equal(t, "https://api.example.com/?token="+fnenv.Redacted,
+		runtime.cfg.Redact("https://api.example.com/?token=s3cr3t"))
+	assert.Equal(t, "unauthorized token: "+fnenv.Redacted, status.GetError().GetMessage())
+}
@@ -0,0 +1,106 @@
+package keyref
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVaultMount     = "secret"
+	defaultVaultKVVersion = 2
+	vaultTokenHeader      = "X-Vault-Token"
+)
+
+// VaultConfig configures the access to the key/value secrets engine of a Vault server.
+type VaultConfig struct {
+	// Addr is the address of the Vault server, such as https://vault:8200.
+	Addr string
+
+	// Token is the Vault token used to read the secrets.
+	Token string
+
+	// Mount is the path at which the key/value secrets engine is mounted. Defaults to "secret".
+	Mount string
+
+	// KVVersion is the version (1 or 2) of the key/value secrets engine. Defaults to 2.
+	KVVersion int
+}
+
+// VaultSecrets reads the keys of secrets from the key/value secrets engine of Vault.
+//
+// The namespace of the secret reference is used as a prefix of the path of the secret in Vault, so the reference
+// "payments/stripe" of a function in the namespace "default" reads the secret at "<mount>/default/payments".
+type VaultSecrets struct {
+	config VaultConfig
+	client *http.Client
+}
+
+func NewVaultSecrets(config VaultConfig) (*VaultSecrets, error) {
+	if len(config.Addr) == 0 {
+		return nil, errors.New("no Vault address specified")
+	}
+	if len(config.Mount) == 0 {
+		config.Mount = defaultVaultMount
+	}
+	if config.KVVersion == 0 {
+		config.KVVersion = defaultVaultKVVersion
+	}
+	if config.KVVersion != 1 && config.KVVersion != 2 {
+		return nil, fmt.Errorf("unsupported Vault KV version: %d", config.KVVersion)
+	}
+	return &VaultSecrets{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (vs *VaultSecrets) GetSecret(namespace, name, key string) ([]byte, error) {
+	path := strings.Trim(vs.config.Mount, "/") + "/"
+	if vs.config.KVVersion == 2 {
+		path += "data/"
+	}
+	path += namespace + "/" + name
+
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(vs.config.Addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(vaultTokenHeader, vs.config.Token)
+	resp, err := vs.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault secret %s/%s: %v", namespace, name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// The body is not included, as Vault could echo parts of the request.
+		ioutil.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to read Vault secret %s/%s: %s", namespace, name, resp.Status)
+	}
+
+	// KV version 1 returns the fields in data; version 2 nests them in data.data, next to the metadata.
+	body := struct {
+		Data map[string]interface{} `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse Vault secret %s/%s: %v", namespace, name, err)
+	}
+	data := body.Data
+	if vs.config.KVVersion == 2 {
+		data, _ = body.Data["data"].(map[string]interface{})
+	}
+	value, ok := data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s has no key '%s'", namespace, name, key)
+	}
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	default:
+		return json.Marshal(v)
+	}
+}
@@ -0,0 +1,85 @@
+package keyref
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupVault(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(vaultTokenHeader) != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var data map[string]interface{}
+		switch r.URL.Path {
+		case "/v1/secret/data/default/my-api":
+			data = map[string]interface{}{
+				"data":     map[string]interface{}{"token": "s3cr3t"},
+				"metadata": map[string]interface{}{"version": 1},
+			}
+		case "/v1/kv/default/my-api":
+			data = map[string]interface{}{"token": "v1-s3cr3t", "port": 8080}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestVaultSecrets(t *testing.T) {
+	vault := setupVault(t)
+	defer vault.Close()
+
+	secrets, err := NewVaultSecrets(VaultConfig{Addr: vault.URL, Token: "root"})
+	require.NoError(t, err)
+	value, err := secrets.GetSecret("default", "my-api", "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(value))
+
+	_, err = secrets.GetSecret("default", "my-api", "password")
+	assert.Error(t, err)
+	_, err = secrets.GetSecret("team-a", "my-api", "token")
+	assert.Error(t, err)
+
+	secrets, err = NewVaultSecrets(VaultConfig{Addr: vault.URL, Token: "root", Mount: "kv", KVVersion: 1})
+	require.NoError(t, err)
+	value, err = secrets.GetSecret("default", "my-api", "token")
+	assert.NoError(t, err)
+	assert.Equal(t, "v1-s3cr3t", string(value))
+	value, err = secrets.GetSecret("default", "my-api", "port")
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", string(value))
+
+	secrets, err = NewVaultSecrets(VaultConfig{Addr: vault.URL, Token: "wrong"})
+	require.NoError(t, err)
+	_, err = secrets.GetSecret("default", "my-api", "token")
+	assert.Error(t, err)
+
+	_, err = NewVaultSecrets(VaultConfig{Addr: vault.URL, KVVersion: 3})
+	assert.Error(t, err)
+}
+
+func TestResolverWithVault(t *testing.T) {
+	vault := setupVault(t)
+	defer vault.Close()
+	secrets, err := NewVaultSecrets(VaultConfig{Addr: vault.URL, Token: "root"})
+	require.NoError(t, err)
+
+	resolver := NewResolverWithSecrets(nil, secrets, "")
+	tv, err := resolver.Resolve("default", &typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-api",
+		Key: "token"})
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cr3t", typedvalues.MustUnwrap(tv))
+
+	_, err = resolver.Resolve("default", &typedvalues.KeyRef{Kind: typedvalues.KeyRefConfigMap, Name: "config",
+		Key: "host"})
+	assert.Error(t, err)
+}
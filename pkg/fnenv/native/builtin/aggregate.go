@@ -0,0 +1,181 @@
+package builtin
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	Aggregate                = "aggregate"
+	AggregateInput           = "aggregate"
+	AggregateInputOperation  = "operation"
+	AggregateOperationConcat = "concat"
+	AggregateOperationMerge  = "merge"
+	AggregateOperationSum    = "sum"
+	AggregateOperationAvg    = "avg"
+	AggregateOperationMin    = "min"
+	AggregateOperationMax    = "max"
+	AggregateOperationCount  = "count"
+)
+
+/*
+FunctionAggregate merges a list of values, such as the outputs of a dynamic fan-out, into a single value.
+It is intended to be used as the fan-in of a `foreach` or as the reducer of a `mapreduce`; when used as the reducer, the
+list of mapped outputs is read from `_items` if the `aggregate` input is not set.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+aggregate       | yes      | list              | The list of values to aggregate.
+operation       | no       | string            | The aggregation to perform (see below). (default: concat)
+
+The following operations are supported:
+- `concat`: concatenates the lists into a single list. Values that are not lists are added as a single element.
+- `merge`: merges the maps into a single map. For duplicate keys, the value of the last map is used.
+- `sum`, `avg`, `min`, `max`: computes the respective statistic of the numbers.
+- `count`: counts the number of values.
+
+Nil values, such as the outputs of tasks without output, are ignored by all operations.
+
+**output** (*) The aggregated value.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: aggregate
+  inputs:
+    aggregate:
+    - [1, 2]
+    - [3]
+    operation: concat
+# ...
+```
+
+A complete example of this function can be found in the [mapreducewhale](../examples/whales/mapreducewhale.wf.yaml)
+example.
+*/
+type FunctionAggregate struct{}
+
+func (fn *FunctionAggregate) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, itemsTv := getFirstDefinedTypedValue(spec.GetInputs(), AggregateInput, MapReduceInputItems, types.InputMain)
+	if itemsTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", AggregateInput)
+	}
+	i, err := typedvalues.Unwrap(itemsTv)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("input '%s' needs to be a 'array', but was '%v'", AggregateInput, itemsTv.ValueType())
+	}
+	var items []interface{}
+	for _, item := range list {
+		if item != nil {
+			items = append(items, item)
+		}
+	}
+
+	operation := AggregateOperationConcat
+	if operationTv, ok := spec.GetInputs()[AggregateInputOperation]; ok {
+		operation, err = typedvalues.UnwrapString(operationTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format operation to a string: %v", err)
+		}
+	}
+
+	switch operation {
+	case AggregateOperationConcat:
+		result := []interface{}{}
+		for _, item := range items {
+			if l, ok := item.([]interface{}); ok {
+				result = append(result, l...)
+			} else {
+				result = append(result, item)
+			}
+		}
+		return typedvalues.Wrap(result)
+	case AggregateOperationMerge:
+		result := map[string]interface{}{}
+		for k, item := range items {
+			mp, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot merge element %d: expected a map, but was %T", k, item)
+			}
+			for key, val := range mp {
+				result[key] = val
+			}
+		}
+		return typedvalues.Wrap(result)
+	case AggregateOperationCount:
+		return typedvalues.Wrap(int64(len(items)))
+	case AggregateOperationSum, AggregateOperationAvg, AggregateOperationMin, AggregateOperationMax:
+		return aggregateNumbers(operation, items)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", operation)
+	}
+}
+
+// aggregateNumbers computes the numeric aggregation of the items. If all items are integers, the sum, min, and max are
+// returned as an integer.
+func aggregateNumbers(operation string, items []interface{}) (*typedvalues.TypedValue, error) {
+	if len(items) == 0 {
+		if operation == AggregateOperationSum {
+			return typedvalues.Wrap(int64(0))
+		}
+		return nil, fmt.Errorf("cannot compute %s of an empty list", operation)
+	}
+
+	isInt := true
+	numbers := make([]float64, len(items))
+	for k, item := range items {
+		switch n := item.(type) {
+		case int32:
+			numbers[k] = float64(n)
+		case int64:
+			numbers[k] = float64(n)
+		case float32:
+			numbers[k] = float64(n)
+			isInt = false
+		case float64:
+			numbers[k] = n
+			isInt = false
+		default:
+			return nil, fmt.Errorf("cannot compute %s of element %d: expected a number, but was %T", operation, k,
+				item)
+		}
+	}
+
+	var result float64
+	switch operation {
+	case AggregateOperationSum:
+		for _, n := range numbers {
+			result += n
+		}
+	case AggregateOperationAvg:
+		for _, n := range numbers {
+			result += n
+		}
+		return typedvalues.Wrap(result / float64(len(numbers)))
+	case AggregateOperationMin:
+		result = math.Inf(1)
+		for _, n := range numbers {
+			result = math.Min(result, n)
+		}
+	case AggregateOperationMax:
+		result = math.Inf(-1)
+		for _, n := range numbers {
+			result = math.Max(result, n)
+		}
+	}
+	if isInt {
+		return typedvalues.Wrap(int64(result))
+	}
+	return typedvalues.Wrap(result)
+}
@@ -0,0 +1,58 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionAggregate_Invoke(t *testing.T) {
+	testCases := []struct {
+		operation string
+		items     []interface{}
+		expected  interface{}
+	}{
+		{AggregateOperationConcat, []interface{}{[]interface{}{1, 2}, nil, []interface{}{3}, "foo"},
+			[]interface{}{int32(1), int32(2), int32(3), "foo"}},
+		{AggregateOperationMerge, []interface{}{map[string]interface{}{"a": "b", "c": "d"}, map[string]interface{}{"c": "e"}},
+			map[string]interface{}{"a": "b", "c": "e"}},
+		{AggregateOperationSum, []interface{}{1, 2, 3}, int64(6)},
+		{AggregateOperationSum, []interface{}{1, 2.5}, 3.5},
+		{AggregateOperationSum, []interface{}{}, int64(0)},
+		{AggregateOperationAvg, []interface{}{1, 2}, 1.5},
+		{AggregateOperationMin, []interface{}{3, -1, 2}, int64(-1)},
+		{AggregateOperationMax, []interface{}{3, -1, 2.5}, 3.0},
+		{AggregateOperationCount, []interface{}{"a", nil, "b"}, int64(2)},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.operation, func(t *testing.T) {
+			internalFunctionTest(t, &FunctionAggregate{}, &types.TaskInvocationSpec{
+				Inputs: map[string]*typedvalues.TypedValue{
+					AggregateInput:          typedvalues.MustWrap(testCase.items),
+					AggregateInputOperation: typedvalues.MustWrap(testCase.operation),
+				},
+			}, testCase.expected)
+		})
+	}
+}
+
+func TestFunctionAggregate_InvokeAsReducer(t *testing.T) {
+	internalFunctionTest(t, &FunctionAggregate{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			MapReduceInputItems:     typedvalues.MustWrap([]interface{}{1, 2, 3}),
+			AggregateInputOperation: typedvalues.MustWrap(AggregateOperationSum),
+		},
+	}, int64(6))
+}
+
+func TestFunctionAggregate_InvokeInvalid(t *testing.T) {
+	_, err := (&FunctionAggregate{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			AggregateInput:          typedvalues.MustWrap([]interface{}{1, "foo"}),
+			AggregateInputOperation: typedvalues.MustWrap(AggregateOperationSum),
+		},
+	})
+	assert.Error(t, err)
+}
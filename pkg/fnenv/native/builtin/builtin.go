@@ -13,18 +13,33 @@ import (
 )
 
 var DefaultBuiltinFunctions = map[string]native.InternalFunction{
-	If:         &FunctionIf{},
-	Noop:       &FunctionNoop{},
-	"nop":      &FunctionNoop{}, // nop is an alias for 'noop'
-	Compose:    &FunctionCompose{},
-	Sleep:      &FunctionSleep{},
-	Repeat:     &FunctionRepeat{},
-	Javascript: NewFunctionJavascript(),
-	Fail:       &FunctionFail{},
-	Http:       NewFunctionHTTP(),
-	Foreach:    &FunctionForeach{},
-	Switch:     &FunctionSwitch{},
-	While:      &FunctionWhile{},
+	If:           &FunctionIf{},
+	Noop:         &FunctionNoop{},
+	"nop":        &FunctionNoop{}, // nop is an alias for 'noop'
+	Compose:      &FunctionCompose{},
+	Sleep:        &FunctionSleep{},
+	Repeat:       &FunctionRepeat{},
+	Javascript:   NewFunctionJavascript(),
+	Fail:         &FunctionFail{},
+	Http:         NewFunctionHTTP(),
+	Foreach:      &FunctionForeach{},
+	MapReduce:    &FunctionMapReduce{},
+	Aggregate:    &FunctionAggregate{},
+	Regex:        NewFunctionRegex(),
+	Transform:    &FunctionTransform{},
+	Switch:       &FunctionSwitch{},
+	While:        &FunctionWhile{},
+	Base64:       &FunctionBase64{},
+	URLEncode:    &FunctionURLEncode{},
+	Gzip:         &FunctionGzip{},
+	Hash:         &FunctionHash{},
+	UUID:         &FunctionUUID{},
+	RandomInt:    &FunctionRandomInt{},
+	RandomChoice: &FunctionRandomChoice{},
+	TimeNow:      &FunctionTimeNow{},
+	TimeFormat:   &FunctionTimeFormat{},
+	TimeAdd:      &FunctionTimeAdd{},
+	TimeDuration: &FunctionTimeDuration{},
 }
 
 // ensureInput verifies that the input for the given key exists and is of one of the provided types.
@@ -0,0 +1,217 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	Emit                    = "emit"
+	EmitInputData           = "data"
+	EmitInputWebhook        = "webhook"
+	EmitInputSubject        = "subject"
+	EmitInputType           = "type"
+	EmitInputSource         = "source"
+	EmitDefaultType         = "io.fission.workflows.emit"
+	emitSpecVersion         = "0.2"
+	emitContentType         = "application/json"
+	emitWebhookContentType  = "application/cloudevents+json"
+	emitDefaultTimeout      = 10 * time.Second
+	emitDefaultSourcePrefix = "/fission-workflows/invocations/"
+)
+
+var ErrEmitNoPublisher = errors.New("no NATS connection is configured to publish events to")
+
+// EventPublisher publishes raw messages to a subject, which is implemented by a NATS connection.
+type EventPublisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// CloudEvent is the JSON (structured mode) representation of an event according to the CloudEvents 0.2 specification.
+type CloudEvent struct {
+	SpecVersion string      `json:"specversion"`
+	Type        string      `json:"type"`
+	Source      string      `json:"source"`
+	ID          string      `json:"id"`
+	Time        string      `json:"time"`
+	ContentType string      `json:"contenttype"`
+	Data        interface{} `json:"data,omitempty"`
+}
+
+/*
+FunctionEmit publishes an event to an external sink, without waiting for the workflow to complete.
+It is useful for notifying external systems of the progress of a workflow. The event is formatted as a JSON CloudEvent.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+data/default    | no       | *                 | The data of the event.
+webhook         | no       | string            | The URL to POST the event to.
+subject         | no       | string            | The NATS subject to publish the event to.
+type            | no       | string            | The type of the event. (default: io.fission.workflows.emit)
+source          | no       | string            | The source of the event. (default: /fission-workflows/invocations/<invocationID>)
+
+Either webhook or subject (or both) needs to be provided. Publishing to a NATS subject is only possible if the
+workflow engine is configured to use NATS.
+
+**output** (map) The event that was published.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: emit
+  inputs:
+    webhook: http://example.com/progress
+    type: com.example.progress
+    data:
+      step: 2
+      of: 3
+# ...
+```
+
+A complete example of this function can be found in the [emitwhale](../examples/whales/emitwhale.wf.yaml) example.
+*/
+type FunctionEmit struct {
+	client    *http.Client
+	publisher EventPublisher
+}
+
+// NewFunctionEmit creates the emit function. The publisher is optional; if it is nil, events can only be emitted to
+// webhooks.
+func NewFunctionEmit(publisher EventPublisher) *FunctionEmit {
+	return &FunctionEmit{
+		client:    &http.Client{},
+		publisher: publisher,
+	}
+}
+
+func (fn *FunctionEmit) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	webhook, err := unwrapOptionalString(spec.GetInputs(), EmitInputWebhook)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := unwrapOptionalString(spec.GetInputs(), EmitInputSubject)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhook) == 0 && len(subject) == 0 {
+		return nil, fmt.Errorf("either input '%s' or '%s' needs to be set", EmitInputWebhook, EmitInputSubject)
+	}
+	if len(subject) > 0 && fn.publisher == nil {
+		return nil, ErrEmitNoPublisher
+	}
+
+	event, err := fn.createEvent(spec)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %v", err)
+	}
+
+	if len(webhook) > 0 {
+		if err := fn.postWebhook(spec, webhook, payload); err != nil {
+			return nil, err
+		}
+	}
+	if len(subject) > 0 {
+		if err := fn.publisher.Publish(subject, payload); err != nil {
+			return nil, fmt.Errorf("failed to publish event to subject '%s': %v", subject, err)
+		}
+	}
+
+	output := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &output); err != nil {
+		return nil, err
+	}
+	return typedvalues.Wrap(output)
+}
+
+func (fn *FunctionEmit) createEvent(spec *types.TaskInvocationSpec) (*CloudEvent, error) {
+	eventType, err := unwrapOptionalString(spec.GetInputs(), EmitInputType)
+	if err != nil {
+		return nil, err
+	}
+	if len(eventType) == 0 {
+		eventType = EmitDefaultType
+	}
+	source, err := unwrapOptionalString(spec.GetInputs(), EmitInputSource)
+	if err != nil {
+		return nil, err
+	}
+	if len(source) == 0 {
+		source = emitDefaultSourcePrefix + spec.GetInvocationId()
+	}
+	var data interface{}
+	if _, dataTv := getFirstDefinedTypedValue(spec.GetInputs(), EmitInputData, types.InputMain); dataTv != nil {
+		data, err = typedvalues.Unwrap(dataTv)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if bs, ok := data.([]byte); ok {
+		data = string(bs)
+	}
+	return &CloudEvent{
+		SpecVersion: emitSpecVersion,
+		Type:        eventType,
+		Source:      source,
+		ID:          uuid.NewV4().String(),
+		Time:        time.Now().UTC().Format(time.RFC3339Nano),
+		ContentType: emitContentType,
+		Data:        data,
+	}, nil
+}
+
+func (fn *FunctionEmit) postWebhook(spec *types.TaskInvocationSpec, url string, payload []byte) error {
+	deadline := time.Now().Add(emitDefaultTimeout)
+	if taskDeadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil && taskDeadline.Before(deadline) {
+		deadline = taskDeadline
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook: %v", err)
+	}
+	req.Header.Set("Content-Type", emitWebhookContentType)
+	resp, err := fn.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to post event to webhook: %v", err)
+	}
+	defer resp.Body.Close()
+	ioutil.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// unwrapOptionalString returns the string value of the input, or an empty string if the input is not set.
+func unwrapOptionalString(inputs map[string]*typedvalues.TypedValue, key string) (string, error) {
+	tv, ok := inputs[key]
+	if !ok {
+		return "", nil
+	}
+	s, err := typedvalues.UnwrapString(tv)
+	if err != nil {
+		return "", fmt.Errorf("failed to format %s to a string: %v", key, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,81 @@
+package builtin
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockEventPublisher struct {
+	published map[string][]byte
+}
+
+func (p *mockEventPublisher) Publish(subject string, data []byte) error {
+	p.published[subject] = data
+	return nil
+}
+
+func TestFunctionEmit_Invoke(t *testing.T) {
+	var received CloudEvent
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, emitWebhookContentType, r.Header.Get("Content-Type"))
+		data, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NoError(t, json.Unmarshal(data, &received))
+	}))
+	defer ts.Close()
+	publisher := &mockEventPublisher{published: map[string][]byte{}}
+
+	out, err := NewFunctionEmit(publisher).Invoke(&types.TaskInvocationSpec{
+		InvocationId: "wi-123",
+		Inputs: map[string]*typedvalues.TypedValue{
+			EmitInputWebhook: typedvalues.MustWrap(ts.URL),
+			EmitInputSubject: typedvalues.MustWrap("progress"),
+			EmitInputType:    typedvalues.MustWrap("com.example.progress"),
+			types.InputMain:  typedvalues.MustWrap(map[string]interface{}{"step": 2}),
+		},
+	})
+	assert.NoError(t, err)
+	event := typedvalues.MustUnwrap(out).(map[string]interface{})
+	assert.Equal(t, "com.example.progress", event["type"])
+	assert.Equal(t, emitDefaultSourcePrefix+"wi-123", event["source"])
+	assert.Equal(t, event["id"], received.ID)
+	assert.Equal(t, map[string]interface{}{"step": float64(2)}, received.Data)
+
+	var published CloudEvent
+	assert.NoError(t, json.Unmarshal(publisher.published["progress"], &published))
+	assert.Equal(t, received, published)
+}
+
+func TestFunctionEmit_InvokeWebhookError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer ts.Close()
+
+	_, err := NewFunctionEmit(nil).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			EmitInputWebhook: typedvalues.MustWrap(ts.URL),
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestFunctionEmit_InvokeNoPublisher(t *testing.T) {
+	_, err := NewFunctionEmit(nil).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			EmitInputSubject: typedvalues.MustWrap("progress"),
+		},
+	})
+	assert.Equal(t, ErrEmitNoPublisher, err)
+
+	_, err = NewFunctionEmit(nil).Invoke(&types.TaskInvocationSpec{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package builtin
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"unicode/utf8"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	Base64                      = "base64"
+	URLEncode                   = "urlencode"
+	Gzip                        = "gzip"
+	EncodingInput               = types.InputMain
+	EncodingInputOperation      = "operation"
+	EncodingOperationEncode     = "encode"
+	EncodingOperationDecode     = "decode"
+	EncodingOperationCompress   = "compress"
+	EncodingOperationDecompress = "decompress"
+)
+
+/*
+FunctionBase64 encodes the input to, or decodes the input from, standard base64.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+default         | yes      | *                 | The value to encode or decode.
+operation       | no       | string            | Either `encode` or `decode`. (default: encode)
+
+Values other than strings and bytes are encoded as JSON before being encoded.
+
+**output** (string/bytes) The encoded string, or the decoded value. The decoded value is a string if it is valid UTF-8.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: base64
+  inputs: "hello world"
+# ...
+```
+
+A complete example of this function can be found in the [encodingwhale](../examples/whales/encodingwhale.wf.yaml)
+example.
+*/
+type FunctionBase64 struct{}
+
+func (fn *FunctionBase64) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	data, operation, err := parseEncodingInputs(spec, EncodingOperationEncode)
+	if err != nil {
+		return nil, err
+	}
+	switch operation {
+	case EncodingOperationEncode:
+		return typedvalues.Wrap(base64.StdEncoding.EncodeToString(data))
+	case EncodingOperationDecode:
+		decoded, err := base64.StdEncoding.DecodeString(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode base64: %v", err)
+		}
+		return wrapBytes(decoded)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", operation)
+	}
+}
+
+/*
+FunctionURLEncode escapes the input so it can be safely placed in a URL query.
+If the input is a map, it is encoded as URL query parameters (e.g. `a=b&c=d`).
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+default         | yes      | string/map        | The value to encode or decode.
+operation       | no       | string            | Either `encode` or `decode`. (default: encode)
+
+**output** (string) The encoded or decoded string.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: urlencode
+  inputs:
+    default:
+      q: "fission workflows"
+      page: 2
+# ...
+```
+
+A complete example of this function can be found in the [encodingwhale](../examples/whales/encodingwhale.wf.yaml)
+example.
+*/
+type FunctionURLEncode struct{}
+
+func (fn *FunctionURLEncode) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	// Maps are encoded as query parameters
+	if tv, ok := spec.GetInputs()[EncodingInput]; ok && tv.ValueType() == typedvalues.TypeMap {
+		mp, err := typedvalues.UnwrapMap(tv)
+		if err != nil {
+			return nil, err
+		}
+		query := url.Values{}
+		for k, v := range mp {
+			query.Set(k, fmt.Sprintf("%v", v))
+		}
+		return typedvalues.Wrap(query.Encode())
+	}
+
+	data, operation, err := parseEncodingInputs(spec, EncodingOperationEncode)
+	if err != nil {
+		return nil, err
+	}
+	switch operation {
+	case EncodingOperationEncode:
+		return typedvalues.Wrap(url.QueryEscape(string(data)))
+	case EncodingOperationDecode:
+		decoded, err := url.QueryUnescape(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode url encoding: %v", err)
+		}
+		return typedvalues.Wrap(decoded)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", operation)
+	}
+}
+
+/*
+FunctionGzip compresses the input with gzip, or decompresses gzipped input.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+default         | yes      | *                 | The value to compress or decompress.
+operation       | no       | string            | Either `compress` or `decompress`. (default: compress)
+
+Values other than strings and bytes are encoded as JSON before being compressed.
+
+**output** (string/bytes) The compressed bytes, or the decompressed value. The decompressed value is a string if it is
+valid UTF-8.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: gzip
+  inputs:
+    default: "{ output('LargeTask') }"
+    operation: compress
+# ...
+```
+
+A complete example of this function can be found in the [encodingwhale](../examples/whales/encodingwhale.wf.yaml)
+example.
+*/
+type FunctionGzip struct{}
+
+func (fn *FunctionGzip) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	data, operation, err := parseEncodingInputs(spec, EncodingOperationCompress)
+	if err != nil {
+		return nil, err
+	}
+	switch operation {
+	case EncodingOperationCompress:
+		buf := &bytes.Buffer{}
+		w := gzip.NewWriter(buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return typedvalues.Wrap(buf.Bytes())
+	case EncodingOperationDecompress:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip: %v", err)
+		}
+		defer r.Close()
+		decompressed, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress gzip: %v", err)
+		}
+		return wrapBytes(decompressed)
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", operation)
+	}
+}
+
+// parseEncodingInputs parses the default input as bytes and the (optional) operation input.
+func parseEncodingInputs(spec *types.TaskInvocationSpec, defaultOperation string) ([]byte, string, error) {
+	tv, err := ensureInput(spec.GetInputs(), EncodingInput)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := unwrapAsBytes(tv)
+	if err != nil {
+		return nil, "", err
+	}
+
+	operation := defaultOperation
+	if operationTv, ok := spec.GetInputs()[EncodingInputOperation]; ok {
+		operation, err = typedvalues.UnwrapString(operationTv)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to format operation to a string: %v", err)
+		}
+	}
+	return data, operation, nil
+}
+
+// unwrapAsBytes returns the raw bytes of strings and bytes, and the JSON representation of any other value.
+func unwrapAsBytes(tv *typedvalues.TypedValue) ([]byte, error) {
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	switch t := i.(type) {
+	case []byte:
+		return t, nil
+	case string:
+		return []byte(t), nil
+	default:
+		return json.Marshal(t)
+	}
+}
+
+// wrapBytes wraps the data as a string if it is valid UTF-8, and as bytes otherwise.
+func wrapBytes(data []byte) (*typedvalues.TypedValue, error) {
+	if utf8.Valid(data) {
+		return typedvalues.Wrap(string(data))
+	}
+	return typedvalues.Wrap(data)
+}
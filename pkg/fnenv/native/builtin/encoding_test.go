@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionBase64_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionBase64{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(EncodingInput, typedvalues.MustWrap("hello world")),
+	}, "aGVsbG8gd29ybGQ=")
+
+	internalFunctionTest(t, &FunctionBase64{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			EncodingInput:          typedvalues.MustWrap("aGVsbG8gd29ybGQ="),
+			EncodingInputOperation: typedvalues.MustWrap(EncodingOperationDecode),
+		},
+	}, "hello world")
+}
+
+func TestFunctionURLEncode_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionURLEncode{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(EncodingInput, typedvalues.MustWrap("a b&c")),
+	}, "a+b%26c")
+
+	internalFunctionTest(t, &FunctionURLEncode{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(EncodingInput, typedvalues.MustWrap(map[string]interface{}{
+			"q":    "fission workflows",
+			"page": 2,
+		})),
+	}, "page=2&q=fission+workflows")
+
+	internalFunctionTest(t, &FunctionURLEncode{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			EncodingInput:          typedvalues.MustWrap("a+b%26c"),
+			EncodingInputOperation: typedvalues.MustWrap(EncodingOperationDecode),
+		},
+	}, "a b&c")
+}
+
+func TestFunctionGzip_Invoke(t *testing.T) {
+	compressed, err := (&FunctionGzip{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: types.SingleInput(EncodingInput, typedvalues.MustWrap(map[string]interface{}{
+			"foo": "bar",
+		})),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, typedvalues.TypeBytes, compressed.ValueType())
+
+	internalFunctionTest(t, &FunctionGzip{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			EncodingInput:          compressed,
+			EncodingInputOperation: typedvalues.MustWrap(EncodingOperationDecompress),
+		},
+	}, `{"foo":"bar"}`)
+}
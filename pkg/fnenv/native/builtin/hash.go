@@ -0,0 +1,83 @@
+package builtin
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	Hash                = "hash"
+	HashInput           = types.InputMain
+	HashInputAlgorithm  = "algorithm"
+	HashAlgorithmSHA256 = "sha256"
+	HashAlgorithmMD5    = "md5"
+)
+
+var hashAlgorithms = map[string]func() hash.Hash{
+	HashAlgorithmSHA256: sha256.New,
+	HashAlgorithmMD5:    md5.New,
+}
+
+/*
+FunctionHash computes the hash of the input, which is useful for checksums, deduplication, or cache keys.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+default         | yes      | *                 | The value to hash.
+algorithm       | no       | string            | The hash algorithm: `sha256` or `md5`. (default: sha256)
+
+Values other than strings and bytes are encoded as JSON before being hashed.
+
+**output** (string) The hex-encoded hash of the input.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: hash
+  inputs:
+    default: "hello world"
+    algorithm: md5
+# ...
+```
+
+A complete example of this function can be found in the [encodingwhale](../examples/whales/encodingwhale.wf.yaml)
+example.
+*/
+type FunctionHash struct{}
+
+func (fn *FunctionHash) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	tv, err := ensureInput(spec.GetInputs(), HashInput)
+	if err != nil {
+		return nil, err
+	}
+	data, err := unwrapAsBytes(tv)
+	if err != nil {
+		return nil, err
+	}
+
+	algorithm := HashAlgorithmSHA256
+	if algorithmTv, ok := spec.GetInputs()[HashInputAlgorithm]; ok {
+		algorithm, err = typedvalues.UnwrapString(algorithmTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format algorithm to a string: %v", err)
+		}
+	}
+	newHash, ok := hashAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm '%s'", algorithm)
+	}
+
+	h := newHash()
+	h.Write(data)
+	return typedvalues.Wrap(hex.EncodeToString(h.Sum(nil)))
+}
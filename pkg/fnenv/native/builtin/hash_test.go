@@ -0,0 +1,30 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionHash_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionHash{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(HashInput, typedvalues.MustWrap("hello world")),
+	}, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+
+	internalFunctionTest(t, &FunctionHash{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			HashInput:          typedvalues.MustWrap("hello world"),
+			HashInputAlgorithm: typedvalues.MustWrap(HashAlgorithmMD5),
+		},
+	}, "5eb63bbbe01eeed093cb22bb8f5acdc3")
+
+	_, err := (&FunctionHash{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			HashInput:          typedvalues.MustWrap("hello world"),
+			HashInputAlgorithm: typedvalues.MustWrap("crc32"),
+		},
+	})
+	assert.Error(t, err)
+}
@@ -1,20 +1,36 @@
 package builtin
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/fnenv/http"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 )
 
 const (
-	Http                = "http"
-	HttpInputUrl        = "url"
-	httpDefaultProtocol = "http"
+	Http                  = "http"
+	HttpInputUrl          = "url"
+	HttpInputAuth         = "auth"
+	HttpInputTimeout      = "timeout"
+	HttpAuthType          = "type"
+	HttpAuthToken         = "token"
+	HttpAuthUsername      = "username"
+	HttpAuthPassword      = "password"
+	HttpAuthSecret        = "secret"
+	HttpAuthTypeBearer    = "bearer"
+	HttpAuthTypeBasic     = "basic"
+	HttpDefaultSecretsDir = "/secrets"
+	httpDefaultProtocol   = "http"
+	httpHeaderAuth        = "Authorization"
 )
 
 /*
@@ -31,9 +47,20 @@ headers         | no       | map[string|string | The action to perform for every
 content-type    | no       | string            | Force a specific content-type for the request.
 method          | no       | string            | HTTP Method of the request. (default: GET)
 body            | no       | *                 | The body of the request. (default: application/octet-stream)
+auth            | no       | map[string|string | The credentials to authenticate the request with (see below).
+timeout         | no       | string/number     | The max duration of the request. It cannot exceed the deadline of the task.
 
 Unless the content type is specified explicitly, the workflow engine will infer the content-type based on the body.
 
+The auth input supports the following fields:
+- type: either `bearer` or `basic`. If omitted, it is inferred from the other fields.
+- token: the token to use for bearer authentication.
+- username/password: the credentials to use for basic authentication.
+- secret: a reference to a secret, which replaces the token (bearer) or password (basic).
+
+Secret references have the format `<namespace>/<name>/<key>`, and are read from the secrets directory
+(default: /secrets), which matches the layout of secrets mounted by Fission.
+
 **output** (*) the body of the response.
 
 Note: currently you cannot access the metadata of the response.
@@ -48,18 +75,24 @@ httpExample:
     url: http://fission.io
     method: post
     body: "foo"
+    timeout: 5s
+    auth:
+      type: bearer
+      secret: default/my-api/token
 # ...
 ```
 
 A complete example of this function can be found in the [httpwhale](../examples/whales/httpwhale.wf.yaml) example.
 */
 type FunctionHTTP struct {
-	runtime *http.Runtime
+	runtime    *http.Runtime
+	secretsDir string
 }
 
 func NewFunctionHTTP() *FunctionHTTP {
 	return &FunctionHTTP{
-		runtime: http.New(),
+		runtime:    http.New(),
+		secretsDir: HttpDefaultSecretsDir,
 	}
 }
 
@@ -76,6 +109,35 @@ func (fn *FunctionHTTP) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.Typ
 	clonedSpec := proto.Clone(spec).(*types.TaskInvocationSpec)
 	clonedSpec.FnRef = &fnref
 
+	// Add the authorization header
+	if authTv, ok := spec.Inputs[HttpInputAuth]; ok {
+		header, err := fn.formatAuthHeader(authTv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth: %v", err)
+		}
+		headersTv, err := setHeader(spec.Inputs[types.InputHeaders], httpHeaderAuth, header)
+		if err != nil {
+			return nil, err
+		}
+		clonedSpec.Inputs[types.InputHeaders] = headersTv
+		delete(clonedSpec.Inputs, HttpInputAuth)
+	}
+
+	// Limit the deadline of the request to the timeout
+	if timeoutTv, ok := spec.Inputs[HttpInputTimeout]; ok {
+		timeout, err := unwrapDuration(timeoutTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse timeout: %v", err)
+		}
+		deadline := time.Now().Add(timeout)
+		if current, err := ptypes.Timestamp(spec.Deadline); err != nil || deadline.Before(current) {
+			clonedSpec.Deadline, err = ptypes.TimestampProto(deadline)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	result, err := fn.runtime.Invoke(clonedSpec)
 	if err != nil {
 		return nil, err
@@ -102,3 +164,92 @@ func (fn *FunctionHTTP) determineTargetURL(inputs map[string]*typedvalues.TypedV
 
 	return s, err
 }
+
+// formatAuthHeader formats the auth input into the value of the Authorization header.
+func (fn *FunctionHTTP) formatAuthHeader(authTv *typedvalues.TypedValue) (string, error) {
+	i, err := typedvalues.Unwrap(authTv)
+	if err != nil {
+		return "", err
+	}
+	auth, ok := i.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("expected a map, but was '%v'", authTv.ValueType())
+	}
+	fields := map[string]string{}
+	for k, v := range auth {
+		fields[k] = fmt.Sprintf("%v", v)
+	}
+
+	authType := strings.ToLower(fields[HttpAuthType])
+	if len(authType) == 0 {
+		if _, ok := fields[HttpAuthUsername]; ok {
+			authType = HttpAuthTypeBasic
+		} else {
+			authType = HttpAuthTypeBearer
+		}
+	}
+
+	// Resolve the secret reference
+	if ref, ok := fields[HttpAuthSecret]; ok {
+		secret, err := fn.readSecret(ref)
+		if err != nil {
+			return "", err
+		}
+		if authType == HttpAuthTypeBasic {
+			fields[HttpAuthPassword] = secret
+		} else {
+			fields[HttpAuthToken] = secret
+		}
+	}
+
+	switch authType {
+	case HttpAuthTypeBearer:
+		token := fields[HttpAuthToken]
+		if len(token) == 0 {
+			return "", errors.New("bearer auth requires a token or secret")
+		}
+		return "Bearer " + token, nil
+	case HttpAuthTypeBasic:
+		username := fields[HttpAuthUsername]
+		if len(username) == 0 {
+			return "", errors.New("basic auth requires a username")
+		}
+		creds := base64.StdEncoding.EncodeToString([]byte(username + ":" + fields[HttpAuthPassword]))
+		return "Basic " + creds, nil
+	default:
+		return "", fmt.Errorf("unknown auth type '%s'", authType)
+	}
+}
+
+// readSecret reads the value of the referenced secret from the secrets directory.
+func (fn *FunctionHTTP) readSecret(ref string) (string, error) {
+	if len(ref) == 0 {
+		return "", errors.New("secret reference is empty")
+	}
+	// Clean the reference to avoid reading files outside of the secrets directory.
+	path := filepath.Join(fn.secretsDir, filepath.Clean("/"+ref))
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret '%s': %v", ref, err)
+	}
+	return strings.TrimSpace(string(bs)), nil
+}
+
+// setHeader adds the header to the (optional) headers input.
+func setHeader(headersTv *typedvalues.TypedValue, key string, value string) (*typedvalues.TypedValue, error) {
+	headers := map[string]interface{}{}
+	if headersTv != nil {
+		i, err := typedvalues.Unwrap(headersTv)
+		if err != nil {
+			return nil, err
+		}
+		mp, ok := i.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("input '%s' needs to be a map, but was '%v'", types.InputHeaders,
+				headersTv.ValueType())
+		}
+		headers = mp
+	}
+	headers[key] = value
+	return typedvalues.Wrap(headers)
+}
@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -75,3 +77,57 @@ func TestFunctionHttp_Invoke_Invalid(t *testing.T) {
 	assert.Nil(t, out)
 	assert.Error(t, err, "expected error\n")
 }
+
+func TestFunctionHttp_InvokeAuth(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+	defer ts.Close()
+
+	secretsDir, err := ioutil.TempDir("", "secrets")
+	assert.NoError(t, err)
+	defer os.RemoveAll(secretsDir)
+	assert.NoError(t, os.MkdirAll(filepath.Join(secretsDir, "default", "api"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(secretsDir, "default", "api", "token"), []byte("s3cr3t\n"), 0600))
+
+	fn := NewFunctionHTTP()
+	fn.secretsDir = secretsDir
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Second))
+
+	out, err := fn.Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			HttpInputUrl: typedvalues.MustWrap(ts.URL),
+			HttpInputAuth: typedvalues.MustWrap(map[string]interface{}{
+				HttpAuthSecret: "default/api/token",
+			}),
+			HttpInputTimeout: typedvalues.MustWrap("5s"),
+		},
+		Deadline: deadline,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer s3cr3t", typedvalues.MustUnwrap(out))
+
+	out, err = fn.Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			HttpInputUrl: typedvalues.MustWrap(ts.URL),
+			HttpInputAuth: typedvalues.MustWrap(map[string]interface{}{
+				HttpAuthUsername: "foo",
+				HttpAuthPassword: "bar",
+			}),
+		},
+		Deadline: deadline,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Basic Zm9vOmJhcg==", typedvalues.MustUnwrap(out))
+
+	_, err = fn.Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			HttpInputUrl: typedvalues.MustWrap(ts.URL),
+			HttpInputAuth: typedvalues.MustWrap(map[string]interface{}{
+				HttpAuthSecret: "../../etc/passwd",
+			}),
+		},
+		Deadline: deadline,
+	})
+	assert.Error(t, err)
+}
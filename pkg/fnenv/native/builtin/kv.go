@@ -0,0 +1,313 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/hashicorp/golang-lru"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	KVSet              = "kv.set"
+	KVGet              = "kv.get"
+	KVInputKey         = "key"
+	KVInputValue       = "value"
+	KVInputAppend      = "append"
+	KVInputOtherwise   = "otherwise"
+	DefaultKVCacheSize = 1000
+	kvMaxParentLookup  = 100
+)
+
+// KVStore stores the key-value pairs of the kv functions, partitioned by scope.
+type KVStore interface {
+	// Get returns the value of the key in the scope, and whether the key was present.
+	Get(scope string, key string) (*typedvalues.TypedValue, bool, error)
+
+	// Update atomically replaces the value of the key in the scope with the result of fn. The current value is nil if
+	// the key is not present.
+	Update(scope string, key string, fn func(current *typedvalues.TypedValue) (*typedvalues.TypedValue, error)) error
+}
+
+// InvocationGetter provides access to the invocations, which is needed to determine the scope of the kv functions.
+type InvocationGetter interface {
+	GetInvocation(invocationID string) (*types.WorkflowInvocation, error)
+}
+
+// EventKVStore is a KVStore that records the values as events of the invocation that is the scope. The values
+// therefore survive restarts of the workflow engine, and are removed along with the invocation.
+//
+// The values of the most recently used scopes are cached. The cache is bounded; evicting a scope from it only
+// discards the cached values, which are read from the event store again when the scope is used next.
+type EventKVStore struct {
+	es          fes.Backend
+	invocations *api.Invocation
+	cache       *lru.Cache // map[string]map[string]*typedvalues.TypedValue
+	mu          sync.Mutex
+}
+
+// NewEventKVStore creates a KVStore on top of the event store. The cacheSize is the maximum number of scopes of which
+// the values are cached; if it is not positive, DefaultKVCacheSize is used.
+func NewEventKVStore(es fes.Backend, invocations *api.Invocation, cacheSize int) *EventKVStore {
+	if cacheSize <= 0 {
+		cacheSize = DefaultKVCacheSize
+	}
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &EventKVStore{
+		es:          es,
+		invocations: invocations,
+		cache:       cache,
+	}
+}
+
+func (s *EventKVStore) Get(scope string, key string) (*typedvalues.TypedValue, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values, err := s.load(scope)
+	if err != nil {
+		return nil, false, err
+	}
+	tv, ok := values[key]
+	return tv, ok, nil
+}
+
+func (s *EventKVStore) Update(scope string, key string,
+	fn func(current *typedvalues.TypedValue) (*typedvalues.TypedValue, error)) error {
+	// Updates are serialized, so that the value that fn is applied to is the latest value of the key.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values, err := s.load(scope)
+	if err != nil {
+		return err
+	}
+	updated, err := fn(values[key])
+	if err != nil {
+		return err
+	}
+	if err := s.invocations.StoreValue(scope, key, updated); err != nil {
+		return err
+	}
+	values[key] = updated
+	return nil
+}
+
+// load returns the values of the scope from the cache, or replays them from the events of the invocation.
+func (s *EventKVStore) load(scope string) (map[string]*typedvalues.TypedValue, error) {
+	if cached, ok := s.cache.Get(scope); ok {
+		return cached.(map[string]*typedvalues.TypedValue), nil
+	}
+	evts, err := s.es.Get(projectors.NewInvocationAggregate(scope))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kv scope %s: %v", scope, err)
+	}
+	values := map[string]*typedvalues.TypedValue{}
+	for _, event := range evts {
+		if event.GetType() != events.EventInvocationValueStored {
+			continue
+		}
+		data, err := fes.ParseEventData(event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kv scope %s: %v", scope, err)
+		}
+		stored := data.(*events.InvocationValueStored)
+		values[stored.GetKey()] = stored.GetValue()
+	}
+	s.cache.Add(scope, values)
+	return values, nil
+}
+
+/*
+FunctionKVSet stores a value in a key-value store that is scoped to the workflow invocation.
+The scope is shared with all dynamic tasks and workflows (such as the iterations of a `while` or `foreach`) that are
+created by the invocation, which allows these constructs to accumulate state across iterations.
+The value can be retrieved using the `kv.get` function.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+key             | yes      | string            | The key to store the value under.
+value/default   | yes      | *                 | The value to store.
+append          | no       | bool              | Append the value to the list stored under the key, instead of replacing it. (default: false)
+
+The values are recorded as events of the invocation, so they survive restarts of the workflow engine and are
+removed along with the invocation. Large values are offloaded to the blob store, if one is configured.
+
+**output** (*) The value that is stored under the key.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: kv.set
+  inputs:
+    key: results
+    value: "{ output('SomeTask') }"
+    append: true
+# ...
+```
+
+A complete example of this function can be found in the [kvwhale](../examples/whales/kvwhale.wf.yaml) example.
+*/
+type FunctionKVSet struct {
+	store       KVStore
+	invocations InvocationGetter
+}
+
+func NewFunctionKVSet(store KVStore, invocations InvocationGetter) *FunctionKVSet {
+	return &FunctionKVSet{
+		store:       store,
+		invocations: invocations,
+	}
+}
+
+func (fn *FunctionKVSet) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	keyTv, err := ensureInput(spec.GetInputs(), KVInputKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := unwrapKVKey(keyTv)
+	if err != nil {
+		return nil, err
+	}
+	_, value := getFirstDefinedTypedValue(spec.GetInputs(), KVInputValue, types.InputMain)
+	if value == nil {
+		return nil, fmt.Errorf("input '%s' is not set", KVInputValue)
+	}
+	var appendValue bool
+	if appendTv, ok := spec.GetInputs()[KVInputAppend]; ok {
+		appendValue, err = typedvalues.UnwrapBool(appendTv)
+		if err != nil {
+			return nil, fmt.Errorf("append could not be parsed into a boolean: %v", err)
+		}
+	}
+
+	scope := kvScope(fn.invocations, spec.GetInvocationId())
+	var result *typedvalues.TypedValue
+	err = fn.store.Update(scope, key, func(current *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+		if !appendValue {
+			result = value
+			return result, nil
+		}
+		var items []interface{}
+		if current != nil {
+			list, err := typedvalues.UnwrapArray(current)
+			if err != nil {
+				return nil, fmt.Errorf("cannot append to non-list value of key '%s': %v", key, err)
+			}
+			items = list
+		}
+		item, err := typedvalues.Unwrap(value)
+		if err != nil {
+			return nil, err
+		}
+		result, err = typedvalues.Wrap(append(items, item))
+		return result, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/*
+FunctionKVGet retrieves a value from the key-value store that is scoped to the workflow invocation.
+See `kv.set` for storing values.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+key/default     | yes      | string            | The key of the value to retrieve.
+otherwise       | no       | *                 | The value to output if the key is not present. (default: nil)
+
+**output** (*) The value stored under the key, or the `otherwise` value if the key is not present.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: kv.get
+  inputs:
+    key: results
+    otherwise: []
+# ...
+```
+
+A complete example of this function can be found in the [kvwhale](../examples/whales/kvwhale.wf.yaml) example.
+*/
+type FunctionKVGet struct {
+	store       KVStore
+	invocations InvocationGetter
+}
+
+func NewFunctionKVGet(store KVStore, invocations InvocationGetter) *FunctionKVGet {
+	return &FunctionKVGet{
+		store:       store,
+		invocations: invocations,
+	}
+}
+
+func (fn *FunctionKVGet) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, keyTv := getFirstDefinedTypedValue(spec.GetInputs(), KVInputKey, types.InputMain)
+	if keyTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", KVInputKey)
+	}
+	key, err := unwrapKVKey(keyTv)
+	if err != nil {
+		return nil, err
+	}
+	scope := kvScope(fn.invocations, spec.GetInvocationId())
+	value, ok, err := fn.store.Get(scope, key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return value, nil
+	}
+	return spec.GetInputs()[KVInputOtherwise], nil
+}
+
+func unwrapKVKey(keyTv *typedvalues.TypedValue) (string, error) {
+	key, err := typedvalues.UnwrapString(keyTv)
+	if err != nil {
+		return "", fmt.Errorf("failed to format key to a string: %v", err)
+	}
+	if len(key) == 0 {
+		return "", errors.New("key is empty")
+	}
+	return key, nil
+}
+
+// kvScope determines the scope of the invocation, which is the top-level invocation that (indirectly) created it.
+func kvScope(invocations InvocationGetter, invocationID string) string {
+	if invocations == nil {
+		return invocationID
+	}
+	scope := invocationID
+	for i := 0; i < kvMaxParentLookup; i++ {
+		invocation, err := invocations.GetInvocation(scope)
+		if err != nil {
+			logrus.Debugf("Could not find invocation %s to determine kv scope: %v", scope, err)
+			break
+		}
+		parentID := invocation.GetSpec().GetParentId()
+		if len(parentID) == 0 {
+			break
+		}
+		scope = parentID
+	}
+	return scope
+}
@@ -0,0 +1,121 @@
+package builtin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockInvocationGetter map[string]*types.WorkflowInvocation
+
+func (m mockInvocationGetter) GetInvocation(invocationID string) (*types.WorkflowInvocation, error) {
+	invocation, ok := m[invocationID]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return invocation, nil
+}
+
+func newTestKVStore(cacheSize int) (*EventKVStore, *mem.Backend) {
+	es := mem.NewBackend()
+	return NewEventKVStore(es, api.NewInvocationAPI(es, nil), cacheSize), es
+}
+
+func TestFunctionKV_Invoke(t *testing.T) {
+	store, _ := newTestKVStore(0)
+	invocations := mockInvocationGetter{
+		"root":  &types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{}},
+		"child": &types.WorkflowInvocation{Spec: &types.WorkflowInvocationSpec{ParentId: "root"}},
+	}
+	set := NewFunctionKVSet(store, invocations)
+	get := NewFunctionKVGet(store, invocations)
+
+	// Missing keys result in the otherwise value
+	internalFunctionTest(t, get, &types.TaskInvocationSpec{
+		InvocationId: "root",
+		Inputs: map[string]*typedvalues.TypedValue{
+			KVInputKey:       typedvalues.MustWrap("foo"),
+			KVInputOtherwise: typedvalues.MustWrap("bar"),
+		},
+	}, "bar")
+
+	// Values set in child invocations are visible in the root invocation
+	for _, v := range []string{"a", "b"} {
+		_, err := set.Invoke(&types.TaskInvocationSpec{
+			InvocationId: "child",
+			Inputs: map[string]*typedvalues.TypedValue{
+				KVInputKey:    typedvalues.MustWrap("foo"),
+				KVInputValue:  typedvalues.MustWrap(v),
+				KVInputAppend: typedvalues.MustWrap(true),
+			},
+		})
+		assert.NoError(t, err)
+	}
+	internalFunctionTest(t, get, &types.TaskInvocationSpec{
+		InvocationId: "root",
+		Inputs:       types.SingleInput(types.InputMain, typedvalues.MustWrap("foo")),
+	}, []interface{}{"a", "b"})
+
+	// Values are not shared between unrelated invocations
+	internalFunctionTest(t, get, &types.TaskInvocationSpec{
+		InvocationId: "other",
+		Inputs:       types.SingleInput(KVInputKey, typedvalues.MustWrap("foo")),
+	}, nil)
+}
+
+func TestFunctionKVSet_InvokeAppendToNonList(t *testing.T) {
+	store, _ := newTestKVStore(0)
+	set := NewFunctionKVSet(store, nil)
+	_, err := set.Invoke(&types.TaskInvocationSpec{
+		InvocationId: "root",
+		Inputs: map[string]*typedvalues.TypedValue{
+			KVInputKey:   typedvalues.MustWrap("foo"),
+			KVInputValue: typedvalues.MustWrap("bar"),
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = set.Invoke(&types.TaskInvocationSpec{
+		InvocationId: "root",
+		Inputs: map[string]*typedvalues.TypedValue{
+			KVInputKey:    typedvalues.MustWrap("foo"),
+			KVInputValue:  typedvalues.MustWrap("baz"),
+			KVInputAppend: typedvalues.MustWrap(true),
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestEventKVStore_Reload(t *testing.T) {
+	store, es := newTestKVStore(1)
+	set := func(scope string, value string) {
+		err := store.Update(scope, "foo", func(current *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+			return typedvalues.MustWrap(value), nil
+		})
+		assert.NoError(t, err)
+	}
+	set("a", "1")
+	set("a", "2")
+	// The scope "a" is evicted from the cache by "b", and read from the events again.
+	set("b", "3")
+	tv, ok, err := store.Get("a", "foo")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "2", typedvalues.MustUnwrap(tv))
+
+	// The values survive a restart.
+	restarted := NewEventKVStore(es, api.NewInvocationAPI(es, nil), 0)
+	tv, ok, err = restarted.Get("b", "foo")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "3", typedvalues.MustUnwrap(tv))
+
+	_, ok, err = restarted.Get("b", "bar")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
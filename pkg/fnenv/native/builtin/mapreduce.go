@@ -0,0 +1,192 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	MapReduce                = "mapreduce"
+	MapReduceInputMapReduce  = "mapreduce"
+	MapReduceInputMapper     = "mapper"
+	MapReduceInputReducer    = "reducer"
+	MapReduceInputSequential = "sequential"
+	MapReduceInputItem       = "_item"
+	MapReduceInputItems      = "_items"
+	mapReduceReduceTask      = "reduce"
+)
+
+/*
+FunctionMapReduce is a control flow construct that applies a mapper to each element of the provided list in parallel,
+and subsequently aggregates the outputs of the mappers with a reducer.
+It generates the fan-out (map) and fan-in (reduce) tasks dynamically, so the size of the list does not need to be
+known when defining the workflow.
+
+**Specification**
+
+**input**                | required | types         | description
+-------------------------|----------|---------------|--------------------------------------------------------
+mapreduce                | yes      | list          | The list of elements to map.
+mapper                   | yes      | string/task   | The function reference or task to apply to each element.
+reducer                  | no       | string/task   | The function reference or task to aggregate the mapped outputs with.
+sequential               | no       | bool          | Whether to execute the mappers sequentially (default: false).
+
+The element is made available to the mapper using the field `_item`, the list of mapped outputs is made available to
+the reducer using the field `_items`.
+If the mapper or reducer is a function reference, the element or list is also provided as the default input.
+
+**output** (*) The output of the reducer, or the list of mapped outputs if no reducer was provided.
+
+**Example**
+
+```
+foo:
+  run: mapreduce
+  inputs:
+    mapreduce:
+    - a
+    - b
+    - c
+    mapper: wordcount
+    reducer:
+      run: aggregate
+      inputs:
+        operation: sum
+```
+
+A complete example of this function can be found in the [mapreducewhale](../examples/whales/mapreducewhale.wf.yaml)
+example.
+*/
+type FunctionMapReduce struct{}
+
+func (fn *FunctionMapReduce) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	// Verify and parse the list
+	listTv, err := ensureInput(spec.GetInputs(), MapReduceInputMapReduce)
+	if err != nil {
+		return nil, err
+	}
+	i, err := typedvalues.Unwrap(listTv)
+	if err != nil {
+		return nil, err
+	}
+	items, ok := i.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("input '%v' needs to be a 'array', but was '%v'", i, listTv.ValueType())
+	}
+
+	// Parse mapper
+	mapperTv, err := ensureInput(spec.GetInputs(), MapReduceInputMapper)
+	if err != nil {
+		return nil, err
+	}
+	mapper, mapperIsRef, err := unwrapTaskOrFnRef(mapperTv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mapper: %v", err)
+	}
+	mapper.Requires = map[string]*types.TaskDependencyParameters{}
+
+	// Parse reducer
+	var reducer *types.TaskSpec
+	var reducerIsRef bool
+	if reducerTv, ok := spec.GetInputs()[MapReduceInputReducer]; ok {
+		reducer, reducerIsRef, err = unwrapTaskOrFnRef(reducerTv)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reducer: %v", err)
+		}
+		reducer.Requires = map[string]*types.TaskDependencyParameters{}
+	}
+
+	// Parse sequential
+	var seq bool
+	if seqTv, ok := spec.GetInputs()[MapReduceInputSequential]; ok {
+		seq, err = typedvalues.UnwrapBool(seqTv)
+		if err != nil {
+			return nil, fmt.Errorf("sequential could not be parsed into a boolean: %v", err)
+		}
+	}
+
+	wf := &types.WorkflowSpec{
+		OutputTask: mapReduceReduceTask,
+		Tasks:      types.Tasks{},
+	}
+
+	// Fan-out: create a mapper task for each element
+	var mapTasks []string // Needed to preserve order of the input list
+	for k, item := range items {
+		t := proto.Clone(mapper).(*types.TaskSpec)
+		itemTv, err := typedvalues.Wrap(item)
+		if err != nil {
+			return nil, err
+		}
+		itemTv.SetMetadata(typedvalues.MetadataPriority, "1000") // Ensure that item is resolved before other inputs
+		t.Input(MapReduceInputItem, itemTv)
+		if mapperIsRef {
+			t.Input(types.InputMain, itemTv)
+		}
+
+		id := fmt.Sprintf("map_%d", k)
+		if seq && k != 0 {
+			t.Require(mapTasks[k-1])
+		}
+		wf.AddTask(id, t)
+		mapTasks = append(mapTasks, id)
+	}
+
+	// Fan-in: collect the outputs of the mappers into the reducer
+	var outputs []interface{}
+	for _, id := range mapTasks {
+		outputs = append(outputs, fmt.Sprintf("{output('%s')}", id))
+	}
+	outputsTv := typedvalues.MustWrap(outputs)
+	outputsTv.SetMetadata(typedvalues.MetadataPriority, "1000")
+
+	var rt *types.TaskSpec
+	if reducer != nil {
+		rt = proto.Clone(reducer).(*types.TaskSpec)
+		rt.Input(MapReduceInputItems, outputsTv)
+		if reducerIsRef {
+			rt.Input(types.InputMain, outputsTv)
+		}
+	} else {
+		rt = &types.TaskSpec{
+			FunctionRef: Compose,
+			Inputs:      types.SingleInput(ComposeInput, outputsTv),
+		}
+	}
+	for _, id := range mapTasks {
+		rt.Require(id)
+	}
+	wf.AddTask(mapReduceReduceTask, rt)
+
+	return typedvalues.Wrap(wf)
+}
+
+// unwrapTaskOrFnRef parses the typed value either into a task or into a function reference.
+// In case of the latter, a task is created for the function reference. The boolean indicates whether the typed value
+// was a function reference.
+func unwrapTaskOrFnRef(tv *typedvalues.TypedValue) (*types.TaskSpec, bool, error) {
+	if controlflow.IsControlFlow(tv) {
+		flow, err := controlflow.UnwrapControlFlow(tv)
+		if err != nil {
+			return nil, false, err
+		}
+		if flow.GetWorkflow() != nil {
+			return nil, false, errors.New("workflows are not supported (yet)")
+		}
+		return flow.GetTask(), false, nil
+	}
+
+	fnRef, err := typedvalues.UnwrapString(tv)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(fnRef) == 0 {
+		return nil, false, errors.New("function reference is empty")
+	}
+	return types.NewTaskSpec(fnRef), true, nil
+}
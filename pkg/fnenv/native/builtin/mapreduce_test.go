@@ -0,0 +1,72 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionMapReduce_Invoke(t *testing.T) {
+	elements := []interface{}{1, 2, 3, "foo"}
+	out, err := (&FunctionMapReduce{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			MapReduceInputMapReduce: typedvalues.MustWrap(elements),
+			MapReduceInputMapper:    typedvalues.MustWrap("wordcount"),
+			MapReduceInputReducer: typedvalues.MustWrap(&types.TaskSpec{
+				FunctionRef: Noop,
+			}),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, controlflow.TypeWorkflow, out.ValueType())
+
+	wf, err := controlflow.UnwrapWorkflow(out)
+	assert.NoError(t, err)
+	assert.Equal(t, len(elements)+1, len(wf.Tasks)) // + 1 for the reducer
+	assert.Equal(t, "reduce", wf.OutputTask)
+
+	mapper := wf.Tasks["map_0"]
+	assert.NotNil(t, mapper)
+	assert.Equal(t, "wordcount", mapper.FunctionRef)
+	assert.Equal(t, elements[0], int(typedvalues.MustUnwrap(mapper.Inputs[MapReduceInputItem]).(int32)))
+	assert.Equal(t, elements[0], int(typedvalues.MustUnwrap(mapper.Inputs[types.InputMain]).(int32)))
+
+	reducer := wf.Tasks["reduce"]
+	assert.Equal(t, Noop, reducer.FunctionRef)
+	assert.Len(t, reducer.Requires, len(elements))
+	assert.Len(t, typedvalues.MustUnwrap(reducer.Inputs[MapReduceInputItems]), len(elements))
+	assert.Nil(t, reducer.Inputs[types.InputMain])
+}
+
+func TestFunctionMapReduce_InvokeWithoutReducer(t *testing.T) {
+	out, err := (&FunctionMapReduce{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			MapReduceInputMapReduce: typedvalues.MustWrap([]interface{}{"a", "b"}),
+			MapReduceInputMapper: typedvalues.MustWrap(&types.TaskSpec{
+				FunctionRef: Noop,
+			}),
+			MapReduceInputSequential: typedvalues.MustWrap(true),
+		},
+	})
+	assert.NoError(t, err)
+
+	wf, err := controlflow.UnwrapWorkflow(out)
+	assert.NoError(t, err)
+	assert.Equal(t, Compose, wf.Tasks["reduce"].FunctionRef)
+	assert.Contains(t, wf.Tasks["map_1"].Requires, "map_0")
+	assert.Nil(t, wf.Tasks["map_0"].Inputs[types.InputMain])
+}
+
+func TestFunctionMapReduce_InvokeInvalidList(t *testing.T) {
+	out, err := (&FunctionMapReduce{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			MapReduceInputMapReduce: typedvalues.MustWrap("not a list"),
+			MapReduceInputMapper:    typedvalues.MustWrap("wordcount"),
+		},
+	})
+	assert.Error(t, err)
+	assert.Nil(t, out)
+}
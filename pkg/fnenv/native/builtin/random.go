@@ -0,0 +1,132 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	RandomInt              = "random.int"
+	RandomIntInputMin      = "min"
+	RandomIntInputMax      = "max"
+	RandomChoice           = "random.choice"
+	RandomChoiceInputItems = "items"
+)
+
+// random is the source of randomness shared by the random functions; rand.Rand is not safe for concurrent use.
+var random = struct {
+	*rand.Rand
+	sync.Mutex
+}{
+	Rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+}
+
+/*
+FunctionRandomInt generates a random integer within a range, which is for example useful for sharding fan-outs.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+min             | no       | number            | The (inclusive) lower bound of the range. (default: 0)
+max/default     | yes      | number            | The (exclusive) upper bound of the range.
+
+**output** (number) A random integer in the range [min, max).
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: random.int
+  inputs:
+    min: 0
+    max: 10
+# ...
+```
+
+A complete example of this function can be found in the [randomwhale](../examples/whales/randomwhale.wf.yaml) example.
+*/
+type FunctionRandomInt struct{}
+
+func (fn *FunctionRandomInt) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	var min int64
+	var err error
+	if minTv, ok := spec.GetInputs()[RandomIntInputMin]; ok {
+		min, err = typedvalues.UnwrapInt64(minTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format min to a number: %v", err)
+		}
+	}
+
+	_, maxTv := getFirstDefinedTypedValue(spec.GetInputs(), RandomIntInputMax, types.InputMain)
+	if maxTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", RandomIntInputMax)
+	}
+	max, err := typedvalues.UnwrapInt64(maxTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format max to a number: %v", err)
+	}
+	if max <= min {
+		return nil, fmt.Errorf("max (%d) needs to be larger than min (%d)", max, min)
+	}
+
+	random.Lock()
+	n := min + random.Int63n(max-min)
+	random.Unlock()
+	return typedvalues.Wrap(n)
+}
+
+/*
+FunctionRandomChoice selects a random element from a list.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+items/default   | yes      | list              | The list to select an element from.
+
+**output** (*) A random element of the list.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: random.choice
+  inputs:
+    items:
+    - a
+    - b
+    - c
+# ...
+```
+
+A complete example of this function can be found in the [randomwhale](../examples/whales/randomwhale.wf.yaml) example.
+*/
+type FunctionRandomChoice struct{}
+
+func (fn *FunctionRandomChoice) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, itemsTv := getFirstDefinedTypedValue(spec.GetInputs(), RandomChoiceInputItems, types.InputMain)
+	if itemsTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", RandomChoiceInputItems)
+	}
+	items, err := typedvalues.UnwrapTypedValueArray(itemsTv)
+	if err != nil {
+		return nil, fmt.Errorf("input '%s' needs to be a list: %v", RandomChoiceInputItems, err)
+	}
+	if len(items) == 0 {
+		return nil, errors.New("cannot choose from an empty list")
+	}
+
+	random.Lock()
+	i := random.Intn(len(items))
+	random.Unlock()
+	return items[i], nil
+}
@@ -0,0 +1,43 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionRandomInt_Invoke(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		out, err := (&FunctionRandomInt{}).Invoke(&types.TaskInvocationSpec{
+			Inputs: map[string]*typedvalues.TypedValue{
+				RandomIntInputMin: typedvalues.MustWrap(-5),
+				RandomIntInputMax: typedvalues.MustWrap(5),
+			},
+		})
+		assert.NoError(t, err)
+		n, err := typedvalues.UnwrapInt64(out)
+		assert.NoError(t, err)
+		assert.True(t, n >= -5 && n < 5)
+	}
+
+	_, err := (&FunctionRandomInt{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: types.SingleInput(types.InputMain, typedvalues.MustWrap(0)),
+	})
+	assert.Error(t, err)
+}
+
+func TestFunctionRandomChoice_Invoke(t *testing.T) {
+	items := []interface{}{"a", "b", "c"}
+	out, err := (&FunctionRandomChoice{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: types.SingleInput(RandomChoiceInputItems, typedvalues.MustWrap(items)),
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, items, typedvalues.MustUnwrap(out))
+
+	_, err = (&FunctionRandomChoice{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: types.SingleInput(RandomChoiceInputItems, typedvalues.MustWrap([]interface{}{})),
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,142 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/hashicorp/golang-lru"
+)
+
+const (
+	Regex                 = "regex"
+	RegexInputValue       = "value"
+	RegexInputPattern     = "pattern"
+	RegexInputOperation   = "operation"
+	RegexInputReplacement = "replacement"
+	RegexOperationMatch   = "match"
+	RegexOperationFindAll = "findall"
+	RegexOperationReplace = "replace"
+	regexCacheSize        = 1000
+)
+
+/*
+FunctionRegex performs regular expression operations on a string.
+It supports checking whether the string matches the pattern, finding all matches of the pattern in the string, and
+replacing all matches of the pattern in the string.
+The patterns follow the [Golang regular expression syntax](https://golang.org/pkg/regexp/syntax/).
+Compiled patterns are cached, so repeatedly applying the same pattern (for example, in a foreach or mapreduce) does not
+require the pattern to be recompiled.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+value/default   | yes      | string            | The string to apply the regular expression to.
+pattern         | yes      | string            | The regular expression.
+operation       | no       | string            | The operation to perform: `match`, `findall` or `replace`. (default: match)
+replacement     | no       | string            | The replacement of the matches, required for the `replace` operation.
+
+In the replacement, `$1` or `${name}` can be used to reference the submatches of the pattern.
+
+**output** (bool/list/string) Depending on the operation: `match` outputs whether the string matches the pattern,
+`findall` outputs the list of all matches, and `replace` outputs the string with all matches replaced.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: regex
+  inputs:
+    value: "The quick brown fox"
+    pattern: "\\w+"
+    operation: findall
+# ...
+```
+
+A complete example of this function can be found in the [regexwhale](../examples/whales/regexwhale.wf.yaml) example.
+*/
+type FunctionRegex struct {
+	patterns *lru.Cache // map[string]*regexp.Regexp
+}
+
+func NewFunctionRegex() *FunctionRegex {
+	cache, err := lru.New(regexCacheSize)
+	if err != nil {
+		panic(err)
+	}
+	return &FunctionRegex{
+		patterns: cache,
+	}
+}
+
+func (fn *FunctionRegex) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, valueTv := getFirstDefinedTypedValue(spec.GetInputs(), RegexInputValue, types.InputMain)
+	if valueTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", RegexInputValue)
+	}
+	value, err := typedvalues.UnwrapString(valueTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format value to a string: %v", err)
+	}
+
+	patternTv, err := ensureInput(spec.GetInputs(), RegexInputPattern)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := typedvalues.UnwrapString(patternTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format pattern to a string: %v", err)
+	}
+	re, err := fn.compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := RegexOperationMatch
+	if operationTv, ok := spec.GetInputs()[RegexInputOperation]; ok {
+		operation, err = typedvalues.UnwrapString(operationTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format operation to a string: %v", err)
+		}
+	}
+
+	switch operation {
+	case RegexOperationMatch:
+		return typedvalues.Wrap(re.MatchString(value))
+	case RegexOperationFindAll:
+		matches := []interface{}{}
+		for _, match := range re.FindAllString(value, -1) {
+			matches = append(matches, match)
+		}
+		return typedvalues.Wrap(matches)
+	case RegexOperationReplace:
+		replacementTv, ok := spec.GetInputs()[RegexInputReplacement]
+		if !ok {
+			return nil, errors.New("replace operation requires a replacement")
+		}
+		replacement, err := typedvalues.UnwrapString(replacementTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format replacement to a string: %v", err)
+		}
+		return typedvalues.Wrap(re.ReplaceAllString(value, replacement))
+	default:
+		return nil, fmt.Errorf("unknown operation '%s'", operation)
+	}
+}
+
+// compile returns the compiled pattern, either from the cache or by compiling the pattern.
+func (fn *FunctionRegex) compile(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := fn.patterns.Get(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+	fn.patterns.Add(pattern, re)
+	return re, nil
+}
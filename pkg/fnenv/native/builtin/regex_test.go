@@ -0,0 +1,60 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionRegex_Invoke(t *testing.T) {
+	fn := NewFunctionRegex()
+	value := "The quick brown fox"
+
+	internalFunctionTest(t, fn, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			types.InputMain:   typedvalues.MustWrap(value),
+			RegexInputPattern: typedvalues.MustWrap("qu?ick"),
+		},
+	}, true)
+
+	internalFunctionTest(t, fn, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RegexInputValue:     typedvalues.MustWrap(value),
+			RegexInputPattern:   typedvalues.MustWrap("\\w+"),
+			RegexInputOperation: typedvalues.MustWrap(RegexOperationFindAll),
+		},
+	}, []interface{}{"The", "quick", "brown", "fox"})
+
+	internalFunctionTest(t, fn, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RegexInputValue:       typedvalues.MustWrap(value),
+			RegexInputPattern:     typedvalues.MustWrap("(\\w+) fox"),
+			RegexInputOperation:   typedvalues.MustWrap(RegexOperationReplace),
+			RegexInputReplacement: typedvalues.MustWrap("fox of color $1"),
+		},
+	}, "The quick fox of color brown")
+
+	assert.Equal(t, 3, fn.patterns.Len())
+}
+
+func TestFunctionRegex_InvokeInvalid(t *testing.T) {
+	fn := NewFunctionRegex()
+	_, err := fn.Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RegexInputValue:   typedvalues.MustWrap("foo"),
+			RegexInputPattern: typedvalues.MustWrap("(foo"),
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = fn.Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RegexInputValue:     typedvalues.MustWrap("foo"),
+			RegexInputPattern:   typedvalues.MustWrap("foo"),
+			RegexInputOperation: typedvalues.MustWrap(RegexOperationReplace),
+		},
+	})
+	assert.Error(t, err)
+}
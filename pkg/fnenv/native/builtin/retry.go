@@ -0,0 +1,318 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/fission/fission-workflows/pkg/util/backoff"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	Retry                = "retry"
+	RetryInputDo         = "do"
+	RetryInputAttempts   = "attempts"
+	RetryInputBackoff    = "backoff"
+	RetryInputDelay      = "delay"
+	RetryInputMaxDelay   = "maxDelay"
+	RetryInputRetryOn    = "retryOn"
+	RetryInputAttempt    = "_attempt"
+	RetryBackoffFixed    = "fixed"
+	RetryBackoffExp      = "exponential"
+	RetryDefaultAttempts = 3
+	RetryDefaultDelay    = 100 * time.Millisecond
+	RetryDefaultMaxDelay = 10 * time.Second
+	retryAttemptTask     = "attempt"
+)
+
+var (
+	retryAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "task",
+		Name:      "retries_total",
+		Help:      "Number of attempts of retry tasks after their first attempt",
+	}, []string{"workflow", "task", "fnenv"})
+)
+
+func init() {
+	prometheus.MustRegister(retryAttempts)
+}
+
+var (
+	ErrRetryNotRetryable = errors.New("error is not retryable")
+	retryBackoffPolicies = map[string]backoff.Policy{
+		RetryBackoffFixed: backoff.FixedBackoff,
+		RetryBackoffExp:   backoff.ExponentialBackoff,
+	}
+)
+
+// WorkflowAPI creates the (internal) workflows that are executed by the retry and timeout functions, and deletes them
+// once they are no longer needed.
+type WorkflowAPI interface {
+	Create(workflow *types.WorkflowSpec, opts ...api.CallOption) (string, error)
+	Delete(workflowID string) error
+}
+
+// WorkflowRuntime invokes workflows and blocks until the invocation has completed.
+type WorkflowRuntime interface {
+	InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fnenv.InvokeOption) (*types.WorkflowInvocation, error)
+}
+
+/*
+FunctionRetry is a control flow construct that executes a task or workflow, and re-executes it in case it failed.
+Every attempt is executed as a separate (child) workflow invocation, which allows the failed attempts to be inspected
+afterwards without failing the workflow invocation itself.
+Between attempts the function backs off according to the backoff policy, unless the invocation is canceled meanwhile.
+The current attempt number (starting at 0) is made available to the action using the invocation input `_attempt`.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+do              | yes      | string/task/workflow | The function reference, task or workflow to execute.
+attempts        | no       | number            | The max number of attempts (default: 3).
+backoff         | no       | string            | The backoff policy: `fixed` or `exponential` (default: exponential).
+delay           | no       | string/number     | The (base) duration to back off between attempts (default: 100ms).
+maxDelay        | no       | string/number     | The max duration to back off between attempts (default: 10s).
+retryOn         | no       | string            | A regular expression that the error message needs to match for the attempt to be retried (default: all errors are retried).
+
+Durations can either be provided as a string (e.g. "1s") or as a number of milliseconds.
+
+**output** (*) The output of the first successful attempt.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: retry
+  inputs:
+    attempts: 5
+    backoff: exponential
+    delay: 1s
+    retryOn: "timeout"
+    do:
+      run: flakyfunction
+      inputs: "{ $.Invocation.Inputs._attempt }"
+# ...
+```
+
+A complete example of this function can be found in the [retrywhale](../examples/whales/retrywhale.wf.yaml) example.
+*/
+type FunctionRetry struct {
+	workflows WorkflowAPI
+	runtime   WorkflowRuntime
+}
+
+func NewFunctionRetry(workflows WorkflowAPI, runtime WorkflowRuntime) *FunctionRetry {
+	return &FunctionRetry{
+		workflows: workflows,
+		runtime:   runtime,
+	}
+}
+
+func (fn *FunctionRetry) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	return fn.InvokeContext(context.Background(), spec)
+}
+
+// InvokeContext executes the attempts until one succeeds. Once the context is done, such as when the invocation has
+// been canceled, it stops waiting for the next attempt.
+func (fn *FunctionRetry) InvokeContext(ctx context.Context, spec *types.TaskInvocationSpec) (*typedvalues.TypedValue,
+	error) {
+	// Parse the action
+	actionTv, err := ensureInput(spec.GetInputs(), RetryInputDo)
+	if err != nil {
+		return nil, err
+	}
+	wfSpec, err := unwrapRetryAction(actionTv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action: %v", err)
+	}
+
+	// Parse the policy
+	attempts := int64(RetryDefaultAttempts)
+	if attemptsTv, ok := spec.GetInputs()[RetryInputAttempts]; ok {
+		attempts, err = typedvalues.UnwrapInt64(attemptsTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format attempts to a number: %v", err)
+		}
+		if attempts < 1 {
+			return nil, fmt.Errorf("attempts needs to be at least 1, but was %d", attempts)
+		}
+	}
+
+	policy := backoff.ExponentialBackoff
+	if backoffTv, ok := spec.GetInputs()[RetryInputBackoff]; ok {
+		s, err := typedvalues.UnwrapString(backoffTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format backoff to a string: %v", err)
+		}
+		policy, ok = retryBackoffPolicies[s]
+		if !ok {
+			return nil, fmt.Errorf("unknown backoff policy '%s'", s)
+		}
+	}
+
+	delay := RetryDefaultDelay
+	if delayTv, ok := spec.GetInputs()[RetryInputDelay]; ok {
+		delay, err = unwrapDuration(delayTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse delay: %v", err)
+		}
+	}
+
+	maxDelay := RetryDefaultMaxDelay
+	if maxDelayTv, ok := spec.GetInputs()[RetryInputMaxDelay]; ok {
+		maxDelay, err = unwrapDuration(maxDelayTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse maxDelay: %v", err)
+		}
+	}
+
+	var retryOn *regexp.Regexp
+	if retryOnTv, ok := spec.GetInputs()[RetryInputRetryOn]; ok {
+		s, err := typedvalues.UnwrapString(retryOnTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format retryOn to a string: %v", err)
+		}
+		retryOn, err = regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retryOn expression: %v", err)
+		}
+	}
+
+	// Create the workflow once, so that all attempts share the same definition. The invocations of the attempts
+	// contain the workflow, so it is deleted once all attempts have been invoked.
+	wfID, err := fn.workflows.Create(wfSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow for action: %v", err)
+	}
+	defer deleteWorkflow(fn.workflows, wfID)
+
+	var lastErr error
+	for attempt := 0; int64(attempt) < attempts; attempt++ {
+		if attempt > 0 {
+			wait := policy(attempt-1, delay)
+			if wait > maxDelay {
+				wait = maxDelay
+			}
+			if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil && time.Now().Add(wait).After(deadline) {
+				return nil, fmt.Errorf("deadline exceeded before attempt %d: %v", attempt, lastErr)
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, fmt.Errorf("%v before attempt %d: %v", ctx.Err(), attempt, lastErr)
+			case <-timer.C:
+			}
+			retryAttempts.WithLabelValues(spec.GetWorkflowId(), spec.GetTaskId(), spec.GetFnRef().GetRuntime()).Inc()
+		}
+
+		logrus.Infof("[retry] attempt: %v (max %v)", attempt, attempts)
+		output, err := fn.invokeAttempt(ctx, spec, wfID, attempt)
+		if err == nil {
+			return output, nil
+		}
+		lastErr = err
+		if retryOn != nil && !retryOn.MatchString(err.Error()) {
+			return nil, fmt.Errorf("%v: %v", ErrRetryNotRetryable, err)
+		}
+	}
+	return nil, fmt.Errorf("all %d attempts failed, last error: %v", attempts, lastErr)
+}
+
+// invokeAttempt executes a single attempt of the action as a child invocation of the current invocation.
+func (fn *FunctionRetry) invokeAttempt(ctx context.Context, spec *types.TaskInvocationSpec, wfID string,
+	attempt int) (*typedvalues.TypedValue, error) {
+	wfi, err := fn.runtime.InvokeWorkflow(&types.WorkflowInvocationSpec{
+		WorkflowId: wfID,
+		ParentId:   spec.GetInvocationId(),
+		CallerId:   spec.GetInvocationId(),
+		Deadline:   spec.GetDeadline(),
+		Inputs: map[string]*typedvalues.TypedValue{
+			RetryInputAttempt: typedvalues.MustWrap(attempt),
+		},
+	}, fnenv.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	status := wfi.GetStatus().ToTaskStatus()
+	if status.GetStatus() != types.TaskInvocationStatus_SUCCEEDED {
+		if status.GetError() != nil {
+			return nil, errors.New(status.GetError().GetMessage())
+		}
+		return nil, fmt.Errorf("attempt %d did not succeed (status: %v)", attempt, status.GetStatus())
+	}
+	return status.GetOutput(), nil
+}
+
+// deleteWorkflow deletes the internal workflow of an action. Failing to do so does not affect the result of the action,
+// so the error is only logged.
+func deleteWorkflow(workflows WorkflowAPI, wfID string) {
+	if err := workflows.Delete(wfID); err != nil {
+		logrus.Warnf("Failed to delete workflow %s of action: %v", wfID, err)
+	}
+}
+
+// unwrapRetryAction parses the action into a workflow. Tasks and function references are wrapped into a
+// single-task workflow.
+func unwrapRetryAction(tv *typedvalues.TypedValue) (*types.WorkflowSpec, error) {
+	if controlflow.IsControlFlow(tv) {
+		flow, err := controlflow.UnwrapControlFlow(tv)
+		if err != nil {
+			return nil, err
+		}
+		if wf := flow.GetWorkflow(); wf != nil {
+			wf.Internal = true
+			return wf, nil
+		}
+	}
+
+	task, _, err := unwrapTaskOrFnRef(tv)
+	if err != nil {
+		return nil, err
+	}
+	task.Requires = map[string]*types.TaskDependencyParameters{}
+	return &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: retryAttemptTask,
+		Tasks: types.Tasks{
+			retryAttemptTask: task,
+		},
+		Internal: true,
+	}, nil
+}
+
+// unwrapDuration parses a typed value either from a duration string or from a number of milliseconds.
+func unwrapDuration(tv *typedvalues.TypedValue) (time.Duration, error) {
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return 0, err
+	}
+	switch t := i.(type) {
+	case string:
+		return time.ParseDuration(t)
+	case int32:
+		return time.Duration(t) * time.Millisecond, nil
+	case int64:
+		return time.Duration(t) * time.Millisecond, nil
+	// Floats are converted to nanoseconds before truncating them, to preserve fractional milliseconds.
+	case float32:
+		return time.Duration(float64(t) * float64(time.Millisecond)), nil
+	case float64:
+		return time.Duration(t * float64(time.Millisecond)), nil
+	default:
+		return 0, fmt.Errorf("invalid duration '%v'", tv.ValueType())
+	}
+}
@@ -0,0 +1,140 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockWorkflowAPI struct {
+	created []*types.WorkflowSpec
+	deleted []string
+}
+
+func (m *mockWorkflowAPI) Create(workflow *types.WorkflowSpec, opts ...api.CallOption) (string, error) {
+	m.created = append(m.created, workflow)
+	return "mockWorkflow", nil
+}
+
+func (m *mockWorkflowAPI) Delete(workflowID string) error {
+	m.deleted = append(m.deleted, workflowID)
+	return nil
+}
+
+// mockWorkflowRuntime fails the first n invocations with the provided error message.
+type mockWorkflowRuntime struct {
+	failures int
+	errMsg   string
+	invoked  []*types.WorkflowInvocationSpec
+}
+
+func (m *mockWorkflowRuntime) InvokeWorkflow(spec *types.WorkflowInvocationSpec,
+	opts ...fnenv.InvokeOption) (*types.WorkflowInvocation, error) {
+	m.invoked = append(m.invoked, spec)
+	status := &types.WorkflowInvocationStatus{
+		Status: types.WorkflowInvocationStatus_SUCCEEDED,
+		Output: typedvalues.MustWrap("ok"),
+	}
+	if len(m.invoked) <= m.failures {
+		status = &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_FAILED,
+			Error:  &types.Error{Message: m.errMsg},
+		}
+	}
+	return &types.WorkflowInvocation{
+		Spec:   spec,
+		Status: status,
+	}, nil
+}
+
+func TestFunctionRetry_Invoke(t *testing.T) {
+	creator := &mockWorkflowAPI{}
+	runtime := &mockWorkflowRuntime{failures: 2, errMsg: "timeout"}
+	out, err := NewFunctionRetry(creator, runtime).Invoke(&types.TaskInvocationSpec{
+		InvocationId: "parent",
+		WorkflowId:   "retryWorkflow",
+		TaskId:       "retryTask",
+		Inputs: map[string]*typedvalues.TypedValue{
+			RetryInputDo:      typedvalues.MustWrap("flaky"),
+			RetryInputBackoff: typedvalues.MustWrap(RetryBackoffFixed),
+			RetryInputDelay:   typedvalues.MustWrap("1ms"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", typedvalues.MustUnwrap(out))
+
+	assert.Len(t, creator.created, 1)
+	assert.Equal(t, "flaky", creator.created[0].Tasks[retryAttemptTask].FunctionRef)
+	assert.Equal(t, []string{"mockWorkflow"}, creator.deleted)
+	assert.Len(t, runtime.invoked, 3)
+	for i, attempt := range runtime.invoked {
+		assert.Equal(t, "parent", attempt.ParentId)
+		assert.EqualValues(t, i, typedvalues.MustUnwrap(attempt.Inputs[RetryInputAttempt]))
+	}
+	assert.EqualValues(t, 2, testutil.ToFloat64(retryAttempts.WithLabelValues("retryWorkflow", "retryTask", "")))
+}
+
+func TestFunctionRetry_InvokeExhausted(t *testing.T) {
+	runtime := &mockWorkflowRuntime{failures: 5, errMsg: "timeout"}
+	_, err := NewFunctionRetry(&mockWorkflowAPI{}, runtime).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RetryInputDo:       typedvalues.MustWrap(&types.TaskSpec{FunctionRef: "flaky"}),
+			RetryInputAttempts: typedvalues.MustWrap(2),
+			RetryInputDelay:    typedvalues.MustWrap(1),
+		},
+	})
+	assert.Error(t, err)
+	assert.Len(t, runtime.invoked, 2)
+}
+
+func TestFunctionRetry_InvokeNotRetryable(t *testing.T) {
+	runtime := &mockWorkflowRuntime{failures: 5, errMsg: "permission denied"}
+	_, err := NewFunctionRetry(&mockWorkflowAPI{}, runtime).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RetryInputDo:      typedvalues.MustWrap("flaky"),
+			RetryInputRetryOn: typedvalues.MustWrap("timeout|unavailable"),
+			RetryInputDelay:   typedvalues.MustWrap("1ms"),
+		},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrRetryNotRetryable.Error())
+	assert.Len(t, runtime.invoked, 1)
+}
+
+func TestFunctionRetry_InvokeCanceled(t *testing.T) {
+	creator := &mockWorkflowAPI{}
+	runtime := &mockWorkflowRuntime{failures: 1, errMsg: "timeout"}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewFunctionRetry(creator, runtime).InvokeContext(ctx, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			RetryInputDo:    typedvalues.MustWrap("flaky"),
+			RetryInputDelay: typedvalues.MustWrap("1m"),
+		},
+	})
+	assert.EqualError(t, err, "context deadline exceeded before attempt 1: timeout")
+	assert.True(t, time.Since(start) < time.Second, "retry did not stop waiting once the context was done")
+	assert.Len(t, runtime.invoked, 1)
+	assert.Equal(t, []string{"mockWorkflow"}, creator.deleted)
+}
+
+func TestUnwrapDuration(t *testing.T) {
+	for input, expected := range map[interface{}]time.Duration{
+		"1.5s":        1500 * time.Millisecond,
+		int64(10):     10 * time.Millisecond,
+		float64(1.5):  1500 * time.Microsecond,
+		float32(0.25): 250 * time.Microsecond,
+	} {
+		d, err := unwrapDuration(typedvalues.MustWrap(input))
+		assert.NoError(t, err, "%v", input)
+		assert.Equal(t, expected, d, "%v", input)
+	}
+}
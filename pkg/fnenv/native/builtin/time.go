@@ -0,0 +1,288 @@
+package builtin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	TimeNow                 = "time.now"
+	TimeFormat              = "time.format"
+	TimeAdd                 = "time.add"
+	TimeDuration            = "time.duration"
+	TimeInputTime           = "time"
+	TimeInputFormat         = "format"
+	TimeInputTimezone       = "timezone"
+	TimeInputDuration       = "duration"
+	TimeInputUnit           = "unit"
+	TimeFormatRFC3339       = "rfc3339"
+	TimeFormatRFC3339Nano   = "rfc3339nano"
+	TimeFormatRFC1123       = "rfc1123"
+	TimeFormatUnix          = "unix"
+	TimeFormatUnixMillis    = "unixms"
+	timeDefaultFormat       = TimeFormatRFC3339
+	timeDefaultDurationUnit = "ms"
+)
+
+var (
+	timeLayouts = map[string]string{
+		TimeFormatRFC3339:     time.RFC3339,
+		TimeFormatRFC3339Nano: time.RFC3339Nano,
+		TimeFormatRFC1123:     time.RFC1123,
+	}
+	timeDurationUnits = map[string]time.Duration{
+		"ns": time.Nanosecond,
+		"us": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+		"m":  time.Minute,
+		"h":  time.Hour,
+	}
+)
+
+/*
+FunctionTimeNow outputs the current time.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+format/default  | no       | string            | The format of the output (see below). (default: rfc3339)
+timezone        | no       | string            | The IANA timezone of the output, such as `Europe/Amsterdam`. (default: UTC)
+
+The format is either one of the named formats `rfc3339`, `rfc3339nano`, `rfc1123`, `unix` (seconds since epoch) and
+`unixms` (milliseconds since epoch), or a [Golang time layout](https://golang.org/pkg/time/#pkg-constants).
+
+**output** (string/number) The current time in the requested format.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: time.now
+  inputs:
+    format: unix
+# ...
+```
+
+A complete example of this function can be found in the [timewhale](../examples/whales/timewhale.wf.yaml) example.
+*/
+type FunctionTimeNow struct{}
+
+func (fn *FunctionTimeNow) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, formatTv := getFirstDefinedTypedValue(spec.GetInputs(), TimeInputFormat, types.InputMain)
+	return formatTimeInputs(time.Now(), formatTv, spec.GetInputs()[TimeInputTimezone])
+}
+
+/*
+FunctionTimeFormat formats a time in another format or timezone.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+time/default    | yes      | string/number     | The time to format, either as a RFC 3339 string or as a number of seconds since epoch.
+format          | no       | string            | The format of the output (see `time.now`). (default: rfc3339)
+timezone        | no       | string            | The IANA timezone of the output, such as `Europe/Amsterdam`. (default: UTC)
+
+**output** (string/number) The time in the requested format.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: time.format
+  inputs:
+    time: "2018-10-15T10:00:00Z"
+    format: "Mon Jan 2 15:04"
+    timezone: "America/New_York"
+# ...
+```
+
+A complete example of this function can be found in the [timewhale](../examples/whales/timewhale.wf.yaml) example.
+*/
+type FunctionTimeFormat struct{}
+
+func (fn *FunctionTimeFormat) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	t, err := parseTimeInput(spec)
+	if err != nil {
+		return nil, err
+	}
+	return formatTimeInputs(t, spec.GetInputs()[TimeInputFormat], spec.GetInputs()[TimeInputTimezone])
+}
+
+/*
+FunctionTimeAdd adds a duration to a time, which is for example useful to compute deadlines.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+time/default    | no       | string/number     | The time to add the duration to, either as a RFC 3339 string or as a number of seconds since epoch. (default: now)
+duration        | yes      | string/number     | The duration to add, either as a string (e.g. "1h30m" or "-10m") or as a number of milliseconds.
+format          | no       | string            | The format of the output (see `time.now`). (default: rfc3339)
+timezone        | no       | string            | The IANA timezone of the output, such as `Europe/Amsterdam`. (default: UTC)
+
+**output** (string/number) The resulting time in the requested format.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: time.add
+  inputs:
+    duration: 24h
+# ...
+```
+
+A complete example of this function can be found in the [timewhale](../examples/whales/timewhale.wf.yaml) example.
+*/
+type FunctionTimeAdd struct{}
+
+func (fn *FunctionTimeAdd) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	t := time.Now()
+	if _, tv := getFirstDefinedTypedValue(spec.GetInputs(), TimeInputTime, types.InputMain); tv != nil {
+		var err error
+		t, err = parseTimeInput(spec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	durationTv, err := ensureInput(spec.GetInputs(), TimeInputDuration)
+	if err != nil {
+		return nil, err
+	}
+	d, err := unwrapDuration(durationTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %v", err)
+	}
+	return formatTimeInputs(t.Add(d), spec.GetInputs()[TimeInputFormat], spec.GetInputs()[TimeInputTimezone])
+}
+
+/*
+FunctionTimeDuration parses a duration, and outputs it as a number in the requested unit.
+
+**Specification**
+
+**input**           | required | types             | description
+--------------------|----------|-------------------|--------------------------------------------------------
+duration/default    | yes      | string/number     | The duration, either as a string (e.g. "1h30m") or as a number of milliseconds.
+unit                | no       | string            | The unit of the output: `ns`, `us`, `ms`, `s`, `m`, or `h`. (default: ms)
+
+**output** (number) The duration in the requested unit.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: time.duration
+  inputs:
+    duration: 1h30m
+    unit: s
+# ...
+```
+
+A complete example of this function can be found in the [timewhale](../examples/whales/timewhale.wf.yaml) example.
+*/
+type FunctionTimeDuration struct{}
+
+func (fn *FunctionTimeDuration) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, durationTv := getFirstDefinedTypedValue(spec.GetInputs(), TimeInputDuration, types.InputMain)
+	if durationTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", TimeInputDuration)
+	}
+	d, err := unwrapDuration(durationTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse duration: %v", err)
+	}
+
+	unitName := timeDefaultDurationUnit
+	if unitTv, ok := spec.GetInputs()[TimeInputUnit]; ok {
+		unitName, err = typedvalues.UnwrapString(unitTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format unit to a string: %v", err)
+		}
+	}
+	unit, ok := timeDurationUnits[unitName]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit '%s'", unitName)
+	}
+	if d%unit == 0 {
+		return typedvalues.Wrap(int64(d / unit))
+	}
+	return typedvalues.Wrap(float64(d) / float64(unit))
+}
+
+// parseTimeInput parses the time input, which is either a RFC 3339 string or a number of seconds since epoch.
+func parseTimeInput(spec *types.TaskInvocationSpec) (time.Time, error) {
+	_, tv := getFirstDefinedTypedValue(spec.GetInputs(), TimeInputTime, types.InputMain)
+	if tv == nil {
+		return time.Time{}, fmt.Errorf("input '%s' is not set", TimeInputTime)
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch t := i.(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse time: %v", err)
+		}
+		return parsed, nil
+	case int32:
+		return time.Unix(int64(t), 0), nil
+	case int64:
+		return time.Unix(t, 0), nil
+	case float32:
+		return time.Unix(0, int64(float64(t)*float64(time.Second))), nil
+	case float64:
+		return time.Unix(0, int64(t*float64(time.Second))), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid time '%v'", tv.ValueType())
+	}
+}
+
+// formatTimeInputs formats the time according to the (optional) format and timezone inputs.
+func formatTimeInputs(t time.Time, formatTv *typedvalues.TypedValue,
+	timezoneTv *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+	t = t.UTC()
+	if timezoneTv != nil {
+		tz, err := typedvalues.UnwrapString(timezoneTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format timezone to a string: %v", err)
+		}
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone: %v", err)
+		}
+		t = t.In(loc)
+	}
+
+	format := timeDefaultFormat
+	if formatTv != nil {
+		var err error
+		format, err = typedvalues.UnwrapString(formatTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format format to a string: %v", err)
+		}
+	}
+	switch strings.ToLower(format) {
+	case TimeFormatUnix:
+		return typedvalues.Wrap(t.Unix())
+	case TimeFormatUnixMillis:
+		return typedvalues.Wrap(t.UnixNano() / int64(time.Millisecond))
+	}
+	if layout, ok := timeLayouts[strings.ToLower(format)]; ok {
+		format = layout
+	}
+	return typedvalues.Wrap(t.Format(format))
+}
@@ -0,0 +1,78 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionTimeNow_Invoke(t *testing.T) {
+	before := time.Now().Unix()
+	out, err := (&FunctionTimeNow{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: types.SingleInput(TimeInputFormat, typedvalues.MustWrap(TimeFormatUnix)),
+	})
+	assert.NoError(t, err)
+	now, err := typedvalues.UnwrapInt64(out)
+	assert.NoError(t, err)
+	assert.True(t, now >= before && now <= time.Now().Unix())
+
+	out, err = (&FunctionTimeNow{}).Invoke(&types.TaskInvocationSpec{})
+	assert.NoError(t, err)
+	_, err = time.Parse(time.RFC3339, typedvalues.MustUnwrap(out).(string))
+	assert.NoError(t, err)
+}
+
+func TestFunctionTimeFormat_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionTimeFormat{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeInputTime:     typedvalues.MustWrap("2018-10-15T10:00:00Z"),
+			TimeInputFormat:   typedvalues.MustWrap("2006-01-02 15:04"),
+			TimeInputTimezone: typedvalues.MustWrap("Europe/Amsterdam"),
+		},
+	}, "2018-10-15 12:00")
+
+	internalFunctionTest(t, &FunctionTimeFormat{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(types.InputMain, typedvalues.MustWrap(1539597600)),
+	}, "2018-10-15T10:00:00Z")
+}
+
+func TestFunctionTimeAdd_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionTimeAdd{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeInputTime:     typedvalues.MustWrap("2018-10-15T10:00:00Z"),
+			TimeInputDuration: typedvalues.MustWrap("-1h30m"),
+		},
+	}, "2018-10-15T08:30:00Z")
+
+	internalFunctionTest(t, &FunctionTimeAdd{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeInputTime:     typedvalues.MustWrap("2018-10-15T10:00:00Z"),
+			TimeInputDuration: typedvalues.MustWrap(1000),
+			TimeInputFormat:   typedvalues.MustWrap(TimeFormatUnixMillis),
+		},
+	}, int64(1539597601000))
+}
+
+func TestFunctionTimeDuration_Invoke(t *testing.T) {
+	internalFunctionTest(t, &FunctionTimeDuration{}, &types.TaskInvocationSpec{
+		Inputs: types.SingleInput(types.InputMain, typedvalues.MustWrap("1h30m")),
+	}, int64(5400000))
+
+	internalFunctionTest(t, &FunctionTimeDuration{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeInputDuration: typedvalues.MustWrap("90s"),
+			TimeInputUnit:     typedvalues.MustWrap("m"),
+		},
+	}, 1.5)
+
+	_, err := (&FunctionTimeDuration{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeInputDuration: typedvalues.MustWrap("90s"),
+			TimeInputUnit:     typedvalues.MustWrap("days"),
+		},
+	})
+	assert.Error(t, err)
+}
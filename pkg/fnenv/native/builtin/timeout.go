@@ -0,0 +1,178 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	Timeout               = "timeout"
+	TimeoutInputDo        = "do"
+	TimeoutInputAfter     = "after"
+	TimeoutInputInputs    = "inputs"
+	TimeoutOutputOutput   = "output"
+	TimeoutOutputTimedOut = "timedOut"
+)
+
+/*
+FunctionTimeout is a control flow construct that executes a task or workflow, and interrupts it if it has not completed
+within the given duration. The action is executed as a separate (child) workflow invocation with a deadline, so that an
+interrupted action does not fail the workflow invocation itself. Instead, the output indicates whether the action timed
+out, which allows subsequent tasks to handle the timeout, such as by running only if the action timed out.
+
+**Specification**
+
+**input**       | required | types                | description
+----------------|----------|----------------------|--------------------------------------------------------
+do              | yes      | string/task/workflow | The function reference, task or workflow to execute.
+after           | yes      | string/number        | The duration after which the action is interrupted.
+inputs          | no       | map                  | The inputs of the invocation of the action.
+
+Durations can either be provided as a string (e.g. "1s") or as a number of milliseconds.
+
+**output** (map) `timedOut` indicates whether the action was interrupted, and `output` contains the output of the action
+if it completed in time.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: timeout
+  inputs:
+    after: 30s
+    inputs:
+      order: "{ $.Invocation.Inputs.order }"
+    do:
+      run: slowfunction
+      inputs: "{ $.Invocation.Inputs.order }"
+# ...
+```
+*/
+type FunctionTimeout struct {
+	workflows WorkflowAPI
+	runtime   WorkflowRuntime
+}
+
+func NewFunctionTimeout(workflows WorkflowAPI, runtime WorkflowRuntime) *FunctionTimeout {
+	return &FunctionTimeout{
+		workflows: workflows,
+		runtime:   runtime,
+	}
+}
+
+func (fn *FunctionTimeout) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	// Parse the action
+	actionTv, err := ensureInput(spec.GetInputs(), TimeoutInputDo)
+	if err != nil {
+		return nil, err
+	}
+	wfSpec, err := unwrapRetryAction(actionTv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid action: %v", err)
+	}
+
+	afterTv, err := ensureInput(spec.GetInputs(), TimeoutInputAfter)
+	if err != nil {
+		return nil, err
+	}
+	after, err := unwrapDuration(afterTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse after: %v", err)
+	}
+	if after <= 0 {
+		return nil, fmt.Errorf("after needs to be positive, but was %v", after)
+	}
+
+	var inputs map[string]*typedvalues.TypedValue
+	if inputsTv, ok := spec.GetInputs()[TimeoutInputInputs]; ok {
+		i, err := typedvalues.Unwrap(inputsTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inputs: %v", err)
+		}
+		m, ok := i.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("inputs should be a map, but was %T", i)
+		}
+		inputs, err = typedvalues.WrapMapTypedValue(m)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse inputs: %v", err)
+		}
+	}
+
+	// The action is interrupted at the deadline of the timeout, unless the task itself has an earlier deadline.
+	deadline := time.Now().Add(after)
+	interruptible := true
+	if taskDeadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil && taskDeadline.Before(deadline) {
+		deadline = taskDeadline
+		interruptible = false
+	}
+	deadlineTs, err := ptypes.TimestampProto(deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	wfID, err := fn.workflows.Create(wfSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workflow for action: %v", err)
+	}
+	// The workflow of the action is created just now, so the invocation awaits it to be ready. The invocation contains
+	// the workflow, so the workflow is no longer needed once it has been invoked.
+	wfi, err := fn.runtime.InvokeWorkflow(&types.WorkflowInvocationSpec{
+		WorkflowId: wfID,
+		ParentId:   spec.GetInvocationId(),
+		CallerId:   spec.GetInvocationId(),
+		Deadline:   deadlineTs,
+		Inputs:     inputs,
+	}, fnenv.AwaitWorkflow(time.Until(deadline)))
+	deleteWorkflow(fn.workflows, wfID)
+	if err == nil && wfi.GetStatus().Successful() {
+		output, err := typedvalues.Unwrap(wfi.GetStatus().GetOutput())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse output of action: %v", err)
+		}
+		return typedvalues.Wrap(map[string]interface{}{
+			TimeoutOutputTimedOut: false,
+			TimeoutOutputOutput:   output,
+		})
+	}
+
+	// An action that has not completed at the deadline is either canceled or failed, depending on which one notices
+	// the deadline first. Other failures of the action are returned as is, even if they occur after the deadline.
+	if interruptible && !time.Now().Before(deadline) && interruptedByDeadline(wfi, err) {
+		logrus.Infof("[timeout] action of task %s timed out after %v", spec.GetTaskId(), after)
+		return typedvalues.Wrap(map[string]interface{}{
+			TimeoutOutputTimedOut: true,
+			TimeoutOutputOutput:   nil,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if wfi.GetStatus().GetError() != nil {
+		return nil, errors.New(wfi.GetStatus().GetError().GetMessage())
+	}
+	return nil, fmt.Errorf("action did not succeed (status: %v)", wfi.GetStatus().GetStatus())
+}
+
+// interruptedByDeadline returns whether the action failed because it exceeded its deadline: the runtime cancels the
+// invocation once the deadline has passed, the controller fails the invocation if it notices the deadline first, and
+// the tasks of the invocation fail with a context deadline error if their function notices it first.
+func interruptedByDeadline(wfi *types.WorkflowInvocation, err error) bool {
+	msg := wfi.GetStatus().GetError().GetMessage()
+	if err != nil {
+		msg = err.Error()
+	}
+	return msg == api.ErrInvocationCanceled || msg == api.ErrInvocationDeadlineExceeded ||
+		strings.Contains(msg, context.DeadlineExceeded.Error())
+}
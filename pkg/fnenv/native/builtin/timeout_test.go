@@ -0,0 +1,129 @@
+package builtin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSlowWorkflowRuntime completes invocations after the duration, or fails them once their deadline is exceeded.
+// The error message of failed invocations defaults to the one of the controller.
+type mockSlowWorkflowRuntime struct {
+	duration time.Duration
+	errMsg   string
+	invoked  []*types.WorkflowInvocationSpec
+}
+
+func (m *mockSlowWorkflowRuntime) InvokeWorkflow(spec *types.WorkflowInvocationSpec,
+	opts ...fnenv.InvokeOption) (*types.WorkflowInvocation, error) {
+	m.invoked = append(m.invoked, spec)
+	status := &types.WorkflowInvocationStatus{
+		Status: types.WorkflowInvocationStatus_SUCCEEDED,
+		Output: typedvalues.MustWrap("ok"),
+	}
+	deadline, _ := ptypes.Timestamp(spec.GetDeadline())
+	if time.Now().Add(m.duration).After(deadline) {
+		time.Sleep(time.Until(deadline))
+		errMsg := m.errMsg
+		if len(errMsg) == 0 {
+			errMsg = api.ErrInvocationDeadlineExceeded
+		}
+		status = &types.WorkflowInvocationStatus{
+			Status: types.WorkflowInvocationStatus_FAILED,
+			Error:  &types.Error{Message: errMsg},
+		}
+	} else {
+		time.Sleep(m.duration)
+	}
+	return &types.WorkflowInvocation{
+		Spec:   spec,
+		Status: status,
+	}, nil
+}
+
+func TestFunctionTimeout_Invoke(t *testing.T) {
+	creator := &mockWorkflowAPI{}
+	runtime := &mockSlowWorkflowRuntime{}
+	out, err := NewFunctionTimeout(creator, runtime).Invoke(&types.TaskInvocationSpec{
+		InvocationId: "parent",
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeoutInputDo:     typedvalues.MustWrap("slow"),
+			TimeoutInputAfter:  typedvalues.MustWrap("1s"),
+			TimeoutInputInputs: typedvalues.MustWrap(map[string]interface{}{"foo": "bar"}),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		TimeoutOutputTimedOut: false,
+		TimeoutOutputOutput:   "ok",
+	}, typedvalues.MustUnwrap(out))
+
+	assert.Len(t, creator.created, 1)
+	assert.Equal(t, "slow", creator.created[0].Tasks[retryAttemptTask].FunctionRef)
+	assert.Equal(t, []string{"mockWorkflow"}, creator.deleted)
+	assert.Len(t, runtime.invoked, 1)
+	assert.Equal(t, "parent", runtime.invoked[0].ParentId)
+	assert.Equal(t, "bar", typedvalues.MustUnwrap(runtime.invoked[0].Inputs["foo"]))
+}
+
+func TestFunctionTimeout_InvokeTimedOut(t *testing.T) {
+	runtime := &mockSlowWorkflowRuntime{duration: time.Second}
+	out, err := NewFunctionTimeout(&mockWorkflowAPI{}, runtime).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeoutInputDo:    typedvalues.MustWrap(&types.TaskSpec{FunctionRef: "slow"}),
+			TimeoutInputAfter: typedvalues.MustWrap(10),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		TimeoutOutputTimedOut: true,
+		TimeoutOutputOutput:   nil,
+	}, typedvalues.MustUnwrap(out))
+}
+
+func TestFunctionTimeout_InvokeFailedAfterDeadline(t *testing.T) {
+	// Failures of the action that are not caused by the deadline are not reported as a timeout.
+	runtime := &mockSlowWorkflowRuntime{duration: time.Second, errMsg: "connection refused"}
+	_, err := NewFunctionTimeout(&mockWorkflowAPI{}, runtime).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeoutInputDo:    typedvalues.MustWrap("slow"),
+			TimeoutInputAfter: typedvalues.MustWrap(10),
+		},
+	})
+	assert.EqualError(t, err, "connection refused")
+}
+
+func TestFunctionTimeout_InvokeTaskDeadline(t *testing.T) {
+	// If the deadline of the task precedes the timeout, exceeding it fails the task instead.
+	deadline, _ := ptypes.TimestampProto(time.Now().Add(10 * time.Millisecond))
+	runtime := &mockSlowWorkflowRuntime{duration: time.Second}
+	_, err := NewFunctionTimeout(&mockWorkflowAPI{}, runtime).Invoke(&types.TaskInvocationSpec{
+		Deadline: deadline,
+		Inputs: map[string]*typedvalues.TypedValue{
+			TimeoutInputDo:    typedvalues.MustWrap("slow"),
+			TimeoutInputAfter: typedvalues.MustWrap("1m"),
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestFunctionTimeout_InvokeInvalid(t *testing.T) {
+	fn := NewFunctionTimeout(&mockWorkflowAPI{}, &mockSlowWorkflowRuntime{})
+	for name, inputs := range map[string]map[string]interface{}{
+		"no action":      {TimeoutInputAfter: "1s"},
+		"no duration":    {TimeoutInputDo: "slow"},
+		"invalid after":  {TimeoutInputDo: "slow", TimeoutInputAfter: "-1s"},
+		"invalid inputs": {TimeoutInputDo: "slow", TimeoutInputAfter: "1s", TimeoutInputInputs: "foo"},
+	} {
+		tvs, err := typedvalues.WrapMapTypedValue(inputs)
+		assert.NoError(t, err)
+		_, err = fn.Invoke(&types.TaskInvocationSpec{Inputs: tvs})
+		assert.Error(t, err, name)
+	}
+}
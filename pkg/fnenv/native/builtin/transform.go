@@ -0,0 +1,170 @@
+package builtin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/itchyny/gojq"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	Transform              = "transform"
+	TransformInputValue    = "value"
+	TransformInputJSONPath = "jsonpath"
+	TransformInputJq       = "jq"
+)
+
+/*
+FunctionTransform applies a JSONPath or jq expression to the provided value and outputs the result.
+It is intended for simple data reshaping between tasks, without needing to write a JavaScript expression or to deploy
+a dedicated function.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+value/default   | yes      | *                 | The value to transform.
+jsonpath        | no       | string            | The JSONPath expression to apply to the value.
+jq              | no       | string            | The jq expression to apply to the value.
+
+Exactly one of `jsonpath` or `jq` needs to be provided.
+The JSONPath expression follows the [Kubernetes JSONPath](https://kubernetes.io/docs/reference/kubectl/jsonpath/)
+notation, without the surrounding curly braces, which are reserved for workflow expressions. For the same reason a jq
+expression cannot start with a curly brace; prefix such expressions with `. | ` instead.
+
+**output** (*) The result of the expression. If the expression yields multiple results, the output is a list of the
+results.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: transform
+  inputs:
+    value:
+      users:
+      - name: alice
+        age: 21
+      - name: bob
+        age: 42
+    jq: "[.users[] | select(.age > 30) | .name]"
+# ...
+```
+
+A complete example of this function can be found in the [transformwhale](../examples/whales/transformwhale.wf.yaml)
+example.
+*/
+type FunctionTransform struct{}
+
+func (fn *FunctionTransform) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, valueTv := getFirstDefinedTypedValue(spec.GetInputs(), TransformInputValue, types.InputMain)
+	if valueTv == nil {
+		return nil, fmt.Errorf("input '%s' is not set", TransformInputValue)
+	}
+	value, err := normalizeJSON(valueTv)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonPathTv, hasJSONPath := spec.GetInputs()[TransformInputJSONPath]
+	jqTv, hasJq := spec.GetInputs()[TransformInputJq]
+	if hasJSONPath == hasJq {
+		return nil, fmt.Errorf("exactly one of '%s' or '%s' needs to be provided", TransformInputJSONPath,
+			TransformInputJq)
+	}
+
+	var results []interface{}
+	if hasJSONPath {
+		expr, err := typedvalues.UnwrapString(jsonPathTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format jsonpath to a string: %v", err)
+		}
+		results, err = evalJSONPath(expr, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate jsonpath '%s': %v", expr, err)
+		}
+	} else {
+		expr, err := typedvalues.UnwrapString(jqTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format jq to a string: %v", err)
+		}
+		results, err = evalJq(expr, value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate jq '%s': %v", expr, err)
+		}
+	}
+
+	switch len(results) {
+	case 0:
+		return nil, nil
+	case 1:
+		return typedvalues.Wrap(results[0])
+	default:
+		return typedvalues.Wrap(results)
+	}
+}
+
+func evalJSONPath(expr string, value interface{}) ([]interface{}, error) {
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New(Transform)
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+	found, err := jp.FindResults(value)
+	if err != nil {
+		return nil, err
+	}
+	var results []interface{}
+	for _, values := range found {
+		for _, v := range values {
+			results = append(results, v.Interface())
+		}
+	}
+	return results, nil
+}
+
+func evalJq(expr string, value interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	var results []interface{}
+	iter := query.Run(value)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			return nil, err
+		}
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+// normalizeJSON unwraps the typed value into the generic JSON representation (maps, lists, float64s, strings, bools,
+// and nils) that the JSONPath and jq evaluators operate on.
+func normalizeJSON(tv *typedvalues.TypedValue) (interface{}, error) {
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return nil, errors.New("value cannot be represented as JSON: " + err.Error())
+	}
+	var value interface{}
+	if err := json.Unmarshal(bs, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
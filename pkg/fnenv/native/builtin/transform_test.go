@@ -0,0 +1,58 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+var transformTestValue = map[string]interface{}{
+	"users": []interface{}{
+		map[string]interface{}{"name": "alice", "age": 21},
+		map[string]interface{}{"name": "bob", "age": 42},
+	},
+}
+
+func TestFunctionTransform_InvokeJSONPath(t *testing.T) {
+	internalFunctionTest(t, &FunctionTransform{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TransformInputValue:    typedvalues.MustWrap(transformTestValue),
+			TransformInputJSONPath: typedvalues.MustWrap(".users[1].name"),
+		},
+	}, "bob")
+
+	internalFunctionTest(t, &FunctionTransform{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			types.InputMain:        typedvalues.MustWrap(transformTestValue),
+			TransformInputJSONPath: typedvalues.MustWrap("$.users[*].name"),
+		},
+	}, []interface{}{"alice", "bob"})
+}
+
+func TestFunctionTransform_InvokeJq(t *testing.T) {
+	internalFunctionTest(t, &FunctionTransform{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TransformInputValue: typedvalues.MustWrap(transformTestValue),
+			TransformInputJq:    typedvalues.MustWrap("[.users[] | select(.age > 30) | .name]"),
+		},
+	}, []interface{}{"bob"})
+}
+
+func TestFunctionTransform_InvokeInvalid(t *testing.T) {
+	_, err := (&FunctionTransform{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TransformInputValue: typedvalues.MustWrap(transformTestValue),
+		},
+	})
+	assert.Error(t, err)
+
+	_, err = (&FunctionTransform{}).Invoke(&types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			TransformInputValue: typedvalues.MustWrap(transformTestValue),
+			TransformInputJq:    typedvalues.MustWrap(".users[] | error(\"boom\")"),
+		},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,68 @@
+package builtin
+
+import (
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	UUID               = "uuid"
+	UUIDInputName      = "name"
+	UUIDInputNamespace = "namespace"
+)
+
+/*
+FunctionUUID generates a UUID, which is useful for generating (idempotency) keys or identifiers.
+By default, a random (version 4) UUID is generated. If a name is provided, a deterministic (version 5) UUID is
+generated based on the name and namespace instead; the same name and namespace always result in the same UUID.
+
+**Specification**
+
+**input**       | required | types             | description
+----------------|----------|-------------------|--------------------------------------------------------
+name/default    | no       | string            | The name to generate a deterministic UUID for.
+namespace       | no       | string            | The UUID of the namespace of the name. (default: the URL namespace)
+
+**output** (string) The generated UUID.
+
+**Example**
+
+```yaml
+# ...
+foo:
+  run: uuid
+  inputs:
+    name: "{ $.Invocation.Id }"
+# ...
+```
+
+A complete example of this function can be found in the [randomwhale](../examples/whales/randomwhale.wf.yaml) example.
+*/
+type FunctionUUID struct{}
+
+func (fn *FunctionUUID) Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error) {
+	_, nameTv := getFirstDefinedTypedValue(spec.GetInputs(), UUIDInputName, types.InputMain)
+	if nameTv == nil {
+		return typedvalues.Wrap(uuid.NewV4().String())
+	}
+	name, err := typedvalues.UnwrapString(nameTv)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format name to a string: %v", err)
+	}
+
+	namespace := uuid.NamespaceURL
+	if namespaceTv, ok := spec.GetInputs()[UUIDInputNamespace]; ok {
+		s, err := typedvalues.UnwrapString(namespaceTv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format namespace to a string: %v", err)
+		}
+		namespace, err = uuid.FromString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace: %v", err)
+		}
+	}
+	return typedvalues.Wrap(uuid.NewV5(namespace, name).String())
+}
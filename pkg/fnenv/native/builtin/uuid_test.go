@@ -0,0 +1,31 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFunctionUUID_Invoke(t *testing.T) {
+	out, err := (&FunctionUUID{}).Invoke(&types.TaskInvocationSpec{})
+	assert.NoError(t, err)
+	id, err := uuid.FromString(typedvalues.MustUnwrap(out).(string))
+	assert.NoError(t, err)
+	assert.EqualValues(t, uuid.V4, id.Version())
+
+	out2, err := (&FunctionUUID{}).Invoke(&types.TaskInvocationSpec{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, typedvalues.MustUnwrap(out), typedvalues.MustUnwrap(out2))
+}
+
+func TestFunctionUUID_InvokeDeterministic(t *testing.T) {
+	internalFunctionTest(t, &FunctionUUID{}, &types.TaskInvocationSpec{
+		Inputs: map[string]*typedvalues.TypedValue{
+			UUIDInputName:      typedvalues.MustWrap("www.example.com"),
+			UUIDInputNamespace: typedvalues.MustWrap(uuid.NamespaceDNS.String()),
+		},
+	}, "2ed6657d-e927-568b-95e1-2665a8aea6a2")
+}
@@ -2,6 +2,7 @@
 package native
 
 import (
+	"context"
 	"fmt"
 	"runtime/debug"
 	"time"
@@ -25,6 +26,13 @@ type InternalFunction interface {
 	Invoke(spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error)
 }
 
+// A ContextFunction is an InternalFunction that stops once the context of the task invocation is done, such as when
+// its deadline has passed or its invocation has been canceled.
+type ContextFunction interface {
+	InternalFunction
+	InvokeContext(ctx context.Context, spec *types.TaskInvocationSpec) (*typedvalues.TypedValue, error)
+}
+
 // FunctionEnv for executing low overhead functions, such as control flow constructs, inside the workflow engine
 //
 // Note: This currently supports Golang only.
@@ -54,7 +62,9 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	}
 
 	timeStart := time.Now()
-	defer fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart)))
+	defer func() {
+		fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart) / time.Millisecond))
+	}()
 	fnID := spec.FnRef.ID
 	fn, ok := fe.fns[fnID]
 	if !ok {
@@ -63,7 +73,13 @@ func (fe *FunctionEnv) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.Invo
 	span, _ := opentracing.StartSpanFromContext(cfg.Ctx, fmt.Sprintf("/fnenv/internal/%s", fnID))
 	defer span.Finish()
 	fnenv.FnActive.WithLabelValues(Name).Inc()
-	out, err := fn.Invoke(spec)
+	var out *typedvalues.TypedValue
+	var err error
+	if ctxFn, ok := fn.(ContextFunction); ok {
+		out, err = ctxFn.InvokeContext(cfg.Ctx, spec)
+	} else {
+		out, err = fn.Invoke(spec)
+	}
 	fnenv.FnActive.WithLabelValues(Name).Dec()
 	fnenv.FnCount.WithLabelValues(Name).Inc()
 	if err != nil {
@@ -0,0 +1,192 @@
+package fnenv
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMaxPayloadSize is the default max_payload of NATS.
+const DefaultMaxPayloadSize = 1024 * 1024
+
+// ErrPayloadTooLarge is returned for inputs and outputs that exceed the MaxPayloadSize.
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// MaxPayloadSize is the maximum size (in bytes) of the inputs of an invocation and of the output of a task. The
+// values are stored in the event store, which fails on messages above its own limit (such as the max_payload of NATS)
+// long after the value was accepted. If 0, the size is not limited.
+//
+// The size of a value that has been offloaded to the blob store is the size of its reference.
+var MaxPayloadSize int64
+
+var rejectedPayloads = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "fnenv",
+	Name:      "payload_rejected_total",
+	Help:      "Total number of invocation inputs and task outputs that exceeded the maximum payload size",
+})
+
+func init() {
+	prometheus.MustRegister(rejectedPayloads)
+}
+
+// CheckPayloadSize returns an error wrapping ErrPayloadTooLarge if the total size of the values exceeds the
+// MaxPayloadSize.
+func CheckPayloadSize(subject string, values ...*typedvalues.TypedValue) error {
+	if MaxPayloadSize <= 0 {
+		return nil
+	}
+	var size int64
+	for _, tv := range values {
+		if tv != nil {
+			size += int64(proto.Size(tv))
+		}
+	}
+	if size > MaxPayloadSize {
+		rejectedPayloads.Inc()
+		return fmt.Errorf("%v: %s of %d bytes exceeds the maximum payload size of %d bytes", ErrPayloadTooLarge,
+			subject, size, MaxPayloadSize)
+	}
+	return nil
+}
+
+// CheckPayloadSizes is the CheckPayloadSize of a map of values, such as the inputs of an invocation or task.
+func CheckPayloadSizes(subject string, values map[string]*typedvalues.TypedValue) error {
+	tvs := make([]*typedvalues.TypedValue, 0, len(values))
+	for _, tv := range values {
+		tvs = append(tvs, tv)
+	}
+	return CheckPayloadSize(subject, tvs...)
+}
+
+// OutputPolicy determines how the outputs of tasks that exceed the MaxOutputSize are handled.
+type OutputPolicy string
+
+const (
+	// OutputPolicyReject fails the task with an error that states the size of the output.
+	OutputPolicyReject OutputPolicy = "reject"
+
+	// OutputPolicyTruncate keeps the first MaxOutputSize bytes of string and bytes outputs, and marks the output with
+	// the MetadataTruncated metadata. Other outputs are rejected.
+	OutputPolicyTruncate OutputPolicy = "truncate"
+
+	// OutputPolicyOffload stores the output in the blob store, regardless of the offload threshold. Without a blob
+	// store, the output is rejected.
+	OutputPolicyOffload OutputPolicy = "offload"
+)
+
+// MetadataTruncated is the metadata key of truncated outputs, which holds the original size (in bytes) of the output.
+const MetadataTruncated = "truncated"
+
+// ErrOutputTooLarge is returned for task outputs that exceed the MaxOutputSize, and cannot be truncated or offloaded.
+var ErrOutputTooLarge = errors.New("output too large")
+
+// MaxOutputSize is the maximum size (in bytes) of the output of a task, before the output is offloaded. Outputs that
+// exceed it are handled according to the OutputSizePolicy. If 0, the output is only limited by the MaxPayloadSize.
+var MaxOutputSize int64
+
+// OutputSizePolicy is the policy for the outputs that exceed the MaxOutputSize.
+var OutputSizePolicy = OutputPolicyReject
+
+var limitedOutputs = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "fnenv",
+	Name:      "outputs_limited_total",
+	Help:      "Total number of task outputs that exceeded the maximum output size, labeled by the applied policy",
+}, []string{"policy"})
+
+func init() {
+	prometheus.MustRegister(limitedOutputs)
+}
+
+// ParseOutputPolicy parses the name of an output policy.
+func ParseOutputPolicy(s string) (OutputPolicy, error) {
+	switch policy := OutputPolicy(s); policy {
+	case OutputPolicyReject, OutputPolicyTruncate, OutputPolicyOffload:
+		return policy, nil
+	case "":
+		return OutputPolicyReject, nil
+	default:
+		return "", fmt.Errorf("unknown output size policy %q (expected reject, truncate or offload)", s)
+	}
+}
+
+// LimitOutput applies the OutputSizePolicy to the output of a task if it exceeds the MaxOutputSize, and returns the
+// output that should be stored. The offload function stores a value in the blob store; if it is nil, outputs cannot be
+// offloaded.
+func LimitOutput(output *typedvalues.TypedValue,
+	offload func(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error)) (*typedvalues.TypedValue, error) {
+	if MaxOutputSize <= 0 || output == nil || typedvalues.IsReference(output) {
+		return output, nil
+	}
+	size := int64(proto.Size(output))
+	if size <= MaxOutputSize {
+		return output, nil
+	}
+	tooLarge := fmt.Errorf("%v: output of %d bytes exceeds the maximum output size of %d bytes", ErrOutputTooLarge,
+		size, MaxOutputSize)
+
+	switch OutputSizePolicy {
+	case OutputPolicyTruncate:
+		truncated, err := truncate(output, MaxOutputSize)
+		if err != nil {
+			return nil, fmt.Errorf("%v; %v", tooLarge, err)
+		}
+		truncated.SetMetadata(MetadataTruncated, fmt.Sprintf("%d", size))
+		limitedOutputs.WithLabelValues(string(OutputPolicyTruncate)).Inc()
+		return truncated, nil
+	case OutputPolicyOffload:
+		if offload == nil {
+			return nil, fmt.Errorf("%v; it cannot be offloaded without a blob store", tooLarge)
+		}
+		ref, err := offload(output)
+		if err != nil {
+			return nil, fmt.Errorf("%v; failed to offload it: %v", tooLarge, err)
+		}
+		limitedOutputs.WithLabelValues(string(OutputPolicyOffload)).Inc()
+		return ref, nil
+	default:
+		limitedOutputs.WithLabelValues(string(OutputPolicyReject)).Inc()
+		return nil, tooLarge
+	}
+}
+
+// truncate returns the first max bytes of a string or bytes value, keeping the metadata of the value. Strings are
+// truncated at a character boundary.
+func truncate(tv *typedvalues.TypedValue, max int64) (*typedvalues.TypedValue, error) {
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return nil, err
+	}
+	var truncated *typedvalues.TypedValue
+	switch v := i.(type) {
+	case string:
+		n := int(max)
+		if n > len(v) {
+			n = len(v)
+		}
+		for n > 0 && n < len(v) && !utf8.RuneStart(v[n]) {
+			n--
+		}
+		truncated, err = typedvalues.Wrap(v[:n])
+	case []byte:
+		n := int(max)
+		if n > len(v) {
+			n = len(v)
+		}
+		truncated, err = typedvalues.Wrap(v[:n])
+	default:
+		return nil, fmt.Errorf("only string and bytes outputs can be truncated, not %s", tv.ValueType())
+	}
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range tv.GetMetadata() {
+		truncated.SetMetadata(k, v)
+	}
+	return truncated, nil
+}
@@ -0,0 +1,99 @@
+package fnenv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPayloadSize(t *testing.T) {
+	defer func(max int64) { MaxPayloadSize = max }(MaxPayloadSize)
+	small := typedvalues.MustWrap("small")
+	large := typedvalues.MustWrap(strings.Repeat("a", 100))
+
+	MaxPayloadSize = 0
+	assert.NoError(t, CheckPayloadSize("output", large))
+
+	MaxPayloadSize = 64
+	assert.NoError(t, CheckPayloadSize("output", small, nil))
+	err := CheckPayloadSize("output", large)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrPayloadTooLarge.Error())
+
+	// The size of the inputs is the total size of all inputs.
+	inputs := map[string]*typedvalues.TypedValue{}
+	for i := 0; i < 10; i++ {
+		inputs[fmt.Sprintf("input%d", i)] = small
+	}
+	assert.Error(t, CheckPayloadSizes("inputs", inputs))
+}
+
+func TestLimitOutput(t *testing.T) {
+	defer func(max int64, policy OutputPolicy) {
+		MaxOutputSize, OutputSizePolicy = max, policy
+	}(MaxOutputSize, OutputSizePolicy)
+	small := typedvalues.MustWrap("small")
+	large := typedvalues.MustWrap(strings.Repeat("ä", 100))
+	large.SetMetadata("key", "value")
+	var offloaded []*typedvalues.TypedValue
+	offload := func(tv *typedvalues.TypedValue) (*typedvalues.TypedValue, error) {
+		offloaded = append(offloaded, tv)
+		return typedvalues.MustWrap(&typedvalues.Reference{Uri: "mem://42"}), nil
+	}
+
+	MaxOutputSize = 0
+	output, err := LimitOutput(large, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, large, output)
+
+	// Outputs within the limit are never modified.
+	MaxOutputSize = 64
+	for _, policy := range []OutputPolicy{OutputPolicyReject, OutputPolicyTruncate, OutputPolicyOffload} {
+		OutputSizePolicy = policy
+		output, err := LimitOutput(small, offload)
+		assert.NoError(t, err)
+		assert.Equal(t, small, output)
+	}
+	assert.Empty(t, offloaded)
+
+	OutputSizePolicy = OutputPolicyReject
+	_, err = LimitOutput(large, offload)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrOutputTooLarge.Error())
+
+	// Strings are truncated at a character boundary, with a marker of the original size.
+	OutputSizePolicy = OutputPolicyTruncate
+	output, err = LimitOutput(large, offload)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("ä", 32), typedvalues.MustUnwrap(output))
+	assert.Equal(t, fmt.Sprintf("%d", proto.Size(large)), output.GetMetadata()[MetadataTruncated])
+	assert.Equal(t, "value", output.GetMetadata()["key"])
+	output, err = LimitOutput(typedvalues.MustWrap([]byte(strings.Repeat("a", 100))), offload)
+	assert.NoError(t, err)
+	assert.Len(t, typedvalues.MustUnwrap(output), 64)
+	_, err = LimitOutput(typedvalues.MustWrap(map[string]interface{}{"key": strings.Repeat("a", 100)}), offload)
+	assert.Error(t, err)
+
+	OutputSizePolicy = OutputPolicyOffload
+	output, err = LimitOutput(large, offload)
+	assert.NoError(t, err)
+	assert.True(t, typedvalues.IsReference(output))
+	assert.Equal(t, []*typedvalues.TypedValue{large}, offloaded)
+	_, err = LimitOutput(large, nil)
+	assert.Error(t, err)
+}
+
+func TestParseOutputPolicy(t *testing.T) {
+	policy, err := ParseOutputPolicy("truncate")
+	assert.NoError(t, err)
+	assert.Equal(t, OutputPolicyTruncate, policy)
+	policy, err = ParseOutputPolicy("")
+	assert.NoError(t, err)
+	assert.Equal(t, OutputPolicyReject, policy)
+	_, err = ParseOutputPolicy("drop")
+	assert.Error(t, err)
+}
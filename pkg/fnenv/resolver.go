@@ -2,6 +2,7 @@ package fnenv
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -38,8 +39,9 @@ func init() {
 //   for scheduling (overhead vs. load)
 //
 type MetaResolver struct {
-	clients map[string]RuntimeResolver
-	timeout time.Duration
+	clients   map[string]RuntimeResolver
+	timeout   time.Duration
+	namespace string
 }
 
 func NewMetaResolver(client map[string]RuntimeResolver) *MetaResolver {
@@ -49,8 +51,16 @@ func NewMetaResolver(client map[string]RuntimeResolver) *MetaResolver {
 	}
 }
 
+// InNamespace returns a copy of the resolver that resolves function references without a namespace in the
+// namespace, for the runtimes that are NamespaceScoped.
+func (ps *MetaResolver) InNamespace(namespace string) Resolver {
+	scoped := *ps
+	scoped.namespace = namespace
+	return &scoped
+}
+
 func (ps *MetaResolver) Resolve(targetFn string) (types.FnRef, error) {
-	ref, err := types.ParseFnRef(targetFn)
+	ref, err := ps.parseFnRef(targetFn)
 	if err != nil {
 		return types.FnRef{}, err
 	}
@@ -90,11 +100,24 @@ func (ps *MetaResolver) Resolve(targetFn string) (types.FnRef, error) {
 	}
 }
 
+// parseFnRef parses the function reference, using the parser of the runtime of the reference if it has one.
+func (ps *MetaResolver) parseFnRef(targetFn string) (types.FnRef, error) {
+	if i := strings.Index(targetFn, "://"); i > 0 {
+		if parser, ok := ps.clients[targetFn[:i]].(RefParser); ok {
+			return parser.ParseFnRef(targetFn)
+		}
+	}
+	return types.ParseFnRef(targetFn)
+}
+
 func (ps *MetaResolver) resolveForRuntime(runtime string, ref types.FnRef) (types.FnRef, error) {
 	dst, ok := ps.clients[runtime]
 	if !ok {
 		return types.FnRef{}, ErrInvalidRuntime
 	}
+	if scoped, ok := dst.(NamespaceScoped); ok && scoped.NamespaceScoped() && len(ref.Namespace) == 0 {
+		ref.Namespace = ps.namespace
+	}
 	rsv, err := dst.Resolve(ref)
 	if err != nil {
 		return types.FnRef{}, err
@@ -108,6 +131,20 @@ func (ps *MetaResolver) resolveForRuntime(runtime string, ref types.FnRef) (type
 	}, nil
 }
 
+// ResolveHints returns the hints of the resolved function from the resolver of its runtime, if the resolver is a
+// HintResolver.
+func (ps *MetaResolver) ResolveHints(fn types.FnRef) (*types.FnHints, error) {
+	dst, ok := ps.clients[fn.Runtime]
+	if !ok {
+		return nil, ErrInvalidRuntime
+	}
+	hr, ok := dst.(HintResolver)
+	if !ok {
+		return nil, nil
+	}
+	return hr.ResolveHints(fn)
+}
+
 //
 // Helper functions
 //
@@ -120,6 +120,25 @@ func TestResolveNotFound(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestResolveInNamespace(t *testing.T) {
+	resolver := NewMetaResolver(map[string]RuntimeResolver{
+		"scoped":   &namespaceScopedResolver{uppercaseResolver},
+		"unscoped": uppercaseResolver,
+	}).InNamespace("team-a")
+
+	ref, err := resolver.Resolve("scoped://lowercase")
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewFnRef("scoped", "team-a", "LOWERCASE"), ref)
+
+	ref, err = resolver.Resolve("scoped://team-b/lowercase")
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewFnRef("scoped", "team-b", "LOWERCASE"), ref)
+
+	ref, err = resolver.Resolve("unscoped://lowercase")
+	assert.NoError(t, err)
+	assert.Equal(t, types.NewFnRef("unscoped", "", "LOWERCASE"), ref)
+}
+
 var (
 	uppercaseResolver = &MockedFunctionResolver{func(name string) (string, error) {
 		return strings.ToUpper(name), nil
@@ -136,3 +155,11 @@ type MockedFunctionResolver struct {
 func (mk *MockedFunctionResolver) Resolve(ref types.FnRef) (string, error) {
 	return mk.Fn(ref.ID)
 }
+
+type namespaceScopedResolver struct {
+	*MockedFunctionResolver
+}
+
+func (r *namespaceScopedResolver) NamespaceScoped() bool {
+	return true
+}
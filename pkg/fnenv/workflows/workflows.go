@@ -25,6 +25,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util/labels"
 	"github.com/fission/fission-workflows/pkg/util/pubsub"
+	"github.com/fission/fission-workflows/pkg/util/redact"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
@@ -61,6 +62,7 @@ func (rt *Runtime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOp
 	if err != nil {
 		return nil, err
 	}
+	rt.inheritLabels(wfSpec, spec.GetInvocationId())
 
 	// Note: currently context is not supported in the runtime interface, so we use a background context.
 	wfi, err := rt.InvokeWorkflow(wfSpec, opts...)
@@ -86,7 +88,7 @@ func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fn
 	// Check if the workflow required by the invocation exists
 	if spec.Workflow == nil {
 		awaitWorkflowCtx, cancel := context.WithTimeout(ctx, cfg.AwaitWorkflow)
-		wf, err := rt.awaitReadyWorkflow(awaitWorkflowCtx, spec.GetWorkflowId())
+		wf, err := rt.awaitReadyWorkflow(awaitWorkflowCtx, spec.GetWorkflowId(), spec.GetWorkflowVersion())
 		cancel()
 		if err != nil {
 			span.LogKV("error", err)
@@ -111,12 +113,14 @@ func (rt *Runtime) InvokeWorkflow(spec *types.WorkflowInvocationSpec, opts ...fn
 		if err != nil {
 			inputs = fmt.Errorf("error: %v", err)
 		}
-		span.LogKV("inputs", inputs)
+		span.LogKV("inputs", redact.DefaultRedactor.Value("inputs", inputs))
 	}
 
 	timeStart := time.Now()
 	fnenv.FnActive.WithLabelValues(Name).Inc()
-	defer fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart)))
+	defer func() {
+		fnenv.FnExecTime.WithLabelValues(Name).Observe(float64(time.Since(timeStart) / time.Millisecond))
+	}()
 	defer fnenv.FnActive.WithLabelValues(Name).Dec()
 	defer fnenv.FnCount.WithLabelValues(Name).Inc()
 
@@ -157,11 +161,17 @@ func (rt *Runtime) checkForInvocationResult(wfiID string) *types.WorkflowInvocat
 	return nil
 }
 
-func (rt *Runtime) checkForReadyWorkflow(workflowID string) (*types.Workflow, error) {
-	wf, err := rt.workflows.GetWorkflow(workflowID)
-	if err != nil {
+// checkForReadyWorkflow returns the version of the workflow if it is ready to be invoked. If the version is
+// types.WorkflowVersionLatest, the latest version of the workflow is checked.
+func (rt *Runtime) checkForReadyWorkflow(workflowID string, version int64) (*types.Workflow, error) {
+	latest, err := rt.workflows.GetWorkflow(workflowID)
+	if err != nil || latest == nil {
 		return nil, fmt.Errorf("failed to find workflow %v for new invocation", workflowID)
 	}
+	wf, ok := latest.AtVersion(version)
+	if !ok {
+		return nil, fmt.Errorf("failed to find version %d of workflow %v for new invocation", version, workflowID)
+	}
 	if !wf.GetStatus().Ready() {
 		return nil, fmt.Errorf("cannot invoke non-ready workflow %v (status: %v)", workflowID,
 			wf.GetStatus().GetStatus().String())
@@ -169,13 +179,18 @@ func (rt *Runtime) checkForReadyWorkflow(workflowID string) (*types.Workflow, er
 	return wf, nil
 }
 
-func (rt *Runtime) awaitReadyWorkflow(ctx context.Context, workflowID string) (wf *types.Workflow, err error) {
-	if wf, err = rt.checkForReadyWorkflow(workflowID); err == nil && wf != nil {
+func (rt *Runtime) awaitReadyWorkflow(ctx context.Context, workflowID string,
+	version int64) (wf *types.Workflow, err error) {
+	if wf, err = rt.checkForReadyWorkflow(workflowID, version); err == nil && wf != nil {
 		return wf, nil
 	}
 
-	// await the parsing of the workflow
-	if pub, ok := rt.invocations.CacheReader.(pubsub.Publisher); ok {
+	// await the parsing of the workflow, preferably using the events of the workflows cache
+	pub, ok := rt.workflows.CacheReader.(pubsub.Publisher)
+	if !ok {
+		pub, ok = rt.invocations.CacheReader.(pubsub.Publisher)
+	}
+	if ok {
 		sub := pub.Subscribe(pubsub.SubscriptionOptions{
 			Buffer: 1,
 			LabelMatcher: labels.And(
@@ -187,23 +202,23 @@ func (rt *Runtime) awaitReadyWorkflow(ctx context.Context, workflowID string) (w
 		defer pub.Unsubscribe(sub)
 
 		// Check the cache once to ensure that we did not miss the terminal event while subscribing
-		if wf, err = rt.checkForReadyWorkflow(workflowID); err == nil {
+		if wf, err = rt.checkForReadyWorkflow(workflowID, version); err == nil {
 			return wf, nil
 		}
 
 		select {
 		case <-ctx.Done():
 			// Check once before cancelling, whether cancelling is needed.
-			if result, err := rt.checkForReadyWorkflow(workflowID); result != nil {
+			if result, err := rt.checkForReadyWorkflow(workflowID, version); result != nil {
 				return result, nil
 			} else {
 				return nil, err
 			}
 		case <-sub.Ch:
-			return rt.checkForReadyWorkflow(workflowID)
+			return rt.checkForReadyWorkflow(workflowID, version)
 		}
 	}
-	return rt.pollUntilWorkflowResult(ctx, workflowID)
+	return rt.pollUntilWorkflowResult(ctx, workflowID, version)
 }
 
 func (rt *Runtime) awaitInvocationResult(ctx context.Context, invocationID string) (invocation *types.WorkflowInvocation, err error) {
@@ -273,9 +288,10 @@ func (rt *Runtime) pollUntilInvocationResult(ctx context.Context, wfiID string)
 	}
 }
 
-func (rt *Runtime) pollUntilWorkflowResult(ctx context.Context, workflowID string) (*types.Workflow, error) {
+func (rt *Runtime) pollUntilWorkflowResult(ctx context.Context, workflowID string,
+	version int64) (*types.Workflow, error) {
 	for {
-		if wf, err := rt.checkForReadyWorkflow(workflowID); err == nil {
+		if wf, err := rt.checkForReadyWorkflow(workflowID, version); err == nil {
 			return wf, nil
 		}
 
@@ -292,11 +308,33 @@ func (rt *Runtime) pollUntilWorkflowResult(ctx context.Context, workflowID strin
 	}
 }
 
+// inheritLabels copies the labels of the calling invocation to the spec of the child invocation. Labels that have
+// been set explicitly in the child invocation are not overridden.
+func (rt *Runtime) inheritLabels(spec *types.WorkflowInvocationSpec, callerID string) {
+	if len(callerID) == 0 {
+		return
+	}
+	caller, err := rt.invocations.GetInvocation(callerID)
+	if err != nil {
+		logrus.Debugf("Could not find calling invocation %s: %v", callerID, err)
+		return
+	}
+	for k, v := range caller.GetSpec().GetLabels() {
+		if spec.Labels == nil {
+			spec.Labels = map[string]string{}
+		}
+		if _, ok := spec.Labels[k]; !ok {
+			spec.Labels[k] = v
+		}
+	}
+}
+
 func toWorkflowSpec(spec *types.TaskInvocationSpec) (*types.WorkflowInvocationSpec, error) {
 	wfSpec := &types.WorkflowInvocationSpec{
 		WorkflowId: spec.FnRef.ID,
 		Inputs:     spec.Inputs,
 		Deadline:   spec.Deadline,
+		CallerId:   spec.GetInvocationId(),
 	}
 	// Check for the parent input
 	if parentTv, ok := spec.Inputs[types.InputParent]; ok {
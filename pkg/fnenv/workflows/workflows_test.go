@@ -3,6 +3,7 @@ package workflows
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/fission/fission-workflows/pkg/fes/backend/mem"
 	"github.com/fission/fission-workflows/pkg/fes/cache"
 	"github.com/fission/fission-workflows/pkg/fes/testutil"
+	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/util"
@@ -185,9 +187,143 @@ func TestRuntime_Invoke(t *testing.T) {
 	util.AssertProtoEqual(t, outputHeaders, task.GetOutputHeaders())
 }
 
+func TestRuntime_Invoke_InheritLabels(t *testing.T) {
+	runtime, invocationAPI, _, cache := setup()
+
+	parentSpec := types.NewWorkflowInvocationSpec(workflowID, defaultDeadline())
+	parentSpec.Labels = map[string]string{
+		"foo":  "bar",
+		"acme": "parent",
+	}
+	parentID, err := invocationAPI.Invoke(parentSpec)
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond) // Wait for the parent invocation to be projected into the cache
+
+	fnref := types.NewFnRef("workflows", "", workflowID)
+	spec := types.NewTaskInvocationSpec(&types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata(parentID),
+		Spec:     parentSpec,
+	}, &types.Task{
+		Metadata: types.NewObjectMetadata("ti-123"),
+		Spec:     &types.TaskSpec{},
+		Status: &types.TaskStatus{
+			FnRef: &fnref,
+		},
+	}, time.Now())
+
+	childLabels := make(chan map[string]string, 1)
+	go func() {
+		// Simulate workflow invocation
+		time.Sleep(50 * time.Millisecond)
+		for _, entity := range cache.List() {
+			if entity.Id == parentID {
+				continue
+			}
+			wfi, err := runtime.invocations.GetInvocation(entity.Id)
+			if err != nil {
+				panic(err)
+			}
+			childLabels <- wfi.GetSpec().GetLabels()
+			err = invocationAPI.Complete(entity.Id, nil, nil)
+			if err != nil {
+				panic(err)
+			}
+		}
+	}()
+
+	_, err = runtime.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, parentSpec.Labels, <-childLabels)
+}
+
+func TestRuntime_Invoke_CancelCascade(t *testing.T) {
+	runtime, invocationAPI, _, _ := setup()
+
+	parentSpec := types.NewWorkflowInvocationSpec(workflowID, defaultDeadline())
+	parentID, err := invocationAPI.Invoke(parentSpec)
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond) // Wait for the parent invocation to be projected into the cache
+
+	fnref := types.NewFnRef("workflows", "", workflowID)
+	spec := types.NewTaskInvocationSpec(&types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata(parentID),
+		Spec:     parentSpec,
+	}, &types.Task{
+		Metadata: types.NewObjectMetadata("ti-123"),
+		Spec:     &types.TaskSpec{},
+		Status: &types.TaskStatus{
+			FnRef: &fnref,
+		},
+	}, time.Now())
+
+	go func() {
+		// Cancel the parent invocation while the child invocation is running
+		time.Sleep(50 * time.Millisecond)
+		err := invocationAPI.CancelCascade(parentID, "stop", runtime.invocations)
+		if err != nil {
+			panic(err)
+		}
+	}()
+
+	task, err := runtime.Invoke(spec)
+	assert.NoError(t, err)
+	assert.Equal(t, types.TaskInvocationStatus_ABORTED, task.GetStatus())
+	assert.Equal(t, fmt.Sprintf("%s: invocation %s was canceled: stop", api.ErrInvocationCanceled, parentID),
+		task.GetError().Error())
+
+	children, err := runtime.invocations.GetChildInvocations(parentID)
+	assert.NoError(t, err)
+	assert.Len(t, children, 1)
+	assert.Equal(t, parentID, children[0].GetSpec().GetCallerId())
+}
+
+func TestRuntime_InvokeWorkflow_Version(t *testing.T) {
+	runtime, invocationAPI, _, cache := setup()
+	err := runtime.workflows.CacheReader.(fes.CacheReaderWriter).Put(&types.Workflow{
+		Metadata: &types.ObjectMetadata{Id: workflowID},
+		Spec:     &types.WorkflowSpec{Name: "v2"},
+		Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_QUEUED, Version: 2},
+		History: []*types.Workflow{{
+			Metadata: &types.ObjectMetadata{Id: workflowID},
+			Spec:     &types.WorkflowSpec{Name: "v1"},
+			Status:   &types.WorkflowStatus{Status: types.WorkflowStatus_READY, Version: 1},
+		}},
+	})
+	assert.NoError(t, err)
+
+	// The previous version can be invoked, while the latest version is still being parsed.
+	pinned := make(chan *types.WorkflowInvocationSpec, 1)
+	go func() {
+		// Simulate workflow invocation
+		time.Sleep(50 * time.Millisecond)
+		wfiID := cache.List()[0].Id
+		wfi, err := runtime.invocations.GetInvocation(wfiID)
+		if err != nil {
+			panic(err)
+		}
+		pinned <- wfi.GetSpec()
+		if err := invocationAPI.Complete(wfiID, nil, nil); err != nil {
+			panic(err)
+		}
+	}()
+	spec := types.NewWorkflowInvocationSpec(workflowID, defaultDeadline())
+	spec.WorkflowVersion = 1
+	_, err = runtime.InvokeWorkflow(spec)
+	assert.NoError(t, err)
+	wfiSpec := <-pinned
+	assert.Equal(t, int64(1), wfiSpec.GetWorkflowVersion())
+	assert.Equal(t, "v1", wfiSpec.GetWorkflow().GetSpec().GetName())
+	assert.Empty(t, wfiSpec.GetWorkflow().GetHistory())
+
+	spec = types.NewWorkflowInvocationSpec(workflowID, defaultDeadline())
+	spec.WorkflowVersion = 3
+	_, err = runtime.InvokeWorkflow(spec, fnenv.AwaitWorkflow(100*time.Millisecond))
+	assert.Error(t, err)
+}
+
 func setup() (*Runtime, *api.Invocation, *mem.Backend, fes.CacheReaderWriter) {
 	backend := mem.NewBackend()
-	invocationAPI := api.NewInvocationAPI(backend)
+	invocationAPI := api.NewInvocationAPI(backend, nil)
 	workflowsCache := testutil.NewCache()
 	err := workflowsCache.Put(&types.Workflow{
 		Metadata: &types.ObjectMetadata{
@@ -0,0 +1,174 @@
+// Package operator syncs Workflow custom resources into the workflow engine, allowing workflows to be managed with
+// kubectl and GitOps tools instead of only with the workflow API.
+//
+// Each Workflow resource is created in the workflow engine with the id <namespace>.<name>. Updating the resource
+// recreates the workflow with the same id, and deleting the resource deletes the workflow. The outcome of each sync
+// is reported in the status of the resource.
+package operator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/parse/yaml"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	DefaultResyncPeriod = 5 * time.Minute
+	DefaultSyncTimeout  = 30 * time.Second
+)
+
+var log = logrus.WithField("component", "operator")
+
+// WorkflowAPI is the part of the workflow API that the operator uses. It is implemented by the
+// apiserver.WorkflowAPIClient.
+type WorkflowAPI interface {
+	CreateSync(ctx context.Context, in *types.WorkflowSpec, opts ...grpc.CallOption) (*types.Workflow, error)
+	Get(ctx context.Context, in *types.ObjectMetadata, opts ...grpc.CallOption) (*types.Workflow, error)
+	Delete(ctx context.Context, in *types.ObjectMetadata, opts ...grpc.CallOption) (*empty.Empty, error)
+}
+
+// Operator watches the Workflow custom resources and syncs them into the workflow engine.
+type Operator struct {
+	workflows WorkflowAPI
+	crds      rest.Interface
+	namespace string
+	resync    time.Duration
+	timeout   time.Duration
+}
+
+// New creates an operator that watches the Workflow resources in the namespace, or in all namespaces if the namespace
+// is empty.
+func New(workflows WorkflowAPI, crds rest.Interface, namespace string) *Operator {
+	return &Operator{
+		workflows: workflows,
+		crds:      crds,
+		namespace: namespace,
+		resync:    DefaultResyncPeriod,
+		timeout:   DefaultSyncTimeout,
+	}
+}
+
+// NewRESTClient creates a client for the Workflow custom resources.
+func NewRESTClient(cfg *rest.Config) (*rest.RESTClient, error) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	config := *cfg
+	config.GroupVersion = &SchemeGroupVersion
+	config.APIPath = "/apis"
+	config.ContentType = runtime.ContentTypeJSON
+	config.NegotiatedSerializer = serializer.DirectCodecFactory{CodecFactory: serializer.NewCodecFactory(scheme)}
+	return rest.RESTClientFor(&config)
+}
+
+// WorkflowID returns the id of the workflow of the custom resource in the workflow engine.
+func WorkflowID(wf *Workflow) string {
+	// Namespaces cannot contain dots, which makes the id unambiguous.
+	return wf.Namespace + "." + wf.Name
+}
+
+// Run syncs the custom resources until the context is canceled.
+func (o *Operator) Run(ctx context.Context) {
+	lw := cache.NewListWatchFromClient(o.crds, Resource, o.namespace, fields.Everything())
+	_, informer := cache.NewInformer(lw, &Workflow{}, o.resync, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			o.handleUpdate(ctx, obj.(*Workflow))
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			o.handleUpdate(ctx, obj.(*Workflow))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if wf, ok := obj.(*Workflow); ok {
+				o.handleDelete(ctx, wf)
+			}
+		},
+	})
+	log.Infof("Watching Workflow resources (namespace: '%s')", o.namespace)
+	informer.Run(ctx.Done())
+}
+
+func (o *Operator) handleUpdate(ctx context.Context, wf *Workflow) {
+	status := o.sync(ctx, wf)
+	if status == wf.Status {
+		return
+	}
+	updated := wf.DeepCopy()
+	updated.Status = status
+	err := o.crds.Put().
+		Namespace(wf.Namespace).
+		Resource(Resource).
+		Name(wf.Name).
+		SubResource("status").
+		Body(updated).
+		Do().
+		Error()
+	if err != nil {
+		log.Errorf("Failed to update status of Workflow %s/%s: %v", wf.Namespace, wf.Name, err)
+	}
+}
+
+func (o *Operator) handleDelete(ctx context.Context, wf *Workflow) {
+	id := WorkflowID(wf)
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	if _, err := o.workflows.Delete(ctx, &types.ObjectMetadata{Id: id}); err != nil {
+		log.Errorf("Failed to delete workflow %s: %v", id, err)
+		return
+	}
+	log.Infof("Deleted workflow %s", id)
+}
+
+// sync creates or updates the workflow of the custom resource, and returns the resulting status of the resource.
+func (o *Operator) sync(ctx context.Context, wf *Workflow) WorkflowStatus {
+	id := WorkflowID(wf)
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	if wf.Status.ObservedGeneration == wf.Generation && wf.Status.WorkflowID == id && len(wf.Status.Error) == 0 {
+		// The resource has been synced already; only recreate the workflow if the workflow engine lost it.
+		existing, err := o.workflows.Get(ctx, &types.ObjectMetadata{Id: id})
+		if err == nil && existing.GetStatus().GetStatus() != types.WorkflowStatus_DELETED {
+			return wf.Status
+		}
+	}
+
+	spec, err := yaml.Parse(bytes.NewReader(wf.Spec))
+	if err != nil {
+		return WorkflowStatus{
+			ObservedGeneration: wf.Generation,
+			Error:              fmt.Sprintf("invalid workflow definition: %v", err),
+		}
+	}
+	spec.ForceId = id
+	if len(spec.Name) == 0 {
+		spec.Name = wf.Name
+	}
+	if _, err := o.workflows.CreateSync(ctx, spec); err != nil {
+		log.Errorf("Failed to sync workflow %s: %v", id, err)
+		return WorkflowStatus{
+			ObservedGeneration: wf.Generation,
+			Error:              err.Error(),
+		}
+	}
+	log.Infof("Synced workflow %s (generation %d)", id, wf.Generation)
+	return WorkflowStatus{
+		WorkflowID:         id,
+		ObservedGeneration: wf.Generation,
+	}
+}
@@ -0,0 +1,142 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const testSpec = `{"apiVersion": "v1", "output": "hello", "tasks": {"hello": {"run": "noop"}}}`
+
+type mockWorkflowAPI struct {
+	mock.Mock
+}
+
+func (m *mockWorkflowAPI) CreateSync(ctx context.Context, in *types.WorkflowSpec,
+	opts ...grpc.CallOption) (*types.Workflow, error) {
+	args := m.Called(in)
+	return &types.Workflow{Spec: in}, args.Error(0)
+}
+
+func (m *mockWorkflowAPI) Get(ctx context.Context, in *types.ObjectMetadata,
+	opts ...grpc.CallOption) (*types.Workflow, error) {
+	args := m.Called(in.GetId())
+	wf, _ := args.Get(0).(*types.Workflow)
+	return wf, args.Error(1)
+}
+
+func (m *mockWorkflowAPI) Delete(ctx context.Context, in *types.ObjectMetadata,
+	opts ...grpc.CallOption) (*empty.Empty, error) {
+	args := m.Called(in.GetId())
+	return &empty.Empty{}, args.Error(0)
+}
+
+func newWorkflow(generation int64, spec string) *Workflow {
+	return &Workflow{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "hello",
+			Namespace:  "default",
+			Generation: generation,
+		},
+		Spec: json.RawMessage(spec),
+	}
+}
+
+func TestOperator_Sync(t *testing.T) {
+	workflows := &mockWorkflowAPI{}
+	workflows.On("CreateSync", mock.MatchedBy(func(spec *types.WorkflowSpec) bool {
+		return spec.GetForceId() == "default.hello" && spec.GetName() == "hello" && spec.GetOutputTask() == "hello"
+	})).Return(nil)
+	op := New(workflows, nil, "")
+
+	status := op.sync(context.Background(), newWorkflow(2, testSpec))
+	assert.Equal(t, WorkflowStatus{WorkflowID: "default.hello", ObservedGeneration: 2}, status)
+	workflows.AssertExpectations(t)
+}
+
+func TestOperator_SyncUpToDate(t *testing.T) {
+	workflows := &mockWorkflowAPI{}
+	workflows.On("Get", "default.hello").Return(&types.Workflow{
+		Status: &types.WorkflowStatus{Status: types.WorkflowStatus_READY},
+	}, nil)
+	op := New(workflows, nil, "")
+
+	wf := newWorkflow(2, testSpec)
+	wf.Status = WorkflowStatus{WorkflowID: "default.hello", ObservedGeneration: 2}
+	status := op.sync(context.Background(), wf)
+	assert.Equal(t, wf.Status, status)
+	workflows.AssertNotCalled(t, "CreateSync", mock.Anything)
+}
+
+func TestOperator_SyncFailed(t *testing.T) {
+	workflows := &mockWorkflowAPI{}
+	workflows.On("CreateSync", mock.Anything).Return(errors.New("unknown function noop"))
+	op := New(workflows, nil, "")
+
+	status := op.sync(context.Background(), newWorkflow(1, testSpec))
+	assert.Equal(t, WorkflowStatus{ObservedGeneration: 1, Error: "unknown function noop"}, status)
+
+	status = op.sync(context.Background(), newWorkflow(1, "tasks: [}"))
+	assert.Contains(t, status.Error, "invalid workflow definition")
+}
+
+func TestOperator_Run(t *testing.T) {
+	statusUpdates := make(chan *Workflow, 1)
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/apis/workflows.fission.io/v1/namespaces/default/workflows/hello/status":
+			bs, _ := ioutil.ReadAll(r.Body)
+			wf := &Workflow{}
+			assert.NoError(t, json.Unmarshal(bs, wf))
+			select {
+			case statusUpdates <- wf:
+			default:
+			}
+			w.Write(bs)
+		case r.URL.Query().Get("watch") == "true" || strings.Contains(r.URL.Path, "/watch/"):
+			// Keep the watch open without events.
+			select {
+			case <-r.Context().Done():
+			case <-stop:
+			}
+		default:
+			list := &WorkflowList{Items: []Workflow{*newWorkflow(1, testSpec)}}
+			list.Kind = "WorkflowList"
+			list.APIVersion = SchemeGroupVersion.String()
+			json.NewEncoder(w).Encode(list)
+		}
+	}))
+	defer server.Close()
+	defer close(stop)
+	crds, err := NewRESTClient(&rest.Config{Host: server.URL})
+	assert.NoError(t, err)
+	workflows := &mockWorkflowAPI{}
+	workflows.On("CreateSync", mock.Anything).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go New(workflows, crds, "").Run(ctx)
+
+	select {
+	case wf := <-statusUpdates:
+		assert.Equal(t, WorkflowStatus{WorkflowID: "default.hello", ObservedGeneration: 1}, wf.Status)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "status of the Workflow resource was not updated")
+	}
+}
@@ -0,0 +1,95 @@
+package operator
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	Group    = "workflows.fission.io"
+	Version  = "v1"
+	Resource = "workflows"
+	Kind     = "Workflow"
+)
+
+var SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+// Workflow is the custom resource of a workflow definition.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the workflow definition, in the same format as the YAML workflow definitions.
+	Spec   json.RawMessage `json:"spec"`
+	Status WorkflowStatus  `json:"status,omitempty"`
+}
+
+// WorkflowStatus reports the result of syncing the custom resource into the workflow engine.
+type WorkflowStatus struct {
+	// WorkflowID is the id of the workflow in the workflow engine.
+	WorkflowID string `json:"workflowId,omitempty"`
+
+	// ObservedGeneration is the generation of the custom resource that was last synced.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Error describes why the last sync failed, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// WorkflowList is a list of Workflow custom resources.
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Workflow `json:"items"`
+}
+
+// AddToScheme registers the custom resource types in the scheme.
+func AddToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(SchemeGroupVersion, &Workflow{}, &WorkflowList{})
+	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
+	return nil
+}
+
+func (in *Workflow) DeepCopyInto(out *Workflow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec != nil {
+		out.Spec = make(json.RawMessage, len(in.Spec))
+		copy(out.Spec, in.Spec)
+	}
+	out.Status = in.Status
+}
+
+func (in *Workflow) DeepCopy() *Workflow {
+	if in == nil {
+		return nil
+	}
+	out := new(Workflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *Workflow) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+func (in *WorkflowList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowList)
+	*out = *in
+	out.ListMeta = *in.ListMeta.DeepCopy()
+	if in.Items != nil {
+		out.Items = make([]Workflow, len(in.Items))
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,774 @@
+// Package bpmn imports workflow definitions from BPMN 2.0 process models, such as the ones that are created with
+// business-process modeling tools.
+//
+// The flow nodes of the executable process are mapped onto tasks: service tasks run the function that they reference,
+// abstract tasks are no-ops, and intermediate timer events sleep. Gateways do not become tasks of their own. Instead,
+// the sequence flows through them become dependencies between the tasks, and the conditions of exclusive gateways
+// become the conditions (when) of the tasks that they lead to. A boundary timer event wraps the task that it is
+// attached to into a timeout, so that the flow that leaves the timer is taken if the task does not complete in time.
+package bpmn
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	// Namespace is the XML namespace of BPMN 2.0 models.
+	Namespace = "http://www.omg.org/spec/BPMN/20100524/MODEL"
+
+	fnNoop    = "noop"
+	fnSleep   = "sleep"
+	fnTimeout = "timeout"
+
+	// invocationInput is the input of the invocation that the tasks that follow the start event receive.
+	invocationInput = "$.Invocation.Inputs." + types.InputMain
+)
+
+var (
+	ErrNotBPMN = errors.New("not a BPMN 2.0 document (expected definitions in the BPMN model namespace)")
+
+	isoDurationRe = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+	languageRe    = regexp.MustCompile(`^[a-z]+:`)
+
+	// ignoredElements are the elements of a process that do not affect its execution.
+	ignoredElements = map[string]bool{
+		"documentation":       true,
+		"extensionElements":   true,
+		"laneSet":             true,
+		"ioSpecification":     true,
+		"property":            true,
+		"dataObject":          true,
+		"dataObjectReference": true,
+		"dataStoreReference":  true,
+		"textAnnotation":      true,
+		"association":         true,
+	}
+)
+
+var DefaultParser = &Parser{}
+
+func Parse(r io.Reader) (*types.WorkflowSpec, error) {
+	return DefaultParser.Parse(r)
+}
+
+// Parser implements the parse.Parser interface to parse workflow specs from BPMN 2.0 XML documents.
+type Parser struct {
+}
+
+// Parse parses a workflow spec from the executable process of the BPMN definitions in the reader. If none of the
+// processes is marked as executable, the definitions should contain a single process.
+func (p *Parser) Parse(r io.Reader) (*types.WorkflowSpec, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BPMN document: %v", err)
+	}
+	defs := &definitions{}
+	if err := xml.NewDecoder(bytes.NewReader(bs)).Decode(defs); err != nil {
+		return nil, ErrNotBPMN
+	}
+	if defs.XMLName.Local != "definitions" || defs.XMLName.Space != Namespace {
+		return nil, ErrNotBPMN
+	}
+
+	proc, err := defs.executableProcess()
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGraph(proc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse process %s: %v", proc.ID, err)
+	}
+	spec, err := g.workflowSpec()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse process %s: %v", proc.ID, err)
+	}
+	return spec, nil
+}
+
+type definitions struct {
+	XMLName   xml.Name
+	Processes []*process `xml:"process"`
+}
+
+type process struct {
+	ID           string     `xml:"id,attr"`
+	IsExecutable string     `xml:"isExecutable,attr"`
+	Elements     []*element `xml:",any"`
+}
+
+// element is a flow element of a process, with the attributes and children of all supported kinds of elements.
+type element struct {
+	XMLName              xml.Name
+	ID                   string           `xml:"id,attr"`
+	Implementation       string           `xml:"implementation,attr"`
+	SourceRef            string           `xml:"sourceRef,attr"`
+	TargetRef            string           `xml:"targetRef,attr"`
+	Default              string           `xml:"default,attr"`
+	AttachedToRef        string           `xml:"attachedToRef,attr"`
+	CancelActivity       string           `xml:"cancelActivity,attr"`
+	ConditionExpression  *string          `xml:"conditionExpression"`
+	TimerEventDefinition *timerDefinition `xml:"timerEventDefinition"`
+	TaskDefinition       *taskDefinition  `xml:"extensionElements>taskDefinition"`
+	Children             []*child         `xml:",any"`
+}
+
+type timerDefinition struct {
+	TimeDuration *string `xml:"timeDuration"`
+	TimeDate     *string `xml:"timeDate"`
+	TimeCycle    *string `xml:"timeCycle"`
+}
+
+// taskDefinition is the extension element that Zeebe (Camunda 8) uses to specify the job type of a service task.
+type taskDefinition struct {
+	Type string `xml:"type,attr"`
+}
+
+type child struct {
+	XMLName xml.Name
+}
+
+// eventDefinition returns the name of the (non-timer) event definition of the event, if it has one.
+func (e *element) eventDefinition() (string, bool) {
+	for _, c := range e.Children {
+		if strings.HasSuffix(c.XMLName.Local, "EventDefinition") {
+			return c.XMLName.Local, true
+		}
+	}
+	return "", false
+}
+
+func (d *definitions) executableProcess() (*process, error) {
+	var executable []*process
+	for _, proc := range d.Processes {
+		if ok, _ := strconv.ParseBool(proc.IsExecutable); ok {
+			executable = append(executable, proc)
+		}
+	}
+	switch {
+	case len(executable) == 1:
+		return executable[0], nil
+	case len(executable) > 1:
+		return nil, errors.New("definitions contain more than one executable process")
+	case len(d.Processes) == 1:
+		return d.Processes[0], nil
+	case len(d.Processes) == 0:
+		return nil, errors.New("definitions do not contain a process")
+	default:
+		return nil, errors.New("definitions contain more than one process, but none is marked as executable")
+	}
+}
+
+type nodeKind int
+
+const (
+	startEvent nodeKind = iota
+	endEvent
+	serviceTask
+	abstractTask
+	timerEvent
+	boundaryTimer
+	exclusiveGateway
+	parallelGateway
+)
+
+// node is a flow node of the process.
+type node struct {
+	id       string
+	kind     nodeKind
+	elem     *element
+	incoming []*flow
+	outgoing []*flow
+
+	// duration is the duration of a timer event.
+	duration time.Duration
+
+	// boundary is the boundary timer that is attached to a task; attachedTo is the task of a boundary timer.
+	boundary   *node
+	attachedTo *node
+}
+
+// isTask returns true if the node is mapped onto a task.
+func (n *node) isTask() bool {
+	return n.kind == serviceTask || n.kind == abstractTask || n.kind == timerEvent
+}
+
+// flow is a sequence flow between two nodes, with its condition as a JavaScript expression, if it has one.
+type flow struct {
+	id        string
+	source    *node
+	target    *node
+	condition string
+}
+
+type graph struct {
+	nodes   map[string]*node
+	ordered []*node
+	reached map[string]string
+}
+
+func newGraph(proc *process) (*graph, error) {
+	g := &graph{
+		nodes:   map[string]*node{},
+		reached: map[string]string{},
+	}
+	var flows []*element
+	for _, elem := range proc.Elements {
+		kind := elem.XMLName.Local
+		if ignoredElements[kind] {
+			continue
+		}
+		if kind == "sequenceFlow" {
+			flows = append(flows, elem)
+			continue
+		}
+		n, err := newNode(elem)
+		if err != nil {
+			return nil, err
+		}
+		if len(n.id) == 0 {
+			return nil, fmt.Errorf("%s does not have an id", kind)
+		}
+		if _, ok := g.nodes[n.id]; ok {
+			return nil, fmt.Errorf("duplicate id '%s'", n.id)
+		}
+		g.nodes[n.id] = n
+		g.ordered = append(g.ordered, n)
+	}
+	if len(g.ordered) == 0 {
+		return nil, errors.New("process does not contain any flow nodes")
+	}
+
+	for _, n := range g.ordered {
+		if n.kind != boundaryTimer {
+			continue
+		}
+		task, ok := g.nodes[n.elem.AttachedToRef]
+		if !ok || (task.kind != serviceTask && task.kind != abstractTask) {
+			return nil, fmt.Errorf("boundary event %s should be attached to a task", n.id)
+		}
+		if task.boundary != nil {
+			return nil, fmt.Errorf("task %s has more than one boundary event", task.id)
+		}
+		task.boundary = n
+		n.attachedTo = task
+	}
+
+	for _, elem := range flows {
+		source, ok := g.nodes[elem.SourceRef]
+		if !ok {
+			return nil, fmt.Errorf("sequence flow %s: unknown source '%s'", elem.ID, elem.SourceRef)
+		}
+		target, ok := g.nodes[elem.TargetRef]
+		if !ok {
+			return nil, fmt.Errorf("sequence flow %s: unknown target '%s'", elem.ID, elem.TargetRef)
+		}
+		f := &flow{id: elem.ID, source: source, target: target}
+		if elem.ConditionExpression != nil {
+			if source.kind != exclusiveGateway {
+				return nil, fmt.Errorf("sequence flow %s: conditions are only supported on the sequence flows "+
+					"of exclusive gateways", elem.ID)
+			}
+			condition, err := parseCondition(*elem.ConditionExpression)
+			if err != nil {
+				return nil, fmt.Errorf("sequence flow %s: %v", elem.ID, err)
+			}
+			f.condition = condition
+		}
+		source.outgoing = append(source.outgoing, f)
+		target.incoming = append(target.incoming, f)
+	}
+
+	for _, n := range g.ordered {
+		switch {
+		case n.kind == startEvent && len(n.incoming) > 0:
+			return nil, fmt.Errorf("start event %s cannot have incoming sequence flows", n.id)
+		case n.kind == boundaryTimer && len(n.incoming) > 0:
+			return nil, fmt.Errorf("boundary event %s cannot have incoming sequence flows", n.id)
+		case n.kind == endEvent && len(n.outgoing) > 0:
+			return nil, fmt.Errorf("end event %s cannot have outgoing sequence flows", n.id)
+		case n.kind != startEvent && n.kind != boundaryTimer && len(n.incoming) == 0:
+			return nil, fmt.Errorf("%s %s does not have any incoming sequence flows", n.elem.XMLName.Local, n.id)
+		}
+		if n.kind == exclusiveGateway && len(n.elem.Default) > 0 {
+			var found bool
+			for _, f := range n.outgoing {
+				found = found || f.id == n.elem.Default
+			}
+			if !found {
+				return nil, fmt.Errorf("default flow '%s' of gateway %s is not one of its outgoing flows",
+					n.elem.Default, n.id)
+			}
+		}
+	}
+	return g, nil
+}
+
+func newNode(elem *element) (*node, error) {
+	n := &node{id: elem.ID, elem: elem}
+	kind := elem.XMLName.Local
+	switch kind {
+	case "startEvent", "endEvent":
+		if def, ok := elem.eventDefinition(); ok {
+			return nil, fmt.Errorf("%s %s: %s is not supported", kind, elem.ID, def)
+		}
+		n.kind = startEvent
+		if kind == "endEvent" {
+			n.kind = endEvent
+		}
+	case "serviceTask":
+		n.kind = serviceTask
+	case "task":
+		n.kind = abstractTask
+	case "exclusiveGateway":
+		n.kind = exclusiveGateway
+	case "parallelGateway":
+		n.kind = parallelGateway
+	case "intermediateCatchEvent", "boundaryEvent":
+		if elem.TimerEventDefinition == nil {
+			def, _ := elem.eventDefinition()
+			return nil, fmt.Errorf("%s %s: only timer events are supported, but was %s", kind, elem.ID, def)
+		}
+		d, err := parseTimer(elem.TimerEventDefinition)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %v", kind, elem.ID, err)
+		}
+		n.duration = d
+		n.kind = timerEvent
+		if kind == "boundaryEvent" {
+			if cancel, err := strconv.ParseBool(elem.CancelActivity); err == nil && !cancel {
+				return nil, fmt.Errorf("boundary event %s: non-interrupting boundary events are not supported",
+					elem.ID)
+			}
+			n.kind = boundaryTimer
+		}
+	default:
+		return nil, fmt.Errorf("unsupported element %s '%s'", kind, elem.ID)
+	}
+	return n, nil
+}
+
+func (g *graph) workflowSpec() (*types.WorkflowSpec, error) {
+	visited := map[string]bool{}
+	for _, n := range g.ordered {
+		if err := g.checkAcyclic(n, map[string]bool{}, visited); err != nil {
+			return nil, err
+		}
+	}
+
+	spec := &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		Tasks:      types.Tasks{},
+	}
+	for _, n := range g.ordered {
+		if !n.isTask() {
+			continue
+		}
+		task, err := g.taskSpec(n)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %v", n.elem.XMLName.Local, n.id, err)
+		}
+		spec.Tasks[n.id] = task
+	}
+	if len(spec.Tasks) == 0 {
+		return nil, errors.New("process does not contain any tasks")
+	}
+
+	// The output of the workflow is that of its final task, or, if there are several, a map of their outputs. Like end
+	// events, flow nodes without outgoing flows end the process.
+	sinks := map[string]string{}
+	for _, n := range g.ordered {
+		if len(n.outgoing) > 0 || n.kind == boundaryTimer {
+			continue
+		}
+		if n.kind == serviceTask || n.kind == abstractTask {
+			sinks[n.id] = outputExpr(n)
+			continue
+		}
+		for id, value := range g.dataSources(n) {
+			if id != invocationInput {
+				sinks[id] = value
+			}
+		}
+	}
+	if len(sinks) == 1 {
+		for id := range sinks {
+			spec.OutputTask = id
+		}
+	} else if len(sinks) > 1 {
+		spec.Outputs = map[string]*typedvalues.TypedValue{}
+		for id, value := range sinks {
+			spec.Outputs[id] = typedvalues.MustWrap("{" + value + "}")
+		}
+	}
+	return spec, nil
+}
+
+// checkAcyclic returns an error if the node is part of a cycle, which cannot be mapped onto the tasks of a workflow.
+func (g *graph) checkAcyclic(n *node, visiting map[string]bool, visited map[string]bool) error {
+	if visiting[n.id] {
+		return fmt.Errorf("the process contains a cycle through %s, which is not supported", n.id)
+	}
+	if visited[n.id] {
+		return nil
+	}
+	visiting[n.id] = true
+	next := make([]*node, 0, len(n.outgoing)+1)
+	for _, f := range n.outgoing {
+		next = append(next, f.target)
+	}
+	if n.boundary != nil {
+		next = append(next, n.boundary)
+	}
+	for _, target := range next {
+		if err := g.checkAcyclic(target, visiting, visited); err != nil {
+			return err
+		}
+	}
+	delete(visiting, n.id)
+	visited[n.id] = true
+	return nil
+}
+
+func (g *graph) taskSpec(n *node) (*types.TaskSpec, error) {
+	var task *types.TaskSpec
+	switch n.kind {
+	case serviceTask:
+		fnRef := n.elem.Implementation
+		if len(fnRef) == 0 || strings.HasPrefix(fnRef, "##") {
+			fnRef = ""
+			if n.elem.TaskDefinition != nil {
+				fnRef = n.elem.TaskDefinition.Type
+			}
+		}
+		if len(fnRef) == 0 {
+			return nil, errors.New("the function should be specified by the implementation attribute or by a " +
+				"taskDefinition extension element")
+		}
+		task = types.NewTaskSpec(fnRef)
+	case abstractTask:
+		task = types.NewTaskSpec(fnNoop)
+	case timerEvent:
+		task = types.NewTaskSpec(fnSleep)
+		task.Inputs = types.SingleDefaultInput(typedvalues.MustWrap(n.duration.String()))
+	}
+
+	// Tasks receive the data of the tasks that precede them as their default input.
+	var input *typedvalues.TypedValue
+	if n.kind != timerEvent {
+		input = dataInput(g.dataSources(n))
+	}
+	if input != nil {
+		task.Inputs = types.SingleDefaultInput(input)
+	}
+
+	// A task with a boundary timer is run by a timeout, which receives the input of the task.
+	if n.boundary != nil {
+		if input != nil {
+			task.Inputs = types.SingleDefaultInput(typedvalues.MustWrap("{" + invocationInput + "}"))
+		}
+		action := task
+		task = types.NewTaskSpec(fnTimeout)
+		task.Inputs = map[string]*typedvalues.TypedValue{
+			"do":    typedvalues.MustWrap(action),
+			"after": typedvalues.MustWrap(n.boundary.duration.String()),
+		}
+		if input != nil {
+			task.Inputs["inputs"] = typedvalues.MustWrap(map[string]interface{}{
+				types.InputMain: typedvalues.MustUnwrap(input),
+			})
+		}
+	}
+
+	requires := types.Requires{}
+	for dep := range g.dependencies(n) {
+		requires[dep] = &types.TaskDependencyParameters{}
+	}
+	task.Requires = requires
+	task.Await = int32(len(requires))
+
+	reached, err := g.reachedExpr(n)
+	if err != nil {
+		return nil, err
+	}
+	if reached != "true" {
+		task.When = typedvalues.MustWrap("{" + reached + "}")
+	}
+	return task, nil
+}
+
+// dependencies returns the ids of the nearest tasks that precede the node, through gateways and boundary timers.
+func (g *graph) dependencies(n *node) map[string]bool {
+	deps := map[string]bool{}
+	var walk func(n *node)
+	walk = func(n *node) {
+		for _, f := range n.incoming {
+			switch {
+			case f.source.isTask():
+				deps[f.source.id] = true
+			case f.source.kind == boundaryTimer:
+				deps[f.source.attachedTo.id] = true
+			default:
+				walk(f.source)
+			}
+		}
+	}
+	walk(n)
+	return deps
+}
+
+// dataSources returns the expressions of the data that reaches the node, keyed by the id of the task that produces
+// it. The data flows through gateways and timer events, but not through boundary timers, because the task that they
+// are attached to did not complete. The input of the invocation is keyed by its expression.
+func (g *graph) dataSources(n *node) map[string]string {
+	sources := map[string]string{}
+	var walk func(n *node)
+	walk = func(n *node) {
+		for _, f := range n.incoming {
+			switch src := f.source; {
+			case src.kind == startEvent:
+				sources[invocationInput] = invocationInput
+			case src.kind == serviceTask || src.kind == abstractTask:
+				sources[src.id] = outputExpr(src)
+			case src.kind == boundaryTimer:
+			default:
+				walk(src)
+			}
+		}
+	}
+	walk(n)
+	return sources
+}
+
+// dataInput returns the default input of a task: the data of the task that precedes it, or a map of the data of the
+// tasks that precede it, keyed by their ids.
+func dataInput(sources map[string]string) *typedvalues.TypedValue {
+	switch len(sources) {
+	case 0:
+		return nil
+	case 1:
+		for _, value := range sources {
+			return typedvalues.MustWrap("{" + value + "}")
+		}
+	}
+	var entries []string
+	for _, id := range sortedKeys(sources) {
+		key := id
+		if id == invocationInput {
+			key = "$invocation"
+		}
+		entries = append(entries, fmt.Sprintf("%s: %s", strconv.Quote(key), sources[id]))
+	}
+	return typedvalues.MustWrap("{({" + strings.Join(entries, ", ") + "})}")
+}
+
+// outputExpr returns the expression that refers to the output of the task of the node.
+func outputExpr(n *node) string {
+	if n.boundary != nil {
+		return fmt.Sprintf("output('%s').output", n.id)
+	}
+	return fmt.Sprintf("output('%s')", n.id)
+}
+
+// reachedExpr returns the JavaScript expression that evaluates to true if the flow of the process reaches the node.
+// A node is reached if one of its incoming flows is taken, or, for parallel gateways, if all of them are taken. A flow
+// is taken if its source is reached, and its condition holds.
+func (g *graph) reachedExpr(n *node) (string, error) {
+	if reached, ok := g.reached[n.id]; ok {
+		return reached, nil
+	}
+	var reached string
+	switch n.kind {
+	case startEvent:
+		reached = "true"
+	case boundaryTimer:
+		task, err := g.reachedExpr(n.attachedTo)
+		if err != nil {
+			return "", err
+		}
+		reached = and(task, fmt.Sprintf("output('%s').timedOut", n.attachedTo.id))
+	default:
+		var flows []string
+		for _, f := range n.incoming {
+			taken, err := g.takenExpr(f)
+			if err != nil {
+				return "", err
+			}
+			flows = append(flows, taken)
+		}
+		if n.kind == parallelGateway {
+			reached = and(flows...)
+		} else {
+			reached = or(flows...)
+		}
+	}
+	g.reached[n.id] = reached
+	return reached, nil
+}
+
+func (g *graph) takenExpr(f *flow) (string, error) {
+	source, err := g.reachedExpr(f.source)
+	if err != nil {
+		return "", err
+	}
+	if f.source.boundary != nil {
+		return and(source, fmt.Sprintf("!output('%s').timedOut", f.source.id)), nil
+	}
+	if f.source.kind != exclusiveGateway {
+		return source, nil
+	}
+
+	// The gateway takes the first flow of which the condition holds, or otherwise the default flow. A flow without a
+	// condition always holds.
+	var preceding []string
+	for _, other := range f.source.outgoing {
+		if other.id == f.source.elem.Default {
+			continue
+		}
+		if other == f {
+			break
+		}
+		if len(other.condition) == 0 {
+			preceding = append(preceding, "true")
+			continue
+		}
+		preceding = append(preceding, other.condition)
+	}
+	terms := []string{source}
+	for _, condition := range preceding {
+		if condition == "true" {
+			return "false", nil
+		}
+		terms = append(terms, "!"+condition)
+	}
+	if f.id != f.source.elem.Default && len(f.condition) > 0 {
+		terms = append(terms, f.condition)
+	}
+	return and(terms...), nil
+}
+
+// and returns the conjunction of the JavaScript expressions.
+func and(terms ...string) string {
+	var filtered []string
+	for _, term := range dedupe(terms) {
+		if term == "false" {
+			return "false"
+		}
+		if term != "true" {
+			filtered = append(filtered, term)
+		}
+	}
+	if len(filtered) == 0 {
+		return "true"
+	}
+	// Disjunctions are parenthesized, because && takes precedence over ||.
+	for i, term := range filtered {
+		if strings.Contains(term, " || ") {
+			filtered[i] = "(" + term + ")"
+		}
+	}
+	return strings.Join(filtered, " && ")
+}
+
+// or returns the disjunction of the JavaScript expressions.
+func or(terms ...string) string {
+	var filtered []string
+	for _, term := range dedupe(terms) {
+		if term == "true" {
+			return "true"
+		}
+		if term != "false" {
+			filtered = append(filtered, term)
+		}
+	}
+	if len(filtered) == 0 {
+		return "false"
+	}
+	return strings.Join(filtered, " || ")
+}
+
+func dedupe(terms []string) []string {
+	seen := map[string]bool{}
+	var deduped []string
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			deduped = append(deduped, term)
+		}
+	}
+	return deduped
+}
+
+// parseCondition parses the condition expression of a sequence flow, which should be a JavaScript expression of the
+// workflow engine, such as { output('check').approved }.
+func parseCondition(s string) (string, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	if !typedvalues.IsExpression(s) {
+		return "", fmt.Errorf("condition should be an expression in braces, such as { output('task').ok }, "+
+			"but was '%s'", s)
+	}
+	body := strings.TrimSpace(typedvalues.RemoveExpressionDelimiters(s))
+	if len(body) == 0 {
+		return "", errors.New("condition is empty")
+	}
+	if languageRe.MatchString(body) {
+		return "", fmt.Errorf("condition should be a JavaScript expression, but was '%s'", s)
+	}
+	return "(" + body + ")", nil
+}
+
+// parseTimer parses the duration of a timer event definition.
+func parseTimer(timer *timerDefinition) (time.Duration, error) {
+	if timer.TimeDuration == nil {
+		if timer.TimeDate != nil || timer.TimeCycle != nil {
+			return 0, errors.New("only timers with a timeDuration are supported")
+		}
+		return 0, errors.New("timer does not have a timeDuration")
+	}
+	return parseDuration(strings.TrimSpace(*timer.TimeDuration))
+}
+
+// parseDuration parses an ISO 8601 duration, such as PT1H30M. Durations in years or months are not supported, because
+// their length varies.
+func parseDuration(s string) (time.Duration, error) {
+	match := isoDurationRe.FindStringSubmatch(s)
+	if match == nil || s == "P" || strings.HasSuffix(s, "T") {
+		return 0, fmt.Errorf("invalid duration '%s' (expected an ISO 8601 duration, such as PT30S)", s)
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{7 * 24 * time.Hour, 24 * time.Hour, time.Hour, time.Minute, time.Second} {
+		if len(match[i+1]) == 0 {
+			continue
+		}
+		v, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration '%s': %v", s, err)
+		}
+		d += time.Duration(v * float64(unit))
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration '%s' should be positive", s)
+	}
+	return d, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,259 @@
+package bpmn
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const header = `<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL"
+    xmlns:zeebe="http://camunda.org/schema/zeebe/1.0" id="definitions">
+`
+
+const order = header + `
+  <bpmn:process id="other" />
+  <bpmn:process id="order" isExecutable="true">
+    <bpmn:documentation>Processes an order</bpmn:documentation>
+    <bpmn:startEvent id="start" />
+    <bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="check" />
+    <bpmn:serviceTask id="check" name="Check order" implementation="check-order" />
+    <bpmn:sequenceFlow id="f2" sourceRef="check" targetRef="approved" />
+    <bpmn:exclusiveGateway id="approved" default="f4" />
+    <bpmn:sequenceFlow id="f3" sourceRef="approved" targetRef="ship">
+      <bpmn:conditionExpression>{ output('check').approved }</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="f4" sourceRef="approved" targetRef="reject" />
+    <bpmn:serviceTask id="ship">
+      <bpmn:extensionElements>
+        <zeebe:taskDefinition type="ship-order" />
+      </bpmn:extensionElements>
+    </bpmn:serviceTask>
+    <bpmn:boundaryEvent id="late" attachedToRef="ship">
+      <bpmn:timerEventDefinition><bpmn:timeDuration>PT30S</bpmn:timeDuration></bpmn:timerEventDefinition>
+    </bpmn:boundaryEvent>
+    <bpmn:sequenceFlow id="f5" sourceRef="late" targetRef="escalate" />
+    <bpmn:serviceTask id="escalate" implementation="escalate" />
+    <bpmn:sequenceFlow id="f6" sourceRef="ship" targetRef="fork" />
+    <bpmn:parallelGateway id="fork" />
+    <bpmn:sequenceFlow id="f7" sourceRef="fork" targetRef="invoice" />
+    <bpmn:sequenceFlow id="f8" sourceRef="fork" targetRef="notify" />
+    <bpmn:serviceTask id="invoice" implementation="invoice" />
+    <bpmn:task id="notify" />
+    <bpmn:sequenceFlow id="f9" sourceRef="invoice" targetRef="join" />
+    <bpmn:sequenceFlow id="f10" sourceRef="notify" targetRef="join" />
+    <bpmn:parallelGateway id="join" />
+    <bpmn:sequenceFlow id="f11" sourceRef="join" targetRef="archive" />
+    <bpmn:serviceTask id="archive" implementation="archive" />
+    <bpmn:sequenceFlow id="f12" sourceRef="archive" targetRef="end" />
+    <bpmn:serviceTask id="reject" implementation="reject" />
+    <bpmn:sequenceFlow id="f13" sourceRef="reject" targetRef="wait" />
+    <bpmn:intermediateCatchEvent id="wait">
+      <bpmn:timerEventDefinition><bpmn:timeDuration>PT1M30S</bpmn:timeDuration></bpmn:timerEventDefinition>
+    </bpmn:intermediateCatchEvent>
+    <bpmn:sequenceFlow id="f14" sourceRef="wait" targetRef="end" />
+    <bpmn:endEvent id="end" />
+  </bpmn:process>
+</bpmn:definitions>
+`
+
+func TestParse(t *testing.T) {
+	spec, err := Parse(strings.NewReader(order))
+	require.NoError(t, err)
+	assert.NoError(t, validate.WorkflowSpec(spec))
+	assert.Len(t, spec.Tasks, 8)
+
+	check := spec.Tasks["check"]
+	assert.Equal(t, "check-order", check.FunctionRef)
+	assert.Empty(t, check.Requires)
+	assert.Nil(t, check.When)
+	assert.Equal(t, "{$.Invocation.Inputs.default}", typedvalues.MustUnwrap(check.Inputs[types.InputMain]))
+
+	// The task with the boundary timer is run by a timeout.
+	ship := spec.Tasks["ship"]
+	assert.Equal(t, fnTimeout, ship.FunctionRef)
+	assert.Equal(t, types.Requires{"check": {}}, types.Requires(ship.Requires))
+	assert.Equal(t, "30s", typedvalues.MustUnwrap(ship.Inputs["after"]))
+	assert.Equal(t, map[string]interface{}{types.InputMain: "{output('check')}"},
+		typedvalues.MustUnwrap(ship.Inputs["inputs"]))
+	action, ok := typedvalues.MustUnwrap(ship.Inputs["do"]).(*types.TaskSpec)
+	require.True(t, ok)
+	assert.Equal(t, "ship-order", action.FunctionRef)
+	assert.Equal(t, "{$.Invocation.Inputs.default}", typedvalues.MustUnwrap(action.Inputs[types.InputMain]))
+
+	assert.Equal(t, types.Requires{"ship": {}}, types.Requires(spec.Tasks["escalate"].Requires))
+	assert.Nil(t, spec.Tasks["escalate"].Inputs)
+	assert.Equal(t, "{output('ship').output}",
+		typedvalues.MustUnwrap(spec.Tasks["invoice"].Inputs[types.InputMain]))
+	assert.Equal(t, fnNoop, spec.Tasks["notify"].FunctionRef)
+
+	archive := spec.Tasks["archive"]
+	assert.Equal(t, types.Requires{"invoice": {}, "notify": {}}, types.Requires(archive.Requires))
+	assert.Equal(t, int32(2), archive.Await)
+	assert.Equal(t, `{({"invoice": output('invoice'), "notify": output('notify')})}`,
+		typedvalues.MustUnwrap(archive.Inputs[types.InputMain]))
+
+	wait := spec.Tasks["wait"]
+	assert.Equal(t, fnSleep, wait.FunctionRef)
+	assert.Equal(t, "1m30s", typedvalues.MustUnwrap(wait.Inputs[types.InputMain]))
+	assert.Equal(t, types.Requires{"reject": {}}, types.Requires(wait.Requires))
+
+	// The output of the workflow consists of the outputs of the final tasks of the branches.
+	assert.Empty(t, spec.OutputTask)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"archive":  typedvalues.MustWrap("{output('archive')}"),
+		"escalate": typedvalues.MustWrap("{output('escalate')}"),
+		"reject":   typedvalues.MustWrap("{output('reject')}"),
+	}, spec.Outputs)
+
+	// The conditions of the tasks follow the paths through the gateways.
+	for _, c := range []struct {
+		outputs  map[string]interface{}
+		expected []string
+	}{
+		{
+			outputs:  map[string]interface{}{"check": map[string]interface{}{"approved": false}},
+			expected: []string{"check", "reject", "wait"},
+		},
+		{
+			outputs: map[string]interface{}{
+				"check": map[string]interface{}{"approved": true},
+				"ship":  map[string]interface{}{"timedOut": false},
+			},
+			expected: []string{"archive", "check", "invoice", "notify", "ship"},
+		},
+		{
+			outputs: map[string]interface{}{
+				"check": map[string]interface{}{"approved": true},
+				"ship":  map[string]interface{}{"timedOut": true},
+			},
+			expected: []string{"check", "escalate", "ship"},
+		},
+	} {
+		assert.Equal(t, c.expected, runTasks(t, spec, c.outputs), c.outputs)
+	}
+}
+
+// runTasks returns the ids of the tasks of which the condition holds, given the outputs of the tasks.
+func runTasks(t *testing.T, spec *types.WorkflowSpec, outputs map[string]interface{}) []string {
+	tasks := map[string]interface{}{}
+	for id := range spec.Tasks {
+		tasks[id] = map[string]interface{}{"Output": outputs[id]}
+	}
+	scope := map[string]interface{}{"Tasks": tasks}
+	var run []string
+	for id, task := range spec.Tasks {
+		if when := task.When; when != nil {
+			resolved, err := expr.Resolve(scope, id, when)
+			require.NoError(t, err)
+			if !typedvalues.MustUnwrap(resolved).(bool) {
+				continue
+			}
+		}
+		run = append(run, id)
+	}
+	sort.Strings(run)
+	return run
+}
+
+func TestParseSingleTask(t *testing.T) {
+	spec, err := Parse(strings.NewReader(header + `
+  <bpmn:process id="p">
+    <bpmn:startEvent id="start" />
+    <bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="hello" />
+    <bpmn:serviceTask id="hello" implementation="hello" />
+    <bpmn:sequenceFlow id="f2" sourceRef="hello" targetRef="end" />
+    <bpmn:endEvent id="end" />
+  </bpmn:process>
+</bpmn:definitions>`))
+	require.NoError(t, err)
+	assert.NoError(t, validate.WorkflowSpec(spec))
+	assert.Equal(t, "hello", spec.OutputTask)
+	assert.Empty(t, spec.Outputs)
+}
+
+func TestParseExclusiveGateway(t *testing.T) {
+	spec, err := Parse(strings.NewReader(header + `
+  <bpmn:process id="p">
+    <bpmn:startEvent id="start" />
+    <bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="gw" />
+    <bpmn:exclusiveGateway id="gw" default="f4" />
+    <bpmn:sequenceFlow id="f2" sourceRef="gw" targetRef="a">
+      <bpmn:conditionExpression>{ $.Invocation.Inputs.x > 1 }</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="f4" sourceRef="gw" targetRef="c" />
+    <bpmn:sequenceFlow id="f3" sourceRef="gw" targetRef="b">
+      <bpmn:conditionExpression>{ $.Invocation.Inputs.x > 0 }</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:serviceTask id="a" implementation="a" />
+    <bpmn:serviceTask id="b" implementation="b" />
+    <bpmn:serviceTask id="c" implementation="c" />
+  </bpmn:process>
+</bpmn:definitions>`))
+	require.NoError(t, err)
+	assert.Equal(t, "{($.Invocation.Inputs.x > 1)}", typedvalues.MustUnwrap(spec.Tasks["a"].When))
+	assert.Equal(t, "{!($.Invocation.Inputs.x > 1) && ($.Invocation.Inputs.x > 0)}",
+		typedvalues.MustUnwrap(spec.Tasks["b"].When))
+	assert.Equal(t, "{!($.Invocation.Inputs.x > 1) && !($.Invocation.Inputs.x > 0)}",
+		typedvalues.MustUnwrap(spec.Tasks["c"].When))
+}
+
+func TestParseUnsupported(t *testing.T) {
+	const start = header + `<bpmn:process id="p"><bpmn:startEvent id="start" />
+		<bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="a" />`
+	const end = `</bpmn:process></bpmn:definitions>`
+	for name, doc := range map[string]string{
+		"not BPMN":       "output: foo\ntasks:\n  foo:\n    run: noop\n",
+		"other XML":      `<definitions id="foo"><process id="p" /></definitions>`,
+		"no process":     header + `</bpmn:definitions>`,
+		"two processes":  header + `<bpmn:process id="a" /><bpmn:process id="b" /></bpmn:definitions>`,
+		"user task":      start + `<bpmn:userTask id="a" />` + end,
+		"no function":    start + `<bpmn:serviceTask id="a" />` + end,
+		"unknown target": start + end,
+		"cycle": start + `<bpmn:task id="a" /><bpmn:task id="b" />
+			<bpmn:sequenceFlow id="f2" sourceRef="a" targetRef="b" />
+			<bpmn:sequenceFlow id="f3" sourceRef="b" targetRef="a" />
+			` + end,
+		"condition": start + `<bpmn:exclusiveGateway id="a" /><bpmn:task id="b" />
+			<bpmn:sequenceFlow id="f2" sourceRef="a" targetRef="b">
+			<bpmn:conditionExpression>${approved}</bpmn:conditionExpression></bpmn:sequenceFlow>
+			` + end,
+		"message event": start + `<bpmn:task id="a" />
+			<bpmn:boundaryEvent id="b" attachedToRef="a"><bpmn:messageEventDefinition /></bpmn:boundaryEvent>
+			` + end,
+		"timer date": start + `<bpmn:task id="a" />
+			<bpmn:boundaryEvent id="b" attachedToRef="a"><bpmn:timerEventDefinition>
+			<bpmn:timeDate>2020-01-01T00:00:00Z</bpmn:timeDate></bpmn:timerEventDefinition></bpmn:boundaryEvent>
+			` + end,
+	} {
+		_, err := Parse(strings.NewReader(doc))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for s, expected := range map[string]time.Duration{
+		"PT30S":     30 * time.Second,
+		"PT1.5S":    1500 * time.Millisecond,
+		"PT1H30M":   90 * time.Minute,
+		"P1D":       24 * time.Hour,
+		"P1W2DT3H":  9*24*time.Hour + 3*time.Hour,
+		"P0DT0H10M": 10 * time.Minute,
+	} {
+		d, err := parseDuration(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, d, s)
+	}
+	for _, s := range []string{"", "P", "PT", "P1M", "P1Y", "30S", "PT0S", "P1DT"} {
+		_, err := parseDuration(s)
+		assert.Error(t, err, s)
+	}
+}
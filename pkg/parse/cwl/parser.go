@@ -0,0 +1,911 @@
+// Package cwl parses workflow definitions written in the Common Workflow Language (CWL) v1.x.
+//
+// A CWL Workflow is mapped onto a workflow, with a task for each of its steps. The inputs of the workflow become the
+// inputs of the invocation, and its outputs the named outputs of the workflow. A step runs either a CommandLineTool,
+// which is run as a container of the image of its DockerRequirement, or a function that is referenced by name. A
+// CommandLineTool document on its own is mapped onto a workflow with a single task.
+//
+// The parser supports the common subset of CWL: parameter references ($(inputs.x)) in the arguments and environment
+// variables of tools, defaults, and packed documents ($graph). Features that cannot be mapped onto tasks, such as
+// scatter, conditional steps, nested workflows and references to other CWL files, are rejected.
+package cwl
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	classWorkflow        = "Workflow"
+	classCommandLineTool = "CommandLineTool"
+	mainProcess          = "main"
+)
+
+var (
+	ErrNotCWL             = errors.New("not a CWL document (cwlVersion is missing)")
+	ErrUnsupportedVersion = errors.New("unsupported CWL version (expected v1.x)")
+)
+
+var DefaultParser = &Parser{}
+
+func Parse(r io.Reader) (*types.WorkflowSpec, error) {
+	return DefaultParser.Parse(r)
+}
+
+// Parser implements the parse.Parser interface to parse workflow specs from CWL documents, in YAML or JSON.
+type Parser struct {
+}
+
+// Parse parses a workflow spec from the CWL document in the reader. The document should contain a Workflow or a
+// CommandLineTool, or, in case of a packed document, a $graph of processes with the main process having the id main.
+func (p *Parser) Parse(r io.Reader) (*types.WorkflowSpec, error) {
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CWL document: %v", err)
+	}
+	var i interface{}
+	if err := yaml.Unmarshal(bs, &i); err != nil {
+		return nil, fmt.Errorf("failed to read CWL document: %v", err)
+	}
+	doc, ok := normalize(i).(map[string]interface{})
+	if !ok {
+		return nil, ErrNotCWL
+	}
+	version, _ := doc["cwlVersion"].(string)
+	if len(version) == 0 {
+		return nil, ErrNotCWL
+	}
+	if !strings.HasPrefix(version, "v1.") {
+		return nil, fmt.Errorf("%v: '%s'", ErrUnsupportedVersion, version)
+	}
+
+	d := &document{processes: map[string]map[string]interface{}{}}
+	main := doc
+	if graph, ok := doc["$graph"]; ok {
+		processes, ok := graph.([]interface{})
+		if !ok {
+			return nil, errors.New("$graph should be a list of processes")
+		}
+		for _, process := range processes {
+			if m, ok := process.(map[string]interface{}); ok {
+				d.processes[localID(stringValue(m["id"]))] = m
+			}
+		}
+		if main, ok = d.processes[mainProcess]; !ok {
+			return nil, fmt.Errorf("$graph does not contain a process with id '%s'", mainProcess)
+		}
+	}
+
+	spec, err := d.parseProcess(main)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CWL document: %v", err)
+	}
+	return spec, nil
+}
+
+// document contains the processes of a (packed) CWL document, by id.
+type document struct {
+	processes map[string]map[string]interface{}
+}
+
+func (d *document) parseProcess(process map[string]interface{}) (*types.WorkflowSpec, error) {
+	switch class := stringValue(process["class"]); class {
+	case classWorkflow:
+		return d.parseWorkflow(process)
+	case classCommandLineTool:
+		return d.parseStandaloneTool(process)
+	default:
+		return nil, fmt.Errorf("unsupported class '%s' (expected %s or %s)", class, classWorkflow,
+			classCommandLineTool)
+	}
+}
+
+func (d *document) parseWorkflow(wf map[string]interface{}) (*types.WorkflowSpec, error) {
+	scope := localID(stringValue(wf["id"]))
+	inputs, err := entries(wf["inputs"])
+	if err != nil {
+		return nil, fmt.Errorf("inputs: %v", err)
+	}
+	spec, err := newWorkflowSpec(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := entries(wf["steps"])
+	if err != nil {
+		return nil, fmt.Errorf("steps: %v", err)
+	}
+	if len(steps) == 0 {
+		return nil, errors.New("workflow does not contain any steps")
+	}
+	refs := &references{scope: scope, inputs: map[string]bool{}, steps: map[string]bool{}}
+	for _, input := range inputs {
+		refs.inputs[input.id] = true
+	}
+	for _, step := range steps {
+		refs.steps[step.id] = true
+	}
+	for _, step := range steps {
+		task, err := d.parseStep(step.value, refs)
+		if err != nil {
+			return nil, fmt.Errorf("step %s: %v", step.id, err)
+		}
+		spec.Tasks[step.id] = task
+	}
+
+	outputs, err := entries(wf["outputs"])
+	if err != nil {
+		return nil, fmt.Errorf("outputs: %v", err)
+	}
+	for _, output := range outputs {
+		value, _, err := refs.resolve(output.value["outputSource"], stringValue(output.value["linkMerge"]))
+		if err != nil {
+			return nil, fmt.Errorf("output %s: %v", output.id, err)
+		}
+		if spec.Outputs == nil {
+			spec.Outputs = map[string]*typedvalues.TypedValue{}
+		}
+		spec.Outputs[output.id] = typedvalues.MustWrap("{" + value + "}")
+	}
+
+	// Without outputs, the output of the workflow is that of its final step, if there is a single one.
+	if len(spec.Outputs) == 0 {
+		if sinks := sinkTasks(spec.Tasks); len(sinks) == 1 {
+			spec.OutputTask = sinks[0]
+		}
+	}
+	return spec, nil
+}
+
+// parseStandaloneTool maps a CommandLineTool document onto a workflow with a single task, which receives the inputs
+// of the invocation.
+func (d *document) parseStandaloneTool(tool map[string]interface{}) (*types.WorkflowSpec, error) {
+	inputs, err := entries(tool["inputs"])
+	if err != nil {
+		return nil, fmt.Errorf("inputs: %v", err)
+	}
+	spec, err := newWorkflowSpec(inputs)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, input := range inputs {
+		values[input.id] = invocationInput(input.id)
+	}
+	task, err := parseTool(tool, values)
+	if err != nil {
+		return nil, err
+	}
+	taskID := localID(stringValue(tool["id"]))
+	if len(taskID) == 0 {
+		taskID = mainProcess
+	}
+	spec.Tasks[taskID] = task
+	spec.OutputTask = taskID
+	return spec, nil
+}
+
+func (d *document) parseStep(step map[string]interface{}, refs *references) (*types.TaskSpec, error) {
+	for _, field := range []string{"scatter", "when"} {
+		if _, ok := step[field]; ok {
+			return nil, fmt.Errorf("%s is not supported", field)
+		}
+	}
+
+	stepInputs, err := entries(step["in"])
+	if err != nil {
+		return nil, fmt.Errorf("in: %v", err)
+	}
+	requires := types.Requires{}
+	values := map[string]string{}
+	literals := map[string]interface{}{}
+	for _, in := range stepInputs {
+		if _, ok := in.value["valueFrom"]; ok {
+			return nil, fmt.Errorf("input %s: valueFrom is not supported", in.id)
+		}
+		src, ok := in.value["source"]
+		if !ok {
+			// Without a source, the input is either the literal default or not set at all.
+			if def, ok := in.value["default"]; ok {
+				literals[in.id] = def
+				if values[in.id], err = withDefault("undefined", def); err != nil {
+					return nil, fmt.Errorf("input %s: %v", in.id, err)
+				}
+			}
+			continue
+		}
+		value, deps, err := refs.resolve(src, stringValue(in.value["linkMerge"]))
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %v", in.id, err)
+		}
+		for _, dep := range deps {
+			requires[dep] = &types.TaskDependencyParameters{}
+		}
+		if def, ok := in.value["default"]; ok {
+			if value, err = withDefault(value, def); err != nil {
+				return nil, fmt.Errorf("input %s: %v", in.id, err)
+			}
+		}
+		values[in.id] = value
+	}
+
+	var task *types.TaskSpec
+	switch run := step["run"].(type) {
+	case string:
+		switch {
+		case strings.HasPrefix(run, "#"):
+			process, ok := d.processes[localID(run)]
+			if !ok {
+				return nil, fmt.Errorf("unknown process '%s'", run)
+			}
+			task, err = d.parseRun(process, values)
+		case strings.HasSuffix(run, ".cwl"):
+			return nil, fmt.Errorf("references to other CWL documents are not supported; pack the workflow into "+
+				"a single document (e.g. cwltool --pack) instead: '%s'", run)
+		default:
+			task, err = parseFunctionRun(run, values, literals)
+		}
+	case map[string]interface{}:
+		task, err = d.parseRun(run, values)
+	case nil:
+		return nil, errors.New("run is required")
+	default:
+		return nil, fmt.Errorf("run should be a process or a reference, but was %T", run)
+	}
+	if err != nil {
+		return nil, err
+	}
+	task.Requires = requires
+	task.Await = int32(len(requires))
+	return task, nil
+}
+
+func (d *document) parseRun(process map[string]interface{}, values map[string]string) (*types.TaskSpec, error) {
+	switch class := stringValue(process["class"]); class {
+	case classCommandLineTool:
+		return parseTool(process, values)
+	case classWorkflow:
+		return nil, errors.New("nested workflows are not supported")
+	default:
+		return nil, fmt.Errorf("unsupported class '%s' of run (expected %s)", class, classCommandLineTool)
+	}
+}
+
+// parseFunctionRun maps a step that runs a function, such as a Fission function or a container:// reference, onto a
+// task that receives the inputs of the step as its inputs. Inputs without a source are passed as their literal
+// default.
+func parseFunctionRun(fnRef string, values map[string]string, literals map[string]interface{}) (*types.TaskSpec,
+	error) {
+	task := types.NewTaskSpec(fnRef)
+	task.Inputs = map[string]*typedvalues.TypedValue{}
+	for key, value := range values {
+		if literal, ok := literals[key]; ok {
+			tv, err := typedvalues.Wrap(literal)
+			if err != nil {
+				return nil, fmt.Errorf("input %s: invalid default: %v", key, err)
+			}
+			task.Inputs[key] = tv
+			continue
+		}
+		task.Inputs[key] = typedvalues.MustWrap("{" + value + "}")
+	}
+	return task, nil
+}
+
+// parseTool maps a CommandLineTool onto a task that runs the container image of its DockerRequirement. The values
+// contain the JavaScript expressions of the inputs of the tool, which are combined with the input bindings and
+// arguments of the tool into the command line of the container.
+func parseTool(tool map[string]interface{}, values map[string]string) (*types.TaskSpec, error) {
+	requirements, err := parseRequirements(tool)
+	if err != nil {
+		return nil, err
+	}
+	docker, ok := requirements["DockerRequirement"]
+	if !ok {
+		return nil, errors.New("CommandLineTool requires a DockerRequirement")
+	}
+	image := stringValue(docker["dockerPull"])
+	if len(image) == 0 {
+		return nil, errors.New("DockerRequirement requires a dockerPull image")
+	}
+	if _, ok := tool["stdin"]; ok {
+		return nil, errors.New("stdin is not supported")
+	}
+
+	inputs, err := entries(tool["inputs"])
+	if err != nil {
+		return nil, fmt.Errorf("inputs: %v", err)
+	}
+	scope, err := inputsScope(inputs, values)
+	if err != nil {
+		return nil, err
+	}
+
+	task := types.NewTaskSpec(containerRef(image))
+	task.Inputs = map[string]*typedvalues.TypedValue{}
+	if baseCommand, ok := tool["baseCommand"]; ok {
+		command, err := stringList(baseCommand)
+		if err != nil {
+			return nil, fmt.Errorf("baseCommand: %v", err)
+		}
+		task.Inputs["command"] = typedvalues.MustWrap(command)
+	}
+
+	bindings, err := parseBindings(tool["arguments"], inputs)
+	if err != nil {
+		return nil, err
+	}
+	if len(bindings) > 0 {
+		args := make([]string, len(bindings))
+		for i, b := range bindings {
+			args[i] = b.js
+		}
+		task.Inputs["args"] = typedvalues.MustWrap(fmt.Sprintf("{(function(inputs) { %s return [].concat(%s); })(%s)}",
+			argFn, strings.Join(args, ", "), scope))
+	}
+
+	if envVars, ok := requirements["EnvVarRequirement"]; ok {
+		env, err := parseEnvDef(envVars["envDef"])
+		if err != nil {
+			return nil, fmt.Errorf("EnvVarRequirement: %v", err)
+		}
+		task.Inputs["env"] = typedvalues.MustWrap(fmt.Sprintf("{(function(inputs) { return {%s}; })(%s)}",
+			strings.Join(env, ", "), scope))
+	}
+	return task, nil
+}
+
+// argFn formats a value as command-line arguments according to its input binding. Like CWL, it omits null values
+// and empty lists, and only adds the prefix of a boolean input if it is true.
+const argFn = `function arg(v, prefix, separate, itemSeparator) { ` +
+	`if (v === undefined || v === null || v === false) { return []; } ` +
+	`if (v === true) { return prefix ? [prefix] : []; } ` +
+	`var vs = [].concat(v).map(function(e) { return typeof e === "object" ? JSON.stringify(e) : String(e); }); ` +
+	`if (vs.length === 0) { return []; } ` +
+	`if (itemSeparator !== null) { vs = [vs.join(itemSeparator)]; } ` +
+	`if (!prefix) { return vs; } ` +
+	`return separate ? [prefix].concat(vs) : [prefix + vs[0]].concat(vs.slice(1)); }`
+
+// binding is an argument or an input binding of a CommandLineTool, with its position on the command line.
+type binding struct {
+	position int
+	js       string
+}
+
+// parseBindings returns the JavaScript expressions that format the arguments and bound inputs of a tool as lists of
+// command-line arguments, ordered by their position. Arguments precede inputs at the same position.
+func parseBindings(arguments interface{}, inputs []entry) ([]binding, error) {
+	var bindings []binding
+	if arguments != nil {
+		args, ok := arguments.([]interface{})
+		if !ok {
+			return nil, errors.New("arguments should be a list")
+		}
+		for i, arg := range args {
+			switch a := arg.(type) {
+			case string:
+				value, err := interpolate(a)
+				if err != nil {
+					return nil, fmt.Errorf("argument %d: %v", i, err)
+				}
+				bindings = append(bindings, binding{js: fmt.Sprintf("arg(%s, null, true, null)", value)})
+			case map[string]interface{}:
+				value, err := interpolate(stringValue(a["valueFrom"]))
+				if err != nil {
+					return nil, fmt.Errorf("argument %d: %v", i, err)
+				}
+				b, err := newBinding(value, a)
+				if err != nil {
+					return nil, fmt.Errorf("argument %d: %v", i, err)
+				}
+				bindings = append(bindings, b)
+			default:
+				return nil, fmt.Errorf("argument %d should be a string or a binding", i)
+			}
+		}
+	}
+	for _, input := range inputs {
+		inputBinding, ok := input.value["inputBinding"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := inputBinding["valueFrom"]; ok {
+			return nil, fmt.Errorf("input %s: valueFrom is not supported", input.id)
+		}
+		b, err := newBinding("inputs["+jsString(input.id)+"]", inputBinding)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %v", input.id, err)
+		}
+		bindings = append(bindings, b)
+	}
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return bindings[i].position < bindings[j].position
+	})
+	return bindings, nil
+}
+
+func newBinding(value string, def map[string]interface{}) (binding, error) {
+	var position int
+	switch p := def["position"].(type) {
+	case nil:
+	case int:
+		position = p
+	default:
+		return binding{}, errors.New("position should be an integer")
+	}
+	prefix := "null"
+	if p, ok := def["prefix"]; ok {
+		prefix = jsString(stringValue(p))
+	}
+	separate := true
+	if s, ok := def["separate"].(bool); ok {
+		separate = s
+	}
+	itemSeparator := "null"
+	if s, ok := def["itemSeparator"]; ok {
+		itemSeparator = jsString(stringValue(s))
+	}
+	return binding{
+		position: position,
+		js:       fmt.Sprintf("arg(%s, %s, %t, %s)", value, prefix, separate, itemSeparator),
+	}, nil
+}
+
+// parseEnvDef returns the properties of a JavaScript object that contains the environment variables of an
+// EnvVarRequirement, which can be defined as a map or as a list of envName/envValue entries.
+func parseEnvDef(envDef interface{}) ([]string, error) {
+	vars := map[string]string{}
+	switch def := envDef.(type) {
+	case map[string]interface{}:
+		for name, value := range def {
+			vars[name] = stringValue(value)
+		}
+	case []interface{}:
+		for _, e := range def {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("envDef should contain envName/envValue entries")
+			}
+			vars[stringValue(m["envName"])] = stringValue(m["envValue"])
+		}
+	default:
+		return nil, errors.New("envDef should be a map or a list")
+	}
+	var props []string
+	for _, name := range sortedKeys(vars) {
+		value, err := interpolate(vars[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		props = append(props, fmt.Sprintf("%s: String(%s)", jsString(name), value))
+	}
+	return props, nil
+}
+
+// parseRequirements returns the requirements and hints of a process by class. Requirements take precedence over
+// hints of the same class.
+func parseRequirements(process map[string]interface{}) (map[string]map[string]interface{}, error) {
+	result := map[string]map[string]interface{}{}
+	for _, field := range []string{"hints", "requirements"} {
+		switch reqs := process[field].(type) {
+		case nil:
+		case []interface{}:
+			for _, r := range reqs {
+				if m, ok := r.(map[string]interface{}); ok {
+					result[stringValue(m["class"])] = m
+				}
+			}
+		case map[string]interface{}:
+			for class, r := range reqs {
+				if m, ok := r.(map[string]interface{}); ok {
+					result[class] = m
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s should be a list or a map", field)
+		}
+	}
+	return result, nil
+}
+
+// references resolves the sources of the step inputs and the outputs of a workflow, which refer to either an input of
+// the workflow (input) or an output of a step (step/output).
+type references struct {
+	scope  string
+	inputs map[string]bool
+	steps  map[string]bool
+}
+
+// resolve returns the JavaScript expression of the sources, along with the steps that the sources refer to. Multiple
+// sources are combined into a list, which is flattened if linkMerge is merge_flattened.
+func (r *references) resolve(source interface{}, linkMerge string) (string, []string, error) {
+	var sources []string
+	switch src := source.(type) {
+	case string:
+		sources = []string{src}
+	case []interface{}:
+		for _, s := range src {
+			sources = append(sources, stringValue(s))
+		}
+	case nil:
+		return "", nil, errors.New("source is required")
+	default:
+		return "", nil, fmt.Errorf("source should be a string or a list, but was %T", source)
+	}
+
+	var values, deps []string
+	for _, src := range sources {
+		id := strings.TrimPrefix(strings.TrimPrefix(src, "#"), r.scope+"/")
+		if i := strings.Index(id, "/"); i >= 0 {
+			step := id[:i]
+			if !r.steps[step] {
+				return "", nil, fmt.Errorf("unknown step '%s' in source '%s'", step, src)
+			}
+			values = append(values, fmt.Sprintf("output(%s)", jsString(step)))
+			deps = append(deps, step)
+			continue
+		}
+		if !r.inputs[id] {
+			return "", nil, fmt.Errorf("unknown input '%s' in source '%s'", id, src)
+		}
+		values = append(values, invocationInput(id))
+	}
+
+	if _, ok := source.(string); ok {
+		return values[0], deps, nil
+	}
+	if linkMerge == "merge_flattened" {
+		return fmt.Sprintf("[].concat(%s)", strings.Join(values, ", ")), deps, nil
+	}
+	return fmt.Sprintf("[%s]", strings.Join(values, ", ")), deps, nil
+}
+
+// entry is an entry of a field that is defined as a map or a list, such as an input or output parameter, or a step.
+type entry struct {
+	id    string
+	value map[string]interface{}
+}
+
+// entries returns the entries of a field that CWL allows to be defined either as a map by id, or as a list of maps with
+// an id field. Map entries are ordered by id; scalar values are expanded to a map with the value as type or source.
+func entries(i interface{}) ([]entry, error) {
+	var result []entry
+	switch v := i.(type) {
+	case nil:
+	case map[string]interface{}:
+		for _, id := range sortedKeys(v) {
+			result = append(result, entry{id: localID(id), value: expand(v[id])})
+		}
+	case []interface{}:
+		for _, e := range v {
+			m, ok := e.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("list entries should be maps with an id")
+			}
+			id := localID(stringValue(m["id"]))
+			if len(id) == 0 {
+				return nil, errors.New("list entry is missing an id")
+			}
+			result = append(result, entry{id: id, value: m})
+		}
+	default:
+		return nil, fmt.Errorf("expected a map or a list, but was %T", i)
+	}
+	return result, nil
+}
+
+// expand expands the shorthand of an entry. Both parameter types (id: string) and step input sources (id: source) are
+// written as scalars or lists, so the value is stored as both.
+func expand(i interface{}) map[string]interface{} {
+	if m, ok := i.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{"type": i, "source": i}
+}
+
+// newWorkflowSpec returns a workflow spec without tasks, with the input schema and default inputs derived from the
+// inputs of the process.
+func newWorkflowSpec(inputs []entry) (*types.WorkflowSpec, error) {
+	spec := &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		Tasks:      types.Tasks{},
+	}
+	if len(inputs) == 0 {
+		return spec, nil
+	}
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, input := range inputs {
+		schema, optional := typeSchema(input.value["type"])
+		properties[input.id] = schema
+		def, hasDefault := input.value["default"]
+		if hasDefault {
+			tv, err := typedvalues.Wrap(def)
+			if err != nil {
+				return nil, fmt.Errorf("input %s: invalid default: %v", input.id, err)
+			}
+			if spec.DefaultInputs == nil {
+				spec.DefaultInputs = map[string]*typedvalues.TypedValue{}
+			}
+			spec.DefaultInputs[input.id] = tv
+		}
+		if !optional && !hasDefault {
+			required = append(required, input.id)
+		}
+	}
+	bs, err := json.Marshal(map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	})
+	if err != nil {
+		return nil, err
+	}
+	spec.InputSchema = string(bs)
+	return spec, nil
+}
+
+// typeSchema returns the JSON schema of a CWL type, and whether the type allows null values. Types without an
+// equivalent, such as File and Any, are not constrained.
+func typeSchema(t interface{}) (schema map[string]interface{}, optional bool) {
+	switch v := t.(type) {
+	case string:
+		if strings.HasSuffix(v, "?") {
+			schema, _ = typeSchema(strings.TrimSuffix(v, "?"))
+			return schema, true
+		}
+		if strings.HasSuffix(v, "[]") {
+			items, _ := typeSchema(strings.TrimSuffix(v, "[]"))
+			return map[string]interface{}{"type": "array", "items": items}, false
+		}
+		switch v {
+		case "null":
+			return map[string]interface{}{"type": "null"}, true
+		case "boolean", "string":
+			return map[string]interface{}{"type": v}, false
+		case "int", "long":
+			return map[string]interface{}{"type": "integer"}, false
+		case "float", "double":
+			return map[string]interface{}{"type": "number"}, false
+		}
+	case []interface{}:
+		var schemas []map[string]interface{}
+		for _, e := range v {
+			s, opt := typeSchema(e)
+			optional = optional || opt
+			if s["type"] != "null" {
+				schemas = append(schemas, s)
+			}
+		}
+		if len(schemas) == 1 {
+			return schemas[0], optional
+		}
+		return map[string]interface{}{}, optional
+	case map[string]interface{}:
+		switch v["type"] {
+		case "array":
+			items, _ := typeSchema(v["items"])
+			return map[string]interface{}{"type": "array", "items": items}, false
+		case "enum":
+			return map[string]interface{}{"enum": v["symbols"]}, false
+		case "record":
+			return map[string]interface{}{"type": "object"}, false
+		}
+	}
+	return map[string]interface{}{}, false
+}
+
+// inputsScope returns the JavaScript object with the values of the inputs of a tool, which is available to the
+// parameter references and input bindings of the tool as inputs. Inputs without a value fall back to the default of
+// the tool.
+func inputsScope(inputs []entry, values map[string]string) (string, error) {
+	var props []string
+	for _, input := range inputs {
+		value, ok := values[input.id]
+		if !ok {
+			value = "undefined"
+		}
+		if def, ok := input.value["default"]; ok {
+			var err error
+			if value, err = withDefault(value, def); err != nil {
+				return "", fmt.Errorf("input %s: %v", input.id, err)
+			}
+		}
+		props = append(props, fmt.Sprintf("%s: %s", jsString(input.id), value))
+	}
+	return "{" + strings.Join(props, ", ") + "}", nil
+}
+
+// interpolate converts a string with CWL parameter references, such as "--name=$(inputs.name)", to a JavaScript
+// expression. A string that consists of a single parameter reference evaluates to the referenced value as is.
+func interpolate(s string) (string, error) {
+	if strings.Contains(s, "${") {
+		return "", errors.New("expressions in ${...} are not supported, only parameter references $(...)")
+	}
+	var parts []string
+	for {
+		start := strings.Index(s, "$(")
+		if start < 0 {
+			break
+		}
+		end := closingParen(s, start+1)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated parameter reference in '%s'", s)
+		}
+		if start > 0 {
+			parts = append(parts, jsString(s[:start]))
+		}
+		parts = append(parts, "("+s[start+2:end]+")")
+		s = s[end+1:]
+	}
+	if len(s) > 0 || len(parts) == 0 {
+		parts = append(parts, jsString(s))
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	for i, part := range parts {
+		parts[i] = "String(" + part + ")"
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// closingParen returns the index of the parenthesis that closes the one at the index, skipping quoted strings, or -1
+// if it is not closed.
+func closingParen(s string, open int) int {
+	var depth int
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// withDefault returns a JavaScript expression that evaluates to the value, or to the default if the value is null.
+func withDefault(value string, def interface{}) (string, error) {
+	bs, err := json.Marshal(def)
+	if err != nil {
+		return "", fmt.Errorf("invalid default: %v", err)
+	}
+	if value == "undefined" {
+		return string(bs), nil
+	}
+	return fmt.Sprintf("(function(v) { return v === undefined || v === null ? %s : v; })(%s)", bs, value), nil
+}
+
+// containerRef returns the function reference of the container function environment for the image. The registry of
+// the image, if any, is the namespace of the reference.
+func containerRef(image string) string {
+	if i := strings.Index(image, "/"); i >= 0 {
+		registry := image[:i]
+		if strings.ContainsAny(registry, ".:") || registry == "localhost" {
+			return "container://" + image
+		}
+	}
+	return "container:///" + image
+}
+
+// sinkTasks returns the ids of the tasks that no other task depends on, in order.
+func sinkTasks(tasks types.Tasks) []string {
+	required := map[string]bool{}
+	for _, task := range tasks {
+		for dep := range task.GetRequires() {
+			required[dep] = true
+		}
+	}
+	var sinks []string
+	for id := range tasks {
+		if !required[id] {
+			sinks = append(sinks, id)
+		}
+	}
+	sort.Strings(sinks)
+	return sinks
+}
+
+func invocationInput(id string) string {
+	return fmt.Sprintf("$.Invocation.Inputs[%s]", jsString(id))
+}
+
+// localID returns the local part of a CWL identifier, which in packed documents is qualified with the ids of the
+// process and step, such as #main/step/input.
+func localID(id string) string {
+	id = strings.TrimPrefix(id, "#")
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+func jsString(s string) string {
+	bs, _ := json.Marshal(s)
+	return string(bs)
+}
+
+func stringValue(i interface{}) string {
+	switch v := i.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func stringList(i interface{}) ([]interface{}, error) {
+	switch v := i.(type) {
+	case string:
+		return []interface{}{v}, nil
+	case []interface{}:
+		for _, e := range v {
+			if _, ok := e.(string); !ok {
+				return nil, errors.New("expected a list of strings")
+			}
+		}
+		return v, nil
+	default:
+		return nil, errors.New("expected a string or a list of strings")
+	}
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch v := m.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	case map[string]string:
+		for k := range v {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// normalize converts the maps that the YAML decoder produces to maps with string keys.
+func normalize(i interface{}) interface{} {
+	switch v := i.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			m[fmt.Sprintf("%v", key)] = normalize(value)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
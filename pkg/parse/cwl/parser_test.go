@@ -0,0 +1,244 @@
+package cwl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const workflow = `
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  message: string
+  count:
+    type: int?
+  greeting:
+    type: string
+    default: hello
+outputs:
+  result:
+    type: string
+    outputSource: shout/out
+steps:
+  echo:
+    run:
+      class: CommandLineTool
+      hints:
+        DockerRequirement:
+          dockerPull: alpine:3.12
+      baseCommand: echo
+      arguments:
+      - -n
+      - valueFrom: $(inputs.greeting), $(inputs.message)!
+        position: 2
+      inputs:
+        greeting: string
+        message: string
+        count:
+          type: int?
+          inputBinding:
+            prefix: --count=
+            separate: false
+            position: 1
+        verbose:
+          type: boolean
+          default: true
+          inputBinding:
+            prefix: -v
+      requirements:
+      - class: EnvVarRequirement
+        envDef:
+          MESSAGE: $(inputs.message)
+      outputs:
+        out: stdout
+    in:
+      greeting: greeting
+      message: message
+      count: count
+    out: [out]
+  shout:
+    run: shout
+    in:
+      text: echo/out
+      volume:
+        default: 11
+    out: [out]
+`
+
+func TestParseWorkflow(t *testing.T) {
+	spec, err := Parse(strings.NewReader(workflow))
+	require.NoError(t, err)
+	assert.NoError(t, validate.WorkflowSpec(spec))
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"message": {"type": "string"},
+			"count": {"type": "integer"},
+			"greeting": {"type": "string"}
+		},
+		"required": ["message"]
+	}`, spec.InputSchema)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"greeting": typedvalues.MustWrap("hello"),
+	}, spec.DefaultInputs)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"result": typedvalues.MustWrap(`{output("shout")}`),
+	}, spec.Outputs)
+
+	echo := spec.Tasks["echo"]
+	assert.Equal(t, "container:///alpine:3.12", echo.FunctionRef)
+	assert.Empty(t, echo.Requires)
+	assert.Equal(t, []interface{}{"echo"}, typedvalues.MustUnwrap(echo.Inputs["command"]))
+
+	shout := spec.Tasks["shout"]
+	assert.Equal(t, "shout", shout.FunctionRef)
+	assert.Equal(t, types.Requires{"echo": {}}, types.Requires(shout.Requires))
+	assert.Equal(t, int32(1), shout.Await)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"text":   typedvalues.MustWrap(`{output("echo")}`),
+		"volume": typedvalues.MustWrap(11),
+	}, shout.Inputs)
+
+	// The command line and environment of the tool are evaluated along with the inputs of the invocation.
+	scope := map[string]interface{}{
+		"Invocation": map[string]interface{}{
+			"Inputs": map[string]interface{}{
+				"greeting": "hi",
+				"message":  "world",
+			},
+		},
+	}
+	args, err := expr.Resolve(scope, "echo", echo.Inputs["args"])
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"-n", "-v", "hi, world!"}, typedvalues.MustUnwrap(args))
+
+	scope["Invocation"].(map[string]interface{})["Inputs"].(map[string]interface{})["count"] = 3
+	args, err = expr.Resolve(scope, "echo", echo.Inputs["args"])
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"-n", "-v", "--count=3", "hi, world!"}, typedvalues.MustUnwrap(args))
+
+	env, err := expr.Resolve(scope, "echo", echo.Inputs["env"])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"MESSAGE": "world"}, typedvalues.MustUnwrap(env))
+}
+
+func TestParsePacked(t *testing.T) {
+	spec, err := Parse(strings.NewReader(`{
+		"cwlVersion": "v1.0",
+		"$graph": [
+			{
+				"id": "#sort",
+				"class": "CommandLineTool",
+				"requirements": [{"class": "DockerRequirement", "dockerPull": "docker.io/library/busybox"}],
+				"baseCommand": ["sort", "-n"],
+				"inputs": [{"id": "#sort/numbers", "type": "int[]", "inputBinding": {"itemSeparator": ","}}],
+				"outputs": []
+			},
+			{
+				"id": "#main",
+				"class": "Workflow",
+				"inputs": [{"id": "#main/a", "type": "int"}, {"id": "#main/b", "type": "int"}],
+				"outputs": [],
+				"steps": [
+					{
+						"id": "#main/sort",
+						"run": "#sort",
+						"in": [{"id": "#main/sort/numbers", "source": ["#main/a", "#main/b"]}],
+						"out": []
+					}
+				]
+			}
+		]
+	}`))
+	require.NoError(t, err)
+	assert.NoError(t, validate.WorkflowSpec(spec))
+	assert.Equal(t, "sort", spec.OutputTask)
+
+	task := spec.Tasks["sort"]
+	assert.Equal(t, "container://docker.io/library/busybox", task.FunctionRef)
+	args, err := expr.Resolve(map[string]interface{}{
+		"Invocation": map[string]interface{}{
+			"Inputs": map[string]interface{}{"a": 3, "b": 1},
+		},
+	}, "sort", task.Inputs["args"])
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"3,1"}, typedvalues.MustUnwrap(args))
+}
+
+func TestParseTool(t *testing.T) {
+	spec, err := Parse(strings.NewReader(`
+cwlVersion: v1.2
+class: CommandLineTool
+id: wc
+requirements:
+  DockerRequirement:
+    dockerPull: alpine
+baseCommand: [wc, -c]
+inputs:
+  path:
+    type: string
+    inputBinding: {}
+outputs: {}
+`))
+	require.NoError(t, err)
+	assert.NoError(t, validate.WorkflowSpec(spec))
+	assert.Equal(t, "wc", spec.OutputTask)
+	assert.Equal(t, "container:///alpine", spec.Tasks["wc"].FunctionRef)
+
+	args, err := expr.Resolve(map[string]interface{}{
+		"Invocation": map[string]interface{}{
+			"Inputs": map[string]interface{}{"path": "/etc/hosts"},
+		},
+	}, "wc", spec.Tasks["wc"].Inputs["args"])
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"/etc/hosts"}, typedvalues.MustUnwrap(args))
+}
+
+func TestParseUnsupported(t *testing.T) {
+	const wf = "cwlVersion: v1.0\nclass: Workflow\ninputs: {}\n"
+	const tool = "cwlVersion: v1.0\nclass: CommandLineTool\ninputs: {}\noutputs: {}\n"
+	for name, doc := range map[string]string{
+		"not CWL":          "output: foo\ntasks:\n  foo:\n    run: noop\n",
+		"CWL version":      "cwlVersion: draft-3\nclass: Workflow\n",
+		"class":            "cwlVersion: v1.0\nclass: ExpressionTool\n",
+		"external tool":    wf + "outputs: {}\nsteps:\n  a:\n    run: a.cwl\n",
+		"scatter":          wf + "outputs: {}\nsteps:\n  a:\n    run: a\n    scatter: x\n",
+		"unknown source":   wf + "outputs: {}\nsteps:\n  a:\n    run: a\n    in: {x: y}\n",
+		"unknown step ref": wf + "outputs: {x: {outputSource: b/out}}\nsteps:\n  a:\n    run: a\n",
+		"no docker":        tool + "baseCommand: ls\n",
+		"script":           tool + "hints: {DockerRequirement: {dockerPull: alpine}}\narguments: ['${return 1}']\n",
+	} {
+		_, err := Parse(strings.NewReader(doc))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	for input, expected := range map[string]string{
+		"plain":                         `"plain"`,
+		"$(inputs.x)":                   `(inputs.x)`,
+		"--name=$(inputs.name)":         `String("--name=") + String((inputs.name))`,
+		"$(inputs.a)-$(inputs['b(c)'])": `String((inputs.a)) + String("-") + String((inputs['b(c)']))`,
+	} {
+		js, err := interpolate(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, js, input)
+	}
+	_, err := interpolate("$(inputs.x")
+	assert.Error(t, err)
+}
+
+func TestContainerRef(t *testing.T) {
+	assert.Equal(t, "container:///alpine:3.12", containerRef("alpine:3.12"))
+	assert.Equal(t, "container:///library/alpine", containerRef("library/alpine"))
+	assert.Equal(t, "container://quay.io/org/tool:1.0", containerRef("quay.io/org/tool:1.0"))
+	assert.Equal(t, "container://localhost/tool", containerRef("localhost/tool"))
+}
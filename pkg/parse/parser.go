@@ -1,9 +1,15 @@
 package parse
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
+	"sort"
 
+	"github.com/fission/fission-workflows/pkg/parse/bpmn"
+	"github.com/fission/fission-workflows/pkg/parse/cwl"
 	"github.com/fission/fission-workflows/pkg/parse/protobuf"
 	"github.com/fission/fission-workflows/pkg/parse/yaml"
 	"github.com/fission/fission-workflows/pkg/types"
@@ -14,6 +20,8 @@ var (
 	DefaultParser = NewMetaParser(map[string]Parser{
 		"yaml": yaml.DefaultParser,
 		"pb":   protobuf.DefaultParser,
+		"cwl":  cwl.DefaultParser,
+		"bpmn": bpmn.DefaultParser,
 	})
 )
 
@@ -47,6 +55,8 @@ func NewMetaParser(parsers map[string]Parser) *MetaParser {
 	}
 }
 
+// Parse parses the workflow with the first parser that succeeds, trying the parsers in the order of Parsers. With the
+// default parsers, the stricter formats (bpmn, cwl, pb) are tried before the yaml parser, which ignores unknown fields.
 func (mp *MetaParser) Parse(r io.Reader) (*types.WorkflowSpec, error) {
 	return mp.ParseWith(r, mp.Parsers()...)
 }
@@ -55,22 +65,26 @@ func (mp *MetaParser) ParseWith(r io.Reader, parsers ...string) (*types.Workflow
 	if parsers == nil {
 		return nil, errors.New("no parsers provided")
 	}
+	// Every parser reads the whole definition, so it is buffered.
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow definition: %v", err)
+	}
 	var result *types.WorkflowSpec
 	for _, name := range parsers {
 		p, ok := mp.parsers[name]
 		if !ok {
 			continue
 		}
-		wf, err := p.Parse(r)
+		wf, err := p.Parse(bytes.NewReader(bs))
 		if err != nil {
-			logrus.WithField("parser", name).Warnf("parser failed: %v", err)
+			logrus.WithField("parser", name).Debugf("parser failed: %v", err)
 			wf = nil
 			continue
 		}
 		result = wf
 		break
 	}
-	var err error
 	if result == nil {
 		err = errors.New("failed to parse workflow")
 	}
@@ -82,6 +96,7 @@ func (mp *MetaParser) Supports(s string) bool {
 	return ok
 }
 
+// Parsers returns the names of the parsers, in alphabetical order.
 func (mp *MetaParser) Parsers() []string {
 	ps := make([]string, len(mp.parsers))
 	var i int
@@ -89,5 +104,6 @@ func (mp *MetaParser) Parsers() []string {
 		ps[i] = name
 		i++
 	}
+	sort.Strings(ps)
 	return ps
 }
@@ -1,14 +1,22 @@
 package yaml
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v2"
 )
@@ -21,10 +29,24 @@ func Parse(r io.Reader) (*types.WorkflowSpec, error) {
 	return DefaultParser.Parse(r)
 }
 
-type Parser struct{}
+// Parser parses YAML workflow definitions.
+type Parser struct {
+	// Strict fails the parsing of definitions that contain unknown fields, such as misspelled fields, which are
+	// ignored otherwise.
+	Strict bool
+}
 
 func (p *Parser) Parse(r io.Reader) (*types.WorkflowSpec, error) {
-	b, err := read(r)
+	bs, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow definition: %v", err)
+	}
+	if p.Strict {
+		if err := CheckFields(bs); err != nil {
+			return nil, err
+		}
+	}
+	b, err := read(bytes.NewReader(bs))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read workflow definition: %v", err)
 	}
@@ -51,6 +73,77 @@ func read(r io.Reader) (*workflowSpec, error) {
 	return i, nil
 }
 
+var (
+	workflowFields = fieldNames(workflowSpec{})
+	taskFields     = fieldNames(taskSpec{})
+)
+
+// CheckFields returns a validate.Error with a diagnostic for each field of the YAML workflow definition that is not
+// part of the workflow definition. The fields are matched case-insensitively, so "apiVersion" is not reported.
+func CheckFields(data []byte) error {
+	var def interface{}
+	if err := Unmarshal(data, &def); err != nil {
+		return fmt.Errorf("failed to read workflow definition: %v", err)
+	}
+	wf, ok := def.(map[string]interface{})
+	if !ok {
+		return validate.NewError("workflow definition", errors.New("workflow definition is not a map"))
+	}
+	errs := unknownFields(wf, workflowFields)
+	if tasks, ok := wf["tasks"].(map[string]interface{}); ok {
+		for _, id := range sortedKeys(tasks) {
+			task, ok := tasks[id].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, err := range unknownFields(task, taskFields) {
+				errs = append(errs, validate.InTask(id, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return validate.NewError("workflow definition", errs...)
+	}
+	return nil
+}
+
+func unknownFields(def map[string]interface{}, fields map[string]bool) []error {
+	var errs []error
+	for _, key := range sortedKeys(def) {
+		if !fields[strings.ToLower(key)] {
+			errs = append(errs, validate.Diagnostic{
+				Reason: validate.ErrUnknownField,
+				Detail: key,
+				Field:  key,
+			})
+		}
+	}
+	return errs
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// fieldNames returns the lowercased YAML names of the fields of a YAML data structure.
+func fieldNames(v interface{}) map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if len(name) == 0 {
+			name = t.Field(i).Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
+
 func parseWorkflow(def *workflowSpec) (*types.WorkflowSpec, error) {
 
 	tasks := map[string]*types.TaskSpec{}
@@ -67,17 +160,88 @@ func parseWorkflow(def *workflowSpec) (*types.WorkflowSpec, error) {
 		tasks[id] = p
 	}
 
+	inputSchema, err := parseInputSchema(def.InputSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaultInputs map[string]*typedvalues.TypedValue
+	if def.DefaultInputs != nil {
+		defaultInputs, err = parseInputs(def.DefaultInputs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var consts map[string]*typedvalues.TypedValue
+	if def.Consts != nil {
+		consts, err = parseInputs(def.Consts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var outputs map[string]*typedvalues.TypedValue
+	if def.Outputs != nil {
+		outputs, err = parseInputs(def.Outputs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var concurrencyPolicy types.WorkflowSpec_ConcurrencyPolicy
+	if len(def.ConcurrencyPolicy) > 0 {
+		policy, ok := types.WorkflowSpec_ConcurrencyPolicy_value[strings.ToUpper(def.ConcurrencyPolicy)]
+		if !ok {
+			return nil, fmt.Errorf("unknown concurrency policy '%s' (expected allow, forbid, queue or replace)",
+				def.ConcurrencyPolicy)
+		}
+		concurrencyPolicy = types.WorkflowSpec_ConcurrencyPolicy(policy)
+	}
+
 	return &types.WorkflowSpec{
-		ApiVersion: def.APIVersion,
-		OutputTask: def.Output,
-		Tasks:      tasks,
+		ApiVersion:        def.APIVersion,
+		OutputTask:        def.Output,
+		Namespace:         def.Namespace,
+		Labels:            def.Labels,
+		Annotations:       def.Annotations,
+		Tasks:             tasks,
+		InputSchema:       inputSchema,
+		DefaultInputs:     defaultInputs,
+		Consts:            consts,
+		Outputs:           outputs,
+		ConcurrencyPolicy: concurrencyPolicy,
 	}, nil
 }
 
+// parseInputSchema converts the input schema of a workflow, which is typically written as a YAML map, to its JSON
+// representation. A schema that is written as a string is assumed to be JSON already.
+func parseInputSchema(i interface{}) (string, error) {
+	switch v := i.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	bs, err := json.Marshal(cleanupMapValue(i))
+	if err != nil {
+		return "", fmt.Errorf("invalid input schema: %v", err)
+	}
+	return string(bs), nil
+}
+
 func parseTask(t *taskSpec) (*types.TaskSpec, error) {
 	deps := map[string]*types.TaskDependencyParameters{}
 	for _, dep := range t.Requires {
-		deps[dep] = &types.TaskDependencyParameters{}
+		params := &types.TaskDependencyParameters{Artifacts: dep.Artifacts}
+		switch strings.ToLower(dep.Type) {
+		case "", "data":
+		case "control":
+			params.Type = types.TaskDependencyParameters_CONTROL
+		default:
+			return nil, fmt.Errorf("unknown type '%s' of dependency '%s' (expected data or control)", dep.Type, dep.Task)
+		}
+		deps[dep.Task] = params
 	}
 
 	inputs, err := parseInputs(t.Inputs)
@@ -91,10 +255,30 @@ func parseTask(t *taskSpec) (*types.TaskSpec, error) {
 	}
 
 	result := &types.TaskSpec{
-		FunctionRef: fn,
-		Requires:    deps,
-		Await:       int32(len(deps)),
-		Inputs:      inputs,
+		FunctionRef:  fn,
+		Requires:     deps,
+		Await:        int32(len(deps)),
+		Inputs:       inputs,
+		InputSchemas: t.InputSchemas,
+		OutputSchema: t.OutputSchema,
+		Idempotent:   t.Idempotent,
+
+		MaxRetries:           t.MaxRetries,
+		RetryableStatusCodes: t.RetryableStatusCodes,
+	}
+	if t.When != nil {
+		when, err := parseInput(t.When)
+		if err != nil {
+			return nil, fmt.Errorf("invalid condition: %v", err)
+		}
+		result.When = when
+	}
+	if len(t.Backoff) > 0 {
+		backoff, err := time.ParseDuration(t.Backoff)
+		if err != nil {
+			return nil, fmt.Errorf("invalid backoff '%s': %v", t.Backoff, err)
+		}
+		result.Backoff = ptypes.DurationProto(backoff)
 	}
 
 	return result, nil
@@ -146,12 +330,14 @@ func parseInput(i interface{}) (*typedvalues.TypedValue, error) {
 		for k, v := range t {
 			mp, ok := v.(map[interface{}]interface{})
 			if ok {
-				t[k] = convertInterfaceMaps(mp)
+				t[k] = parseValueFrom(convertInterfaceMaps(mp))
 			}
 		}
 	case map[interface{}]interface{}:
 		res := convertInterfaceMaps(t)
-		if _, ok := res["run"]; ok {
+		if ref, ok := parseValueFrom(res).(*typedvalues.TypedValue); ok {
+			i = ref
+		} else if _, ok := res["run"]; ok {
 			// The input might be a task
 			td := &taskSpec{}
 			bs, err := json.Marshal(res)
@@ -217,27 +403,126 @@ func convertInterfaceMaps(src map[interface{}]interface{}) map[string]interface{
 	res := map[string]interface{}{}
 	for k, v := range src {
 		if ii, ok := v.(map[interface{}]interface{}); ok {
-			v = convertInterfaceMaps(ii)
+			v = parseValueFrom(convertInterfaceMaps(ii))
 		}
 		res[fmt.Sprintf("%v", k)] = v
 	}
 	return res
 }
 
+// parseValueFrom parses a reference to a key of a secret or configmap, which has the same format as the value
+// sources of environment variables in Kubernetes:
+//
+//	valueFrom:
+//	  secretKeyRef:      # or configMapKeyRef
+//	    name: my-api
+//	    key: token
+//
+// Secrets can also be referenced with the shorthand:
+//
+//	secret: my-api/token
+//
+// If the map is not a reference, it is returned as is.
+func parseValueFrom(m map[string]interface{}) interface{} {
+	if ref, ok := m["secret"].(string); ok && len(m) == 1 {
+		parts := strings.SplitN(ref, "/", 2)
+		if len(parts) == 2 && len(parts[0]) > 0 && len(parts[1]) > 0 {
+			return typedvalues.MustWrap(&typedvalues.KeyRef{
+				Kind: typedvalues.KeyRefSecret,
+				Name: parts[0],
+				Key:  parts[1],
+			})
+		}
+		return m
+	}
+	valueFrom, ok := m["valueFrom"].(map[string]interface{})
+	if !ok || len(m) != 1 || len(valueFrom) != 1 {
+		return m
+	}
+	for kind, keyRefKind := range map[string]string{
+		"secretKeyRef":    typedvalues.KeyRefSecret,
+		"configMapKeyRef": typedvalues.KeyRefConfigMap,
+	} {
+		selector, ok := valueFrom[kind].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := selector["name"].(string)
+		key, _ := selector["key"].(string)
+		if len(name) == 0 || len(key) == 0 {
+			return m
+		}
+		return typedvalues.MustWrap(&typedvalues.KeyRef{
+			Kind: keyRefKind,
+			Name: name,
+			Key:  key,
+		})
+	}
+	return m
+}
+
 //
 // YAML data structures
 //
 
 type workflowSpec struct {
-	APIVersion  string
-	Description string
-	Output      string
-	Tasks       map[string]*taskSpec
+	APIVersion        string
+	Description       string
+	Output            string
+	Namespace         string
+	Labels            map[string]string
+	Annotations       map[string]string
+	Tasks             map[string]*taskSpec
+	InputSchema       interface{} `yaml:"inputSchema" json:"inputSchema"`
+	DefaultInputs     interface{} `yaml:"defaultInputs" json:"defaultInputs"`
+	Consts            interface{}
+	Outputs           interface{}
+	ConcurrencyPolicy string `yaml:"concurrencyPolicy" json:"concurrencyPolicy"`
 }
 
 type taskSpec struct {
-	ID       string
-	Run      string
-	Inputs   interface{}
-	Requires []string
+	ID           string
+	Run          string
+	Inputs       interface{}
+	Requires     []dependency
+	InputSchemas map[string]string `yaml:"inputSchemas" json:"inputSchemas"`
+	OutputSchema string            `yaml:"outputSchema" json:"outputSchema"`
+	Idempotent   bool
+	When         interface{}
+
+	// Retry configuration of the task; the backoff is a duration, such as 500ms.
+	MaxRetries           int32   `yaml:"maxRetries" json:"maxRetries"`
+	Backoff              string  `yaml:"backoff" json:"backoff"`
+	RetryableStatusCodes []int32 `yaml:"retryableStatusCodes" json:"retryableStatusCodes"`
+}
+
+// dependency is a dependency of a task, which is written either as the id of the task, for a data dependency, or as a
+// map with the task, the type of the dependency (data or control) and the artifacts that the task uses:
+//
+//	requires:
+//	- fetch
+//	- task: notify
+//	  type: control
+//	- task: resize
+//	  artifacts: [thumbnail]
+type dependency struct {
+	Task      string
+	Type      string
+	Artifacts []string
+}
+
+func (d *dependency) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&d.Task); err == nil {
+		return nil
+	}
+	type plain dependency
+	return unmarshal((*plain)(d))
+}
+
+func (d *dependency) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &d.Task); err == nil {
+		return nil
+	}
+	type plain dependency
+	return json.Unmarshal(data, (*plain)(d))
 }
@@ -3,11 +3,15 @@ package yaml
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"fmt"
 
+	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/fission/fission-workflows/pkg/types/validate"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -38,6 +42,11 @@ func TestParseSimple(t *testing.T) {
 	data := `
 apiversion: 123
 output: $.tasks.foo.output
+namespace: team-a
+labels:
+  team: payments
+annotations:
+  owner: alice@example.com
 tasks:
   foo:
     run: someSh
@@ -62,6 +71,9 @@ tasks:
 
 	assert.Equal(t, wf.OutputTask, wfd.Output)
 	assert.Equal(t, wf.ApiVersion, wfd.APIVersion)
+	assert.Equal(t, "team-a", wf.Namespace)
+	assert.Equal(t, map[string]string{"team": "payments"}, wf.Labels)
+	assert.Equal(t, map[string]string{"owner": "alice@example.com"}, wf.Annotations)
 	for id, task := range wfd.Tasks {
 		if len(task.Run) == 0 {
 			assert.Equal(t, wf.Tasks[id].FunctionRef, "noop")
@@ -161,3 +173,353 @@ tasks:
 	assert.NoError(t, err)
 	assert.NotNil(t, wf)
 }
+
+func TestParseSchemas(t *testing.T) {
+	data := `
+apiversion: 123
+output: foo
+tasks:
+  foo:
+    run: someSh
+    inputs:
+      default: bar
+    inputSchemas:
+      default: protobuf:google.protobuf.StringValue
+    outputSchema: avro:42
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]string{"default": "protobuf:google.protobuf.StringValue"}, wf.Tasks["foo"].InputSchemas)
+	assert.Equal(t, "avro:42", wf.Tasks["foo"].OutputSchema)
+}
+
+func TestParseInputSchema(t *testing.T) {
+	data := `
+output: foo
+inputSchema:
+  type: object
+  required: [name]
+  properties:
+    name:
+      type: string
+tasks:
+  foo:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.JSONEq(t, `{"type": "object", "required": ["name"], "properties": {"name": {"type": "string"}}}`,
+		wf.InputSchema)
+}
+
+func TestParseDefaultInputs(t *testing.T) {
+	data := `
+output: foo
+defaultInputs:
+  greeting: hello
+  times: 2
+tasks:
+  foo:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"greeting": typedvalues.MustWrap("hello"),
+		"times":    typedvalues.MustWrap(2),
+	}, wf.DefaultInputs)
+}
+
+func TestParseConsts(t *testing.T) {
+	data := `
+output: foo
+consts:
+  bucket: uploads
+  retries: 3
+tasks:
+  foo:
+    run: someSh
+    inputs: "{$.Consts.bucket}"
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"bucket":  typedvalues.MustWrap("uploads"),
+		"retries": typedvalues.MustWrap(3),
+	}, wf.Consts)
+}
+
+func TestParseOutputs(t *testing.T) {
+	data := `
+outputs:
+  name: "{$.Tasks.foo.Output}"
+  static: 42
+tasks:
+  foo:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, wf.OutputTask)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"name":   typedvalues.MustWrap("{$.Tasks.foo.Output}"),
+		"static": typedvalues.MustWrap(42),
+	}, wf.Outputs)
+}
+
+func TestParseDependencies(t *testing.T) {
+	data := `
+output: notify
+tasks:
+  fetch:
+    run: someSh
+  resize:
+    run: someSh
+    requires:
+    - fetch
+  notify:
+    run: someSh
+    requires:
+    - task: fetch
+      type: control
+    - task: resize
+      artifacts: [thumbnail]
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.Requires{"fetch": {}}, types.Requires(wf.Tasks["resize"].Requires))
+	assert.Equal(t, types.Requires{
+		"fetch":  {Type: types.TaskDependencyParameters_CONTROL},
+		"resize": {Artifacts: []string{"thumbnail"}},
+	}, types.Requires(wf.Tasks["notify"].Requires))
+	assert.Equal(t, int32(2), wf.Tasks["notify"].Await)
+
+	_, err = parseTask(&taskSpec{Run: "someSh", Requires: []dependency{{Task: "fetch", Type: "unknown"}}})
+	assert.Error(t, err)
+}
+
+func TestParseConcurrencyPolicy(t *testing.T) {
+	data := `
+output: foo
+concurrencyPolicy: Replace
+tasks:
+  foo:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, types.WorkflowSpec_REPLACE, wf.ConcurrencyPolicy)
+
+	wfd.ConcurrencyPolicy = "singleton"
+	_, err = parseWorkflow(wfd)
+	assert.Error(t, err)
+}
+
+func TestParseIdempotent(t *testing.T) {
+	data := `
+apiversion: 123
+output: foo
+tasks:
+  foo:
+    run: someSh
+    idempotent: true
+  bar:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, wf.Tasks["foo"].Idempotent)
+	assert.False(t, wf.Tasks["bar"].Idempotent)
+}
+
+func TestParseRetry(t *testing.T) {
+	data := `
+output: foo
+tasks:
+  foo:
+    run: someSh
+    maxRetries: 3
+    backoff: 500ms
+    retryableStatusCodes: [502, 503]
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := wf.Tasks["foo"]
+	assert.Equal(t, int32(3), task.MaxRetries)
+	assert.Equal(t, ptypes.DurationProto(500*time.Millisecond), task.Backoff)
+	assert.Equal(t, []int32{502, 503}, task.RetryableStatusCodes)
+	assert.NoError(t, CheckFields([]byte(strings.TrimSpace(data))))
+
+	wfd.Tasks["foo"].Backoff = "soon"
+	_, err = parseWorkflow(wfd)
+	assert.Error(t, err)
+}
+
+func TestParseWhen(t *testing.T) {
+	data := `
+output: foo
+tasks:
+  foo:
+    run: someSh
+    when: "{ $.Invocation.Inputs.enabled }"
+  bar:
+    run: someSh
+    when: false
+  baz:
+    run: someSh
+`
+	wfd, err := read(strings.NewReader(strings.TrimSpace(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf, err := parseWorkflow(wfd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, typedvalues.TypeExpression, wf.Tasks["foo"].When.ValueType())
+	assert.Equal(t, typedvalues.MustWrap(false), wf.Tasks["bar"].When)
+	assert.Nil(t, wf.Tasks["baz"].When)
+}
+
+func TestParseValueFrom(t *testing.T) {
+	data := `
+output: foo
+tasks:
+  foo:
+    run: fission://api
+    inputs:
+      token:
+        valueFrom:
+          secretKeyRef:
+            name: my-api
+            key: token
+      headers:
+        X-Host:
+          valueFrom:
+            configMapKeyRef:
+              name: config
+              key: host
+      password:
+        secret: my-db/password
+      body:
+        valueFrom: not-a-reference
+      query:
+        secret: not-a-reference
+`
+	wf, err := Parse(strings.NewReader(strings.TrimSpace(data)))
+	assert.NoError(t, err)
+	inputs := wf.Tasks["foo"].Inputs
+
+	ref, err := typedvalues.UnwrapKeyRef(inputs["token"])
+	assert.NoError(t, err)
+	assert.Equal(t, &typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-api", Key: "token"}, ref)
+
+	headers, err := typedvalues.UnwrapMap(inputs["headers"])
+	assert.NoError(t, err)
+	assert.Equal(t, &typedvalues.KeyRef{Kind: typedvalues.KeyRefConfigMap, Name: "config", Key: "host"},
+		headers["X-Host"])
+
+	ref, err = typedvalues.UnwrapKeyRef(inputs["password"])
+	assert.NoError(t, err)
+	assert.Equal(t, &typedvalues.KeyRef{Kind: typedvalues.KeyRefSecret, Name: "my-db", Key: "password"}, ref)
+
+	body, err := typedvalues.Unwrap(inputs["body"])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"valueFrom": "not-a-reference"}, body)
+
+	query, err := typedvalues.Unwrap(inputs["query"])
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"secret": "not-a-reference"}, query)
+}
+
+func TestCheckFields(t *testing.T) {
+	data := `
+apiVersion: 1
+output: foo
+ouput: foo
+tasks:
+  foo:
+    run: noop
+    requries:
+    - bar
+  bar:
+    run: noop
+`
+	err := CheckFields([]byte(strings.TrimSpace(data)))
+	assert.Error(t, err)
+	diagnostics := validate.Diagnostics(err)
+	assert.Len(t, diagnostics, 2)
+	assert.Equal(t, validate.ErrUnknownField, diagnostics[0].Reason)
+	assert.Equal(t, "ouput", diagnostics[0].Field)
+	assert.Equal(t, "foo", diagnostics[1].TaskID)
+	assert.Equal(t, "tasks.foo.requries", diagnostics[1].Field)
+
+	_, err = (&Parser{Strict: true}).Parse(strings.NewReader(strings.TrimSpace(data)))
+	assert.Error(t, err)
+	_, err = Parse(strings.NewReader(strings.TrimSpace(data)))
+	assert.NoError(t, err)
+}
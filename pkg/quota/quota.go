@@ -0,0 +1,224 @@
+// Package quota limits the resources that the workflows and invocations of a namespace can use of the shared
+// workflow engine.
+//
+// The namespace of a workflow is the namespace in its spec; the invocations of a workflow are in the namespace of the
+// workflow. Workflows without a namespace are in the DefaultNamespace.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultNamespace is the namespace of the workflows that do not specify a namespace.
+const DefaultNamespace = "default"
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+var (
+	exceededQuotas = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "quota",
+		Name:      "exceeded_total",
+		Help:      "Total number of invocations, tasks and payloads that exceeded the quota of their namespace",
+	}, []string{"namespace", "quota"})
+
+	activeInvocations = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "quota",
+		Name:      "active_invocations",
+		Help:      "Number of invocations that are running, by namespace",
+	}, []string{"namespace"})
+)
+
+func init() {
+	prometheus.MustRegister(exceededQuotas, activeInvocations)
+}
+
+// Quota limits the resources of a namespace. A limit of 0 inherits the default quota; a negative limit is
+// unlimited.
+type Quota struct {
+	// MaxConcurrentInvocations is the maximum number of invocations that run at the same time. New invocations are
+	// rejected once the limit is reached.
+	MaxConcurrentInvocations int `yaml:"maxConcurrentInvocations"`
+
+	// MaxTasksPerSecond is the maximum rate at which tasks are started. Tasks that exceed the rate are delayed.
+	MaxTasksPerSecond float64 `yaml:"maxTasksPerSecond"`
+
+	// MaxPayloadSize is the maximum size (in bytes) of the inputs of an invocation or a task.
+	MaxPayloadSize int64 `yaml:"maxPayloadSize"`
+}
+
+func (q Quota) inherit(defaults Quota) Quota {
+	if q.MaxConcurrentInvocations == 0 {
+		q.MaxConcurrentInvocations = defaults.MaxConcurrentInvocations
+	}
+	if q.MaxTasksPerSecond == 0 {
+		q.MaxTasksPerSecond = defaults.MaxTasksPerSecond
+	}
+	if q.MaxPayloadSize == 0 {
+		q.MaxPayloadSize = defaults.MaxPayloadSize
+	}
+	return q
+}
+
+// Config contains the default quota and the quotas of specific namespaces.
+type Config struct {
+	Default    Quota            `yaml:"default"`
+	Namespaces map[string]Quota `yaml:"namespaces"`
+}
+
+// LoadConfig reads the quotas from a YAML (or JSON) file:
+//
+//	default:
+//	  maxConcurrentInvocations: 100
+//	  maxTasksPerSecond: 50
+//	  maxPayloadSize: 1048576
+//	namespaces:
+//	  payments:
+//	    maxConcurrentInvocations: 500
+//	  batch:
+//	    maxTasksPerSecond: -1
+func LoadConfig(path string) (*Config, error) {
+	bs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read quotas: %v", err)
+	}
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(bs, config); err != nil {
+		return nil, fmt.Errorf("failed to parse quotas: %v", err)
+	}
+	return config, nil
+}
+
+// Manager enforces the quotas of the namespaces.
+//
+// The running invocations are tracked in memory: the API server tracks the invocations that it creates, and the
+// controller tracks the invocations that it evaluates until they have finished.
+type Manager struct {
+	config   Config
+	mu       sync.Mutex
+	active   map[string]map[string]struct{}
+	limiters map[string]*rate.Limiter
+}
+
+func NewManager(config Config) *Manager {
+	return &Manager{
+		config:   config,
+		active:   map[string]map[string]struct{}{},
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// Namespace returns the namespace, or the DefaultNamespace if it is empty.
+func Namespace(namespace string) string {
+	if len(namespace) == 0 {
+		return DefaultNamespace
+	}
+	return namespace
+}
+
+// Quota returns the effective quota of the namespace.
+func (m *Manager) Quota(namespace string) Quota {
+	return m.config.Namespaces[Namespace(namespace)].inherit(m.config.Default)
+}
+
+// AdmitInvocations returns an error wrapping ErrQuotaExceeded if starting count invocations would exceed the maximum
+// number of running invocations of the namespace.
+func (m *Manager) AdmitInvocations(namespace string, count int) error {
+	namespace = Namespace(namespace)
+	max := m.Quota(namespace).MaxConcurrentInvocations
+	if max <= 0 {
+		return nil
+	}
+	m.mu.Lock()
+	running := len(m.active[namespace])
+	m.mu.Unlock()
+	if running+count > max {
+		exceededQuotas.WithLabelValues(namespace, "concurrent_invocations").Inc()
+		return fmt.Errorf("%v: namespace '%s' has %d running invocations (max: %d)", ErrQuotaExceeded, namespace,
+			running, max)
+	}
+	return nil
+}
+
+// Track marks the invocation as running in the namespace.
+func (m *Manager) Track(namespace string, invocationID string) {
+	namespace = Namespace(namespace)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invocations, ok := m.active[namespace]
+	if !ok {
+		invocations = map[string]struct{}{}
+		m.active[namespace] = invocations
+	}
+	invocations[invocationID] = struct{}{}
+	activeInvocations.WithLabelValues(namespace).Set(float64(len(invocations)))
+}
+
+// Release marks the invocation as finished.
+func (m *Manager) Release(namespace string, invocationID string) {
+	namespace = Namespace(namespace)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	invocations, ok := m.active[namespace]
+	if !ok {
+		return
+	}
+	delete(invocations, invocationID)
+	activeInvocations.WithLabelValues(namespace).Set(float64(len(invocations)))
+}
+
+// AllowTask returns whether a task of the namespace can be started now, without exceeding its maximum task rate.
+func (m *Manager) AllowTask(namespace string) bool {
+	namespace = Namespace(namespace)
+	perSecond := m.Quota(namespace).MaxTasksPerSecond
+	if perSecond <= 0 {
+		return true
+	}
+	m.mu.Lock()
+	limiter, ok := m.limiters[namespace]
+	if !ok {
+		// Allow a burst of a second's worth of tasks, and at least a single task.
+		burst := int(perSecond)
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(perSecond), burst)
+		m.limiters[namespace] = limiter
+	}
+	m.mu.Unlock()
+	if !limiter.Allow() {
+		exceededQuotas.WithLabelValues(namespace, "tasks_per_second").Inc()
+		return false
+	}
+	return true
+}
+
+// CheckPayload returns an error wrapping ErrQuotaExceeded if the total size of the inputs exceeds the maximum
+// payload size of the namespace.
+func (m *Manager) CheckPayload(namespace string, inputs map[string]*typedvalues.TypedValue) error {
+	namespace = Namespace(namespace)
+	max := m.Quota(namespace).MaxPayloadSize
+	if max <= 0 {
+		return nil
+	}
+	var size int64
+	for _, input := range inputs {
+		size += int64(proto.Size(input))
+	}
+	if size > max {
+		exceededQuotas.WithLabelValues(namespace, "payload_size").Inc()
+		return fmt.Errorf("%v: payload of %d bytes exceeds the maximum of %d bytes of namespace '%s'",
+			ErrQuotaExceeded, size, max, namespace)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package quota
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	f, err := ioutil.TempFile("", "quotas")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(`
+default:
+  maxConcurrentInvocations: 10
+  maxPayloadSize: 1024
+namespaces:
+  batch:
+    maxConcurrentInvocations: -1
+    maxTasksPerSecond: 5
+`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	config, err := LoadConfig(f.Name())
+	require.NoError(t, err)
+	m := NewManager(*config)
+
+	assert.Equal(t, Quota{MaxConcurrentInvocations: 10, MaxPayloadSize: 1024}, m.Quota(""))
+	assert.Equal(t, Quota{MaxConcurrentInvocations: -1, MaxTasksPerSecond: 5, MaxPayloadSize: 1024},
+		m.Quota("batch"))
+}
+
+func TestLoadConfigUnknownField(t *testing.T) {
+	f, err := ioutil.TempFile("", "quotas")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("default:\n  maxInvocations: 10\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	_, err = LoadConfig(f.Name())
+	assert.Error(t, err)
+}
+
+func TestManager_AdmitInvocations(t *testing.T) {
+	m := NewManager(Config{
+		Default: Quota{MaxConcurrentInvocations: 2},
+		Namespaces: map[string]Quota{
+			"batch": {MaxConcurrentInvocations: -1},
+		},
+	})
+
+	assert.NoError(t, m.AdmitInvocations("", 2))
+	assert.Error(t, m.AdmitInvocations("", 3))
+
+	m.Track("", "wi-1")
+	m.Track(DefaultNamespace, "wi-2")
+	err := m.AdmitInvocations(DefaultNamespace, 1)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), ErrQuotaExceeded.Error()))
+
+	// Other namespaces are not affected.
+	assert.NoError(t, m.AdmitInvocations("other", 1))
+	assert.NoError(t, m.AdmitInvocations("batch", 1000))
+
+	m.Release("", "wi-1")
+	assert.NoError(t, m.AdmitInvocations(DefaultNamespace, 1))
+}
+
+func TestManager_AllowTask(t *testing.T) {
+	m := NewManager(Config{
+		Namespaces: map[string]Quota{
+			"limited": {MaxTasksPerSecond: 2},
+		},
+	})
+
+	assert.True(t, m.AllowTask("limited"))
+	assert.True(t, m.AllowTask("limited"))
+	assert.False(t, m.AllowTask("limited"))
+
+	for i := 0; i < 10; i++ {
+		assert.True(t, m.AllowTask("unlimited"))
+	}
+}
+
+func TestManager_CheckPayload(t *testing.T) {
+	m := NewManager(Config{
+		Default: Quota{MaxPayloadSize: 100},
+	})
+
+	assert.NoError(t, m.CheckPayload("", typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		"default": "small",
+	})))
+	assert.Error(t, m.CheckPayload("", typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		"default": strings.Repeat("a", 200),
+	})))
+}
@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/types"
@@ -13,8 +15,10 @@ var DefaultPolicy = NewHorizonPolicy()
 
 // HorizonPolicy is the default policy of the workflow engine. It solely schedules tasks that are on the scheduling horizon.
 //
-// The scheduling horizon is the set of tasks that only depend on tasks that have already completed.
-// If a task has failed this policy simply fails the workflow
+// The scheduling horizon is the set of tasks that only depend on tasks that have already completed. Tasks that only
+// have control dependencies on tasks on the horizon are scheduled along with these tasks.
+// If a task has failed this policy simply fails the workflow, unless the task is retried. A failed task that is retried
+// is scheduled again once its backoff has passed; until then, it blocks the tasks that depend on it.
 type HorizonPolicy struct {
 }
 
@@ -24,6 +28,7 @@ func NewHorizonPolicy() *HorizonPolicy {
 
 func (p *HorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedule, error) {
 	schedule := &Schedule{InvocationId: invocation.ID(), CreatedAt: ptypes.TimestampNow()}
+	now := time.Now()
 
 	// If there are failed tasks halt the workflow
 	if failedTasks := getFailedTasks(invocation); len(failedTasks) > 0 {
@@ -39,11 +44,11 @@ func (p *HorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedul
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
-	for _, node := range horizon {
-		schedule.AddRunTask(newRunTaskAction(node.(*graph.TaskInvocationNode).Task().ID()))
+	for _, taskRun := range getHorizon(openTasks, now) {
+		schedule.AddRunTask(newRunTaskAction(taskRun.Task().ID()))
+		delete(openTasks, taskRun.GetMetadata().GetId())
 	}
+	schedule.BlockedTasks = getBlockedTasks(invocation, openTasks)
 	return schedule, nil
 }
 
@@ -55,7 +60,8 @@ func (p *HorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedul
 // However, on top of the HorizonPolicy, this policy prewarms tasks aggressively. Any unstarted task not on the
 // scheduling horizon will be prewarmed.
 //
-// This policy does not try to infer runtimes or cold starts; instead, it prewarms with a static duration.
+// The time at which a task is expected to start is estimated from the expected durations of the functions of the tasks
+// that it depends on (see FnHints). Without these hints, the policy prewarms with a static duration.
 type PrewarmAllPolicy struct {
 	coldStartDuration time.Duration
 }
@@ -66,6 +72,7 @@ func NewPrewarmAllPolicy(coldstartDuration time.Duration) *PrewarmAllPolicy {
 
 func (p *PrewarmAllPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedule, error) {
 	schedule := &Schedule{InvocationId: invocation.ID(), CreatedAt: ptypes.TimestampNow()}
+	now := time.Now()
 
 	// If there are failed tasks halt the workflow
 	if failedTasks := getFailedTasks(invocation); len(failedTasks) > 0 {
@@ -81,19 +88,17 @@ func (p *PrewarmAllPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Sche
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
-	for _, node := range horizon {
-		taskRun := node.(*graph.TaskInvocationNode)
-		schedule.AddRunTask(newRunTaskAction(taskRun.TaskInvocation.ID()))
+	for _, taskRun := range getHorizon(openTasks, now) {
+		schedule.AddRunTask(newRunTaskAction(taskRun.Task().ID()))
 		delete(openTasks, taskRun.GetMetadata().GetId())
 	}
 
 	// Prewarm all other tasks
-	expectedAt := time.Now().Add(p.coldStartDuration)
 	for _, task := range openTasks {
+		expectedAt := expectedStart(invocation, openTasks, task.ID(), now, p.coldStartDuration)
 		schedule.AddPrepareTask(newPrepareTaskAction(task.ID(), expectedAt))
 	}
+	schedule.BlockedTasks = getBlockedTasks(invocation, openTasks)
 	return schedule, nil
 }
 
@@ -105,7 +110,8 @@ func (p *PrewarmAllPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Sche
 // However, on top of the HorizonPolicy, tries to policy prewarms tasks aggressively. Any unstarted task on the
 // prewarm horizon will be prewarmed.
 //
-// This policy does not try to infer runtimes or cold starts; instead, it prewarms with a static duration.
+// The time at which a task is expected to start is estimated from the expected durations of the functions of the tasks
+// that it depends on (see FnHints). Without these hints, the policy prewarms with a static duration.
 type PrewarmHorizonPolicy struct {
 	coldStartDuration time.Duration
 }
@@ -116,6 +122,7 @@ func NewPrewarmHorizonPolicy(coldstartDuration time.Duration) *PrewarmHorizonPol
 
 func (p *PrewarmHorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*Schedule, error) {
 	schedule := &Schedule{InvocationId: invocation.ID(), CreatedAt: ptypes.TimestampNow()}
+	now := time.Now()
 
 	// If there are failed tasks halt the workflow
 	if failedTasks := getFailedTasks(invocation); len(failedTasks) > 0 {
@@ -131,30 +138,145 @@ func (p *PrewarmHorizonPolicy) Evaluate(invocation *types.WorkflowInvocation) (*
 
 	// Find and schedule all tasks on the scheduling horizon
 	openTasks := getOpenTasks(invocation)
-	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
-	horizon := graph.Roots(depGraph)
-	for _, node := range horizon {
-		taskRun := node.(*graph.TaskInvocationNode)
-		schedule.AddRunTask(newRunTaskAction(taskRun.TaskInvocation.ID()))
+	for _, taskRun := range getHorizon(openTasks, now) {
+		schedule.AddRunTask(newRunTaskAction(taskRun.Task().ID()))
 		delete(openTasks, taskRun.GetMetadata().GetId())
 	}
 
 	// Prewarm all tasks on the prewarm horizon
 	// Note: we are mutating openTasks!
-	expectedAt := time.Now().Add(p.coldStartDuration)
 	prewarmDepGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
 	prewarmHorizon := graph.Roots(prewarmDepGraph)
 	for _, node := range prewarmHorizon {
 		taskRun := node.(*graph.TaskInvocationNode)
+		expectedAt := expectedStart(invocation, openTasks, taskRun.Task().ID(), now, p.coldStartDuration)
 		schedule.AddPrepareTask(newPrepareTaskAction(taskRun.Task().ID(), expectedAt))
 	}
+	schedule.BlockedTasks = getBlockedTasks(invocation, openTasks)
 
 	return schedule, nil
 }
 
+// expectedStart estimates when the open task will be started. The task is expected to start once the tasks that it
+// depends on have completed, or started for control dependencies, which is estimated along the longest path of the
+// expected durations of their functions. Tasks that have not started yet are assumed to start as soon as their own
+// dependencies have completed. If none of these functions has an expected duration, the task is expected to start
+// after the fallback duration.
+func expectedStart(invocation *types.WorkflowInvocation, openTasks map[string]*types.TaskInvocation, taskID string,
+	now time.Time, fallback time.Duration) time.Time {
+	var hinted bool
+	offsets := map[string]time.Duration{}
+	var offset func(taskID string) time.Duration
+	offset = func(taskID string) time.Duration {
+		if d, ok := offsets[taskID]; ok {
+			return d
+		}
+		offsets[taskID] = 0 // Guards against cycles
+		task, ok := invocation.Task(taskID)
+		if !ok {
+			return 0
+		}
+		var max time.Duration
+		for depID, params := range task.GetSpec().GetRequires() {
+			if run, ok := invocation.TaskInvocation(depID); ok && finished(run) {
+				continue
+			}
+			var d time.Duration
+			if _, open := openTasks[depID]; open {
+				d = offset(depID)
+			}
+			// A task with a control dependency only waits for the dependency to start.
+			if params.GetType() == types.TaskDependencyParameters_CONTROL {
+				if d > max {
+					max = d
+				}
+				continue
+			}
+			if dep, ok := invocation.Task(depID); ok {
+				if expected, err := ptypes.Duration(dep.GetStatus().GetFnHints().GetExpectedDuration()); err == nil {
+					d += expected
+					hinted = true
+				}
+			}
+			if d > max {
+				max = d
+			}
+		}
+		offsets[taskID] = max
+		return max
+	}
+
+	d := offset(taskID)
+	if !hinted {
+		return now.Add(fallback)
+	}
+	return now.Add(d)
+}
+
+// getHorizon returns the open tasks on the scheduling horizon, which do not depend on other open tasks, except for
+// control dependencies on tasks that are on the horizon themselves. Tasks that are awaiting a retry are not on the
+// horizon. The tasks are ordered such that tasks come after the tasks that they have a control dependency on.
+func getHorizon(openTasks map[string]*types.TaskInvocation, now time.Time) []*types.TaskInvocation {
+	depGraph := graph.Parse(graph.NewTaskInstanceIterator(openTasks))
+	var horizon []*types.TaskInvocation
+	scheduled := map[int64]bool{}
+	for _, node := range graph.Roots(depGraph) {
+		taskRun := node.(*graph.TaskInvocationNode)
+		if awaitingRetry(taskRun.TaskInvocation, now) {
+			continue
+		}
+		horizon = append(horizon, taskRun.TaskInvocation)
+		scheduled[node.ID()] = true
+	}
+	sortTaskRuns(horizon)
+
+	// Tasks that only have control dependencies on scheduled tasks can be started along with them, rather than once
+	// they have completed.
+	for {
+		var next []*types.TaskInvocation
+		for _, node := range depGraph.Nodes() {
+			taskRun := node.(*graph.TaskInvocationNode)
+			if scheduled[node.ID()] || awaitingRetry(taskRun.TaskInvocation, now) {
+				continue
+			}
+			requires := taskRun.Task().GetSpec().GetRequires()
+			ready := true
+			for _, dep := range depGraph.To(node) {
+				depRun := dep.(*graph.TaskInvocationNode)
+				params, ok := requires[depRun.Task().ID()]
+				if !ok || params.GetType() != types.TaskDependencyParameters_CONTROL || !scheduled[dep.ID()] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = append(next, taskRun.TaskInvocation)
+			}
+		}
+		if len(next) == 0 {
+			return horizon
+		}
+		sortTaskRuns(next)
+		for _, taskRun := range next {
+			scheduled[(&graph.TaskInvocationNode{TaskInvocation: taskRun}).ID()] = true
+		}
+		horizon = append(horizon, next...)
+	}
+}
+
+func sortTaskRuns(taskRuns []*types.TaskInvocation) {
+	sort.Slice(taskRuns, func(i, j int) bool {
+		return taskRuns[i].Task().ID() < taskRuns[j].Task().ID()
+	})
+}
+
+// getFailedTasks returns the tasks that have failed, and that are not retried.
 func getFailedTasks(invocation *types.WorkflowInvocation) []*types.TaskInvocation {
 	var failedTasks []*types.TaskInvocation
 	for _, task := range invocation.TaskInvocations() {
+		if _, retry := task.RetryAt(); retry {
+			continue
+		}
 		if task.GetStatus().GetStatus() == types.TaskInvocationStatus_FAILED {
 			failedTasks = append(failedTasks, task)
 		}
@@ -162,6 +284,56 @@ func getFailedTasks(invocation *types.WorkflowInvocation) []*types.TaskInvocatio
 	return failedTasks
 }
 
+// awaitingRetry returns true if the task has failed, and is retried once its backoff has passed.
+func awaitingRetry(taskRun *types.TaskInvocation, now time.Time) bool {
+	retryAt, retry := taskRun.RetryAt()
+	return retry && now.Before(retryAt)
+}
+
+// finished returns true if the task is in a terminal state, and is not retried.
+func finished(taskRun *types.TaskInvocation) bool {
+	if _, retry := taskRun.RetryAt(); retry {
+		return false
+	}
+	return taskRun.GetStatus() != nil && taskRun.GetStatus().Finished()
+}
+
+// getBlockedTasks explains why the open tasks, which are not on the scheduling horizon, cannot be run yet. The tasks
+// are ordered by their id.
+func getBlockedTasks(invocation *types.WorkflowInvocation, openTasks map[string]*types.TaskInvocation) []*BlockedTask {
+	var blocked []*BlockedTask
+	for id := range openTasks {
+		task, ok := invocation.Task(id)
+		if !ok {
+			continue
+		}
+		var waitingFor []string
+		for depID := range task.GetSpec().GetRequires() {
+			if run, ok := invocation.TaskInvocation(depID); ok && finished(run) {
+				continue
+			}
+			waitingFor = append(waitingFor, depID)
+		}
+		sort.Strings(waitingFor)
+		reason := "waiting for dependencies to finish"
+		if retryAt, retry := openTasks[id].RetryAt(); retry {
+			reason = fmt.Sprintf("waiting to retry failed attempt %d at %s", openTasks[id].GetStatus().GetAttempts(),
+				retryAt.Format(time.RFC3339))
+		} else if len(waitingFor) > 0 {
+			reason = fmt.Sprintf("waiting for %s to finish", strings.Join(waitingFor, ", "))
+		}
+		blocked = append(blocked, &BlockedTask{
+			TaskID:     id,
+			WaitingFor: waitingFor,
+			Reason:     reason,
+		})
+	}
+	sort.Slice(blocked, func(i, j int) bool {
+		return blocked[i].TaskID < blocked[j].TaskID
+	})
+	return blocked
+}
+
 func getOpenTasks(invocation *types.WorkflowInvocation) map[string]*types.TaskInvocation {
 	openTasks := map[string]*types.TaskInvocation{}
 	for id, task := range invocation.Tasks() {
@@ -175,7 +347,8 @@ func getOpenTasks(invocation *types.WorkflowInvocation) map[string]*types.TaskIn
 				},
 			}
 		}
-		if taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_UNKNOWN {
+		// Failed tasks that are retried are open again.
+		if _, retry := taskRun.RetryAt(); retry || taskRun.GetStatus().GetStatus() == types.TaskInvocationStatus_UNKNOWN {
 			openTasks[id] = taskRun
 		}
 	}
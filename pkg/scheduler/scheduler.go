@@ -5,12 +5,16 @@ import (
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/hashicorp/golang-lru"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 )
 
 var log = logrus.WithField("component", "scheduler")
 
+// maxExplanations is the maximum number of invocations for which the most recent schedule is kept.
+const maxExplanations = 1000
+
 var (
 	metricEvalTime = prometheus.NewSummary(prometheus.SummaryOpts{
 		Namespace: "workflows",
@@ -35,12 +39,18 @@ func init() {
 }
 
 type InvocationScheduler struct {
-	policy Policy
+	policy       Policy
+	explanations *lru.Cache
 }
 
 func NewInvocationScheduler(policy Policy) *InvocationScheduler {
+	explanations, err := lru.New(maxExplanations)
+	if err != nil {
+		panic(err)
+	}
 	return &InvocationScheduler{
-		policy: policy,
+		policy:       policy,
+		explanations: explanations,
 	}
 }
 
@@ -61,9 +71,22 @@ func (ws *InvocationScheduler) Evaluate(invocation *types.WorkflowInvocation) (*
 	}
 
 	ctxLog.Debugf("Determined schedule: %v", schedule)
+	ws.explanations.Add(invocation.ID(), schedule)
 	return schedule, nil
 }
 
+// Explain returns the most recent schedule of the invocation, which lists the tasks that were runnable, the tasks that
+// were blocked along with the reason, and the tasks that were prewarmed along with the time at which they are
+// expected to start. It returns false if the invocation has not been evaluated by this scheduler, or if the schedule
+// has been evicted in favor of those of more recent invocations.
+func (ws *InvocationScheduler) Explain(invocationID string) (*Schedule, bool) {
+	schedule, ok := ws.explanations.Get(invocationID)
+	if !ok {
+		return nil, false
+	}
+	return schedule.(*Schedule), true
+}
+
 func newRunTaskAction(taskID string) *RunTaskAction {
 	return &RunTaskAction{
 		TaskID: taskID,
@@ -12,6 +12,7 @@ It has these top-level messages:
 	AbortAction
 	RunTaskAction
 	PrepareTaskAction
+	BlockedTask
 */
 package scheduler
 
@@ -43,6 +44,8 @@ type Schedule struct {
 	Abort        *AbortAction               `protobuf:"bytes,4,opt,name=abort" json:"abort,omitempty"`
 	RunTasks     []*RunTaskAction           `protobuf:"bytes,5,rep,name=runTasks" json:"runTasks,omitempty"`
 	PrepareTasks []*PrepareTaskAction       `protobuf:"bytes,6,rep,name=prepareTasks" json:"prepareTasks,omitempty"`
+	// blockedTasks are the tasks that could not be run yet, because they depend on tasks that have not finished.
+	BlockedTasks []*BlockedTask `protobuf:"bytes,7,rep,name=blockedTasks" json:"blockedTasks,omitempty"`
 }
 
 func (m *Schedule) Reset()                    { *m = Schedule{} }
@@ -85,6 +88,13 @@ func (m *Schedule) GetPrepareTasks() []*PrepareTaskAction {
 	return nil
 }
 
+func (m *Schedule) GetBlockedTasks() []*BlockedTask {
+	if m != nil {
+		return m.BlockedTasks
+	}
+	return nil
+}
+
 type AbortAction struct {
 	Reason string `protobuf:"bytes,1,opt,name=reason" json:"reason,omitempty"`
 }
@@ -142,11 +152,47 @@ func (m *PrepareTaskAction) GetExpectedAt() *google_protobuf.Timestamp {
 	return nil
 }
 
+type BlockedTask struct {
+	// Id of the task in the workflow
+	TaskID string `protobuf:"bytes,1,opt,name=taskID" json:"taskID,omitempty"`
+	// waitingFor contains the ids of the unfinished tasks that the task depends on.
+	WaitingFor []string `protobuf:"bytes,2,rep,name=waitingFor" json:"waitingFor,omitempty"`
+	// reason is a human-readable explanation of why the task is blocked.
+	Reason string `protobuf:"bytes,3,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *BlockedTask) Reset()                    { *m = BlockedTask{} }
+func (m *BlockedTask) String() string            { return proto.CompactTextString(m) }
+func (*BlockedTask) ProtoMessage()               {}
+func (*BlockedTask) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *BlockedTask) GetTaskID() string {
+	if m != nil {
+		return m.TaskID
+	}
+	return ""
+}
+
+func (m *BlockedTask) GetWaitingFor() []string {
+	if m != nil {
+		return m.WaitingFor
+	}
+	return nil
+}
+
+func (m *BlockedTask) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Schedule)(nil), "fission.workflows.scheduler.Schedule")
 	proto.RegisterType((*AbortAction)(nil), "fission.workflows.scheduler.AbortAction")
 	proto.RegisterType((*RunTaskAction)(nil), "fission.workflows.scheduler.RunTaskAction")
 	proto.RegisterType((*PrepareTaskAction)(nil), "fission.workflows.scheduler.PrepareTaskAction")
+	proto.RegisterType((*BlockedTask)(nil), "fission.workflows.scheduler.BlockedTask")
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -224,29 +270,32 @@ var _Scheduler_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/scheduler/scheduler.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 374 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x92, 0x4f, 0x4b, 0xeb, 0x40,
-	0x14, 0xc5, 0x5f, 0xfb, 0x5e, 0x4b, 0x73, 0xd3, 0xb7, 0x70, 0x16, 0x12, 0x22, 0x62, 0x09, 0x14,
-	0x83, 0xe2, 0x04, 0xea, 0x46, 0xba, 0x10, 0x2a, 0x22, 0x74, 0x27, 0xb1, 0x20, 0xb8, 0x72, 0x92,
-	0x4e, 0xd3, 0x90, 0x3f, 0x13, 0x66, 0x26, 0xad, 0x7e, 0x3f, 0x3f, 0x98, 0x24, 0x93, 0xa4, 0x2d,
-	0x6a, 0x70, 0x93, 0xe4, 0x0e, 0xbf, 0x73, 0xce, 0xcd, 0x9d, 0x0b, 0xa7, 0x59, 0x14, 0x38, 0xc2,
-	0x5f, 0xd3, 0x65, 0x1e, 0x53, 0xbe, 0xfb, 0xc2, 0x19, 0x67, 0x92, 0xa1, 0x93, 0x55, 0x28, 0x44,
-	0xc8, 0x52, 0xbc, 0x65, 0x3c, 0x5a, 0xc5, 0x6c, 0x2b, 0x70, 0x83, 0x98, 0xd3, 0x20, 0x94, 0xeb,
-	0xdc, 0xc3, 0x3e, 0x4b, 0x9c, 0x8a, 0xab, 0xdf, 0x57, 0x0d, 0xef, 0x14, 0x01, 0xf2, 0x3d, 0xa3,
-	0x42, 0x3d, 0x95, 0xb1, 0x79, 0x16, 0x30, 0x16, 0xc4, 0xd4, 0x29, 0x2b, 0x2f, 0x5f, 0x39, 0x32,
-	0x4c, 0xa8, 0x90, 0x24, 0xc9, 0x14, 0x60, 0x7d, 0x74, 0x61, 0xf0, 0x54, 0x45, 0x21, 0x0b, 0x86,
-	0x61, 0xba, 0x61, 0x3e, 0x91, 0x21, 0x4b, 0xe7, 0x4b, 0xa3, 0x33, 0xea, 0xd8, 0x9a, 0x7b, 0x70,
-	0x86, 0x6e, 0x40, 0xf3, 0x39, 0x25, 0x92, 0x2e, 0x67, 0xd2, 0xe8, 0x8e, 0x3a, 0xb6, 0x3e, 0x31,
-	0xb1, 0x4a, 0xc1, 0x75, 0x0a, 0x5e, 0xd4, 0x29, 0xee, 0x0e, 0x46, 0xb7, 0xd0, 0x23, 0x1e, 0xe3,
-	0xd2, 0xf8, 0x57, 0xaa, 0x6c, 0xdc, 0xf2, 0xd3, 0x78, 0x56, 0x90, 0x33, 0xbf, 0x08, 0x75, 0x95,
-	0x0c, 0x3d, 0xc0, 0x80, 0xe7, 0xe9, 0x82, 0x88, 0x48, 0x18, 0xbd, 0xd1, 0x5f, 0x5b, 0x9f, 0x5c,
-	0xb4, 0x5a, 0xb8, 0x0a, 0xae, 0x4c, 0x1a, 0x2d, 0x72, 0x61, 0x98, 0x71, 0x9a, 0x11, 0x4e, 0x95,
-	0x57, 0xbf, 0xf4, 0xc2, 0xad, 0x5e, 0x8f, 0x3b, 0x41, 0xe5, 0x77, 0xe0, 0x61, 0x8d, 0x41, 0xdf,
-	0xeb, 0x18, 0x1d, 0x43, 0x9f, 0x53, 0x22, 0x58, 0x5a, 0x8d, 0xb0, 0xaa, 0xac, 0x73, 0xf8, 0x7f,
-	0xd0, 0x55, 0x01, 0x4a, 0x22, 0xa2, 0xf9, 0x7d, 0x0d, 0xaa, 0xca, 0x0a, 0xe0, 0xe8, 0x4b, 0xe4,
-	0x4f, 0x30, 0x9a, 0x02, 0xd0, 0xb7, 0x8c, 0xfa, 0xbf, 0xbd, 0x93, 0x3d, 0x7a, 0x92, 0x80, 0x56,
-	0x5f, 0x3f, 0x47, 0xaf, 0x30, 0xa0, 0x1b, 0x12, 0xe7, 0x44, 0x52, 0x74, 0xf9, 0xcd, 0x3c, 0xd4,
-	0x66, 0x3d, 0x57, 0xf5, 0xbc, 0x59, 0x0b, 0x73, 0xdc, 0x3a, 0xbc, 0x3a, 0xc0, 0xfa, 0x73, 0xa7,
-	0xbf, 0x68, 0xcd, 0xb9, 0xd7, 0x2f, 0x7b, 0xbb, 0xfe, 0x0c, 0x00, 0x00, 0xff, 0xff, 0xc1, 0x32,
-	0xe7, 0xf1, 0x1d, 0x03, 0x00, 0x00,
+	// 424 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0x5f, 0x8b, 0xd3, 0x40,
+	0x14, 0xc5, 0xed, 0xc6, 0xad, 0xcd, 0x4d, 0x7d, 0x70, 0x1e, 0x24, 0x54, 0xd4, 0x10, 0x58, 0x0c,
+	0x8a, 0x13, 0xa8, 0x2f, 0xb2, 0x0f, 0x42, 0x17, 0x59, 0x28, 0xf8, 0x20, 0x71, 0x41, 0x10, 0x04,
+	0x27, 0xc9, 0x34, 0x3b, 0xe4, 0xcf, 0x84, 0x99, 0xc9, 0x56, 0xbf, 0x86, 0x9f, 0x58, 0x92, 0xc9,
+	0x5f, 0xb4, 0x71, 0x5f, 0xda, 0xdc, 0xe1, 0x77, 0xce, 0xe1, 0xe4, 0x4e, 0xe0, 0x79, 0x99, 0x26,
+	0xbe, 0x8c, 0x6e, 0x69, 0x5c, 0x65, 0x54, 0x0c, 0x4f, 0xb8, 0x14, 0x5c, 0x71, 0xf4, 0xec, 0xc0,
+	0xa4, 0x64, 0xbc, 0xc0, 0x47, 0x2e, 0xd2, 0x43, 0xc6, 0x8f, 0x12, 0xf7, 0xc8, 0xe6, 0x32, 0x61,
+	0xea, 0xb6, 0x0a, 0x71, 0xc4, 0x73, 0xbf, 0xe5, 0xba, 0xff, 0xb7, 0x3d, 0xef, 0xd7, 0x01, 0xea,
+	0x57, 0x49, 0xa5, 0xfe, 0xd5, 0xc6, 0x9b, 0x97, 0x09, 0xe7, 0x49, 0x46, 0xfd, 0x66, 0x0a, 0xab,
+	0x83, 0xaf, 0x58, 0x4e, 0xa5, 0x22, 0x79, 0xa9, 0x01, 0xf7, 0xb7, 0x01, 0xab, 0x2f, 0x6d, 0x14,
+	0x72, 0x61, 0xcd, 0x8a, 0x3b, 0x1e, 0x11, 0xc5, 0x78, 0xb1, 0x8f, 0xed, 0x85, 0xb3, 0xf0, 0xcc,
+	0x60, 0x72, 0x86, 0xde, 0x83, 0x19, 0x09, 0x4a, 0x14, 0x8d, 0x77, 0xca, 0x3e, 0x73, 0x16, 0x9e,
+	0xb5, 0xdd, 0x60, 0x9d, 0x82, 0xbb, 0x14, 0x7c, 0xd3, 0xa5, 0x04, 0x03, 0x8c, 0x3e, 0xc0, 0x39,
+	0x09, 0xb9, 0x50, 0xf6, 0xc3, 0x46, 0xe5, 0xe1, 0x99, 0xd2, 0x78, 0x57, 0x93, 0xbb, 0xa8, 0x0e,
+	0x0d, 0xb4, 0x0c, 0x5d, 0xc3, 0x4a, 0x54, 0xc5, 0x0d, 0x91, 0xa9, 0xb4, 0xcf, 0x1d, 0xc3, 0xb3,
+	0xb6, 0xaf, 0x67, 0x2d, 0x02, 0x0d, 0xb7, 0x26, 0xbd, 0x16, 0x05, 0xb0, 0x2e, 0x05, 0x2d, 0x89,
+	0xa0, 0xda, 0x6b, 0xd9, 0x78, 0xe1, 0x59, 0xaf, 0xcf, 0x83, 0xa0, 0xf5, 0x9b, 0x78, 0xa0, 0x4f,
+	0xb0, 0x0e, 0x33, 0x1e, 0xa5, 0x34, 0xd6, 0x9e, 0x8f, 0x1c, 0xe3, 0xbf, 0x15, 0xaf, 0x06, 0x41,
+	0x30, 0x51, 0xbb, 0x17, 0x60, 0x8d, 0xfa, 0xa3, 0xa7, 0xb0, 0x14, 0x94, 0x48, 0x5e, 0xb4, 0x0b,
+	0x69, 0x27, 0xf7, 0x15, 0x3c, 0x9e, 0x74, 0xac, 0x41, 0x45, 0x64, 0xba, 0xff, 0xd8, 0x81, 0x7a,
+	0x72, 0x13, 0x78, 0xf2, 0x57, 0x81, 0x53, 0x30, 0xba, 0x04, 0xa0, 0x3f, 0x4b, 0x1a, 0xdd, 0x77,
+	0xc3, 0x23, 0xda, 0xfd, 0x0e, 0xd6, 0xa8, 0xd5, 0xc9, 0x88, 0x17, 0x00, 0x47, 0xc2, 0x14, 0x2b,
+	0x92, 0x6b, 0x2e, 0xec, 0x33, 0xc7, 0xf0, 0xcc, 0x60, 0x74, 0x32, 0x2a, 0x6c, 0x8c, 0x0b, 0x6f,
+	0x73, 0x30, 0xbb, 0xbb, 0x2a, 0xd0, 0x0f, 0x58, 0xd1, 0x3b, 0x92, 0x55, 0x44, 0x51, 0xf4, 0xe6,
+	0x1f, 0x2f, 0x5a, 0x7f, 0x06, 0x5f, 0xdb, 0x79, 0xdf, 0xdf, 0xe1, 0xcd, 0xc5, 0xec, 0x56, 0xba,
+	0x00, 0xf7, 0xc1, 0x95, 0xf5, 0xcd, 0xec, 0xcf, 0xc3, 0x65, 0x53, 0xfd, 0xdd, 0x9f, 0x01, 0x00,
+	0x11, 0xf0, 0x5f, 0x13, 0xca, 0x03, 0x00, 0x00,
 }
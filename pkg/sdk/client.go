@@ -0,0 +1,94 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+// DefaultTimeout is the deadline of the invocations of the client, if the context has no deadline.
+const DefaultTimeout = 5 * time.Minute
+
+// Client deploys and invokes workflows with the gRPC APIs of the workflow engine.
+type Client struct {
+	api *apiserver.Client
+}
+
+func NewClient(api *apiserver.Client) *Client {
+	return &Client{api: api}
+}
+
+// Connect connects to the gRPC APIs of the workflow engine at the address, such as workflows:5555.
+func Connect(addr string) (*Client, error) {
+	api, err := apiserver.Connect(addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(api), nil
+}
+
+// Deploy builds and creates the workflow, and returns its id once it is ready to be invoked.
+func (c *Client) Deploy(ctx context.Context, workflow *Workflow) (string, error) {
+	spec, err := workflow.Build()
+	if err != nil {
+		return "", err
+	}
+	wf, err := c.api.Workflow.CreateSync(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+	if !wf.GetStatus().Ready() {
+		msg := wf.GetStatus().GetError().GetMessage()
+		if len(msg) == 0 {
+			msg = wf.GetStatus().GetStatus().String()
+		}
+		return "", fmt.Errorf("workflow %s is not ready: %s", wf.ID(), msg)
+	}
+	return wf.ID(), nil
+}
+
+// Invoke invokes the workflow with the inputs, and returns the invocation once it has completed. An error is returned
+// if the invocation did not succeed. The deadline of the invocation is the deadline of the context, or DefaultTimeout
+// if the context has none.
+func (c *Client) Invoke(ctx context.Context, wfID string, inputs map[string]interface{}) (*types.WorkflowInvocation,
+	error) {
+	tvs, err := typedvalues.WrapMapTypedValue(inputs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inputs: %v", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(DefaultTimeout)
+	}
+	spec := types.NewWorkflowInvocationSpec(wfID, deadline)
+	spec.Inputs = tvs
+	wi, err := c.api.Invocation.InvokeSync(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	if !wi.GetStatus().Successful() {
+		if wi.GetStatus().GetError() != nil {
+			return wi, errors.New(wi.GetStatus().GetError().GetMessage())
+		}
+		return wi, fmt.Errorf("invocation %s did not succeed (status: %v)", wi.ID(), wi.GetStatus().GetStatus())
+	}
+	return wi, nil
+}
+
+// Run deploys the workflow, invokes it with the inputs, and returns the output of the invocation.
+func (c *Client) Run(ctx context.Context, workflow *Workflow, inputs map[string]interface{}) (interface{}, error) {
+	wfID, err := c.Deploy(ctx, workflow)
+	if err != nil {
+		return nil, err
+	}
+	wi, err := c.Invoke(ctx, wfID, inputs)
+	if err != nil {
+		return nil, err
+	}
+	return typedvalues.Unwrap(wi.GetStatus().GetOutput())
+}
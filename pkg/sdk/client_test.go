@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+type mockWorkflowAPI struct {
+	apiserver.WorkflowAPIClient
+	created *types.WorkflowSpec
+	status  types.WorkflowStatus_Status
+}
+
+func (m *mockWorkflowAPI) CreateSync(ctx context.Context, spec *types.WorkflowSpec,
+	opts ...grpc.CallOption) (*types.Workflow, error) {
+	m.created = spec
+	return &types.Workflow{
+		Metadata: types.NewObjectMetadata("wf-123"),
+		Spec:     spec,
+		Status:   &types.WorkflowStatus{Status: m.status},
+	}, nil
+}
+
+type mockInvocationAPI struct {
+	apiserver.WorkflowInvocationAPIClient
+	invoked *types.WorkflowInvocationSpec
+	status  *types.WorkflowInvocationStatus
+}
+
+func (m *mockInvocationAPI) InvokeSync(ctx context.Context, spec *types.WorkflowInvocationSpec,
+	opts ...grpc.CallOption) (*types.WorkflowInvocation, error) {
+	m.invoked = spec
+	return &types.WorkflowInvocation{
+		Metadata: types.NewObjectMetadata("wi-123"),
+		Spec:     spec,
+		Status:   m.status,
+	}, nil
+}
+
+func TestClient_Run(t *testing.T) {
+	workflows := &mockWorkflowAPI{status: types.WorkflowStatus_READY}
+	invocations := &mockInvocationAPI{status: &types.WorkflowInvocationStatus{
+		Status: types.WorkflowInvocationStatus_SUCCEEDED,
+		Output: typedvalues.MustWrap("Hello, world"),
+	}}
+	client := NewClient(&apiserver.Client{Workflow: workflows, Invocation: invocations})
+
+	greet := NewTask("greet", "noop").Input(Expr("'Hello, ' + param('name')"))
+	output, err := client.Run(context.Background(), NewWorkflow("greeter").AddTask(greet).OutputTask(greet),
+		map[string]interface{}{"name": "world"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", output)
+	assert.Equal(t, "greeter", workflows.created.Name)
+	assert.Equal(t, "wf-123", invocations.invoked.WorkflowId)
+	assert.Equal(t, "world", typedvalues.MustUnwrap(invocations.invoked.Inputs["name"]))
+	assert.NotNil(t, invocations.invoked.Deadline)
+}
+
+func TestClient_Failed(t *testing.T) {
+	greet := NewTask("greet", "noop")
+	wf := NewWorkflow("greeter").AddTask(greet).OutputTask(greet)
+
+	workflows := &mockWorkflowAPI{status: types.WorkflowStatus_FAILED}
+	invocations := &mockInvocationAPI{status: &types.WorkflowInvocationStatus{
+		Status: types.WorkflowInvocationStatus_FAILED,
+		Error:  &types.Error{Message: "function failed"},
+	}}
+	client := NewClient(&apiserver.Client{Workflow: workflows, Invocation: invocations})
+	_, err := client.Deploy(context.Background(), wf)
+	assert.Error(t, err)
+
+	wi, err := client.Invoke(context.Background(), "wf-123", nil)
+	assert.EqualError(t, err, "function failed")
+	assert.NotNil(t, wi)
+
+	// Invalid workflows are not deployed.
+	workflows.created = nil
+	_, err = client.Deploy(context.Background(), NewWorkflow("invalid").OutputTask(greet))
+	assert.Error(t, err)
+	assert.Nil(t, workflows.created)
+}
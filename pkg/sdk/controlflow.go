@@ -0,0 +1,81 @@
+package sdk
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+)
+
+// The control flow constructs create tasks that run the control flow functions of the internal function environment.
+// Their actions are tasks or workflows, which are run as dynamic tasks, or values, which are returned as they are. The
+// ids of the tasks that are used as actions do not matter. As the actions are built when the control flow task is
+// created, later changes to them do not affect the control flow task.
+
+// If creates a task that runs the then action if the condition holds, or the otherwise action if not. The otherwise
+// action is optional; if nil, the task has no output if the condition does not hold.
+func If(id string, condition interface{}, then interface{}, otherwise interface{}) *Task {
+	task := NewTask(id, builtin.If).
+		InputKey(builtin.IfInputCondition, condition).
+		InputKey(builtin.IfInputThen, then)
+	if otherwise != nil {
+		task.InputKey(builtin.IfInputElse, otherwise)
+	}
+	return task
+}
+
+// Foreach creates a task that runs the action for each of the items, and outputs a list of their outputs. The action
+// refers to the current item with the expression "{ task().Inputs._item }".
+func Foreach(id string, items interface{}, do interface{}) *Task {
+	return NewTask(id, builtin.Foreach).
+		InputKey(builtin.ForeachInputForeach, items).
+		InputKey(builtin.ForeachInputDo, do)
+}
+
+// Switch creates a task that runs the action of the case that matches the value, or the default action if none of
+// the cases match. The default action is optional.
+func Switch(id string, value interface{}, cases map[string]interface{}, defaultCase interface{}) *Task {
+	var keys []string
+	for key := range cases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var caseList []interface{}
+	for _, key := range keys {
+		caseList = append(caseList, map[string]interface{}{
+			builtin.SwitchCaseKey:   key,
+			builtin.SwitchCaseValue: cases[key],
+		})
+	}
+	task := NewTask(id, builtin.Switch).
+		InputKey(builtin.SwitchInputCondition, value).
+		InputKey(builtin.SwitchInputCases, caseList)
+	if defaultCase != nil {
+		task.InputKey(builtin.SwitchInputDefaultCase, defaultCase)
+	}
+	return task
+}
+
+// While creates a task that runs the action as long as the condition holds, up to the limit of iterations.
+func While(id string, condition interface{}, limit int, do interface{}) *Task {
+	return NewTask(id, builtin.While).
+		InputKey(builtin.WhileInputExpr, condition).
+		InputKey(builtin.WhileInputLimit, limit).
+		InputKey(builtin.WhileInputAction, do)
+}
+
+// Repeat creates a task that runs the action the number of times, each time after the previous one has completed.
+func Repeat(id string, times int, do interface{}) *Task {
+	return NewTask(id, builtin.Repeat).
+		InputKey(builtin.RepeatInputTimes, times).
+		InputKey(builtin.RepeatInputDo, do)
+}
+
+// Timeout creates a task that runs the action, and interrupts it if it has not completed after the duration. The
+// output of the task indicates whether the action timed out ("timedOut"), and contains the output of the action
+// ("output") if it completed in time.
+func Timeout(id string, after time.Duration, do interface{}) *Task {
+	return NewTask(id, builtin.Timeout).
+		InputKey(builtin.TimeoutInputAfter, after.String()).
+		InputKey(builtin.TimeoutInputDo, do)
+}
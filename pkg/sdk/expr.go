@@ -0,0 +1,27 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Expr returns the JavaScript code as an expression, which is evaluated when the task is run, for example
+// Expr("param('a') + param('b')").
+func Expr(js string) string {
+	return "{ " + js + " }"
+}
+
+// Param returns an expression that refers to the input of the invocation with the key.
+func Param(key string) string {
+	return Expr(fmt.Sprintf("param(%s)", strconv.Quote(key)))
+}
+
+// TaskOutput returns an expression that refers to the output of the task.
+func TaskOutput(task *Task) string {
+	return Expr(fmt.Sprintf("output(%s)", strconv.Quote(task.ID())))
+}
+
+// Const returns an expression that refers to the constant of the workflow with the key.
+func Const(key string) string {
+	return Expr(fmt.Sprintf("$.Consts[%s]", strconv.Quote(key)))
+}
@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// Task builds the spec of a task.
+type Task struct {
+	id   string
+	spec *types.TaskSpec
+	errs []error
+}
+
+// NewTask creates a builder of a task with the id, which runs the function.
+func NewTask(id string, fn string) *Task {
+	return &Task{
+		id:   id,
+		spec: types.NewTaskSpec(fn),
+	}
+}
+
+func (t *Task) ID() string {
+	return t.id
+}
+
+// Input sets the default input of the task.
+func (t *Task) Input(value interface{}) *Task {
+	return t.InputKey(types.InputMain, value)
+}
+
+// InputKey sets the input of the task with the key.
+func (t *Task) InputKey(key string, value interface{}) *Task {
+	if t.spec.Inputs == nil {
+		t.spec.Inputs = map[string]*typedvalues.TypedValue{}
+	}
+	tv, err := wrap(value)
+	if err != nil {
+		t.errs = append(t.errs, fmt.Errorf("invalid input %s of task %s: %v", key, t.id, err))
+		return t
+	}
+	t.spec.Inputs[key] = tv
+	return t
+}
+
+// After adds data dependencies on the tasks, of which the task uses the outputs.
+func (t *Task) After(tasks ...*Task) *Task {
+	for _, task := range tasks {
+		t.require(task.ID(), nil)
+	}
+	return t
+}
+
+// AfterControl adds control dependencies on the tasks, which only order the start of the task.
+func (t *Task) AfterControl(tasks ...*Task) *Task {
+	for _, task := range tasks {
+		t.require(task.ID(), &types.TaskDependencyParameters{Type: types.TaskDependencyParameters_CONTROL})
+	}
+	return t
+}
+
+// Await sets the number of dependencies to wait for before the task is started. By default, the task waits for all
+// of its dependencies.
+func (t *Task) Await(n int) *Task {
+	t.spec.Await = int32(n)
+	return t
+}
+
+// When sets the condition under which the task is run, which is typically an expression. If the condition does not
+// hold, the task is skipped.
+func (t *Task) When(condition interface{}) *Task {
+	tv, err := wrap(condition)
+	if err != nil {
+		t.errs = append(t.errs, fmt.Errorf("invalid condition of task %s: %v", t.id, err))
+		return t
+	}
+	t.spec.When = tv
+	return t
+}
+
+// Output overrides the output of the task, which is typically an expression that transforms the output of the
+// function.
+func (t *Task) Output(value interface{}) *Task {
+	tv, err := wrap(value)
+	if err != nil {
+		t.errs = append(t.errs, fmt.Errorf("invalid output of task %s: %v", t.id, err))
+		return t
+	}
+	t.spec.Output = tv
+	return t
+}
+
+// Timeout sets the maximum duration of the execution of the task.
+func (t *Task) Timeout(timeout time.Duration) *Task {
+	t.spec.Timeout = ptypes.DurationProto(timeout)
+	return t
+}
+
+// Retry runs the task again up to maxRetries times after a failed attempt, with a backoff that doubles for every
+// retry. If status codes are provided, only the attempts that failed with one of them are retried.
+func (t *Task) Retry(maxRetries int, backoff time.Duration, statusCodes ...int) *Task {
+	t.spec.MaxRetries = int32(maxRetries)
+	if backoff > 0 {
+		t.spec.Backoff = ptypes.DurationProto(backoff)
+	}
+	t.spec.RetryableStatusCodes = nil
+	for _, code := range statusCodes {
+		t.spec.RetryableStatusCodes = append(t.spec.RetryableStatusCodes, int32(code))
+	}
+	return t
+}
+
+// Idempotent marks the function of the task as safe to invoke again, for example after a restart of the workflow
+// engine.
+func (t *Task) Idempotent() *Task {
+	t.spec.Idempotent = true
+	return t
+}
+
+// Build returns the spec of the task, or an error if one of the values of the task is invalid.
+func (t *Task) Build() (*types.TaskSpec, error) {
+	if len(t.errs) > 0 {
+		return nil, t.errs[0]
+	}
+	return t.spec, nil
+}
+
+func (t *Task) require(id string, params *types.TaskDependencyParameters) {
+	if t.spec.Requires == nil {
+		t.spec.Requires = map[string]*types.TaskDependencyParameters{}
+	}
+	t.spec.Requires[id] = params
+}
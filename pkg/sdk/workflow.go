@@ -0,0 +1,212 @@
+// Package sdk provides a fluent API to construct workflows in Go, as an alternative to defining them in YAML, and a
+// client to deploy and invoke them.
+//
+// A workflow consists of tasks, which run a function with inputs once their dependencies have completed:
+//
+//	greet := sdk.NewTask("greet", "noop").
+//		Input(sdk.Expr("'Hello, ' + param('name')"))
+//	shout := sdk.NewTask("shout", "shout").
+//		Input(sdk.TaskOutput(greet)).
+//		After(greet)
+//	spec, err := sdk.NewWorkflow("greeter").
+//		AddTask(greet, shout).
+//		OutputTask(shout).
+//		Build()
+//
+// The inputs of tasks can be any value that can be wrapped into a TypedValue, including expressions (see Expr). The
+// control flow constructs, such as If and Foreach, take tasks or workflows as their actions, which are run as dynamic
+// tasks.
+//
+// Build validates the workflow like the workflow engine does, so that workflows can be unit-tested without deploying
+// them.
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/validate"
+)
+
+// Workflow builds the spec of a workflow.
+type Workflow struct {
+	spec  *types.WorkflowSpec
+	tasks []*Task
+	errs  []error
+}
+
+// NewWorkflow creates a builder of a workflow with the name.
+func NewWorkflow(name string) *Workflow {
+	spec := types.NewWorkflowSpec()
+	spec.Name = name
+	return &Workflow{spec: spec}
+}
+
+func (w *Workflow) Description(description string) *Workflow {
+	w.spec.Description = description
+	return w
+}
+
+func (w *Workflow) Namespace(namespace string) *Workflow {
+	w.spec.Namespace = namespace
+	return w
+}
+
+func (w *Workflow) Label(key string, value string) *Workflow {
+	if w.spec.Labels == nil {
+		w.spec.Labels = map[string]string{}
+	}
+	w.spec.Labels[key] = value
+	return w
+}
+
+func (w *Workflow) Annotation(key string, value string) *Workflow {
+	if w.spec.Annotations == nil {
+		w.spec.Annotations = map[string]string{}
+	}
+	w.spec.Annotations[key] = value
+	return w
+}
+
+// InputSchema sets the JSON Schema that the inputs of the invocations should conform to. The schema is either a JSON
+// string, or a value that is marshaled to JSON, such as a map.
+func (w *Workflow) InputSchema(schema interface{}) *Workflow {
+	if s, ok := schema.(string); ok {
+		w.spec.InputSchema = s
+		return w
+	}
+	bs, err := json.Marshal(schema)
+	if err != nil {
+		w.errs = append(w.errs, fmt.Errorf("invalid input schema: %v", err))
+		return w
+	}
+	w.spec.InputSchema = string(bs)
+	return w
+}
+
+// DefaultInput sets the value of the input that is used when the invoker omits it.
+func (w *Workflow) DefaultInput(key string, value interface{}) *Workflow {
+	if w.spec.DefaultInputs == nil {
+		w.spec.DefaultInputs = map[string]*typedvalues.TypedValue{}
+	}
+	w.set(w.spec.DefaultInputs, key, value, "default input")
+	return w
+}
+
+// Const sets a constant of the workflow, which the expressions can refer to (see Const).
+func (w *Workflow) Const(key string, value interface{}) *Workflow {
+	if w.spec.Consts == nil {
+		w.spec.Consts = map[string]*typedvalues.TypedValue{}
+	}
+	w.set(w.spec.Consts, key, value, "constant")
+	return w
+}
+
+// AddTask adds the tasks to the workflow.
+func (w *Workflow) AddTask(tasks ...*Task) *Workflow {
+	w.tasks = append(w.tasks, tasks...)
+	return w
+}
+
+// OutputTask sets the task of which the output is the output of the workflow.
+func (w *Workflow) OutputTask(task *Task) *Workflow {
+	w.spec.OutputTask = task.ID()
+	return w
+}
+
+// Output sets a named output of the workflow, which is typically an expression that refers to the outputs of tasks.
+func (w *Workflow) Output(name string, value interface{}) *Workflow {
+	if w.spec.Outputs == nil {
+		w.spec.Outputs = map[string]*typedvalues.TypedValue{}
+	}
+	w.set(w.spec.Outputs, name, value, "output")
+	return w
+}
+
+func (w *Workflow) ConcurrencyPolicy(policy types.WorkflowSpec_ConcurrencyPolicy) *Workflow {
+	w.spec.ConcurrencyPolicy = policy
+	return w
+}
+
+// Build returns the spec of the workflow, or an error if the workflow is invalid.
+func (w *Workflow) Build() (*types.WorkflowSpec, error) {
+	if len(w.errs) > 0 {
+		return nil, w.errs[0]
+	}
+	spec := *w.spec
+	spec.Tasks = map[string]*types.TaskSpec{}
+	for _, task := range w.tasks {
+		if _, ok := spec.Tasks[task.ID()]; ok {
+			return nil, fmt.Errorf("duplicate task: %s", task.ID())
+		}
+		taskSpec, err := task.Build()
+		if err != nil {
+			return nil, err
+		}
+		spec.Tasks[task.ID()] = taskSpec
+	}
+	if err := validate.WorkflowSpec(&spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// MustBuild is like Build, but panics if the workflow is invalid.
+func (w *Workflow) MustBuild() *types.WorkflowSpec {
+	spec, err := w.Build()
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+func (w *Workflow) set(values map[string]*typedvalues.TypedValue, key string, value interface{}, kind string) {
+	tv, err := wrap(value)
+	if err != nil {
+		w.errs = append(w.errs, fmt.Errorf("invalid %s %s: %v", kind, key, err))
+		return
+	}
+	values[key] = tv
+}
+
+// wrap wraps the value into a TypedValue, with the tasks and workflows in the value being replaced by their specs.
+func wrap(value interface{}) (*typedvalues.TypedValue, error) {
+	v, err := specs(value)
+	if err != nil {
+		return nil, err
+	}
+	return typedvalues.Wrap(v)
+}
+
+func specs(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case *Task:
+		return v.Build()
+	case *Workflow:
+		return v.Build()
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			s, err := specs(item)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = s
+		}
+		return values, nil
+	case map[string]interface{}:
+		values := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			s, err := specs(item)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = s
+		}
+		return values, nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,180 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/controller/expr"
+	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflow_Build(t *testing.T) {
+	fetch := NewTask("fetch", "fetch-order").
+		Input(Param("order")).
+		InputKey("bucket", Const("bucket")).
+		Timeout(10*time.Second).
+		Retry(3, time.Second, 503).
+		Idempotent()
+	audit := NewTask("audit", "audit-log").
+		AfterControl(fetch)
+	ship := NewTask("ship", "ship-order").
+		Input(TaskOutput(fetch)).
+		After(fetch).
+		When(Expr("output('fetch').paid")).
+		Output(Expr("output().trackingId"))
+
+	spec, err := NewWorkflow("orders").
+		Description("Ships the paid orders.").
+		Namespace("shop").
+		Label("team", "logistics").
+		Annotation("owner", "jane").
+		InputSchema(map[string]interface{}{
+			"type":     "object",
+			"required": []string{"order"},
+		}).
+		DefaultInput("priority", "normal").
+		Const("bucket", "orders").
+		AddTask(fetch, audit, ship).
+		OutputTask(ship).
+		ConcurrencyPolicy(types.WorkflowSpec_FORBID).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, types.WorkflowAPIVersion, spec.ApiVersion)
+	assert.Equal(t, "orders", spec.Name)
+	assert.Equal(t, "Ships the paid orders.", spec.Description)
+	assert.Equal(t, "shop", spec.Namespace)
+	assert.Equal(t, map[string]string{"team": "logistics"}, spec.Labels)
+	assert.Equal(t, map[string]string{"owner": "jane"}, spec.Annotations)
+	assert.JSONEq(t, `{"type": "object", "required": ["order"]}`, spec.InputSchema)
+	assert.Equal(t, "normal", typedvalues.MustUnwrap(spec.DefaultInputs["priority"]))
+	assert.Equal(t, "orders", typedvalues.MustUnwrap(spec.Consts["bucket"]))
+	assert.Equal(t, "ship", spec.OutputTask)
+	assert.Equal(t, types.WorkflowSpec_FORBID, spec.ConcurrencyPolicy)
+	assert.Len(t, spec.Tasks, 3)
+
+	fetchSpec := spec.Tasks["fetch"]
+	assert.Equal(t, "fetch-order", fetchSpec.FunctionRef)
+	assert.Equal(t, `{ param("order") }`, typedvalues.MustUnwrap(fetchSpec.Inputs[types.InputMain]))
+	assert.Equal(t, `{ $.Consts["bucket"] }`, typedvalues.MustUnwrap(fetchSpec.Inputs["bucket"]))
+	assert.Equal(t, ptypes.DurationProto(10*time.Second), fetchSpec.Timeout)
+	assert.Equal(t, int32(3), fetchSpec.MaxRetries)
+	assert.Equal(t, ptypes.DurationProto(time.Second), fetchSpec.Backoff)
+	assert.Equal(t, []int32{503}, fetchSpec.RetryableStatusCodes)
+	assert.True(t, fetchSpec.Idempotent)
+
+	assert.Equal(t, types.Requires{}.AddControl("fetch"), types.Requires(spec.Tasks["audit"].Requires))
+
+	shipSpec := spec.Tasks["ship"]
+	assert.Equal(t, types.Require("fetch"), types.Requires(shipSpec.Requires))
+	assert.Equal(t, `{ output("fetch") }`, typedvalues.MustUnwrap(shipSpec.Inputs[types.InputMain]))
+	assert.Equal(t, "{ output('fetch').paid }", typedvalues.MustUnwrap(shipSpec.When))
+	assert.Equal(t, "{ output().trackingId }", typedvalues.MustUnwrap(shipSpec.Output))
+}
+
+func TestWorkflow_BuildNamedOutputs(t *testing.T) {
+	a := NewTask("a", "noop").Input(1)
+	b := NewTask("b", "noop").Input(2)
+	spec, err := NewWorkflow("outputs").
+		AddTask(a, b).
+		Output("sum", Expr("output('a') + output('b')")).
+		Build()
+	require.NoError(t, err)
+	assert.Empty(t, spec.OutputTask)
+	assert.Equal(t, "{ output('a') + output('b') }", typedvalues.MustUnwrap(spec.Outputs["sum"]))
+}
+
+func TestWorkflow_BuildInvalid(t *testing.T) {
+	a := NewTask("a", "noop")
+	for name, wf := range map[string]*Workflow{
+		"duplicate task":     NewWorkflow("wf").AddTask(a, NewTask("a", "noop")).OutputTask(a),
+		"unknown dependency": NewWorkflow("wf").AddTask(NewTask("b", "noop").After(a)).OutputTask(a),
+		"unknown output":     NewWorkflow("wf").AddTask(a).OutputTask(NewTask("c", "noop")),
+		"invalid input":      NewWorkflow("wf").AddTask(NewTask("a", "noop").Input(make(chan int))).OutputTask(a),
+		"invalid schema":     NewWorkflow("wf").AddTask(a).OutputTask(a).InputSchema(make(chan int)),
+	} {
+		_, err := wf.Build()
+		assert.Error(t, err, name)
+		assert.Panics(t, func() { wf.MustBuild() }, name)
+	}
+}
+
+func TestControlFlow(t *testing.T) {
+	then := NewTask("then", "noop").Input("yes")
+	check := If("check", Param("ok"), then, "no")
+	items := Foreach("items", []interface{}{1, 2, 3}, NewTask("item", "noop").Input(Expr("task().Inputs._item")))
+	sw := Switch("route", Param("kind"), map[string]interface{}{
+		"b": NewTask("b", "noop"),
+		"a": "constant",
+	}, nil)
+	loop := While("loop", Expr("task().Inputs._prev < 3"), 10, NewTask("inc", "noop"))
+	repeat := Repeat("repeat", 5, NewTask("inc", "noop"))
+	timeout := Timeout("slow", 30*time.Second, NewWorkflow("slow").
+		AddTask(NewTask("sleep", "sleep").Input("1m")).
+		OutputTask(NewTask("sleep", "sleep")))
+
+	spec, err := NewWorkflow("controlflow").
+		AddTask(check, items, sw, loop, repeat, timeout).
+		OutputTask(check).
+		Build()
+	require.NoError(t, err)
+
+	checkSpec := spec.Tasks["check"]
+	assert.Equal(t, builtin.If, checkSpec.FunctionRef)
+	thenSpec, err := controlflow.UnwrapTask(checkSpec.Inputs[builtin.IfInputThen])
+	require.NoError(t, err)
+	assert.Equal(t, "noop", thenSpec.FunctionRef)
+	assert.Equal(t, "no", typedvalues.MustUnwrap(checkSpec.Inputs[builtin.IfInputElse]))
+
+	itemsSpec := spec.Tasks["items"]
+	assert.Equal(t, builtin.Foreach, itemsSpec.FunctionRef)
+	assert.Equal(t, []interface{}{int32(1), int32(2), int32(3)},
+		typedvalues.MustUnwrap(itemsSpec.Inputs[builtin.ForeachInputForeach]))
+	_, err = controlflow.UnwrapTask(itemsSpec.Inputs[builtin.ForeachInputDo])
+	assert.NoError(t, err)
+
+	// The cases of a switch are ordered by their key, to keep the spec deterministic.
+	cases, ok := typedvalues.MustUnwrap(spec.Tasks["route"].Inputs[builtin.SwitchInputCases]).([]interface{})
+	require.True(t, ok)
+	require.Len(t, cases, 2)
+	assert.Equal(t, map[string]interface{}{builtin.SwitchCaseKey: "a", builtin.SwitchCaseValue: "constant"}, cases[0])
+	assert.Equal(t, "b", cases[1].(map[string]interface{})[builtin.SwitchCaseKey])
+	assert.IsType(t, &types.TaskSpec{}, cases[1].(map[string]interface{})[builtin.SwitchCaseValue])
+	assert.NotContains(t, spec.Tasks["route"].Inputs, builtin.SwitchInputDefaultCase)
+
+	assert.Equal(t, builtin.While, spec.Tasks["loop"].FunctionRef)
+	assert.Equal(t, builtin.Repeat, spec.Tasks["repeat"].FunctionRef)
+
+	timeoutSpec := spec.Tasks["slow"]
+	assert.Equal(t, builtin.Timeout, timeoutSpec.FunctionRef)
+	assert.Equal(t, "30s", typedvalues.MustUnwrap(timeoutSpec.Inputs[builtin.TimeoutInputAfter]))
+	wfSpec, err := controlflow.UnwrapWorkflow(timeoutSpec.Inputs[builtin.TimeoutInputDo])
+	require.NoError(t, err)
+	assert.Equal(t, "sleep", wfSpec.OutputTask)
+}
+
+func TestExpressions(t *testing.T) {
+	task := NewTask("it's", "noop")
+	scope := map[string]interface{}{
+		"Invocation": map[string]interface{}{
+			"Inputs": map[string]interface{}{"a": 1},
+		},
+		"Consts": map[string]interface{}{"my-const": "value"},
+	}
+	for expression, expected := range map[string]interface{}{
+		Expr("1 + 1"):     int64(2),
+		Const("my-const"): "value",
+	} {
+		tv, err := expr.Resolve(scope, "current", typedvalues.MustWrap(expression))
+		require.NoError(t, err, expression)
+		assert.EqualValues(t, expected, typedvalues.MustUnwrap(tv), expression)
+	}
+	assert.Equal(t, `{ param("a") }`, Param("a"))
+	assert.Equal(t, `{ output("it's") }`, TaskOutput(task))
+}
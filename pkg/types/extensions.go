@@ -1,8 +1,15 @@
 package types
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/util/backoff"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 )
 
 // Types other than specified in protobuf
@@ -17,6 +24,9 @@ const (
 	typedValueShortMaxLen = 32
 	WorkflowAPIVersion    = "v1"
 
+	// WorkflowVersionLatest refers to the latest version of a workflow.
+	WorkflowVersionLatest int64 = 0
+
 	TypeWorkflow   = "workflow"
 	TypeInvocation = "invocation"
 	TypeTaskRun    = "taskrun"
@@ -144,6 +154,23 @@ func (m WorkflowInvocationStatus) Successful() bool {
 	return m.GetStatus() == WorkflowInvocationStatus_SUCCEEDED
 }
 
+//
+// Intent
+//
+
+// ID returns the id by which the intent is indexed in the status of the invocation. An invocation has at most one
+// pending intent to run each task, and at most one pending intent to complete or fail it.
+func (m *Intent) ID() string {
+	switch m.GetAction() {
+	case Intent_FAIL:
+		return "fail"
+	case Intent_COMPLETE:
+		return "complete"
+	default:
+		return "run." + m.GetTaskId()
+	}
+}
+
 //
 // TaskInvocation
 //
@@ -164,6 +191,36 @@ func (m *TaskInvocation) Task() *Task {
 	return m.GetSpec().GetTask()
 }
 
+// RetryAt returns the time at which the failed task invocation should be retried, which is the backoff of its task
+// after the failure. It returns false if the task invocation has not failed, or if it should not be retried.
+func (m *TaskInvocation) RetryAt() (time.Time, bool) {
+	spec := m.GetSpec().GetTask().GetSpec()
+	if !spec.Retryable(m.GetStatus()) {
+		return time.Time{}, false
+	}
+	failedAt, err := ptypes.Timestamp(m.GetStatus().GetUpdatedAt())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return failedAt.Add(spec.RetryDelay(m.GetStatus().GetAttempts())), true
+}
+
+//
+// TaskInvocationSpec
+//
+
+// IdempotencyKey returns the key that identifies the attempt of the task invocation. The key is deterministic, so that
+// functions can deduplicate the re-executions of an attempt, such as retried requests, whereas every attempt of the
+// task has a different key.
+func (m *TaskInvocationSpec) IdempotencyKey() string {
+	taskID := m.GetTask().ID()
+	if len(taskID) == 0 {
+		taskID = m.GetTaskId()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%d", m.GetInvocationId(), taskID, m.GetAttempt())))
+	return hex.EncodeToString(sum[:16])
+}
+
 //
 // TaskInvocationStatus
 //
@@ -215,6 +272,35 @@ func (m *TaskSpec) Parent() (string, bool) {
 	return parent, present
 }
 
+// Retryable returns true if the failed attempt of the task should be retried, because the task has retries left and
+// the attempt failed with one of the retryable status codes of the task. Failures of tasks that did not start, such as
+// invalid inputs, are not retried.
+func (m *TaskSpec) Retryable(status *TaskInvocationStatus) bool {
+	if status.GetStatus() != TaskInvocationStatus_FAILED || status.GetAttempts() == 0 ||
+		status.GetAttempts() > m.GetMaxRetries() {
+		return false
+	}
+	if len(m.GetRetryableStatusCodes()) == 0 {
+		return true
+	}
+	for _, code := range m.GetRetryableStatusCodes() {
+		if code == status.GetError().GetStatusCode() {
+			return true
+		}
+	}
+	return false
+}
+
+// RetryDelay returns the delay between the given number of failed attempts of the task and the next attempt. The
+// backoff of the task is doubled for every retry after the first one.
+func (m *TaskSpec) RetryDelay(attempts int32) time.Duration {
+	unit, err := ptypes.Duration(m.GetBackoff())
+	if err != nil || attempts < 1 {
+		return 0
+	}
+	return backoff.ExponentialBackoff(int(attempts-1), unit)
+}
+
 func (m *TaskSpec) Require(taskID string, opts ...*TaskDependencyParameters) *TaskSpec {
 	if m.Requires == nil {
 		m.Requires = map[string]*TaskDependencyParameters{}
@@ -251,6 +337,22 @@ func (m *Workflow) Type() string {
 	return TypeWorkflow
 }
 
+// AtVersion returns a snapshot of the workflow at the version, without the history of the workflow. If the version is
+// WorkflowVersionLatest, the latest version is returned. It returns false if the workflow has no such version.
+func (m *Workflow) AtVersion(version int64) (*Workflow, bool) {
+	if version == WorkflowVersionLatest || version == m.GetStatus().GetVersion() {
+		snapshot := m.Copy()
+		snapshot.History = nil
+		return snapshot, true
+	}
+	for _, previous := range m.GetHistory() {
+		if previous.GetStatus().GetVersion() == version {
+			return previous.Copy(), true
+		}
+	}
+	return nil, false
+}
+
 // Note: this only retrieves the statically, top-level defined tasks
 // TODO just store entire task in status
 func (m *Workflow) Task(id string) (*Task, bool) {
@@ -354,6 +456,28 @@ func (m *WorkflowSpec) AddTask(id string, task *TaskSpec) *WorkflowSpec {
 	return m
 }
 
+// WithDefaultInputs returns the inputs, complemented with the default inputs of the workflow for the keys that are
+// missing. If no defaults are missing, the inputs are returned as is; otherwise a new map is returned.
+func (m *WorkflowSpec) WithDefaultInputs(inputs map[string]*typedvalues.TypedValue) map[string]*typedvalues.TypedValue {
+	var result map[string]*typedvalues.TypedValue
+	for key, value := range m.GetDefaultInputs() {
+		if _, ok := inputs[key]; ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]*typedvalues.TypedValue, len(inputs)+len(m.GetDefaultInputs()))
+			for k, v := range inputs {
+				result[k] = v
+			}
+		}
+		result[key] = value
+	}
+	if result == nil {
+		return inputs
+	}
+	return result
+}
+
 func (m *WorkflowSpec) TaskSpec(taskID string) *TaskSpec {
 	tasks := m.GetTasks()
 	if tasks == nil {
@@ -71,6 +71,10 @@ func ParseFnRef(s string) (FnRef, error) {
 	if len(id) == 0 {
 		id = strings.Trim(u.Host, "/")
 		ns = ""
+		// The version of a function reference without a namespace (e.g. fission://fn@v2) is parsed as the userinfo.
+		if u.User != nil {
+			id = u.User.String() + "@" + id
+		}
 	}
 	if len(id) == 0 {
 		return FnRef{}, ErrInvalidFnRef
@@ -15,6 +15,8 @@ var parseCases = map[string]struct {
 	"a://b":                             {NewFnRef("a", "", "b"), nil, "a://b"},
 	"http://foobar":                     {NewFnRef("http", "", "foobar"), nil, "http://foobar"},
 	"fission://fission-function/foobar": {NewFnRef("fission", "fission-function", "foobar"), nil, "fission://fission-function/foobar"},
+	"fission://foobar@v2":               {NewFnRef("fission", "", "foobar@v2"), nil, "fission://foobar@v2"},
+	"fission://ns/foobar@v1=90,v2=10":   {NewFnRef("fission", "ns", "foobar@v1=90,v2=10"), nil, "fission://ns/foobar@v1=90,v2=10"},
 
 	"":             {FnRef{}, ErrInvalidFnRef, ""},
 	"://":          {FnRef{}, ErrInvalidFnRef, ""},
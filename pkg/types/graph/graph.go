@@ -47,6 +47,11 @@ func (n *TaskSpecNode) ID() int64 {
 	return createID(n.id)
 }
 
+// TaskID returns the id of the task in the workflow.
+func (n *TaskSpecNode) TaskID() string {
+	return n.id
+}
+
 type Iterator interface {
 	Get(ptr int) LinkedNode
 }
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+var (
+	// taskFieldRe matches the references to tasks by field, such as $.Tasks.foo.Output.bar (JavaScript) or
+	// .Tasks.foo.Output (jq), with the task id and the optional field of the output as groups.
+	taskFieldRe = regexp.MustCompile(`\.Tasks\.([A-Za-z_$][\w$]*)(?:\.Output\.([A-Za-z_$][\w$]*))?`)
+
+	// taskFnRe matches the references to tasks by the built-in functions, such as output('foo'), with the task id as
+	// group.
+	taskFnRe = regexp.MustCompile(`\b(?:task|input|output|outputHeaders)\(\s*["']([^"']+)["']`)
+)
+
+// Reference is a reference in an expression of a task to another task of the workflow.
+type Reference struct {
+	// TaskID is the id of the task that contains the expression.
+	TaskID string
+
+	// Field is the dot-separated path to the field of the task that contains the expression, such as "inputs.default".
+	Field string
+
+	// Target is the id of the referenced task.
+	Target string
+
+	// Artifact is the referenced field of the output of the target, or empty if the reference is not to a specific
+	// field of the output.
+	Artifact string
+}
+
+// References returns the references in the expressions of the task to the other tasks in the workflow, ordered by
+// field and target. The references are found syntactically, so references that are constructed dynamically, such as
+// $.Tasks[id], are not found.
+func References(taskID string, task *types.TaskSpec, tasks map[string]*types.TaskSpec) []Reference {
+	fields := map[string]*typedvalues.TypedValue{
+		"output":        task.GetOutput(),
+		"outputHeaders": task.GetOutputHeaders(),
+		"when":          task.GetWhen(),
+	}
+	for key, input := range task.GetInputs() {
+		fields["inputs."+key] = input
+	}
+
+	var refs []Reference
+	for field, tv := range fields {
+		if tv == nil {
+			continue
+		}
+		i, err := typedvalues.Unwrap(tv)
+		if err != nil {
+			continue
+		}
+		seen := map[Reference]bool{}
+		for _, expr := range expressions(i) {
+			for _, m := range taskFieldRe.FindAllStringSubmatch(expr, -1) {
+				seen[Reference{TaskID: taskID, Field: field, Target: m[1], Artifact: m[2]}] = true
+			}
+			for _, m := range taskFnRe.FindAllStringSubmatch(expr, -1) {
+				seen[Reference{TaskID: taskID, Field: field, Target: m[1]}] = true
+			}
+		}
+		for ref := range seen {
+			if _, ok := tasks[ref.Target]; ok && ref.Target != taskID {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Field != refs[j].Field {
+			return refs[i].Field < refs[j].Field
+		}
+		if refs[i].Target != refs[j].Target {
+			return refs[i].Target < refs[j].Target
+		}
+		return refs[i].Artifact < refs[j].Artifact
+	})
+	return refs
+}
+
+// DanglingReferences returns the references of the task to tasks that have not necessarily completed once the task
+// is started, because the task does not (transitively) have a data dependency on them, and the references to fields
+// of the output of direct data dependencies that are not listed in the artifacts of the dependency.
+func DanglingReferences(taskID string, task *types.TaskSpec, tasks map[string]*types.TaskSpec) []Reference {
+	completed := completedBefore(taskID, task, tasks, map[string]map[string]bool{})
+	var dangling []Reference
+	for _, ref := range References(taskID, task, tasks) {
+		if !completed[ref.Target] {
+			dangling = append(dangling, ref)
+			continue
+		}
+		artifacts := task.GetRequires()[ref.Target].GetArtifacts()
+		if len(ref.Artifact) > 0 && len(artifacts) > 0 && !contains(artifacts, ref.Artifact) {
+			dangling = append(dangling, ref)
+		}
+	}
+	return dangling
+}
+
+// completedBefore returns the ids of the tasks that have completed once the task is started. These are the data
+// dependencies of the task and, transitively, the tasks that have completed before its dependencies are started.
+func completedBefore(taskID string, task *types.TaskSpec, tasks map[string]*types.TaskSpec,
+	cache map[string]map[string]bool) map[string]bool {
+	if completed, ok := cache[taskID]; ok {
+		return completed
+	}
+	completed := map[string]bool{}
+	cache[taskID] = completed // Guards against cycles
+	for dep, params := range task.GetRequires() {
+		if params.GetType() != types.TaskDependencyParameters_CONTROL {
+			completed[dep] = true
+		}
+		if depTask, ok := tasks[dep]; ok {
+			for id := range completedBefore(dep, depTask, tasks, cache) {
+				completed[id] = true
+			}
+		}
+	}
+	return completed
+}
+
+// expressions returns the expressions in the unwrapped value, including those nested in maps and lists.
+func expressions(i interface{}) []string {
+	switch v := i.(type) {
+	case string:
+		if typedvalues.IsExpression(v) {
+			return []string{v}
+		}
+	case map[string]interface{}:
+		var exprs []string
+		for _, e := range v {
+			exprs = append(exprs, expressions(e)...)
+		}
+		return exprs
+	case []interface{}:
+		var exprs []string
+		for _, e := range v {
+			exprs = append(exprs, expressions(e)...)
+		}
+		return exprs
+	}
+	return nil
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
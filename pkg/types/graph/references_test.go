@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReferences(t *testing.T) {
+	tasks := types.Tasks{
+		"a": types.NewTaskSpec("noop"),
+		"b": types.NewTaskSpec("noop"),
+		"c": {
+			FunctionRef: "noop",
+			Inputs: map[string]*typedvalues.TypedValue{
+				"nested": typedvalues.MustWrap(map[string]interface{}{
+					"list": []interface{}{"{$.Tasks.a.Output.x + output('b')}"},
+				}),
+				"self":    typedvalues.MustWrap("{$.Tasks.c.Inputs.nested}"),
+				"unknown": typedvalues.MustWrap("{$.Tasks.missing.Output}"),
+				"literal": typedvalues.MustWrap("$.Tasks.a.Output"),
+			},
+			When: typedvalues.MustWrap("{ jq: .Tasks.b.Output.ok }"),
+		},
+	}
+
+	assert.Equal(t, []Reference{
+		{TaskID: "c", Field: "inputs.nested", Target: "a", Artifact: "x"},
+		{TaskID: "c", Field: "inputs.nested", Target: "b"},
+		{TaskID: "c", Field: "when", Target: "b", Artifact: "ok"},
+	}, References("c", tasks["c"], tasks))
+}
+
+func TestDanglingReferences(t *testing.T) {
+	tasks := types.Tasks{
+		"a": types.NewTaskSpec("noop"),
+		"b": {
+			FunctionRef: "noop",
+			Requires: types.Requires{
+				"a": {Artifacts: []string{"x"}},
+			},
+		},
+		"c": {
+			FunctionRef: "noop",
+			Requires:    types.Require("b"),
+		},
+		"d": {
+			FunctionRef: "noop",
+			Inputs: map[string]*typedvalues.TypedValue{
+				"declared":   typedvalues.MustWrap("{$.Tasks.a.Output.x}"),
+				"undeclared": typedvalues.MustWrap("{$.Tasks.a.Output.y}"),
+				"transitive": typedvalues.MustWrap("{$.Tasks.b.Output}"),
+				"control":    typedvalues.MustWrap("{$.Tasks.c.Output}"),
+				"missing":    typedvalues.MustWrap("{$.Tasks.e.Output}"),
+			},
+			Requires: types.Requires{
+				"a": {Artifacts: []string{"x"}},
+			}.AddControl("c"),
+		},
+		"e": types.NewTaskSpec("noop"),
+	}
+
+	assert.Equal(t, []Reference{
+		{TaskID: "d", Field: "inputs.control", Target: "c"},
+		{TaskID: "d", Field: "inputs.missing", Target: "e"},
+		{TaskID: "d", Field: "inputs.undeclared", Target: "a", Artifact: "y"},
+	}, DanglingReferences("d", tasks["d"], tasks))
+}
@@ -0,0 +1,290 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fission/fission-workflows/pkg/types"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// statusColors are the fill colors of the tasks of an invocation, by the status of the task.
+var statusColors = map[types.TaskInvocationStatus_Status]string{
+	types.TaskInvocationStatus_SCHEDULED:   "#bbdefb",
+	types.TaskInvocationStatus_IN_PROGRESS: "#fff59d",
+	types.TaskInvocationStatus_SUCCEEDED:   "#c8e6c9",
+	types.TaskInvocationStatus_FAILED:      "#ffcdd2",
+	types.TaskInvocationStatus_ABORTED:     "#e0e0e0",
+	types.TaskInvocationStatus_SKIPPED:     "#f5f5f5",
+}
+
+// danglingColor is the color of the edges of references to tasks without a (matching) data dependency.
+const danglingColor = "#e53935"
+
+// Visualization is the task graph of a workflow or invocation, in a form that can be rendered.
+type Visualization struct {
+	Name       string
+	OutputTask string
+	Nodes      []VisualizationNode
+	Edges      []VisualizationEdge
+}
+
+type VisualizationNode struct {
+	TaskID   string
+	Function string
+
+	// Status is the status of the task in the invocation, or UNKNOWN if the task has not run (yet).
+	Status types.TaskInvocationStatus_Status
+
+	// Dynamic is true for tasks that were added to the invocation while it was running.
+	Dynamic bool
+}
+
+type VisualizationEdge struct {
+	From string
+	To   string
+
+	// Dynamic is true for the edges of dynamic tasks.
+	Dynamic bool
+
+	// Control is true for control dependencies, of which the task does not use the output.
+	Control bool
+
+	// Artifacts are the fields of the output of the dependency that the task uses, if declared.
+	Artifacts []string
+
+	// Dangling is true if the task refers to the output of the other task without a (matching) data dependency on it.
+	// Dangling edges without a dependency are added to the visualization for the references themselves.
+	Dangling bool
+}
+
+// NewWorkflowVisualization creates the visualization of the tasks of the workflow.
+func NewWorkflowVisualization(wf *types.Workflow) *Visualization {
+	return newVisualization(wf.ID(), wf.GetSpec().GetOutputTask(), wf.Tasks(), nil, nil)
+}
+
+// NewInvocationVisualization creates the visualization of the tasks of the invocation, which includes the dynamic
+// tasks and the status of the tasks.
+func NewInvocationVisualization(wi *types.WorkflowInvocation) *Visualization {
+	return newVisualization(wi.ID(), wi.Workflow().GetSpec().GetOutputTask(), wi.Tasks(),
+		wi.GetStatus().GetDynamicTasks(), wi.GetStatus().GetTasks())
+}
+
+func newVisualization(name string, outputTask string, tasks map[string]*types.Task, dynamicTasks map[string]*types.Task,
+	runs map[string]*types.TaskInvocation) *Visualization {
+	v := &Visualization{
+		Name:       name,
+		OutputTask: outputTask,
+	}
+	ids := make([]string, 0, len(tasks))
+	for id := range tasks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Dependents of the parents of dynamic tasks also depend on the dynamic task.
+	dependents := map[string][]string{}
+	specs := map[string]*types.TaskSpec{}
+	for _, id := range ids {
+		for dep := range tasks[id].GetSpec().GetRequires() {
+			dependents[dep] = append(dependents[dep], id)
+		}
+		specs[id] = tasks[id].GetSpec()
+	}
+
+	for _, id := range ids {
+		task := tasks[id]
+		_, dynamic := dynamicTasks[id]
+		v.Nodes = append(v.Nodes, VisualizationNode{
+			TaskID:   id,
+			Function: task.GetSpec().GetFunctionRef(),
+			Status:   runs[id].GetStatus().GetStatus(),
+			Dynamic:  dynamic,
+		})
+
+		dangling := map[string]bool{}
+		for _, ref := range DanglingReferences(id, task.GetSpec(), specs) {
+			dangling[ref.Target] = true
+		}
+
+		deps := make([]string, 0, len(task.GetSpec().GetRequires()))
+		for dep := range task.GetSpec().GetRequires() {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := tasks[dep]; !ok {
+				continue
+			}
+			params := task.GetSpec().GetRequires()[dep]
+			if params.GetType() != types.TaskDependencyParameters_DYNAMIC_OUTPUT {
+				v.Edges = append(v.Edges, VisualizationEdge{
+					From:      dep,
+					To:        id,
+					Control:   params.GetType() == types.TaskDependencyParameters_CONTROL,
+					Artifacts: params.GetArtifacts(),
+					Dangling:  dangling[dep],
+				})
+				continue
+			}
+			v.Edges = append(v.Edges, VisualizationEdge{From: dep, To: id, Dynamic: true})
+			for _, dependent := range dependents[dep] {
+				if dependent != id {
+					v.Edges = append(v.Edges, VisualizationEdge{From: id, To: dependent, Dynamic: true})
+				}
+			}
+		}
+
+		// Show the references to tasks that the task does not depend on at all.
+		for _, target := range sortedKeys(dangling) {
+			if _, ok := task.GetSpec().GetRequires()[target]; !ok {
+				v.Edges = append(v.Edges, VisualizationEdge{From: target, To: id, Dangling: true})
+			}
+		}
+	}
+	return v
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Render writes the visualization in the format: FormatDOT or FormatMermaid.
+func (v *Visualization) Render(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case FormatDOT, "graphviz", "":
+		return v.renderDOT(w)
+	case FormatMermaid:
+		return v.renderMermaid(w)
+	default:
+		return fmt.Errorf("unknown graph format '%s' (expected %s or %s)", format, FormatDOT, FormatMermaid)
+	}
+}
+
+func (n VisualizationNode) label(separator string) string {
+	label := n.TaskID
+	if len(n.Function) > 0 && n.Function != n.TaskID {
+		label += separator + n.Function
+	}
+	if n.Status != types.TaskInvocationStatus_UNKNOWN {
+		label += separator + n.Status.String()
+	}
+	return label
+}
+
+func (v *Visualization) renderDOT(w io.Writer) error {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "digraph %s {\n", dotQuote(v.Name))
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=\"#ffffff\"];\n")
+	for _, n := range v.Nodes {
+		attrs := []string{"label=" + dotQuote(n.label("\n"))}
+		if color, ok := statusColors[n.Status]; ok {
+			attrs = append(attrs, "fillcolor="+dotQuote(color))
+		}
+		if n.Dynamic {
+			attrs = append(attrs, "style=\"rounded,filled,dashed\"")
+		}
+		if n.TaskID == v.OutputTask {
+			attrs = append(attrs, "peripheries=2")
+		}
+		fmt.Fprintf(b, "  %s [%s];\n", dotQuote(n.TaskID), strings.Join(attrs, ", "))
+	}
+	for _, e := range v.Edges {
+		var attrs []string
+		switch {
+		case e.Dynamic:
+			attrs = append(attrs, "style=dashed")
+		case e.Control:
+			attrs = append(attrs, "style=dotted")
+		}
+		if e.Dangling {
+			attrs = append(attrs, "color="+dotQuote(danglingColor))
+		}
+		if len(e.Artifacts) > 0 {
+			attrs = append(attrs, "label="+dotQuote(strings.Join(e.Artifacts, ", ")))
+		}
+		if len(attrs) > 0 {
+			fmt.Fprintf(b, "  %s -> %s [%s];\n", dotQuote(e.From), dotQuote(e.To), strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(b, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+		}
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "\"", "\\\"", -1)
+	s = strings.Replace(s, "\n", "\\n", -1)
+	return "\"" + s + "\""
+}
+
+func (v *Visualization) renderMermaid(w io.Writer) error {
+	// Mermaid ids are restricted to simple identifiers, so the tasks are numbered instead.
+	ids := map[string]string{}
+	for i, n := range v.Nodes {
+		ids[n.TaskID] = fmt.Sprintf("t%d", i)
+	}
+
+	b := &strings.Builder{}
+	b.WriteString("graph TD\n")
+	for _, n := range v.Nodes {
+		label := mermaidQuote(n.label("<br/>"))
+		if n.TaskID == v.OutputTask {
+			fmt.Fprintf(b, "  %s([%s])\n", ids[n.TaskID], label)
+		} else {
+			fmt.Fprintf(b, "  %s[%s]\n", ids[n.TaskID], label)
+		}
+	}
+	var danglingLinks []string
+	for i, e := range v.Edges {
+		arrow := "-->"
+		switch {
+		case e.Dynamic:
+			arrow = "-.->"
+		case e.Control:
+			arrow = "--o"
+		}
+		if len(e.Artifacts) > 0 {
+			arrow += "|" + mermaidQuote(strings.Join(e.Artifacts, ", ")) + "|"
+		}
+		fmt.Fprintf(b, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+		if e.Dangling {
+			danglingLinks = append(danglingLinks, fmt.Sprintf("%d", i))
+		}
+	}
+	for _, n := range v.Nodes {
+		var styles []string
+		if color, ok := statusColors[n.Status]; ok {
+			styles = append(styles, "fill:"+color)
+		}
+		if n.Dynamic {
+			styles = append(styles, "stroke-dasharray:5 5")
+		}
+		if len(styles) > 0 {
+			fmt.Fprintf(b, "  style %s %s\n", ids[n.TaskID], strings.Join(styles, ","))
+		}
+	}
+	if len(danglingLinks) > 0 {
+		fmt.Fprintf(b, "  linkStyle %s stroke:%s\n", strings.Join(danglingLinks, ","), danglingColor)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return "\"" + strings.Replace(s, "\"", "#quot;", -1) + "\""
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testWorkflow() *types.Workflow {
+	wf := types.NewWorkflow("wf")
+	wf.Spec.OutputTask = "c"
+	wf.Spec.AddTask("a", types.NewTaskSpec("noop"))
+	wf.Spec.AddTask("b", types.NewTaskSpec("foreach").Require("a"))
+	wf.Spec.AddTask("c", types.NewTaskSpec("noop").Require("b"))
+	return wf
+}
+
+func TestNewWorkflowVisualization(t *testing.T) {
+	v := NewWorkflowVisualization(testWorkflow())
+	assert.Equal(t, "wf", v.Name)
+	assert.Len(t, v.Nodes, 3)
+	assert.Equal(t, []VisualizationEdge{
+		{From: "a", To: "b"},
+		{From: "b", To: "c"},
+	}, v.Edges)
+}
+
+func TestNewInvocationVisualization(t *testing.T) {
+	wi := types.NewWorkflowInvocation("wf", "wi", time.Now().Add(time.Minute))
+	wi.Spec.Workflow = testWorkflow()
+	dynamic := types.NewTask("b_dyn", "noop")
+	dynamic.Spec.Require("b", &types.TaskDependencyParameters{Type: types.TaskDependencyParameters_DYNAMIC_OUTPUT})
+	wi.Status.DynamicTasks = map[string]*types.Task{"b_dyn": dynamic}
+	wi.Status.Tasks = map[string]*types.TaskInvocation{
+		"a": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_SUCCEEDED}},
+		"b": {Status: &types.TaskInvocationStatus{Status: types.TaskInvocationStatus_IN_PROGRESS}},
+	}
+
+	v := NewInvocationVisualization(wi)
+	assert.Len(t, v.Nodes, 4)
+	for _, n := range v.Nodes {
+		assert.Equal(t, n.TaskID == "b_dyn", n.Dynamic)
+	}
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, v.Nodes[0].Status)
+	assert.Equal(t, types.TaskInvocationStatus_UNKNOWN, v.Nodes[3].Status)
+	// The dependents of the parent of a dynamic task also depend on the dynamic task.
+	assert.Contains(t, v.Edges, VisualizationEdge{From: "b", To: "b_dyn", Dynamic: true})
+	assert.Contains(t, v.Edges, VisualizationEdge{From: "b_dyn", To: "c", Dynamic: true})
+}
+
+func TestVisualization_Render(t *testing.T) {
+	v := NewWorkflowVisualization(testWorkflow())
+
+	dot := &bytes.Buffer{}
+	assert.NoError(t, v.Render(dot, FormatDOT))
+	assert.Contains(t, dot.String(), `digraph "wf" {`)
+	assert.Contains(t, dot.String(), `"b" [label="b\nforeach"];`)
+	assert.Contains(t, dot.String(), `"c" [label="c\nnoop", peripheries=2];`)
+	assert.Contains(t, dot.String(), `"a" -> "b";`)
+
+	mermaid := &bytes.Buffer{}
+	assert.NoError(t, v.Render(mermaid, FormatMermaid))
+	assert.Contains(t, mermaid.String(), "graph TD\n")
+	assert.Contains(t, mermaid.String(), `t2(["c<br/>noop"])`)
+	assert.Contains(t, mermaid.String(), "t0 --> t1\n")
+
+	assert.Error(t, v.Render(&bytes.Buffer{}, "svg"))
+}
+
+func TestVisualization_DependencyTypes(t *testing.T) {
+	wf := types.NewWorkflow("wf")
+	wf.Spec.OutputTask = "c"
+	wf.Spec.AddTask("a", types.NewTaskSpec("noop"))
+	wf.Spec.AddTask("b", types.NewTaskSpec("noop").Require("a", &types.TaskDependencyParameters{
+		Artifacts: []string{"x"},
+	}))
+	c := types.NewTaskSpec("noop").Require("b", &types.TaskDependencyParameters{
+		Type: types.TaskDependencyParameters_CONTROL,
+	})
+	c.Inputs = types.Input("{$.Tasks.b.Output}")
+	wf.Spec.AddTask("c", c)
+
+	v := NewWorkflowVisualization(wf)
+	assert.Equal(t, []VisualizationEdge{
+		{From: "a", To: "b", Artifacts: []string{"x"}},
+		{From: "b", To: "c", Control: true, Dangling: true},
+	}, v.Edges)
+
+	dot := &bytes.Buffer{}
+	assert.NoError(t, v.Render(dot, FormatDOT))
+	assert.Contains(t, dot.String(), `"a" -> "b" [label="x"];`)
+	assert.Contains(t, dot.String(), `"b" -> "c" [style=dotted, color="#e53935"];`)
+
+	mermaid := &bytes.Buffer{}
+	assert.NoError(t, v.Render(mermaid, FormatMermaid))
+	assert.Contains(t, mermaid.String(), "t0 -->|\"x\"| t1\n")
+	assert.Contains(t, mermaid.String(), "t1 --o t2\n")
+	assert.Contains(t, mermaid.String(), "linkStyle 1 stroke:#e53935\n")
+}
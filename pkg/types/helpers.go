@@ -86,6 +86,14 @@ func (r Requires) Add(s ...string) Requires {
 	return r
 }
 
+// AddControl adds control dependencies on the tasks, which only order the start of the tasks.
+func (r Requires) AddControl(s ...string) Requires {
+	for _, v := range s {
+		r[v] = &TaskDependencyParameters{Type: TaskDependencyParameters_CONTROL}
+	}
+	return r
+}
+
 func Require(s ...string) Requires {
 	return Requires{}.Add(s...)
 }
@@ -105,15 +113,49 @@ type NamedTypedValue struct {
 
 type Inputs map[string]*typedvalues.TypedValue
 
+// NewRunIntent creates the intent to run the task of an invocation.
+func NewRunIntent(taskID string) *Intent {
+	return &Intent{
+		Action:    Intent_RUN,
+		TaskId:    taskID,
+		CreatedAt: ptypes.TimestampNow(),
+	}
+}
+
+// NewFailIntent creates the intent to fail an invocation because of the error.
+func NewFailIntent(err error) *Intent {
+	intent := &Intent{
+		Action:    Intent_FAIL,
+		CreatedAt: ptypes.TimestampNow(),
+	}
+	if err != nil {
+		intent.Error = &Error{Message: err.Error()}
+	}
+	return intent
+}
+
+// NewCompleteIntent creates the intent to complete an invocation.
+func NewCompleteIntent() *Intent {
+	return &Intent{
+		Action:    Intent_COMPLETE,
+		CreatedAt: ptypes.TimestampNow(),
+	}
+}
+
 func NewTaskInvocationSpec(invocation *WorkflowInvocation, task *Task, startAt time.Time) *TaskInvocationSpec {
 	// Decide on the deadline of the task invocation.
-	// If there is no timeout specified for the task, use the deadline of the overall workflow invocation.
-	// If there is a timeout specified for the task, calculate the deadline using the startAt + timout time, but do not
-	// exceed the invocation deadline.
+	// If there is no timeout specified for the task, use the timeout hint of the function, if any.
+	// If there is no timeout at all, use the deadline of the overall workflow invocation.
+	// If there is a timeout, calculate the deadline using the startAt + timout time, but do not exceed the invocation
+	// deadline.
 	deadline := invocation.GetSpec().GetDeadline()
-	if task.GetSpec().GetTimeout() != nil {
+	timeout := task.GetSpec().GetTimeout()
+	if timeout == nil {
+		timeout = task.GetStatus().GetFnHints().GetTimeout()
+	}
+	if timeout != nil {
 		deadlineTime, err := ptypes.Timestamp(deadline)
-		maxRuntime, err := ptypes.Duration(task.GetSpec().GetTimeout())
+		maxRuntime, err := ptypes.Duration(timeout)
 		if err == nil {
 			taskMaxRuntime := startAt.Add(maxRuntime)
 			if taskMaxRuntime.Before(deadlineTime) {
@@ -124,6 +166,7 @@ func NewTaskInvocationSpec(invocation *WorkflowInvocation, task *Task, startAt t
 
 	return &TaskInvocationSpec{
 		InvocationId: invocation.ID(),
+		WorkflowId:   invocation.GetSpec().GetWorkflowId(),
 		Task:         task,
 		FnRef:        task.GetStatus().GetFnRef(),
 		TaskId:       task.ID(),
@@ -1,9 +1,12 @@
 package types
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +69,121 @@ func TestCalculateWorkflowWithDynamicTasks(t *testing.T) {
 	assert.Equal(t, 0, len(cwf["foo"].Spec.Requires))
 	assert.Equal(t, int32(42), cwf["bar2"].Spec.Await)
 }
+
+func TestNewTaskInvocationSpecDeadline(t *testing.T) {
+	now := time.Now()
+	invocation := NewWorkflowInvocation("wf-1", "wfi-1", now.Add(time.Hour))
+	task := &Task{
+		Metadata: NewObjectMetadata("foo"),
+		Spec:     &TaskSpec{FunctionRef: "fn"},
+		Status:   &TaskStatus{},
+	}
+	deadline := func() time.Time {
+		ts, err := ptypes.Timestamp(NewTaskInvocationSpec(invocation, task, now).GetDeadline())
+		assert.NoError(t, err)
+		return ts
+	}
+
+	// Without timeouts, the deadline of the invocation is used
+	assert.WithinDuration(t, now.Add(time.Hour), deadline(), time.Millisecond)
+
+	// The timeout hint of the function is used if the task has no timeout
+	task.Status.FnHints = &FnHints{Timeout: ptypes.DurationProto(time.Minute)}
+	assert.WithinDuration(t, now.Add(time.Minute), deadline(), time.Millisecond)
+
+	// The timeout of the task overrides the hint
+	task.Spec.Timeout = ptypes.DurationProto(10 * time.Second)
+	assert.WithinDuration(t, now.Add(10*time.Second), deadline(), time.Millisecond)
+}
+
+func TestIntentID(t *testing.T) {
+	assert.Equal(t, "run.foo", NewRunIntent("foo").ID())
+	assert.Equal(t, "fail", NewFailIntent(errors.New("failed")).ID())
+	assert.Equal(t, "complete", NewCompleteIntent().ID())
+	assert.Equal(t, "failed", NewFailIntent(errors.New("failed")).GetError().GetMessage())
+	assert.Nil(t, NewFailIntent(nil).GetError())
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	spec := &TaskInvocationSpec{InvocationId: "wi-1", Task: NewTask("foo", "fn")}
+	key := spec.IdempotencyKey()
+	assert.Len(t, key, 32)
+	assert.Equal(t, key, (&TaskInvocationSpec{InvocationId: "wi-1", TaskId: "foo"}).IdempotencyKey())
+	assert.NotEqual(t, key, (&TaskInvocationSpec{InvocationId: "wi-2", TaskId: "foo"}).IdempotencyKey())
+	assert.NotEqual(t, key, (&TaskInvocationSpec{InvocationId: "wi-1", TaskId: "bar"}).IdempotencyKey())
+	assert.NotEqual(t, key, (&TaskInvocationSpec{InvocationId: "wi-1", TaskId: "foo", Attempt: 1}).IdempotencyKey())
+}
+
+func TestWorkflowSpec_WithDefaultInputs(t *testing.T) {
+	spec := NewWorkflowSpec()
+	inputs := map[string]*typedvalues.TypedValue{
+		"a": typedvalues.MustWrap("provided"),
+	}
+	assert.Equal(t, inputs, spec.WithDefaultInputs(inputs))
+
+	spec.DefaultInputs = map[string]*typedvalues.TypedValue{
+		"a": typedvalues.MustWrap("default"),
+		"b": typedvalues.MustWrap(42),
+	}
+	result := spec.WithDefaultInputs(inputs)
+	assert.Equal(t, map[string]*typedvalues.TypedValue{
+		"a": typedvalues.MustWrap("provided"),
+		"b": typedvalues.MustWrap(42),
+	}, result)
+	// The provided inputs are not modified.
+	assert.Len(t, inputs, 1)
+
+	assert.Equal(t, spec.DefaultInputs, spec.WithDefaultInputs(nil))
+}
+
+func TestTaskSpec_Retryable(t *testing.T) {
+	spec := &TaskSpec{MaxRetries: 2}
+	failed := func(attempts int32, code int32) *TaskInvocationStatus {
+		return &TaskInvocationStatus{
+			Status:   TaskInvocationStatus_FAILED,
+			Attempts: attempts,
+			Error:    &Error{Message: "failed", StatusCode: code},
+		}
+	}
+	assert.True(t, spec.Retryable(failed(1, 500)))
+	assert.True(t, spec.Retryable(failed(2, 500)))
+	assert.False(t, spec.Retryable(failed(3, 500)))
+	assert.False(t, spec.Retryable(failed(0, 500)))
+	assert.False(t, spec.Retryable(&TaskInvocationStatus{Status: TaskInvocationStatus_SUCCEEDED, Attempts: 1}))
+	assert.False(t, (&TaskSpec{}).Retryable(failed(1, 500)))
+
+	spec.RetryableStatusCodes = []int32{502, 503}
+	assert.True(t, spec.Retryable(failed(1, 503)))
+	assert.False(t, spec.Retryable(failed(1, 500)))
+	assert.False(t, spec.Retryable(failed(1, 0)))
+}
+
+func TestTaskInvocation_RetryAt(t *testing.T) {
+	failedAt := time.Now()
+	ts, _ := ptypes.TimestampProto(failedAt)
+	task := NewTask("foo", "fn")
+	task.Spec.MaxRetries = 3
+	task.Spec.Backoff = ptypes.DurationProto(time.Second)
+	taskRun := &TaskInvocation{
+		Spec: &TaskInvocationSpec{Task: task},
+		Status: &TaskInvocationStatus{
+			Status:    TaskInvocationStatus_FAILED,
+			UpdatedAt: ts,
+			Attempts:  1,
+		},
+	}
+
+	retryAt, ok := taskRun.RetryAt()
+	assert.True(t, ok)
+	assert.True(t, failedAt.Add(time.Second).Equal(retryAt))
+
+	// The backoff is doubled for every subsequent retry.
+	taskRun.Status.Attempts = 3
+	retryAt, ok = taskRun.RetryAt()
+	assert.True(t, ok)
+	assert.True(t, failedAt.Add(4*time.Second).Equal(retryAt))
+
+	taskRun.Status.Attempts = 4
+	_, ok = taskRun.RetryAt()
+	assert.False(t, ok)
+}
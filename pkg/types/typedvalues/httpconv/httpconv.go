@@ -24,12 +24,20 @@ import (
 )
 
 const (
-	inputContentType  = "content-type"
-	headerContentType = "Content-Type"
+	inputContentType       = "content-type"
+	headerContentType      = "Content-Type"
+	DefaultStreamThreshold = 8 * 1024 * 1024  // 8 MiB
+	DefaultMaxMemorySize   = 64 * 1024 * 1024 // 64 MiB
 )
 
+// ErrBodyTooLarge is returned when a body exceeds the maximum in-memory size, and cannot be streamed because no stream
+// store is registered.
+var ErrBodyTooLarge = errors.New("body exceeds the maximum in-memory size")
+
 var DefaultHTTPMapper = &HTTPMapper{
 	DefaultHTTPMethod: http.MethodPost,
+	StreamThreshold:   DefaultStreamThreshold,
+	MaxMemorySize:     DefaultMaxMemorySize,
 	ValueTypeResolver: func(tv *typedvalues.TypedValue) *mediatype.MediaType {
 		// Check metadata of the value
 		if tv == nil {
@@ -45,6 +53,13 @@ var DefaultHTTPMapper = &HTTPMapper{
 
 		// Handle special cases
 		switch tv.ValueType() {
+		case typedvalues.TypeStream:
+			if stream, err := typedvalues.UnwrapStream(tv); err == nil {
+				if mt, err := mediatype.Parse(stream.GetContentType()); err == nil {
+					return mt
+				}
+			}
+			return MediaTypeBytes
 		case typedvalues.TypeBytes:
 			return MediaTypeBytes
 		case typedvalues.TypeNil:
@@ -114,12 +129,23 @@ type HTTPMapper struct {
 	ValueTypeResolver func(tv *typedvalues.TypedValue) *mediatype.MediaType
 	DefaultMediaType  *mediatype.MediaType
 	MediaTypeResolver func(mediaType *mediatype.MediaType) ParserFormatter
+
+	// StreamThreshold is the size (in bytes) above which binary bodies are not materialized in memory, but stored as
+	// streams in the registered stream store. If no stream store is registered, or the threshold is 0, bodies are
+	// always materialized.
+	StreamThreshold int64
+
+	// MaxMemorySize is the size (in bytes) above which bodies of any content type are not materialized in memory.
+	// Structured bodies, such as JSON, can only be parsed into values in memory; above this size, they are stored as
+	// streams with their content type instead. If no stream store is registered, parsing these bodies fails with
+	// ErrBodyTooLarge. If 0, the size of bodies is not limited.
+	MaxMemorySize int64
 }
 
 func (h *HTTPMapper) ParseResponse(resp *http.Response) (*typedvalues.TypedValue, error) {
 	contentType := h.getRequestContentType(resp.Header)
 	defer resp.Body.Close()
-	return DefaultHTTPMapper.parseBody(resp.Body, contentType)
+	return h.parseBody(resp.Body, contentType, resp.ContentLength)
 }
 
 func (h *HTTPMapper) ParseResponseHeaders(resp *http.Response) *typedvalues.TypedValue {
@@ -153,7 +179,7 @@ func (h *HTTPMapper) ParseRequest(req *http.Request) (map[string]*typedvalues.Ty
 
 	// Default case parse body using the Parser interface
 	default:
-		body, err = h.parseBody(req.Body, contentType)
+		body, err = h.parseBody(req.Body, contentType, req.ContentLength)
 		if err != nil {
 			return nil, errors.Errorf("failed to parse request: %v", err)
 		}
@@ -194,6 +220,22 @@ func (h *HTTPMapper) FormatResponse(w http.ResponseWriter, output *typedvalues.T
 		return
 	}
 
+	// Resolve values that have been offloaded to a blob store
+	output, err := typedvalues.Dereference(output)
+	if err != nil {
+		h.FormatResponse(w, nil, nil, &types.Error{
+			Message: fmt.Sprintf("Failed to dereference response body: %v", err),
+		})
+		return
+	}
+	outputHeaders, err = typedvalues.Dereference(outputHeaders)
+	if err != nil {
+		h.FormatResponse(w, nil, nil, &types.Error{
+			Message: fmt.Sprintf("Failed to dereference response headers: %v", err),
+		})
+		return
+	}
+
 	headers := h.formatHeaders(outputHeaders)
 	for k, v := range headers {
 		if len(v) > 0 {
@@ -201,9 +243,14 @@ func (h *HTTPMapper) FormatResponse(w http.ResponseWriter, output *typedvalues.T
 		}
 	}
 
+	if typedvalues.IsStream(output) {
+		h.formatStreamResponse(w, output)
+		return
+	}
+
 	w.WriteHeader(http.StatusOK)
 	contentType := h.ValueTypeResolver(output)
-	err := h.formatBody(w, output, contentType)
+	err = h.formatBody(w, output, contentType)
 	if err != nil {
 		h.FormatResponse(w, nil, nil, &types.Error{
 			Message: fmt.Sprintf("Failed to format response body: %v", err),
@@ -218,16 +265,29 @@ func (h *HTTPMapper) FormatRequest(source map[string]*typedvalues.TypedValue, ta
 		panic("cannot format request to nil")
 	}
 
+	// Resolve values that have been offloaded to a blob store
+	source, err := dereferenceAll(source)
+	if err != nil {
+		return err
+	}
+
 	// Map content-type to the request's content-type
 	contentType := h.determineContentTypeFromInputs(source)
 
 	// Map 'body' input to the body of the request
 	mainInput := getFirstDefined(source, types.InputBody, types.InputMain)
-	if mainInput != nil {
-		err := h.formatBody(&requestWriter{req: target}, mainInput, contentType)
+	if typedvalues.IsStream(mainInput) {
+		err := h.formatStreamRequest(target, mainInput, contentType)
+		if err != nil {
+			return err
+		}
+	} else if mainInput != nil {
+		rw := &requestWriter{req: target}
+		err := h.formatBody(rw, mainInput, contentType)
 		if err != nil {
 			return err
 		}
+		rw.flush()
 	}
 
 	// Map method input to HTTP method
@@ -268,16 +328,107 @@ func (h *HTTPMapper) Clone() *HTTPMapper {
 		DefaultHTTPMethod: h.DefaultHTTPMethod,
 		ValueTypeResolver: h.ValueTypeResolver,
 		MediaTypeResolver: h.MediaTypeResolver,
+		StreamThreshold:   h.StreamThreshold,
+		MaxMemorySize:     h.MaxMemorySize,
 	}
 }
 
-// parseBody maps the body of the HTTP request to a corresponding typedvalue.
-func (h *HTTPMapper) parseBody(data io.Reader, contentType *mediatype.MediaType) (*typedvalues.TypedValue, error) {
+// parseBody maps the body of the HTTP request to a corresponding typedvalue. The size is the length of the body, or
+// -1 if unknown.
+func (h *HTTPMapper) parseBody(data io.Reader, contentType *mediatype.MediaType, size int64) (*typedvalues.TypedValue,
+	error) {
 	if contentType == nil {
 		contentType = h.DefaultMediaType
 	}
 
-	return h.MediaTypeResolver(contentType).Parse(contentType, data)
+	parser := h.MediaTypeResolver(contentType)
+	limit := h.MaxMemorySize
+	if _, isBinary := parser.(*BytesMapper); isBinary && h.StreamThreshold > 0 && typedvalues.StreamingEnabled() &&
+		(limit <= 0 || h.StreamThreshold < limit) {
+		limit = h.StreamThreshold
+	}
+	if limit > 0 {
+		if size > limit {
+			return h.parseLargeBody(data, contentType, size)
+		}
+		if size < 0 {
+			// The size is unknown (e.g. chunked transfer encoding), so read up to the limit to decide.
+			buf := &bytes.Buffer{}
+			n, err := io.CopyN(buf, data, limit+1)
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			if n > limit {
+				return h.parseLargeBody(io.MultiReader(buf, data), contentType, -1)
+			}
+			data = buf
+		}
+	}
+
+	return parser.Parse(contentType, data)
+}
+
+// parseLargeBody stores a body that is too large to materialize in memory as a stream.
+func (h *HTTPMapper) parseLargeBody(data io.Reader, contentType *mediatype.MediaType, size int64) (
+	*typedvalues.TypedValue, error) {
+	if !typedvalues.StreamingEnabled() {
+		return nil, errors.Wrapf(ErrBodyTooLarge, "%s body larger than %d bytes", contentType, h.MaxMemorySize)
+	}
+	return typedvalues.NewStream(data, contentType.String(), size)
+}
+
+// formatStreamRequest sets the body of the request to the data of the stream, which is transferred in chunks.
+func (h *HTTPMapper) formatStreamRequest(target *http.Request, body *typedvalues.TypedValue,
+	contentType *mediatype.MediaType) error {
+	stream, err := typedvalues.UnwrapStream(body)
+	if err != nil {
+		return err
+	}
+	rc, err := typedvalues.OpenStream(body)
+	if err != nil {
+		return err
+	}
+	if contentType == nil {
+		contentType = h.ValueTypeResolver(body)
+	}
+	if target.Header == nil {
+		target.Header = http.Header{}
+	}
+	target.Header.Set(headerContentType, contentType.String())
+	target.Body = rc
+	// If the size is unknown (0), the request is sent using chunked transfer encoding.
+	target.ContentLength = stream.GetSize()
+	if target.ContentLength == 0 {
+		target.ContentLength = -1
+	}
+	return nil
+}
+
+// formatStreamResponse writes the data of the stream to the response, without materializing it in memory.
+func (h *HTTPMapper) formatStreamResponse(w http.ResponseWriter, output *typedvalues.TypedValue) {
+	stream, err := typedvalues.UnwrapStream(output)
+	if err != nil {
+		h.FormatResponse(w, nil, nil, &types.Error{Message: err.Error()})
+		return
+	}
+	rc, err := typedvalues.OpenStream(output)
+	if err != nil {
+		h.FormatResponse(w, nil, nil, &types.Error{
+			Message: fmt.Sprintf("Failed to open response body: %v", err),
+		})
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set(headerContentType, h.ValueTypeResolver(output).String())
+	if stream.GetSize() > 0 {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", stream.GetSize()))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, rc); err != nil {
+		// The status has already been written, so the error can only be logged.
+		logrus.Errorf("Failed to stream response body: %v", err)
+	}
 }
 
 // parseMethod maps the method param from a request to a TypedValue
@@ -455,10 +606,39 @@ func (rw *requestWriter) Write(data []byte) (int, error) {
 	return n, nil
 }
 
+// flush sets the buffered body as a replayable body of the request, allowing clients to retry the request.
+func (rw *requestWriter) flush() {
+	if rw.buf == nil {
+		return
+	}
+	body := rw.buf.Bytes()
+	rw.req.ContentLength = int64(len(body))
+	rw.req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	rw.req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+}
+
 func (rw *requestWriter) WriteHeader(statusCode int) {
 	return // Not relevant for http.Request
 }
 
+// dereferenceAll returns a copy of the inputs in which all references have been replaced by the referenced values.
+func dereferenceAll(inputs map[string]*typedvalues.TypedValue) (map[string]*typedvalues.TypedValue, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+	dereferenced := make(map[string]*typedvalues.TypedValue, len(inputs))
+	for k, tv := range inputs {
+		v, err := typedvalues.Dereference(tv)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dereference input '%s': %v", k, err)
+		}
+		dereferenced[k] = v
+	}
+	return dereferenced, nil
+}
+
 func getFirstDefined(inputs map[string]*typedvalues.TypedValue, keys ...string) *typedvalues.TypedValue {
 	for _, key := range keys {
 		if val, ok := inputs[key]; ok {
@@ -4,12 +4,14 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -44,6 +46,14 @@ func TestFormatRequest(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, body, string(bs))
 	assert.Equal(t, target.Header.Get(headerContentType), "text/plain")
+	assert.EqualValues(t, len(body), target.ContentLength)
+
+	// The body should be replayable for retries
+	rc, err := target.GetBody()
+	assert.NoError(t, err)
+	bs, err = ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, body, string(bs))
 
 	// Check headers
 	assert.Equal(t, headers["Header-Key"], target.Header["Header-Key"][0])
@@ -174,3 +184,166 @@ func createRequest(method string, rawURL string, headers map[string]string, body
 		Body:   body,
 	}
 }
+
+type staticDereferencer map[string]*typedvalues.TypedValue
+
+func (d staticDereferencer) Dereference(ref *typedvalues.Reference) (*typedvalues.TypedValue, error) {
+	return d[ref.GetUri()], nil
+}
+
+func TestFormatRequestReference(t *testing.T) {
+	typedvalues.RegisterDereferencer(staticDereferencer{
+		"test://body": typedvalues.MustWrap("some body input"),
+	})
+	defer typedvalues.RegisterDereferencer(nil)
+
+	reqURL, err := url.Parse("http://bar.example")
+	if err != nil {
+		panic(err)
+	}
+	target := &http.Request{
+		URL:    reqURL,
+		Header: http.Header{},
+	}
+	source := map[string]*typedvalues.TypedValue{
+		types.InputMain: typedvalues.MustWrap(&typedvalues.Reference{
+			Uri:       "test://body",
+			ValueType: typedvalues.TypeString,
+		}),
+	}
+
+	err = FormatRequest(source, target)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", target.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(target.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "some body input", string(body))
+}
+
+type memStreamStore map[string][]byte
+
+func (s memStreamStore) Create(r io.Reader, contentType string, size int64) (*typedvalues.Stream, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	uri := "test://stream"
+	s[uri] = data
+	return &typedvalues.Stream{Uri: uri, ContentType: contentType, Size: int64(len(data))}, nil
+}
+
+func (s memStreamStore) Open(stream *typedvalues.Stream) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(string(s[stream.GetUri()]))), nil
+}
+
+func TestParseResponseStream(t *testing.T) {
+	typedvalues.RegisterStreamStore(memStreamStore{})
+	defer typedvalues.RegisterStreamStore(nil)
+	mapper := DefaultHTTPMapper.Clone()
+	mapper.StreamThreshold = 4
+
+	// Bodies below the threshold are not streamed
+	output, err := mapper.ParseResponse(createBinaryResponse("foo", 3))
+	assert.NoError(t, err)
+	assert.Equal(t, typedvalues.TypeBytes, output.ValueType())
+
+	// Bodies above the threshold are streamed
+	output, err = mapper.ParseResponse(createBinaryResponse("foobar", 6))
+	assert.NoError(t, err)
+	assert.True(t, typedvalues.IsStream(output))
+
+	// Bodies of unknown size are streamed if they turn out to exceed the threshold
+	output, err = mapper.ParseResponse(createBinaryResponse("foo", -1))
+	assert.NoError(t, err)
+	assert.Equal(t, typedvalues.TypeBytes, output.ValueType())
+	output, err = mapper.ParseResponse(createBinaryResponse("foobar", -1))
+	assert.NoError(t, err)
+	assert.True(t, typedvalues.IsStream(output))
+	rc, err := typedvalues.OpenStream(output)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", string(data))
+}
+
+func TestParseResponseMaxMemorySize(t *testing.T) {
+	mapper := DefaultHTTPMapper.Clone()
+	mapper.MaxMemorySize = 8
+	createJSONResponse := func(size int64) *http.Response {
+		resp := createBinaryResponse(`{"foo":"bar"}`, size)
+		resp.Header.Set("Content-Type", "application/json")
+		return resp
+	}
+
+	// Without a stream store, bodies that exceed the max in-memory size cannot be parsed
+	_, err := mapper.ParseResponse(createJSONResponse(13))
+	assert.Equal(t, ErrBodyTooLarge, errors.Cause(err))
+	_, err = mapper.ParseResponse(createJSONResponse(-1))
+	assert.Equal(t, ErrBodyTooLarge, errors.Cause(err))
+
+	// With a stream store, they are streamed with their content type
+	typedvalues.RegisterStreamStore(memStreamStore{})
+	defer typedvalues.RegisterStreamStore(nil)
+	for _, size := range []int64{13, -1} {
+		output, err := mapper.ParseResponse(createJSONResponse(size))
+		assert.NoError(t, err)
+		stream, err := typedvalues.UnwrapStream(output)
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json", stream.GetContentType())
+		rc, err := typedvalues.OpenStream(output)
+		assert.NoError(t, err)
+		data, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"foo":"bar"}`, string(data))
+	}
+
+	// Bodies within the max in-memory size are parsed as usual
+	mapper.MaxMemorySize = 64
+	output, err := mapper.ParseResponse(createJSONResponse(-1))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, typedvalues.MustUnwrap(output))
+}
+
+func TestFormatStream(t *testing.T) {
+	typedvalues.RegisterStreamStore(memStreamStore{"test://stream": []byte("foobar")})
+	defer typedvalues.RegisterStreamStore(nil)
+	stream := typedvalues.MustWrap(&typedvalues.Stream{
+		Uri:         "test://stream",
+		ContentType: "image/png",
+		Size:        6,
+	})
+
+	reqURL, err := url.Parse("http://bar.example")
+	if err != nil {
+		panic(err)
+	}
+	target := &http.Request{
+		URL:    reqURL,
+		Header: http.Header{},
+	}
+	err = FormatRequest(map[string]*typedvalues.TypedValue{
+		types.InputMain: stream,
+	}, target)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", target.Header.Get("Content-Type"))
+	assert.EqualValues(t, 6, target.ContentLength)
+	body, err := ioutil.ReadAll(target.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", string(body))
+
+	w := httptest.NewRecorder()
+	FormatResponse(w, stream, nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "foobar", w.Body.String())
+}
+
+func createBinaryResponse(body string, size int64) *http.Response {
+	return &http.Response{
+		Header: http.Header{
+			"Content-Type": []string{"application/octet-stream"},
+		},
+		ContentLength: size,
+		Body:          ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
@@ -0,0 +1,96 @@
+package typedvalues
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+const (
+	KeyRefSecret    = "secret"
+	KeyRefConfigMap = "configmap"
+)
+
+// IsKeyRef checks if the TypedValue is a reference to a key of a secret or configmap.
+func IsKeyRef(tv *TypedValue) bool {
+	return tv.ValueType() == TypeKeyRef
+}
+
+// UnwrapKeyRef returns the KeyRef of the TypedValue, without resolving it.
+func UnwrapKeyRef(tv *TypedValue) (*KeyRef, error) {
+	ref := &KeyRef{}
+	err := ptypes.UnmarshalAny(tv.GetValue(), ref)
+	if err != nil {
+		return nil, errors.Wrapf(ErrIllegalTypeAssertion, "failed to unwrap %s to key reference", tv.ValueType())
+	}
+	return ref, nil
+}
+
+// ResolveKeyRefs returns a copy of the TypedValue in which the KeyRefs - including those nested in maps and arrays -
+// are replaced by the values that resolve returns for them. If the TypedValue does not contain any KeyRefs, it is
+// returned as is.
+func ResolveKeyRefs(tv *TypedValue, resolve func(ref *KeyRef) (*TypedValue, error)) (*TypedValue, error) {
+	switch tv.ValueType() {
+	case TypeKeyRef:
+		ref, err := UnwrapKeyRef(tv)
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := resolve(ref)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve key '%s' of %s '%s'", ref.Key, ref.Kind, ref.Name)
+		}
+		for k, v := range tv.GetMetadata() {
+			resolved.SetMetadata(k, v)
+		}
+		return resolved, nil
+	case TypeMap:
+		mp := &MapValue{}
+		if err := ptypes.UnmarshalAny(tv.GetValue(), mp); err != nil {
+			return nil, err
+		}
+		var changed bool
+		for k, v := range mp.Value {
+			resolved, err := ResolveKeyRefs(v, resolve)
+			if err != nil {
+				return nil, err
+			}
+			changed = changed || resolved != v
+			mp.Value[k] = resolved
+		}
+		if !changed {
+			return tv, nil
+		}
+		return wrapWithMetadata(mp, tv.GetMetadata())
+	case TypeList:
+		arr := &ArrayValue{}
+		if err := ptypes.UnmarshalAny(tv.GetValue(), arr); err != nil {
+			return nil, err
+		}
+		var changed bool
+		for i, v := range arr.Value {
+			resolved, err := ResolveKeyRefs(v, resolve)
+			if err != nil {
+				return nil, err
+			}
+			changed = changed || resolved != v
+			arr.Value[i] = resolved
+		}
+		if !changed {
+			return tv, nil
+		}
+		return wrapWithMetadata(arr, tv.GetMetadata())
+	default:
+		return tv, nil
+	}
+}
+
+func wrapWithMetadata(val interface{}, metadata map[string]string) (*TypedValue, error) {
+	tv, err := Wrap(val)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range metadata {
+		tv.SetMetadata(k, v)
+	}
+	return tv, nil
+}
@@ -0,0 +1,53 @@
+package typedvalues
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveKeyRefs(t *testing.T) {
+	token := MustWrap(&KeyRef{Kind: KeyRefSecret, Name: "my-api", Key: "token"})
+	assert.True(t, IsKeyRef(token))
+	tv := MustWrap(map[string]interface{}{
+		"headers": map[string]interface{}{
+			"Authorization": token,
+		},
+		"hosts": []interface{}{"a", MustWrap(&KeyRef{Kind: KeyRefConfigMap, Name: "config", Key: "host"})},
+		"body":  "foo",
+	})
+	tv.SetMetadata("foo", "bar")
+	original := MustUnwrap(tv)
+
+	resolved, err := ResolveKeyRefs(tv, func(ref *KeyRef) (*TypedValue, error) {
+		return Wrap(ref.Kind + "/" + ref.Name + "/" + ref.Key)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"headers": map[string]interface{}{
+			"Authorization": "secret/my-api/token",
+		},
+		"hosts": []interface{}{"a", "configmap/config/host"},
+		"body":  "foo",
+	}, MustUnwrap(resolved))
+	assert.Equal(t, "bar", resolved.GetMetadata()["foo"])
+
+	// The original value should still contain the references
+	assert.Equal(t, original, MustUnwrap(tv))
+}
+
+func TestResolveKeyRefsUnchanged(t *testing.T) {
+	tv := MustWrap(map[string]interface{}{"body": "foo"})
+	resolved, err := ResolveKeyRefs(tv, func(ref *KeyRef) (*TypedValue, error) {
+		return nil, errors.New("unexpected key reference")
+	})
+	assert.NoError(t, err)
+	assert.True(t, tv == resolved)
+
+	_, err = ResolveKeyRefs(MustWrap(&KeyRef{Kind: KeyRefSecret, Name: "my-api", Key: "token"}),
+		func(ref *KeyRef) (*TypedValue, error) {
+			return nil, errors.New("not found")
+		})
+	assert.EqualError(t, err, "failed to resolve key 'token' of secret 'my-api': not found")
+}
@@ -0,0 +1,68 @@
+package typedvalues
+
+import (
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoDereferencer = errors.New("no dereferencer registered to resolve the reference")
+
+	dereferencer   Dereferencer
+	dereferencerMu sync.RWMutex
+)
+
+// Dereferencer fetches the values that references point to.
+type Dereferencer interface {
+	Dereference(ref *Reference) (*TypedValue, error)
+}
+
+// RegisterDereferencer sets the dereferencer that is used to transparently resolve references.
+func RegisterDereferencer(d Dereferencer) {
+	dereferencerMu.Lock()
+	defer dereferencerMu.Unlock()
+	dereferencer = d
+}
+
+// IsReference checks if the TypedValue is a reference to a value stored elsewhere.
+func IsReference(tv *TypedValue) bool {
+	return tv.ValueType() == TypeReference
+}
+
+// UnwrapReference returns the reference of the TypedValue, without dereferencing it.
+func UnwrapReference(tv *TypedValue) (*Reference, error) {
+	ref := &Reference{}
+	err := ptypes.UnmarshalAny(tv.GetValue(), ref)
+	if err != nil {
+		return nil, errors.Wrapf(ErrIllegalTypeAssertion, "failed to unwrap %s to reference", tv.ValueType())
+	}
+	return ref, nil
+}
+
+// Dereference resolves the value that the TypedValue refers to using the registered dereferencer. The metadata of the
+// reference is preserved. If the TypedValue is not a reference, it is returned as is.
+func Dereference(tv *TypedValue) (*TypedValue, error) {
+	if !IsReference(tv) {
+		return tv, nil
+	}
+	ref, err := UnwrapReference(tv)
+	if err != nil {
+		return nil, err
+	}
+	dereferencerMu.RLock()
+	d := dereferencer
+	dereferencerMu.RUnlock()
+	if d == nil {
+		return nil, errors.Wrapf(ErrNoDereferencer, "failed to dereference %s", ref.GetUri())
+	}
+	referenced, err := d.Dereference(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dereference %s", ref.GetUri())
+	}
+	for k, v := range tv.GetMetadata() {
+		referenced.SetMetadata(k, v)
+	}
+	return referenced, nil
+}
@@ -0,0 +1,46 @@
+package typedvalues
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticDereferencer map[string]*TypedValue
+
+func (d staticDereferencer) Dereference(ref *Reference) (*TypedValue, error) {
+	return d[ref.GetUri()], nil
+}
+
+func TestDereference(t *testing.T) {
+	RegisterDereferencer(staticDereferencer{
+		"test://foo": MustWrap("foo"),
+	})
+	defer RegisterDereferencer(nil)
+
+	ref := MustWrap(&Reference{Uri: "test://foo", ValueType: TypeString})
+	ref.SetMetadata("key", "value")
+	assert.True(t, IsReference(ref))
+
+	tv, err := Dereference(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, TypeString, tv.ValueType())
+	assert.Equal(t, "value", tv.GetMetadata()["key"])
+
+	s, err := UnwrapString(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", s)
+}
+
+func TestDereferenceNonReference(t *testing.T) {
+	tv := MustWrap("foo")
+	assert.False(t, IsReference(tv))
+	result, err := Dereference(tv)
+	assert.NoError(t, err)
+	assert.Equal(t, tv, result)
+}
+
+func TestDereferenceWithoutDereferencer(t *testing.T) {
+	_, err := Unwrap(MustWrap(&Reference{Uri: "test://foo"}))
+	assert.Error(t, err)
+}
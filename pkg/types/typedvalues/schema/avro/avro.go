@@ -0,0 +1,98 @@
+// package avro contains a client for Avro schema registries that implement the Confluent Schema Registry API.
+package avro
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/schema"
+	"github.com/linkedin/goavro/v2"
+)
+
+const defaultTimeout = 10 * time.Second
+
+// RegistryClient resolves Avro schemas by their ID from a schema registry. Schemas are immutable, so resolved
+// schemas are cached indefinitely.
+type RegistryClient struct {
+	url    string
+	client *http.Client
+	cache  map[string]*Schema
+	mu     sync.RWMutex
+}
+
+// NewRegistryClient creates a client for the schema registry at the URL, e.g. http://schema-registry:8081.
+func NewRegistryClient(url string) *RegistryClient {
+	return &RegistryClient{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: defaultTimeout},
+		cache:  map[string]*Schema{},
+	}
+}
+
+func (c *RegistryClient) Resolve(id string) (schema.Schema, error) {
+	c.mu.RLock()
+	cached, ok := c.cache[id]
+	c.mu.RUnlock()
+	if ok {
+		return cached, nil
+	}
+
+	resp, err := c.client.Get(fmt.Sprintf("%s/schemas/ids/%s", c.url, id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("schema registry responded with status %d", resp.StatusCode)
+	}
+	body := struct {
+		Schema string `json:"schema"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode schema registry response: %v", err)
+	}
+	s, err := NewSchema(body.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.cache[id] = s
+	c.mu.Unlock()
+	return s, nil
+}
+
+// Schema validates values against an Avro schema.
+type Schema struct {
+	codec *goavro.Codec
+}
+
+// NewSchema parses the JSON representation of an Avro schema.
+func NewSchema(spec string) (*Schema, error) {
+	codec, err := goavro.NewCodec(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid avro schema: %v", err)
+	}
+	return &Schema{
+		codec: codec,
+	}, nil
+}
+
+// Validate checks that the JSON representation of the value is a valid Avro (JSON-encoded) datum of the schema.
+func (s *Schema) Validate(tv *typedvalues.TypedValue) error {
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	_, _, err = s.codec.NativeFromTextual(bs)
+	return err
+}
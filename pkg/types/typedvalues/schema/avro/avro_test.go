@@ -0,0 +1,57 @@
+package avro
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "int"}
+  ]
+}`
+
+func TestRegistryClient(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/schemas/ids/1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"schema": %q}`, userSchema)
+	}))
+	defer srv.Close()
+	client := NewRegistryClient(srv.URL + "/")
+
+	s, err := client.Resolve("1")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Validate(typedvalues.MustWrap(map[string]interface{}{
+		"name": "foo",
+		"age":  42,
+	})))
+	assert.Error(t, s.Validate(typedvalues.MustWrap(map[string]interface{}{
+		"name": "foo",
+	})))
+
+	// Resolved schemas are cached
+	_, err = client.Resolve("1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	_, err = client.Resolve("2")
+	assert.Error(t, err)
+}
+
+func TestNewSchemaInvalid(t *testing.T) {
+	_, err := NewSchema(`{"type": "unknown"}`)
+	assert.Error(t, err)
+}
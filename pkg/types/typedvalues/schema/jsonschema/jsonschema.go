@@ -0,0 +1,435 @@
+// package jsonschema validates values against JSON Schemas, such as the input schema of a workflow.
+//
+// It supports the subset of JSON Schema (draft-07) that describes the structure of data: type, enum, const,
+// properties, required, additionalProperties, items, the numeric bounds (minimum, maximum, exclusiveMinimum and
+// exclusiveMaximum), the length bounds (minLength, maxLength, minItems and maxItems) and pattern. Other keywords,
+// such as title, description and format, are ignored, as JSON Schema prescribes for unknown keywords.
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+var typeNames = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// Schema is a parsed JSON Schema.
+type Schema struct {
+	// never is set for the false schema, which no value conforms to.
+	never bool
+
+	Types                []string
+	Enum                 []interface{}
+	Const                interface{}
+	HasConst             bool
+	Properties           map[string]*Schema
+	Required             []string
+	AdditionalProperties *Schema
+	Items                *Schema
+	Minimum              *float64
+	Maximum              *float64
+	ExclusiveMinimum     *float64
+	ExclusiveMaximum     *float64
+	MinLength            *int
+	MaxLength            *int
+	MinItems             *int
+	MaxItems             *int
+	Pattern              *regexp.Regexp
+}
+
+// Parse parses the JSON representation of a JSON Schema.
+func Parse(data []byte) (*Schema, error) {
+	var def interface{}
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	s, err := parse(def, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	return s, nil
+}
+
+// MustParse parses the JSON Schema, and panics if it is invalid.
+func MustParse(data string) *Schema {
+	s, err := Parse([]byte(data))
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func parse(def interface{}, path string) (*Schema, error) {
+	if b, ok := def.(bool); ok {
+		return &Schema{never: !b}, nil
+	}
+	m, ok := def.(map[string]interface{})
+	if !ok {
+		return nil, fieldErrorf(path, "schema should be an object or a boolean")
+	}
+	s := &Schema{}
+
+	switch t := m["type"].(type) {
+	case nil:
+	case string:
+		s.Types = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fieldErrorf(joinField(path, "type"), "type should be a string")
+			}
+			s.Types = append(s.Types, name)
+		}
+	default:
+		return nil, fieldErrorf(joinField(path, "type"), "type should be a string or an array of strings")
+	}
+	for _, name := range s.Types {
+		if !typeNames[name] {
+			return nil, fieldErrorf(joinField(path, "type"), "unknown type '%s'", name)
+		}
+	}
+
+	if enum, ok := m["enum"]; ok {
+		values, ok := enum.([]interface{})
+		if !ok {
+			return nil, fieldErrorf(joinField(path, "enum"), "enum should be an array")
+		}
+		s.Enum = values
+	}
+	s.Const, s.HasConst = m["const"]
+
+	if properties, ok := m["properties"]; ok {
+		props, ok := properties.(map[string]interface{})
+		if !ok {
+			return nil, fieldErrorf(joinField(path, "properties"), "properties should be an object")
+		}
+		s.Properties = map[string]*Schema{}
+		for name, prop := range props {
+			ps, err := parse(prop, joinField(path, "properties."+name))
+			if err != nil {
+				return nil, err
+			}
+			s.Properties[name] = ps
+		}
+	}
+	if required, ok := m["required"]; ok {
+		names, ok := required.([]interface{})
+		if !ok {
+			return nil, fieldErrorf(joinField(path, "required"), "required should be an array of strings")
+		}
+		for _, item := range names {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fieldErrorf(joinField(path, "required"), "required should be an array of strings")
+			}
+			s.Required = append(s.Required, name)
+		}
+	}
+	if additional, ok := m["additionalProperties"]; ok {
+		as, err := parse(additional, joinField(path, "additionalProperties"))
+		if err != nil {
+			return nil, err
+		}
+		s.AdditionalProperties = as
+	}
+	if items, ok := m["items"]; ok {
+		is, err := parse(items, joinField(path, "items"))
+		if err != nil {
+			return nil, err
+		}
+		s.Items = is
+	}
+
+	var err error
+	for keyword, target := range map[string]**float64{
+		"minimum":          &s.Minimum,
+		"maximum":          &s.Maximum,
+		"exclusiveMinimum": &s.ExclusiveMinimum,
+		"exclusiveMaximum": &s.ExclusiveMaximum,
+	} {
+		if *target, err = parseNumber(m, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+	for keyword, target := range map[string]**int{
+		"minLength": &s.MinLength,
+		"maxLength": &s.MaxLength,
+		"minItems":  &s.MinItems,
+		"maxItems":  &s.MaxItems,
+	} {
+		if *target, err = parseCount(m, keyword, path); err != nil {
+			return nil, err
+		}
+	}
+	if pattern, ok := m["pattern"]; ok {
+		expr, ok := pattern.(string)
+		if !ok {
+			return nil, fieldErrorf(joinField(path, "pattern"), "pattern should be a string")
+		}
+		if s.Pattern, err = regexp.Compile(expr); err != nil {
+			return nil, fieldErrorf(joinField(path, "pattern"), "invalid pattern: %v", err)
+		}
+	}
+	return s, nil
+}
+
+func parseNumber(m map[string]interface{}, keyword string, path string) (*float64, error) {
+	v, ok := m[keyword]
+	if !ok {
+		return nil, nil
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return nil, fieldErrorf(joinField(path, keyword), "%s should be a number", keyword)
+	}
+	return &n, nil
+}
+
+func parseCount(m map[string]interface{}, keyword string, path string) (*int, error) {
+	n, err := parseNumber(m, keyword, path)
+	if err != nil || n == nil {
+		return nil, err
+	}
+	if *n < 0 || *n != math.Trunc(*n) {
+		return nil, fieldErrorf(joinField(path, keyword), "%s should be a non-negative integer", keyword)
+	}
+	count := int(*n)
+	return &count, nil
+}
+
+// FieldError is a violation of the schema by a value, or by one of its (nested) fields.
+type FieldError struct {
+	// Field is the dot-separated path to the field, for example "user.tags.0", or empty for the value itself.
+	Field string
+
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if len(e.Field) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+func fieldErrorf(field string, format string, args ...interface{}) FieldError {
+	return FieldError{
+		Field:   field,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// Errors contains the violations of the schema by a value.
+type Errors []FieldError
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate validates the unwrapped value against the schema. It implements schema.Schema.
+func (s *Schema) Validate(tv *typedvalues.TypedValue) error {
+	v, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return err
+	}
+	if errs := s.ValidateValue(v); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidateValue validates the value, which should be JSON-like data, against the schema. It returns a FieldError for
+// each violation of the schema, ordered by the path of the field.
+func (s *Schema) ValidateValue(v interface{}) Errors {
+	normalized, err := normalize(v)
+	if err != nil {
+		return Errors{fieldErrorf("", "value cannot be represented as JSON: %v", err)}
+	}
+	var errs Errors
+	s.validate(normalized, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return errs[i].Field < errs[j].Field
+	})
+	return errs
+}
+
+// normalize converts the value to the types of encoding/json, so that, for example, all numbers are float64.
+func normalize(v interface{}) (interface{}, error) {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	err = json.Unmarshal(bs, &normalized)
+	return normalized, err
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *Errors) {
+	if s.never {
+		*errs = append(*errs, fieldErrorf(path, "value is not allowed"))
+		return
+	}
+	if len(s.Types) > 0 && !s.matchesType(v) {
+		*errs = append(*errs, fieldErrorf(path, "expected %s, but got %s", strings.Join(s.Types, " or "),
+			typeOf(v)))
+		return
+	}
+	if s.Enum != nil && !containsValue(s.Enum, v) {
+		*errs = append(*errs, fieldErrorf(path, "value should be one of %s", formatValues(s.Enum)))
+	}
+	if s.HasConst && !reflect.DeepEqual(s.Const, v) {
+		*errs = append(*errs, fieldErrorf(path, "value should be %s", formatValues([]interface{}{s.Const})))
+	}
+
+	switch val := v.(type) {
+	case float64:
+		s.validateNumber(val, path, errs)
+	case string:
+		s.validateString(val, path, errs)
+	case []interface{}:
+		s.validateArray(val, path, errs)
+	case map[string]interface{}:
+		s.validateObject(val, path, errs)
+	}
+}
+
+func (s *Schema) validateNumber(n float64, path string, errs *Errors) {
+	if s.Minimum != nil && n < *s.Minimum {
+		*errs = append(*errs, fieldErrorf(path, "value should be at least %v", *s.Minimum))
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		*errs = append(*errs, fieldErrorf(path, "value should be at most %v", *s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+		*errs = append(*errs, fieldErrorf(path, "value should be greater than %v", *s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+		*errs = append(*errs, fieldErrorf(path, "value should be less than %v", *s.ExclusiveMaximum))
+	}
+}
+
+func (s *Schema) validateString(str string, path string, errs *Errors) {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		*errs = append(*errs, fieldErrorf(path, "value should have at least %d characters", *s.MinLength))
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		*errs = append(*errs, fieldErrorf(path, "value should have at most %d characters", *s.MaxLength))
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(str) {
+		*errs = append(*errs, fieldErrorf(path, "value should match the pattern '%s'", s.Pattern))
+	}
+}
+
+func (s *Schema) validateArray(items []interface{}, path string, errs *Errors) {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		*errs = append(*errs, fieldErrorf(path, "value should have at least %d items", *s.MinItems))
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		*errs = append(*errs, fieldErrorf(path, "value should have at most %d items", *s.MaxItems))
+	}
+	if s.Items != nil {
+		for i, item := range items {
+			s.Items.validate(item, joinField(path, strconv.Itoa(i)), errs)
+		}
+	}
+}
+
+func (s *Schema) validateObject(obj map[string]interface{}, path string, errs *Errors) {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			*errs = append(*errs, fieldErrorf(joinField(path, name), "field is required"))
+		}
+	}
+	for name, field := range obj {
+		if prop, ok := s.Properties[name]; ok {
+			prop.validate(field, joinField(path, name), errs)
+		} else if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.never {
+				*errs = append(*errs, fieldErrorf(joinField(path, name), "field is not allowed"))
+				continue
+			}
+			s.AdditionalProperties.validate(field, joinField(path, name), errs)
+		}
+	}
+}
+
+func (s *Schema) matchesType(v interface{}) bool {
+	actual := typeOf(v)
+	for _, expected := range s.Types {
+		if expected == actual || (expected == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of the normalized value; numbers without a fractional part are integers.
+func typeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func containsValue(values []interface{}, v interface{}) bool {
+	for _, value := range values {
+		if reflect.DeepEqual(value, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValues(values []interface{}) string {
+	formatted := make([]string, len(values))
+	for i, value := range values {
+		bs, _ := json.Marshal(value)
+		formatted[i] = string(bs)
+	}
+	return strings.Join(formatted, ", ")
+}
+
+func joinField(prefix string, field string) string {
+	if len(prefix) == 0 {
+		return field
+	}
+	return prefix + "." + field
+}
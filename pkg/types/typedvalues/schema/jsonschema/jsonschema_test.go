@@ -0,0 +1,86 @@
+package jsonschema
+
+import (
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["name", "age"],
+	"additionalProperties": false,
+	"properties": {
+		"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+		"age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+		"role": {"enum": ["admin", "user"]},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"nickname": {"type": ["string", "null"]}
+	}
+}`
+
+func TestParse(t *testing.T) {
+	for name, def := range map[string]string{
+		"malformed":       `{`,
+		"not a schema":    `42`,
+		"unknown type":    `{"type": "text"}`,
+		"invalid pattern": `{"pattern": "("}`,
+		"invalid bound":   `{"minLength": -1}`,
+		"invalid nested":  `{"properties": {"a": {"type": 1}}}`,
+	} {
+		_, err := Parse([]byte(def))
+		assert.Error(t, err, name)
+	}
+
+	_, err := Parse([]byte(userSchema))
+	assert.NoError(t, err)
+}
+
+func TestSchema_ValidateValue(t *testing.T) {
+	s := MustParse(userSchema)
+
+	assert.Empty(t, s.ValidateValue(map[string]interface{}{
+		"name":     "alice",
+		"age":      30,
+		"role":     "admin",
+		"tags":     []string{"a", "b"},
+		"nickname": nil,
+	}))
+
+	errs := s.ValidateValue(map[string]interface{}{
+		"name":  "Alice",
+		"age":   30.5,
+		"role":  "root",
+		"tags":  []interface{}{"a", 2, "c"},
+		"email": "alice@example.com",
+	})
+	assert.Equal(t, Errors{
+		{Field: "age", Message: "expected integer, but got number"},
+		{Field: "email", Message: "field is not allowed"},
+		{Field: "name", Message: "value should match the pattern '^[a-z]+$'"},
+		{Field: "role", Message: `value should be one of "admin", "user"`},
+		{Field: "tags", Message: "value should have at most 2 items"},
+		{Field: "tags.1", Message: "expected string, but got integer"},
+	}, errs)
+
+	errs = s.ValidateValue(map[string]interface{}{
+		"age": 150,
+	})
+	assert.Equal(t, Errors{
+		{Field: "age", Message: "value should be less than 150"},
+		{Field: "name", Message: "field is required"},
+	}, errs)
+
+	errs = s.ValidateValue("alice")
+	assert.Equal(t, Errors{
+		{Message: "expected object, but got string"},
+	}, errs)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	s := MustParse(`{"type": "number", "minimum": 1}`)
+	assert.NoError(t, s.Validate(typedvalues.MustWrap(int32(1))))
+	err := s.Validate(typedvalues.MustWrap(0.5))
+	assert.EqualError(t, err, "value should be at least 1")
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// ProtobufRegistry resolves Protobuf schemas using the message types that are compiled into the workflow engine. The
+// identifier of a schema is the fully-qualified name of the message, such as google.protobuf.Timestamp.
+type ProtobufRegistry struct{}
+
+func (r *ProtobufRegistry) Resolve(id string) (Schema, error) {
+	msgType := proto.MessageType(id)
+	if msgType == nil {
+		return nil, fmt.Errorf("unknown protobuf message '%s'", id)
+	}
+	return &protobufSchema{
+		name:    id,
+		msgType: msgType,
+	}, nil
+}
+
+type protobufSchema struct {
+	name    string
+	msgType reflect.Type
+}
+
+// Validate checks that the value either is the message, or is a JSON-like value that represents the message.
+func (s *protobufSchema) Validate(tv *typedvalues.TypedValue) error {
+	if tv.ValueType() == s.name {
+		return nil
+	}
+	i, err := typedvalues.Unwrap(tv)
+	if err != nil {
+		return err
+	}
+	bs, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	msg := reflect.New(s.msgType.Elem()).Interface().(proto.Message)
+	unmarshaler := &jsonpb.Unmarshaler{}
+	if err := unmarshaler.Unmarshal(bytes.NewReader(bs), msg); err != nil {
+		return fmt.Errorf("invalid %s: %v", s.name, err)
+	}
+	return nil
+}
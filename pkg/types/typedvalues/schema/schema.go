@@ -0,0 +1,139 @@
+// package schema provides validation of TypedValues against schemas, such as Protobuf messages and Avro schemas.
+//
+// By default, TypedValues are treated as loose JSON-like data. Strongly typed pipelines can tag values with a schema
+// reference - either by the task that declares the schemas of its inputs and output, or by adding the reference to the
+// metadata of the value itself. The schema is resolved using the Registry of the kind of the reference, which allows
+// external schema registries to be plugged in.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+)
+
+const (
+	// MetadataKey is the key of the metadata of a TypedValue that contains its schema reference.
+	MetadataKey = "schema"
+
+	KindProtobuf = "protobuf"
+	KindAvro     = "avro"
+)
+
+var (
+	ErrInvalidRef = errors.New("invalid schema reference (expected <kind>:<id>)")
+
+	// DefaultValidator is the validator that is used to validate task inputs and outputs. By default, it is only
+	// able to resolve Protobuf schemas.
+	DefaultValidator = NewValidator()
+)
+
+// Ref is a reference to a schema, consisting of the kind of schema and the identifier of the schema within that
+// kind. For example, protobuf:google.protobuf.Timestamp or avro:42.
+type Ref struct {
+	Kind string
+	ID   string
+}
+
+// ParseRef parses a schema reference of the form <kind>:<id>.
+func ParseRef(s string) (Ref, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return Ref{}, ErrInvalidRef
+	}
+	return Ref{
+		Kind: parts[0],
+		ID:   parts[1],
+	}, nil
+}
+
+func (r Ref) String() string {
+	return r.Kind + ":" + r.ID
+}
+
+// Schema validates values.
+type Schema interface {
+	Validate(tv *typedvalues.TypedValue) error
+}
+
+// Registry resolves the schemas of a specific kind.
+type Registry interface {
+	Resolve(id string) (Schema, error)
+}
+
+// ValidationError is returned when a value does not conform to its schema.
+type ValidationError struct {
+	Ref Ref
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("value does not conform to schema %s: %v", e.Ref, e.Err)
+}
+
+// Validator validates values using the registries of the kinds of schemas.
+type Validator struct {
+	registries map[string]Registry
+	mu         sync.RWMutex
+}
+
+// NewValidator creates a validator with the built-in Protobuf registry.
+func NewValidator() *Validator {
+	return &Validator{
+		registries: map[string]Registry{
+			KindProtobuf: &ProtobufRegistry{},
+		},
+	}
+}
+
+// Register adds (or replaces) the registry for a kind of schemas.
+func (v *Validator) Register(kind string, registry Registry) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.registries[kind] = registry
+}
+
+// Validate validates the value against the referenced schema.
+func (v *Validator) Validate(ref string, tv *typedvalues.TypedValue) error {
+	r, err := ParseRef(ref)
+	if err != nil {
+		return err
+	}
+	v.mu.RLock()
+	registry, ok := v.registries[r.Kind]
+	v.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no schema registry configured for kind '%s'", r.Kind)
+	}
+	s, err := registry.Resolve(r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve schema %s: %v", r, err)
+	}
+	if err := s.Validate(tv); err != nil {
+		return &ValidationError{Ref: r, Err: err}
+	}
+	return nil
+}
+
+// Tag adds the schema reference to the metadata of the value.
+func Tag(tv *typedvalues.TypedValue, ref string) *typedvalues.TypedValue {
+	return tv.SetMetadata(MetadataKey, ref)
+}
+
+// RefOf returns the schema reference in the metadata of the value, or an empty string if the value has no schema.
+func RefOf(tv *typedvalues.TypedValue) string {
+	ref, _ := tv.GetMetadataValue(MetadataKey)
+	return ref
+}
+
+// ValidateTagged validates the value against the schema in its metadata. Values without a schema are always valid.
+func ValidateTagged(tv *typedvalues.TypedValue) error {
+	ref := RefOf(tv)
+	if len(ref) == 0 {
+		return nil
+	}
+	return DefaultValidator.Validate(ref, tv)
+}
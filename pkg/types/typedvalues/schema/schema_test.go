@@ -0,0 +1,82 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRef(t *testing.T) {
+	ref, err := ParseRef("avro:42")
+	assert.NoError(t, err)
+	assert.Equal(t, Ref{Kind: KindAvro, ID: "42"}, ref)
+	assert.Equal(t, "avro:42", ref.String())
+
+	for _, invalid := range []string{"", "avro", "avro:", ":42"} {
+		_, err := ParseRef(invalid)
+		assert.Equal(t, ErrInvalidRef, err, invalid)
+	}
+}
+
+func TestValidateProtobuf(t *testing.T) {
+	v := NewValidator()
+	ref := "protobuf:google.protobuf.Timestamp"
+
+	assert.NoError(t, v.Validate(ref, typedvalues.MustWrap(ptypes.TimestampNow())))
+	assert.NoError(t, v.Validate(ref, typedvalues.MustWrap("2018-01-01T00:00:00Z")))
+
+	err := v.Validate(ref, typedvalues.MustWrap(map[string]interface{}{
+		"foo": "bar",
+	}))
+	assert.IsType(t, &ValidationError{}, err)
+
+	err = v.Validate("protobuf:does.not.Exist", typedvalues.MustWrap("foo"))
+	assert.Error(t, err)
+}
+
+type staticSchema struct {
+	err error
+}
+
+func (s staticSchema) Validate(tv *typedvalues.TypedValue) error {
+	return s.err
+}
+
+type staticRegistry map[string]Schema
+
+func (r staticRegistry) Resolve(id string) (Schema, error) {
+	s, ok := r[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return s, nil
+}
+
+func TestValidatorRegister(t *testing.T) {
+	v := NewValidator()
+	tv := typedvalues.MustWrap("foo")
+	assert.Error(t, v.Validate("custom:valid", tv))
+
+	v.Register("custom", staticRegistry{
+		"valid":   staticSchema{},
+		"invalid": staticSchema{err: errors.New("invalid")},
+	})
+	assert.NoError(t, v.Validate("custom:valid", tv))
+	assert.IsType(t, &ValidationError{}, v.Validate("custom:invalid", tv))
+	assert.Error(t, v.Validate("custom:unknown", tv))
+}
+
+func TestValidateTagged(t *testing.T) {
+	tv := typedvalues.MustWrap("2018-01-01T00:00:00Z")
+	assert.NoError(t, ValidateTagged(tv))
+
+	Tag(tv, "protobuf:google.protobuf.Timestamp")
+	assert.Equal(t, "protobuf:google.protobuf.Timestamp", RefOf(tv))
+	assert.NoError(t, ValidateTagged(tv))
+
+	Tag(tv, "protobuf:google.protobuf.Duration")
+	assert.Error(t, ValidateTagged(tv))
+}
@@ -0,0 +1,114 @@
+package typedvalues
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrNoStreamStore = errors.New("no stream store registered to store or open streams")
+
+	streamStore   StreamStore
+	streamStoreMu sync.RWMutex
+)
+
+// StreamStore stores the data of streams, without materializing the data in memory.
+type StreamStore interface {
+	// Create stores the data of the reader as a new stream. The size is the number of bytes that will be read from
+	// the reader, or -1 if unknown.
+	Create(r io.Reader, contentType string, size int64) (*Stream, error)
+
+	// Open returns a reader of the data of the stream. The caller is responsible for closing the reader.
+	Open(stream *Stream) (io.ReadCloser, error)
+}
+
+// RegisterStreamStore sets the store that is used to create and open streams.
+func RegisterStreamStore(s StreamStore) {
+	streamStoreMu.Lock()
+	defer streamStoreMu.Unlock()
+	streamStore = s
+}
+
+func getStreamStore() StreamStore {
+	streamStoreMu.RLock()
+	defer streamStoreMu.RUnlock()
+	return streamStore
+}
+
+// StreamingEnabled returns whether a stream store has been registered.
+func StreamingEnabled() bool {
+	return getStreamStore() != nil
+}
+
+// IsStream checks if the TypedValue is a stream.
+func IsStream(tv *TypedValue) bool {
+	return tv.ValueType() == TypeStream
+}
+
+// UnwrapStream returns the stream of the TypedValue, without opening it.
+func UnwrapStream(tv *TypedValue) (*Stream, error) {
+	stream := &Stream{}
+	err := ptypes.UnmarshalAny(tv.GetValue(), stream)
+	if err != nil {
+		return nil, errors.Wrapf(ErrIllegalTypeAssertion, "failed to unwrap %s to stream", tv.ValueType())
+	}
+	return stream, nil
+}
+
+// NewStream stores the data of the reader in the registered stream store, and returns a TypedValue of the stream.
+func NewStream(r io.Reader, contentType string, size int64) (*TypedValue, error) {
+	store := getStreamStore()
+	if store == nil {
+		return nil, ErrNoStreamStore
+	}
+	stream, err := store.Create(r, contentType, size)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create stream")
+	}
+	return Wrap(stream)
+}
+
+// OpenStream returns a reader of the binary data of the TypedValue.
+//
+// For a stream, the data is read in chunks from the registered stream store. For other values, the reader is
+// backed by the value in memory; bytes and strings are read as is, and references are dereferenced first.
+func OpenStream(tv *TypedValue) (io.ReadCloser, error) {
+	if !IsStream(tv) {
+		dereferenced, err := Dereference(tv)
+		if err != nil {
+			return nil, err
+		}
+		i, err := Unwrap(dereferenced)
+		if err != nil {
+			return nil, err
+		}
+		switch t := i.(type) {
+		case []byte:
+			return ioutil.NopCloser(bytes.NewReader(t)), nil
+		case string:
+			return ioutil.NopCloser(strings.NewReader(t)), nil
+		default:
+			return nil, errors.Wrapf(ErrIllegalTypeAssertion, "cannot stream %s", tv.ValueType())
+		}
+	}
+
+	stream, err := UnwrapStream(tv)
+	if err != nil {
+		return nil, err
+	}
+	store := getStreamStore()
+	if store == nil {
+		return nil, errors.Wrapf(ErrNoStreamStore, "failed to open %s", stream.GetUri())
+	}
+	rc, err := store.Open(stream)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %s", stream.GetUri())
+	}
+	return rc, nil
+}
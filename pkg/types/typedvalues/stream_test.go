@@ -0,0 +1,66 @@
+package typedvalues
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memStreamStore map[string][]byte
+
+func (s memStreamStore) Create(r io.Reader, contentType string, size int64) (*Stream, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	uri := "test://" + string(rune('a'+len(s)))
+	s[uri] = data
+	return &Stream{Uri: uri, ContentType: contentType, Size: int64(len(data))}, nil
+}
+
+func (s memStreamStore) Open(stream *Stream) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(s[stream.GetUri()])), nil
+}
+
+func TestStream(t *testing.T) {
+	RegisterStreamStore(memStreamStore{})
+	defer RegisterStreamStore(nil)
+	assert.True(t, StreamingEnabled())
+
+	tv, err := NewStream(bytes.NewReader([]byte("foobar")), "application/octet-stream", -1)
+	assert.NoError(t, err)
+	assert.True(t, IsStream(tv))
+
+	stream, err := UnwrapStream(tv)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 6, stream.GetSize())
+	assert.Equal(t, "application/octet-stream", stream.GetContentType())
+
+	rc, err := OpenStream(tv)
+	assert.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", string(data))
+}
+
+func TestOpenStreamInMemory(t *testing.T) {
+	for _, v := range []interface{}{[]byte("foobar"), "foobar"} {
+		rc, err := OpenStream(MustWrap(v))
+		assert.NoError(t, err)
+		data, err := ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, "foobar", string(data))
+	}
+
+	_, err := OpenStream(MustWrap(42))
+	assert.Error(t, err)
+}
+
+func TestStreamWithoutStreamStore(t *testing.T) {
+	assert.False(t, StreamingEnabled())
+	_, err := NewStream(bytes.NewReader([]byte("foobar")), "", -1)
+	assert.Equal(t, ErrNoStreamStore, err)
+}
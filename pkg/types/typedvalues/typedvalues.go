@@ -16,6 +16,7 @@ package typedvalues
 
 import (
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -167,6 +168,12 @@ func Unwrap(tv *TypedValue) (interface{}, error) {
 		i = t.Value
 	case *NilValue:
 		i = nil
+	case *Reference:
+		referenced, err := Dereference(tv)
+		if err != nil {
+			return nil, err
+		}
+		return Unwrap(referenced)
 	default:
 		// Message does not have to be unwrapped(?)
 		i = t
@@ -233,6 +240,15 @@ func Wrap(val interface{}) (*TypedValue, error) {
 	case nil:
 		msg = &NilValue{}
 	default:
+		// Typed slices, such as the []string that the JavaScript interpreter exports for a list of strings, are
+		// wrapped as lists.
+		if rv := reflect.ValueOf(t); rv.Kind() == reflect.Slice {
+			values := make([]interface{}, rv.Len())
+			for i := range values {
+				values[i] = rv.Index(i).Interface()
+			}
+			return Wrap(values)
+		}
 		return nil, errors.Wrapf(ErrUnsupportedType, "parse %T", t)
 	}
 	marshaled, err := marshalAny(msg)
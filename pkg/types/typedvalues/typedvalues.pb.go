@@ -13,6 +13,9 @@ It has these top-level messages:
 	MapValue
 	ArrayValue
 	NilValue
+	Reference
+	Stream
+	KeyRef
 */
 package typedvalues
 
@@ -118,35 +121,161 @@ func (m *NilValue) String() string            { return proto.CompactTextString(m
 func (*NilValue) ProtoMessage()               {}
 func (*NilValue) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
+// Reference points to a value that is stored outside of the TypedValue, such as in a blob store.
+//
+// It is used to avoid storing large values in the event store and caches. Users should not have to deal with
+// references directly; the value is dereferenced transparently when it is unwrapped.
+type Reference struct {
+	// Uri is the location of the serialized TypedValue.
+	Uri string `protobuf:"bytes,1,opt,name=uri" json:"uri,omitempty"`
+	// ValueType is the type of the referenced value.
+	ValueType string `protobuf:"bytes,2,opt,name=valueType" json:"valueType,omitempty"`
+	// Size is the size (in bytes) of the serialized TypedValue.
+	Size int64 `protobuf:"varint,3,opt,name=size" json:"size,omitempty"`
+}
+
+func (m *Reference) Reset()                    { *m = Reference{} }
+func (m *Reference) String() string            { return proto.CompactTextString(m) }
+func (*Reference) ProtoMessage()               {}
+func (*Reference) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *Reference) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+func (m *Reference) GetValueType() string {
+	if m != nil {
+		return m.ValueType
+	}
+	return ""
+}
+
+func (m *Reference) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+// Stream points to binary data that is too large to be materialized in memory, such as a large file.
+//
+// Unlike a Reference, a stream is not dereferenced when it is unwrapped. Instead, the data is streamed (in chunks) to
+// the consumer, such as the body of an HTTP request to a function.
+type Stream struct {
+	// Uri is the location of the data.
+	Uri string `protobuf:"bytes,1,opt,name=uri" json:"uri,omitempty"`
+	// ContentType is the media type of the data, if known.
+	ContentType string `protobuf:"bytes,2,opt,name=contentType" json:"contentType,omitempty"`
+	// Size is the size (in bytes) of the data.
+	Size int64 `protobuf:"varint,3,opt,name=size" json:"size,omitempty"`
+}
+
+func (m *Stream) Reset()                    { *m = Stream{} }
+func (m *Stream) String() string            { return proto.CompactTextString(m) }
+func (*Stream) ProtoMessage()               {}
+func (*Stream) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *Stream) GetUri() string {
+	if m != nil {
+		return m.Uri
+	}
+	return ""
+}
+
+func (m *Stream) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Stream) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+// KeyRef references a key of a Kubernetes secret or configmap, such as an API token.
+//
+// Unlike a Reference, a KeyRef is not dereferenced when it is unwrapped. Instead, it is resolved by the function
+// environment right before the function is invoked, so that the value does not end up in the event store.
+type KeyRef struct {
+	// Kind is the kind of the referenced resource: secret or configmap.
+	Kind string `protobuf:"bytes,1,opt,name=kind" json:"kind,omitempty"`
+	// Name is the name of the secret or configmap.
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// Key is the key of the value in the secret or configmap.
+	Key string `protobuf:"bytes,3,opt,name=key" json:"key,omitempty"`
+}
+
+func (m *KeyRef) Reset()                    { *m = KeyRef{} }
+func (m *KeyRef) String() string            { return proto.CompactTextString(m) }
+func (*KeyRef) ProtoMessage()               {}
+func (*KeyRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *KeyRef) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *KeyRef) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *KeyRef) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*TypedValue)(nil), "fission.workflows.types.TypedValue")
 	proto.RegisterType((*Expression)(nil), "fission.workflows.types.Expression")
 	proto.RegisterType((*MapValue)(nil), "fission.workflows.types.MapValue")
 	proto.RegisterType((*ArrayValue)(nil), "fission.workflows.types.ArrayValue")
 	proto.RegisterType((*NilValue)(nil), "fission.workflows.types.NilValue")
+	proto.RegisterType((*Reference)(nil), "fission.workflows.types.Reference")
+	proto.RegisterType((*Stream)(nil), "fission.workflows.types.Stream")
+	proto.RegisterType((*KeyRef)(nil), "fission.workflows.types.KeyRef")
 }
 
 func init() { proto.RegisterFile("pkg/types/typedvalues/typedvalues.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 302 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x2f, 0xc8, 0x4e, 0xd7,
-	0x2f, 0xa9, 0x2c, 0x48, 0x2d, 0x06, 0x93, 0x29, 0x65, 0x89, 0x39, 0xa5, 0xa8, 0x6c, 0xbd, 0x82,
-	0xa2, 0xfc, 0x92, 0x7c, 0x21, 0xf1, 0xb4, 0xcc, 0xe2, 0xe2, 0xcc, 0xfc, 0x3c, 0xbd, 0xf2, 0xfc,
-	0xa2, 0xec, 0xb4, 0x9c, 0xfc, 0xf2, 0x62, 0x3d, 0xb0, 0x36, 0x29, 0xc9, 0xf4, 0xfc, 0xfc, 0xf4,
-	0x9c, 0x54, 0x7d, 0xb0, 0xb2, 0xa4, 0xd2, 0x34, 0xfd, 0xc4, 0xbc, 0x4a, 0x88, 0x1e, 0xa5, 0x23,
-	0x8c, 0x5c, 0x5c, 0x21, 0x20, 0x93, 0xc2, 0x40, 0x26, 0x09, 0x69, 0x71, 0xb1, 0x82, 0x8d, 0x94,
-	0x60, 0x54, 0x60, 0xd4, 0xe0, 0x36, 0x12, 0xd1, 0x83, 0xe8, 0xd4, 0x83, 0xe9, 0xd4, 0x73, 0xcc,
-	0xab, 0x0c, 0x82, 0x28, 0x11, 0xf2, 0xe5, 0xe2, 0xc8, 0x4d, 0x2d, 0x49, 0x4c, 0x49, 0x2c, 0x49,
-	0x94, 0x60, 0x56, 0x60, 0xd6, 0xe0, 0x36, 0x32, 0xd4, 0xc3, 0xe1, 0x02, 0x3d, 0x84, 0x15, 0x7a,
-	0xbe, 0x50, 0x3d, 0xae, 0x79, 0x25, 0x45, 0x95, 0x41, 0x70, 0x23, 0xa4, 0xac, 0xb9, 0x78, 0x51,
-	0xa4, 0x84, 0x04, 0xb8, 0x98, 0xb3, 0x53, 0x2b, 0xc1, 0x2e, 0xe1, 0x0c, 0x02, 0x31, 0x85, 0x44,
-	0x60, 0xae, 0x63, 0x02, 0x8b, 0x41, 0x38, 0x56, 0x4c, 0x16, 0x8c, 0x4a, 0x4a, 0x5c, 0x5c, 0xae,
-	0x15, 0x05, 0x45, 0xa9, 0x60, 0xdb, 0x11, 0xea, 0x18, 0x91, 0xd4, 0x29, 0xad, 0x65, 0xe4, 0xe2,
-	0xf0, 0x4d, 0x2c, 0x80, 0x78, 0xd4, 0x09, 0xa1, 0x04, 0xe4, 0x72, 0x1d, 0x9c, 0x2e, 0x87, 0xe9,
-	0xd0, 0x03, 0x93, 0x10, 0x47, 0x43, 0xb4, 0x4a, 0xc5, 0x72, 0x71, 0x21, 0x04, 0xb1, 0x38, 0xd7,
-	0x12, 0xd9, 0xb9, 0xdc, 0x46, 0xca, 0x44, 0x84, 0x0e, 0xb2, 0x9f, 0xdc, 0xb9, 0xb8, 0x1c, 0x8b,
-	0x8a, 0x12, 0x2b, 0x21, 0x0e, 0xb6, 0x44, 0x75, 0x30, 0x09, 0x86, 0x29, 0x71, 0x71, 0x71, 0xf8,
-	0x65, 0xe6, 0x80, 0x85, 0x9c, 0x78, 0xa3, 0xb8, 0x91, 0x12, 0x4e, 0x12, 0x1b, 0x38, 0x62, 0x8d,
-	0x01, 0x01, 0x00, 0x00, 0xff, 0xff, 0x80, 0xbc, 0x9e, 0x1f, 0x64, 0x02, 0x00, 0x00,
+	// 396 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0x51, 0x4b, 0xeb, 0x30,
+	0x14, 0xc7, 0xe9, 0x7a, 0xef, 0xd8, 0x4e, 0x19, 0x5c, 0xc2, 0xe0, 0xee, 0x8e, 0xfb, 0x50, 0xea,
+	0x83, 0x43, 0x24, 0xc3, 0xf9, 0xe2, 0xf4, 0x69, 0x83, 0xe1, 0x83, 0x4c, 0xa5, 0x8a, 0x0f, 0x82,
+	0x0f, 0xd9, 0x96, 0x8e, 0xd2, 0x2e, 0x29, 0x69, 0xea, 0xac, 0xdf, 0xc9, 0x6f, 0xe1, 0x07, 0x93,
+	0x24, 0xeb, 0xda, 0xc1, 0x06, 0xfa, 0x12, 0x4e, 0xff, 0x39, 0xff, 0x7f, 0x7e, 0x39, 0x34, 0x70,
+	0x9c, 0x44, 0xcb, 0xbe, 0xcc, 0x13, 0x9a, 0xea, 0x75, 0xf1, 0x4a, 0xe2, 0x6c, 0xb7, 0xc6, 0x89,
+	0xe0, 0x92, 0xa3, 0xbf, 0x41, 0x98, 0xa6, 0x21, 0x67, 0x78, 0xcd, 0x45, 0x14, 0xc4, 0x7c, 0x9d,
+	0x62, 0x6d, 0xeb, 0xfe, 0x5b, 0x72, 0xbe, 0x8c, 0x69, 0x5f, 0xb7, 0xcd, 0xb2, 0xa0, 0x4f, 0x58,
+	0x6e, 0x3c, 0xde, 0xa7, 0x05, 0xf0, 0xa8, 0x92, 0x9e, 0x54, 0x12, 0x3a, 0x81, 0xdf, 0x3a, 0xb2,
+	0x63, 0xb9, 0x56, 0xcf, 0x19, 0xb4, 0xb1, 0x71, 0xe2, 0xc2, 0x89, 0x47, 0x2c, 0xf7, 0x4d, 0x0b,
+	0x9a, 0x42, 0x63, 0x45, 0x25, 0x59, 0x10, 0x49, 0x3a, 0xb6, 0x6b, 0xf7, 0x9c, 0xc1, 0x19, 0x3e,
+	0x40, 0x80, 0xcb, 0x23, 0xf0, 0x74, 0xe3, 0x99, 0x30, 0x29, 0x72, 0x7f, 0x1b, 0xd1, 0xbd, 0x82,
+	0xd6, 0xce, 0x16, 0xfa, 0x03, 0x76, 0x44, 0x73, 0x4d, 0xd2, 0xf4, 0x55, 0x89, 0xda, 0x05, 0x5d,
+	0x4d, 0x6b, 0xe6, 0xe3, 0xb2, 0x76, 0x61, 0x79, 0x1e, 0xc0, 0xe4, 0x2d, 0x11, 0x54, 0x9f, 0x5e,
+	0xf6, 0x59, 0x95, 0x3e, 0xef, 0xc3, 0x82, 0xc6, 0x94, 0x24, 0xe6, 0xa2, 0xe3, 0xb2, 0x45, 0x91,
+	0x9f, 0x1e, 0x24, 0x2f, 0x1c, 0x58, 0xaf, 0x06, 0xda, 0x58, 0xbb, 0x2f, 0x00, 0xa5, 0xb8, 0x07,
+	0x77, 0x58, 0xc5, 0x75, 0x06, 0x47, 0xdf, 0x98, 0x4e, 0xf5, 0x4e, 0xd7, 0x00, 0x23, 0x21, 0x48,
+	0x6e, 0x80, 0x87, 0xbb, 0xc0, 0x3f, 0x08, 0xf3, 0x00, 0x1a, 0xb7, 0x61, 0xac, 0x25, 0xef, 0x0e,
+	0x9a, 0x3e, 0x0d, 0xa8, 0xa0, 0x6c, 0x4e, 0x15, 0x72, 0x26, 0xc2, 0x02, 0x39, 0x13, 0x21, 0xfa,
+	0x0f, 0x4d, 0xed, 0x51, 0x21, 0x9b, 0x29, 0x97, 0x02, 0x42, 0xf0, 0x2b, 0x0d, 0xdf, 0x69, 0xc7,
+	0x76, 0xad, 0x9e, 0xed, 0xeb, 0xda, 0xbb, 0x87, 0xfa, 0x83, 0x14, 0x94, 0xac, 0xf6, 0xa4, 0xb9,
+	0xe0, 0xcc, 0x39, 0x93, 0x94, 0xc9, 0x4a, 0x5e, 0x55, 0xda, 0x9b, 0x38, 0x86, 0xfa, 0x0d, 0xcd,
+	0x7d, 0x1a, 0xa8, 0xdd, 0x28, 0x64, 0x8b, 0x4d, 0xa4, 0xae, 0x95, 0xc6, 0xc8, 0xaa, 0x08, 0xd3,
+	0x75, 0x31, 0x7a, 0x7b, 0x3b, 0xfa, 0x71, 0xeb, 0xd9, 0xa9, 0xbc, 0x8f, 0x59, 0x5d, 0xff, 0xbf,
+	0xe7, 0x5f, 0x03, 0x00, 0x9e, 0xa5, 0x76, 0xa6, 0x4b, 0x03, 0x00, 0x00,
 }
@@ -121,6 +121,16 @@ func TestValueTester(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 }
 
+func TestWrapTypedSlice(t *testing.T) {
+	tv, err := Wrap([]string{"foo", "bar"})
+	assert.NoError(t, err)
+	assert.Equal(t, TypeList, tv.ValueType())
+	assert.Equal(t, []interface{}{"foo", "bar"}, MustUnwrap(tv))
+
+	_, err = Wrap([]struct{}{{}})
+	assert.Error(t, err)
+}
+
 func BenchmarkParse(b *testing.B) {
 	for _, testCase := range parseFormatTestCases() {
 		b.Run(testCase.expectedType+"_parse", func(b *testing.B) {
@@ -19,6 +19,9 @@ var (
 	TypeExpression string
 	TypeMap        string
 	TypeList       string
+	TypeReference  string
+	TypeStream     string
+	TypeKeyRef     string
 	TypeNumber     []string
 	Types          []string
 )
@@ -38,6 +41,9 @@ func init() {
 	TypeExpression = proto.MessageName(&Expression{})
 	TypeMap = proto.MessageName(&MapValue{})
 	TypeList = proto.MessageName(&ArrayValue{})
+	TypeReference = proto.MessageName(&Reference{})
+	TypeStream = proto.MessageName(&Stream{})
+	TypeKeyRef = proto.MessageName(&KeyRef{})
 	TypeNumber = []string{
 		TypeFloat64,
 		TypeFloat32,
@@ -60,5 +66,8 @@ func init() {
 		TypeExpression,
 		TypeMap,
 		TypeList,
+		TypeReference,
+		TypeStream,
+		TypeKeyRef,
 	}
 }
@@ -14,6 +14,7 @@ It has these top-level messages:
 	WorkflowInvocation
 	WorkflowInvocationSpec
 	WorkflowInvocationStatus
+	Intent
 	DependencyConfig
 	Task
 	TaskSpec
@@ -25,6 +26,7 @@ It has these top-level messages:
 	ObjectMetadata
 	Error
 	FnRef
+	FnHints
 	TypedValueMap
 	TypedValueList
 */
@@ -48,6 +50,40 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// ConcurrencyPolicy determines what happens when the workflow is invoked while invocations of it are still running.
+type WorkflowSpec_ConcurrencyPolicy int32
+
+const (
+	// ALLOW runs the invocations in parallel.
+	WorkflowSpec_ALLOW WorkflowSpec_ConcurrencyPolicy = 0
+	// FORBID fails the new invocation.
+	WorkflowSpec_FORBID WorkflowSpec_ConcurrencyPolicy = 1
+	// QUEUE starts the new invocation once the invocations that were created before it have finished.
+	WorkflowSpec_QUEUE WorkflowSpec_ConcurrencyPolicy = 2
+	// REPLACE cancels the running invocations, and starts the new invocation.
+	WorkflowSpec_REPLACE WorkflowSpec_ConcurrencyPolicy = 3
+)
+
+var WorkflowSpec_ConcurrencyPolicy_name = map[int32]string{
+	0: "ALLOW",
+	1: "FORBID",
+	2: "QUEUE",
+	3: "REPLACE",
+}
+var WorkflowSpec_ConcurrencyPolicy_value = map[string]int32{
+	"ALLOW":   0,
+	"FORBID":  1,
+	"QUEUE":   2,
+	"REPLACE": 3,
+}
+
+func (x WorkflowSpec_ConcurrencyPolicy) String() string {
+	return proto.EnumName(WorkflowSpec_ConcurrencyPolicy_name, int32(x))
+}
+func (WorkflowSpec_ConcurrencyPolicy) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{1, 0}
+}
+
 type WorkflowStatus_Status int32
 
 const (
@@ -110,6 +146,30 @@ func (WorkflowInvocationStatus_Status) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor0, []int{5, 0}
 }
 
+type Intent_Action int32
+
+const (
+	Intent_RUN      Intent_Action = 0
+	Intent_FAIL     Intent_Action = 1
+	Intent_COMPLETE Intent_Action = 2
+)
+
+var Intent_Action_name = map[int32]string{
+	0: "RUN",
+	1: "FAIL",
+	2: "COMPLETE",
+}
+var Intent_Action_value = map[string]int32{
+	"RUN":      0,
+	"FAIL":     1,
+	"COMPLETE": 2,
+}
+
+func (x Intent_Action) String() string {
+	return proto.EnumName(Intent_Action_name, int32(x))
+}
+func (Intent_Action) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 0} }
+
 type TaskStatus_Status int32
 
 const (
@@ -132,12 +192,16 @@ var TaskStatus_Status_value = map[string]int32{
 func (x TaskStatus_Status) String() string {
 	return proto.EnumName(TaskStatus_Status_name, int32(x))
 }
-func (TaskStatus_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{9, 0} }
+func (TaskStatus_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{10, 0} }
 
 type TaskDependencyParameters_DependencyType int32
 
 const (
-	TaskDependencyParameters_DATA           TaskDependencyParameters_DependencyType = 0
+	// DATA indicates that the task uses the output of the dependency, so it is started once the dependency has
+	// completed.
+	TaskDependencyParameters_DATA TaskDependencyParameters_DependencyType = 0
+	// CONTROL indicates that the task only has to be started after the dependency, without using its output. The
+	// task is started as soon as the dependency has been started, rather than once it has completed.
 	TaskDependencyParameters_CONTROL        TaskDependencyParameters_DependencyType = 1
 	TaskDependencyParameters_DYNAMIC_OUTPUT TaskDependencyParameters_DependencyType = 2
 )
@@ -157,7 +221,7 @@ func (x TaskDependencyParameters_DependencyType) String() string {
 	return proto.EnumName(TaskDependencyParameters_DependencyType_name, int32(x))
 }
 func (TaskDependencyParameters_DependencyType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{10, 0}
+	return fileDescriptor0, []int{11, 0}
 }
 
 type TaskInvocationStatus_Status int32
@@ -195,7 +259,7 @@ func (x TaskInvocationStatus_Status) String() string {
 	return proto.EnumName(TaskInvocationStatus_Status_name, int32(x))
 }
 func (TaskInvocationStatus_Status) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor0, []int{13, 0}
+	return fileDescriptor0, []int{14, 0}
 }
 
 //
@@ -205,6 +269,10 @@ type Workflow struct {
 	Metadata *ObjectMetadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
 	Spec     *WorkflowSpec   `protobuf:"bytes,2,opt,name=spec" json:"spec,omitempty"`
 	Status   *WorkflowStatus `protobuf:"bytes,3,opt,name=status" json:"status,omitempty"`
+	// History contains snapshots of the previous versions of the workflow, ordered from old to new. Each update of a
+	// workflow creates a new immutable version; the previous versions remain available to be invoked. The snapshots do
+	// not contain a history themselves.
+	History []*Workflow `protobuf:"bytes,4,rep,name=history" json:"history,omitempty"`
 }
 
 func (m *Workflow) Reset()                    { *m = Workflow{} }
@@ -233,6 +301,13 @@ func (m *Workflow) GetStatus() *WorkflowStatus {
 	return nil
 }
 
+func (m *Workflow) GetHistory() []*Workflow {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
 // WorkflowSpec contains the definition of a workflow.
 //
 // Ideally the source code (json, yaml) can be converted directly to this message.
@@ -246,7 +321,7 @@ type WorkflowSpec struct {
 	//
 	// Note: Dependency graph is build into the tasks.
 	Tasks map[string]*TaskSpec `protobuf:"bytes,2,rep,name=tasks" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	// From which task should the workflow return the output? Future: multiple? Implicit?
+	// From which task should the workflow return the output? Optional if the workflow declares outputs.
 	OutputTask  string `protobuf:"bytes,3,opt,name=outputTask" json:"outputTask,omitempty"`
 	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
 	// The UID that the workflow should have. Only use this in case you want to force a specific UID.
@@ -255,6 +330,31 @@ type WorkflowSpec struct {
 	Name string `protobuf:"bytes,6,opt,name=name" json:"name,omitempty"`
 	// Internal indicates whether is a workflow should be visible to a human (default) or not.
 	Internal bool `protobuf:"varint,7,opt,name=internal" json:"internal,omitempty"`
+	// Namespace is the namespace of the workflow and its invocations, to which the authorization rules and quotas
+	// apply. It is also the default namespace of the functions that the tasks reference without a namespace, for
+	// the function environments that scope functions to namespaces, such as Fission.
+	Namespace string `protobuf:"bytes,8,opt,name=namespace" json:"namespace,omitempty"`
+	// Labels contains arbitrary key-value pairs that describe the workflow, which can be used to search workflows.
+	Labels map[string]string `protobuf:"bytes,9,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Annotations contains arbitrary key-value pairs with additional information about the workflow. Unlike the
+	// labels, annotations cannot be used to search workflows.
+	Annotations map[string]string `protobuf:"bytes,10,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// InputSchema is a JSON Schema that the inputs of the invocations of the workflow should conform to, with the
+	// properties of the schema being the keys of the inputs. The inputs of an invocation that do not conform to the
+	// schema are rejected, with an error for each invalid input field. If empty, the inputs are not validated.
+	InputSchema string `protobuf:"bytes,11,opt,name=inputSchema" json:"inputSchema,omitempty"`
+	// DefaultInputs contains the values of the inputs that are used when the invoker omits them, with the key being
+	// the input key. The defaults are applied when the invocation is created, before its inputs are validated against
+	// the input schema.
+	DefaultInputs map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,12,rep,name=defaultInputs" json:"defaultInputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Consts contains the constants of the workflow, such as shared configuration, which the expressions of all tasks
+	// can refer to (e.g. $.Consts.bucket). Unlike inputs, the constants cannot be overridden by the invoker.
+	Consts map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,13,rep,name=consts" json:"consts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Outputs contains the named outputs of the workflow, with each value being an expression that is evaluated in the
+	// scope of the invocation once all tasks have completed (e.g. $.Tasks.foo.Output). If present, the invocation
+	// returns a map of the named outputs instead of the output of the output task.
+	Outputs           map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,14,rep,name=outputs" json:"outputs,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ConcurrencyPolicy WorkflowSpec_ConcurrencyPolicy                 `protobuf:"varint,15,opt,name=concurrencyPolicy,enum=fission.workflows.types.WorkflowSpec_ConcurrencyPolicy" json:"concurrencyPolicy,omitempty"`
 }
 
 func (m *WorkflowSpec) Reset()                    { *m = WorkflowSpec{} }
@@ -311,12 +411,70 @@ func (m *WorkflowSpec) GetInternal() bool {
 	return false
 }
 
+func (m *WorkflowSpec) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *WorkflowSpec) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetInputSchema() string {
+	if m != nil {
+		return m.InputSchema
+	}
+	return ""
+}
+
+func (m *WorkflowSpec) GetDefaultInputs() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.DefaultInputs
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetConsts() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Consts
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetOutputs() map[string]*fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.Outputs
+	}
+	return nil
+}
+
+func (m *WorkflowSpec) GetConcurrencyPolicy() WorkflowSpec_ConcurrencyPolicy {
+	if m != nil {
+		return m.ConcurrencyPolicy
+	}
+	return WorkflowSpec_ALLOW
+}
+
 type WorkflowStatus struct {
 	Status    WorkflowStatus_Status      `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.WorkflowStatus_Status" json:"status,omitempty"`
 	UpdatedAt *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	// Tasks contains the status of the tasks, with the key being the task id.
 	Tasks map[string]*Task `protobuf:"bytes,3,rep,name=tasks" json:"tasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Error *Error           `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// Version is the version of the workflow spec. It starts at 1 and is incremented for every update of the workflow.
+	Version int64 `protobuf:"varint,5,opt,name=version" json:"version,omitempty"`
 }
 
 func (m *WorkflowStatus) Reset()                    { *m = WorkflowStatus{} }
@@ -352,6 +510,13 @@ func (m *WorkflowStatus) GetError() *Error {
 	return nil
 }
 
+func (m *WorkflowStatus) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
 //
 // Workflow Invocation Model
 //
@@ -408,6 +573,29 @@ type WorkflowInvocationSpec struct {
 	// Each invocation has a deadline. If no deadline is provided Fission Workflows uses a default deadline (typically
 	// 10 minutes).
 	Deadline *google_protobuf.Timestamp `protobuf:"bytes,5,opt,name=Deadline" json:"Deadline,omitempty"`
+	// Labels contains arbitrary key-value pairs that describe the invocation.
+	//
+	// Invocations started from within another invocation (such as nested workflows or dynamic tasks) inherit the
+	// labels of the parent invocation, unless the label is explicitly set in the child invocation.
+	Labels map[string]string `protobuf:"bytes,6,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// TracingContext contains the serialized opentracing span context of the caller of the invocation.
+	//
+	// It is used to relate the spans of the invocation to the spans of the caller, for example to link the spans of a
+	// nested workflow invocation to those of the parent invocation.
+	TracingContext map[string]string `protobuf:"bytes,7,rep,name=tracingContext" json:"tracingContext,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// CallerId contains the id of the invocation that started this invocation, such as the invocation of the parent
+	// workflow of a nested workflow.
+	//
+	// Unlike the parentId, the callerId does not affect the scope of the invocation. It is used to find the child
+	// invocations of an invocation, for example to cancel them along with the invocation.
+	CallerId string `protobuf:"bytes,8,opt,name=callerId" json:"callerId,omitempty"`
+	// WorkflowVersion is the version of the workflow to invoke. If it is not set (0), the latest version of the
+	// workflow is invoked. Once the invocation has been created, it contains the version that the invocation is pinned
+	// to; later updates of the workflow do not affect the invocation.
+	WorkflowVersion int64 `protobuf:"varint,9,opt,name=workflowVersion" json:"workflowVersion,omitempty"`
+	// Annotations contains arbitrary key-value pairs with additional information about the invocation. Unlike the
+	// labels, annotations cannot be used to search invocations.
+	Annotations map[string]string `protobuf:"bytes,10,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *WorkflowInvocationSpec) Reset()                    { *m = WorkflowInvocationSpec{} }
@@ -450,6 +638,41 @@ func (m *WorkflowInvocationSpec) GetDeadline() *google_protobuf.Timestamp {
 	return nil
 }
 
+func (m *WorkflowInvocationSpec) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationSpec) GetTracingContext() map[string]string {
+	if m != nil {
+		return m.TracingContext
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationSpec) GetCallerId() string {
+	if m != nil {
+		return m.CallerId
+	}
+	return ""
+}
+
+func (m *WorkflowInvocationSpec) GetWorkflowVersion() int64 {
+	if m != nil {
+		return m.WorkflowVersion
+	}
+	return 0
+}
+
+func (m *WorkflowInvocationSpec) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
 type WorkflowInvocationStatus struct {
 	Status    WorkflowInvocationStatus_Status     `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.WorkflowInvocationStatus_Status" json:"status,omitempty"`
 	UpdatedAt *google_protobuf.Timestamp          `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
@@ -460,6 +683,15 @@ type WorkflowInvocationStatus struct {
 	DynamicTasks  map[string]*Task                    `protobuf:"bytes,5,rep,name=dynamicTasks" json:"dynamicTasks,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	Error         *Error                              `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
 	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,7,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// TraceId is the id of the distributed trace of the invocation, such as a Jaeger or OpenTelemetry trace, as a
+	// 32-character hex string. It is only set if the invocation was created within a sampled trace.
+	TraceId string `protobuf:"bytes,8,opt,name=traceId" json:"traceId,omitempty"`
+	// Intents are the actions on the invocation that the controller has decided on, but that have not been applied
+	// yet, indexed by their id. They are resumed when the controller is restarted.
+	Intents map[string]*Intent `protobuf:"bytes,9,rep,name=intents" json:"intents,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// TracingContext is the serialized opentracing span context of the caller of the invocation: the tracing context
+	// of the spec if it was provided, or otherwise that of the request that created the invocation.
+	TracingContext map[string]string `protobuf:"bytes,10,rep,name=tracingContext" json:"tracingContext,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *WorkflowInvocationStatus) Reset()                    { *m = WorkflowInvocationStatus{} }
@@ -516,6 +748,73 @@ func (m *WorkflowInvocationStatus) GetOutputHeaders() *fission_workflows_types.T
 	return nil
 }
 
+func (m *WorkflowInvocationStatus) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *WorkflowInvocationStatus) GetIntents() map[string]*Intent {
+	if m != nil {
+		return m.Intents
+	}
+	return nil
+}
+
+func (m *WorkflowInvocationStatus) GetTracingContext() map[string]string {
+	if m != nil {
+		return m.TracingContext
+	}
+	return nil
+}
+
+// Intent is an action on an invocation that the controller has submitted for execution. It is recorded in the event
+// store before the action is executed, so that the action is not lost if the controller crashes in the meantime.
+// An intent is resolved once the outcome of the action has been recorded: the task has finished, or the invocation
+// has completed or failed.
+type Intent struct {
+	Action Intent_Action `protobuf:"varint,1,opt,name=action,enum=fission.workflows.types.Intent_Action" json:"action,omitempty"`
+	// TaskId is the id of the task to run. Only set if action == RUN.
+	TaskId string `protobuf:"bytes,2,opt,name=taskId" json:"taskId,omitempty"`
+	// Error is the reason for which the invocation is failed. Only set if action == FAIL.
+	Error     *Error                     `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	CreatedAt *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=createdAt" json:"createdAt,omitempty"`
+}
+
+func (m *Intent) Reset()                    { *m = Intent{} }
+func (m *Intent) String() string            { return proto.CompactTextString(m) }
+func (*Intent) ProtoMessage()               {}
+func (*Intent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *Intent) GetAction() Intent_Action {
+	if m != nil {
+		return m.Action
+	}
+	return Intent_RUN
+}
+
+func (m *Intent) GetTaskId() string {
+	if m != nil {
+		return m.TaskId
+	}
+	return ""
+}
+
+func (m *Intent) GetError() *Error {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+func (m *Intent) GetCreatedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return nil
+}
+
 type DependencyConfig struct {
 	// Dependencies for this task to execute
 	Requires map[string]*TaskDependencyParameters `protobuf:"bytes,1,rep,name=requires" json:"requires,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
@@ -526,7 +825,7 @@ type DependencyConfig struct {
 func (m *DependencyConfig) Reset()                    { *m = DependencyConfig{} }
 func (m *DependencyConfig) String() string            { return proto.CompactTextString(m) }
 func (*DependencyConfig) ProtoMessage()               {}
-func (*DependencyConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*DependencyConfig) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 func (m *DependencyConfig) GetRequires() map[string]*TaskDependencyParameters {
 	if m != nil {
@@ -554,7 +853,7 @@ type Task struct {
 func (m *Task) Reset()                    { *m = Task{} }
 func (m *Task) String() string            { return proto.CompactTextString(m) }
 func (*Task) ProtoMessage()               {}
-func (*Task) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*Task) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
 func (m *Task) GetMetadata() *ObjectMetadata {
 	if m != nil {
@@ -601,12 +900,33 @@ type TaskSpec struct {
 	// It overrides the deadline specified by the workflow invocation, but cannot exceed it. If set, this field will be
 	// used in the task invocation spec to compute the deadline.
 	Timeout *google_protobuf1.Duration `protobuf:"bytes,7,opt,name=timeout" json:"timeout,omitempty"`
+	// InputSchemas contains the schema references (e.g. protobuf:google.protobuf.Timestamp or avro:42) that the inputs
+	// of this task should conform to, keyed by the input key.
+	InputSchemas map[string]string `protobuf:"bytes,8,rep,name=inputSchemas" json:"inputSchemas,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// OutputSchema is the schema reference that the output of this task should conform to.
+	OutputSchema string `protobuf:"bytes,9,opt,name=outputSchema" json:"outputSchema,omitempty"`
+	// Idempotent indicates that the function of this task can safely be invoked more than once with the same inputs,
+	// for example because it ignores requests with an idempotency key that it has processed already. If the workflow
+	// engine restarts while the task is in progress, an idempotent task is invoked again; other tasks are failed.
+	Idempotent bool `protobuf:"varint,10,opt,name=idempotent" json:"idempotent,omitempty"`
+	// MaxRetries is the maximum number of times that the task is run again after a failed attempt. By default, failed
+	// tasks are not retried.
+	MaxRetries int32 `protobuf:"varint,11,opt,name=maxRetries" json:"maxRetries,omitempty"`
+	// Backoff is the delay between a failed attempt and its retry. The delay is doubled for every subsequent retry. By
+	// default, failed attempts are retried immediately.
+	Backoff *google_protobuf1.Duration `protobuf:"bytes,12,opt,name=backoff" json:"backoff,omitempty"`
+	// RetryableStatusCodes limits the retries to the attempts that failed with one of these status codes, such as the
+	// HTTP status code of the response of the function. If empty, all failed attempts are retried.
+	RetryableStatusCodes []int32 `protobuf:"varint,13,rep,packed,name=retryableStatusCodes" json:"retryableStatusCodes,omitempty"`
+	// When is the condition under which the task is run. It is evaluated, typically as an expression, once the
+	// dependencies of the task have finished. If it evaluates to false, the task is skipped instead of run.
+	When *fission_workflows_types.TypedValue `protobuf:"bytes,14,opt,name=when" json:"when,omitempty"`
 }
 
 func (m *TaskSpec) Reset()                    { *m = TaskSpec{} }
 func (m *TaskSpec) String() string            { return proto.CompactTextString(m) }
 func (*TaskSpec) ProtoMessage()               {}
-func (*TaskSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*TaskSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *TaskSpec) GetFunctionRef() string {
 	if m != nil {
@@ -657,17 +977,68 @@ func (m *TaskSpec) GetTimeout() *google_protobuf1.Duration {
 	return nil
 }
 
+func (m *TaskSpec) GetInputSchemas() map[string]string {
+	if m != nil {
+		return m.InputSchemas
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetOutputSchema() string {
+	if m != nil {
+		return m.OutputSchema
+	}
+	return ""
+}
+
+func (m *TaskSpec) GetIdempotent() bool {
+	if m != nil {
+		return m.Idempotent
+	}
+	return false
+}
+
+func (m *TaskSpec) GetMaxRetries() int32 {
+	if m != nil {
+		return m.MaxRetries
+	}
+	return 0
+}
+
+func (m *TaskSpec) GetBackoff() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Backoff
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetRetryableStatusCodes() []int32 {
+	if m != nil {
+		return m.RetryableStatusCodes
+	}
+	return nil
+}
+
+func (m *TaskSpec) GetWhen() *fission_workflows_types.TypedValue {
+	if m != nil {
+		return m.When
+	}
+	return nil
+}
+
 type TaskStatus struct {
 	Status    TaskStatus_Status          `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.TaskStatus_Status" json:"status,omitempty"`
 	UpdatedAt *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	FnRef     *FnRef                     `protobuf:"bytes,3,opt,name=fnRef" json:"fnRef,omitempty"`
 	Error     *Error                     `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// FnHints are the hints of the function environment about the runtime behavior of the resolved function.
+	FnHints *FnHints `protobuf:"bytes,5,opt,name=fnHints" json:"fnHints,omitempty"`
 }
 
 func (m *TaskStatus) Reset()                    { *m = TaskStatus{} }
 func (m *TaskStatus) String() string            { return proto.CompactTextString(m) }
 func (*TaskStatus) ProtoMessage()               {}
-func (*TaskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*TaskStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 func (m *TaskStatus) GetStatus() TaskStatus_Status {
 	if m != nil {
@@ -697,15 +1068,25 @@ func (m *TaskStatus) GetError() *Error {
 	return nil
 }
 
+func (m *TaskStatus) GetFnHints() *FnHints {
+	if m != nil {
+		return m.FnHints
+	}
+	return nil
+}
+
 type TaskDependencyParameters struct {
 	Type  TaskDependencyParameters_DependencyType `protobuf:"varint,1,opt,name=type,enum=fission.workflows.types.TaskDependencyParameters_DependencyType" json:"type,omitempty"`
 	Alias string                                  `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
+	// Artifacts optionally lists the fields of the output of a data dependency that the task uses. If present,
+	// references to other fields of the output of the dependency are reported as dangling by the validation.
+	Artifacts []string `protobuf:"bytes,3,rep,name=artifacts" json:"artifacts,omitempty"`
 }
 
 func (m *TaskDependencyParameters) Reset()                    { *m = TaskDependencyParameters{} }
 func (m *TaskDependencyParameters) String() string            { return proto.CompactTextString(m) }
 func (*TaskDependencyParameters) ProtoMessage()               {}
-func (*TaskDependencyParameters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (*TaskDependencyParameters) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *TaskDependencyParameters) GetType() TaskDependencyParameters_DependencyType {
 	if m != nil {
@@ -721,6 +1102,13 @@ func (m *TaskDependencyParameters) GetAlias() string {
 	return ""
 }
 
+func (m *TaskDependencyParameters) GetArtifacts() []string {
+	if m != nil {
+		return m.Artifacts
+	}
+	return nil
+}
+
 //
 // Task Invocation Model
 //
@@ -733,7 +1121,7 @@ type TaskInvocation struct {
 func (m *TaskInvocation) Reset()                    { *m = TaskInvocation{} }
 func (m *TaskInvocation) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocation) ProtoMessage()               {}
-func (*TaskInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+func (*TaskInvocation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *TaskInvocation) GetMetadata() *ObjectMetadata {
 	if m != nil {
@@ -776,12 +1164,18 @@ type TaskInvocationSpec struct {
 	// Each task has a deadline. If no deadline is specified the task invocation inherits the deadline of the
 	// invocation.
 	Deadline *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=Deadline" json:"Deadline,omitempty"`
+	// WorkflowId is the id of the workflow of the invocation of this task.
+	WorkflowId string `protobuf:"bytes,7,opt,name=workflowId" json:"workflowId,omitempty"`
+	// Attempt is the number of the attempt of the task within the invocation, starting at 0. Re-executions of the same
+	// attempt, such as the resumption of the task after a restart of the controller, share the attempt, and thereby
+	// the idempotency key that is passed to the function.
+	Attempt int32 `protobuf:"varint,8,opt,name=attempt" json:"attempt,omitempty"`
 }
 
 func (m *TaskInvocationSpec) Reset()                    { *m = TaskInvocationSpec{} }
 func (m *TaskInvocationSpec) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocationSpec) ProtoMessage()               {}
-func (*TaskInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (*TaskInvocationSpec) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func (m *TaskInvocationSpec) GetFnRef() *FnRef {
 	if m != nil {
@@ -825,18 +1219,36 @@ func (m *TaskInvocationSpec) GetDeadline() *google_protobuf.Timestamp {
 	return nil
 }
 
+func (m *TaskInvocationSpec) GetWorkflowId() string {
+	if m != nil {
+		return m.WorkflowId
+	}
+	return ""
+}
+
+func (m *TaskInvocationSpec) GetAttempt() int32 {
+	if m != nil {
+		return m.Attempt
+	}
+	return 0
+}
+
 type TaskInvocationStatus struct {
 	Status        TaskInvocationStatus_Status         `protobuf:"varint,1,opt,name=status,enum=fission.workflows.types.TaskInvocationStatus_Status" json:"status,omitempty"`
 	UpdatedAt     *google_protobuf.Timestamp          `protobuf:"bytes,2,opt,name=updatedAt" json:"updatedAt,omitempty"`
 	Output        *fission_workflows_types.TypedValue `protobuf:"bytes,3,opt,name=output" json:"output,omitempty"`
 	Error         *Error                              `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
 	OutputHeaders *fission_workflows_types.TypedValue `protobuf:"bytes,5,opt,name=outputHeaders" json:"outputHeaders,omitempty"`
+	// Attempts is the number of attempts of the task, including the current one.
+	Attempts int32 `protobuf:"varint,6,opt,name=attempts" json:"attempts,omitempty"`
+	// LastError is the error of the most recent failed attempt of the task, which is kept when the task is retried.
+	LastError *Error `protobuf:"bytes,7,opt,name=lastError" json:"lastError,omitempty"`
 }
 
 func (m *TaskInvocationStatus) Reset()                    { *m = TaskInvocationStatus{} }
 func (m *TaskInvocationStatus) String() string            { return proto.CompactTextString(m) }
 func (*TaskInvocationStatus) ProtoMessage()               {}
-func (*TaskInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (*TaskInvocationStatus) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
 func (m *TaskInvocationStatus) GetStatus() TaskInvocationStatus_Status {
 	if m != nil {
@@ -873,6 +1285,20 @@ func (m *TaskInvocationStatus) GetOutputHeaders() *fission_workflows_types.Typed
 	return nil
 }
 
+func (m *TaskInvocationStatus) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *TaskInvocationStatus) GetLastError() *Error {
+	if m != nil {
+		return m.LastError
+	}
+	return nil
+}
+
 // ObjectMetadata contains common metadata present for all objects in the workflow engine.
 //
 // It closely follows the structure of Kubernetes' ObjectMetadata, leaving out the parameters that do not fit the
@@ -889,12 +1315,16 @@ type ObjectMetadata struct {
 	// Generation is a sequence identifier used and updated by the system to record the number of events or
 	// changes applied to the object.
 	Generation int64 `protobuf:"varint,4,opt,name=generation" json:"generation,omitempty"`
+	// Labels contains the labels of the spec of the object, which can be used to search objects with label selectors.
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Annotations contains the annotations of the spec of the object.
+	Annotations map[string]string `protobuf:"bytes,6,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *ObjectMetadata) Reset()                    { *m = ObjectMetadata{} }
 func (m *ObjectMetadata) String() string            { return proto.CompactTextString(m) }
 func (*ObjectMetadata) ProtoMessage()               {}
-func (*ObjectMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (*ObjectMetadata) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
 
 func (m *ObjectMetadata) GetId() string {
 	if m != nil {
@@ -924,14 +1354,31 @@ func (m *ObjectMetadata) GetGeneration() int64 {
 	return 0
 }
 
+func (m *ObjectMetadata) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *ObjectMetadata) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
 type Error struct {
 	Message string `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	// StatusCode is the status code with which the function failed, such as the HTTP status code of its response or
+	// the exit code of its container, or 0 if the function did not report one.
+	StatusCode int32 `protobuf:"varint,2,opt,name=statusCode" json:"statusCode,omitempty"`
 }
 
 func (m *Error) Reset()                    { *m = Error{} }
 func (m *Error) String() string            { return proto.CompactTextString(m) }
 func (*Error) ProtoMessage()               {}
-func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
 
 func (m *Error) GetMessage() string {
 	if m != nil {
@@ -940,6 +1387,13 @@ func (m *Error) GetMessage() string {
 	return ""
 }
 
+func (m *Error) GetStatusCode() int32 {
+	if m != nil {
+		return m.StatusCode
+	}
+	return 0
+}
+
 // FnRef is an immutable, unique reference to a function on a specific function runtime environment.
 //
 // The string representation (via String or Format): runtime://runtimeId
@@ -955,7 +1409,7 @@ type FnRef struct {
 func (m *FnRef) Reset()                    { *m = FnRef{} }
 func (m *FnRef) String() string            { return proto.CompactTextString(m) }
 func (*FnRef) ProtoMessage()               {}
-func (*FnRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (*FnRef) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
 
 func (m *FnRef) GetRuntime() string {
 	if m != nil {
@@ -978,6 +1432,45 @@ func (m *FnRef) GetID() string {
 	return ""
 }
 
+// FnHints are hints about the runtime behavior of a function, which a function environment can provide when it
+// resolves the function. The workflow engine uses them instead of its defaults, for example to determine the deadline
+// of the task, or when to prewarm the function.
+type FnHints struct {
+	// Timeout is the maximum duration of an invocation of the function.
+	Timeout *google_protobuf1.Duration `protobuf:"bytes,1,opt,name=timeout" json:"timeout,omitempty"`
+	// ExpectedDuration is the typical duration of an invocation of the function.
+	ExpectedDuration *google_protobuf1.Duration `protobuf:"bytes,2,opt,name=expectedDuration" json:"expectedDuration,omitempty"`
+	// MaxConcurrency is the maximum number of concurrent invocations of the function. If 0, the number of concurrent
+	// invocations is not limited.
+	MaxConcurrency int32 `protobuf:"varint,3,opt,name=maxConcurrency" json:"maxConcurrency,omitempty"`
+}
+
+func (m *FnHints) Reset()                    { *m = FnHints{} }
+func (m *FnHints) String() string            { return proto.CompactTextString(m) }
+func (*FnHints) ProtoMessage()               {}
+func (*FnHints) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *FnHints) GetTimeout() *google_protobuf1.Duration {
+	if m != nil {
+		return m.Timeout
+	}
+	return nil
+}
+
+func (m *FnHints) GetExpectedDuration() *google_protobuf1.Duration {
+	if m != nil {
+		return m.ExpectedDuration
+	}
+	return nil
+}
+
+func (m *FnHints) GetMaxConcurrency() int32 {
+	if m != nil {
+		return m.MaxConcurrency
+	}
+	return 0
+}
+
 // Utility wrapper for a TypedValue map
 type TypedValueMap struct {
 	Value map[string]*fission_workflows_types.TypedValue `protobuf:"bytes,1,rep,name=Value" json:"Value,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
@@ -986,7 +1479,7 @@ type TypedValueMap struct {
 func (m *TypedValueMap) Reset()                    { *m = TypedValueMap{} }
 func (m *TypedValueMap) String() string            { return proto.CompactTextString(m) }
 func (*TypedValueMap) ProtoMessage()               {}
-func (*TypedValueMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (*TypedValueMap) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
 
 func (m *TypedValueMap) GetValue() map[string]*fission_workflows_types.TypedValue {
 	if m != nil {
@@ -1003,7 +1496,7 @@ type TypedValueList struct {
 func (m *TypedValueList) Reset()                    { *m = TypedValueList{} }
 func (m *TypedValueList) String() string            { return proto.CompactTextString(m) }
 func (*TypedValueList) ProtoMessage()               {}
-func (*TypedValueList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (*TypedValueList) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
 
 func (m *TypedValueList) GetValue() []*fission_workflows_types.TypedValue {
 	if m != nil {
@@ -1019,6 +1512,7 @@ func init() {
 	proto.RegisterType((*WorkflowInvocation)(nil), "fission.workflows.types.WorkflowInvocation")
 	proto.RegisterType((*WorkflowInvocationSpec)(nil), "fission.workflows.types.WorkflowInvocationSpec")
 	proto.RegisterType((*WorkflowInvocationStatus)(nil), "fission.workflows.types.WorkflowInvocationStatus")
+	proto.RegisterType((*Intent)(nil), "fission.workflows.types.Intent")
 	proto.RegisterType((*DependencyConfig)(nil), "fission.workflows.types.DependencyConfig")
 	proto.RegisterType((*Task)(nil), "fission.workflows.types.Task")
 	proto.RegisterType((*TaskSpec)(nil), "fission.workflows.types.TaskSpec")
@@ -1030,10 +1524,13 @@ func init() {
 	proto.RegisterType((*ObjectMetadata)(nil), "fission.workflows.types.ObjectMetadata")
 	proto.RegisterType((*Error)(nil), "fission.workflows.types.Error")
 	proto.RegisterType((*FnRef)(nil), "fission.workflows.types.FnRef")
+	proto.RegisterType((*FnHints)(nil), "fission.workflows.types.FnHints")
 	proto.RegisterType((*TypedValueMap)(nil), "fission.workflows.types.TypedValueMap")
 	proto.RegisterType((*TypedValueList)(nil), "fission.workflows.types.TypedValueList")
+	proto.RegisterEnum("fission.workflows.types.WorkflowSpec_ConcurrencyPolicy", WorkflowSpec_ConcurrencyPolicy_name, WorkflowSpec_ConcurrencyPolicy_value)
 	proto.RegisterEnum("fission.workflows.types.WorkflowStatus_Status", WorkflowStatus_Status_name, WorkflowStatus_Status_value)
 	proto.RegisterEnum("fission.workflows.types.WorkflowInvocationStatus_Status", WorkflowInvocationStatus_Status_name, WorkflowInvocationStatus_Status_value)
+	proto.RegisterEnum("fission.workflows.types.Intent_Action", Intent_Action_name, Intent_Action_value)
 	proto.RegisterEnum("fission.workflows.types.TaskStatus_Status", TaskStatus_Status_name, TaskStatus_Status_value)
 	proto.RegisterEnum("fission.workflows.types.TaskDependencyParameters_DependencyType", TaskDependencyParameters_DependencyType_name, TaskDependencyParameters_DependencyType_value)
 	proto.RegisterEnum("fission.workflows.types.TaskInvocationStatus_Status", TaskInvocationStatus_Status_name, TaskInvocationStatus_Status_value)
@@ -1042,98 +1539,146 @@ func init() {
 func init() { proto.RegisterFile("pkg/types/types.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1487 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0xae, 0x2c, 0xcb, 0x3f, 0xc7, 0x8d, 0x31, 0x3b, 0xa5, 0x18, 0x0f, 0x94, 0x54, 0x1d, 0xa6,
-	0x1d, 0xa0, 0x32, 0x49, 0x0b, 0x4d, 0x09, 0x9d, 0xe2, 0x5a, 0x4a, 0xab, 0xc9, 0x8f, 0x83, 0x6c,
-	0xb7, 0x53, 0x98, 0xb6, 0xa3, 0x58, 0x6b, 0xa3, 0x26, 0x96, 0x84, 0x24, 0xb7, 0x93, 0x3b, 0x9e,
-	0x80, 0x87, 0x60, 0xe0, 0x19, 0xb8, 0xe4, 0xa2, 0x37, 0xcc, 0xf0, 0x0c, 0x0c, 0x17, 0x5c, 0x71,
-	0xc1, 0x3b, 0x30, 0xbb, 0x5a, 0xfd, 0xd9, 0x71, 0x6c, 0x67, 0x5c, 0x86, 0x9b, 0x44, 0xbb, 0x7b,
-	0xce, 0xd9, 0xb3, 0xe7, 0xe7, 0xfb, 0x76, 0x0d, 0x6f, 0x39, 0x87, 0x83, 0xba, 0x7f, 0xec, 0x60,
-	0x2f, 0xf8, 0x2b, 0x39, 0xae, 0xed, 0xdb, 0xe8, 0xed, 0xbe, 0xe9, 0x79, 0xa6, 0x6d, 0x49, 0x2f,
-	0x6d, 0xf7, 0xb0, 0x7f, 0x64, 0xbf, 0xf4, 0x24, 0xba, 0x5c, 0x7b, 0x7f, 0x60, 0xdb, 0x83, 0x23,
-	0x5c, 0xa7, 0x62, 0x07, 0xa3, 0x7e, 0xdd, 0x37, 0x87, 0xd8, 0xf3, 0xf5, 0xa1, 0x13, 0x68, 0xd6,
-	0x2e, 0x8d, 0x0b, 0x18, 0x23, 0x57, 0xf7, 0x89, 0xa9, 0x60, 0x7d, 0x67, 0x60, 0xfa, 0xdf, 0x8e,
-	0x0e, 0xa4, 0x9e, 0x3d, 0xac, 0xb3, 0x4d, 0xc2, 0xff, 0xd7, 0xa3, 0xcd, 0xea, 0x69, 0xaf, 0x8c,
-	0x17, 0xfa, 0xd1, 0x28, 0xfd, 0x1d, 0x58, 0x13, 0x7f, 0xe7, 0xa0, 0xf0, 0x88, 0x69, 0xa1, 0x26,
-	0x14, 0x86, 0xd8, 0xd7, 0x0d, 0xdd, 0xd7, 0xab, 0xdc, 0x2a, 0x77, 0xad, 0xb4, 0x7e, 0x55, 0x9a,
-	0x72, 0x0e, 0xa9, 0x75, 0xf0, 0x1c, 0xf7, 0xfc, 0x5d, 0x26, 0xae, 0x45, 0x8a, 0xe8, 0x36, 0x64,
-	0x3d, 0x07, 0xf7, 0xaa, 0x19, 0x6a, 0xe0, 0x83, 0xa9, 0x06, 0xc2, 0x5d, 0xdb, 0x0e, 0xee, 0x69,
-	0x54, 0x05, 0xdd, 0x85, 0x9c, 0xe7, 0xeb, 0xfe, 0xc8, 0xab, 0xf2, 0x33, 0x76, 0x8f, 0x94, 0xa9,
-	0xb8, 0xc6, 0xd4, 0xc4, 0x3f, 0x33, 0x70, 0x3e, 0x69, 0x17, 0x5d, 0x02, 0xd0, 0x1d, 0xf3, 0x21,
-	0x76, 0x89, 0x15, 0x7a, 0xa6, 0xa2, 0x96, 0x98, 0x41, 0x5b, 0x20, 0xf8, 0xba, 0x77, 0xe8, 0x55,
-	0x33, 0xab, 0xfc, 0xb5, 0xd2, 0xfa, 0x27, 0x73, 0x79, 0x2b, 0x75, 0x88, 0x8a, 0x62, 0xf9, 0xee,
-	0xb1, 0x16, 0xa8, 0x93, 0x7d, 0xec, 0x91, 0xef, 0x8c, 0x7c, 0xb2, 0x44, 0xbd, 0x2f, 0x6a, 0x89,
-	0x19, 0xb4, 0x0a, 0x25, 0x03, 0x7b, 0x3d, 0xd7, 0x74, 0x48, 0x26, 0xab, 0x59, 0x2a, 0x90, 0x9c,
-	0x42, 0x55, 0xc8, 0xf7, 0x6d, 0xb7, 0x87, 0x55, 0xa3, 0x2a, 0xd0, 0xd5, 0x70, 0x88, 0x10, 0x64,
-	0x2d, 0x7d, 0x88, 0xab, 0x39, 0x3a, 0x4d, 0xbf, 0x51, 0x0d, 0x0a, 0xa6, 0xe5, 0x63, 0xd7, 0xd2,
-	0x8f, 0xaa, 0xf9, 0x55, 0xee, 0x5a, 0x41, 0x8b, 0xc6, 0xb5, 0x6f, 0x00, 0x62, 0x07, 0x51, 0x05,
-	0xf8, 0x43, 0x7c, 0xcc, 0x8e, 0x4e, 0x3e, 0xd1, 0x2d, 0x10, 0x68, 0x09, 0xb0, 0x0c, 0x5d, 0x9e,
-	0x7a, 0x66, 0x62, 0x85, 0x66, 0x27, 0x90, 0xff, 0x3c, 0xb3, 0xc1, 0x89, 0x3f, 0xf3, 0x50, 0x4e,
-	0x07, 0x1f, 0x6d, 0x45, 0x59, 0x23, 0x9b, 0x94, 0xd7, 0xa5, 0x39, 0xb3, 0x26, 0xa5, 0x93, 0x87,
-	0x36, 0xa0, 0x38, 0x72, 0x0c, 0xdd, 0xc7, 0x46, 0xc3, 0x67, 0xbe, 0xd5, 0xa4, 0xa0, 0x19, 0xa4,
-	0xb0, 0x19, 0xa4, 0x4e, 0xd8, 0x2d, 0x5a, 0x2c, 0x8c, 0x1e, 0x84, 0x59, 0xe4, 0x69, 0x16, 0xd7,
-	0xe7, 0x75, 0x60, 0x32, 0x8f, 0x37, 0x41, 0xc0, 0xae, 0x6b, 0xbb, 0x34, 0x43, 0xa5, 0xf5, 0x4b,
-	0x53, 0x2d, 0x29, 0x44, 0x4a, 0x0b, 0x84, 0x6b, 0x8f, 0x66, 0x44, 0xfc, 0x46, 0x3a, 0xe2, 0xef,
-	0x9d, 0x1a, 0xf1, 0x64, 0xb4, 0x37, 0x20, 0xc7, 0x82, 0x0c, 0x90, 0xfb, 0xaa, 0xab, 0x74, 0x15,
-	0xb9, 0x72, 0x0e, 0x15, 0x41, 0xd0, 0x94, 0x86, 0xfc, 0xb8, 0x92, 0x21, 0xd3, 0x5b, 0x0d, 0x75,
-	0x47, 0x91, 0x2b, 0x3c, 0x2a, 0x41, 0x5e, 0x56, 0x76, 0x94, 0x8e, 0x22, 0x57, 0xb2, 0xe2, 0xdf,
-	0x1c, 0xa0, 0xf0, 0xb4, 0xaa, 0xf5, 0xc2, 0xee, 0x51, 0x08, 0x59, 0x4e, 0x87, 0x37, 0x53, 0x1d,
-	0x5e, 0x9f, 0x19, 0xed, 0x78, 0xff, 0x44, 0xaf, 0xab, 0x63, 0xbd, 0xbe, 0xb6, 0x88, 0x99, 0x74,
-	0xd7, 0x7f, 0xcf, 0xc3, 0xc5, 0x93, 0xf7, 0x22, 0x7d, 0x19, 0x9a, 0x53, 0x8d, 0xb0, 0xff, 0xe3,
-	0x19, 0xd4, 0x86, 0x9c, 0x69, 0x39, 0x23, 0x3f, 0x04, 0x80, 0xcd, 0x05, 0x0f, 0x23, 0xa9, 0x54,
-	0x3b, 0xa8, 0x21, 0x66, 0x8a, 0x34, 0xa7, 0xa3, 0xbb, 0xd8, 0xf2, 0x55, 0x83, 0x41, 0x41, 0x34,
-	0x46, 0x77, 0xa0, 0x10, 0x5a, 0x66, 0x35, 0x76, 0x79, 0xe6, 0x96, 0x5a, 0xa4, 0x82, 0x3e, 0x83,
-	0x82, 0x8c, 0x75, 0xe3, 0xc8, 0xb4, 0x30, 0x85, 0x89, 0xd3, 0x5b, 0x24, 0x92, 0xad, 0x3d, 0x85,
-	0x52, 0xc2, 0xd3, 0x13, 0x4a, 0xf4, 0x76, 0xba, 0x44, 0xaf, 0x4c, 0x2f, 0x51, 0x42, 0x21, 0x0f,
-	0x89, 0x68, 0xb2, 0x50, 0x5f, 0xe5, 0xa0, 0x3a, 0x2d, 0x4f, 0x68, 0x7f, 0x0c, 0x20, 0x36, 0x16,
-	0x4e, 0xf5, 0xf2, 0xa0, 0x42, 0x4b, 0x43, 0xc5, 0x17, 0x8b, 0xbb, 0x32, 0x09, 0x1a, 0x9b, 0x90,
-	0x0b, 0xa0, 0x9e, 0x65, 0x74, 0xae, 0xe0, 0x31, 0x15, 0x34, 0x80, 0xf3, 0xc6, 0xb1, 0xa5, 0x0f,
-	0xcd, 0x1e, 0x35, 0x5c, 0x15, 0xa8, 0x5f, 0xcd, 0xc5, 0xfd, 0x92, 0x13, 0x56, 0x02, 0xf7, 0x52,
-	0x86, 0x63, 0x68, 0xcb, 0x2d, 0x00, 0x6d, 0x48, 0x85, 0x95, 0xc0, 0xd1, 0x07, 0x58, 0x37, 0xb0,
-	0xeb, 0x51, 0xb6, 0x99, 0xf3, 0x88, 0x69, 0xcd, 0x9a, 0x3e, 0x03, 0x25, 0xef, 0xa4, 0x4b, 0xf0,
-	0xea, 0xa9, 0x28, 0x19, 0x1f, 0x3f, 0x51, 0x86, 0xb5, 0xa7, 0xf0, 0xe6, 0x44, 0x18, 0x96, 0x89,
-	0xc7, 0x4f, 0x22, 0x3c, 0x2e, 0x41, 0xbe, 0xbb, 0xb7, 0xbd, 0xd7, 0x7a, 0xb4, 0x57, 0x39, 0x87,
-	0x56, 0xa0, 0xd8, 0x6e, 0x3e, 0x50, 0xe4, 0x2e, 0x01, 0x62, 0x0e, 0xbd, 0x01, 0x25, 0x75, 0xef,
-	0xd9, 0xbe, 0xd6, 0xba, 0xaf, 0x29, 0xed, 0x76, 0x25, 0x43, 0xd7, 0xbb, 0xcd, 0xa6, 0xa2, 0xc8,
-	0x14, 0xa8, 0x63, 0xd0, 0xce, 0x12, 0x3b, 0x8d, 0x7b, 0x2d, 0x8d, 0x80, 0xb6, 0x20, 0xfe, 0xc3,
-	0x41, 0x45, 0xc6, 0x0e, 0xb6, 0x0c, 0x6c, 0xf5, 0x8e, 0x9b, 0xb6, 0xd5, 0x37, 0x07, 0xa8, 0x0d,
-	0x05, 0x17, 0x7f, 0x37, 0x32, 0x5d, 0x4c, 0xfa, 0x87, 0x14, 0xc7, 0xad, 0xa9, 0xfe, 0x8e, 0x2b,
-	0x4b, 0x1a, 0xd3, 0x0c, 0x0a, 0x22, 0x32, 0x84, 0x2e, 0x80, 0xa0, 0xbf, 0xd4, 0xcd, 0xa0, 0x79,
-	0x04, 0x2d, 0x18, 0xd4, 0x2c, 0x58, 0x49, 0x29, 0x9c, 0x10, 0xba, 0xfb, 0xe9, 0xd0, 0xad, 0x9d,
-	0x1a, 0xba, 0xd8, 0x9d, 0x7d, 0xdd, 0xd5, 0x87, 0xd8, 0xc7, 0xae, 0x97, 0x0c, 0xe7, 0xaf, 0x1c,
-	0x64, 0xe9, 0xf5, 0x68, 0x29, 0xb4, 0xf4, 0x69, 0x8a, 0x96, 0xe6, 0xb8, 0xd6, 0x04, 0x44, 0xb4,
-	0x39, 0x46, 0x44, 0x57, 0x4e, 0x57, 0x4c, 0x53, 0xcf, 0x5f, 0x59, 0x28, 0x84, 0xf6, 0xc8, 0x25,
-	0xaf, 0x3f, 0xb2, 0x7a, 0xb4, 0x28, 0x71, 0x9f, 0x45, 0x2d, 0x39, 0x85, 0x94, 0x31, 0xba, 0xb9,
-	0x3e, 0xd3, 0xc9, 0x13, 0x09, 0x66, 0x3b, 0x51, 0x12, 0x01, 0x8e, 0xd5, 0x67, 0x1b, 0x9a, 0x59,
-	0x0a, 0xd9, 0x44, 0x29, 0x24, 0x30, 0x4d, 0x58, 0x1c, 0xd3, 0x26, 0x40, 0x23, 0x77, 0x56, 0xd0,
-	0x40, 0x37, 0x20, 0x4f, 0x1e, 0x48, 0xf6, 0xc8, 0x67, 0xc8, 0xf3, 0xce, 0x04, 0xce, 0xcb, 0xec,
-	0x7d, 0xa4, 0x85, 0x92, 0xaf, 0x9b, 0xed, 0xfe, 0xf3, 0x3e, 0xf9, 0x29, 0x13, 0x40, 0x27, 0xc3,
-	0x9e, 0x7b, 0x63, 0x7c, 0xfa, 0xe1, 0x1c, 0x15, 0xbb, 0x3c, 0x06, 0xbd, 0x09, 0x42, 0x9f, 0xd6,
-	0x37, 0x3f, 0x83, 0x47, 0xb6, 0x88, 0x94, 0x16, 0x08, 0x9f, 0xed, 0x62, 0x2d, 0x7e, 0x9c, 0xc4,
-	0xdb, 0x76, 0xa7, 0x41, 0x71, 0x32, 0x71, 0x01, 0xe6, 0x12, 0x58, 0x9a, 0x11, 0x5f, 0x71, 0x50,
-	0x9d, 0x16, 0x4e, 0xd4, 0x81, 0x2c, 0xd9, 0x80, 0x85, 0xec, 0xcb, 0x85, 0xf3, 0x91, 0xc0, 0x56,
-	0x52, 0x14, 0x1a, 0xb5, 0x46, 0x9b, 0xe7, 0xc8, 0xd4, 0x3d, 0x1a, 0xc2, 0xa2, 0x16, 0x0c, 0xc4,
-	0x4d, 0x28, 0xa7, 0xa5, 0x51, 0x01, 0xb2, 0x72, 0xa3, 0xd3, 0xa8, 0x9c, 0x23, 0x07, 0x69, 0xb6,
-	0xf6, 0x3a, 0x5a, 0x6b, 0xa7, 0xc2, 0x21, 0x04, 0x65, 0xf9, 0xf1, 0x5e, 0x63, 0x57, 0x6d, 0x3e,
-	0x6b, 0x75, 0x3b, 0xfb, 0xdd, 0x4e, 0x25, 0x23, 0xfe, 0xc1, 0x41, 0x39, 0xcd, 0x70, 0xcb, 0x81,
-	0xc7, 0xbb, 0x29, 0x78, 0xfc, 0x68, 0x4e, 0x76, 0x4d, 0x00, 0xa5, 0x32, 0x06, 0x94, 0xd7, 0xe7,
-	0x35, 0x91, 0x86, 0xcc, 0x1f, 0x79, 0x40, 0x93, 0x7b, 0xc4, 0x65, 0xc5, 0x2d, 0x52, 0x56, 0x17,
-	0x21, 0x47, 0xee, 0x60, 0xaa, 0xc1, 0x12, 0xc0, 0x46, 0xa8, 0x15, 0x01, 0x2d, 0x3f, 0x83, 0x32,
-	0x27, 0x5d, 0x39, 0x11, 0x72, 0x45, 0x38, 0x6f, 0x46, 0x52, 0xaa, 0xc1, 0x5e, 0xf0, 0xa9, 0x39,
-	0xb4, 0x06, 0x59, 0xb2, 0x3d, 0x43, 0xcc, 0x19, 0xb7, 0x0a, 0x2a, 0x9a, 0xba, 0xcf, 0xe7, 0xfe,
-	0x47, 0xf7, 0xf9, 0xdf, 0x78, 0xb8, 0x70, 0x52, 0x16, 0xd1, 0xce, 0x18, 0xf6, 0xdc, 0x5c, 0xa8,
-	0x08, 0x96, 0x87, 0x42, 0x31, 0x3f, 0xf1, 0x8b, 0xf3, 0xd3, 0x99, 0xc0, 0x68, 0x92, 0xd5, 0x84,
-	0xb3, 0xb2, 0x9a, 0xf8, 0xfc, 0xb5, 0xde, 0x23, 0x29, 0x58, 0x6e, 0xab, 0xfb, 0xfb, 0x8a, 0x5c,
-	0xc9, 0x89, 0x3f, 0x70, 0x50, 0x4e, 0x83, 0x02, 0x2a, 0x43, 0xc6, 0x0c, 0x5f, 0xc3, 0x19, 0x33,
-	0xfe, 0x85, 0x29, 0x93, 0xf8, 0x85, 0x69, 0x03, 0x8a, 0x3d, 0x17, 0xb3, 0xd4, 0xf0, 0xb3, 0x53,
-	0x13, 0x09, 0x93, 0x37, 0xf7, 0x00, 0x5b, 0x38, 0x20, 0x65, 0x1a, 0x62, 0x5e, 0x4b, 0xcc, 0x88,
-	0x97, 0x41, 0xa0, 0x71, 0x45, 0x55, 0xc8, 0x0f, 0xb1, 0xe7, 0xe9, 0x03, 0xcc, 0x7c, 0x09, 0x87,
-	0x62, 0x0b, 0x04, 0xda, 0xe6, 0x44, 0xc4, 0x1d, 0x59, 0x84, 0xd7, 0x99, 0x73, 0xe1, 0x10, 0xbd,
-	0x0b, 0x45, 0xe2, 0xa7, 0xe7, 0xe8, 0x3d, 0xcc, 0x5e, 0xd9, 0xf1, 0x04, 0x39, 0xa1, 0x2a, 0xb3,
-	0x26, 0xcd, 0xa8, 0xb2, 0xf8, 0x0b, 0x07, 0x2b, 0x71, 0x3a, 0x76, 0x75, 0x87, 0x10, 0x34, 0xfd,
-	0x66, 0x77, 0xea, 0xb5, 0x39, 0xb2, 0xb8, 0xab, 0x3b, 0x12, 0xfd, 0x60, 0xaf, 0x3f, 0xfa, 0x5d,
-	0x7b, 0x02, 0x10, 0x4f, 0x2e, 0xbf, 0x13, 0xb7, 0xa1, 0x1c, 0x2f, 0xec, 0x98, 0x9e, 0x4f, 0x0c,
-	0x26, 0x3d, 0x9f, 0xcf, 0x20, 0xfd, 0x77, 0x2f, 0xff, 0xb5, 0x40, 0x97, 0x0e, 0x72, 0x34, 0x85,
-	0x37, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x41, 0x5e, 0x49, 0xf4, 0xbe, 0x16, 0x00, 0x00,
+	// 2255 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x59, 0xcb, 0x73, 0xdb, 0xc6,
+	0x19, 0x37, 0x48, 0x82, 0x8f, 0x8f, 0x12, 0x43, 0x6f, 0xdd, 0x14, 0xe5, 0xb4, 0x8e, 0x8a, 0x4c,
+	0x13, 0xf7, 0x61, 0xaa, 0x96, 0x9d, 0x58, 0x8e, 0x13, 0x3b, 0x34, 0x09, 0xc7, 0x1c, 0x53, 0xa2,
+	0x0a, 0x51, 0x51, 0x1e, 0x93, 0x78, 0x56, 0xc0, 0x52, 0x46, 0x44, 0x02, 0x2c, 0xb0, 0xb4, 0xad,
+	0x73, 0x2f, 0x9d, 0xe9, 0x7f, 0xd1, 0x63, 0x67, 0x7a, 0xe9, 0xa5, 0xc7, 0xdc, 0xfa, 0x57, 0xb4,
+	0x33, 0xed, 0xb1, 0x87, 0xfe, 0x05, 0xbd, 0x74, 0xf6, 0x01, 0x12, 0xe0, 0x43, 0x04, 0x54, 0x3a,
+	0xbd, 0x48, 0xdc, 0xc5, 0xf7, 0xfd, 0xf6, 0xf5, 0xed, 0xf7, 0xfb, 0xed, 0x2e, 0x7c, 0x7f, 0x74,
+	0x76, 0xba, 0x4d, 0xcf, 0x47, 0x24, 0x10, 0x7f, 0xeb, 0x23, 0xdf, 0xa3, 0x1e, 0xfa, 0x41, 0xdf,
+	0x09, 0x02, 0xc7, 0x73, 0xeb, 0x2f, 0x3d, 0xff, 0xac, 0x3f, 0xf0, 0x5e, 0x06, 0x75, 0xfe, 0xb9,
+	0xf6, 0xd6, 0xa9, 0xe7, 0x9d, 0x0e, 0xc8, 0x36, 0x37, 0x3b, 0x19, 0xf7, 0xb7, 0xa9, 0x33, 0x24,
+	0x01, 0xc5, 0xc3, 0x91, 0xf0, 0xac, 0x5d, 0x9f, 0x35, 0xb0, 0xc7, 0x3e, 0xa6, 0x0c, 0x4a, 0x7c,
+	0xef, 0x9c, 0x3a, 0xf4, 0xf9, 0xf8, 0xa4, 0x6e, 0x79, 0xc3, 0x6d, 0xd9, 0x48, 0xf8, 0xff, 0xe6,
+	0xa4, 0xb1, 0xed, 0x78, 0xaf, 0xec, 0x17, 0x78, 0x30, 0x8e, 0xff, 0x16, 0x68, 0xfa, 0xef, 0x32,
+	0x50, 0x3c, 0x96, 0x5e, 0xa8, 0x09, 0xc5, 0x21, 0xa1, 0xd8, 0xc6, 0x14, 0x6b, 0xca, 0x96, 0x72,
+	0xa3, 0xbc, 0xf3, 0x6e, 0x7d, 0xc9, 0x38, 0xea, 0xdd, 0x93, 0x6f, 0x88, 0x45, 0xf7, 0xa4, 0xb9,
+	0x39, 0x71, 0x44, 0xf7, 0x20, 0x17, 0x8c, 0x88, 0xa5, 0x65, 0x38, 0xc0, 0x4f, 0x97, 0x02, 0x84,
+	0xad, 0x1e, 0x8e, 0x88, 0x65, 0x72, 0x17, 0xf4, 0x10, 0xf2, 0x01, 0xc5, 0x74, 0x1c, 0x68, 0xd9,
+	0x15, 0xad, 0x4f, 0x9c, 0xb9, 0xb9, 0x29, 0xdd, 0xd0, 0x7d, 0x28, 0x3c, 0x77, 0x02, 0xea, 0xf9,
+	0xe7, 0x5a, 0x6e, 0x2b, 0x7b, 0xa3, 0xbc, 0xf3, 0x93, 0x95, 0x08, 0x66, 0xe8, 0xa1, 0xff, 0xbe,
+	0x0c, 0x1b, 0xd1, 0x4e, 0xa1, 0xeb, 0x00, 0x78, 0xe4, 0x7c, 0x4a, 0x7c, 0x06, 0xc0, 0x27, 0xa4,
+	0x64, 0x46, 0x6a, 0xd0, 0x63, 0x50, 0x29, 0x0e, 0xce, 0x02, 0x2d, 0xc3, 0xdb, 0xfa, 0x55, 0xa2,
+	0xa1, 0xd6, 0x7b, 0xcc, 0xc5, 0x70, 0xa9, 0x7f, 0x6e, 0x0a, 0x77, 0xd6, 0x8e, 0x37, 0xa6, 0xa3,
+	0x31, 0x65, 0x9f, 0xf8, 0xd0, 0x4b, 0x66, 0xa4, 0x06, 0x6d, 0x41, 0xd9, 0x26, 0x81, 0xe5, 0x3b,
+	0x23, 0x16, 0x06, 0x5a, 0x8e, 0x1b, 0x44, 0xab, 0x90, 0x06, 0x85, 0xbe, 0xe7, 0x5b, 0xa4, 0x6d,
+	0x6b, 0x2a, 0xff, 0x1a, 0x16, 0x11, 0x82, 0x9c, 0x8b, 0x87, 0x44, 0xcb, 0xf3, 0x6a, 0xfe, 0x1b,
+	0xd5, 0xa0, 0xe8, 0xb8, 0x94, 0xf8, 0x2e, 0x1e, 0x68, 0x85, 0x2d, 0xe5, 0x46, 0xd1, 0x9c, 0x94,
+	0xd1, 0x8f, 0xa0, 0xc4, 0x6c, 0x82, 0x11, 0xb6, 0x88, 0x56, 0xe4, 0x4e, 0xd3, 0x0a, 0xd4, 0x86,
+	0xfc, 0x00, 0x9f, 0x90, 0x41, 0xa0, 0x95, 0xf8, 0x90, 0x6f, 0x25, 0x1b, 0x72, 0x87, 0xfb, 0x88,
+	0x31, 0x4b, 0x00, 0xf4, 0x19, 0x94, 0xb1, 0xeb, 0x7a, 0x94, 0x87, 0x76, 0xa0, 0x01, 0xc7, 0x7b,
+	0x3f, 0x19, 0x5e, 0x63, 0xea, 0x28, 0x40, 0xa3, 0x50, 0x6c, 0xba, 0x1c, 0x77, 0x34, 0xa6, 0x87,
+	0xd6, 0x73, 0x32, 0xc4, 0x5a, 0x59, 0x4c, 0x57, 0xa4, 0x0a, 0x7d, 0x0d, 0x9b, 0x36, 0xe9, 0xe3,
+	0xf1, 0x80, 0xb6, 0x59, 0x6d, 0xa0, 0x6d, 0xf0, 0xd6, 0x77, 0x93, 0xb5, 0xde, 0x8a, 0xba, 0x8a,
+	0xf6, 0xe3, 0x70, 0x6c, 0x9a, 0x2c, 0xcf, 0x0d, 0x68, 0xa0, 0x6d, 0xa6, 0x99, 0xa6, 0x26, 0xf7,
+	0x91, 0xd3, 0x24, 0x00, 0x50, 0x07, 0x0a, 0x22, 0x12, 0x02, 0xad, 0xc2, 0xb1, 0x76, 0x92, 0x61,
+	0x75, 0x85, 0x93, 0x00, 0x0b, 0x21, 0x10, 0x81, 0xab, 0x96, 0xe7, 0x5a, 0x63, 0xdf, 0x27, 0xae,
+	0x75, 0x7e, 0xe0, 0x0d, 0x1c, 0xeb, 0x5c, 0x7b, 0x63, 0x4b, 0xb9, 0x51, 0xd9, 0xb9, 0x9b, 0xb8,
+	0x8f, 0x71, 0x77, 0x73, 0x1e, 0xb1, 0xf6, 0x25, 0xc0, 0x34, 0xca, 0x51, 0x15, 0xb2, 0x67, 0xe4,
+	0x5c, 0xee, 0x1f, 0xf6, 0x13, 0xdd, 0x05, 0x95, 0x27, 0x21, 0x99, 0x23, 0x96, 0x6f, 0x52, 0x86,
+	0xc2, 0xf3, 0x83, 0xb0, 0xff, 0x20, 0xb3, 0xab, 0xd4, 0xee, 0x41, 0x39, 0x12, 0x4f, 0x0b, 0xd0,
+	0xaf, 0x45, 0xd1, 0x4b, 0x51, 0xd7, 0x07, 0x50, 0x9d, 0x0d, 0x9d, 0x54, 0xfe, 0x04, 0xd0, 0xfc,
+	0xe2, 0x2f, 0x40, 0xb8, 0x17, 0x1f, 0xdf, 0xdb, 0xcb, 0xc7, 0xc7, 0xf2, 0xf1, 0xa7, 0xcc, 0x34,
+	0xda, 0xcc, 0xd7, 0x50, 0x8e, 0x84, 0xc2, 0xfa, 0xf1, 0x9f, 0xc1, 0x46, 0x34, 0x3c, 0xd6, 0xde,
+	0x80, 0xfe, 0x08, 0xae, 0xce, 0xc5, 0x09, 0x2a, 0x81, 0xda, 0xe8, 0x74, 0xba, 0xc7, 0xd5, 0x2b,
+	0x08, 0x20, 0xff, 0xb8, 0x6b, 0x3e, 0x6a, 0xb7, 0xaa, 0x0a, 0xab, 0xfe, 0xf5, 0x91, 0x71, 0x64,
+	0x54, 0x33, 0xa8, 0x0c, 0x05, 0xd3, 0x38, 0xe8, 0x34, 0x9a, 0x46, 0x35, 0xab, 0x7f, 0x9b, 0x85,
+	0x4a, 0x3c, 0xcb, 0xa3, 0xc7, 0x13, 0x7a, 0x50, 0x78, 0xc8, 0xd6, 0x13, 0xd2, 0x43, 0x7d, 0x86,
+	0x25, 0x76, 0xa1, 0x34, 0x1e, 0xd9, 0x98, 0x12, 0xbb, 0x41, 0xe5, 0x08, 0x6b, 0x75, 0xc1, 0xba,
+	0xf5, 0x90, 0x75, 0xeb, 0xbd, 0x90, 0x96, 0xcd, 0xa9, 0x31, 0x7a, 0x12, 0x66, 0xfc, 0x6c, 0xd2,
+	0xbd, 0x28, 0x3a, 0x30, 0x9f, 0xf3, 0xef, 0x80, 0x4a, 0x7c, 0xdf, 0xf3, 0x79, 0x36, 0x2f, 0xef,
+	0x5c, 0x5f, 0x8a, 0x64, 0x30, 0x2b, 0x53, 0x18, 0xb3, 0x3c, 0xff, 0x42, 0xd2, 0x11, 0xcb, 0xf3,
+	0x59, 0x33, 0x2c, 0xd6, 0x8e, 0x57, 0x6c, 0xb9, 0xdb, 0xf1, 0x15, 0xfd, 0xf1, 0x85, 0x5b, 0x2e,
+	0xba, 0x96, 0xbb, 0x90, 0x97, 0xd3, 0x0f, 0x90, 0xe7, 0x2b, 0xd5, 0xaa, 0x5e, 0x61, 0xab, 0x66,
+	0x1a, 0x8d, 0xd6, 0xe7, 0xd5, 0x0c, 0x5f, 0xcc, 0x46, 0xbb, 0x63, 0xb4, 0xaa, 0x59, 0xb6, 0x82,
+	0x2d, 0xa3, 0x63, 0xf4, 0x8c, 0x56, 0x35, 0xa7, 0xff, 0x4b, 0x01, 0x14, 0xce, 0x43, 0xdb, 0x7d,
+	0xe1, 0x59, 0x7c, 0xdb, 0xad, 0x47, 0x64, 0x34, 0x63, 0x22, 0x63, 0x7b, 0xe5, 0x3a, 0x4c, 0xdb,
+	0x8f, 0xc8, 0x8d, 0xf6, 0x8c, 0xdc, 0xb8, 0x95, 0x06, 0x26, 0x16, 0x52, 0xfa, 0x1f, 0x0b, 0xf0,
+	0xe6, 0xe2, 0xb6, 0x18, 0xbb, 0x87, 0x70, 0x6d, 0x3b, 0x54, 0x11, 0xd3, 0x1a, 0x74, 0x08, 0x79,
+	0x47, 0xb0, 0x90, 0x90, 0x11, 0xf7, 0x53, 0x0e, 0xa6, 0x1e, 0x25, 0x22, 0x09, 0xc5, 0x28, 0x7e,
+	0x84, 0x7d, 0xe2, 0xd2, 0xb6, 0x2d, 0x05, 0xc5, 0xa4, 0x8c, 0x3e, 0x82, 0x62, 0x88, 0xac, 0xe5,
+	0x56, 0x24, 0xe0, 0x89, 0x4a, 0x9a, 0xb8, 0xa0, 0xf7, 0xa1, 0xd8, 0x22, 0xd8, 0x1e, 0x38, 0x2e,
+	0xd1, 0xd4, 0x95, 0x9b, 0x67, 0x62, 0xcb, 0xc6, 0x29, 0xb5, 0x43, 0xfe, 0x72, 0xe3, 0x5c, 0xa4,
+	0x22, 0xce, 0xa0, 0x42, 0x7d, 0x6c, 0x39, 0xee, 0x69, 0xd3, 0x73, 0x29, 0x79, 0x45, 0xb5, 0x02,
+	0x07, 0x6f, 0xa6, 0x05, 0xef, 0xc5, 0x50, 0x44, 0x23, 0x33, 0xd0, 0x6c, 0x52, 0x2d, 0x3c, 0x18,
+	0x10, 0xbf, 0x6d, 0x4b, 0x69, 0x34, 0x29, 0xa3, 0x1b, 0xf0, 0x46, 0xd8, 0x52, 0x28, 0x18, 0x4b,
+	0x7c, 0x87, 0xce, 0x56, 0xa3, 0x93, 0x45, 0xc2, 0xe7, 0xe3, 0xb4, 0xfd, 0xbd, 0x50, 0x02, 0x31,
+	0x06, 0x79, 0xad, 0x0c, 0xf5, 0x3f, 0x70, 0x70, 0x03, 0xbe, 0xb7, 0x60, 0xae, 0xbf, 0x4b, 0x1a,
+	0xd7, 0xff, 0x5a, 0x02, 0x6d, 0xd9, 0x8e, 0x46, 0x07, 0x33, 0x24, 0xb3, 0x9b, 0x3a, 0x29, 0xac,
+	0x8f, 0x6e, 0xcc, 0x38, 0xdd, 0x7c, 0x98, 0xbe, 0x2b, 0xf3, 0xc4, 0x73, 0x1f, 0xf2, 0x42, 0x0d,
+	0x6a, 0xb9, 0xe4, 0x4b, 0x2f, 0x5d, 0xd0, 0x29, 0x6c, 0xd8, 0xe7, 0x2e, 0x1e, 0x3a, 0x16, 0x07,
+	0xd6, 0xd4, 0xf4, 0x9b, 0x4d, 0xf4, 0xab, 0x15, 0x41, 0x11, 0xdd, 0x8b, 0x01, 0x4f, 0xe9, 0x31,
+	0x9f, 0x86, 0x1e, 0xdb, 0xb0, 0x29, 0x3a, 0xfa, 0x84, 0x60, 0x9b, 0xf8, 0x81, 0x56, 0x48, 0x3e,
+	0xc4, 0xb8, 0x27, 0x63, 0x5a, 0xb6, 0xfb, 0xc9, 0x64, 0xab, 0x87, 0x45, 0xf4, 0x19, 0x14, 0xd8,
+	0x69, 0xc9, 0xa5, 0xe1, 0x21, 0xe8, 0x41, 0xfa, 0xe1, 0xb7, 0x05, 0x80, 0x54, 0xe7, 0x12, 0x0e,
+	0x0d, 0xe7, 0x92, 0x99, 0x48, 0x0e, 0xc6, 0x25, 0xd6, 0x7d, 0x75, 0x3a, 0xab, 0xe1, 0x15, 0x92,
+	0xe1, 0xa3, 0x78, 0x8e, 0x78, 0xf7, 0x42, 0xc9, 0x30, 0xed, 0x41, 0x5c, 0xc9, 0x5e, 0x9d, 0x5b,
+	0xe9, 0x35, 0x8a, 0x93, 0xda, 0x97, 0xb0, 0x11, 0x9d, 0xca, 0x05, 0xd0, 0xef, 0xc5, 0xa1, 0xdf,
+	0x5a, 0x0a, 0x2d, 0x70, 0xd6, 0x9b, 0xa9, 0xf4, 0xaf, 0x26, 0xe2, 0xa9, 0x0c, 0x85, 0xa3, 0xfd,
+	0xa7, 0xfb, 0xdd, 0xe3, 0xfd, 0xea, 0x15, 0xb4, 0x09, 0xa5, 0xc3, 0xe6, 0x13, 0xa3, 0x75, 0xc4,
+	0x54, 0x93, 0x82, 0xde, 0x80, 0x72, 0x7b, 0xff, 0xd9, 0x81, 0xd9, 0xfd, 0xc4, 0x34, 0x0e, 0x0f,
+	0xab, 0x19, 0xfe, 0xfd, 0xa8, 0xd9, 0x34, 0x8c, 0x16, 0x57, 0x55, 0x53, 0x85, 0x95, 0x63, 0x38,
+	0x8d, 0x47, 0x5d, 0x93, 0x29, 0x2c, 0x55, 0xff, 0x8f, 0x02, 0x79, 0xd1, 0x6f, 0xf4, 0x00, 0xf2,
+	0xd8, 0xa2, 0xe1, 0x3d, 0x45, 0x65, 0xe7, 0x9d, 0x15, 0x03, 0xad, 0x37, 0xb8, 0xb5, 0x29, 0xbd,
+	0xd0, 0x9b, 0x90, 0x67, 0xf9, 0xa1, 0x6d, 0xcb, 0x41, 0xc8, 0xd2, 0x74, 0x23, 0x66, 0xd3, 0x6c,
+	0xc4, 0x5d, 0x28, 0x59, 0x3e, 0x91, 0x29, 0x2f, 0xb7, 0x3a, 0xe5, 0x4d, 0x8c, 0xf5, 0x9f, 0x41,
+	0x5e, 0xf4, 0x0c, 0x15, 0x20, 0x6b, 0x1e, 0xb1, 0xd9, 0x2a, 0x42, 0x8e, 0x0d, 0xbf, 0xaa, 0xa0,
+	0x0d, 0x28, 0x36, 0xbb, 0x7b, 0x07, 0x4c, 0x60, 0x56, 0x33, 0xfa, 0xbf, 0x15, 0xa8, 0xb6, 0xc8,
+	0x88, 0xb8, 0x36, 0x3b, 0x65, 0x34, 0x3d, 0xb7, 0xef, 0x9c, 0xa2, 0x43, 0x28, 0xfa, 0xe4, 0x37,
+	0x63, 0xc7, 0x27, 0x2c, 0x81, 0xb3, 0xdd, 0xb3, 0xfc, 0x60, 0x3b, 0xeb, 0x5c, 0x37, 0xa5, 0xa7,
+	0xd8, 0x2f, 0x13, 0x20, 0xb6, 0xc0, 0xf8, 0x25, 0x76, 0x44, 0xf6, 0x56, 0x4d, 0x51, 0xa8, 0xb9,
+	0xb0, 0x19, 0x73, 0x58, 0x10, 0x19, 0x9f, 0xc4, 0xa3, 0xef, 0xd6, 0x85, 0x81, 0x3d, 0xed, 0xce,
+	0x01, 0xf6, 0xf1, 0x90, 0x50, 0xe2, 0x07, 0xd1, 0x60, 0xfa, 0x56, 0x81, 0x1c, 0xb3, 0x5b, 0x8f,
+	0x82, 0x7e, 0x2f, 0xa6, 0xa0, 0x13, 0x1c, 0xc1, 0xb9, 0x39, 0xa3, 0x8f, 0x98, 0x66, 0x7e, 0xfb,
+	0x62, 0xc7, 0xb8, 0x4a, 0xfe, 0x6d, 0x11, 0x8a, 0x21, 0x1e, 0xbb, 0xa6, 0xe9, 0x8f, 0x5d, 0x11,
+	0x85, 0xa4, 0x2f, 0x67, 0x2d, 0x5a, 0x85, 0x8c, 0x19, 0x65, 0x7c, 0x73, 0x65, 0x27, 0x17, 0x6a,
+	0xe1, 0xa7, 0x91, 0x90, 0x10, 0x44, 0xba, 0xbd, 0x1a, 0x68, 0x65, 0x28, 0xe4, 0x22, 0xa1, 0x10,
+	0x21, 0x55, 0x35, 0x3d, 0xa9, 0xce, 0xb1, 0x56, 0xfe, 0xd2, 0xac, 0x75, 0x1b, 0x0a, 0xec, 0x3a,
+	0xd9, 0x1b, 0x53, 0x49, 0x7d, 0x3f, 0x9c, 0xdb, 0x75, 0x2d, 0x79, 0x9b, 0x6c, 0x86, 0x96, 0xe8,
+	0x18, 0x36, 0x22, 0x97, 0x63, 0x81, 0x56, 0xe4, 0x73, 0x74, 0x3b, 0xe1, 0x64, 0x4b, 0x2f, 0x49,
+	0xe2, 0x51, 0x20, 0xa4, 0xc3, 0x86, 0xe8, 0x9e, 0xa8, 0xe0, 0x82, 0xb8, 0x64, 0xc6, 0xea, 0xd8,
+	0xe9, 0xc8, 0xb1, 0xc9, 0x70, 0xe4, 0xb1, 0xa4, 0xa4, 0x01, 0xbf, 0x8d, 0x8c, 0xd4, 0xb0, 0xef,
+	0x43, 0xfc, 0xca, 0x24, 0xd4, 0x77, 0x48, 0xc0, 0xef, 0xf2, 0x54, 0x33, 0x52, 0xc3, 0x46, 0x7c,
+	0x82, 0xad, 0x33, 0xaf, 0xdf, 0xd7, 0x36, 0x56, 0x8e, 0x58, 0x5a, 0xa2, 0x1d, 0xb8, 0xe6, 0x13,
+	0xea, 0x9f, 0xe3, 0x93, 0x01, 0x11, 0x21, 0xda, 0xf4, 0x6c, 0x22, 0x6e, 0xeb, 0x54, 0x73, 0xe1,
+	0x37, 0x74, 0x17, 0x72, 0x2f, 0x9f, 0x13, 0x57, 0xab, 0x24, 0x5f, 0x1c, 0xee, 0xf0, 0xda, 0xb5,
+	0xf8, 0x77, 0x9c, 0x86, 0x6a, 0x0f, 0xe1, 0xea, 0xdc, 0xc2, 0xa7, 0x22, 0xc5, 0x7f, 0x64, 0x84,
+	0xf0, 0x90, 0xcc, 0xf8, 0x68, 0x46, 0x70, 0xff, 0x3c, 0x41, 0x46, 0x59, 0x9f, 0xc4, 0xbe, 0x03,
+	0x6a, 0x9f, 0xe7, 0x9f, 0x55, 0xfc, 0xf6, 0x98, 0x59, 0x99, 0xc2, 0xf8, 0x92, 0xb7, 0x37, 0x1f,
+	0x40, 0xa1, 0xef, 0x3e, 0x71, 0x98, 0x72, 0x14, 0x69, 0x62, 0xeb, 0x82, 0xd6, 0xb8, 0x9d, 0x19,
+	0x3a, 0xe8, 0xbf, 0x8c, 0x2a, 0x89, 0xc3, 0x5e, 0xc3, 0xec, 0xc5, 0xef, 0x61, 0x94, 0x88, 0x4a,
+	0xc8, 0xe8, 0xff, 0x54, 0x40, 0x5b, 0xb6, 0x96, 0xa8, 0x07, 0x39, 0xd6, 0x88, 0x9c, 0xee, 0x8f,
+	0x53, 0x07, 0x43, 0x84, 0x37, 0x59, 0x44, 0x9a, 0x1c, 0x8d, 0x27, 0xc6, 0x81, 0x83, 0x83, 0x70,
+	0xbd, 0x79, 0x81, 0x3d, 0x27, 0x60, 0x9f, 0x3a, 0x7d, 0x6c, 0x51, 0x91, 0x7c, 0x4b, 0xe6, 0xb4,
+	0x42, 0xbf, 0x0f, 0x95, 0x38, 0x16, 0xe3, 0xfa, 0x56, 0xa3, 0xd7, 0xa8, 0x5e, 0x61, 0xc3, 0x6c,
+	0x76, 0xf7, 0x7b, 0x66, 0x97, 0x11, 0x3f, 0x82, 0x4a, 0xeb, 0xf3, 0xfd, 0xc6, 0x5e, 0xbb, 0xf9,
+	0xac, 0x7b, 0xd4, 0x3b, 0x38, 0xea, 0x55, 0x33, 0xfa, 0xdf, 0x15, 0xa8, 0xc4, 0x95, 0xe7, 0x7a,
+	0x88, 0xf1, 0x61, 0x8c, 0x18, 0x7f, 0x91, 0x50, 0xf5, 0x46, 0x28, 0xd2, 0x98, 0xa1, 0xc8, 0x9b,
+	0x49, 0x21, 0xe2, 0x64, 0xf9, 0xb7, 0x2c, 0xa0, 0xf9, 0x36, 0xa6, 0x01, 0xab, 0xa4, 0x09, 0xd8,
+	0x65, 0xf2, 0xae, 0x3b, 0xa1, 0xd8, 0xec, 0x0a, 0xb1, 0x34, 0xdf, 0x95, 0x85, 0x64, 0xab, 0x33,
+	0x32, 0x09, 0xad, 0xda, 0xb6, 0x7c, 0xac, 0x8a, 0xd5, 0xa1, 0x5b, 0x90, 0x63, 0xcd, 0x6b, 0x6a,
+	0x12, 0xb5, 0xcf, 0x4d, 0x63, 0x97, 0x4e, 0xf9, 0x14, 0x97, 0x4e, 0xf1, 0xcb, 0xb7, 0xc2, 0xdc,
+	0xe5, 0x9b, 0x06, 0x05, 0x4c, 0x29, 0x19, 0x8e, 0x28, 0x3f, 0xe6, 0xa9, 0x66, 0x58, 0x7c, 0xdd,
+	0x69, 0x5b, 0xff, 0x43, 0x0e, 0xae, 0x2d, 0x5a, 0x7f, 0xd4, 0x99, 0xc9, 0x87, 0x77, 0x52, 0x85,
+	0xcf, 0xfa, 0x32, 0xe3, 0x54, 0xd3, 0x64, 0xd3, 0x6b, 0x9a, 0xcb, 0x25, 0xc8, 0x39, 0x25, 0xa4,
+	0x5e, 0x5a, 0x09, 0xd5, 0xa0, 0x28, 0x57, 0x52, 0xe8, 0x29, 0xd5, 0x9c, 0x94, 0xd1, 0x87, 0x50,
+	0x1a, 0xe0, 0x80, 0xf2, 0xa6, 0xb5, 0x42, 0xa2, 0x0e, 0x4e, 0x1d, 0xf4, 0x6f, 0x5e, 0xeb, 0x99,
+	0x8e, 0x15, 0x0e, 0x9f, 0xb6, 0x0f, 0x0e, 0x8c, 0x56, 0x35, 0xaf, 0xff, 0x39, 0x0b, 0x95, 0x78,
+	0xa2, 0x42, 0x15, 0xc8, 0x38, 0xe1, 0x35, 0x72, 0xc6, 0x99, 0x3e, 0xf0, 0x66, 0x22, 0x0f, 0xbc,
+	0xb1, 0xe3, 0x57, 0x36, 0xc5, 0xf1, 0x8b, 0xed, 0x97, 0x53, 0xe2, 0x12, 0x21, 0x98, 0xf8, 0xe2,
+	0x65, 0xcd, 0x48, 0x0d, 0x7a, 0x3a, 0xb9, 0xc4, 0x55, 0x57, 0xa8, 0xc4, 0x78, 0xb7, 0x17, 0x5e,
+	0xde, 0x7e, 0x11, 0xbf, 0x09, 0xcd, 0xaf, 0x78, 0x84, 0x9d, 0x41, 0xbc, 0xf8, 0x06, 0xf4, 0xff,
+	0xf7, 0x4a, 0xa8, 0x37, 0x40, 0x35, 0xc2, 0xd7, 0x9a, 0x21, 0x09, 0x02, 0x7c, 0x4a, 0xa4, 0x63,
+	0x58, 0x64, 0xd3, 0x1c, 0x4c, 0xb4, 0xa5, 0x3c, 0x55, 0x46, 0x6a, 0xf4, 0x2e, 0xa8, 0x3c, 0x7d,
+	0x33, 0x08, 0x7f, 0xec, 0x32, 0xa5, 0x2e, 0xdb, 0x09, 0x8b, 0xf1, 0x87, 0xfa, 0xec, 0xec, 0x43,
+	0x7d, 0x05, 0x32, 0xed, 0x96, 0x4c, 0xbe, 0x99, 0x76, 0x4b, 0xff, 0x93, 0x02, 0x05, 0xa9, 0x29,
+	0xa2, 0x87, 0x04, 0x25, 0xf1, 0x21, 0xc1, 0x80, 0x2a, 0x79, 0x35, 0x22, 0x16, 0x25, 0x76, 0xf8,
+	0x51, 0xcb, 0xac, 0xf2, 0x9e, 0x73, 0x41, 0xef, 0x40, 0x65, 0x88, 0x5f, 0x45, 0x1e, 0x07, 0x79,
+	0xd7, 0x55, 0x73, 0xa6, 0x56, 0xff, 0x8b, 0x02, 0x9b, 0xd3, 0xcd, 0xbd, 0x87, 0x47, 0x4c, 0xc3,
+	0xf2, 0xdf, 0xf2, 0x54, 0x7f, 0x2b, 0x41, 0x4e, 0xd8, 0xc3, 0xa3, 0x3a, 0xff, 0x21, 0x2f, 0x40,
+	0xf9, 0xef, 0xda, 0x57, 0x00, 0xd3, 0xca, 0xf5, 0xe7, 0xf5, 0xa7, 0x50, 0x99, 0x7e, 0xe8, 0x38,
+	0x01, 0x65, 0x80, 0xd1, 0x9e, 0x27, 0x03, 0xe4, 0xff, 0x1e, 0x15, 0xbe, 0x50, 0xf9, 0xa7, 0x93,
+	0x3c, 0x9f, 0xdc, 0xdb, 0xff, 0x1d, 0x00, 0x49, 0x52, 0x9e, 0x21, 0x6e, 0x24, 0x00, 0x00,
 }
@@ -0,0 +1,78 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Diagnostic is a validation error that is located in a specific part of the validated value.
+//
+// It allows tooling, such as linters in CI pipelines, to point out the issues without parsing the error messages.
+type Diagnostic struct {
+	// Reason is the type of the issue, which is one of the errors defined in this package.
+	Reason error
+
+	// Detail optionally describes the specific occurrence of the issue.
+	Detail string
+
+	// TaskID is the id of the task that contains the issue, or empty if the issue is not located in a task.
+	TaskID string
+
+	// Field is the dot-separated path to the field that contains the issue, for example "tasks.foo.requires.bar".
+	Field string
+}
+
+func (d Diagnostic) Error() string {
+	if len(d.Detail) == 0 {
+		return d.Reason.Error()
+	}
+	return fmt.Sprintf("%v: %v", d.Reason, d.Detail)
+}
+
+// InTask locates the diagnostics in the validation error of a task spec in the task with the id, by setting their
+// task id and prefixing their field paths with the path of the task.
+func InTask(taskID string, err error) error {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case Error:
+		located := Error{subject: e.subject}
+		for _, nested := range e.errs {
+			located.append(InTask(taskID, nested))
+		}
+		return located
+	case Diagnostic:
+		e.TaskID = taskID
+		e.Field = joinField("tasks."+taskID, e.Field)
+		return e
+	default:
+		return Diagnostic{
+			Reason: err,
+			TaskID: taskID,
+			Field:  "tasks." + taskID,
+		}
+	}
+}
+
+// Diagnostics flattens a validation error into a list of diagnostics. Nested errors that are not a Diagnostic are
+// converted to a Diagnostic without a location.
+func Diagnostics(err error) []Diagnostic {
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case Error:
+		var diagnostics []Diagnostic
+		for _, nested := range e.errs {
+			diagnostics = append(diagnostics, Diagnostics(nested)...)
+		}
+		return diagnostics
+	case Diagnostic:
+		return []Diagnostic{e}
+	default:
+		return []Diagnostic{{Reason: err}}
+	}
+}
+
+func joinField(prefix string, field string) string {
+	return strings.TrimSuffix(prefix+"."+field, ".")
+}
@@ -11,7 +11,10 @@ import (
 
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/graph"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues/controlflow"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues/schema/jsonschema"
+	"github.com/golang/protobuf/ptypes"
 	"gonum.org/v1/gonum/graph/topo"
 )
 
@@ -34,6 +37,17 @@ var (
 	ErrNoWorkflow                   = errors.New("workflow id is required")
 	ErrNoID                         = errors.New("id is required")
 	ErrNoStatus                     = errors.New("status is required")
+	ErrUnresolvedFunction           = errors.New("function could not be resolved")
+	ErrInvalidExpression            = errors.New("expression is invalid")
+	ErrUnknownField                 = errors.New("unknown field")
+	ErrInvalidInputSchema           = errors.New("input schema is invalid")
+	ErrInvalidInput                 = errors.New("input is invalid")
+	ErrInvalidRetry                 = errors.New("retry configuration is invalid")
+	ErrInvalidCondition             = errors.New("condition should be a boolean or an expression")
+	ErrInvalidConst                 = errors.New("constant should not be an expression")
+	ErrInvalidDependency            = errors.New("control dependency should not have artifacts")
+	ErrDanglingReference            = errors.New("expression refers to a task that is not a data dependency")
+	ErrInvalidConcurrencyPolicy     = errors.New("unknown concurrency policy")
 )
 
 type Error struct {
@@ -61,6 +75,10 @@ func (ie Error) Contains(err error) bool {
 			if nestedErr.Contains(err) {
 				return true
 			}
+		case Diagnostic:
+			if nestedErr.Reason == err {
+				return true
+			}
 		default:
 			if e == err {
 				return true
@@ -107,25 +125,54 @@ func WorkflowSpec(spec *types.WorkflowSpec) error {
 	}
 
 	if len(spec.ApiVersion) > 0 && !strings.EqualFold(spec.GetApiVersion(), types.WorkflowAPIVersion) {
-		errs.append(fmt.Errorf("%v: '%v'", ErrInvalidAPIVersion, spec.GetApiVersion()))
+		errs.append(Diagnostic{
+			Reason: ErrInvalidAPIVersion,
+			Detail: fmt.Sprintf("'%v'", spec.GetApiVersion()),
+			Field:  "apiVersion",
+		})
 	}
 
 	if len(spec.Tasks) == 0 {
-		errs.append(ErrWorkflowWithoutTasks)
+		errs.append(Diagnostic{Reason: ErrWorkflowWithoutTasks, Field: "tasks"})
 	}
 
+	// The output task is optional if the workflow declares named outputs.
 	_, ok := spec.Tasks[spec.OutputTask]
-	if !ok {
-		errs.append(ErrInvalidOutputTask)
+	if !ok && (len(spec.OutputTask) > 0 || len(spec.GetOutputs()) == 0) {
+		errs.append(Diagnostic{
+			Reason: ErrInvalidOutputTask,
+			Detail: fmt.Sprintf("'%v'", spec.OutputTask),
+			Field:  "outputTask",
+		})
+	}
+
+	if len(spec.GetInputSchema()) > 0 {
+		if _, err := jsonschema.Parse([]byte(spec.GetInputSchema())); err != nil {
+			errs.append(Diagnostic{Reason: ErrInvalidInputSchema, Detail: err.Error(), Field: "inputSchema"})
+		}
+	}
+
+	if _, ok := types.WorkflowSpec_ConcurrencyPolicy_name[int32(spec.GetConcurrencyPolicy())]; !ok {
+		errs.append(Diagnostic{
+			Reason: ErrInvalidConcurrencyPolicy,
+			Detail: fmt.Sprintf("'%v'", spec.GetConcurrencyPolicy()),
+			Field:  "concurrencyPolicy",
+		})
+	}
+
+	for key, value := range spec.GetConsts() {
+		if value.ValueType() == typedvalues.TypeExpression {
+			errs.append(Diagnostic{Reason: ErrInvalidConst, Field: "consts." + key})
+		}
 	}
 
 	refTable := map[string]*types.TaskSpec{}
 	for taskID, task := range spec.Tasks {
 		if len(taskID) == 0 {
-			errs.append(ErrTaskIDMissing)
+			errs.append(Diagnostic{Reason: ErrTaskIDMissing, Field: "tasks"})
 		}
 
-		errs.append(TaskSpec(task))
+		errs.append(InTask(taskID, TaskSpec(task)))
 
 		_, ok := refTable[taskID]
 		if ok {
@@ -144,20 +191,40 @@ func WorkflowSpec(spec *types.WorkflowSpec) error {
 		// Check for undefined dependencies
 		for depName := range task.Requires {
 			if _, ok := refTable[depName]; !ok {
-				errs.append(fmt.Errorf("%v: '%v->%v'", ErrUndefinedDependency, taskID, depName))
+				errs.append(Diagnostic{
+					Reason: ErrUndefinedDependency,
+					Detail: fmt.Sprintf("'%v->%v'", taskID, depName),
+					TaskID: taskID,
+					Field:  fmt.Sprintf("tasks.%s.requires.%s", taskID, depName),
+				})
 			}
 		}
 	}
 
 	// Check for circular dependencies
 	dg := graph.Parse(graph.NewTaskSpecIterator(spec.Tasks))
-	if len(topo.DirectedCyclesIn(dg)) > 0 {
-		errs.append(ErrCircularDependency)
+	for _, cycle := range topo.DirectedCyclesIn(dg) {
+		var taskIDs []string
+		for _, node := range cycle {
+			if n, ok := node.(*graph.TaskSpecNode); ok {
+				taskIDs = append(taskIDs, n.TaskID())
+			}
+		}
+		if len(taskIDs) == 0 {
+			errs.append(Diagnostic{Reason: ErrCircularDependency, Field: "tasks"})
+			continue
+		}
+		errs.append(Diagnostic{
+			Reason: ErrCircularDependency,
+			Detail: fmt.Sprintf("'%v'", strings.Join(taskIDs, "->")),
+			TaskID: taskIDs[0],
+			Field:  fmt.Sprintf("tasks.%s.requires", taskIDs[0]),
+		})
 	}
 
 	// Check if there are starting points
 	if len(startTasks) == 0 {
-		errs.append(ErrWorkflowWithoutStartTasks)
+		errs.append(Diagnostic{Reason: ErrWorkflowWithoutStartTasks, Field: "tasks"})
 	}
 
 	return errs.getOrNil()
@@ -172,7 +239,47 @@ func TaskSpec(spec *types.TaskSpec) error {
 	}
 
 	if len(spec.FunctionRef) == 0 {
-		errs.append(ErrTaskRequiresFnRef)
+		errs.append(Diagnostic{Reason: ErrTaskRequiresFnRef, Field: "functionRef"})
+	}
+
+	if spec.GetMaxRetries() < 0 {
+		errs.append(Diagnostic{
+			Reason: ErrInvalidRetry,
+			Detail: fmt.Sprintf("maxRetries should not be negative, but was %d", spec.GetMaxRetries()),
+			Field:  "maxRetries",
+		})
+	}
+	if spec.GetBackoff() != nil {
+		if backoff, err := ptypes.Duration(spec.GetBackoff()); err != nil || backoff < 0 {
+			errs.append(Diagnostic{
+				Reason: ErrInvalidRetry,
+				Detail: "backoff should be a positive duration",
+				Field:  "backoff",
+			})
+		}
+	}
+	if when := spec.GetWhen(); when != nil {
+		if t := when.ValueType(); t != typedvalues.TypeBool && t != typedvalues.TypeExpression {
+			errs.append(Diagnostic{Reason: ErrInvalidCondition, Detail: t, Field: "when"})
+		}
+	}
+	for dep, params := range spec.GetRequires() {
+		if params.GetType() == types.TaskDependencyParameters_CONTROL && len(params.GetArtifacts()) > 0 {
+			errs.append(Diagnostic{
+				Reason: ErrInvalidDependency,
+				Detail: fmt.Sprintf("'%v'", dep),
+				Field:  fmt.Sprintf("requires.%s.artifacts", dep),
+			})
+		}
+	}
+	for i, code := range spec.GetRetryableStatusCodes() {
+		if code <= 0 {
+			errs.append(Diagnostic{
+				Reason: ErrInvalidRetry,
+				Detail: fmt.Sprintf("status code should be positive, but was %d", code),
+				Field:  fmt.Sprintf("retryableStatusCodes.%d", i),
+			})
+		}
 	}
 
 	return errs.getOrNil()
@@ -248,6 +355,39 @@ func WorkflowInvocationSpec(spec *types.WorkflowInvocationSpec) error {
 	return errs.getOrNil()
 }
 
+// WorkflowInputs validates the inputs of an invocation against the input schema of the workflow. Each violation of the
+// schema is reported as a Diagnostic that is located at the input field, such as "inputs.user.name".
+func WorkflowInputs(spec *types.WorkflowSpec, inputs map[string]*typedvalues.TypedValue) error {
+	if len(spec.GetInputSchema()) == 0 {
+		return nil
+	}
+	errs := Error{subject: "inputs"}
+	s, err := jsonschema.Parse([]byte(spec.GetInputSchema()))
+	if err != nil {
+		errs.append(Diagnostic{Reason: ErrInvalidInputSchema, Detail: err.Error(), Field: "inputSchema"})
+		return errs.getOrNil()
+	}
+	values := map[string]interface{}{}
+	for key, input := range inputs {
+		v, err := typedvalues.Unwrap(input)
+		if err != nil {
+			field := joinField("inputs", key)
+			errs.append(Diagnostic{Reason: ErrInvalidInput, Detail: fmt.Sprintf("%s: %v", field, err), Field: field})
+			continue
+		}
+		values[key] = v
+	}
+	for _, fieldErr := range s.ValidateValue(values) {
+		field := joinField("inputs", fieldErr.Field)
+		errs.append(Diagnostic{
+			Reason: ErrInvalidInput,
+			Detail: fmt.Sprintf("%s: %s", field, fieldErr.Message),
+			Field:  field,
+		})
+	}
+	return errs.getOrNil()
+}
+
 func TaskInvocationSpec(spec *types.TaskInvocationSpec) error {
 	errs := Error{subject: "TaskInvocationSpec"}
 
@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/golang/protobuf/ptypes/duration"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -48,6 +50,20 @@ func TestWorkflowSpecInvalidOutputTask(t *testing.T) {
 	assert.Error(t, WorkflowSpec(spec))
 }
 
+func TestWorkflowSpecOutputs(t *testing.T) {
+	spec := validSpec()
+	spec.OutputTask = ""
+	assert.Error(t, WorkflowSpec(spec))
+
+	spec.Outputs = map[string]*typedvalues.TypedValue{
+		"result": typedvalues.MustWrap("{$.Tasks.last.Output}"),
+	}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.OutputTask = "nonExistent"
+	assert.Error(t, WorkflowSpec(spec))
+}
+
 func TestWorkflowSpecNoTasks(t *testing.T) {
 	spec := validSpec()
 	spec.Tasks = map[string]*types.TaskSpec{}
@@ -65,3 +81,170 @@ func TestWorkflowSpecInvalidCircularDependency(t *testing.T) {
 	spec.Tasks["first"].Require("last")
 	assert.Error(t, WorkflowSpec(spec))
 }
+
+func TestWorkflowSpecDiagnostics(t *testing.T) {
+	spec := validSpec()
+	spec.Tasks["middle"].FunctionRef = ""
+	spec.Tasks["last"].Require("nonExistentDep")
+
+	err := WorkflowSpec(spec)
+	assert.Error(t, err)
+	assert.True(t, err.(Error).Contains(ErrTaskRequiresFnRef))
+	assert.True(t, err.(Error).Contains(ErrUndefinedDependency))
+
+	diagnostics := Diagnostics(err)
+	assert.Len(t, diagnostics, 2)
+	assert.Contains(t, diagnostics, Diagnostic{
+		Reason: ErrTaskRequiresFnRef,
+		TaskID: "middle",
+		Field:  "tasks.middle.functionRef",
+	})
+	assert.Contains(t, diagnostics, Diagnostic{
+		Reason: ErrUndefinedDependency,
+		Detail: "'last->nonExistentDep'",
+		TaskID: "last",
+		Field:  "tasks.last.requires.nonExistentDep",
+	})
+}
+
+func TestWorkflowSpecDiagnosticsCircularDependency(t *testing.T) {
+	spec := validSpec()
+	spec.Tasks["first"].Require("last")
+
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	var cycles []Diagnostic
+	for _, d := range diagnostics {
+		if d.Reason == ErrCircularDependency {
+			cycles = append(cycles, d)
+		}
+	}
+	assert.Len(t, cycles, 1)
+	assert.NotEmpty(t, cycles[0].TaskID)
+	assert.Contains(t, cycles[0].Detail, "first")
+	assert.Contains(t, cycles[0].Detail, "last")
+}
+
+func TestWorkflowSpecInvalidInputSchema(t *testing.T) {
+	spec := validSpec()
+	spec.InputSchema = `{"type": "text"}`
+
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, ErrInvalidInputSchema, diagnostics[0].Reason)
+	assert.Equal(t, "inputSchema", diagnostics[0].Field)
+}
+
+func TestWorkflowSpecInvalidRetry(t *testing.T) {
+	spec := validSpec()
+	spec.Tasks["middle"].MaxRetries = -1
+	spec.Tasks["middle"].Backoff = &duration.Duration{Seconds: -1}
+	spec.Tasks["middle"].RetryableStatusCodes = []int32{503, 0}
+
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	assert.Len(t, diagnostics, 3)
+	var fields []string
+	for _, d := range diagnostics {
+		assert.Equal(t, ErrInvalidRetry, d.Reason)
+		assert.Equal(t, "middle", d.TaskID)
+		fields = append(fields, d.Field)
+	}
+	assert.ElementsMatch(t, []string{
+		"tasks.middle.maxRetries",
+		"tasks.middle.backoff",
+		"tasks.middle.retryableStatusCodes.1",
+	}, fields)
+
+	spec = validSpec()
+	spec.Tasks["middle"].MaxRetries = 3
+	spec.Tasks["middle"].Backoff = &duration.Duration{Seconds: 1}
+	spec.Tasks["middle"].RetryableStatusCodes = []int32{503}
+	assert.NoError(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecInvalidCondition(t *testing.T) {
+	spec := validSpec()
+	spec.Tasks["middle"].When = typedvalues.MustWrap("yes")
+
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, ErrInvalidCondition, diagnostics[0].Reason)
+	assert.Equal(t, "tasks.middle.when", diagnostics[0].Field)
+
+	spec.Tasks["middle"].When = typedvalues.MustWrap(false)
+	assert.NoError(t, WorkflowSpec(spec))
+	spec.Tasks["middle"].When = typedvalues.MustWrap("{ $.Invocation.Inputs.enabled }")
+	assert.NoError(t, WorkflowSpec(spec))
+}
+
+func TestWorkflowSpecInvalidConst(t *testing.T) {
+	spec := validSpec()
+	spec.Consts = map[string]*typedvalues.TypedValue{
+		"bucket":   typedvalues.MustWrap("uploads"),
+		"endpoint": typedvalues.MustWrap("{$.Invocation.Inputs.endpoint}"),
+	}
+
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	assert.Len(t, diagnostics, 1)
+	assert.Equal(t, ErrInvalidConst, diagnostics[0].Reason)
+	assert.Equal(t, "consts.endpoint", diagnostics[0].Field)
+}
+
+func TestWorkflowSpecInvalidDependency(t *testing.T) {
+	spec := validSpec()
+	spec.Tasks["middle"].Requires["first"] = &types.TaskDependencyParameters{Artifacts: []string{"foo"}}
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.Tasks["middle"].Requires["first"].Type = types.TaskDependencyParameters_CONTROL
+	diagnostics := Diagnostics(WorkflowSpec(spec))
+	if assert.Len(t, diagnostics, 1) {
+		assert.Equal(t, ErrInvalidDependency, diagnostics[0].Reason)
+		assert.Equal(t, "tasks.middle.requires.first.artifacts", diagnostics[0].Field)
+	}
+}
+
+func TestWorkflowSpecConcurrencyPolicy(t *testing.T) {
+	spec := validSpec()
+	spec.ConcurrencyPolicy = types.WorkflowSpec_REPLACE
+	assert.NoError(t, WorkflowSpec(spec))
+
+	spec.ConcurrencyPolicy = 42
+	assert.Equal(t, []Diagnostic{
+		{Reason: ErrInvalidConcurrencyPolicy, Detail: "'42'", Field: "concurrencyPolicy"},
+	}, Diagnostics(WorkflowSpec(spec)))
+}
+
+func TestWorkflowInputs(t *testing.T) {
+	spec := validSpec()
+	assert.NoError(t, WorkflowInputs(spec, nil))
+
+	spec.InputSchema = `{
+		"required": ["user"],
+		"properties": {
+			"user": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {"name": {"type": "string"}}
+			},
+			"count": {"type": "integer"}
+		}
+	}`
+	assert.NoError(t, WorkflowInputs(spec, map[string]*typedvalues.TypedValue{
+		"user": typedvalues.MustWrap(map[string]interface{}{"name": "alice"}),
+	}))
+
+	err := WorkflowInputs(spec, map[string]*typedvalues.TypedValue{
+		"count": typedvalues.MustWrap("many"),
+	})
+	assert.Equal(t, []Diagnostic{
+		{Reason: ErrInvalidInput, Detail: "inputs.count: expected integer, but got string", Field: "inputs.count"},
+		{Reason: ErrInvalidInput, Detail: "inputs.user: field is required", Field: "inputs.user"},
+	}, Diagnostics(err))
+
+	err = WorkflowInputs(spec, map[string]*typedvalues.TypedValue{
+		"user": typedvalues.MustWrap(map[string]interface{}{"name": 42}),
+	})
+	assert.Equal(t, []Diagnostic{
+		{Reason: ErrInvalidInput, Detail: "inputs.user.name: expected string, but got integer",
+			Field: "inputs.user.name"},
+	}, Diagnostics(err))
+}
@@ -62,6 +62,10 @@ func ExponentialBackoff(i int, unit time.Duration) time.Duration {
 	return time.Duration(1<<uint(i)) * unit
 }
 
+func FixedBackoff(i int, unit time.Duration) time.Duration {
+	return unit
+}
+
 func min(l, r time.Duration) time.Duration {
 	if l < r {
 		return l
@@ -0,0 +1,176 @@
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var setRequirement = regexp.MustCompile(`^([^\s!=(),]+)\s+(in|notin)\s*\(([^()]*)\)$`)
+
+// NotInMatcher matches all labels where the label with the key does not exist, or where its value is not in the set
+// of excluded values.
+type NotInMatcher struct {
+	Key    string
+	Values []string
+}
+
+// NotIn matches all labels where the label with the key does not exist, or where its value is not in the set of
+// excluded values.
+func NotIn(key string, values ...string) NotInMatcher {
+	return NotInMatcher{
+		Key:    key,
+		Values: values,
+	}
+}
+
+// Matches returns true if the label does not exist, or if its value is none of the excluded values.
+func (s NotInMatcher) Matches(labels Labels) bool {
+	val, ok := labels.Get(s.Key)
+	if !ok {
+		return true
+	}
+	for _, v := range s.Values {
+		if val == v {
+			return false
+		}
+	}
+	return true
+}
+
+// ExistsMatcher matches all labels where the existence of the label with the key equals Exists.
+type ExistsMatcher struct {
+	Key    string
+	Exists bool
+}
+
+// Exists matches all labels that contain a label with the key, regardless of its value.
+func Exists(key string) ExistsMatcher {
+	return ExistsMatcher{
+		Key:    key,
+		Exists: true,
+	}
+}
+
+// DoesNotExist matches all labels that do not contain a label with the key.
+func DoesNotExist(key string) ExistsMatcher {
+	return ExistsMatcher{
+		Key:    key,
+		Exists: false,
+	}
+}
+
+// Matches returns true if the existence of the label matches the expected existence.
+func (s ExistsMatcher) Matches(labels Labels) bool {
+	_, ok := labels.Get(s.Key)
+	return ok == s.Exists
+}
+
+// ParseSelector parses a label selector into a matcher that selects the labels that meet all requirements of the
+// selector.
+//
+// The selector is a comma-separated list of requirements, which follows the syntax of the Kubernetes label selectors:
+//
+//	key=value, key==value   the label has the value
+//	key!=value              the label does not exist or has a different value
+//	key in (v1, v2)         the label has one of the values
+//	key notin (v1, v2)      the label does not exist or has none of the values
+//	key                     the label exists
+//	!key                    the label does not exist
+//
+// An empty selector selects all labels.
+func ParseSelector(selector string) (Matcher, error) {
+	var matchers []Matcher
+	for _, requirement := range splitRequirements(selector) {
+		requirement = strings.TrimSpace(requirement)
+		if len(requirement) == 0 {
+			return nil, fmt.Errorf("invalid label selector '%s': empty requirement", selector)
+		}
+		matcher, err := parseRequirement(requirement)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector '%s': %v", selector, err)
+		}
+		matchers = append(matchers, matcher)
+	}
+	return And(matchers...), nil
+}
+
+// splitRequirements splits the selector on the commas that are not part of a set of values.
+func splitRequirements(selector string) []string {
+	if len(strings.TrimSpace(selector)) == 0 {
+		return nil
+	}
+	var requirements []string
+	var depth, start int
+	for i, c := range selector {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				requirements = append(requirements, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(requirements, selector[start:])
+}
+
+func parseRequirement(requirement string) (Matcher, error) {
+	if m := setRequirement.FindStringSubmatch(requirement); m != nil {
+		var values []string
+		for _, v := range strings.Split(m[3], ",") {
+			v = strings.TrimSpace(v)
+			if err := validateToken(v); err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		if m[2] == "in" {
+			return In(m[1], values...), nil
+		}
+		return NotIn(m[1], values...), nil
+	}
+
+	if strings.HasPrefix(requirement, "!") && !strings.Contains(requirement, "=") {
+		key := strings.TrimSpace(strings.TrimPrefix(requirement, "!"))
+		if err := validateToken(key); err != nil {
+			return nil, err
+		}
+		return DoesNotExist(key), nil
+	}
+
+	for _, op := range []string{"!=", "==", "="} {
+		if i := strings.Index(requirement, op); i >= 0 {
+			key := strings.TrimSpace(requirement[:i])
+			value := strings.TrimSpace(requirement[i+len(op):])
+			if err := validateToken(key); err != nil {
+				return nil, err
+			}
+			if err := validateToken(value); err != nil {
+				return nil, err
+			}
+			if op == "!=" {
+				return NotIn(key, value), nil
+			}
+			return In(key, value), nil
+		}
+	}
+
+	if err := validateToken(requirement); err != nil {
+		return nil, err
+	}
+	return Exists(requirement), nil
+}
+
+func validateToken(token string) error {
+	if len(token) == 0 {
+		return fmt.Errorf("missing key or value")
+	}
+	if strings.ContainsAny(token, " \t\n!=(),") {
+		return fmt.Errorf("invalid key or value '%s'", token)
+	}
+	return nil
+}
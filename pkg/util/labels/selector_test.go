@@ -0,0 +1,54 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSelector(t *testing.T) {
+	lbls := Set{
+		"team": "payments",
+		"env":  "prod",
+	}
+
+	cases := map[string]bool{
+		"":                                  true,
+		"team=payments":                     true,
+		"team==payments":                    true,
+		"team=payments,env=prod":            true,
+		"team = payments, env = prod":       true,
+		"team=payments,env=staging":         false,
+		"team!=payments":                    false,
+		"tier!=web":                         true,
+		"env in (staging, prod)":            true,
+		"env in (staging)":                  false,
+		"env notin (staging, prod)":         false,
+		"tier notin (web)":                  true,
+		"team":                              true,
+		"tier":                              false,
+		"!tier":                             true,
+		"!team":                             false,
+		"team=payments,env in (prod),!tier": true,
+	}
+	for selector, expected := range cases {
+		matcher, err := ParseSelector(selector)
+		assert.NoError(t, err, selector)
+		assert.Equal(t, expected, matcher.Matches(lbls), selector)
+	}
+}
+
+func TestParseSelectorInvalid(t *testing.T) {
+	for _, selector := range []string{
+		"team=",
+		"=payments",
+		"team=payments,",
+		"env in ()",
+		"env in (prod",
+		"team payments",
+		"!",
+	} {
+		_, err := ParseSelector(selector)
+		assert.Error(t, err, selector)
+	}
+}
@@ -0,0 +1,346 @@
+// Package otlp exports the spans of the Jaeger tracer to an OpenTelemetry collector, or any other tracing backend that
+// accepts the OpenTelemetry protocol (OTLP) over HTTP with JSON encoding.
+//
+// The workflow engine is instrumented with the OpenTracing API. The Transport plugs into the remote reporter of the
+// Jaeger tracer, which batches the finished spans, and converts the spans to OTLP. As a result, the structure of
+// the traces is the same for both the Jaeger agent and OTLP.
+package otlp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/uber/jaeger-client-go"
+	j "github.com/uber/jaeger-client-go/thrift-gen/jaeger"
+)
+
+const (
+	// TracesPath is the path of the traces endpoint of a collector, which is appended to endpoints without a path.
+	TracesPath = "/v1/traces"
+
+	DefaultTimeout   = 10 * time.Second
+	DefaultBatchSize = 100
+
+	instrumentationScope = "github.com/fission/fission-workflows"
+)
+
+// The span kinds of OTLP.
+const (
+	spanKindInternal = 1
+	spanKindServer   = 2
+	spanKindClient   = 3
+	spanKindProducer = 4
+	spanKindConsumer = 5
+)
+
+// statusCodeError is the OTLP status code of spans that are tagged as an error.
+const statusCodeError = 2
+
+var spanKinds = map[string]int{
+	"server":   spanKindServer,
+	"client":   spanKindClient,
+	"producer": spanKindProducer,
+	"consumer": spanKindConsumer,
+}
+
+// Config configures the export of spans with OTLP.
+type Config struct {
+	// Endpoint is the URL of the collector, such as http://otel-collector:4318. If the URL does not have a path,
+	// the spans are sent to the default traces path (/v1/traces).
+	Endpoint string
+
+	// Headers are added to the export requests, such as the API key of a hosted tracing backend.
+	Headers map[string]string
+
+	// Timeout is the timeout of the export requests. If 0, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// BatchSize is the number of spans after which the buffered spans are exported. If 0, DefaultBatchSize is used.
+	BatchSize int
+}
+
+// Transport is a jaeger.Transport that exports spans with OTLP.
+//
+// As required by the jaeger.Transport interface, it is not safe for concurrent use; the remote reporter of the
+// tracer calls it from a single goroutine.
+type Transport struct {
+	url       string
+	headers   map[string]string
+	client    *http.Client
+	batchSize int
+	resource  *resource
+	spans     []*span
+}
+
+// NewTransport creates a Transport that exports the spans to the endpoint of the config.
+func NewTransport(cfg Config) (*Transport, error) {
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid OTLP endpoint %s: expected an http or https URL", cfg.Endpoint)
+	}
+	if len(u.Path) == 0 || u.Path == "/" {
+		u.Path = TracesPath
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Transport{
+		url:       u.String(),
+		headers:   cfg.Headers,
+		client:    &http.Client{Timeout: timeout},
+		batchSize: batchSize,
+	}, nil
+}
+
+// Append converts the span to OTLP and buffers it, exporting the buffered spans once the batch is full.
+func (t *Transport) Append(s *jaeger.Span) (int, error) {
+	if t.resource == nil {
+		t.resource = convertProcess(jaeger.BuildJaegerProcessThrift(s))
+	}
+	t.spans = append(t.spans, convertSpan(jaeger.BuildJaegerThrift(s)))
+	if len(t.spans) >= t.batchSize {
+		return t.Flush()
+	}
+	return 0, nil
+}
+
+// Flush exports the buffered spans. The spans are dropped if the export fails.
+func (t *Transport) Flush() (int, error) {
+	n := len(t.spans)
+	if n == 0 {
+		return 0, nil
+	}
+	req := &exportRequest{
+		ResourceSpans: []*resourceSpans{{
+			Resource: t.resource,
+			ScopeSpans: []*scopeSpans{{
+				Scope: &scope{Name: instrumentationScope},
+				Spans: t.spans,
+			}},
+		}},
+	}
+	t.spans = nil
+	return n, t.export(req)
+}
+
+// Close exports the remaining buffered spans.
+func (t *Transport) Close() error {
+	_, err := t.Flush()
+	return err
+}
+
+func (t *Transport) export(req *exportRequest) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		httpReq.Header.Set(k, v)
+	}
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to export spans: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to export spans: %s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+func convertProcess(process *j.Process) *resource {
+	attrs := []*keyValue{stringAttribute("service.name", process.GetServiceName())}
+	for _, tag := range process.GetTags() {
+		attrs = append(attrs, convertTag(tag))
+	}
+	return &resource{Attributes: attrs}
+}
+
+func convertSpan(s *j.Span) *span {
+	traceID := formatTraceID(s.GetTraceIdHigh(), s.GetTraceIdLow())
+	result := &span{
+		TraceID:           traceID,
+		SpanID:            formatSpanID(s.GetSpanId()),
+		Name:              s.GetOperationName(),
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: formatMicros(s.GetStartTime()),
+		EndTimeUnixNano:   formatMicros(s.GetStartTime() + s.GetDuration()),
+	}
+	if s.GetParentSpanId() != 0 {
+		result.ParentSpanID = formatSpanID(s.GetParentSpanId())
+	}
+
+	for _, tag := range s.GetTags() {
+		switch tag.GetKey() {
+		case "span.kind":
+			if kind, ok := spanKinds[tag.GetVStr()]; ok {
+				result.Kind = kind
+			}
+		case "error":
+			if tag.GetVBool() {
+				result.Status = &status{Code: statusCodeError}
+			}
+			result.Attributes = append(result.Attributes, convertTag(tag))
+		default:
+			result.Attributes = append(result.Attributes, convertTag(tag))
+		}
+	}
+
+	for _, log := range s.GetLogs() {
+		e := &event{
+			TimeUnixNano: formatMicros(log.GetTimestamp()),
+			Name:         "log",
+		}
+		for _, field := range log.GetFields() {
+			if field.GetKey() == "event" && field.GetVType() == j.TagType_STRING {
+				e.Name = field.GetVStr()
+				continue
+			}
+			e.Attributes = append(e.Attributes, convertTag(field))
+		}
+		result.Events = append(result.Events, e)
+	}
+
+	// The parent is already referenced by the parent span id; other references, such as follows-from references to
+	// other traces, are links.
+	for _, ref := range s.GetReferences() {
+		refTraceID := formatTraceID(ref.GetTraceIdHigh(), ref.GetTraceIdLow())
+		if refTraceID == traceID && ref.GetSpanId() == s.GetParentSpanId() {
+			continue
+		}
+		result.Links = append(result.Links, &link{
+			TraceID: refTraceID,
+			SpanID:  formatSpanID(ref.GetSpanId()),
+		})
+	}
+	return result
+}
+
+func convertTag(tag *j.Tag) *keyValue {
+	kv := &keyValue{Key: tag.GetKey()}
+	switch tag.GetVType() {
+	case j.TagType_BOOL:
+		b := tag.GetVBool()
+		kv.Value.BoolValue = &b
+	case j.TagType_LONG:
+		i := strconv.FormatInt(tag.GetVLong(), 10)
+		kv.Value.IntValue = &i
+	case j.TagType_DOUBLE:
+		d := tag.GetVDouble()
+		kv.Value.DoubleValue = &d
+	case j.TagType_BINARY:
+		s := string(tag.GetVBinary())
+		kv.Value.StringValue = &s
+	default:
+		s := tag.GetVStr()
+		kv.Value.StringValue = &s
+	}
+	return kv
+}
+
+func stringAttribute(key, value string) *keyValue {
+	return &keyValue{Key: key, Value: anyValue{StringValue: &value}}
+}
+
+// formatTraceID formats a trace id as the 32 hex characters that the JSON encoding of OTLP expects.
+func formatTraceID(high, low int64) string {
+	return fmt.Sprintf("%016x%016x", uint64(high), uint64(low))
+}
+
+// formatSpanID formats a span id as the 16 hex characters that the JSON encoding of OTLP expects.
+func formatSpanID(id int64) string {
+	return fmt.Sprintf("%016x", uint64(id))
+}
+
+// formatMicros formats a Jaeger timestamp, in microseconds since the epoch, as an OTLP timestamp, in nanoseconds.
+// OTLP encodes 64-bit integers as strings in JSON.
+func formatMicros(micros int64) string {
+	return strconv.FormatInt(micros*int64(time.Microsecond), 10)
+}
+
+// The types below are the subset of the JSON encoding of the OTLP ExportTraceServiceRequest that the Transport uses.
+
+type exportRequest struct {
+	ResourceSpans []*resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   *resource     `json:"resource,omitempty"`
+	ScopeSpans []*scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []*keyValue `json:"attributes,omitempty"`
+}
+
+type scopeSpans struct {
+	Scope *scope  `json:"scope,omitempty"`
+	Spans []*span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string      `json:"traceId"`
+	SpanID            string      `json:"spanId"`
+	ParentSpanID      string      `json:"parentSpanId,omitempty"`
+	Name              string      `json:"name"`
+	Kind              int         `json:"kind"`
+	StartTimeUnixNano string      `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string      `json:"endTimeUnixNano"`
+	Attributes        []*keyValue `json:"attributes,omitempty"`
+	Events            []*event    `json:"events,omitempty"`
+	Links             []*link     `json:"links,omitempty"`
+	Status            *status     `json:"status,omitempty"`
+}
+
+type event struct {
+	TimeUnixNano string      `json:"timeUnixNano"`
+	Name         string      `json:"name"`
+	Attributes   []*keyValue `json:"attributes,omitempty"`
+}
+
+type link struct {
+	TraceID string `json:"traceId"`
+	SpanID  string `json:"spanId"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
@@ -0,0 +1,188 @@
+package otlp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+)
+
+// collector records the export requests that it receives.
+type collector struct {
+	paths    []string
+	headers  []http.Header
+	requests []*exportRequest
+	status   int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.paths = append(c.paths, r.URL.Path)
+	c.headers = append(c.headers, r.Header)
+	body, _ := ioutil.ReadAll(r.Body)
+	req := &exportRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.requests = append(c.requests, req)
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+}
+
+func newTracer() (opentracing.Tracer, func()) {
+	tracer, closer := jaeger.NewTracer("workflows-test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	return tracer, func() { closer.Close() }
+}
+
+func TestTransport(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	transport, err := NewTransport(Config{
+		Endpoint: server.URL,
+		Headers:  map[string]string{"X-Api-Key": "secret"},
+	})
+	assert.NoError(t, err)
+	tracer, closeTracer := newTracer()
+	defer closeTracer()
+
+	parent := tracer.StartSpan("/controller/eval")
+	ext.SpanKindRPCServer.Set(parent)
+	child := tracer.StartSpan("/task/foo", opentracing.ChildOf(parent.Context()))
+	child.SetTag("task", "foo")
+	child.SetTag("attempt", 2)
+	ext.Error.Set(child, true)
+	child.LogKV("event", "failed", "message", "function crashed")
+	child.Finish()
+	parent.Finish()
+
+	n, err := transport.Append(child.(*jaeger.Span))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	n, err = transport.Append(parent.(*jaeger.Span))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, c.requests)
+
+	n, err = transport.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, c.requests, 1)
+	assert.Equal(t, []string{TracesPath}, c.paths)
+	assert.Equal(t, "application/json", c.headers[0].Get("Content-Type"))
+	assert.Equal(t, "secret", c.headers[0].Get("X-Api-Key"))
+
+	rs := c.requests[0].ResourceSpans[0]
+	assert.Equal(t, "service.name", rs.Resource.Attributes[0].Key)
+	assert.Equal(t, "workflows-test", *rs.Resource.Attributes[0].Value.StringValue)
+	spans := rs.ScopeSpans[0].Spans
+	assert.Len(t, spans, 2)
+	childSpan, parentSpan := spans[0], spans[1]
+
+	parentCtx := parent.Context().(jaeger.SpanContext)
+	assert.Len(t, parentSpan.TraceID, 32)
+	assert.Equal(t, parentSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, formatSpanID(int64(parentCtx.SpanID())), parentSpan.SpanID)
+	assert.Equal(t, parentSpan.SpanID, childSpan.ParentSpanID)
+	assert.Empty(t, parentSpan.ParentSpanID)
+	assert.Empty(t, childSpan.Links)
+	assert.Equal(t, spanKindServer, parentSpan.Kind)
+	assert.Equal(t, spanKindInternal, childSpan.Kind)
+	assert.Nil(t, parentSpan.Status)
+	assert.Equal(t, statusCodeError, childSpan.Status.Code)
+	assert.Equal(t, "/task/foo", childSpan.Name)
+	assert.NotEmpty(t, childSpan.StartTimeUnixNano)
+
+	attrs := map[string]anyValue{}
+	for _, kv := range childSpan.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, "foo", *attrs["task"].StringValue)
+	assert.Equal(t, "2", *attrs["attempt"].IntValue)
+	assert.True(t, *attrs["error"].BoolValue)
+
+	assert.Len(t, childSpan.Events, 1)
+	assert.Equal(t, "failed", childSpan.Events[0].Name)
+	assert.Equal(t, "message", childSpan.Events[0].Attributes[0].Key)
+	assert.Equal(t, "function crashed", *childSpan.Events[0].Attributes[0].Value.StringValue)
+
+	// Nothing is exported when there are no buffered spans.
+	n, err = transport.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Len(t, c.requests, 1)
+}
+
+func TestTransportBatch(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	transport, err := NewTransport(Config{
+		Endpoint:  server.URL + "/custom/traces",
+		BatchSize: 2,
+	})
+	assert.NoError(t, err)
+	tracer, closeTracer := newTracer()
+	defer closeTracer()
+
+	for i := 0; i < 3; i++ {
+		s := tracer.StartSpan("op")
+		s.Finish()
+		n, err := transport.Append(s.(*jaeger.Span))
+		assert.NoError(t, err)
+		if i == 1 {
+			assert.Equal(t, 2, n)
+		} else {
+			assert.Equal(t, 0, n)
+		}
+	}
+	assert.Len(t, c.requests, 1)
+	assert.NoError(t, transport.Close())
+	assert.Len(t, c.requests, 2)
+	assert.Equal(t, []string{"/custom/traces", "/custom/traces"}, c.paths)
+}
+
+func TestTransportExportFailure(t *testing.T) {
+	c := &collector{status: http.StatusServiceUnavailable}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	transport, err := NewTransport(Config{Endpoint: server.URL})
+	assert.NoError(t, err)
+	tracer, closeTracer := newTracer()
+	defer closeTracer()
+
+	s := tracer.StartSpan("op")
+	s.Finish()
+	_, err = transport.Append(s.(*jaeger.Span))
+	assert.NoError(t, err)
+	n, err := transport.Flush()
+	assert.Error(t, err)
+	assert.Equal(t, 1, n)
+
+	// The failed spans are dropped.
+	n, err = transport.Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestNewTransportInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "otel-collector:4318", "grpc://otel-collector:4317"} {
+		_, err := NewTransport(Config{Endpoint: endpoint})
+		assert.Error(t, err, endpoint)
+	}
+}
+
+func TestFormatIDs(t *testing.T) {
+	assert.Equal(t, "000000000000000a0000000000000001", formatTraceID(10, 1))
+	assert.Equal(t, "ffffffffffffffff", formatSpanID(-1))
+	assert.Equal(t, "1500000000000000000", formatMicros(1500000000000000))
+}
+
+var _ jaeger.Transport = &Transport{}
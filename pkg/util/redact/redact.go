@@ -0,0 +1,186 @@
+// Package redact masks sensitive fields and headers in the values that are written to logs and traces.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"strings"
+)
+
+// Placeholder replaces the redacted values.
+const Placeholder = "[REDACTED]"
+
+var (
+	// DefaultFields are the field masks that are always redacted.
+	DefaultFields = []string{
+		"inputs.password",
+		"inputs.body.password",
+	}
+
+	// DefaultHeaders are the HTTP headers that are always redacted.
+	DefaultHeaders = []string{
+		"Authorization",
+		"Proxy-Authorization",
+		"Cookie",
+		"Set-Cookie",
+	}
+
+	// DefaultRedactor is used by the controller and the function environments before they log or trace values.
+	DefaultRedactor = NewRedactor(nil, nil)
+)
+
+// Redactor replaces the values of fields and headers that match its masks with the Placeholder.
+//
+// A field mask is a dot-separated path into a value, such as "inputs.body.password". The first segment is the prefix
+// that the caller passes along with the value: "inputs" for the inputs of a task, and "output" for its output. The
+// segment "*" matches any key or index. Segments are matched case-insensitively.
+type Redactor struct {
+	fields  [][]string
+	headers map[string]struct{}
+}
+
+// NewRedactor creates a redactor for the fields and headers, in addition to DefaultFields and DefaultHeaders.
+// The headers are also redacted from the headers input of tasks (inputs.headers.<header>).
+func NewRedactor(fields []string, headers []string) *Redactor {
+	r := &Redactor{
+		headers: map[string]struct{}{},
+	}
+	for _, header := range append(append([]string{}, DefaultHeaders...), headers...) {
+		if len(header) == 0 {
+			continue
+		}
+		r.headers[http.CanonicalHeaderKey(header)] = struct{}{}
+		fields = append(fields, "inputs.headers."+header)
+	}
+	for _, field := range append(append([]string{}, DefaultFields...), fields...) {
+		if len(field) == 0 {
+			continue
+		}
+		r.fields = append(r.fields, strings.Split(strings.ToLower(field), "."))
+	}
+	return r
+}
+
+// Value returns a copy of the value at the path in which the fields that match the masks are redacted. The value is
+// expected to consist of maps, slices and primitives, such as unwrapped typed values or decoded JSON.
+func (r *Redactor) Value(path string, v interface{}) interface{} {
+	return r.redact(strings.Split(strings.ToLower(path), "."), v)
+}
+
+// Map is a convenience function for Value for maps, such as the unwrapped inputs of a task.
+func (r *Redactor) Map(path string, m map[string]interface{}) map[string]interface{} {
+	redacted, _ := r.Value(path, m).(map[string]interface{})
+	return redacted
+}
+
+func (r *Redactor) redact(path []string, v interface{}) interface{} {
+	matched, partial := r.match(path)
+	if matched {
+		return Placeholder
+	}
+	if !partial {
+		return v
+	}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			redacted[k] = r.redact(append(path, strings.ToLower(k)), item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(t))
+		for i, item := range t {
+			redacted[i] = r.redact(append(path, strconv.Itoa(i)), item)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// match returns whether a mask matches the path, and whether a mask could match a path below it.
+func (r *Redactor) match(path []string) (matched bool, partial bool) {
+	for _, mask := range r.fields {
+		if len(mask) < len(path) {
+			continue
+		}
+		prefix := true
+		for i, segment := range path {
+			if mask[i] != "*" && mask[i] != segment {
+				prefix = false
+				break
+			}
+		}
+		if !prefix {
+			continue
+		}
+		if len(mask) == len(path) {
+			return true, true
+		}
+		partial = true
+	}
+	return false, partial
+}
+
+// Header returns a copy of the header in which the values of the masked headers are redacted.
+func (r *Redactor) Header(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for k, vs := range h {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; ok {
+			vs = []string{Placeholder}
+		}
+		redacted[k] = vs
+	}
+	return redacted
+}
+
+// DumpRequest dumps the request like httputil.DumpRequest, with the masked headers redacted. If the body is JSON, the
+// fields matching the masks for inputs.body are redacted as well.
+func (r *Redactor) DumpRequest(req *http.Request) ([]byte, error) {
+	header := req.Header
+	req.Header = r.Header(header)
+	bs, err := httputil.DumpRequest(req, true)
+	req.Header = header
+	if err != nil {
+		return nil, err
+	}
+	return r.dumpBody("inputs.body", bs), nil
+}
+
+// DumpResponse dumps the response like httputil.DumpResponse, with the masked headers redacted. If the body is JSON,
+// the fields matching the masks for output are redacted as well.
+func (r *Redactor) DumpResponse(resp *http.Response) ([]byte, error) {
+	header := resp.Header
+	resp.Header = r.Header(header)
+	bs, err := httputil.DumpResponse(resp, true)
+	resp.Header = header
+	if err != nil {
+		return nil, err
+	}
+	return r.dumpBody("output", bs), nil
+}
+
+func (r *Redactor) dumpBody(path string, dump []byte) []byte {
+	if matched, partial := r.match(strings.Split(path, ".")); !matched && !partial {
+		return dump
+	}
+	separator := []byte("\r\n\r\n")
+	i := bytes.Index(dump, separator)
+	if i < 0 {
+		return dump
+	}
+	body := dump[i+len(separator):]
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return dump
+	}
+	redacted, err := json.Marshal(r.Value(path, v))
+	if err != nil {
+		return dump
+	}
+	return append(dump[:i+len(separator):i+len(separator)], redacted...)
+}
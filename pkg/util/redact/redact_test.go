@@ -0,0 +1,96 @@
+package redact
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_Value(t *testing.T) {
+	r := NewRedactor([]string{"inputs.body.users.*.apiKey", "output.token"}, []string{"X-Api-Key"})
+	inputs := map[string]interface{}{
+		"password": "hunter2",
+		"headers": map[string]interface{}{
+			"authorization": "Bearer foo",
+			"X-Api-Key":     "bar",
+			"Accept":        "application/json",
+		},
+		"body": map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "alice", "apikey": "a"},
+				map[string]interface{}{"name": "bob", "apiKey": "b"},
+			},
+			"password": "hunter2",
+		},
+		"token": "not-masked",
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"password": Placeholder,
+		"headers": map[string]interface{}{
+			"authorization": Placeholder,
+			"X-Api-Key":     Placeholder,
+			"Accept":        "application/json",
+		},
+		"body": map[string]interface{}{
+			"users": []interface{}{
+				map[string]interface{}{"name": "alice", "apikey": Placeholder},
+				map[string]interface{}{"name": "bob", "apiKey": Placeholder},
+			},
+			"password": Placeholder,
+		},
+		"token": "not-masked",
+	}, r.Map("inputs", inputs))
+
+	// The original value should not be modified.
+	assert.Equal(t, "hunter2", inputs["password"])
+
+	assert.Equal(t, map[string]interface{}{"token": Placeholder},
+		r.Value("output", map[string]interface{}{"token": "foo"}))
+	assert.Equal(t, "foo", r.Value("output", "foo"))
+}
+
+func TestRedactor_DumpRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://example.com/fn",
+		strings.NewReader(`{"user":"alice","password":"hunter2"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer foo")
+	req.Header.Set("Content-Type", "application/json")
+
+	bs, err := DefaultRedactor.DumpRequest(req)
+	require.NoError(t, err)
+	dump := string(bs)
+	assert.NotContains(t, dump, "foo")
+	assert.NotContains(t, dump, "hunter2")
+	assert.Contains(t, dump, "Authorization: "+Placeholder)
+	assert.Contains(t, dump, `"user":"alice"`)
+
+	// The request itself should not be modified.
+	assert.Equal(t, "Bearer foo", req.Header.Get("Authorization"))
+	body, err := ioutil.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "hunter2")
+}
+
+func TestRedactor_DumpResponse(t *testing.T) {
+	r := NewRedactor([]string{"output.token"}, nil)
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Set-Cookie": []string{"session=foo"}},
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"token":"s3cr3t"}`)),
+	}
+
+	bs, err := r.DumpResponse(resp)
+	require.NoError(t, err)
+	dump := string(bs)
+	assert.NotContains(t, dump, "session=foo")
+	assert.NotContains(t, dump, "s3cr3t")
+	assert.Equal(t, "session=foo", resp.Header.Get("Set-Cookie"))
+}
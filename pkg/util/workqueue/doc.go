@@ -5,6 +5,8 @@
 // - workqueue.go 		- Added bool return value whether value was added.
 // - workqueue.go 		- Added Identifier interface to allow items in workqueue to deviate from the associated ID.
 // - workqueue.go 		- Added Replace field to allow subsequent Adds of the same ID to simply replace the value.
+// - workqueue.go 		- Added SetMaxSize to change the MaxSize of a running workqueue.
+// - workqueue.go 		- Added Prioritized interface to process items with a higher priority first.
 // - delaying_queue.go 	- added non-blocking TryAddAfter.
 // - all 				- Replaced t and set types with interface{} and map[interface{}]interface{}
 //
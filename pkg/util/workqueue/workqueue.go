@@ -17,6 +17,7 @@ limitations under the License.
 package workqueue
 
 import (
+	"sort"
 	"sync"
 )
 
@@ -27,6 +28,7 @@ const (
 type Interface interface {
 	Add(item interface{}) (accepted bool)
 	Len() int
+	SetMaxSize(maxSize int)
 	Get() (item interface{}, shutdown bool)
 	Done(item interface{})
 	ShutDown()
@@ -41,6 +43,7 @@ func NewWorkQueue(maxSize int, replace bool) *Type {
 	return &Type{
 		MaxSize:    maxSize,
 		Replace:    replace,
+		queue:      make(map[int][]interface{}),
 		dirty:      make(map[interface{}]interface{}),
 		processing: make(map[interface{}]interface{}),
 		cond:       sync.NewCond(&sync.Mutex{}),
@@ -57,10 +60,18 @@ type Type struct {
 	MaxSize int
 	Replace bool
 
-	// queue defines the order in which we will work on items. Every
-	// element of queue should be in the dirty set and not in the
-	// processing set.
-	queue []interface{}
+	// queue defines the order in which we will work on items, per
+	// priority. Every element of queue should be in the dirty set and not
+	// in the processing set.
+	queue map[int][]interface{}
+
+	// priorities are the priorities of queue in descending order.
+	priorities []int
+
+	// queued and bounded are the number of elements in queue, and the
+	// number of those that count towards MaxSize.
+	queued  int
+	bounded int
 
 	// dirty defines all of the items that need to be processed.
 	dirty map[interface{}]interface{}
@@ -92,7 +103,8 @@ func (q *Type) Add(item interface{}) (accepted bool) {
 		return true
 	}
 
-	if len(q.queue) >= q.MaxSize {
+	priority := getPriority(item)
+	if priority <= 0 && q.bounded >= q.MaxSize {
 		return false
 	}
 
@@ -101,18 +113,60 @@ func (q *Type) Add(item interface{}) (accepted bool) {
 		return true
 	}
 
-	q.queue = append(q.queue, key)
+	q.push(key, priority)
 	q.cond.Signal()
 	return true
 }
 
+// push appends the key to the queue of the priority.
+func (q *Type) push(key interface{}, priority int) {
+	if _, ok := q.queue[priority]; !ok {
+		i := sort.Search(len(q.priorities), func(i int) bool {
+			return q.priorities[i] < priority
+		})
+		q.priorities = append(q.priorities, 0)
+		copy(q.priorities[i+1:], q.priorities[i:])
+		q.priorities[i] = priority
+	}
+	q.queue[priority] = append(q.queue[priority], key)
+	q.queued++
+	if priority <= 0 {
+		q.bounded++
+	}
+}
+
+// pop removes the first key of the queue with the highest priority.
+func (q *Type) pop() interface{} {
+	for _, priority := range q.priorities {
+		keys := q.queue[priority]
+		if len(keys) == 0 {
+			continue
+		}
+		q.queue[priority] = keys[1:]
+		q.queued--
+		if priority <= 0 {
+			q.bounded--
+		}
+		return keys[0]
+	}
+	return nil
+}
+
 // Len returns the current queue length, for informational purposes only. You
 // shouldn't e.g. gate a call to Add() or Get() on Len() being a particular
 // value, that can't be synchronized properly.
 func (q *Type) Len() int {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	return len(q.queue)
+	return q.queued
+}
+
+// SetMaxSize changes the maximum number of queued items. Items that are queued already are kept, even if they exceed
+// the new size.
+func (q *Type) SetMaxSize(maxSize int) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.MaxSize = maxSize
 }
 
 // Get blocks until it can return an item to be processed. If shutdown = true,
@@ -121,16 +175,15 @@ func (q *Type) Len() int {
 func (q *Type) Get() (item interface{}, shutdown bool) {
 	q.cond.L.Lock()
 	defer q.cond.L.Unlock()
-	for len(q.queue) == 0 && !q.shuttingDown {
+	for q.queued == 0 && !q.shuttingDown {
 		q.cond.Wait()
 	}
-	if len(q.queue) == 0 {
+	if q.queued == 0 {
 		// We must be shutting down.
 		return nil, true
 	}
 
-	var key interface{}
-	key, q.queue = q.queue[0], q.queue[1:]
+	key := q.pop()
 	item = q.dirty[key]
 	q.processing[key] = item
 	delete(q.dirty, key)
@@ -147,8 +200,8 @@ func (q *Type) Done(item interface{}) {
 
 	key := getKey(item)
 	delete(q.processing, key)
-	if _, ok := q.dirty[key]; ok {
-		q.queue = append(q.queue, key)
+	if dirty, ok := q.dirty[key]; ok {
+		q.push(key, getPriority(dirty))
 		q.cond.Signal()
 	}
 }
@@ -174,6 +227,20 @@ type Identifier interface {
 	ID() interface{}
 }
 
+// Prioritized is implemented by items that should be processed before or after other items. Items with a higher
+// priority are processed first; items without a priority have priority 0. Items with a positive priority do not count
+// towards MaxSize, so that urgent items are never rejected because of a backlog of regular items.
+type Prioritized interface {
+	GetPriority() int
+}
+
+func getPriority(item interface{}) int {
+	if prioritized, ok := item.(Prioritized); ok {
+		return prioritized.GetPriority()
+	}
+	return 0
+}
+
 func getKey(item interface{}) interface{} {
 	if identifier, ok := item.(Identifier); ok && identifier.ID() != nil {
 		return identifier.ID()
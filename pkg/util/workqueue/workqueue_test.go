@@ -201,3 +201,54 @@ func TestInsertItemsWithIdentifiers(t *testing.T) {
 		t.Errorf("Expected queue to be empty. Has %v items", a)
 	}
 }
+
+type PrioritizedItem struct {
+	key      string
+	priority int
+}
+
+func (i *PrioritizedItem) ID() interface{} {
+	return i.key
+}
+
+func (i *PrioritizedItem) GetPriority() int {
+	return i.priority
+}
+
+func TestPriorities(t *testing.T) {
+	q := workqueue.NewWorkQueue(2, false)
+	low := &PrioritizedItem{key: "low", priority: -1}
+	normal1 := &PrioritizedItem{key: "normal1"}
+	normal2 := &PrioritizedItem{key: "normal2"}
+	high1 := &PrioritizedItem{key: "high1", priority: 1}
+	high2 := &PrioritizedItem{key: "high2", priority: 2}
+
+	for _, item := range []*PrioritizedItem{low, normal1} {
+		if !q.Add(item) {
+			t.Errorf("Expected %v to be accepted", item.key)
+		}
+	}
+	// The queue is full, but items with a positive priority are still accepted.
+	if q.Add(normal2) {
+		t.Errorf("Expected %v to be rejected", normal2.key)
+	}
+	for _, item := range []*PrioritizedItem{high1, high2} {
+		if !q.Add(item) {
+			t.Errorf("Expected %v to be accepted", item.key)
+		}
+	}
+	if a := q.Len(); a != 4 {
+		t.Errorf("Expected queue to have 4 items. Has %v items", a)
+	}
+
+	for _, expected := range []*PrioritizedItem{high2, high1, normal1, low} {
+		i, _ := q.Get()
+		if i != expected {
+			t.Errorf("Expected %v, got %v", expected.key, i.(*PrioritizedItem).key)
+		}
+		q.Done(i)
+	}
+	if a := q.Len(); a != 0 {
+		t.Errorf("Expected queue to be empty. Has %v items", a)
+	}
+}
@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var poolTasks = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "workflows",
+	Subsystem: "worker_pool",
+	Name:      "tasks_total",
+	Help:      "Number of tasks submitted to the workers, by worker and result (executed, unavailable or error)",
+}, []string{"worker", "result"})
+
+func init() {
+	prometheus.MustRegister(poolTasks)
+}
+
+// Pool is a function runtime that executes the tasks on a set of remote workers.
+//
+// Each task is submitted to the worker with the fewest tasks in progress. If a worker is unavailable, at its capacity,
+// or does not support the runtime of the function, the task is submitted to the next worker.
+type Pool struct {
+	workers []*remoteWorker
+	next    *atomic.Uint32
+}
+
+type remoteWorker struct {
+	addr   string
+	conn   *grpc.ClientConn
+	client WorkerClient
+	active *atomic.Int32
+}
+
+// NewPool creates a pool of the workers at the addresses. The connections to the workers are established lazily, so
+// workers that are not running yet are skipped until they are.
+func NewPool(addrs []string, dialOpts ...grpc.DialOption) (*Pool, error) {
+	if len(addrs) == 0 {
+		return nil, ErrNoWorkers
+	}
+	if len(dialOpts) == 0 {
+		dialOpts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+	pool := &Pool{
+		next: atomic.NewUint32(0),
+	}
+	for _, addr := range addrs {
+		conn, err := grpc.Dial(addr, dialOpts...)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to connect to worker %s: %v", addr, err)
+		}
+		pool.workers = append(pool.workers, &remoteWorker{
+			addr:   addr,
+			conn:   conn,
+			client: NewWorkerClient(conn),
+			active: atomic.NewInt32(0),
+		})
+	}
+	return pool, nil
+}
+
+// Invoke executes the task on one of the workers in a blocking way.
+func (p *Pool) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	cfg := fnenv.ParseInvokeOptions(opts)
+	ctx := cfg.Ctx
+	if deadline, err := ptypes.Timestamp(spec.GetDeadline()); err == nil {
+		var cancel func()
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	var lastErr error
+	for _, worker := range p.candidates() {
+		worker.active.Inc()
+		result, err := worker.client.Execute(ctx, spec)
+		worker.active.Dec()
+		if err == nil {
+			poolTasks.WithLabelValues(worker.addr, "executed").Inc()
+			return result, nil
+		}
+		switch status.Code(err) {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Unimplemented:
+			poolTasks.WithLabelValues(worker.addr, "unavailable").Inc()
+			log.Debugf("Worker %s cannot execute task %s: %v", worker.addr, spec.GetTask().ID(), err)
+			lastErr = err
+		default:
+			poolTasks.WithLabelValues(worker.addr, "error").Inc()
+			return nil, fmt.Errorf("failed to execute task on worker %s: %v", worker.addr, err)
+		}
+	}
+	return nil, fmt.Errorf("%v: %v", ErrNoWorkers, lastErr)
+}
+
+// candidates returns the workers in the order in which a task is submitted to them: the workers with the fewest
+// tasks in progress first. Workers with the same number of tasks in progress are ordered round-robin.
+func (p *Pool) candidates() []*remoteWorker {
+	start := int(p.next.Inc())
+	workers := make([]*remoteWorker, len(p.workers))
+	active := make(map[*remoteWorker]int32, len(p.workers))
+	for i := range workers {
+		worker := p.workers[(start+i)%len(p.workers)]
+		workers[i] = worker
+		active[worker] = worker.active.Load()
+	}
+	sort.SliceStable(workers, func(i, j int) bool {
+		return active[workers[i]] < active[workers[j]]
+	})
+	return workers
+}
+
+// Close closes the connections to the workers.
+func (p *Pool) Close() error {
+	var err error
+	for _, worker := range p.workers {
+		if cerr := worker.conn.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	return err
+}
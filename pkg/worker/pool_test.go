@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"net"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_Invoke(t *testing.T) {
+	rt1 := &testRuntime{name: "w1", started: make(chan string, 1), release: make(chan struct{})}
+	rt2 := &testRuntime{name: "w2", started: make(chan string, 1), release: make(chan struct{})}
+	addr1, stop1 := startTestWorker(t, rt1, Config{MaxParallelism: 1})
+	defer stop1()
+	addr2, stop2 := startTestWorker(t, rt2, Config{MaxParallelism: 1})
+	defer stop2()
+	pool, err := NewPool([]string{addr1, addr2})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	// The tasks are spread over the workers.
+	var executedBy []string
+	for i := 0; i < 2; i++ {
+		result, err := pool.Invoke(newSpec("test", "echo"))
+		require.NoError(t, err)
+		executedBy = append(executedBy, typedvalues.MustUnwrap(result.GetOutput()).(string))
+	}
+	assert.ElementsMatch(t, []string{"w1/echo", "w2/echo"}, executedBy)
+
+	// A busy worker is skipped.
+	done := make(chan struct{})
+	go func() {
+		pool.Invoke(newSpec("test", "block"))
+		close(done)
+	}()
+	var busy string
+	select {
+	case busy = <-rt1.started:
+	case busy = <-rt2.started:
+	}
+	for i := 0; i < 3; i++ {
+		result, err := pool.Invoke(newSpec("test", "echo"))
+		require.NoError(t, err)
+		assert.NotContains(t, typedvalues.MustUnwrap(result.GetOutput()), busy)
+	}
+	close(rt1.release)
+	close(rt2.release)
+	<-done
+
+	// Errors other than the unavailability of a worker are returned.
+	_, err = pool.Invoke(newSpec("test", "fail"))
+	assert.Error(t, err)
+
+	// If no worker supports the runtime, the task cannot be executed.
+	_, err = pool.Invoke(newSpec("unknown", "echo"))
+	assert.Error(t, err)
+}
+
+func TestPool_Unavailable(t *testing.T) {
+	// Reserve an address on which no worker is listening.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	unavailable := lis.Addr().String()
+	lis.Close()
+
+	rt := &testRuntime{name: "w1"}
+	addr, stop := startTestWorker(t, rt, Config{})
+	defer stop()
+	pool, err := NewPool([]string{unavailable, addr})
+	require.NoError(t, err)
+	defer pool.Close()
+	for i := 0; i < 2; i++ {
+		result, err := pool.Invoke(newSpec("test", "echo"))
+		require.NoError(t, err)
+		assert.Equal(t, "w1/echo", typedvalues.MustUnwrap(result.GetOutput()))
+	}
+
+	pool, err = NewPool([]string{unavailable})
+	require.NoError(t, err)
+	defer pool.Close()
+	_, err = pool.Invoke(newSpec("test", "echo"))
+	assert.Error(t, err)
+
+	_, err = NewPool(nil)
+	assert.Equal(t, ErrNoWorkers, err)
+}
@@ -0,0 +1,92 @@
+// Package worker executes the tasks of invocations in worker processes, separate from the invocation controller.
+//
+// The Server serves the Worker service of worker.proto, which invokes the functions of tasks with the function
+// runtimes of the worker process. The Pool is a function runtime that the invocation controller uses to delegate the
+// invocations of functions to a set of workers. As the controller only keeps track of the state of the invocations,
+// the throughput of the task execution can be scaled by adding workers, without scaling the controller.
+package worker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxParallelism is the default maximum number of tasks that a worker executes at the same time.
+const DefaultMaxParallelism = 100
+
+var (
+	ErrNoWorkers = errors.New("worker: no worker available to execute the task")
+
+	log = logrus.WithField("component", "worker")
+
+	activeTasks = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "workflows",
+		Subsystem: "worker",
+		Name:      "active_tasks",
+		Help:      "Number of tasks that the worker is executing",
+	})
+
+	rejectedTasks = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "workflows",
+		Subsystem: "worker",
+		Name:      "rejected_tasks_total",
+		Help:      "Number of tasks that the worker rejected, because it was executing the maximum number of tasks",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(activeTasks, rejectedTasks)
+}
+
+// Config contains the configuration of a worker.
+type Config struct {
+	// MaxParallelism is the maximum number of tasks that the worker executes at the same time. Additional tasks are
+	// rejected, so that they are executed by other workers. If 0, DefaultMaxParallelism is used.
+	MaxParallelism int
+}
+
+// Server executes the tasks that the invocation controller submits to the worker.
+type Server struct {
+	runtimes map[string]fnenv.Runtime
+	slots    chan struct{}
+}
+
+// NewServer creates a worker that executes tasks with the function runtimes, indexed by the runtime of the function
+// references.
+func NewServer(runtimes map[string]fnenv.Runtime, cfg Config) *Server {
+	if cfg.MaxParallelism <= 0 {
+		cfg.MaxParallelism = DefaultMaxParallelism
+	}
+	return &Server{
+		runtimes: runtimes,
+		slots:    make(chan struct{}, cfg.MaxParallelism),
+	}
+}
+
+func (s *Server) Execute(ctx context.Context, spec *types.TaskInvocationSpec) (*types.TaskInvocationStatus, error) {
+	runtime, ok := s.runtimes[spec.GetFnRef().GetRuntime()]
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "worker does not support function runtime '%s'",
+			spec.GetFnRef().GetRuntime())
+	}
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		rejectedTasks.Inc()
+		return nil, status.Errorf(codes.ResourceExhausted, "worker is executing the maximum of %d tasks",
+			cap(s.slots))
+	}
+
+	activeTasks.Inc()
+	defer activeTasks.Dec()
+	log.Debugf("Executing task %s of invocation %s", spec.GetTask().ID(), spec.GetInvocationId())
+	return runtime.Invoke(spec, fnenv.WithContext(ctx))
+}
@@ -0,0 +1,127 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/worker/worker.proto
+
+/*
+Package worker is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/worker/worker.proto
+
+It has these top-level messages:
+*/
+package worker
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import fission_workflows_types1 "github.com/fission/fission-workflows/pkg/types"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Worker service
+
+type WorkerClient interface {
+	// Execute invokes the function of the task to completion, and returns the status of the task. Failures of the
+	// function itself are returned as a FAILED status. If the worker is at its capacity, a ResourceExhausted error is
+	// returned, in which case the task can be executed by another worker.
+	Execute(ctx context.Context, in *fission_workflows_types1.TaskInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.TaskInvocationStatus, error)
+}
+
+type workerClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewWorkerClient(cc *grpc.ClientConn) WorkerClient {
+	return &workerClient{cc}
+}
+
+func (c *workerClient) Execute(ctx context.Context, in *fission_workflows_types1.TaskInvocationSpec, opts ...grpc.CallOption) (*fission_workflows_types1.TaskInvocationStatus, error) {
+	out := new(fission_workflows_types1.TaskInvocationStatus)
+	err := grpc.Invoke(ctx, "/fission.workflows.worker.Worker/Execute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Worker service
+
+type WorkerServer interface {
+	// Execute invokes the function of the task to completion, and returns the status of the task. Failures of the
+	// function itself are returned as a FAILED status. If the worker is at its capacity, a ResourceExhausted error is
+	// returned, in which case the task can be executed by another worker.
+	Execute(context.Context, *fission_workflows_types1.TaskInvocationSpec) (*fission_workflows_types1.TaskInvocationStatus, error)
+}
+
+func RegisterWorkerServer(s *grpc.Server, srv WorkerServer) {
+	s.RegisterService(&_Worker_serviceDesc, srv)
+}
+
+func _Worker_Execute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(fission_workflows_types1.TaskInvocationSpec)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).Execute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/fission.workflows.worker.Worker/Execute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).Execute(ctx, req.(*fission_workflows_types1.TaskInvocationSpec))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Worker_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "fission.workflows.worker.Worker",
+	HandlerType: (*WorkerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Execute",
+			Handler:    _Worker_Execute_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/worker/worker.proto",
+}
+
+func init() { proto.RegisterFile("pkg/worker/worker.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 158 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2f, 0xc8, 0x4e, 0xd7,
+	0x2f, 0xcf, 0x2f, 0xca, 0x4e, 0x2d, 0x82, 0x52, 0x7a, 0x05, 0x45, 0xf9, 0x25, 0xf9, 0x42, 0x12,
+	0x69, 0x99, 0xc5, 0xc5, 0x99, 0xf9, 0x79, 0x7a, 0x20, 0xd1, 0xb4, 0x9c, 0xfc, 0xf2, 0x62, 0x3d,
+	0x88, 0xbc, 0x94, 0x55, 0x7a, 0x66, 0x49, 0x46, 0x69, 0x92, 0x5e, 0x72, 0x7e, 0xae, 0x3e, 0x54,
+	0x11, 0x8c, 0xd6, 0x85, 0x2b, 0xd6, 0x07, 0x99, 0x5b, 0x52, 0x59, 0x90, 0x5a, 0x0c, 0x21, 0x21,
+	0xa6, 0x1a, 0x15, 0x72, 0xb1, 0x85, 0x83, 0x4d, 0x11, 0x4a, 0xe7, 0x62, 0x77, 0xad, 0x48, 0x4d,
+	0x2e, 0x2d, 0x49, 0x15, 0xd2, 0xd6, 0xc3, 0xb4, 0x0b, 0xa2, 0x29, 0x24, 0xb1, 0x38, 0xdb, 0x33,
+	0xaf, 0x2c, 0x3f, 0x39, 0xb1, 0x24, 0x33, 0x3f, 0x2f, 0xb8, 0x20, 0x35, 0x59, 0x4a, 0x97, 0x58,
+	0xc5, 0x25, 0x89, 0x25, 0xa5, 0xc5, 0x4a, 0x0c, 0x4e, 0x1c, 0x51, 0x6c, 0x10, 0x87, 0x27, 0xb1,
+	0x81, 0xdd, 0x60, 0x0c, 0x18, 0x00, 0x37, 0x01, 0x2a, 0x25, 0xf4, 0x00, 0x00, 0x00,
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/types"
+	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testRuntime returns the function id with the name of the worker as the output, or fails for the "fail" function.
+// Invocations of the "block" function block until release is closed.
+type testRuntime struct {
+	name    string
+	started chan string
+	release chan struct{}
+}
+
+func (rt *testRuntime) Invoke(spec *types.TaskInvocationSpec, opts ...fnenv.InvokeOption) (*types.TaskInvocationStatus,
+	error) {
+	switch spec.GetFnRef().GetID() {
+	case "fail":
+		return nil, errors.New("runtime failure")
+	case "block":
+		rt.started <- rt.name
+		<-rt.release
+	}
+	return &types.TaskInvocationStatus{
+		Status: types.TaskInvocationStatus_SUCCEEDED,
+		Output: typedvalues.MustWrap(rt.name + "/" + spec.GetFnRef().GetID()),
+	}, nil
+}
+
+func startTestWorker(t *testing.T, runtime *testRuntime, cfg Config) (addr string, stop func()) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := grpc.NewServer()
+	RegisterWorkerServer(srv, NewServer(map[string]fnenv.Runtime{"test": runtime}, cfg))
+	go srv.Serve(lis)
+	return lis.Addr().String(), srv.Stop
+}
+
+func newSpec(runtime string, fn string) *types.TaskInvocationSpec {
+	return &types.TaskInvocationSpec{
+		FnRef:        &types.FnRef{Runtime: runtime, ID: fn},
+		InvocationId: "invocation",
+		Task: &types.Task{
+			Metadata: &types.ObjectMetadata{Id: "task"},
+		},
+	}
+}
+
+func TestServer_Execute(t *testing.T) {
+	runtime := &testRuntime{name: "w1", started: make(chan string, 1), release: make(chan struct{})}
+	srv := NewServer(map[string]fnenv.Runtime{"test": runtime}, Config{MaxParallelism: 1})
+	ctx := context.Background()
+
+	result, err := srv.Execute(ctx, newSpec("test", "echo"))
+	assert.NoError(t, err)
+	assert.Equal(t, "w1/echo", typedvalues.MustUnwrap(result.GetOutput()))
+
+	_, err = srv.Execute(ctx, newSpec("unknown", "echo"))
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	_, err = srv.Execute(ctx, newSpec("test", "fail"))
+	assert.EqualError(t, err, "runtime failure")
+
+	// Tasks that exceed the parallelism of the worker are rejected.
+	done := make(chan struct{})
+	go func() {
+		srv.Execute(ctx, newSpec("test", "block"))
+		close(done)
+	}()
+	<-runtime.started
+	_, err = srv.Execute(ctx, newSpec("test", "echo"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	close(runtime.release)
+	<-done
+	_, err = srv.Execute(ctx, newSpec("test", "echo"))
+	assert.NoError(t, err)
+}
@@ -3,25 +3,43 @@ package bundle
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/fission/fission-workflows/pkg/api"
+	"github.com/fission/fission-workflows/pkg/api/events"
+	"github.com/fission/fission-workflows/pkg/api/projectors"
 	"github.com/fission/fission-workflows/pkg/apiserver"
+	"github.com/fission/fission-workflows/pkg/apiserver/httpclient"
+	"github.com/fission/fission-workflows/pkg/controller"
+	"github.com/fission/fission-workflows/pkg/fes"
+	"github.com/fission/fission-workflows/pkg/fnenv"
 	"github.com/fission/fission-workflows/pkg/fnenv/native/builtin"
+	"github.com/fission/fission-workflows/pkg/parse/bpmn"
+	"github.com/fission/fission-workflows/pkg/parse/cwl"
+	"github.com/fission/fission-workflows/pkg/scheduler"
+	"github.com/fission/fission-workflows/pkg/sdk"
 	"github.com/fission/fission-workflows/pkg/types"
 	"github.com/fission/fission-workflows/pkg/types/typedvalues"
+	"github.com/fission/fission-workflows/pkg/types/validate"
 	"github.com/fission/fission-workflows/pkg/util"
 	"github.com/fission/fission-workflows/test/integration"
 	"github.com/golang/protobuf/ptypes"
-	"github.com/golang/protobuf/ptypes/empty"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -44,8 +62,6 @@ func TestMain(m *testing.M) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), testSuiteTimeout)
 	integration.SetupBundle(ctx)
 
-	time.Sleep(time.Duration(4) * time.Second)
-
 	exitCode := m.Run()
 	defer os.Exit(exitCode)
 	// Teardown
@@ -78,13 +94,59 @@ func TestWorkflowCreate(t *testing.T) {
 	assert.Equal(t, wf.Status.Status, types.WorkflowStatus_READY)
 
 	// Test workflow list
-	l, err := client.Workflow.List(ctx, &empty.Empty{})
+	l, err := client.Workflow.List(ctx, &apiserver.WorkflowListQuery{})
 	assert.NoError(t, err)
 	if len(l.Workflows) != 1 || l.Workflows[0] != wf.ID() {
 		t.Errorf("Listed workflows '%v' did not match expected workflow '%s'", l.Workflows, wf.ID())
 	}
 }
 
+func TestWorkflowValidate(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	result, err := client.Workflow.Validate(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{$.Tasks.first.Output}"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.GetValid())
+	assert.Empty(t, result.GetDiagnostics())
+
+	result, err = client.Workflow.Validate(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"first": {
+				FunctionRef: "nonexistent",
+			},
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{$.Tasks.first.}"),
+				Requires:    types.Require("first", "missing"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.GetValid())
+	fields := map[string]string{}
+	for _, d := range result.GetDiagnostics() {
+		fields[d.GetField()] = d.GetReason()
+	}
+	assert.Equal(t, map[string]string{
+		"tasks.first.functionRef":       validate.ErrUnresolvedFunction.Error(),
+		"tasks.output.inputs.default":   validate.ErrInvalidExpression.Error(),
+		"tasks.output.requires.missing": validate.ErrUndefinedDependency.Error(),
+	}, fields)
+}
+
 func TestWorkflowInvocation(t *testing.T) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
 	defer cancelFn()
@@ -154,6 +216,8 @@ func TestWorkflowInvocation(t *testing.T) {
 		}
 	}
 	wiSpec.Workflow = invocation.Workflow()
+	// The invocation is pinned to the latest version of the workflow.
+	wiSpec.WorkflowVersion = 1
 	util.AssertProtoEqual(t, wiSpec, invocation.Spec)
 	assert.Equal(t, etv.Value, invocation.Status.Output.Value)
 	assert.True(t, invocation.Status.Successful())
@@ -422,6 +486,145 @@ func TestLongRunningWorkflowInvocation(t *testing.T) {
 	assert.Equal(t, "1234", output)
 }
 
+func TestWorkflowVersioning(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	newSpec := func(output string) *types.WorkflowSpec {
+		return &types.WorkflowSpec{
+			ApiVersion: types.WorkflowAPIVersion,
+			OutputTask: "output",
+			Tasks: types.Tasks{
+				"output": {
+					FunctionRef: builtin.Noop,
+					Inputs:      types.Input(output),
+				},
+			},
+		}
+	}
+	wf, err := client.Workflow.CreateSync(ctx, newSpec("v1"))
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), wf.GetStatus().GetVersion())
+
+	_, err = client.Workflow.Update(ctx, &apiserver.WorkflowUpdateRequest{
+		Id:   wf.ID(),
+		Spec: newSpec("v2"),
+	})
+	assert.NoError(t, err)
+	for wf.GetStatus().GetVersion() != 2 || !wf.GetStatus().Ready() {
+		time.Sleep(100 * time.Millisecond)
+		wf, err = client.Workflow.Get(ctx, &types.ObjectMetadata{Id: wf.ID()})
+		assert.NoError(t, err)
+		if ctx.Err() != nil {
+			t.Fatal("updated workflow did not become ready")
+		}
+	}
+	assert.Len(t, wf.GetHistory(), 1)
+
+	// By default the latest version is invoked.
+	wfi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), wfi.GetSpec().GetWorkflowVersion())
+	assert.Equal(t, "v2", typedvalues.MustUnwrap(wfi.GetStatus().GetOutput()))
+
+	// The previous version can still be invoked.
+	wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	wiSpec.WorkflowVersion = 1
+	wfi, err = client.Invocation.InvokeSync(ctx, wiSpec)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), wfi.GetSpec().GetWorkflowVersion())
+	assert.Equal(t, "v1", typedvalues.MustUnwrap(wfi.GetStatus().GetOutput()))
+
+	wiSpec.WorkflowVersion = 3
+	_, err = client.Invocation.Invoke(ctx, wiSpec)
+	assert.Error(t, err)
+}
+
+func TestWorkflowUpdatePolicy(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"wait": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("2s"),
+			},
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("v1"),
+				Requires:    types.Require("wait"),
+			},
+		},
+	})
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+	awaitInvocation := func(id string) *types.WorkflowInvocation {
+		for {
+			wfi, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: id})
+			assert.NoError(t, err)
+			if wfi.GetStatus().Finished() {
+				return wfi
+			}
+			if ctx.Err() != nil {
+				t.Fatal("invocation did not finish")
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	// Migrate a running invocation to a patched version of the workflow.
+	md, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	_, err = client.Workflow.Update(ctx, &apiserver.WorkflowUpdateRequest{
+		Id: wf.ID(),
+		Patch: &apiserver.WorkflowPatch{
+			Tasks: map[string]*types.TaskSpec{
+				"output": {
+					Inputs: types.Input("v2"),
+				},
+			},
+		},
+		Policy: apiserver.RunningInvocationPolicy_MIGRATE,
+	})
+	assert.NoError(t, err)
+	wfi := awaitInvocation(md.GetId())
+	assert.True(t, wfi.GetStatus().Successful())
+	assert.Equal(t, int64(2), wfi.GetSpec().GetWorkflowVersion())
+	assert.Equal(t, "v2", typedvalues.MustUnwrap(wfi.GetStatus().GetOutput()))
+
+	// Fail a running invocation on an update.
+	md, err = client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	_, err = client.Workflow.Update(ctx, &apiserver.WorkflowUpdateRequest{
+		Id: wf.ID(),
+		Patch: &apiserver.WorkflowPatch{
+			Tasks: map[string]*types.TaskSpec{
+				"output": {
+					Inputs: types.Input("v3"),
+				},
+			},
+		},
+		Policy: apiserver.RunningInvocationPolicy_FAIL,
+	})
+	assert.NoError(t, err)
+	wfi = awaitInvocation(md.GetId())
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, wfi.GetStatus().GetStatus())
+	assert.Equal(t, int64(2), wfi.GetSpec().GetWorkflowVersion())
+
+	// Removing a task that does not exist is rejected.
+	_, err = client.Workflow.Update(ctx, &apiserver.WorkflowUpdateRequest{
+		Id: wf.ID(),
+		Patch: &apiserver.WorkflowPatch{
+			RemoveTasks: []string{"nonexistent"},
+		},
+	})
+	assert.Error(t, err)
+}
+
 func TestWorkflowCancellation(t *testing.T) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
 	defer cancelFn()
@@ -473,6 +676,192 @@ func TestWorkflowCancellation(t *testing.T) {
 	assert.Equal(t, api.ErrInvocationCanceled, wfi.GetStatus().GetError().Error())
 }
 
+func TestCascadingCancellation(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	wfSpec := &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "dynamicSleep",
+		Tasks: types.Tasks{
+			"dynamicSleep": {
+				FunctionRef: builtin.Noop,
+				Inputs: types.Input(&types.TaskSpec{
+					FunctionRef: builtin.Sleep,
+					Inputs:      types.Input("5s"),
+				}),
+			},
+		},
+	}
+	wf, err := client.Workflow.CreateSync(ctx, wfSpec)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+
+	md, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+
+	// Wait for the dynamic task to start its child invocation.
+	var child *types.WorkflowInvocation
+	for child == nil {
+		time.Sleep(100 * time.Millisecond)
+		invocations, err := client.Invocation.List(ctx, &apiserver.InvocationListQuery{})
+		assert.NoError(t, err)
+		for _, id := range invocations.GetInvocations() {
+			wfi, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: id})
+			assert.NoError(t, err)
+			if wfi.GetSpec().GetParentId() == md.Id || wfi.GetSpec().GetCallerId() == md.Id {
+				child = wfi
+			}
+		}
+		if ctx.Err() != nil {
+			t.Fatal("child invocation was not started")
+		}
+	}
+
+	_, err = client.Invocation.Cancel(ctx, &apiserver.CancelRequest{
+		Id:      md.Id,
+		Reason:  "test",
+		Cascade: true,
+	})
+	assert.NoError(t, err)
+	time.Sleep(500 * time.Millisecond)
+
+	wfi, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: md.Id})
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, wfi.GetStatus().GetStatus())
+	assert.Equal(t, api.ErrInvocationCanceled+": test", wfi.GetStatus().GetError().Error())
+
+	child, err = client.Invocation.Get(ctx, &types.ObjectMetadata{Id: child.ID()})
+	assert.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, child.GetStatus().GetStatus())
+	assert.Equal(t, fmt.Sprintf("%s: invocation %s was canceled: test", api.ErrInvocationCanceled, md.Id),
+		child.GetStatus().GetError().Error())
+}
+
+func TestInvocationWatch(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	wfSpec := &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "sleep",
+		Tasks: types.Tasks{
+			"sleep": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("250ms"),
+			},
+		},
+	}
+	wf, err := client.Workflow.CreateSync(ctx, wfSpec)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+
+	md, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	stream, err := client.Invocation.Watch(ctx, &apiserver.InvocationWatchQuery{Ids: []string{md.Id}})
+	assert.NoError(t, err)
+
+	// The watch ends once the invocation has finished.
+	var updates []*apiserver.InvocationUpdate
+	for {
+		update, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		if err != nil {
+			break
+		}
+		assert.Equal(t, md.Id, update.GetInvocation().ID())
+		updates = append(updates, update)
+	}
+	assert.NotEmpty(t, updates)
+	last := updates[len(updates)-1].GetInvocation()
+	assert.True(t, last.GetStatus().Finished())
+	assert.True(t, last.GetStatus().Successful())
+
+	// The HTTP gateway streams the updates as server-sent events.
+	req, err := http.NewRequest(http.MethodGet, "http://localhost:8080/invocation/watch?ids="+md.Id, nil)
+	assert.NoError(t, err)
+	req.Header.Set("Accept", apiserver.MIMEEventStream)
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, apiserver.MIMEEventStream, resp.Header.Get("Content-Type"))
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(body), "data: {\"result\":"), string(body))
+	assert.Contains(t, string(body), md.Id)
+
+	// The HTTP client reads the newline-delimited JSON stream of the gateway.
+	httpClient := httpclient.NewInvocationAPI("http://localhost:8080", http.Client{})
+	var httpUpdates []*apiserver.InvocationUpdate
+	err = httpClient.Watch(ctx, &apiserver.InvocationWatchQuery{Ids: []string{md.Id}},
+		func(update *apiserver.InvocationUpdate) error {
+			httpUpdates = append(httpUpdates, update)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, httpUpdates)
+	assert.True(t, httpUpdates[len(httpUpdates)-1].GetInvocation().GetStatus().Successful())
+}
+
+func TestInvokeMany(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	wfSpec := &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "task1",
+		Tasks: types.Tasks{
+			"task1": {
+				FunctionRef: "noop",
+				Inputs:      types.Input("{$.Invocation.Inputs.default}"),
+			},
+		},
+	}
+	wf, err := client.Workflow.CreateSync(ctx, wfSpec)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+
+	inputs := []string{"a", "b", "c"}
+	req := &apiserver.InvokeManyRequest{
+		Spec:    types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()),
+		MaxRate: 20,
+	}
+	for _, input := range inputs {
+		req.Inputs = append(req.Inputs, &apiserver.InvocationInputs{
+			Inputs: types.Input(input),
+		})
+	}
+	resp, err := client.Invocation.InvokeMany(ctx, req)
+	assert.NoError(t, err)
+	assert.Len(t, resp.GetResults(), len(inputs))
+
+	for i, input := range inputs {
+		result := resp.GetResults()[i]
+		assert.Nil(t, result.GetError())
+		stream, err := client.Invocation.Watch(ctx, &apiserver.InvocationWatchQuery{Ids: []string{result.GetId()}})
+		assert.NoError(t, err)
+		var wi *types.WorkflowInvocation
+		for {
+			update, err := stream.Recv()
+			if err != nil {
+				break
+			}
+			wi = update.GetInvocation()
+		}
+		assert.True(t, wi.GetStatus().Successful())
+		output, err := typedvalues.Unwrap(wi.GetStatus().GetOutput())
+		assert.NoError(t, err)
+		assert.Equal(t, input, output)
+	}
+
+	req.MaxRate = -1
+	_, err = client.Invocation.InvokeMany(ctx, req)
+	assert.Error(t, err)
+}
+
 func TestInvocationInvalid(t *testing.T) {
 	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
 	defer cancelFn()
@@ -704,6 +1093,1321 @@ func TestMassivelyParallelInvocation(t *testing.T) {
 	assert.True(t, wfi.Status.Successful())
 }
 
+func TestLabelSearch(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+	newSpec := func(lbls map[string]string) *types.WorkflowSpec {
+		return &types.WorkflowSpec{
+			ApiVersion: types.WorkflowAPIVersion,
+			OutputTask: "output",
+			Labels:     lbls,
+			Tasks: types.Tasks{
+				"output": {
+					FunctionRef: builtin.Noop,
+				},
+			},
+		}
+	}
+	payments, err := client.Workflow.CreateSync(ctx, newSpec(map[string]string{"team": "payments", "env": "prod"}))
+	defer client.Workflow.Delete(ctx, payments.GetMetadata())
+	assert.NoError(t, err)
+	assert.Equal(t, "payments", payments.GetMetadata().GetLabels()["team"])
+	search, err := client.Workflow.CreateSync(ctx, newSpec(map[string]string{"team": "search"}))
+	defer client.Workflow.Delete(ctx, search.GetMetadata())
+	assert.NoError(t, err)
+
+	wfs, err := client.Workflow.List(ctx, &apiserver.WorkflowListQuery{LabelSelector: "team=payments,env=prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{payments.ID()}, wfs.GetWorkflows())
+	wfs, err = client.Workflow.List(ctx, &apiserver.WorkflowListQuery{LabelSelector: "team in (payments, search),!env"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{search.ID()}, wfs.GetWorkflows())
+	_, err = client.Workflow.List(ctx, &apiserver.WorkflowListQuery{LabelSelector: "team in (payments"})
+	assert.Error(t, err)
+
+	invoke := func(wfID string, lbls map[string]string) string {
+		spec := types.NewWorkflowInvocationSpec(wfID, defaultDeadline())
+		spec.Labels = lbls
+		wfi, err := client.Invocation.InvokeSync(ctx, spec)
+		assert.NoError(t, err)
+		return wfi.ID()
+	}
+	prod := invoke(payments.ID(), map[string]string{"team": "payments", "env": "prod"})
+	invoke(payments.ID(), map[string]string{"team": "payments", "env": "staging"})
+	invoke(search.ID(), map[string]string{"team": "search", "env": "prod"})
+
+	wfis, err := client.Invocation.List(ctx, &apiserver.InvocationListQuery{LabelSelector: "team=payments,env=prod"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{prod}, wfis.GetInvocations())
+	wfis, err = client.Invocation.List(ctx, &apiserver.InvocationListQuery{
+		Workflows:     []string{search.ID()},
+		LabelSelector: "env notin (prod)",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, wfis.GetInvocations())
+}
+
+func TestAuditLog(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = client.Workflow.Delete(ctx, wf.GetMetadata())
+	assert.NoError(t, err)
+
+	auditLog, err := client.Admin.AuditLog(ctx, &apiserver.AuditLogQuery{Resource: wf.ID()})
+	assert.NoError(t, err)
+	if assert.Len(t, auditLog.GetEvents(), 2) {
+		assert.Equal(t, "/fission.workflows.apiserver.WorkflowAPI/Delete", auditLog.Events[0].GetMethod())
+		assert.Equal(t, "/fission.workflows.apiserver.WorkflowAPI/CreateSync", auditLog.Events[1].GetMethod())
+		assert.Equal(t, codes.OK.String(), auditLog.Events[0].GetCode())
+		assert.NotEmpty(t, auditLog.Events[0].GetRequestDigest())
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		Namespace:  integration.QuotaNamespace,
+		OutputTask: "sleep",
+		Tasks: types.Tasks{
+			"sleep": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("1s"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	// Inputs exceeding the maximum payload size are rejected.
+	wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	wiSpec.Inputs = typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		types.InputMain: strings.Repeat("a", 2048),
+	})
+	_, err = client.Invocation.Invoke(ctx, wiSpec)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Only a single invocation can run at the same time.
+	md, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	_, err = client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Once the invocation has finished, the next invocation is admitted.
+	_, err = client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	for err != nil && status.Code(err) == codes.ResourceExhausted && ctx.Err() == nil {
+		time.Sleep(100 * time.Millisecond)
+		_, err = client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	}
+	assert.NoError(t, err)
+	invocation, err := client.Invocation.Get(ctx, md)
+	assert.NoError(t, err)
+	assert.True(t, invocation.GetStatus().Finished())
+}
+
+func TestMaxPayloadSize(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	wiSpec.Inputs = typedvalues.MustWrapMapTypedValue(map[string]interface{}{
+		types.InputMain: strings.Repeat("a", fnenv.DefaultMaxPayloadSize+1),
+	})
+	_, err = client.Invocation.Invoke(ctx, wiSpec)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, err.Error(), fnenv.ErrPayloadTooLarge.Error())
+}
+
+func TestVisualize(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "second",
+		Tasks: types.Tasks{
+			"first": {
+				FunctionRef: builtin.Noop,
+			},
+			"second": {
+				FunctionRef: builtin.Noop,
+				Requires:    types.Require("first"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	v, err := client.Workflow.Visualize(ctx, &apiserver.VisualizeRequest{WorkflowId: wf.ID()})
+	assert.NoError(t, err)
+	assert.Equal(t, "dot", v.Format)
+	assert.Contains(t, v.Graph, `"first" -> "second";`)
+
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	v, err = client.Workflow.Visualize(ctx, &apiserver.VisualizeRequest{
+		WorkflowId:   wf.ID(),
+		InvocationId: wi.ID(),
+		Format:       "mermaid",
+	})
+	assert.NoError(t, err)
+	assert.Contains(t, v.Graph, "SUCCEEDED")
+
+	_, err = client.Workflow.Visualize(ctx, &apiserver.VisualizeRequest{WorkflowId: wf.ID(), Format: "svg"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestInvocationRestore(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("restored"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+	events, err := client.Invocation.Events(ctx, wi.GetMetadata())
+	assert.NoError(t, err)
+	// The events of the invocation include the events of its tasks.
+	assert.True(t, len(events.GetEvents()) > 2)
+
+	// Restore the history of the invocation as a new invocation, as if it was exported by another workflow engine.
+	restoredID := wi.ID() + "-restored"
+	for _, event := range events.GetEvents() {
+		for _, aggregate := range []*fes.Aggregate{event.Aggregate, event.Parent} {
+			if aggregate != nil && aggregate.Id == wi.ID() {
+				aggregate.Id = restoredID
+			}
+		}
+	}
+	events.Metadata = &types.ObjectMetadata{Id: restoredID}
+	_, err = client.Admin.Restore(ctx, events)
+	assert.NoError(t, err)
+
+	var restored *types.WorkflowInvocation
+	for i := 0; i < 50; i++ {
+		restored, err = client.Invocation.Get(ctx, &types.ObjectMetadata{Id: restoredID})
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+	assert.True(t, restored.GetStatus().Successful())
+	assert.Equal(t, wi.GetStatus().GetOutput(), restored.GetStatus().GetOutput())
+
+	// Existing invocations cannot be overwritten.
+	_, err = client.Admin.Restore(ctx, events)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+func TestGetTaskLogs(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "task1",
+		Tasks: types.Tasks{
+			"task1": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+
+	// The internal function environment does not provide logs.
+	_, err = client.Invocation.GetTaskLogs(ctx, &apiserver.TaskLogsRequest{InvocationID: wi.ID(), TaskID: "task1"})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+	_, err = client.Invocation.GetTaskLogs(ctx, &apiserver.TaskLogsRequest{InvocationID: wi.ID(), TaskID: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInvocationExplain(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "task2",
+		Tasks: types.Tasks{
+			"task1": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("2s"),
+			},
+			"task2": {
+				FunctionRef: builtin.Noop,
+				Requires:    types.Require("task1"),
+			},
+		},
+	})
+	assert.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	md, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.NoError(t, err)
+
+	// While task1 is running, task2 is blocked on it.
+	var schedule *scheduler.Schedule
+	for i := 0; i < 10; i++ {
+		schedule, err = client.Invocation.Explain(ctx, md)
+		if err == nil {
+			break
+		}
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, md.GetId(), schedule.GetInvocationId())
+	assert.Len(t, schedule.GetRunTasks(), 1)
+	assert.Equal(t, "task1", schedule.GetRunTasks()[0].GetTaskID())
+	assert.Len(t, schedule.GetBlockedTasks(), 1)
+	assert.Equal(t, "task2", schedule.GetBlockedTasks()[0].GetTaskID())
+	assert.Equal(t, []string{"task1"}, schedule.GetBlockedTasks()[0].GetWaitingFor())
+
+	_, err = client.Invocation.Explain(ctx, &types.ObjectMetadata{Id: "missing"})
+	assert.Error(t, err)
+}
+
+func TestExecutorResize(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	executors, err := client.Admin.ListExecutors(ctx, &apiserver.Empty{})
+	assert.NoError(t, err)
+	var names []string
+	var original *apiserver.ExecutorConfig
+	for _, ex := range executors.GetExecutors() {
+		names = append(names, ex.GetName())
+		if ex.GetName() == "workflow" {
+			original = ex
+		}
+	}
+	assert.Equal(t, []string{"invocation", "workflow"}, names)
+	require.NotNil(t, original)
+
+	// Only the non-zero fields are changed.
+	updated, err := client.Admin.UpdateExecutor(ctx, &apiserver.ExecutorConfig{
+		Name:        "workflow",
+		Parallelism: original.GetParallelism() + 5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, original.GetParallelism()+5, updated.GetParallelism())
+	assert.Equal(t, original.GetParallelism()+5, updated.GetWorkers())
+	assert.Equal(t, original.GetQueueSize(), updated.GetQueueSize())
+
+	updated, err = client.Admin.UpdateExecutor(ctx, original)
+	assert.NoError(t, err)
+	assert.Equal(t, original.GetParallelism(), updated.GetParallelism())
+
+	_, err = client.Admin.UpdateExecutor(ctx, &apiserver.ExecutorConfig{Name: "missing", Parallelism: 1})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Admin.UpdateExecutor(ctx, &apiserver.ExecutorConfig{Name: "workflow", Parallelism: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestQuarantineAdmin(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	// Healthy invocations are not quarantined.
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	require.NoError(t, err)
+	assert.True(t, wi.GetStatus().Successful())
+
+	quarantined, err := client.Admin.ListQuarantined(ctx, &apiserver.Empty{})
+	require.NoError(t, err)
+	for _, q := range quarantined.GetControllers() {
+		assert.NotEqual(t, wi.ID(), q.GetKey())
+	}
+
+	_, err = client.Admin.ReleaseQuarantined(ctx, &apiserver.QuarantinedControllerRef{
+		System: "invocation",
+		Key:    wi.ID(),
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Admin.ReleaseQuarantined(ctx, &apiserver.QuarantinedControllerRef{
+		System: "missing",
+		Key:    wi.ID(),
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestInvocationIntentResume(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("500ms"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	// The intents of the controller are recorded, and resolved once the invocation has completed.
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	require.NoError(t, err)
+	assert.True(t, wi.GetStatus().Successful())
+	assert.Empty(t, wi.GetStatus().GetIntents())
+	history, err := client.Invocation.Events(ctx, wi.GetMetadata())
+	require.NoError(t, err)
+	var recorded []string
+	for _, event := range history.GetEvents() {
+		if data, err := fes.ParseEventData(event); err == nil {
+			if m, ok := data.(*events.InvocationIntentRecorded); ok {
+				recorded = append(recorded, m.GetIntent().ID())
+			}
+		}
+	}
+	assert.Contains(t, recorded, "run.output")
+	assert.Contains(t, recorded, "complete")
+
+	// Restore an invocation of which the controller crashed after it recorded the intent to fail the invocation, but
+	// before it failed it. The new controller resumes the intent, rather than reevaluating the invocation.
+	resumedID := wi.ID() + "-resumed"
+	created := history.GetEvents()[0]
+	require.Equal(t, events.EventInvocationCreated, created.GetType())
+	created.Aggregate.Id = resumedID
+	intent, err := fes.NewEvent(projectors.NewInvocationAggregate(resumedID), &events.InvocationIntentRecorded{
+		Intent: types.NewFailIntent(errors.New("controller crashed")),
+	})
+	require.NoError(t, err)
+	_, err = client.Admin.Restore(ctx, &apiserver.ObjectEvents{
+		Metadata: &types.ObjectMetadata{Id: resumedID},
+		Events:   []*fes.Event{created, intent},
+	})
+	require.NoError(t, err)
+
+	var resumed *types.WorkflowInvocation
+	for i := 0; i < 50; i++ {
+		resumed, err = client.Invocation.Get(ctx, &types.ObjectMetadata{Id: resumedID})
+		if err == nil && resumed.GetStatus().Finished() {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	require.NoError(t, err)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, resumed.GetStatus().GetStatus())
+	assert.Equal(t, "controller crashed", resumed.GetStatus().GetError().GetMessage())
+	assert.Empty(t, resumed.GetStatus().GetIntents())
+}
+
+func TestDuplicateEvents(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "second",
+		Tasks: types.Tasks{
+			"first": {
+				FunctionRef: builtin.Noop,
+			},
+			"second": {
+				FunctionRef: builtin.Noop,
+				Requires:    types.Require("first"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+	history, err := client.Invocation.Events(ctx, wi.GetMetadata())
+	require.NoError(t, err)
+
+	// Restore the invocation with each of its events delivered twice; the duplicates are skipped.
+	duplicatedID := wi.ID() + "-duplicated"
+	var duplicated []*fes.Event
+	for _, event := range history.GetEvents() {
+		require.NotEmpty(t, event.GetUid())
+		if event.GetParent() != nil {
+			event.Parent.Id = duplicatedID
+		} else {
+			event.Aggregate.Id = duplicatedID
+		}
+		duplicated = append(duplicated, event, event)
+	}
+	_, err = client.Admin.Restore(ctx, &apiserver.ObjectEvents{
+		Metadata: &types.ObjectMetadata{Id: duplicatedID},
+		Events:   duplicated,
+	})
+	require.NoError(t, err)
+
+	expected, err := projectors.NewWorkflowInvocation().Project(nil, history.GetEvents()...)
+	require.NoError(t, err)
+	restored, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: duplicatedID})
+	require.NoError(t, err)
+	assert.True(t, restored.GetStatus().Successful())
+	assert.Equal(t, expected.(*types.WorkflowInvocation).GetMetadata().GetGeneration(),
+		restored.GetMetadata().GetGeneration())
+	assert.Len(t, restored.GetStatus().GetTasks(), 2)
+}
+
+func TestInterruptedTaskRecovery(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	// interrupt restores the invocation of the workflow up to the start of its task, as if the workflow engine
+	// restarted while the task was in progress, and returns the invocation once it has been recovered.
+	interrupt := func(idempotent bool) *types.WorkflowInvocation {
+		wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+			ApiVersion: types.WorkflowAPIVersion,
+			OutputTask: "output",
+			Tasks: types.Tasks{
+				"output": {
+					FunctionRef: builtin.Noop,
+					Inputs:      types.Input("foo"),
+					Idempotent:  idempotent,
+				},
+			},
+		})
+		require.NoError(t, err)
+		defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+		wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+		require.NoError(t, err)
+		require.True(t, wi.GetStatus().Successful())
+		history, err := client.Invocation.Events(ctx, wi.GetMetadata())
+		require.NoError(t, err)
+
+		interruptedID := wi.ID() + "-interrupted"
+		var interrupted []*fes.Event
+		for _, event := range history.GetEvents() {
+			if event.GetParent() != nil {
+				event.Parent.Id = interruptedID
+			} else {
+				event.Aggregate.Id = interruptedID
+			}
+			interrupted = append(interrupted, event)
+			if event.GetType() == events.EventTaskStarted {
+				break
+			}
+		}
+		require.Equal(t, events.EventTaskStarted, interrupted[len(interrupted)-1].GetType())
+		_, err = client.Admin.Restore(ctx, &apiserver.ObjectEvents{
+			Metadata: &types.ObjectMetadata{Id: interruptedID},
+			Events:   interrupted,
+		})
+		require.NoError(t, err)
+
+		var recovered *types.WorkflowInvocation
+		for i := 0; i < 50; i++ {
+			recovered, err = client.Invocation.Get(ctx, &types.ObjectMetadata{Id: interruptedID})
+			if err == nil && recovered.GetStatus().Finished() {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		require.NoError(t, err)
+		return recovered
+	}
+
+	// An idempotent task is invoked again.
+	recovered := interrupt(true)
+	assert.True(t, recovered.GetStatus().Successful())
+	assert.Equal(t, "foo", typedvalues.MustUnwrap(recovered.GetStatus().GetOutput()))
+
+	// Other tasks are failed, rather than left in progress until the deadline.
+	recovered = interrupt(false)
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, recovered.GetStatus().GetStatus())
+	taskRun, ok := recovered.TaskInvocation("output")
+	require.True(t, ok)
+	assert.Equal(t, controller.ErrTaskInterrupted.Error(), taskRun.GetStatus().GetError().GetMessage())
+}
+
+func TestReady(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	// The in-memory event store is always available.
+	health, err := client.Admin.Ready(ctx, &apiserver.Empty{})
+	require.NoError(t, err)
+	assert.Equal(t, apiserver.StatusOK, health.GetStatus())
+}
+
+func TestRebuildProjections(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+
+	// The rebuilt invocation is identical to the invocation before the rebuild.
+	_, err = client.Admin.RebuildProjection(ctx, &apiserver.ProjectionRef{
+		Type: types.TypeInvocation,
+		Id:   wi.ID(),
+	})
+	require.NoError(t, err)
+	rebuilt, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: wi.ID()})
+	require.NoError(t, err)
+	assert.True(t, rebuilt.GetStatus().Successful())
+	assert.Equal(t, len(wi.GetStatus().GetTasks()), len(rebuilt.GetStatus().GetTasks()))
+
+	_, err = client.Admin.RebuildProjection(ctx, &apiserver.ProjectionRef{
+		Type: types.TypeInvocation,
+		Id:   "missing",
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.Admin.RebuildProjection(ctx, &apiserver.ProjectionRef{
+		Type: "task",
+		Id:   wi.ID(),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	result, err := client.Admin.ResyncProjections(ctx, &apiserver.ResyncProjectionsRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.GetRebuilt() >= 2, "expected the workflow and invocation to be rebuilt: %v", result)
+	assert.Empty(t, result.GetFailed())
+}
+
+func TestInvocationInputSchema(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	// Workflows with an invalid input schema are rejected.
+	_, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion:  types.WorkflowAPIVersion,
+		OutputTask:  "output",
+		InputSchema: `{"type": "text"}`,
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+			},
+		},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion:  types.WorkflowAPIVersion,
+		OutputTask:  "output",
+		InputSchema: `{"required": ["name"], "properties": {"name": {"type": "string"}, "times": {"type": "integer"}}}`,
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs: map[string]*typedvalues.TypedValue{
+					types.InputMain: typedvalues.MustWrap("{$.Invocation.Inputs.name}"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	spec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	spec.Inputs = map[string]*typedvalues.TypedValue{
+		"times": typedvalues.MustWrap("twice"),
+	}
+	_, err = client.Invocation.Invoke(ctx, spec)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "inputs.name: field is required")
+	assert.Contains(t, status.Convert(err).Message(), "inputs.times: expected integer, but got string")
+	_, err = client.Invocation.InvokeSync(ctx, spec)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	spec = types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	spec.Inputs = map[string]*typedvalues.TypedValue{
+		"name": typedvalues.MustWrap("alice"),
+	}
+	wi, err := client.Invocation.InvokeSync(ctx, spec)
+	require.NoError(t, err)
+	assert.True(t, wi.GetStatus().Successful())
+	output, err := typedvalues.Unwrap(wi.GetStatus().GetOutput())
+	require.NoError(t, err)
+	assert.Equal(t, "alice", output)
+}
+
+func TestInvocationDefaultInputs(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion:  types.WorkflowAPIVersion,
+		OutputTask:  "output",
+		InputSchema: `{"required": ["greeting", "name"]}`,
+		DefaultInputs: map[string]*typedvalues.TypedValue{
+			"greeting": typedvalues.MustWrap("hello"),
+		},
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs: map[string]*typedvalues.TypedValue{
+					types.InputMain: typedvalues.MustWrap("{$.Invocation.Inputs.greeting + ' ' + " +
+						"$.Invocation.Inputs.name}"),
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	for input, expected := range map[string]string{
+		"":     "hello alice",
+		"hola": "hola alice",
+	} {
+		spec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+		spec.Inputs = map[string]*typedvalues.TypedValue{
+			"name": typedvalues.MustWrap("alice"),
+		}
+		if len(input) > 0 {
+			spec.Inputs["greeting"] = typedvalues.MustWrap(input)
+		}
+		wi, err := client.Invocation.InvokeSync(ctx, spec)
+		require.NoError(t, err)
+		require.True(t, wi.GetStatus().Successful())
+		output, err := typedvalues.Unwrap(wi.GetStatus().GetOutput())
+		require.NoError(t, err)
+		assert.Equal(t, expected, output)
+	}
+
+	// Inputs without defaults are still required.
+	_, err = client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestTaskRetries(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	invoke := func(task *types.TaskSpec) *types.TaskInvocation {
+		wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+			ApiVersion: types.WorkflowAPIVersion,
+			OutputTask: "unreliable",
+			Tasks: types.Tasks{
+				"unreliable": task,
+			},
+		})
+		require.NoError(t, err)
+		defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+		wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+		require.NoError(t, err)
+		assert.False(t, wi.GetStatus().Successful())
+		return wi.GetStatus().GetTasks()["unreliable"]
+	}
+
+	// The task is attempted once, and retried twice, before the invocation fails.
+	taskRun := invoke(&types.TaskSpec{
+		FunctionRef: builtin.Fail,
+		Inputs:      types.Input("expected error"),
+		MaxRetries:  2,
+		Backoff:     ptypes.DurationProto(100 * time.Millisecond),
+	})
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, taskRun.GetStatus().GetStatus())
+	assert.EqualValues(t, 3, taskRun.GetStatus().GetAttempts())
+	assert.EqualValues(t, 2, taskRun.GetSpec().GetAttempt())
+	assert.Equal(t, "expected error", taskRun.GetStatus().GetLastError().GetMessage())
+
+	// The failure of the task does not have one of the retryable status codes, so it is not retried.
+	taskRun = invoke(&types.TaskSpec{
+		FunctionRef:          builtin.Fail,
+		Inputs:               types.Input("expected error"),
+		MaxRetries:           2,
+		RetryableStatusCodes: []int32{503},
+	})
+	assert.EqualValues(t, 1, taskRun.GetStatus().GetAttempts())
+}
+
+func TestConditionalTask(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Tasks: types.Tasks{
+			"greet": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("hello"),
+				When:        typedvalues.MustWrap("{$.Invocation.Inputs.greet}"),
+			},
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{$.Tasks.greet.Output}"),
+				Requires: map[string]*types.TaskDependencyParameters{
+					"greet": nil,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	invoke := func(greet interface{}) *types.WorkflowInvocation {
+		spec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+		spec.Inputs = map[string]*typedvalues.TypedValue{
+			"greet": typedvalues.MustWrap(greet),
+		}
+		wi, err := client.Invocation.InvokeSync(ctx, spec)
+		require.NoError(t, err)
+		return wi
+	}
+
+	wi := invoke(true)
+	assert.True(t, wi.GetStatus().Successful())
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, wi.GetStatus().GetTasks()["greet"].GetStatus().GetStatus())
+	assert.Equal(t, "hello", typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
+
+	// The skipped task does not fail the invocation, and the tasks that depend on it still run.
+	wi = invoke(false)
+	assert.True(t, wi.GetStatus().Successful())
+	assert.Equal(t, types.TaskInvocationStatus_SKIPPED, wi.GetStatus().GetTasks()["greet"].GetStatus().GetStatus())
+	assert.Equal(t, types.TaskInvocationStatus_SUCCEEDED, wi.GetStatus().GetTasks()["output"].GetStatus().GetStatus())
+
+	// A condition that does not evaluate to a boolean fails the task.
+	wi = invoke("yes")
+	assert.False(t, wi.GetStatus().Successful())
+	assert.Equal(t, types.TaskInvocationStatus_FAILED, wi.GetStatus().GetTasks()["greet"].GetStatus().GetStatus())
+}
+
+func TestWorkflowConsts(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Consts: map[string]*typedvalues.TypedValue{
+			"greeting": typedvalues.MustWrap("hello"),
+		},
+		Tasks: types.Tasks{
+			"output": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{$.Consts.greeting + ' ' + $.Invocation.Inputs.name}"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	spec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	spec.Inputs = map[string]*typedvalues.TypedValue{
+		"name": typedvalues.MustWrap("alice"),
+	}
+	wi, err := client.Invocation.InvokeSync(ctx, spec)
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+	assert.Equal(t, "hello alice", typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
+
+	// Constants cannot be expressions.
+	_, err = client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "output",
+		Consts: map[string]*typedvalues.TypedValue{
+			"greeting": typedvalues.MustWrap("{$.Invocation.Inputs.greeting}"),
+		},
+		Tasks: types.Tasks{
+			"output": {FunctionRef: builtin.Noop},
+		},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestWorkflowOutputs(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		Outputs: map[string]*typedvalues.TypedValue{
+			"greeting": typedvalues.MustWrap("{$.Tasks.greet.Output}"),
+			"name":     typedvalues.MustWrap("{$.Invocation.Inputs.name}"),
+			"static":   typedvalues.MustWrap(42),
+		},
+		Tasks: types.Tasks{
+			"greet": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{'hello ' + $.Invocation.Inputs.name}"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	spec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	spec.Inputs = map[string]*typedvalues.TypedValue{
+		"name": typedvalues.MustWrap("alice"),
+	}
+	wi, err := client.Invocation.InvokeSync(ctx, spec)
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+	assert.Equal(t, map[string]interface{}{
+		"greeting": "hello alice",
+		"name":     "alice",
+		"static":   int32(42),
+	}, typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
+
+	// The expressions of the outputs should be valid.
+	result, err := client.Workflow.Validate(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		Outputs: map[string]*typedvalues.TypedValue{
+			"invalid": typedvalues.MustWrap("{$.Tasks.greet.}"),
+		},
+		Tasks: types.Tasks{
+			"greet": {FunctionRef: builtin.Noop},
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.GetValid())
+	require.Len(t, result.GetDiagnostics(), 1)
+	assert.Equal(t, "outputs.invalid", result.GetDiagnostics()[0].GetField())
+	assert.Equal(t, validate.ErrInvalidExpression.Error(), result.GetDiagnostics()[0].GetReason())
+}
+
+func TestControlDependencies(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "data",
+		Tasks: types.Tasks{
+			"sleep": {
+				FunctionRef: builtin.Sleep,
+				Inputs:      types.Input("500ms"),
+			},
+			"control": {
+				FunctionRef: builtin.Noop,
+				Requires:    types.Require().AddControl("sleep"),
+			},
+			"data": {
+				FunctionRef: builtin.Noop,
+				Requires:    types.Require("sleep", "control"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	wi, err := client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+
+	startedAt := func(taskID string) time.Time {
+		ts, err := ptypes.Timestamp(wi.GetStatus().GetTasks()[taskID].GetMetadata().GetCreatedAt())
+		require.NoError(t, err)
+		return ts
+	}
+	sleepFinishedAt, err := ptypes.Timestamp(wi.GetStatus().GetTasks()["sleep"].GetStatus().GetUpdatedAt())
+	require.NoError(t, err)
+
+	// The task with the control dependency is started along with the dependency, whereas the task with the data
+	// dependency waits for it to complete.
+	assert.True(t, startedAt("control").Before(sleepFinishedAt))
+	assert.False(t, startedAt("data").Before(sleepFinishedAt))
+
+	// The output of a control dependency is not available to the task.
+	result, err := client.Workflow.Validate(ctx, &types.WorkflowSpec{
+		ApiVersion: types.WorkflowAPIVersion,
+		OutputTask: "control",
+		Tasks: types.Tasks{
+			"sleep": {FunctionRef: builtin.Sleep},
+			"control": {
+				FunctionRef: builtin.Noop,
+				Inputs:      types.Input("{$.Tasks.sleep.Output}"),
+				Requires:    types.Require().AddControl("sleep"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	assert.False(t, result.GetValid())
+	require.Len(t, result.GetDiagnostics(), 1)
+	assert.Equal(t, validate.ErrDanglingReference.Error(), result.GetDiagnostics()[0].GetReason())
+	assert.Equal(t, "tasks.control.inputs.default", result.GetDiagnostics()[0].GetField())
+}
+
+func TestConcurrencyPolicy(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	awaitInvocation := func(id string) *types.WorkflowInvocation {
+		for {
+			wfi, err := client.Invocation.Get(ctx, &types.ObjectMetadata{Id: id})
+			require.NoError(t, err)
+			if wfi.GetStatus().Finished() {
+				return wfi
+			}
+			if ctx.Err() != nil {
+				t.Fatal("invocation did not finish")
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+
+	// invokeTwice invokes a workflow with the policy twice, with the second invocation being created while the first
+	// one is running, and returns both invocations once they have finished.
+	invokeTwice := func(policy types.WorkflowSpec_ConcurrencyPolicy) (first, second *types.WorkflowInvocation) {
+		wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+			ApiVersion:        types.WorkflowAPIVersion,
+			OutputTask:        "sleep",
+			ConcurrencyPolicy: policy,
+			Tasks: types.Tasks{
+				"sleep": {
+					FunctionRef: builtin.Sleep,
+					Inputs:      types.Input("1s"),
+				},
+			},
+		})
+		require.NoError(t, err)
+		defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+		firstMd, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+		require.NoError(t, err)
+		time.Sleep(300 * time.Millisecond)
+		secondMd, err := client.Invocation.Invoke(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+		require.NoError(t, err)
+		return awaitInvocation(firstMd.GetId()), awaitInvocation(secondMd.GetId())
+	}
+
+	// Allow runs the invocations in parallel.
+	first, second := invokeTwice(types.WorkflowSpec_ALLOW)
+	assert.True(t, first.GetStatus().Successful())
+	assert.True(t, second.GetStatus().Successful())
+
+	// Forbid fails the second invocation.
+	first, second = invokeTwice(types.WorkflowSpec_FORBID)
+	assert.True(t, first.GetStatus().Successful())
+	assert.Equal(t, types.WorkflowInvocationStatus_FAILED, second.GetStatus().GetStatus())
+	assert.Contains(t, second.GetStatus().GetError().Error(), first.ID())
+	assert.Empty(t, second.GetStatus().GetTasks())
+
+	// Queue starts the second invocation once the first one has finished.
+	first, second = invokeTwice(types.WorkflowSpec_QUEUE)
+	assert.True(t, first.GetStatus().Successful())
+	assert.True(t, second.GetStatus().Successful())
+	firstFinishedAt, err := ptypes.Timestamp(first.GetStatus().GetUpdatedAt())
+	require.NoError(t, err)
+	secondStartedAt, err := ptypes.Timestamp(second.GetStatus().GetTasks()["sleep"].GetMetadata().GetCreatedAt())
+	require.NoError(t, err)
+	assert.False(t, secondStartedAt.Before(firstFinishedAt))
+
+	// Replace cancels the first invocation.
+	first, second = invokeTwice(types.WorkflowSpec_REPLACE)
+	assert.Equal(t, types.WorkflowInvocationStatus_ABORTED, first.GetStatus().GetStatus())
+	assert.Contains(t, first.GetStatus().GetError().Error(), "replaced by invocation "+second.ID())
+	assert.True(t, second.GetStatus().Successful())
+}
+
+func TestCWLWorkflow(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	spec, err := cwl.Parse(strings.NewReader(`
+cwlVersion: v1.0
+class: Workflow
+inputs:
+  message: string
+  suffix:
+    type: string
+    default: "!"
+outputs:
+  first:
+    type: string
+    outputSource: first/out
+  merged:
+    type: string[]
+    outputSource: second/out
+steps:
+  first:
+    run: noop
+    in:
+      default: message
+    out: [out]
+  second:
+    run: noop
+    in:
+      default:
+        source: [first/out, suffix]
+    out: [out]
+`))
+	require.NoError(t, err)
+	wf, err := client.Workflow.CreateSync(ctx, spec)
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+	wiSpec.Inputs = map[string]*typedvalues.TypedValue{
+		"message": typedvalues.MustWrap("hello"),
+	}
+	wi, err := client.Invocation.InvokeSync(ctx, wiSpec)
+	require.NoError(t, err)
+	require.True(t, wi.GetStatus().Successful())
+	assert.Equal(t, map[string]interface{}{
+		"first":  "hello",
+		"merged": []interface{}{"hello", "!"},
+	}, typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
+
+	// The input schema that is derived from the CWL inputs requires the message.
+	_, err = client.Invocation.InvokeSync(ctx, types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline()))
+	assert.Error(t, err)
+}
+
+func TestBPMNProcess(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	spec, err := bpmn.Parse(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<bpmn:definitions xmlns:bpmn="http://www.omg.org/spec/BPMN/20100524/MODEL" id="definitions">
+  <bpmn:process id="process" isExecutable="true">
+    <bpmn:startEvent id="start" />
+    <bpmn:sequenceFlow id="f1" sourceRef="start" targetRef="slow" />
+    <bpmn:serviceTask id="slow" implementation="sleep" />
+    <bpmn:boundaryEvent id="timer" attachedToRef="slow">
+      <bpmn:timerEventDefinition><bpmn:timeDuration>PT1S</bpmn:timeDuration></bpmn:timerEventDefinition>
+    </bpmn:boundaryEvent>
+    <bpmn:sequenceFlow id="f2" sourceRef="timer" targetRef="late" />
+    <bpmn:task id="late" />
+    <bpmn:sequenceFlow id="f3" sourceRef="slow" targetRef="gateway" />
+    <bpmn:exclusiveGateway id="gateway" default="f5" />
+    <bpmn:sequenceFlow id="f4" sourceRef="gateway" targetRef="fast">
+      <bpmn:conditionExpression>{ $.Invocation.Inputs.default &lt; 100 }</bpmn:conditionExpression>
+    </bpmn:sequenceFlow>
+    <bpmn:sequenceFlow id="f5" sourceRef="gateway" targetRef="moderate" />
+    <bpmn:task id="fast" />
+    <bpmn:task id="moderate" />
+  </bpmn:process>
+</bpmn:definitions>`))
+	require.NoError(t, err)
+	wf, err := client.Workflow.CreateSync(ctx, spec)
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+
+	// The tasks on the paths that are not taken are skipped.
+	invoke := func(sleep int) map[string]types.TaskInvocationStatus_Status {
+		wiSpec := types.NewWorkflowInvocationSpec(wf.ID(), defaultDeadline())
+		wiSpec.Inputs = types.Input(sleep)
+		wi, err := client.Invocation.InvokeSync(ctx, wiSpec)
+		require.NoError(t, err)
+		require.True(t, wi.GetStatus().Successful(), wi.GetStatus().GetError().GetMessage())
+		statuses := map[string]types.TaskInvocationStatus_Status{}
+		for id, task := range wi.GetStatus().GetTasks() {
+			statuses[id] = task.GetStatus().GetStatus()
+		}
+		return statuses
+	}
+	assert.Equal(t, map[string]types.TaskInvocationStatus_Status{
+		"slow":     types.TaskInvocationStatus_SUCCEEDED,
+		"late":     types.TaskInvocationStatus_SKIPPED,
+		"fast":     types.TaskInvocationStatus_SUCCEEDED,
+		"moderate": types.TaskInvocationStatus_SKIPPED,
+	}, invoke(10))
+	assert.Equal(t, map[string]types.TaskInvocationStatus_Status{
+		"slow":     types.TaskInvocationStatus_SUCCEEDED,
+		"late":     types.TaskInvocationStatus_SKIPPED,
+		"fast":     types.TaskInvocationStatus_SKIPPED,
+		"moderate": types.TaskInvocationStatus_SUCCEEDED,
+	}, invoke(200))
+
+	// The slow task is interrupted by the boundary timer.
+	assert.Equal(t, map[string]types.TaskInvocationStatus_Status{
+		"slow":     types.TaskInvocationStatus_SUCCEEDED,
+		"late":     types.TaskInvocationStatus_SUCCEEDED,
+		"fast":     types.TaskInvocationStatus_SKIPPED,
+		"moderate": types.TaskInvocationStatus_SKIPPED,
+	}, invoke(5000))
+}
+
+func TestWorkflowOpenAPI(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := setup(ctx)
+
+	wf, err := client.Workflow.CreateSync(ctx, &types.WorkflowSpec{
+		ApiVersion:  types.WorkflowAPIVersion,
+		Name:        "greet",
+		OutputTask:  "greet",
+		InputSchema: `{"type": "object", "properties": {"name": {"type": "string"}}, "required": ["name"]}`,
+		Tasks: map[string]*types.TaskSpec{
+			"greet": {
+				FunctionRef: "noop",
+				Inputs:      types.Input("{ 'Hello, ' + $.Invocation.Inputs.name }"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer client.Workflow.Delete(ctx, wf.GetMetadata())
+	invokePath := "/workflow/" + wf.ID() + "/invoke"
+
+	get := func(path string) map[string]interface{} {
+		resp, err := http.Get("http://localhost:8080" + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		doc := map[string]interface{}{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		return doc
+	}
+	doc := get("/workflow/" + wf.ID() + "/openapi.json")
+	assert.Equal(t, "greet", doc["info"].(map[string]interface{})["title"])
+	assert.Contains(t, doc["paths"], invokePath)
+	assert.Contains(t, get("/openapi.json")["paths"], invokePath)
+
+	// The other requests are still served by the HTTP gateway of the gRPC APIs.
+	resp, err := http.Get("http://localhost:8080/workflow/" + wf.ID())
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	invoke := func(body string) (int, string) {
+		resp, err := http.Post("http://localhost:8080"+invokePath, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		output, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(output)
+	}
+	code, output := invoke(`{"name": "world"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Hello, world", output)
+
+	// Inputs that do not conform to the input schema are rejected.
+	code, _ = invoke(`{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestSDK(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	cl := setup(ctx)
+	client := sdk.NewClient(cl)
+
+	greet := sdk.NewTask("greet", "noop").
+		Input(sdk.Expr("'Hello, ' + param('name')"))
+	shout := sdk.If("shout", sdk.Param("loud"),
+		sdk.NewTask("upper", "noop").Input(sdk.Expr("output('greet').toUpperCase()")),
+		sdk.TaskOutput(greet)).
+		After(greet)
+	wf := sdk.NewWorkflow("greeter").
+		DefaultInput("loud", false).
+		AddTask(greet, shout).
+		OutputTask(shout)
+
+	output, err := client.Run(ctx, wf, map[string]interface{}{"name": "world"})
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", output)
+
+	wfID, err := client.Deploy(ctx, wf)
+	require.NoError(t, err)
+	defer cl.Workflow.Delete(ctx, &types.ObjectMetadata{Id: wfID})
+	wi, err := client.Invoke(ctx, wfID, map[string]interface{}{"name": "world", "loud": true})
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO, WORLD", typedvalues.MustUnwrap(wi.GetStatus().GetOutput()))
+}
+
+func TestKVStore(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), testTimeout)
+	defer cancelFn()
+	client := sdk.NewClient(setup(ctx))
+
+	collect := sdk.Foreach("collect", []interface{}{1, 2, 3}, sdk.NewTask("set", builtin.KVSet).
+		InputKey(builtin.KVInputKey, "numbers").
+		InputKey(builtin.KVInputValue, sdk.Expr("task().Inputs._item * 10")).
+		InputKey(builtin.KVInputAppend, true)).
+		InputKey(builtin.ForeachInputSequential, true)
+	get := sdk.NewTask("get", builtin.KVGet).
+		InputKey(builtin.KVInputKey, "numbers").
+		After(collect)
+	wf := sdk.NewWorkflow("kv").
+		AddTask(collect, get).
+		OutputTask(get)
+
+	output, err := client.Run(ctx, wf, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(10), float64(20), float64(30)}, output)
+}
+
 func setup(ctx context.Context) *apiserver.Client {
 	conn, err := grpc.Dial(gRPCAddress, grpc.WithInsecure())
 	if err != nil {
@@ -2,12 +2,22 @@ package integration
 
 import (
 	"context"
+	"net/http"
+	"time"
 
 	"github.com/fission/fission-workflows/cmd/fission-workflows-bundle/bundle"
+	"github.com/fission/fission-workflows/pkg/controller/ctrl"
+	"github.com/fission/fission-workflows/pkg/fnenv"
+	"github.com/fission/fission-workflows/pkg/quota"
 	"github.com/fission/fission-workflows/pkg/scheduler"
 )
 
-// SetupBundle sets up and runs the workflows-bundle.
+// QuotaNamespace is the namespace that is limited by the quotas of the bundle, to test the quotas without affecting
+// the other tests.
+const QuotaNamespace = "quota-test"
+
+// SetupBundle sets up and runs the workflows-bundle. If the HTTP gateway is enabled, it returns once the gateway is
+// serving, which the bundle starts after the controllers and the gRPC APIs.
 //
 // By default the bundle runs with all components are enabled, setting up a NATS cluster as the
 // backing event store, and internal fnenv and workflow runtime
@@ -27,8 +37,37 @@ func SetupBundle(ctx context.Context, opts ...bundle.Options) bundle.Options {
 			AdminAPI:             true,
 			Metrics:              true,
 			Debug:                true,
+			Audit:                &bundle.AuditOptions{EventStore: true},
+			MaxPayloadSize:       fnenv.DefaultMaxPayloadSize,
+			QuarantineThreshold:  ctrl.DefaultQuarantineThreshold,
+			Quotas: &quota.Config{
+				Namespaces: map[string]quota.Quota{
+					QuotaNamespace: {MaxConcurrentInvocations: 1, MaxPayloadSize: 1024},
+				},
+			},
 		}
 	}
 	go bundle.Run(ctx, &bundleOpts)
+	if bundleOpts.HTTPGateway {
+		awaitHealthy(ctx, "http://localhost:8080/healthz")
+	}
 	return bundleOpts
 }
+
+// awaitHealthy blocks until the health check at the url succeeds, or panics if the context is done before that.
+func awaitHealthy(ctx context.Context, url string) {
+	for {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			panic("bundle did not become healthy: " + ctx.Err().Error())
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}